@@ -133,6 +133,30 @@ type TestClusterArgs struct {
 	// A copy of an entry from this map will be copied to each individual server
 	// and potentially adjusted according to ReplicationMode.
 	ServerArgsPerNode map[int]TestServerArgs
+
+	// RegionLatencies, if set, simulates a multi-region cluster by injecting
+	// latency into the RPC connections between nodes in different regions. The
+	// region of a node is the "region" tier of its locality, so localities need
+	// to be specified in ServerArgs or ServerArgsPerNode.
+	RegionLatencies RegionLatencies
+}
+
+// RegionPair is a pair of regions.
+type RegionPair struct {
+	RegionA, RegionB string
+}
+
+// RegionLatencies contains the simulated network latencies between pairs of
+// regions. The latencies are symmetric; pairs of regions that are not present
+// have no latency.
+type RegionLatencies map[RegionPair]time.Duration
+
+// Latency returns the latency between the two given regions.
+func (l RegionLatencies) Latency(regionA, regionB string) time.Duration {
+	if latency, ok := l[RegionPair{RegionA: regionA, RegionB: regionB}]; ok {
+		return latency
+	}
+	return l[RegionPair{RegionA: regionB, RegionB: regionA}]
 }
 
 var (
@@ -263,6 +263,14 @@ func changefeedPlanHook(
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
+		if formatType(details.Opts[optFormat]) == optFormatAvro {
+			for id := range targets {
+				if isSchemaFeedTable(id) {
+					return errors.Errorf(`%s=%s is not supported when targeting %s`,
+						optFormat, optFormatAvro, targets[id].StatementTimeName)
+				}
+			}
+		}
 		if isCloudStorageSink(parsedSink) {
 			details.Opts[optKeyInValue] = ``
 		}
@@ -422,7 +430,11 @@ func validateChangefeedTable(
 	// (which creates a cycle since the resolved timestamp high-water mark is
 	// saved in it), but there are subtle differences in the way many of them
 	// work and this will be under-tested, so disallow them all until demand
-	// dictates.
+	// dictates. The exception is the descriptor and namespace tables, which are
+	// watched to follow schema changes.
+	if isSchemaFeedTable(tableDesc.ID) {
+		return nil
+	}
 	if tableDesc.ID < keys.MinUserDescID {
 		return errors.Errorf(`CHANGEFEEDs are not supported on system tables`)
 	}
@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 	gosql "database/sql"
+	gojson "encoding/json"
 	"fmt"
 	"math"
 	"net/url"
@@ -262,6 +263,85 @@ func TestChangefeedCursor(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedSchemaTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+
+		// Skip the initial scan, which would emit every existing descriptor.
+		var ts string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+		schema := feed(t, f,
+			`CREATE CHANGEFEED FOR system.descriptor, system.namespace WITH cursor=$1`, ts)
+		defer closeFeed(t, schema)
+
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		var fooID int64
+		sqlDB.QueryRow(t, `SELECT table_id FROM crdb_internal.tables WHERE name = 'foo'`).Scan(&fooID)
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN b STRING`)
+
+		// Consume messages until the namespace entry and a descriptor that
+		// includes the new column have been seen for foo. The descriptor and its
+		// version are expected to be decoded.
+		var seenNamespace bool
+		var lastVersion int64
+		for !seenNamespace || lastVersion < 2 {
+			m, err := schema.Next()
+			if err != nil {
+				t.Fatal(err)
+			} else if m == nil {
+				t.Fatal(`expected message`)
+			}
+			if len(m.Key) == 0 {
+				// Skip resolved timestamps.
+				continue
+			}
+			var value struct {
+				After map[string]gojson.RawMessage `json:"after"`
+			}
+			if err := gojson.Unmarshal(m.Value, &value); err != nil {
+				t.Fatal(err)
+			}
+			var id int64
+			if err := gojson.Unmarshal(value.After[`id`], &id); err != nil {
+				t.Fatal(err)
+			}
+			if id != fooID {
+				continue
+			}
+			switch m.Topic {
+			case `namespace`:
+				require.Equal(t, `"foo"`, string(value.After[`name`]))
+				seenNamespace = true
+			case `descriptor`:
+				var desc struct {
+					Table struct {
+						Name string `json:"name"`
+					} `json:"table"`
+				}
+				if err := gojson.Unmarshal(value.After[`descriptor`], &desc); err != nil {
+					t.Fatal(err)
+				}
+				require.Equal(t, `foo`, desc.Table.Name)
+				var version int64
+				if err := gojson.Unmarshal(value.After[`version`], &version); err != nil {
+					t.Fatal(err)
+				}
+				if version <= lastVersion {
+					t.Fatalf(`expected version after %d, got %d`, lastVersion, version)
+				}
+				lastVersion = version
+			default:
+				t.Fatalf(`unexpected topic: %s`, m.Topic)
+			}
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedTimestamps(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1522,6 +1602,10 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `not supported on system tables`,
 		`EXPERIMENTAL CHANGEFEED FOR system.jobs`,
 	)
+	sqlDB.ExpectErr(
+		t, `format=experimental_avro is not supported when targeting descriptor`,
+		`EXPERIMENTAL CHANGEFEED FOR system.descriptor WITH format=experimental_avro, confluent_schema_registry='http://nope'`,
+	)
 	sqlDB.ExpectErr(
 		t, `table "bar" does not exist`,
 		`EXPERIMENTAL CHANGEFEED FOR bar`,
@@ -17,6 +17,7 @@ import (
 	"net/url"
 	"path/filepath"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -177,6 +178,11 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 				return nil, err
 			}
 		}
+		if row.tableDesc.ID == keys.DescriptorTableID {
+			if err := addDecodedDescriptor(after, row.tableDesc, row.datums); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	var before map[string]interface{}
@@ -195,6 +201,11 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 				return nil, err
 			}
 		}
+		if row.prevTableDesc.ID == keys.DescriptorTableID {
+			if err := addDecodedDescriptor(before, row.prevTableDesc, row.prevDatums); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	var jsonEntries map[string]interface{}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pkg/errors"
+)
+
+// isSchemaFeedTable returns whether the given table is one of the system
+// tables that are allowed as a changefeed target so that external tooling
+// (such as a schema registry) can follow schema changes without polling.
+//
+// These tables have two column families, but only the non-primary family is
+// ever written, so each changed kv still corresponds to exactly one row.
+func isSchemaFeedTable(id sqlbase.ID) bool {
+	return id == keys.DescriptorTableID || id == keys.NamespaceTableID
+}
+
+// descriptorColumnName is the name of the column of system.descriptor holding
+// the encoded descriptor proto.
+const descriptorColumnName = `descriptor`
+
+// decodeDescriptorJSON decodes the bytes of a system.descriptor row into a
+// JSON object along with the version of the descriptor. Database descriptors
+// are not versioned, so their version is always 0.
+func decodeDescriptorJSON(encoded []byte) (json.JSON, sqlbase.DescriptorVersion, error) {
+	var desc sqlbase.Descriptor
+	if err := protoutil.Unmarshal(encoded, &desc); err != nil {
+		return nil, 0, errors.Wrap(err, "decoding descriptor")
+	}
+	var version sqlbase.DescriptorVersion
+	if tableDesc := desc.GetTable(); tableDesc != nil {
+		version = tableDesc.Version
+	}
+	str, err := (&jsonpb.Marshaler{}).MarshalToString(&desc)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "encoding descriptor as JSON")
+	}
+	j, err := json.ParseJSON(str)
+	if err != nil {
+		return nil, 0, err
+	}
+	return j, version, nil
+}
+
+// addDecodedDescriptor replaces the encoded descriptor in the JSON columns of a
+// system.descriptor row with its decoded form and adds its version under the
+// `version` key. The datums are expected to have already been decoded.
+func addDecodedDescriptor(
+	columns map[string]interface{}, tableDesc *sqlbase.TableDescriptor, datums sqlbase.EncDatumRow,
+) error {
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Name != descriptorColumnName {
+			continue
+		}
+		encoded, ok := datums[i].Datum.(*tree.DBytes)
+		if !ok {
+			// The descriptor is NULL, there is nothing to decode.
+			return nil
+		}
+		j, version, err := decodeDescriptorJSON([]byte(*encoded))
+		if err != nil {
+			return err
+		}
+		columns[descriptorColumnName] = j
+		columns[`version`] = int64(version)
+		return nil
+	}
+	return errors.Errorf(`unknown column: %s`, descriptorColumnName)
+}
@@ -137,6 +137,12 @@ func evalExport(
 		return result.Result{}, err
 	}
 
+	// Pace the export according to the amount of data it read, so that backups
+	// yield to foreground traffic.
+	if err := cArgs.EvalCtx.GetLimiters().BulkReadRate.Wait(ctx, summary.DataSize); err != nil {
+		return result.Result{}, err
+	}
+
 	if summary.DataSize == 0 {
 		reply.Files = []roachpb.ExportResponse_File{}
 		return result.Result{}, nil
@@ -13,6 +13,8 @@ package allccl
 
 import (
 	// workloads
+	_ "github.com/cockroachdb/cockroach/pkg/ccl/workloadccl/backuprestoreccl"
+	_ "github.com/cockroachdb/cockroach/pkg/ccl/workloadccl/changefeedloadccl"
 	_ "github.com/cockroachdb/cockroach/pkg/ccl/workloadccl/roachmartccl"
 	_ "github.com/cockroachdb/cockroach/pkg/workload/bank"
 	_ "github.com/cockroachdb/cockroach/pkg/workload/bulkingest"
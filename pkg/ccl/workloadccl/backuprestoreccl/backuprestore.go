@@ -0,0 +1,294 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backuprestoreccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/workload"
+	"github.com/cockroachdb/cockroach/pkg/workload/histogram"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	backupTable  = `backup_kv`
+	backupSchema = `(
+		k BIGINT NOT NULL PRIMARY KEY,
+		v BYTES NOT NULL
+	)`
+
+	defaultRows        = 1000
+	defaultPayloadSize = 100
+	defaultDestination = `nodelocal://1/workload-backuprestore`
+)
+
+type backupRestore struct {
+	flags     workload.Flags
+	connFlags *workload.ConnFlags
+
+	seed        int64
+	rows        int
+	payloadSize int
+	destination string
+	incremental bool
+}
+
+func init() {
+	workload.Register(backupRestoreMeta)
+}
+
+var backupRestoreMeta = workload.Meta{
+	Name: `backuprestore`,
+	Description: `BackupRestore writes to a table while repeatedly backing it up, ` +
+		`restoring the backup and comparing the fingerprints of the restored and original data`,
+	Details: `BACKUP and RESTORE require an enterprise license.`,
+	Version: `1.0.0`,
+	New: func() workload.Generator {
+		g := &backupRestore{}
+		g.flags.FlagSet = pflag.NewFlagSet(`backuprestore`, pflag.ContinueOnError)
+		g.flags.Meta = map[string]workload.FlagMeta{
+			`destination`: {RuntimeOnly: true},
+			`incremental`: {RuntimeOnly: true},
+		}
+		g.flags.Int64Var(&g.seed, `seed`, 1, `Random number generator seed.`)
+		g.flags.IntVar(&g.rows, `rows`, defaultRows, `Number of rows that are written to.`)
+		g.flags.IntVar(&g.payloadSize, `payload-bytes`, defaultPayloadSize, `Size of the value of each row.`)
+		g.flags.StringVar(&g.destination, `destination`, defaultDestination,
+			`Base URI of the backups. Each backup is written to a subdirectory.`)
+		g.flags.BoolVar(&g.incremental, `incremental`, false,
+			`Whether every backup after the first one is an incremental backup on top of the previous ones.`)
+		g.connFlags = workload.NewConnFlags(&g.flags)
+		return g
+	},
+}
+
+// Meta implements the Generator interface.
+func (*backupRestore) Meta() workload.Meta { return backupRestoreMeta }
+
+// Flags implements the Flagser interface.
+func (w *backupRestore) Flags() workload.Flags { return w.flags }
+
+// Hooks implements the Hookser interface.
+func (w *backupRestore) Hooks() workload.Hooks {
+	return workload.Hooks{
+		Validate: func() error {
+			if w.rows <= 0 {
+				return errors.Errorf(`--rows must be positive, got %d`, w.rows)
+			}
+			if w.destination == `` {
+				return errors.New(`--destination must be specified`)
+			}
+			return nil
+		},
+	}
+}
+
+// Tables implements the Generator interface.
+func (w *backupRestore) Tables() []workload.Table {
+	table := workload.Table{
+		Name:   backupTable,
+		Schema: backupSchema,
+		InitialRows: workload.Tuples(
+			w.rows,
+			func(rowIdx int) []interface{} {
+				rng := rand.New(rand.NewSource(w.seed + int64(rowIdx)))
+				return []interface{}{
+					rowIdx,                                 // k
+					randutil.RandBytes(rng, w.payloadSize), // v
+				}
+			},
+		),
+	}
+	return []workload.Table{table}
+}
+
+// Ops implements the Opser interface.
+func (w *backupRestore) Ops(urls []string, reg *histogram.Registry) (workload.QueryLoad, error) {
+	sqlDatabase, err := workload.SanitizeUrls(w, w.connFlags.DBOverride, urls)
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+	db, err := gosql.Open(`cockroach`, strings.Join(urls, ` `))
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+	db.SetMaxOpenConns(w.connFlags.Concurrency + 2)
+	db.SetMaxIdleConns(w.connFlags.Concurrency + 2)
+
+	upsertStmt, err := db.Prepare(
+		fmt.Sprintf(`UPSERT INTO %s (k, v) VALUES ($1, $2)`, backupTable),
+	)
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+
+	ql := workload.QueryLoad{SQLDatabase: sqlDatabase}
+	for i := 0; i < w.connFlags.Concurrency; i++ {
+		op := upsertOp{
+			config:     w,
+			hists:      reg.GetHandle(),
+			upsertStmt: upsertStmt,
+			rng:        rand.New(rand.NewSource(w.seed + int64(i))),
+		}
+		ql.WorkerFns = append(ql.WorkerFns, op.run)
+	}
+	cycle := &cycleOp{
+		config:      w,
+		hists:       reg.GetHandle(),
+		db:          db,
+		sqlDatabase: sqlDatabase,
+		runID:       timeutil.Now().UnixNano(),
+	}
+	ql.WorkerFns = append(ql.WorkerFns, cycle.run)
+	return ql, nil
+}
+
+// upsertOp is a worker that overwrites random rows.
+type upsertOp struct {
+	config     *backupRestore
+	hists      *histogram.Histograms
+	upsertStmt *gosql.Stmt
+	rng        *rand.Rand
+}
+
+func (o *upsertOp) run(ctx context.Context) error {
+	k := o.rng.Intn(o.config.rows)
+	v := randutil.RandBytes(o.rng, o.config.payloadSize)
+	start := timeutil.Now()
+	if _, err := o.upsertStmt.ExecContext(ctx, k, v); err != nil {
+		return err
+	}
+	o.hists.Get(`upsert`).Record(timeutil.Since(start))
+	return nil
+}
+
+// cycleOp is a worker that runs one backup/restore cycle per call: it backs
+// the table up as of the current time, restores the backup into a new
+// database, checks that the restored table has the same fingerprint as the
+// original one at the time of the backup and drops the restored database.
+type cycleOp struct {
+	config      *backupRestore
+	hists       *histogram.Histograms
+	db          *gosql.DB
+	sqlDatabase string
+	// runID distinguishes the backups of different runs of the workload that
+	// use the same destination.
+	runID int64
+	// numCycles is the number of cycles that were started.
+	numCycles int
+
+	// backups are the URIs of the backups taken so far in the current chain of
+	// incremental backups.
+	backups []string
+}
+
+func (o *cycleOp) run(ctx context.Context) error {
+	var asOf string
+	if err := o.db.QueryRowContext(ctx, `SELECT cluster_logical_timestamp()`).Scan(&asOf); err != nil {
+		return err
+	}
+	o.numCycles++
+	uri := fmt.Sprintf(`%s/%d/%d`, o.config.destination, o.runID, o.numCycles)
+	table := fmt.Sprintf(`%s.%s`, o.sqlDatabase, backupTable)
+
+	backupStmt := fmt.Sprintf(`BACKUP TABLE %s TO $1 AS OF SYSTEM TIME %s`, table, asOf)
+	if !o.config.incremental {
+		o.backups = o.backups[:0]
+	}
+	args := []interface{}{uri}
+	if len(o.backups) > 0 {
+		backupStmt += ` INCREMENTAL FROM ` + placeholders(2, len(o.backups))
+		for _, b := range o.backups {
+			args = append(args, b)
+		}
+	}
+	start := timeutil.Now()
+	if _, err := o.db.ExecContext(ctx, backupStmt, args...); err != nil {
+		return errors.Wrap(err, `backup`)
+	}
+	o.hists.Get(`backup`).Record(timeutil.Since(start))
+	o.backups = append(o.backups, uri)
+
+	restoreDB := fmt.Sprintf(`%s_restore_%d`, o.sqlDatabase, o.numCycles)
+	if _, err := o.db.ExecContext(ctx, `CREATE DATABASE IF NOT EXISTS `+restoreDB); err != nil {
+		return err
+	}
+	args = args[:0]
+	for _, b := range o.backups {
+		args = append(args, b)
+	}
+	start = timeutil.Now()
+	if _, err := o.db.ExecContext(ctx, fmt.Sprintf(
+		`RESTORE TABLE %s FROM %s WITH into_db = '%s'`,
+		table, placeholders(1, len(o.backups)), restoreDB,
+	), args...); err != nil {
+		return errors.Wrap(err, `restore`)
+	}
+	o.hists.Get(`restore`).Record(timeutil.Since(start))
+
+	expected, err := o.fingerprint(ctx, table, asOf)
+	if err != nil {
+		return err
+	}
+	actual, err := o.fingerprint(ctx, fmt.Sprintf(`%s.%s`, restoreDB, backupTable), ``)
+	if err != nil {
+		return err
+	}
+	if expected != actual {
+		return errors.Errorf(`fingerprint of the restored table %s as of %s doesn't match: %s != %s`,
+			table, asOf, actual, expected)
+	}
+	_, err = o.db.ExecContext(ctx, `DROP DATABASE `+restoreDB+` CASCADE`)
+	return err
+}
+
+// fingerprint returns the fingerprints of all the indexes of the given table,
+// as of the given time if it is not empty.
+func (o *cycleOp) fingerprint(ctx context.Context, table, asOf string) (string, error) {
+	query := fmt.Sprintf(
+		`SELECT index_name, fingerprint FROM [SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE %s]`, table,
+	)
+	if asOf != `` {
+		query += ` AS OF SYSTEM TIME ` + asOf
+	}
+	rows, err := o.db.QueryContext(ctx, query)
+	if err != nil {
+		return ``, err
+	}
+	defer rows.Close()
+	var buf strings.Builder
+	for rows.Next() {
+		var name, fingerprint string
+		if err := rows.Scan(&name, &fingerprint); err != nil {
+			return ``, err
+		}
+		fmt.Fprintf(&buf, "%s:%s ", name, fingerprint)
+	}
+	return buf.String(), rows.Err()
+}
+
+// placeholders returns a comma-separated list of n placeholders starting with
+// $first.
+func placeholders(first, n int) string {
+	var buf strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(`, `)
+		}
+		fmt.Fprintf(&buf, `$%d`, first+i)
+	}
+	return buf.String()
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedloadccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/workload"
+	"github.com/cockroachdb/cockroach/pkg/workload/histogram"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	changefeedTable  = `changefeed_kv`
+	changefeedSchema = `(
+		k BIGINT NOT NULL PRIMARY KEY,
+		v BIGINT NOT NULL
+	)`
+
+	defaultKeys     = 1000
+	defaultResolved = time.Second
+
+	// feedPartition is the partition reported to the validator for all the
+	// emissions. Sinkless changefeeds don't have partitions, which the order
+	// validator handles like a single partition.
+	feedPartition = `sinkless`
+)
+
+type changefeed struct {
+	flags     workload.Flags
+	connFlags *workload.ConnFlags
+
+	seed     int64
+	keys     int
+	resolved time.Duration
+}
+
+func init() {
+	workload.Register(changefeedMeta)
+}
+
+var changefeedMeta = workload.Meta{
+	Name: `changefeed`,
+	Description: `Changefeed upserts into a table while a sinkless changefeed on it ` +
+		`checks the ordering guarantees of the emitted rows and resolved timestamps`,
+	Version: `1.0.0`,
+	New: func() workload.Generator {
+		g := &changefeed{}
+		g.flags.FlagSet = pflag.NewFlagSet(`changefeed`, pflag.ContinueOnError)
+		g.flags.Meta = map[string]workload.FlagMeta{
+			`resolved`: {RuntimeOnly: true},
+		}
+		g.flags.Int64Var(&g.seed, `seed`, 1, `Seed for the choice of the upserted keys.`)
+		g.flags.IntVar(&g.keys, `keys`, defaultKeys, `Number of distinct keys that are upserted.`)
+		g.flags.DurationVar(&g.resolved, `resolved`, defaultResolved,
+			`Interval at which the changefeed emits resolved timestamps.`)
+		g.connFlags = workload.NewConnFlags(&g.flags)
+		return g
+	},
+}
+
+// Meta implements the Generator interface.
+func (*changefeed) Meta() workload.Meta { return changefeedMeta }
+
+// Flags implements the Flagser interface.
+func (w *changefeed) Flags() workload.Flags { return w.flags }
+
+// Hooks implements the Hookser interface.
+func (w *changefeed) Hooks() workload.Hooks {
+	return workload.Hooks{
+		Validate: func() error {
+			if w.keys <= 0 {
+				return errors.Errorf(`--keys must be positive, got %d`, w.keys)
+			}
+			if w.resolved <= 0 {
+				return errors.Errorf(`--resolved must be positive, got %s`, w.resolved)
+			}
+			return nil
+		},
+		PreLoad: func(db *gosql.DB) error {
+			_, err := db.Exec(`SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+			return err
+		},
+	}
+}
+
+// Tables implements the Generator interface.
+func (w *changefeed) Tables() []workload.Table {
+	table := workload.Table{
+		Name:   changefeedTable,
+		Schema: changefeedSchema,
+		InitialRows: workload.Tuples(
+			w.keys,
+			func(rowIdx int) []interface{} {
+				return []interface{}{
+					rowIdx, // k
+					0,      // v
+				}
+			},
+		),
+	}
+	return []workload.Table{table}
+}
+
+// Ops implements the Opser interface.
+func (w *changefeed) Ops(urls []string, reg *histogram.Registry) (workload.QueryLoad, error) {
+	sqlDatabase, err := workload.SanitizeUrls(w, w.connFlags.DBOverride, urls)
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+	db, err := gosql.Open(`cockroach`, strings.Join(urls, ` `))
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+	// The changefeed holds on to one of the connections for the whole run.
+	db.SetMaxOpenConns(w.connFlags.Concurrency + 2)
+	db.SetMaxIdleConns(w.connFlags.Concurrency + 2)
+
+	upsertStmt, err := db.Prepare(
+		fmt.Sprintf(`UPSERT INTO %s (k, v) VALUES ($1, $2)`, changefeedTable),
+	)
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+
+	var version int64
+	ql := workload.QueryLoad{SQLDatabase: sqlDatabase}
+	for i := 0; i < w.connFlags.Concurrency; i++ {
+		op := upsertOp{
+			config:     w,
+			hists:      reg.GetHandle(),
+			upsertStmt: upsertStmt,
+			rng:        rand.New(rand.NewSource(w.seed + int64(i))),
+			version:    &version,
+		}
+		ql.WorkerFns = append(ql.WorkerFns, op.run)
+	}
+	feed := &feedOp{
+		config:    w,
+		hists:     reg.GetHandle(),
+		db:        db,
+		validator: cdctest.MakeCountValidator(cdctest.NewOrderValidator(changefeedTable)),
+	}
+	ql.WorkerFns = append(ql.WorkerFns, feed.run)
+	ql.Close = func(ctx context.Context) {
+		feed.close()
+		log.Infof(ctx, "changefeed: %d rows and %d resolved timestamps validated",
+			feed.validator.NumRows, feed.validator.NumResolved)
+	}
+	return ql, nil
+}
+
+// upsertOp is a worker that changes the values of random keys.
+type upsertOp struct {
+	config     *changefeed
+	hists      *histogram.Histograms
+	upsertStmt *gosql.Stmt
+	rng        *rand.Rand
+	version    *int64
+}
+
+func (o *upsertOp) run(ctx context.Context) error {
+	k := o.rng.Intn(o.config.keys)
+	v := atomic.AddInt64(o.version, 1)
+	start := timeutil.Now()
+	if _, err := o.upsertStmt.ExecContext(ctx, k, v); err != nil {
+		return err
+	}
+	o.hists.Get(`upsert`).Record(timeutil.Since(start))
+	return nil
+}
+
+// feedOp is a worker that consumes the emissions of a sinkless changefeed on
+// the table, one per call, and passes them to a validator.
+type feedOp struct {
+	config    *changefeed
+	hists     *histogram.Histograms
+	db        *gosql.DB
+	validator *cdctest.CountValidator
+
+	rows *gosql.Rows
+}
+
+func (o *feedOp) run(ctx context.Context) error {
+	if o.rows == nil {
+		// The changefeed is started lazily so that it is bound to the context of
+		// the run.
+		rows, err := o.db.QueryContext(ctx, fmt.Sprintf(
+			`EXPERIMENTAL CHANGEFEED FOR %s WITH updated, resolved = '%s'`,
+			changefeedTable, o.config.resolved,
+		))
+		if err != nil {
+			return err
+		}
+		o.rows = rows
+	}
+	if !o.rows.Next() {
+		if err := o.rows.Err(); err != nil {
+			return err
+		}
+		return errors.New(`changefeed ended unexpectedly`)
+	}
+	var topic gosql.NullString
+	var key, value []byte
+	if err := o.rows.Scan(&topic, &key, &value); err != nil {
+		return err
+	}
+	updated, resolved, err := cdctest.ParseJSONValueTimestamps(value)
+	if err != nil {
+		return err
+	}
+	if topic.Valid {
+		// The latency of a row is the time between its update and its emission.
+		o.hists.Get(`emit`).Record(timeutil.Since(timeutil.Unix(0, updated.WallTime)))
+		err = o.validator.NoteRow(feedPartition, string(key), string(value), updated)
+	} else {
+		o.hists.Get(`resolved`).Record(timeutil.Since(timeutil.Unix(0, resolved.WallTime)))
+		err = o.validator.NoteResolved(feedPartition, resolved)
+	}
+	if err != nil {
+		return err
+	}
+	if failures := o.validator.Failures(); len(failures) > 0 {
+		return errors.Errorf("changefeed validation failed:\n%s", strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+func (o *feedOp) close() {
+	if o.rows != nil {
+		_ = o.rows.Close()
+	}
+}
@@ -88,36 +88,13 @@ var defaultLocalities = demoLocalityList{
 	{Tiers: []roachpb.Tier{{Key: "region", Value: "europe-west1"}, {Key: "az", Value: "d"}}},
 }
 
-type regionPair struct {
-	regionA string
-	regionB string
-}
-
-var regionToRegionToLatency map[string]map[string]int
-
-func insertPair(pair regionPair, latency int) {
-	regionToLatency, ok := regionToRegionToLatency[pair.regionA]
-	if !ok {
-		regionToLatency = make(map[string]int)
-		regionToRegionToLatency[pair.regionA] = regionToLatency
-	}
-	regionToLatency[pair.regionB] = latency
-}
-
-func init() {
-	regionToRegionToLatency = make(map[string]map[string]int)
-	// Latencies collected from http://cloudping.co on 2019-09-11.
-	for pair, latency := range map[regionPair]int{
-		{regionA: "us-east1", regionB: "us-west1"}:     66,
-		{regionA: "us-east1", regionB: "europe-west1"}: 64,
-		{regionA: "us-west1", regionB: "europe-west1"}: 146,
-	} {
-		insertPair(pair, latency)
-		insertPair(regionPair{
-			regionA: pair.regionB,
-			regionB: pair.regionA,
-		}, latency)
-	}
+// demoRegionLatencies are the latencies between the regions of
+// defaultLocalities that are simulated when --global is specified.
+// Latencies collected from http://cloudping.co on 2019-09-11.
+var demoRegionLatencies = base.RegionLatencies{
+	{RegionA: "us-east1", RegionB: "us-west1"}:     66 * time.Millisecond,
+	{RegionA: "us-east1", RegionB: "europe-west1"}: 64 * time.Millisecond,
+	{RegionA: "us-west1", RegionB: "europe-west1"}: 146 * time.Millisecond,
 }
 
 func init() {
@@ -432,10 +409,6 @@ func setupTransientCluster(
 			if !ok {
 				continue
 			}
-			srcLocalityMap, ok := regionToRegionToLatency[srcLocality]
-			if !ok {
-				continue
-			}
 			for j, dst := range servers {
 				if i == j {
 					continue
@@ -444,8 +417,8 @@ func setupTransientCluster(
 				if !ok {
 					continue
 				}
-				latency := srcLocalityMap[dstLocality]
-				latencyMap[dst.ServingRPCAddr()] = latency
+				latency := demoRegionLatencies.Latency(srcLocality, dstLocality)
+				latencyMap[dst.ServingRPCAddr()] = int(latency / time.Millisecond)
 			}
 		}
 	}
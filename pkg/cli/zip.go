@@ -81,11 +81,13 @@ var debugZipTablesPerNode = []string{
 	"crdb_internal.leases",
 
 	"crdb_internal.node_build_info",
+	"crdb_internal.node_contention_events",
 	"crdb_internal.node_metrics",
 	"crdb_internal.node_queries",
 	"crdb_internal.node_runtime_info",
 	"crdb_internal.node_sessions",
 	"crdb_internal.node_statement_statistics",
+	"crdb_internal.node_transaction_statistics",
 	"crdb_internal.node_txn_stats",
 }
 
@@ -46,10 +46,12 @@ SELECT concat('crdb_internal.', table_name) as name FROM [ SHOW TABLES FROM crdb
 'backward_dependencies',
 'builtin_functions',
 'create_statements',
+'error_codes',
 'forward_dependencies',
 'index_columns',
 'table_columns',
 'table_indexes',
+'range_events',
 'ranges',
 'ranges_no_leases',
 'predefined_comments',
@@ -123,11 +125,13 @@ writing ` + os.DevNull + `
   debug/nodes/1/crdb_internal.gossip_nodes.txt
   debug/nodes/1/crdb_internal.leases.txt
   debug/nodes/1/crdb_internal.node_build_info.txt
+  debug/nodes/1/crdb_internal.node_contention_events.txt
   debug/nodes/1/crdb_internal.node_metrics.txt
   debug/nodes/1/crdb_internal.node_queries.txt
   debug/nodes/1/crdb_internal.node_runtime_info.txt
   debug/nodes/1/crdb_internal.node_sessions.txt
   debug/nodes/1/crdb_internal.node_statement_statistics.txt
+  debug/nodes/1/crdb_internal.node_transaction_statistics.txt
   debug/nodes/1/crdb_internal.node_txn_stats.txt
   debug/nodes/1/details.json
   debug/nodes/1/gossip.json
@@ -173,6 +177,7 @@ writing ` + os.DevNull + `
   debug/schema/system/locations.json
   debug/schema/system/namespace.json
   debug/schema/system/namespace_deprecated.json
+  debug/schema/system/plan_changes.json
   debug/schema/system/protected_ts_meta.json
   debug/schema/system/protected_ts_records.json
   debug/schema/system/rangelog.json
@@ -181,6 +186,7 @@ writing ` + os.DevNull + `
   debug/schema/system/replication_stats.json
   debug/schema/system/reports_meta.json
   debug/schema/system/role_members.json
+  debug/schema/system/session_traces.json
   debug/schema/system/settings.json
   debug/schema/system/table_statistics.json
   debug/schema/system/ui.json
@@ -254,11 +260,13 @@ writing /dev/null
   debug/nodes/1/crdb_internal.gossip_nodes.txt
   debug/nodes/1/crdb_internal.leases.txt
   debug/nodes/1/crdb_internal.node_build_info.txt
+  debug/nodes/1/crdb_internal.node_contention_events.txt
   debug/nodes/1/crdb_internal.node_metrics.txt
   debug/nodes/1/crdb_internal.node_queries.txt
   debug/nodes/1/crdb_internal.node_runtime_info.txt
   debug/nodes/1/crdb_internal.node_sessions.txt
   debug/nodes/1/crdb_internal.node_statement_statistics.txt
+  debug/nodes/1/crdb_internal.node_transaction_statistics.txt
   debug/nodes/1/crdb_internal.node_txn_stats.txt
   debug/nodes/1/details.json
   debug/nodes/1/gossip.json
@@ -308,6 +316,8 @@ writing /dev/null
   ^- resulted in ...
   debug/nodes/2/crdb_internal.node_build_info.txt
   ^- resulted in ...
+  debug/nodes/2/crdb_internal.node_contention_events.txt
+  ^- resulted in ...
   debug/nodes/2/crdb_internal.node_metrics.txt
   ^- resulted in ...
   debug/nodes/2/crdb_internal.node_queries.txt
@@ -318,6 +328,8 @@ writing /dev/null
   ^- resulted in ...
   debug/nodes/2/crdb_internal.node_statement_statistics.txt
   ^- resulted in ...
+  debug/nodes/2/crdb_internal.node_transaction_statistics.txt
+  ^- resulted in ...
   debug/nodes/2/crdb_internal.node_txn_stats.txt
   ^- resulted in ...
   debug/nodes/2/details.json
@@ -346,11 +358,13 @@ writing /dev/null
   debug/nodes/3/crdb_internal.gossip_nodes.txt
   debug/nodes/3/crdb_internal.leases.txt
   debug/nodes/3/crdb_internal.node_build_info.txt
+  debug/nodes/3/crdb_internal.node_contention_events.txt
   debug/nodes/3/crdb_internal.node_metrics.txt
   debug/nodes/3/crdb_internal.node_queries.txt
   debug/nodes/3/crdb_internal.node_runtime_info.txt
   debug/nodes/3/crdb_internal.node_sessions.txt
   debug/nodes/3/crdb_internal.node_statement_statistics.txt
+  debug/nodes/3/crdb_internal.node_transaction_statistics.txt
   debug/nodes/3/crdb_internal.node_txn_stats.txt
   debug/nodes/3/details.json
   debug/nodes/3/gossip.json
@@ -396,6 +410,7 @@ writing /dev/null
   debug/schema/system/locations.json
   debug/schema/system/namespace.json
   debug/schema/system/namespace_deprecated.json
+  debug/schema/system/plan_changes.json
   debug/schema/system/protected_ts_meta.json
   debug/schema/system/protected_ts_records.json
   debug/schema/system/rangelog.json
@@ -404,6 +419,7 @@ writing /dev/null
   debug/schema/system/replication_stats.json
   debug/schema/system/reports_meta.json
   debug/schema/system/role_members.json
+  debug/schema/system/session_traces.json
   debug/schema/system/settings.json
   debug/schema/system/table_statistics.json
   debug/schema/system/ui.json
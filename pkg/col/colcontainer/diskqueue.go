@@ -0,0 +1,461 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colcontainer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/rand"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+var (
+	// defaultBufferSizeBytes is the default number of bytes that a disk queue
+	// buffers in memory before writing them to disk.
+	defaultBufferSizeBytes = 128 << 10 /* 128 KiB */
+	// defaultMaxFileSizeBytes is the default size of a file of a disk queue
+	// after which a new file is started.
+	defaultMaxFileSizeBytes = 32 << 20 /* 32 MiB */
+)
+
+func init() {
+	metamorphic.Register("colcontainer.defaultBufferSizeBytes", func(rng *rand.Rand) interface{} {
+		defaultBufferSizeBytes = metamorphic.IntInRange(rng, 1, defaultBufferSizeBytes)
+		return defaultBufferSizeBytes
+	})
+	metamorphic.Register("colcontainer.defaultMaxFileSizeBytes", func(rng *rand.Rand) interface{} {
+		defaultMaxFileSizeBytes = metamorphic.IntInRange(rng, 1, defaultMaxFileSizeBytes)
+		return defaultMaxFileSizeBytes
+	})
+}
+
+// blockHeaderSize is the size of the header of every block written to a file:
+// the length of the block followed by its CRC-32C checksum.
+const blockHeaderSize = 8
+
+var crc32Table = crc32.MakeTable(crc32.Castagnoli)
+
+// Queue describes a simple queue interface to which coldata.Batches can be
+// Enqueued and Dequeued.
+type Queue interface {
+	// Enqueue enqueues a coldata.Batch to this queue. A zero-length batch must
+	// be enqueued once no more batches are going to be enqueued.
+	// WARNING: Selection vectors are ignored.
+	Enqueue(coldata.Batch) error
+	// Dequeue dequeues a coldata.Batch from the queue into the batch that is
+	// passed in. The returned boolean is false if the queue was empty, which can
+	// only happen if the zero-length batch hasn't been enqueued yet. If true is
+	// returned and the batch has a length of zero, the queue is finished and
+	// there is nothing more to dequeue. The contents of the batch are only valid
+	// until the next call to Dequeue.
+	Dequeue(coldata.Batch) (bool, error)
+	// CloseRead closes the file that is currently being read from, if any. The
+	// file is reopened by the next call to Dequeue.
+	CloseRead() error
+	// Close closes all the files of the queue and removes them from disk.
+	Close() error
+}
+
+// RewindableQueue is a Queue that can be read from multiple times. Note that
+// in order for this Queue to return the same data after rewinding, all
+// Enqueueing must be done before the first Dequeue.
+type RewindableQueue interface {
+	Queue
+	// Rewind resets the Queue so that it Dequeues all Enqueued batches from the
+	// start.
+	Rewind() error
+}
+
+// DiskQueueCfg is a struct holding the configuration options for a disk queue.
+type DiskQueueCfg struct {
+	// FS is the filesystem that the files of the queue are created in.
+	FS vfs.FS
+	// Path is the directory in FS in which every queue creates its own
+	// directory for its files.
+	Path string
+	// BufferSizeBytes is the number of bytes to buffer in memory before
+	// writing them to disk. Zero means defaultBufferSizeBytes.
+	BufferSizeBytes int
+	// MaxFileSizeBytes is the size of a file after which the queue starts a new
+	// one. Zero means defaultMaxFileSizeBytes.
+	MaxFileSizeBytes int
+
+	// SpilledBytesWritten and SpilledBytesRead, if set, are incremented by the
+	// number of bytes that the queue writes to and reads from disk.
+	SpilledBytesWritten *metric.Counter
+	SpilledBytesRead    *metric.Counter
+}
+
+// EnsureDefaults returns an error if the configuration is invalid and sets
+// the unset optional fields to their defaults otherwise.
+func (cfg *DiskQueueCfg) EnsureDefaults() error {
+	if cfg.FS == nil {
+		return errors.New("FS unset on DiskQueueCfg")
+	}
+	if cfg.BufferSizeBytes == 0 {
+		cfg.BufferSizeBytes = defaultBufferSizeBytes
+	}
+	if cfg.MaxFileSizeBytes == 0 {
+		cfg.MaxFileSizeBytes = defaultMaxFileSizeBytes
+	}
+	return nil
+}
+
+// block describes a block of serialized batches that was written to a file.
+type block struct {
+	// offset is the offset of the header of the block in its file.
+	offset int64
+	// size is the size of the block without its header.
+	size int
+}
+
+// file describes a file of a disk queue.
+type file struct {
+	name string
+	// size is the number of bytes written to the file.
+	size int64
+	// blocks are the blocks that were written to the file, in order.
+	blocks []block
+}
+
+// diskQueue is a Queue that stores the enqueued batches in files. Batches are
+// serialized using the Arrow file format and buffered in memory until the
+// buffer reaches cfg.BufferSizeBytes, at which point the buffer is written to
+// the current file as a block that is prefixed with its length and checksum.
+// The checksum is verified when the block is read back.
+//
+// A diskQueue holds at most two open files at any point in time: the one that
+// is written to and the one that is read from. Files that have been read
+// completely are removed right away unless the queue is rewindable.
+type diskQueue struct {
+	typs       []coltypes.T
+	cfg        DiskQueueCfg
+	dirName    string
+	rewindable bool
+	// done is true once the zero-length batch has been enqueued.
+	done bool
+
+	// files are all the files of the queue that haven't been removed yet. The
+	// last one is the one that is written to.
+	files []file
+	// seqNo is used to name the files of the queue.
+	seqNo int
+
+	// writeBuf holds the serialized batches that haven't been written to disk
+	// yet.
+	writeBuf           bytes.Buffer
+	serializer         *colserde.FileSerializer
+	numBufferedBatches int
+	// writeFile is the open file that blocks are appended to, if any. It is
+	// always the last one of files.
+	writeFile vfs.File
+
+	// readFileIdx and readBlockIdx identify the next block to read.
+	readFileIdx  int
+	readBlockIdx int
+	readFile     vfs.File
+	// readBuf holds the block that is currently being dequeued from.
+	readBuf      []byte
+	deserializer *colserde.FileDeserializer
+	// numDequeuedFromBlock is the number of batches that were dequeued from the
+	// block that is currently being dequeued from.
+	numDequeuedFromBlock int
+
+	scratchHeader [blockHeaderSize]byte
+}
+
+var _ RewindableQueue = &diskQueue{}
+
+// NewDiskQueue creates a Queue that spills to disk.
+func NewDiskQueue(typs []coltypes.T, cfg DiskQueueCfg) (Queue, error) {
+	return newDiskQueue(typs, cfg, false /* rewindable */)
+}
+
+// NewRewindableDiskQueue creates a RewindableQueue that spills to disk.
+func NewRewindableDiskQueue(typs []coltypes.T, cfg DiskQueueCfg) (RewindableQueue, error) {
+	return newDiskQueue(typs, cfg, true /* rewindable */)
+}
+
+func newDiskQueue(typs []coltypes.T, cfg DiskQueueCfg, rewindable bool) (*diskQueue, error) {
+	if err := cfg.EnsureDefaults(); err != nil {
+		return nil, err
+	}
+	d := &diskQueue{
+		typs:       typs,
+		cfg:        cfg,
+		dirName:    cfg.FS.PathJoin(cfg.Path, uuid.FastMakeV4().String()),
+		rewindable: rewindable,
+	}
+	if err := cfg.FS.MkdirAll(d.dirName, 0755); err != nil {
+		return nil, err
+	}
+	var err error
+	if d.serializer, err = colserde.NewFileSerializer(&d.writeBuf, typs); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Enqueue is part of the Queue interface.
+func (d *diskQueue) Enqueue(b coldata.Batch) error {
+	if d.done {
+		return errors.New("Enqueue called on a finished disk queue")
+	}
+	if b.Length() == 0 {
+		// No more batches are going to be enqueued, so everything that is
+		// buffered is written out and the write file can be closed.
+		if err := d.flush(); err != nil {
+			return err
+		}
+		d.done = true
+		return d.closeWriteFile()
+	}
+	if err := d.serializer.AppendBatch(b); err != nil {
+		return err
+	}
+	d.numBufferedBatches++
+	if d.writeBuf.Len() >= d.cfg.BufferSizeBytes {
+		return d.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered batches to the write file as a new block, starting
+// a new file if there is no write file.
+func (d *diskQueue) flush() error {
+	if d.numBufferedBatches == 0 {
+		return nil
+	}
+	if err := d.serializer.Finish(); err != nil {
+		return err
+	}
+	if d.writeFile == nil {
+		if err := d.startWriteFile(); err != nil {
+			return err
+		}
+	}
+	payload := d.writeBuf.Bytes()
+	binary.LittleEndian.PutUint32(d.scratchHeader[:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(d.scratchHeader[4:], crc32.Checksum(payload, crc32Table))
+	if _, err := d.writeFile.Write(d.scratchHeader[:]); err != nil {
+		return err
+	}
+	if _, err := d.writeFile.Write(payload); err != nil {
+		return err
+	}
+	f := &d.files[len(d.files)-1]
+	f.blocks = append(f.blocks, block{offset: f.size, size: len(payload)})
+	written := int64(blockHeaderSize + len(payload))
+	f.size += written
+	if d.cfg.SpilledBytesWritten != nil {
+		d.cfg.SpilledBytesWritten.Inc(written)
+	}
+
+	d.writeBuf.Reset()
+	d.numBufferedBatches = 0
+	if err := d.serializer.Reset(&d.writeBuf); err != nil {
+		return err
+	}
+	if f.size >= int64(d.cfg.MaxFileSizeBytes) {
+		// The next flush starts a new file.
+		return d.closeWriteFile()
+	}
+	return nil
+}
+
+func (d *diskQueue) startWriteFile() error {
+	name := d.cfg.FS.PathJoin(d.dirName, fmt.Sprintf("%d", d.seqNo))
+	d.seqNo++
+	f, err := d.cfg.FS.Create(name)
+	if err != nil {
+		return err
+	}
+	d.writeFile = f
+	d.files = append(d.files, file{name: name})
+	return nil
+}
+
+func (d *diskQueue) closeWriteFile() error {
+	if d.writeFile == nil {
+		return nil
+	}
+	err := d.writeFile.Close()
+	d.writeFile = nil
+	return err
+}
+
+// Dequeue is part of the Queue interface.
+func (d *diskQueue) Dequeue(b coldata.Batch) (bool, error) {
+	if d.deserializer == nil || d.numDequeuedFromBlock == d.deserializer.NumBatches() {
+		ok, err := d.nextBlock()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			if !d.done {
+				return false, nil
+			}
+			b.SetLength(0)
+			return true, nil
+		}
+		if err := d.readBlock(); err != nil {
+			return false, err
+		}
+	}
+	if err := d.deserializer.GetBatch(d.numDequeuedFromBlock, b); err != nil {
+		return false, err
+	}
+	d.numDequeuedFromBlock++
+	return true, nil
+}
+
+// nextBlock moves readFileIdx and readBlockIdx to the next block that hasn't
+// been read yet. If all the blocks on disk have been read, the buffered
+// batches are written out first. false is returned if there is nothing left to
+// read.
+func (d *diskQueue) nextBlock() (bool, error) {
+	for {
+		if d.readFileIdx < len(d.files) {
+			if d.readBlockIdx < len(d.files[d.readFileIdx].blocks) {
+				return true, nil
+			}
+			if d.readFileIdx < len(d.files)-1 {
+				// The read file has been read completely, and since there is a file
+				// after it, no more blocks are going to be written to it.
+				if err := d.finishReadFile(); err != nil {
+					return false, err
+				}
+				continue
+			}
+		}
+		if d.numBufferedBatches == 0 {
+			return false, nil
+		}
+		if err := d.flush(); err != nil {
+			return false, err
+		}
+	}
+}
+
+// finishReadFile moves on to the file after the current read file, removing
+// the current one unless the queue is rewindable.
+func (d *diskQueue) finishReadFile() error {
+	if err := d.CloseRead(); err != nil {
+		return err
+	}
+	if d.rewindable {
+		d.readFileIdx++
+	} else {
+		// Files are only removed by non-rewindable queues, so the read file is
+		// always the first one.
+		if err := d.cfg.FS.Remove(d.files[0].name); err != nil {
+			return err
+		}
+		d.files = d.files[1:]
+	}
+	d.readBlockIdx = 0
+	return nil
+}
+
+// readBlock reads the next block into readBuf, verifies its checksum and sets
+// up the deserializer for it.
+func (d *diskQueue) readBlock() error {
+	f := &d.files[d.readFileIdx]
+	if d.readFile == nil {
+		var err error
+		if d.readFile, err = d.cfg.FS.Open(f.name); err != nil {
+			return err
+		}
+	}
+	blk := f.blocks[d.readBlockIdx]
+	if _, err := d.readFile.ReadAt(d.scratchHeader[:], blk.offset); err != nil {
+		return err
+	}
+	size := int(binary.LittleEndian.Uint32(d.scratchHeader[:4]))
+	checksum := binary.LittleEndian.Uint32(d.scratchHeader[4:])
+	if size != blk.size {
+		return errors.Errorf(
+			"block %d of %s has length %d, expected %d", d.readBlockIdx, f.name, size, blk.size)
+	}
+	if cap(d.readBuf) < size {
+		d.readBuf = make([]byte, size)
+	}
+	d.readBuf = d.readBuf[:size]
+	if _, err := d.readFile.ReadAt(d.readBuf, blk.offset+blockHeaderSize); err != nil && err != io.EOF {
+		return err
+	}
+	if d.cfg.SpilledBytesRead != nil {
+		d.cfg.SpilledBytesRead.Inc(int64(blockHeaderSize + size))
+	}
+	if actual := crc32.Checksum(d.readBuf, crc32Table); actual != checksum {
+		return errors.Errorf(
+			"checksum mismatch in block %d of %s: expected %x, computed %x",
+			d.readBlockIdx, f.name, checksum, actual)
+	}
+	var err error
+	if d.deserializer, err = colserde.NewFileDeserializerFromBytes(d.readBuf); err != nil {
+		return err
+	}
+	d.numDequeuedFromBlock = 0
+	d.readBlockIdx++
+	return nil
+}
+
+// CloseRead is part of the Queue interface.
+func (d *diskQueue) CloseRead() error {
+	if d.readFile == nil {
+		return nil
+	}
+	err := d.readFile.Close()
+	d.readFile = nil
+	return err
+}
+
+// Rewind is part of the RewindableQueue interface.
+func (d *diskQueue) Rewind() error {
+	if err := d.CloseRead(); err != nil {
+		return err
+	}
+	d.readFileIdx = 0
+	d.readBlockIdx = 0
+	d.deserializer = nil
+	d.numDequeuedFromBlock = 0
+	return nil
+}
+
+// Close is part of the Queue interface.
+func (d *diskQueue) Close() error {
+	if err := d.CloseRead(); err != nil {
+		return err
+	}
+	if err := d.closeWriteFile(); err != nil {
+		return err
+	}
+	for _, f := range d.files {
+		if err := d.cfg.FS.Remove(f.name); err != nil {
+			return err
+		}
+	}
+	d.files = nil
+	d.deserializer = nil
+	return d.cfg.FS.Remove(d.dirName)
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colcontainer_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+var testTypes = []coltypes.T{coltypes.Int64, coltypes.Bytes}
+
+// makeRandomBatch returns a batch of testTypes with random contents.
+func makeRandomBatch(rng *rand.Rand) coldata.Batch {
+	length := 1 + rng.Intn(int(coldata.BatchSize()))
+	b := coldata.NewMemBatchWithSize(testTypes, length)
+	ints := b.ColVec(0).Int64()
+	bytesVec := b.ColVec(1).Bytes()
+	for i := 0; i < length; i++ {
+		ints[i] = rng.Int63()
+		if rng.Intn(8) == 0 {
+			b.ColVec(0).Nulls().SetNull(uint16(i))
+		}
+		bytesVec.Set(i, randutil.RandBytes(rng, rng.Intn(16)))
+	}
+	b.SetLength(uint16(length))
+	return b
+}
+
+func requireEqualBatches(t *testing.T, expected, actual coldata.Batch) {
+	t.Helper()
+	require.Equal(t, expected.Length(), actual.Length())
+	for i := 0; i < int(expected.Length()); i++ {
+		expectedNull := expected.ColVec(0).Nulls().NullAt(uint16(i))
+		require.Equal(t, expectedNull, actual.ColVec(0).Nulls().NullAt(uint16(i)))
+		if !expectedNull {
+			require.Equal(t, expected.ColVec(0).Int64()[i], actual.ColVec(0).Int64()[i])
+		}
+		if e, a := expected.ColVec(1).Bytes().Get(i), actual.ColVec(1).Bytes().Get(i); !bytes.Equal(e, a) {
+			t.Fatalf("bytes mismatch at index %d: expected %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestDiskQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	for _, rewindable := range []bool{false, true} {
+		// A buffer size of 1 byte writes every batch to disk separately.
+		for _, bufferSizeBytes := range []int{1, 16 << 10, 0} {
+			// A file size of 1 byte starts a new file for every block.
+			for _, maxFileSizeBytes := range []int{1, 64 << 10, 0} {
+				name := fmt.Sprintf(
+					"rewindable=%t/bufferSizeBytes=%d/maxFileSizeBytes=%d",
+					rewindable, bufferSizeBytes, maxFileSizeBytes,
+				)
+				t.Run(name, func(t *testing.T) {
+					fs := vfs.NewMem()
+					cfg := colcontainer.DiskQueueCfg{
+						FS:                  fs,
+						Path:                "queues",
+						BufferSizeBytes:     bufferSizeBytes,
+						MaxFileSizeBytes:    maxFileSizeBytes,
+						SpilledBytesWritten: metric.NewCounter(metric.Metadata{}),
+						SpilledBytesRead:    metric.NewCounter(metric.Metadata{}),
+					}
+					var (
+						q   colcontainer.Queue
+						rq  colcontainer.RewindableQueue
+						err error
+					)
+					if rewindable {
+						rq, err = colcontainer.NewRewindableDiskQueue(testTypes, cfg)
+						q = rq
+					} else {
+						q, err = colcontainer.NewDiskQueue(testTypes, cfg)
+					}
+					require.NoError(t, err)
+
+					batches := make([]coldata.Batch, 1+rng.Intn(32))
+					for i := range batches {
+						batches[i] = makeRandomBatch(rng)
+					}
+					dest := coldata.NewMemBatch(testTypes)
+					numDequeued := 0
+					for i, b := range batches {
+						require.NoError(t, q.Enqueue(b))
+						if !rewindable && rng.Intn(2) == 0 {
+							// Dequeue everything that has been enqueued so far, which
+							// leaves the queue empty.
+							for ; numDequeued <= i; numDequeued++ {
+								ok, err := q.Dequeue(dest)
+								require.NoError(t, err)
+								require.True(t, ok)
+								requireEqualBatches(t, batches[numDequeued], dest)
+							}
+							ok, err := q.Dequeue(dest)
+							require.NoError(t, err)
+							require.False(t, ok)
+						}
+					}
+					require.NoError(t, q.Enqueue(coldata.ZeroBatch))
+
+					numReads := 1
+					if rewindable {
+						numReads = 2
+					}
+					for read := 0; read < numReads; read++ {
+						for ; numDequeued < len(batches); numDequeued++ {
+							ok, err := q.Dequeue(dest)
+							require.NoError(t, err)
+							require.True(t, ok)
+							requireEqualBatches(t, batches[numDequeued], dest)
+							if rng.Intn(4) == 0 {
+								// Closing the read file must not lose the read position.
+								require.NoError(t, q.CloseRead())
+							}
+						}
+						ok, err := q.Dequeue(dest)
+						require.NoError(t, err)
+						require.True(t, ok)
+						require.Equal(t, uint16(0), dest.Length())
+						if rewindable {
+							require.NoError(t, rq.Rewind())
+							numDequeued = 0
+						}
+					}
+					if !rewindable {
+						// Everything that was written has been read exactly once.
+						require.Equal(t, cfg.SpilledBytesWritten.Count(), cfg.SpilledBytesRead.Count())
+					}
+					require.True(t, cfg.SpilledBytesWritten.Count() > 0)
+
+					require.NoError(t, q.Close())
+					files, err := fs.List(cfg.Path)
+					require.NoError(t, err)
+					require.Empty(t, files)
+				})
+			}
+		}
+	}
+}
+
+func TestDiskQueueChecksumMismatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	fs := vfs.NewMem()
+	cfg := colcontainer.DiskQueueCfg{FS: fs, Path: "queues", BufferSizeBytes: 1}
+	q, err := colcontainer.NewDiskQueue(testTypes, cfg)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, q.Close()) }()
+	require.NoError(t, q.Enqueue(makeRandomBatch(rng)))
+	require.NoError(t, q.Enqueue(coldata.ZeroBatch))
+
+	// Flip a bit of the last byte of the only file of the queue.
+	dirs, err := fs.List(cfg.Path)
+	require.NoError(t, err)
+	require.Len(t, dirs, 1)
+	name := fs.PathJoin(cfg.Path, dirs[0], "0")
+	f, err := fs.Open(name)
+	require.NoError(t, err)
+	contents, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	contents[len(contents)-1] ^= 1
+	f, err = fs.Create(name)
+	require.NoError(t, err)
+	_, err = f.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = q.Dequeue(coldata.NewMemBatch(testTypes))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+}
@@ -0,0 +1,289 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colcontainer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// DefaultMaxOpenFDs is the default number of file descriptors that the disk
+// queues of all the spilling operators of a node can hold at once.
+const DefaultMaxOpenFDs = 256
+
+// PartitionedQueue is the abstraction for on-disk storage that spilling
+// operators use. Batches are enqueued to and dequeued from partitions that are
+// identified by their index.
+type PartitionedQueue interface {
+	// Enqueue adds the batch to the end of the partitionIdx'th partition. If a
+	// partition at that index does not exist, a new one is created. Enqueueing
+	// to a partition that has been dequeued from or that has been closed for
+	// writes is an error.
+	// WARNING: Selection vectors are ignored.
+	Enqueue(ctx context.Context, partitionIdx int, batch coldata.Batch) error
+	// Dequeue removes and returns the batch from the front of the
+	// partitionIdx'th partition. The first call to Dequeue closes the partition
+	// for writes. Once the partition is empty, a zero-length batch is returned.
+	// The contents of the batch are only valid until the next call to Dequeue.
+	Dequeue(ctx context.Context, partitionIdx int, batch coldata.Batch) error
+	// CloseAllOpenWriteFileDescriptors closes the partitions that are open for
+	// writes. The caller must not enqueue to them anymore.
+	CloseAllOpenWriteFileDescriptors(ctx context.Context) error
+	// CloseAllOpenReadFileDescriptors closes the files of the partitions that
+	// are being read from. They are reopened by the next call to Dequeue.
+	CloseAllOpenReadFileDescriptors() error
+	// Close closes all the partitions and removes their files.
+	Close(ctx context.Context) error
+}
+
+// PartitionerStrategy describes a strategy used by the PartitionedDiskQueue
+// to limit the number of file descriptors that it holds.
+type PartitionerStrategy int
+
+const (
+	// PartitionerStrategyDefault keeps every partition open for writes until it
+	// is dequeued from or CloseAllOpenWriteFileDescriptors is called. The
+	// number of file descriptors held is the number of partitions open for
+	// writes plus the number of partitions being read from.
+	PartitionerStrategyDefault PartitionerStrategy = iota
+	// PartitionerStrategyCloseOnNewPartition closes a partition for writes as
+	// soon as a different partition is enqueued to, so that there is at most one
+	// partition open for writes at any point in time. This is useful for
+	// operators that write partitions one after another, like the external
+	// sorter.
+	PartitionerStrategyCloseOnNewPartition
+)
+
+type partitionState int
+
+const (
+	// partitionStateUninitialized is the state of a partition that has never
+	// been enqueued to.
+	partitionStateUninitialized partitionState = iota
+	// partitionStateWriting is the state of a partition that is open for
+	// writes.
+	partitionStateWriting
+	// partitionStateClosedForWriting is the state of a partition that is closed
+	// for writes and doesn't hold a file descriptor.
+	partitionStateClosedForWriting
+	// partitionStateReading is the state of a partition that is being read
+	// from.
+	partitionStateReading
+	// partitionStatePermanentlyClosed is the state of a partition that has been
+	// read completely and whose files have been removed.
+	partitionStatePermanentlyClosed
+)
+
+type partition struct {
+	Queue
+	state partitionState
+}
+
+// PartitionedDiskQueue is a PartitionedQueue whose partitions are disk queues.
+// Every partition that is open for writes or being read from holds a file
+// descriptor, which is acquired from a semaphore that is shared by all the
+// spilling operators of the node in order to limit the number of files that
+// they have open at once.
+type PartitionedDiskQueue struct {
+	typs     []coltypes.T
+	cfg      DiskQueueCfg
+	strategy PartitionerStrategy
+
+	partitions []partition
+	// lastEnqueuedPartitionIdx is the index of the partition that was enqueued
+	// to last, or -1.
+	lastEnqueuedPartitionIdx int
+
+	fdSemaphore semaphore.Semaphore
+	// numOpenFDs is the number of file descriptors acquired from fdSemaphore.
+	numOpenFDs int
+}
+
+var _ PartitionedQueue = &PartitionedDiskQueue{}
+
+// NewPartitionedDiskQueue creates a PartitionedDiskQueue whose partitions are
+// created using the given configuration. Enqueueing to a new partition or
+// starting to dequeue from a partition acquires a file descriptor from
+// fdSemaphore, blocking if none is available.
+func NewPartitionedDiskQueue(
+	typs []coltypes.T,
+	cfg DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+	strategy PartitionerStrategy,
+) (*PartitionedDiskQueue, error) {
+	if err := cfg.EnsureDefaults(); err != nil {
+		return nil, err
+	}
+	if fdSemaphore == nil {
+		return nil, errors.New("nil file descriptor semaphore")
+	}
+	return &PartitionedDiskQueue{
+		typs:                     typs,
+		cfg:                      cfg,
+		strategy:                 strategy,
+		lastEnqueuedPartitionIdx: -1,
+		fdSemaphore:              fdSemaphore,
+	}, nil
+}
+
+func (p *PartitionedDiskQueue) acquireFD(ctx context.Context) error {
+	if err := p.fdSemaphore.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	p.numOpenFDs++
+	return nil
+}
+
+func (p *PartitionedDiskQueue) releaseFD() {
+	p.fdSemaphore.Release(1)
+	p.numOpenFDs--
+}
+
+// closeWritePartition finishes the writes to the partitionIdx'th partition,
+// which must be open for writes.
+func (p *PartitionedDiskQueue) closeWritePartition(partitionIdx int) error {
+	part := &p.partitions[partitionIdx]
+	if err := part.Enqueue(coldata.ZeroBatch); err != nil {
+		return err
+	}
+	p.releaseFD()
+	part.state = partitionStateClosedForWriting
+	return nil
+}
+
+// Enqueue is part of the PartitionedQueue interface.
+func (p *PartitionedDiskQueue) Enqueue(
+	ctx context.Context, partitionIdx int, batch coldata.Batch,
+) error {
+	if batch.Length() == 0 {
+		return errors.New("zero-length batch enqueued to a partitioned disk queue")
+	}
+	for len(p.partitions) <= partitionIdx {
+		p.partitions = append(p.partitions, partition{})
+	}
+	part := &p.partitions[partitionIdx]
+	if part.state != partitionStateUninitialized && part.state != partitionStateWriting {
+		return errors.Errorf("partition at index %d is closed for writes", partitionIdx)
+	}
+	if p.strategy == PartitionerStrategyCloseOnNewPartition &&
+		p.lastEnqueuedPartitionIdx != -1 && p.lastEnqueuedPartitionIdx != partitionIdx &&
+		p.partitions[p.lastEnqueuedPartitionIdx].state == partitionStateWriting {
+		if err := p.closeWritePartition(p.lastEnqueuedPartitionIdx); err != nil {
+			return err
+		}
+	}
+	if part.state == partitionStateUninitialized {
+		if err := p.acquireFD(ctx); err != nil {
+			return err
+		}
+		q, err := NewDiskQueue(p.typs, p.cfg)
+		if err != nil {
+			p.releaseFD()
+			return err
+		}
+		*part = partition{Queue: q, state: partitionStateWriting}
+	}
+	p.lastEnqueuedPartitionIdx = partitionIdx
+	return part.Enqueue(batch)
+}
+
+// Dequeue is part of the PartitionedQueue interface.
+func (p *PartitionedDiskQueue) Dequeue(
+	ctx context.Context, partitionIdx int, batch coldata.Batch,
+) error {
+	if partitionIdx >= len(p.partitions) {
+		batch.SetLength(0)
+		return nil
+	}
+	part := &p.partitions[partitionIdx]
+	switch part.state {
+	case partitionStateUninitialized, partitionStatePermanentlyClosed:
+		// The partition has never been enqueued to or has been read completely.
+		batch.SetLength(0)
+		return nil
+	case partitionStateWriting:
+		if err := p.closeWritePartition(partitionIdx); err != nil {
+			return err
+		}
+		fallthrough
+	case partitionStateClosedForWriting:
+		if err := p.acquireFD(ctx); err != nil {
+			return err
+		}
+		part.state = partitionStateReading
+	}
+	ok, err := part.Dequeue(batch)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.AssertionFailedf("partition at index %d is unexpectedly empty", partitionIdx)
+	}
+	if batch.Length() == 0 {
+		// The partition has been read completely, so its files can be removed.
+		p.releaseFD()
+		if err := part.Close(); err != nil {
+			return err
+		}
+		*part = partition{state: partitionStatePermanentlyClosed}
+	}
+	return nil
+}
+
+// CloseAllOpenWriteFileDescriptors is part of the PartitionedQueue interface.
+func (p *PartitionedDiskQueue) CloseAllOpenWriteFileDescriptors(ctx context.Context) error {
+	for i := range p.partitions {
+		if p.partitions[i].state == partitionStateWriting {
+			if err := p.closeWritePartition(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CloseAllOpenReadFileDescriptors is part of the PartitionedQueue interface.
+func (p *PartitionedDiskQueue) CloseAllOpenReadFileDescriptors() error {
+	for i := range p.partitions {
+		part := &p.partitions[i]
+		if part.state == partitionStateReading {
+			if err := part.CloseRead(); err != nil {
+				return err
+			}
+			p.releaseFD()
+			part.state = partitionStateClosedForWriting
+		}
+	}
+	return nil
+}
+
+// Close is part of the PartitionedQueue interface.
+func (p *PartitionedDiskQueue) Close(ctx context.Context) error {
+	var retErr error
+	for i := range p.partitions {
+		if p.partitions[i].Queue == nil {
+			continue
+		}
+		if err := p.partitions[i].Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+		p.partitions[i] = partition{state: partitionStatePermanentlyClosed}
+	}
+	if p.numOpenFDs > 0 {
+		p.fdSemaphore.Release(p.numOpenFDs)
+		p.numOpenFDs = 0
+	}
+	return retErr
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colcontainer_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/marusama/semaphore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionedDiskQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	rng, _ := randutil.NewPseudoRand()
+	for _, strategy := range []colcontainer.PartitionerStrategy{
+		colcontainer.PartitionerStrategyDefault,
+		colcontainer.PartitionerStrategyCloseOnNewPartition,
+	} {
+		t.Run(fmt.Sprintf("strategy=%d", strategy), func(t *testing.T) {
+			fs := vfs.NewMem()
+			cfg := colcontainer.DiskQueueCfg{FS: fs, Path: "queues", BufferSizeBytes: 1 << 10}
+			numPartitions := 1 + rng.Intn(8)
+			// The default strategy holds a file descriptor for every partition
+			// while writing, the other one only for the last partition. Reading
+			// needs one more.
+			maxFDs := numPartitions + 1
+			if strategy == colcontainer.PartitionerStrategyCloseOnNewPartition {
+				maxFDs = 2
+			}
+			sem := semaphore.New(maxFDs)
+			p, err := colcontainer.NewPartitionedDiskQueue(testTypes, cfg, sem, strategy)
+			require.NoError(t, err)
+
+			expected := make([][]coldata.Batch, numPartitions)
+			for i := 0; i < 4*numPartitions; i++ {
+				// The partitions of a queue that closes partitions on a new
+				// partition have to be written one after another.
+				partitionIdx := rng.Intn(numPartitions)
+				if strategy == colcontainer.PartitionerStrategyCloseOnNewPartition {
+					partitionIdx = i / 4
+				}
+				b := makeRandomBatch(rng)
+				require.NoError(t, p.Enqueue(ctx, partitionIdx, b))
+				expected[partitionIdx] = append(expected[partitionIdx], b)
+				require.True(t, sem.GetCount() <= maxFDs)
+			}
+			if strategy == colcontainer.PartitionerStrategyCloseOnNewPartition {
+				require.Equal(t, 1, sem.GetCount())
+				if numPartitions > 1 {
+					require.Error(t, p.Enqueue(ctx, 0, makeRandomBatch(rng)))
+				}
+			}
+
+			dest := coldata.NewMemBatch(testTypes)
+			for partitionIdx, batches := range expected {
+				for i, b := range batches {
+					require.NoError(t, p.Dequeue(ctx, partitionIdx, dest))
+					requireEqualBatches(t, b, dest)
+					require.True(t, sem.GetCount() <= maxFDs)
+					if i == 0 {
+						// The partition is closed for writes once it is read from.
+						require.Error(t, p.Enqueue(ctx, partitionIdx, makeRandomBatch(rng)))
+					}
+					if rng.Intn(4) == 0 {
+						// The read position must survive closing the read file.
+						numFDs := sem.GetCount()
+						require.NoError(t, p.CloseAllOpenReadFileDescriptors())
+						require.Equal(t, numFDs-1, sem.GetCount())
+					}
+				}
+				require.NoError(t, p.Dequeue(ctx, partitionIdx, dest))
+				require.Equal(t, uint16(0), dest.Length())
+			}
+			// Dequeueing from a partition that was never enqueued to returns a
+			// zero-length batch.
+			require.NoError(t, p.Dequeue(ctx, numPartitions, dest))
+			require.Equal(t, uint16(0), dest.Length())
+
+			require.NoError(t, p.Close(ctx))
+			require.Equal(t, 0, sem.GetCount())
+			files, err := fs.List(cfg.Path)
+			require.NoError(t, err)
+			require.Empty(t, files)
+		})
+	}
+}
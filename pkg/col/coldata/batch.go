@@ -13,8 +13,10 @@ package coldata
 import (
 	"fmt"
 	"math"
+	"math/rand"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
 )
 
 // Batch is the type that columnar operators receive and produce. It
@@ -26,6 +28,9 @@ type Batch interface {
 	Length() uint16
 	// SetLength sets the number of values in the columns in the batch.
 	SetLength(uint16)
+	// Capacity returns the maximum number of values that can be stored in the
+	// columns in the batch. Note that it can be smaller than BatchSize().
+	Capacity() int
 	// Width returns the number of columns in the batch.
 	Width() int
 	// ColVec returns the ith Vec in this batch.
@@ -120,6 +125,10 @@ var ZeroBatch = NewMemBatchWithSize(nil /* types */, 0 /* size */)
 
 func init() {
 	ZeroBatch.SetLength(0)
+	metamorphic.Register("coldata.BatchSize", func(rng *rand.Rand) interface{} {
+		SetBatchSizeForTests(uint16(metamorphic.IntInRange(rng, MinBatchSize, MaxBatchSize)))
+		return batchSize
+	})
 }
 
 // MemBatch is an in-memory implementation of Batch.
@@ -139,6 +148,12 @@ func (m *MemBatch) Length() uint16 {
 	return m.n
 }
 
+// Capacity implements the Batch interface.
+func (m *MemBatch) Capacity() int {
+	// The columns are always sized the same as the selection vector.
+	return cap(m.sel)
+}
+
 // Width implements the Batch interface.
 func (m *MemBatch) Width() int {
 	return len(m.b)
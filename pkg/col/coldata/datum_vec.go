@@ -0,0 +1,78 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package coldata
+
+import "fmt"
+
+// This file contains the manipulation functions for the Vecs of
+// coltypes.Datum type. Such Vecs store tree.Datums as interface{}'s (the
+// coldata package cannot depend on the tree package) and are never operated on
+// by the templated operators, so the functions are written by hand.
+
+func (m *memColumn) appendDatums(args SliceArgs) {
+	fromCol := args.Src.Datum()
+	toCol := m.Datum()
+	if args.Sel == nil {
+		toCol = append(toCol[:args.DestIdx], fromCol[args.SrcStartIdx:args.SrcEndIdx]...)
+	} else {
+		toCol = toCol[:args.DestIdx]
+		for _, selIdx := range args.Sel[args.SrcStartIdx:args.SrcEndIdx] {
+			toCol = append(toCol, fromCol[selIdx])
+		}
+	}
+	m.nulls.set(args)
+	m.col = toCol
+}
+
+func (m *memColumn) copyDatums(args CopySliceArgs) {
+	fromCol := args.Src.Datum()
+	toCol := m.Datum()
+	if args.Sel64 == nil && args.Sel == nil {
+		copy(toCol[args.DestIdx:], fromCol[args.SrcStartIdx:args.SrcEndIdx])
+		m.nulls.set(args.SliceArgs)
+		return
+	}
+	n := int(args.SrcEndIdx - args.SrcStartIdx)
+	srcNulls := args.Src.Nulls()
+	maybeHasNulls := args.Src.MaybeHasNulls()
+	for i := 0; i < n; i++ {
+		var selIdx uint64
+		if args.Sel64 != nil {
+			selIdx = args.Sel64[int(args.SrcStartIdx)+i]
+		} else {
+			selIdx = uint64(args.Sel[int(args.SrcStartIdx)+i])
+		}
+		destIdx := uint64(i) + args.DestIdx
+		if args.SelOnDest {
+			destIdx = selIdx
+		}
+		if maybeHasNulls && srcNulls.NullAt64(selIdx) {
+			m.nulls.SetNull64(destIdx)
+			continue
+		}
+		if args.SelOnDest {
+			m.nulls.UnsetNull64(destIdx)
+		}
+		toCol[destIdx] = fromCol[selIdx]
+	}
+}
+
+func (m *memColumn) windowDatums(start uint64, end uint64) Vec {
+	return &memColumn{
+		t:     m.t,
+		col:   m.Datum()[start:end],
+		nulls: m.nulls.Slice(start, end),
+	}
+}
+
+func (m *memColumn) prettyDatumAt(idx uint16) string {
+	return fmt.Sprintf("%v", m.Datum()[idx])
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // column is an interface that represents a raw array of a Go native type.
@@ -79,6 +80,11 @@ type Vec interface {
 	Decimal() []apd.Decimal
 	// Timestamp returns a time.Time slice.
 	Timestamp() []time.Time
+	// Interval returns a duration.Duration slice.
+	Interval() []duration.Duration
+	// Datum returns a slice of tree.Datums stored as interface{}'s. It is used
+	// for the types that don't have a native columnar representation.
+	Datum() []interface{}
 
 	// Col returns the raw, typeless backing storage for this Vec.
 	Col() interface{}
@@ -171,6 +177,10 @@ func NewMemColumn(t coltypes.T, n int) Vec {
 		return &memColumn{t: t, col: make([]apd.Decimal, n), nulls: nulls}
 	case coltypes.Timestamp:
 		return &memColumn{t: t, col: make([]time.Time, n), nulls: nulls}
+	case coltypes.Interval:
+		return &memColumn{t: t, col: make([]duration.Duration, n), nulls: nulls}
+	case coltypes.Datum:
+		return &memColumn{t: t, col: make([]interface{}, n), nulls: nulls}
 	case coltypes.Unhandled:
 		return unknown{}
 	default:
@@ -218,6 +228,14 @@ func (m *memColumn) Timestamp() []time.Time {
 	return m.col.([]time.Time)
 }
 
+func (m *memColumn) Interval() []duration.Duration {
+	return m.col.([]duration.Duration)
+}
+
+func (m *memColumn) Datum() []interface{} {
+	return m.col.([]interface{})
+}
+
 func (m *memColumn) Col() interface{} {
 	return m.col
 }
@@ -256,6 +274,10 @@ func (m *memColumn) Length() int {
 		return len(m.col.([]apd.Decimal))
 	case coltypes.Timestamp:
 		return len(m.col.([]time.Time))
+	case coltypes.Interval:
+		return len(m.col.([]duration.Duration))
+	case coltypes.Datum:
+		return len(m.col.([]interface{}))
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
@@ -279,6 +301,10 @@ func (m *memColumn) SetLength(l int) {
 		m.col = m.col.([]apd.Decimal)[:l]
 	case coltypes.Timestamp:
 		m.col = m.col.([]time.Time)[:l]
+	case coltypes.Interval:
+		m.col = m.col.([]duration.Duration)[:l]
+	case coltypes.Datum:
+		m.col = m.col.([]interface{})[:l]
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
@@ -302,6 +328,10 @@ func (m *memColumn) Capacity() int {
 		return cap(m.col.([]apd.Decimal))
 	case coltypes.Timestamp:
 		return cap(m.col.([]time.Time))
+	case coltypes.Interval:
+		return cap(m.col.([]duration.Duration))
+	case coltypes.Datum:
+		return cap(m.col.([]interface{}))
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
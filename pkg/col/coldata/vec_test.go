@@ -537,3 +537,60 @@ func BenchmarkCopy(b *testing.B) {
 		})
 	}
 }
+
+func TestDatumVec(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const typ = coltypes.Datum
+	src := NewMemColumn(typ, 4)
+	datums := src.Datum()
+	datums[0], datums[2], datums[3] = "zero", "two", "three"
+	src.Nulls().SetNull(1)
+
+	t.Run("Append", func(t *testing.T) {
+		dest := NewMemColumn(typ, 1)
+		dest.Datum()[0] = "dest"
+		dest.Append(SliceArgs{
+			ColType:     typ,
+			Src:         src,
+			Sel:         []uint16{3, 1, 0},
+			DestIdx:     1,
+			SrcStartIdx: 0,
+			SrcEndIdx:   3,
+		})
+		require.Equal(t, 4, dest.Length())
+		require.Equal(t, []interface{}{"dest", "three", nil, "zero"}, dest.Datum())
+		require.True(t, dest.Nulls().NullAt(2))
+		require.False(t, dest.Nulls().NullAt(3))
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		for _, selOnDest := range []bool{false, true} {
+			dest := NewMemColumn(typ, 4)
+			dest.Copy(CopySliceArgs{
+				SliceArgs: SliceArgs{
+					ColType:     typ,
+					Src:         src,
+					Sel:         []uint16{1, 2},
+					SrcStartIdx: 0,
+					SrcEndIdx:   2,
+				},
+				SelOnDest: selOnDest,
+			})
+			if selOnDest {
+				require.True(t, dest.Nulls().NullAt(1))
+				require.Equal(t, "two", dest.Datum()[2])
+			} else {
+				require.True(t, dest.Nulls().NullAt(0))
+				require.Equal(t, "two", dest.Datum()[1])
+			}
+		}
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		window := src.Window(typ, 1, 3)
+		require.Equal(t, 2, window.Length())
+		require.True(t, window.Nulls().NullAt(0))
+		require.Equal(t, "two", window.PrettyValueAt(1, typ))
+	})
+}
@@ -28,6 +28,7 @@ import (
 	// {{/*
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	// */}}
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	// HACK: crlfmt removes the "*/}}" comment if it's the last line in the import
 	// block. This was picked because it sorts after "pkg/sql/exec/execgen" and
 	// has no deps.
@@ -49,10 +50,15 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // */}}
 
 func (m *memColumn) Append(args SliceArgs) {
 	switch args.ColType {
+	case coltypes.Datum:
+		m.appendDatums(args)
 	// {{range .}}
 	case _TYPES_T:
 		fromCol := args.Src._TemplateType()
@@ -159,6 +165,8 @@ func (m *memColumn) Copy(args CopySliceArgs) {
 	// }
 
 	switch args.ColType {
+	case coltypes.Datum:
+		m.copyDatums(args)
 	// {{range .}}
 	case _TYPES_T:
 		fromCol := args.Src._TemplateType()
@@ -191,6 +199,8 @@ func (m *memColumn) Copy(args CopySliceArgs) {
 
 func (m *memColumn) Window(colType coltypes.T, start uint64, end uint64) Vec {
 	switch colType {
+	case coltypes.Datum:
+		return m.windowDatums(start, end)
 	// {{range .}}
 	case _TYPES_T:
 		col := m._TemplateType()
@@ -210,6 +220,8 @@ func (m *memColumn) PrettyValueAt(colIdx uint16, colType coltypes.T) string {
 		return "NULL"
 	}
 	switch colType {
+	case coltypes.Datum:
+		return m.prettyDatumAt(colIdx)
 	// {{range .}}
 	case _TYPES_T:
 		col := m._TemplateType()
@@ -224,6 +236,8 @@ func (m *memColumn) PrettyValueAt(colIdx uint16, colType coltypes.T) string {
 // Helper to set the value in a Vec when the type is unknown.
 func SetValueAt(v Vec, elem interface{}, rowIdx uint16, colType coltypes.T) {
 	switch colType {
+	case coltypes.Datum:
+		v.Datum()[rowIdx] = elem
 	// {{range .}}
 	case _TYPES_T:
 		target := v._TemplateType()
@@ -11,6 +11,7 @@
 package colserde
 
 import (
+	"encoding/binary"
 	"fmt"
 	"reflect"
 	"unsafe"
@@ -20,6 +21,7 @@ import (
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/errors"
 )
 
@@ -77,8 +79,31 @@ const (
 	sizeOfInt32   = int(unsafe.Sizeof(int32(0)))
 	sizeOfInt64   = int(unsafe.Sizeof(int64(0)))
 	sizeOfFloat64 = int(unsafe.Sizeof(float64(0)))
+	// sizeOfInterval is the size of an interval marshaled by marshalInterval.
+	sizeOfInterval = 3 * sizeOfInt64
 )
 
+// marshalInterval appends the months, days and nanos of d to b.
+func marshalInterval(b []byte, d duration.Duration) []byte {
+	var buf [sizeOfInterval]byte
+	binary.LittleEndian.PutUint64(buf[0:], uint64(d.Months))
+	binary.LittleEndian.PutUint64(buf[sizeOfInt64:], uint64(d.Days))
+	binary.LittleEndian.PutUint64(buf[2*sizeOfInt64:], uint64(d.Nanos()))
+	return append(b, buf[:]...)
+}
+
+// unmarshalInterval decodes an interval marshaled by marshalInterval.
+func unmarshalInterval(b []byte) (duration.Duration, error) {
+	if len(b) != sizeOfInterval {
+		return duration.Duration{}, errors.Errorf("unexpected marshaled interval length %d", len(b))
+	}
+	return duration.DecodeDuration(
+		int64(binary.LittleEndian.Uint64(b[0:])),
+		int64(binary.LittleEndian.Uint64(b[sizeOfInt64:])),
+		int64(binary.LittleEndian.Uint64(b[2*sizeOfInt64:])),
+	), nil
+}
+
 var supportedTypes = func() map[coltypes.T]struct{} {
 	typs := make(map[coltypes.T]struct{})
 	for _, t := range []coltypes.T{
@@ -90,6 +115,7 @@ var supportedTypes = func() map[coltypes.T]struct{} {
 		coltypes.Int32,
 		coltypes.Int64,
 		coltypes.Timestamp,
+		coltypes.Interval,
 	} {
 		typs[t] = struct{}{}
 	}
@@ -116,10 +142,10 @@ func (c *ArrowBatchConverter) BatchToArrow(batch coldata.Batch) ([]*array.Data,
 			arrowBitmap = n.NullBitmap()
 		}
 
-		if typ == coltypes.Bool || typ == coltypes.Decimal || typ == coltypes.Timestamp {
-			// Bools, Decimals, and Timestamps are handled differently from other
-			// coltypes. Refer to the comment on ArrowBatchConverter.builders for
-			// more information.
+		if typ == coltypes.Bool || typ == coltypes.Decimal || typ == coltypes.Timestamp || typ == coltypes.Interval {
+			// Bools, Decimals, Timestamps, and Intervals are handled differently
+			// from other coltypes. Refer to the comment on
+			// ArrowBatchConverter.builders for more information.
 			var data *array.Data
 			switch typ {
 			case coltypes.Bool:
@@ -145,6 +171,14 @@ func (c *ArrowBatchConverter) BatchToArrow(batch coldata.Batch) ([]*array.Data,
 					c.builders.binaryBuilder.Append(marshaled)
 				}
 				data = c.builders.binaryBuilder.NewBinaryArray().Data()
+			case coltypes.Interval:
+				intervals := vec.Interval()[:n]
+				var marshaled []byte
+				for _, d := range intervals {
+					marshaled = marshalInterval(marshaled[:0], d)
+					c.builders.binaryBuilder.Append(marshaled)
+				}
+				data = c.builders.binaryBuilder.NewBinaryArray().Data()
 			default:
 				panic(fmt.Sprintf("unexpected type %s", typ))
 			}
@@ -314,6 +348,24 @@ func (c *ArrowBatchConverter) ArrowToBatch(data []*array.Data, b coldata.Batch)
 				}
 			}
 			arr = bytesArr
+		case coltypes.Interval:
+			bytesArr := array.NewBinaryData(d)
+			bytes := bytesArr.ValueBytes()
+			if bytes == nil {
+				// All bytes values are empty, so the representation is solely with the
+				// offsets slice, so create an empty slice so that the conversion
+				// corresponds.
+				bytes = make([]byte, 0)
+			}
+			offsets := bytesArr.ValueOffsets()
+			vecArr := vec.Interval()
+			for i := 0; i < len(offsets)-1; i++ {
+				var err error
+				if vecArr[i], err = unmarshalInterval(bytes[offsets[i]:offsets[i+1]]); err != nil {
+					return err
+				}
+			}
+			arr = bytesArr
 		default:
 			var col interface{}
 			switch typ {
@@ -92,7 +92,7 @@ func (s *FileSerializer) Reset(w io.Writer) error {
 	// The file format is a wrapper around the streaming format and the streaming
 	// format starts with a Schema message.
 	s.fb.Reset()
-	messageOffset := schemaMessage(s.fb, s.typs)
+	messageOffset := schemaMessage(s.fb, s.typs, nil /* names */, arrowserde.MetadataVersionV1)
 	s.fb.Finish(messageOffset)
 	schemaBytes := s.fb.FinishedBytes()
 	if _, err := s.w.Write(schemaBytes); err != nil {
@@ -331,9 +331,15 @@ func (w *countingWriter) Write(buf []byte) (int, error) {
 	return n, err
 }
 
-func schema(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
+// schema encodes the arrow Schema of typs. If names is non-nil, it holds the
+// names of the fields, which are then also marked as nullable.
+func schema(fb *flatbuffers.Builder, typs []coltypes.T, names []string) flatbuffers.UOffsetT {
 	fieldOffsets := make([]flatbuffers.UOffsetT, len(typs))
 	for idx, typ := range typs {
+		var nameOffset flatbuffers.UOffsetT
+		if names != nil {
+			nameOffset = fb.CreateString(names[idx])
+		}
 		var fbTyp byte
 		var fbTypOffset flatbuffers.UOffsetT
 		switch typ {
@@ -378,10 +384,19 @@ func schema(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
 			arrowserde.BinaryStart(fb)
 			fbTypOffset = arrowserde.BinaryEnd(fb)
 			fbTyp = arrowserde.TypeTimestamp
+		case coltypes.Interval:
+			// Intervals are marshaled into bytes, so we use binary headers.
+			arrowserde.BinaryStart(fb)
+			fbTypOffset = arrowserde.BinaryEnd(fb)
+			fbTyp = arrowserde.TypeInterval
 		default:
 			panic(errors.Errorf(`don't know how to map %s`, typ))
 		}
 		arrowserde.FieldStart(fb)
+		if names != nil {
+			arrowserde.FieldAddName(fb, nameOffset)
+			arrowserde.FieldAddNullable(fb, 1)
+		}
 		arrowserde.FieldAddTypeType(fb, fbTyp)
 		arrowserde.FieldAddType(fb, fbTypOffset)
 		fieldOffsets[idx] = arrowserde.FieldEnd(fb)
@@ -400,10 +415,15 @@ func schema(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
 	return arrowserde.SchemaEnd(fb)
 }
 
-func schemaMessage(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
-	schemaOffset := schema(fb, typs)
+func schemaMessage(
+	fb *flatbuffers.Builder,
+	typs []coltypes.T,
+	names []string,
+	version arrowserde.MetadataVersion,
+) flatbuffers.UOffsetT {
+	schemaOffset := schema(fb, typs, names)
 	arrowserde.MessageStart(fb)
-	arrowserde.MessageAddVersion(fb, arrowserde.MetadataVersionV1)
+	arrowserde.MessageAddVersion(fb, version)
 	arrowserde.MessageAddHeaderType(fb, arrowserde.MessageHeaderSchema)
 	arrowserde.MessageAddHeader(fb, schemaOffset)
 	return arrowserde.MessageEnd(fb)
@@ -412,7 +432,7 @@ func schemaMessage(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffs
 func fileFooter(
 	fb *flatbuffers.Builder, typs []coltypes.T, recordBatches []fileBlock,
 ) flatbuffers.UOffsetT {
-	schemaOffset := schema(fb, typs)
+	schemaOffset := schema(fb, typs, nil /* names */)
 	arrowserde.FooterStartRecordBatchesVector(fb, len(recordBatches))
 	// flatbuffers adds everything back to front. Reverse iterate so they're in
 	// the right order when they come out.
@@ -465,6 +485,8 @@ func typeFromField(field *arrowserde.Field) (coltypes.T, error) {
 		return coltypes.Decimal, nil
 	case arrowserde.TypeTimestamp:
 		return coltypes.Timestamp, nil
+	case arrowserde.TypeInterval:
+		return coltypes.Interval, nil
 	}
 	// It'd be nice if this error could include more details, but flatbuffers
 	// doesn't make a String method or anything like that.
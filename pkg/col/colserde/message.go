@@ -0,0 +1,86 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colserde
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde/arrowserde"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/pkg/errors"
+)
+
+// MessageSerializer converts our in-mem columnar batch representation into
+// standalone arrow IPC messages, each made of a flatbuffer-encoded metadata
+// part and a body, as carried by protocols such as Arrow Flight. Unlike the
+// messages used internally, the messages carry the names of the columns and
+// use the latest metadata version, which external arrow implementations
+// require.
+type MessageSerializer struct {
+	typs  []coltypes.T
+	names []string
+	a     *ArrowBatchConverter
+	rb    *RecordBatchSerializer
+
+	buf bytes.Buffer
+}
+
+// NewMessageSerializer creates a MessageSerializer for the given coltypes and
+// column names.
+func NewMessageSerializer(typs []coltypes.T, names []string) (*MessageSerializer, error) {
+	if len(typs) != len(names) {
+		return nil, errors.Errorf("mismatched number of types and names: %d != %d", len(typs), len(names))
+	}
+	a, err := NewArrowBatchConverter(typs)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := NewRecordBatchSerializer(typs)
+	if err != nil {
+		return nil, err
+	}
+	rb.version = arrowserde.MetadataVersionV4
+	return &MessageSerializer{
+		typs:  typs,
+		names: names,
+		a:     a,
+		rb:    rb,
+	}, nil
+}
+
+// SchemaMessage returns the metadata of the Schema message that describes the
+// batches. The message has no body.
+func (s *MessageSerializer) SchemaMessage() []byte {
+	fb := flatbuffers.NewBuilder(flatbufferBuilderInitialCapacity)
+	fb.Finish(schemaMessage(fb, s.typs, s.names, arrowserde.MetadataVersionV4))
+	return fb.FinishedBytes()
+}
+
+// BatchMessage serializes batch as a RecordBatch message and returns its
+// metadata and its body. They are only valid until the next call to
+// BatchMessage.
+func (s *MessageSerializer) BatchMessage(batch coldata.Batch) (metadata, body []byte, _ error) {
+	data, err := s.a.BatchToArrow(batch)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.buf.Reset()
+	metadataLen, _, err := s.rb.Serialize(&s.buf, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The serialized message is prefixed with the length of its metadata, which
+	// is followed by the body.
+	msg := s.buf.Bytes()[metadataLengthNumBytes:]
+	return msg[:metadataLen], msg[metadataLen:], nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colserde_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde/arrowserde"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageSerializer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	typs, b := randomBatch(testAllocator)
+	names := make([]string, len(typs))
+	for i := range names {
+		names[i] = fmt.Sprintf("col%d", i)
+	}
+
+	s, err := colserde.NewMessageSerializer(typs, names)
+	require.NoError(t, err)
+
+	schemaMsg := arrowserde.GetRootAsMessage(s.SchemaMessage(), 0)
+	require.Equal(t, arrowserde.MetadataVersionV4, schemaMsg.Version())
+	require.Equal(t, arrowserde.MessageHeaderSchema, schemaMsg.HeaderType())
+	require.Equal(t, int64(0), schemaMsg.BodyLength())
+
+	// Make a copy of the original batch because the converter modifies and
+	// casts data without copying for performance reasons.
+	original := copyBatch(b)
+	metadata, body, err := s.BatchMessage(b)
+	require.NoError(t, err)
+	batchMsg := arrowserde.GetRootAsMessage(metadata, 0)
+	require.Equal(t, arrowserde.MetadataVersionV4, batchMsg.Version())
+	require.Equal(t, arrowserde.MessageHeaderRecordBatch, batchMsg.HeaderType())
+	require.Equal(t, int64(len(body)), batchMsg.BodyLength())
+
+	// Reassemble the message in the format expected by the RecordBatchSerializer
+	// and check that it decodes into the original batch.
+	msg := make([]byte, 4, 4+len(metadata)+len(body))
+	binary.LittleEndian.PutUint32(msg, uint32(len(metadata)))
+	msg = append(append(msg, metadata...), body...)
+	rb, err := colserde.NewRecordBatchSerializer(typs)
+	require.NoError(t, err)
+	var data []*array.Data
+	require.NoError(t, rb.Deserialize(&data, msg))
+	c, err := colserde.NewArrowBatchConverter(typs)
+	require.NoError(t, err)
+	roundtrip := coldata.NewMemBatchWithSize(typs, int(b.Length()))
+	require.NoError(t, c.ArrowToBatch(data, roundtrip))
+	assertEqualBatches(t, original, roundtrip)
+}
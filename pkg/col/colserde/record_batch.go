@@ -36,7 +36,7 @@ func numBuffersForType(t coltypes.T) int {
 	// null bitmap and one for the values.
 	numBuffers := 2
 	switch t {
-	case coltypes.Bytes, coltypes.Decimal, coltypes.Timestamp:
+	case coltypes.Bytes, coltypes.Decimal, coltypes.Timestamp, coltypes.Interval:
 		// This type has an extra offsets buffer.
 		numBuffers = 3
 	}
@@ -56,6 +56,8 @@ type RecordBatchSerializer struct {
 	// of the type at the corresponding index of typs passed in in
 	// NewRecordBatchSerializer.
 	numBuffers []int
+	// version is the metadata version of the serialized messages.
+	version arrowserde.MetadataVersion
 
 	builder *flatbuffers.Builder
 	scratch struct {
@@ -74,6 +76,7 @@ func NewRecordBatchSerializer(typs []coltypes.T) (*RecordBatchSerializer, error)
 	}
 	s := &RecordBatchSerializer{
 		numBuffers: make([]int, len(typs)),
+		version:    arrowserde.MetadataVersionV1,
 		builder:    flatbuffers.NewBuilder(flatbufferBuilderInitialCapacity),
 	}
 	for i := range typs {
@@ -173,7 +176,7 @@ func (s *RecordBatchSerializer) Serialize(
 	// Finally, encode the Message table. This will include the RecordBatch above
 	// as well as some metadata.
 	arrowserde.MessageStart(s.builder)
-	arrowserde.MessageAddVersion(s.builder, arrowserde.MetadataVersionV1)
+	arrowserde.MessageAddVersion(s.builder, s.version)
 	arrowserde.MessageAddHeaderType(s.builder, arrowserde.MessageHeaderRecordBatch)
 	arrowserde.MessageAddHeader(s.builder, header)
 	arrowserde.MessageAddBodyLength(s.builder, int64(totalBufferLen))
@@ -12,6 +12,7 @@ package colserde_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
@@ -163,6 +164,17 @@ func randomDataFromType(rng *rand.Rand, t coltypes.T, n int, nullProbability flo
 			}
 		}
 		builder.(*array.BinaryBuilder).AppendValues(data, valid)
+	case coltypes.Interval:
+		builder = array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		data := make([][]byte, n)
+		for i := range data {
+			// Intervals are marshaled as their months, days and nanos.
+			data[i] = make([]byte, 24)
+			for j := 0; j < 3; j++ {
+				binary.LittleEndian.PutUint64(data[i][8*j:], rng.Uint64())
+			}
+		}
+		builder.(*array.BinaryBuilder).AppendValues(data, valid)
 	default:
 		panic(fmt.Sprintf("unsupported type %s", t))
 	}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package colsimd contains data-parallel implementations of the hottest
+// int64 and float64 kernels used by the vectorized engine: comparing a column
+// against a constant, hashing a column of keys and marking the rows of a
+// sorted column that differ from their predecessor.
+//
+// On amd64 machines that support AVX2 the kernels are implemented in assembly
+// and process four values per instruction. Everywhere else (other
+// architectures, or amd64 machines without AVX2) they fall back to plain Go
+// loops. Both implementations produce identical results, including the hash
+// values, which must agree across nodes because they are used to route rows
+// between them.
+package colsimd
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// CmpOp is a comparison operator supported by the selection kernels.
+type CmpOp int
+
+const (
+	// EQ is the = operator.
+	EQ CmpOp = iota
+	// NE is the != operator.
+	NE
+	// LT is the < operator.
+	LT
+	// LE is the <= operator.
+	LE
+	// GT is the > operator.
+	GT
+	// GE is the >= operator.
+	GE
+)
+
+func (op CmpOp) String() string {
+	switch op {
+	case EQ:
+		return "EQ"
+	case NE:
+		return "NE"
+	case LT:
+		return "LT"
+	case LE:
+		return "LE"
+	case GT:
+		return "GT"
+	case GE:
+		return "GE"
+	default:
+		return fmt.Sprintf("CmpOp(%d)", int(op))
+	}
+}
+
+// useAVX2 determines whether the assembly kernels are used. It is initialized
+// from the CPU features of the machine and is only changed in tests.
+var useAVX2 = hasAVX2
+
+// Enabled returns whether the assembly kernels are used on this machine.
+func Enabled() bool {
+	return useAVX2
+}
+
+// maxSelLength is the maximum number of values that the selection kernels can
+// process at once, since the selected indices are stored as uint16s.
+const maxSelLength = math.MaxUint16 + 1
+
+// chunkLen is the number of values that the assembly selection kernels compare
+// before the resulting bitmask is converted into selected indices.
+const chunkLen = 1024
+
+// SelectInt64Const stores into sel the indices i of col for which col[i] op c
+// holds, in increasing order, and returns the number of such indices. sel must
+// be at least as long as col, and col must have at most 65536 elements.
+func SelectInt64Const(op CmpOp, col []int64, c int64, sel []uint16) int {
+	if len(col) > maxSelLength {
+		panic("colsimd: column too long for selection")
+	}
+	sel = sel[:len(col)]
+	idx, n := 0, 0
+	if useAVX2 {
+		var mask [chunkLen / 64]uint64
+		n = len(col) &^ 3
+		for start := 0; start < n; start += chunkLen {
+			end := start + chunkLen
+			if end > n {
+				end = n
+			}
+			cmpInt64ConstAVX2(&col[start], end-start, c, int(op), &mask[0])
+			idx = maskToSel(mask[:(end-start+63)/64], sel, idx, start)
+		}
+	}
+	return selectInt64ConstGeneric(op, col, c, sel, n, idx)
+}
+
+// SelectFloat64Const is like SelectInt64Const for float64 columns. It uses
+// SQL semantics for NaN: NaN is equal to itself and smaller than any other
+// value.
+func SelectFloat64Const(op CmpOp, col []float64, c float64, sel []uint16) int {
+	if len(col) > maxSelLength {
+		panic("colsimd: column too long for selection")
+	}
+	sel = sel[:len(col)]
+	idx, n := 0, 0
+	// The assembly kernels assume that the constant isn't NaN, so we leave that
+	// unusual case to the generic loop.
+	if useAVX2 && !math.IsNaN(c) {
+		var mask [chunkLen / 64]uint64
+		n = len(col) &^ 3
+		for start := 0; start < n; start += chunkLen {
+			end := start + chunkLen
+			if end > n {
+				end = n
+			}
+			cmpFloat64ConstAVX2(&col[start], end-start, c, int(op), &mask[0])
+			idx = maskToSel(mask[:(end-start+63)/64], sel, idx, start)
+		}
+	}
+	return selectFloat64ConstGeneric(op, col, c, sel, n, idx)
+}
+
+// maskToSel stores base plus the positions of the set bits of mask into sel,
+// starting at sel[idx], and returns the new number of selected indices.
+func maskToSel(mask []uint64, sel []uint16, idx int, base int) int {
+	for w, m := range mask {
+		for m != 0 {
+			sel[idx] = uint16(base + w*64 + bits.TrailingZeros64(m))
+			idx++
+			m &= m - 1
+		}
+	}
+	return idx
+}
+
+// HashInt64 combines every key with the hash already stored in the
+// corresponding bucket, exactly like the vectorized hash table does for INT8
+// keys. buckets must be at least as long as keys.
+func HashInt64(buckets []uint64, keys []int64) {
+	buckets = buckets[:len(keys)]
+	start := 0
+	if useAVX2 && len(keys) >= 4 {
+		start = len(keys) &^ 3
+		hashInt64AVX2(&buckets[0], &keys[0], start)
+	}
+	hashInt64Generic(buckets[start:], keys[start:])
+}
+
+// HashFloat64 is like HashInt64 for float64 keys. Positive and negative zero
+// hash to the same value, as do all NaNs.
+func HashFloat64(buckets []uint64, keys []float64) {
+	buckets = buckets[:len(keys)]
+	start := 0
+	if useAVX2 && len(keys) >= 4 {
+		start = len(keys) &^ 3
+		hashFloat64AVX2(&buckets[0], &keys[0], start)
+	}
+	hashFloat64Generic(buckets[start:], keys[start:])
+}
+
+// DistinctInt64 sets outputCol[i] to true for every i > 0 such that col[i]
+// differs from col[i-1], leaving the other values of outputCol untouched. It
+// is used to find the boundaries between groups of equal values in a sorted
+// column. outputCol must be at least as long as col.
+func DistinctInt64(col []int64, outputCol []bool) {
+	outputCol = outputCol[:len(col)]
+	start := 1
+	if useAVX2 && len(col) >= 5 {
+		n := (len(col) - 1) &^ 3
+		distinctInt64AVX2(&col[0], n, &outputCol[1])
+		start += n
+	}
+	distinctInt64Generic(col, outputCol, start)
+}
+
+// DistinctFloat64 is like DistinctInt64 for float64 columns. NaNs are
+// considered equal to each other.
+func DistinctFloat64(col []float64, outputCol []bool) {
+	outputCol = outputCol[:len(col)]
+	start := 1
+	if useAVX2 && len(col) >= 5 {
+		n := (len(col) - 1) &^ 3
+		distinctFloat64AVX2(&col[0], n, &outputCol[1])
+		start += n
+	}
+	distinctFloat64Generic(col, outputCol, start)
+}
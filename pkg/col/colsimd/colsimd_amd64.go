@@ -0,0 +1,75 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colsimd
+
+// hasAVX2 is true if both the CPU and the operating system support AVX2.
+var hasAVX2 = detectAVX2()
+
+func detectAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	// The OS must have enabled the saving of the YMM registers (OSXSAVE, bit 27
+	// of ECX), and the XMM and YMM state must be enabled in XCR0 (bits 1 and 2).
+	if ecx1&(1<<27) == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 {
+		return false
+	}
+	// AVX2 is bit 5 of EBX.
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+// cpuid executes the CPUID instruction with the given EAX and ECX inputs.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv returns the contents of the XCR0 register.
+func xgetbv() (eax, edx uint32)
+
+// cmpInt64ConstAVX2 compares the first n values of col (n must be a multiple
+// of 4) against c using the comparison operator op and stores the result as a
+// bitmask into mask, which must have room for (n+63)/64 words.
+//
+//go:noescape
+func cmpInt64ConstAVX2(col *int64, n int, c int64, op int, mask *uint64)
+
+// cmpFloat64ConstAVX2 is like cmpInt64ConstAVX2 for float64 values. c must not
+// be NaN.
+//
+//go:noescape
+func cmpFloat64ConstAVX2(col *float64, n int, c float64, op int, mask *uint64)
+
+// hashInt64AVX2 hashes the first n keys (n must be a multiple of 4) into the
+// corresponding buckets.
+//
+//go:noescape
+func hashInt64AVX2(buckets *uint64, keys *int64, n int)
+
+// hashFloat64AVX2 is like hashInt64AVX2 for float64 keys.
+//
+//go:noescape
+func hashFloat64AVX2(buckets *uint64, keys *float64, n int)
+
+// distinctInt64AVX2 compares the n values starting at the second element of
+// col (n must be a multiple of 4) to their predecessors and sets the
+// corresponding values of outputCol to true when they differ.
+//
+//go:noescape
+func distinctInt64AVX2(col *int64, n int, outputCol *bool)
+
+// distinctFloat64AVX2 is like distinctInt64AVX2 for float64 values.
+//
+//go:noescape
+func distinctFloat64AVX2(col *float64, n int, outputCol *bool)
@@ -0,0 +1,424 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+#include "textflag.h"
+
+// The hash constants, see generic.go. Each one is repeated four times so that
+// it can be used directly as a memory operand of a 256-bit instruction. For
+// the multiplications we also need the high 32 bits of each constant, since
+// VPMULUDQ only multiplies the low 32 bits of every lane.
+DATA m1<>+0(SB)/8, $0xea38ec9079f01541
+DATA m1<>+8(SB)/8, $0xea38ec9079f01541
+DATA m1<>+16(SB)/8, $0xea38ec9079f01541
+DATA m1<>+24(SB)/8, $0xea38ec9079f01541
+GLOBL m1<>(SB), RODATA|NOPTR, $32
+
+DATA m1hi<>+0(SB)/8, $0xea38ec90
+DATA m1hi<>+8(SB)/8, $0xea38ec90
+DATA m1hi<>+16(SB)/8, $0xea38ec90
+DATA m1hi<>+24(SB)/8, $0xea38ec90
+GLOBL m1hi<>(SB), RODATA|NOPTR, $32
+
+DATA m2<>+0(SB)/8, $0x2723a30d96da1399
+DATA m2<>+8(SB)/8, $0x2723a30d96da1399
+DATA m2<>+16(SB)/8, $0x2723a30d96da1399
+DATA m2<>+24(SB)/8, $0x2723a30d96da1399
+GLOBL m2<>(SB), RODATA|NOPTR, $32
+
+DATA m2hi<>+0(SB)/8, $0x2723a30d
+DATA m2hi<>+8(SB)/8, $0x2723a30d
+DATA m2hi<>+16(SB)/8, $0x2723a30d
+DATA m2hi<>+24(SB)/8, $0x2723a30d
+GLOBL m2hi<>(SB), RODATA|NOPTR, $32
+
+DATA m3<>+0(SB)/8, $0x83cf8eadf876d2d7
+DATA m3<>+8(SB)/8, $0x83cf8eadf876d2d7
+DATA m3<>+16(SB)/8, $0x83cf8eadf876d2d7
+DATA m3<>+24(SB)/8, $0x83cf8eadf876d2d7
+GLOBL m3<>(SB), RODATA|NOPTR, $32
+
+DATA m3hi<>+0(SB)/8, $0x83cf8ead
+DATA m3hi<>+8(SB)/8, $0x83cf8ead
+DATA m3hi<>+16(SB)/8, $0x83cf8ead
+DATA m3hi<>+24(SB)/8, $0x83cf8ead
+GLOBL m3hi<>(SB), RODATA|NOPTR, $32
+
+DATA c0<>+0(SB)/8, $0x00756ea16a56a621
+DATA c0<>+8(SB)/8, $0x00756ea16a56a621
+DATA c0<>+16(SB)/8, $0x00756ea16a56a621
+DATA c0<>+24(SB)/8, $0x00756ea16a56a621
+GLOBL c0<>(SB), RODATA|NOPTR, $32
+
+DATA c1<>+0(SB)/8, $0x0052ef6bbb8f63bf
+DATA c1<>+8(SB)/8, $0x0052ef6bbb8f63bf
+DATA c1<>+16(SB)/8, $0x0052ef6bbb8f63bf
+DATA c1<>+24(SB)/8, $0x0052ef6bbb8f63bf
+GLOBL c1<>(SB), RODATA|NOPTR, $32
+
+DATA c1hi<>+0(SB)/8, $0x0052ef6b
+DATA c1hi<>+8(SB)/8, $0x0052ef6b
+DATA c1hi<>+16(SB)/8, $0x0052ef6b
+DATA c1hi<>+24(SB)/8, $0x0052ef6b
+GLOBL c1hi<>(SB), RODATA|NOPTR, $32
+
+DATA eight<>+0(SB)/8, $8
+DATA eight<>+8(SB)/8, $8
+DATA eight<>+16(SB)/8, $8
+DATA eight<>+24(SB)/8, $8
+GLOBL eight<>(SB), RODATA|NOPTR, $32
+
+// expandMask maps a 4 bit mask to 4 bytes, each of which is 1 if the
+// corresponding bit of the mask is set and 0 otherwise.
+DATA expandMask<>+0(SB)/4, $0x00000000
+DATA expandMask<>+4(SB)/4, $0x00000001
+DATA expandMask<>+8(SB)/4, $0x00000100
+DATA expandMask<>+12(SB)/4, $0x00000101
+DATA expandMask<>+16(SB)/4, $0x00010000
+DATA expandMask<>+20(SB)/4, $0x00010001
+DATA expandMask<>+24(SB)/4, $0x00010100
+DATA expandMask<>+28(SB)/4, $0x00010101
+DATA expandMask<>+32(SB)/4, $0x01000000
+DATA expandMask<>+36(SB)/4, $0x01000001
+DATA expandMask<>+40(SB)/4, $0x01000100
+DATA expandMask<>+44(SB)/4, $0x01000101
+DATA expandMask<>+48(SB)/4, $0x01010000
+DATA expandMask<>+52(SB)/4, $0x01010001
+DATA expandMask<>+56(SB)/4, $0x01010100
+DATA expandMask<>+60(SB)/4, $0x01010101
+GLOBL expandMask<>(SB), RODATA|NOPTR, $64
+
+// MUL64 multiplies each 64 bit lane of a by the constant whose low and high
+// 32 bits are in lo and hi, modulo 2^64, using t and u as scratch registers:
+// a*b = lo(a)*lo(b) + (hi(a)*lo(b) + lo(a)*hi(b)) << 32.
+#define MUL64(a, lo, hi, t, u) \
+	VPSRLQ   $32, a, t; \
+	VPMULUDQ lo, t, t; \
+	VPMULUDQ hi, a, u; \
+	VPADDQ   u, t, t; \
+	VPSLLQ   $32, t, t; \
+	VPMULUDQ lo, a, a; \
+	VPADDQ   t, a, a
+
+// ROTL31 rotates each 64 bit lane of a left by 31 bits.
+#define ROTL31(a, t) \
+	VPSLLQ $31, a, t; \
+	VPSRLQ $33, a, a; \
+	VPOR   t, a, a
+
+// HASH64 combines the seeds in h with the 8 byte values in v, which is
+// clobbered, the same way hash64 does.
+#define HASH64(h, v, t, u) \
+	VPADDQ eight<>(SB), h, h; \
+	VPXOR  v, h, h; \
+	MUL64(h, m1<>(SB), m1hi<>(SB), t, u); \
+	ROTL31(h, t); \
+	MUL64(h, m2<>(SB), m2hi<>(SB), t, u); \
+	VPSRLQ $29, h, t; \
+	VPXOR  t, h, h; \
+	MUL64(h, m3<>(SB), m3hi<>(SB), t, u); \
+	VPSRLQ $32, h, t; \
+	VPXOR  t, h, h
+
+// ACCUMULATE appends the 4 bit comparison result in the sign bits of y, xored
+// with R9, to the bitmask word in R8, which is stored into (DI) once it is
+// full, and advances the loop counters. CX is the number of bits already in
+// R8, SI points to the next values and R10 is the number of values left.
+#define ACCUMULATE(y, loop) \
+	VMOVMSKPD y, AX; \
+	XORQ      R9, AX; \
+	SHLQ      CX, AX; \
+	ORQ       AX, R8; \
+	ADDQ      $32, SI; \
+	SUBQ      $4, R10; \
+	ADDQ      $4, CX; \
+	CMPQ      CX, $64; \
+	JNE       loop; \
+	MOVQ      R8, (DI); \
+	ADDQ      $8, DI; \
+	XORQ      R8, R8; \
+	XORQ      CX, CX; \
+	JMP       loop
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// func cmpInt64ConstAVX2(col *int64, n int, c int64, op int, mask *uint64)
+TEXT ·cmpInt64ConstAVX2(SB), NOSPLIT, $0-40
+	MOVQ         col+0(FP), SI
+	MOVQ         n+8(FP), R10
+	VPBROADCASTQ c+16(FP), Y1
+	MOVQ         op+24(FP), DX
+	MOVQ         mask+32(FP), DI
+	XORQ         R8, R8
+	XORQ         CX, CX
+
+	// NE, LE and GE are computed as the negation of EQ, GT and LT
+	// respectively, so R9 is set to 0xf for them.
+	XORQ R9, R9
+	CMPQ DX, $0
+	JEQ  int64eq
+	CMPQ DX, $1
+	JEQ  int64ne
+	CMPQ DX, $2
+	JEQ  int64lt
+	CMPQ DX, $3
+	JEQ  int64le
+	CMPQ DX, $4
+	JEQ  int64gt
+	MOVQ $0xf, R9
+	JMP  int64lt
+
+int64ne:
+	MOVQ $0xf, R9
+
+int64eq:
+	TESTQ    R10, R10
+	JZ       int64done
+	VMOVDQU  (SI), Y0
+	VPCMPEQQ Y1, Y0, Y0
+	ACCUMULATE(Y0, int64eq)
+
+int64le:
+	MOVQ $0xf, R9
+
+int64gt:
+	TESTQ    R10, R10
+	JZ       int64done
+	VMOVDQU  (SI), Y0
+	VPCMPGTQ Y1, Y0, Y0
+	ACCUMULATE(Y0, int64gt)
+
+int64lt:
+	TESTQ    R10, R10
+	JZ       int64done
+	VMOVDQU  (SI), Y0
+	VPCMPGTQ Y0, Y1, Y0
+	ACCUMULATE(Y0, int64lt)
+
+int64done:
+	TESTQ CX, CX
+	JZ    int64ret
+	MOVQ  R8, (DI)
+
+int64ret:
+	VZEROUPPER
+	RET
+
+// The comparison predicates below make NaN values in the column, which are
+// smaller than any (non-NaN) constant, compare correctly: the ordered (_OQ)
+// predicates are false for NaN and the unordered (_UQ) ones are true.
+#define CMP_EQ_OQ $0x00
+#define CMP_NEQ_UQ $0x04
+#define CMP_NGE_UQ $0x19
+#define CMP_NGT_UQ $0x1a
+#define CMP_GE_OQ $0x1d
+#define CMP_GT_OQ $0x1e
+
+// func cmpFloat64ConstAVX2(col *float64, n int, c float64, op int, mask *uint64)
+TEXT ·cmpFloat64ConstAVX2(SB), NOSPLIT, $0-40
+	MOVQ         col+0(FP), SI
+	MOVQ         n+8(FP), R10
+	VBROADCASTSD c+16(FP), Y1
+	MOVQ         op+24(FP), DX
+	MOVQ         mask+32(FP), DI
+	XORQ         R8, R8
+	XORQ         CX, CX
+	XORQ         R9, R9
+	CMPQ         DX, $0
+	JEQ          float64eq
+	CMPQ         DX, $1
+	JEQ          float64ne
+	CMPQ         DX, $2
+	JEQ          float64lt
+	CMPQ         DX, $3
+	JEQ          float64le
+	CMPQ         DX, $4
+	JEQ          float64gt
+	JMP          float64ge
+
+float64eq:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_EQ_OQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64eq)
+
+float64ne:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_NEQ_UQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64ne)
+
+float64lt:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_NGE_UQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64lt)
+
+float64le:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_NGT_UQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64le)
+
+float64gt:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_GT_OQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64gt)
+
+float64ge:
+	TESTQ   R10, R10
+	JZ      float64done
+	VMOVUPD (SI), Y0
+	VCMPPD  CMP_GE_OQ, Y1, Y0, Y0
+	ACCUMULATE(Y0, float64ge)
+
+float64done:
+	TESTQ CX, CX
+	JZ    float64ret
+	MOVQ  R8, (DI)
+
+float64ret:
+	VZEROUPPER
+	RET
+
+// func hashInt64AVX2(buckets *uint64, keys *int64, n int)
+TEXT ·hashInt64AVX2(SB), NOSPLIT, $0-24
+	MOVQ buckets+0(FP), DI
+	MOVQ keys+8(FP), SI
+	MOVQ n+16(FP), CX
+
+hashint64loop:
+	TESTQ   CX, CX
+	JZ      hashint64done
+	VMOVDQU (DI), Y0
+	VMOVDQU (SI), Y1
+	HASH64(Y0, Y1, Y2, Y3)
+	VMOVDQU Y0, (DI)
+	ADDQ    $32, DI
+	ADDQ    $32, SI
+	SUBQ    $4, CX
+	JMP     hashint64loop
+
+hashint64done:
+	VZEROUPPER
+	RET
+
+// func hashFloat64AVX2(buckets *uint64, keys *float64, n int)
+TEXT ·hashFloat64AVX2(SB), NOSPLIT, $0-24
+	MOVQ  buckets+0(FP), DI
+	MOVQ  keys+8(FP), SI
+	MOVQ  n+16(FP), CX
+	VPXOR Y7, Y7, Y7
+
+hashfloat64loop:
+	TESTQ   CX, CX
+	JZ      hashfloat64done
+	VMOVDQU (DI), Y0
+	VMOVUPD (SI), Y1
+
+	// Zeroes and NaNs (the lanes set in Y5) hash to c1 * (c0 ^ seed).
+	VCMPPD CMP_EQ_OQ, Y7, Y1, Y5
+	VCMPPD $0x03, Y1, Y1, Y6
+	VPOR   Y6, Y5, Y5
+	VPXOR  c0<>(SB), Y0, Y4
+	MUL64(Y4, c1<>(SB), c1hi<>(SB), Y2, Y3)
+
+	HASH64(Y0, Y1, Y2, Y3)
+	VPAND   Y5, Y4, Y4
+	VPANDN  Y0, Y5, Y0
+	VPOR    Y4, Y0, Y0
+	VMOVDQU Y0, (DI)
+	ADDQ    $32, DI
+	ADDQ    $32, SI
+	SUBQ    $4, CX
+	JMP     hashfloat64loop
+
+hashfloat64done:
+	VZEROUPPER
+	RET
+
+// func distinctInt64AVX2(col *int64, n int, outputCol *bool)
+TEXT ·distinctInt64AVX2(SB), NOSPLIT, $0-24
+	MOVQ col+0(FP), SI
+	MOVQ n+8(FP), CX
+	MOVQ outputCol+16(FP), DI
+	LEAQ expandMask<>(SB), DX
+
+distinctint64loop:
+	TESTQ     CX, CX
+	JZ        distinctint64done
+	VMOVDQU   (SI), Y0
+	VMOVDQU   8(SI), Y1
+	VPCMPEQQ  Y1, Y0, Y0
+	VMOVMSKPD Y0, AX
+	XORQ      $0xf, AX
+	MOVL      (DX)(AX*4), BX
+	ORL       BX, (DI)
+	ADDQ      $32, SI
+	ADDQ      $4, DI
+	SUBQ      $4, CX
+	JMP       distinctint64loop
+
+distinctint64done:
+	VZEROUPPER
+	RET
+
+// func distinctFloat64AVX2(col *float64, n int, outputCol *bool)
+TEXT ·distinctFloat64AVX2(SB), NOSPLIT, $0-24
+	MOVQ col+0(FP), SI
+	MOVQ n+8(FP), CX
+	MOVQ outputCol+16(FP), DI
+	LEAQ expandMask<>(SB), DX
+
+distinctfloat64loop:
+	TESTQ   CX, CX
+	JZ      distinctfloat64done
+	VMOVUPD (SI), Y0
+	VMOVUPD 8(SI), Y1
+
+	// Two values are distinct if they aren't equal, unless both are NaN.
+	VCMPPD    CMP_NEQ_UQ, Y1, Y0, Y2
+	VCMPPD    $0x03, Y0, Y0, Y3
+	VCMPPD    $0x03, Y1, Y1, Y4
+	VPAND     Y4, Y3, Y3
+	VPANDN    Y2, Y3, Y2
+	VMOVMSKPD Y2, AX
+	MOVL      (DX)(AX*4), BX
+	ORL       BX, (DI)
+	ADDQ      $32, SI
+	ADDQ      $4, DI
+	SUBQ      $4, CX
+	JMP       distinctfloat64loop
+
+distinctfloat64done:
+	VZEROUPPER
+	RET
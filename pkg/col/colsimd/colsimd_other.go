@@ -0,0 +1,43 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build !amd64
+
+package colsimd
+
+// hasAVX2 is always false on architectures without assembly kernels.
+const hasAVX2 = false
+
+// The functions below are never called since useAVX2 is always false, but
+// they are needed for the package to compile.
+
+func cmpInt64ConstAVX2(col *int64, n int, c int64, op int, mask *uint64) {
+	panic("colsimd: AVX2 is not supported")
+}
+
+func cmpFloat64ConstAVX2(col *float64, n int, c float64, op int, mask *uint64) {
+	panic("colsimd: AVX2 is not supported")
+}
+
+func hashInt64AVX2(buckets *uint64, keys *int64, n int) {
+	panic("colsimd: AVX2 is not supported")
+}
+
+func hashFloat64AVX2(buckets *uint64, keys *float64, n int) {
+	panic("colsimd: AVX2 is not supported")
+}
+
+func distinctInt64AVX2(col *int64, n int, outputCol *bool) {
+	panic("colsimd: AVX2 is not supported")
+}
+
+func distinctFloat64AVX2(col *float64, n int, outputCol *bool) {
+	panic("colsimd: AVX2 is not supported")
+}
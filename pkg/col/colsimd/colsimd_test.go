@@ -0,0 +1,317 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colsimd
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+var allCmpOps = []CmpOp{EQ, NE, LT, LE, GT, GE}
+
+// withAVX2 runs f with the assembly kernels disabled and, if the machine
+// supports them, enabled.
+func withAVX2(t testing.TB, f func(t testing.TB, avx2 bool)) {
+	defer func(old bool) { useAVX2 = old }(useAVX2)
+	useAVX2 = false
+	f(t, false)
+	if !hasAVX2 {
+		return
+	}
+	useAVX2 = true
+	f(t, true)
+}
+
+// randInt64s returns n random int64s drawn from a small domain (so that
+// equality is common) with occasional extreme values.
+func randInt64s(rng *rand.Rand, n int) []int64 {
+	col := make([]int64, n)
+	for i := range col {
+		switch rng.Intn(20) {
+		case 0:
+			col[i] = math.MinInt64
+		case 1:
+			col[i] = math.MaxInt64
+		default:
+			col[i] = int64(rng.Intn(10) - 5)
+		}
+	}
+	return col
+}
+
+// randFloat64s returns n random float64s drawn from a small domain with
+// occasional NaNs, infinities and negative zeroes.
+func randFloat64s(rng *rand.Rand, n int) []float64 {
+	col := make([]float64, n)
+	for i := range col {
+		switch rng.Intn(20) {
+		case 0:
+			col[i] = math.NaN()
+		case 1:
+			col[i] = math.Inf(1)
+		case 2:
+			col[i] = math.Inf(-1)
+		case 3:
+			col[i] = math.Copysign(0, -1)
+		default:
+			col[i] = float64(rng.Intn(10)-5) / 2
+		}
+	}
+	return col
+}
+
+// testLengths are the column lengths that the tests use. They exercise the
+// remainders of the assembly loops as well as several bitmask chunks.
+var testLengths = []int{0, 1, 3, 4, 5, 63, 64, 65, 1023, 1024, 1025, 3000, maxSelLength}
+
+func TestSelectInt64Const(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(0))
+	for _, n := range testLengths {
+		col := randInt64s(rng, n)
+		for _, c := range []int64{0, 3, math.MinInt64, math.MaxInt64} {
+			for _, op := range allCmpOps {
+				var expected []uint16
+				for i, v := range col {
+					if cmpInt64(op, v, c) {
+						expected = append(expected, uint16(i))
+					}
+				}
+				withAVX2(t, func(t testing.TB, avx2 bool) {
+					sel := make([]uint16, n)
+					actual := sel[:SelectInt64Const(op, col, c, sel)]
+					if len(expected) == 0 && len(actual) == 0 {
+						return
+					}
+					if !reflect.DeepEqual(expected, actual) {
+						t.Fatalf("n=%d c=%d op=%s avx2=%t: expected %v, got %v", n, c, op, avx2, expected, actual)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSelectFloat64Const(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(0))
+	for _, n := range testLengths {
+		col := randFloat64s(rng, n)
+		for _, c := range []float64{0, 1.5, math.Inf(1), math.Inf(-1), math.NaN()} {
+			for _, op := range allCmpOps {
+				var expected []uint16
+				for i, v := range col {
+					if cmpFloat64(op, v, c) {
+						expected = append(expected, uint16(i))
+					}
+				}
+				withAVX2(t, func(t testing.TB, avx2 bool) {
+					sel := make([]uint16, n)
+					actual := sel[:SelectFloat64Const(op, col, c, sel)]
+					if len(expected) == 0 && len(actual) == 0 {
+						return
+					}
+					if !reflect.DeepEqual(expected, actual) {
+						t.Fatalf("n=%d c=%f op=%s avx2=%t: expected %v, got %v", n, c, op, avx2, expected, actual)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestCmpFloat64(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	nan := math.NaN()
+	for _, tc := range []struct {
+		a, b     float64
+		expected int
+	}{
+		{1, 2, -1},
+		{2, 1, 1},
+		{0, math.Copysign(0, -1), 0},
+		{nan, nan, 0},
+		{nan, math.Inf(-1), -1},
+		{math.Inf(-1), nan, 1},
+	} {
+		if actual := compareFloat64(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("compare(%f, %f): expected %d, got %d", tc.a, tc.b, tc.expected, actual)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(0))
+	for _, n := range testLengths {
+		seeds := make([]uint64, n)
+		for i := range seeds {
+			seeds[i] = rng.Uint64()
+		}
+		ints := randInt64s(rng, n)
+		floats := randFloat64s(rng, n)
+
+		expectedInts := make([]uint64, n)
+		expectedFloats := make([]uint64, n)
+		for i := range seeds {
+			expectedInts[i] = hash64(uint64(ints[i]), seeds[i])
+			if floats[i] == 0 || math.IsNaN(floats[i]) {
+				expectedFloats[i] = c1 * (c0 ^ seeds[i])
+			} else {
+				expectedFloats[i] = hash64(math.Float64bits(floats[i]), seeds[i])
+			}
+		}
+
+		withAVX2(t, func(t testing.TB, avx2 bool) {
+			buckets := make([]uint64, n)
+			copy(buckets, seeds)
+			HashInt64(buckets, ints)
+			if !reflect.DeepEqual(expectedInts, buckets) {
+				t.Fatalf("n=%d avx2=%t: unexpected int64 hashes", n, avx2)
+			}
+			copy(buckets, seeds)
+			HashFloat64(buckets, floats)
+			if !reflect.DeepEqual(expectedFloats, buckets) {
+				t.Fatalf("n=%d avx2=%t: unexpected float64 hashes", n, avx2)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(0))
+	for _, n := range testLengths {
+		ints := randInt64s(rng, n)
+		floats := randFloat64s(rng, n)
+		initial := make([]bool, n)
+		for i := range initial {
+			initial[i] = rng.Intn(10) == 0
+		}
+
+		expectedInts := make([]bool, n)
+		expectedFloats := make([]bool, n)
+		copy(expectedInts, initial)
+		copy(expectedFloats, initial)
+		for i := 1; i < n; i++ {
+			expectedInts[i] = expectedInts[i] || ints[i] != ints[i-1]
+			expectedFloats[i] = expectedFloats[i] || compareFloat64(floats[i], floats[i-1]) != 0
+		}
+
+		withAVX2(t, func(t testing.TB, avx2 bool) {
+			outputCol := make([]bool, n)
+			copy(outputCol, initial)
+			DistinctInt64(ints, outputCol)
+			if !reflect.DeepEqual(expectedInts, outputCol) {
+				t.Fatalf("n=%d avx2=%t: unexpected int64 distinct output", n, avx2)
+			}
+			copy(outputCol, initial)
+			DistinctFloat64(floats, outputCol)
+			if !reflect.DeepEqual(expectedFloats, outputCol) {
+				t.Fatalf("n=%d avx2=%t: unexpected float64 distinct output", n, avx2)
+			}
+		})
+	}
+}
+
+// benchLength is the number of values that the benchmarks process at once.
+// It is the largest batch that the selection kernels support.
+const benchLength = maxSelLength
+
+func runBenchmark(b *testing.B, name string, bytes int64, f func()) {
+	for _, avx2 := range []bool{false, true} {
+		if avx2 && !hasAVX2 {
+			continue
+		}
+		b.Run(fmt.Sprintf("%s/avx2=%t", name, avx2), func(b *testing.B) {
+			defer func(old bool) { useAVX2 = old }(useAVX2)
+			useAVX2 = avx2
+			b.SetBytes(bytes)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				f()
+			}
+		})
+	}
+}
+
+func BenchmarkSelectInt64Const(b *testing.B) {
+	rng := rand.New(rand.NewSource(0))
+	col := make([]int64, benchLength)
+	for i := range col {
+		col[i] = rng.Int63n(100)
+	}
+	sel := make([]uint16, benchLength)
+	for _, op := range []CmpOp{EQ, LT} {
+		for _, c := range []int64{10, 50} {
+			runBenchmark(b, fmt.Sprintf("op=%s/selectivity=%d%%", op, c), 8*benchLength, func() {
+				SelectInt64Const(op, col, c, sel)
+			})
+		}
+	}
+}
+
+func BenchmarkSelectFloat64Const(b *testing.B) {
+	rng := rand.New(rand.NewSource(0))
+	col := make([]float64, benchLength)
+	for i := range col {
+		col[i] = rng.Float64() * 100
+	}
+	sel := make([]uint16, benchLength)
+	for _, op := range []CmpOp{EQ, LT} {
+		for _, c := range []float64{10, 50} {
+			runBenchmark(b, fmt.Sprintf("op=%s/selectivity=%d%%", op, int(c)), 8*benchLength, func() {
+				SelectFloat64Const(op, col, c, sel)
+			})
+		}
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	rng := rand.New(rand.NewSource(0))
+	buckets := make([]uint64, benchLength)
+	ints := make([]int64, benchLength)
+	floats := make([]float64, benchLength)
+	for i := range ints {
+		ints[i] = rng.Int63()
+		floats[i] = rng.NormFloat64()
+	}
+	runBenchmark(b, "int64", 8*benchLength, func() {
+		HashInt64(buckets, ints)
+	})
+	runBenchmark(b, "float64", 8*benchLength, func() {
+		HashFloat64(buckets, floats)
+	})
+}
+
+func BenchmarkDistinct(b *testing.B) {
+	rng := rand.New(rand.NewSource(0))
+	outputCol := make([]bool, benchLength)
+	ints := make([]int64, benchLength)
+	floats := make([]float64, benchLength)
+	// The columns are sorted and have runs of equal values, like the ones that
+	// the sorter partitions.
+	for i := 1; i < benchLength; i++ {
+		ints[i] = ints[i-1] + int64(rng.Intn(2))
+		floats[i] = float64(ints[i])
+	}
+	runBenchmark(b, "int64", 8*benchLength, func() {
+		DistinctInt64(ints, outputCol)
+	})
+	runBenchmark(b, "float64", 8*benchLength, func() {
+		DistinctFloat64(floats, outputCol)
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colsimd
+
+import (
+	"fmt"
+	"math"
+)
+
+// The constants below are the ones used by the hash functions of the
+// vectorized engine (which are themselves adapted from the Go runtime), and
+// they must be kept in sync with them.
+const (
+	m1 = 16877499708836156737
+	m2 = 2820277070424839065
+	m3 = 9497967016996688599
+	c0 = 33054211828000289
+	c1 = 23344194077549503
+)
+
+func rotl31(x uint64) uint64 {
+	return (x << 31) | (x >> (64 - 31))
+}
+
+// hash64 is the hash of an 8 byte value v combined with the seed h.
+func hash64(v uint64, h uint64) uint64 {
+	h += 8
+	h ^= v
+	h = rotl31(h*m1) * m2
+	h ^= h >> 29
+	h *= m3
+	h ^= h >> 32
+	return h
+}
+
+// cmpInt64 returns the result of comparing a with b using op.
+func cmpInt64(op CmpOp, a, b int64) bool {
+	switch op {
+	case EQ:
+		return a == b
+	case NE:
+		return a != b
+	case LT:
+		return a < b
+	case LE:
+		return a <= b
+	case GT:
+		return a > b
+	case GE:
+		return a >= b
+	default:
+		panic(fmt.Sprintf("colsimd: unknown comparison operator %d", op))
+	}
+}
+
+// compareFloat64 compares a and b, considering NaN to be equal to itself and
+// smaller than any other value.
+func compareFloat64(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	} else if a == b {
+		return 0
+	}
+	aNaN, bNaN := math.IsNaN(a), math.IsNaN(b)
+	if aNaN && bNaN {
+		return 0
+	} else if aNaN {
+		return -1
+	}
+	return 1
+}
+
+// cmpFloat64 returns the result of comparing a with b using op.
+func cmpFloat64(op CmpOp, a, b float64) bool {
+	cmp := compareFloat64(a, b)
+	switch op {
+	case EQ:
+		return cmp == 0
+	case NE:
+		return cmp != 0
+	case LT:
+		return cmp < 0
+	case LE:
+		return cmp <= 0
+	case GT:
+		return cmp > 0
+	case GE:
+		return cmp >= 0
+	default:
+		panic(fmt.Sprintf("colsimd: unknown comparison operator %d", op))
+	}
+}
+
+func selectInt64ConstGeneric(op CmpOp, col []int64, c int64, sel []uint16, start, idx int) int {
+	// The switch is outside of the loops so that each of them is as tight as
+	// possible.
+	switch op {
+	case EQ:
+		for i := start; i < len(col); i++ {
+			if col[i] == c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case NE:
+		for i := start; i < len(col); i++ {
+			if col[i] != c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case LT:
+		for i := start; i < len(col); i++ {
+			if col[i] < c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case LE:
+		for i := start; i < len(col); i++ {
+			if col[i] <= c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case GT:
+		for i := start; i < len(col); i++ {
+			if col[i] > c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case GE:
+		for i := start; i < len(col); i++ {
+			if col[i] >= c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	default:
+		panic(fmt.Sprintf("colsimd: unknown comparison operator %d", op))
+	}
+	return idx
+}
+
+func selectFloat64ConstGeneric(
+	op CmpOp, col []float64, c float64, sel []uint16, start, idx int,
+) int {
+	if math.IsNaN(c) {
+		for i := start; i < len(col); i++ {
+			if cmpFloat64(op, col[i], c) {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+		return idx
+	}
+	// Since c isn't NaN, NaN values in col only need to compare as smaller than
+	// c, which the negated comparisons below take care of (all comparisons with
+	// NaN are false in Go).
+	switch op {
+	case EQ:
+		for i := start; i < len(col); i++ {
+			if col[i] == c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case NE:
+		for i := start; i < len(col); i++ {
+			if !(col[i] == c) {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case LT:
+		for i := start; i < len(col); i++ {
+			if !(col[i] >= c) {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case LE:
+		for i := start; i < len(col); i++ {
+			if !(col[i] > c) {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case GT:
+		for i := start; i < len(col); i++ {
+			if col[i] > c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	case GE:
+		for i := start; i < len(col); i++ {
+			if col[i] >= c {
+				sel[idx] = uint16(i)
+				idx++
+			}
+		}
+	default:
+		panic(fmt.Sprintf("colsimd: unknown comparison operator %d", op))
+	}
+	return idx
+}
+
+func hashInt64Generic(buckets []uint64, keys []int64) {
+	for i, k := range keys {
+		buckets[i] = hash64(uint64(k), buckets[i])
+	}
+}
+
+func hashFloat64Generic(buckets []uint64, keys []float64) {
+	for i, k := range keys {
+		if k == 0 || math.IsNaN(k) {
+			buckets[i] = c1 * (c0 ^ buckets[i])
+		} else {
+			buckets[i] = hash64(math.Float64bits(k), buckets[i])
+		}
+	}
+}
+
+func distinctInt64Generic(col []int64, outputCol []bool, start int) {
+	for i := start; i < len(col); i++ {
+		outputCol[i] = outputCol[i] || col[i] != col[i-1]
+	}
+}
+
+func distinctFloat64Generic(col []float64, outputCol []bool, start int) {
+	for i := start; i < len(col); i++ {
+		outputCol[i] = outputCol[i] || compareFloat64(col[i], col[i-1]) != 0
+	}
+}
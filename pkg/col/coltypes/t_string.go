@@ -16,12 +16,14 @@ func _() {
 	_ = x[Int64-5]
 	_ = x[Float64-6]
 	_ = x[Timestamp-7]
-	_ = x[Unhandled-8]
+	_ = x[Interval-8]
+	_ = x[Datum-9]
+	_ = x[Unhandled-10]
 }
 
-const _T_name = "BoolBytesDecimalInt16Int32Int64Float64TimestampUnhandled"
+const _T_name = "BoolBytesDecimalInt16Int32Int64Float64TimestampIntervalDatumUnhandled"
 
-var _T_index = [...]uint8{0, 4, 9, 16, 21, 26, 31, 38, 47, 56}
+var _T_index = [...]uint8{0, 4, 9, 16, 21, 26, 31, 38, 47, 55, 60, 69}
 
 func (i T) String() string {
 	if i < 0 || i >= T(len(_T_index)-1) {
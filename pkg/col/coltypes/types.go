@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // T represents an exec physical type - a bytes representation of a particular
@@ -41,6 +42,13 @@ const (
 	Float64
 	// Timestamp is a column of type time.Time
 	Timestamp
+	// Interval is a column of type duration.Duration
+	Interval
+	// Datum is a column of tree.Datums (stored as interface{} in order to not
+	// depend on the tree package). It is used for the types that don't have a
+	// native columnar representation so that the columns of such types can
+	// flow through the vectorized engine without being operated on.
+	Datum
 
 	// Unhandled is a temporary value that represents an unhandled type.
 	// TODO(jordan): this should be replaced by a panic once all types are
@@ -66,6 +74,11 @@ var FloatTypes = []T{Float64}
 
 func init() {
 	for i := Bool; i < Unhandled; i++ {
+		if i == Datum {
+			// Datum is not included into AllTypes because the templated operators
+			// can't be generated for it.
+			continue
+		}
 		AllTypes = append(AllTypes, i)
 	}
 
@@ -78,6 +91,7 @@ func init() {
 	CompatibleTypes[Int64] = append(CompatibleTypes[Int64], NumberTypes...)
 	CompatibleTypes[Float64] = append(CompatibleTypes[Float64], NumberTypes...)
 	CompatibleTypes[Timestamp] = append(CompatibleTypes[Timestamp], Timestamp)
+	CompatibleTypes[Interval] = append(CompatibleTypes[Interval], Interval)
 }
 
 // FromGoType returns the type for a Go value, if applicable. Shouldn't be used at
@@ -102,6 +116,8 @@ func FromGoType(v interface{}) T {
 		return Decimal
 	case time.Time:
 		return Timestamp
+	case duration.Duration:
+		return Interval
 	default:
 		panic(fmt.Sprintf("type %T not supported yet", t))
 	}
@@ -126,6 +142,10 @@ func (t T) GoTypeName() string {
 		return "float64"
 	case Timestamp:
 		return "time.Time"
+	case Interval:
+		return "duration.Duration"
+	case Datum:
+		return "interface{}"
 	default:
 		panic(fmt.Sprintf("unhandled type %d", t))
 	}
@@ -57,6 +57,10 @@ type Batch struct {
 	// Once received, any error encountered sending the batch.
 	pErr *roachpb.Error
 
+	// approxMutationReqBytes tracks the approximate size of keys and values in
+	// mutations added to this batch via Put, CPut, InitPut, Del, etc.
+	approxMutationReqBytes int
+
 	// We use pre-allocated buffers to avoid dynamic allocations for small batches.
 	resultsBuf    [8]Result
 	rowsBuf       []KeyValue
@@ -64,6 +68,13 @@ type Batch struct {
 	rowsStaticIdx int
 }
 
+// ApproximateMutationBytes returns the approximate byte size of the mutations
+// added to this batch via Put, CPut, InitPut, Del, etc methods. Mutations added
+// via AddRawRequest are not tracked.
+func (b *Batch) ApproximateMutationBytes() int {
+	return b.approxMutationReqBytes
+}
+
 // RawResponse returns the BatchResponse which was the result of a successful
 // execution of the batch, and nil otherwise.
 func (b *Batch) RawResponse() *roachpb.BatchResponse {
@@ -373,6 +384,7 @@ func (b *Batch) put(key, value interface{}, inline bool) {
 	} else {
 		b.appendReqs(roachpb.NewPut(k, v))
 	}
+	b.approxMutationReqBytes += len(k) + len(v.RawBytes)
 	b.initResult(1, 1, notRaw, nil)
 }
 
@@ -456,6 +468,7 @@ func (b *Batch) cputInternal(key, value interface{}, expValue *roachpb.Value, al
 		ev.ClearChecksum()
 	}
 	b.appendReqs(roachpb.NewConditionalPut(k, v, ev, allowNotExist))
+	b.approxMutationReqBytes += len(k) + len(v.RawBytes) + len(ev.RawBytes)
 	b.initResult(1, 1, notRaw, nil)
 }
 
@@ -476,6 +489,7 @@ func (b *Batch) cputInternalDeprecated(key, value, expValue interface{}, allowNo
 		return
 	}
 	b.appendReqs(roachpb.NewConditionalPut(k, v, ev, allowNotExist))
+	b.approxMutationReqBytes += len(k) + len(v.RawBytes) + len(ev.RawBytes)
 	b.initResult(1, 1, notRaw, nil)
 }
 
@@ -499,6 +513,7 @@ func (b *Batch) InitPut(key, value interface{}, failOnTombstones bool) {
 		return
 	}
 	b.appendReqs(roachpb.NewInitPut(k, v, failOnTombstones))
+	b.approxMutationReqBytes += len(k) + len(v.RawBytes)
 	b.initResult(1, 1, notRaw, nil)
 }
 
@@ -576,6 +591,7 @@ func (b *Batch) Del(keys ...interface{}) {
 			return
 		}
 		reqs = append(reqs, roachpb.NewDelete(k))
+		b.approxMutationReqBytes += len(k)
 	}
 	b.appendReqs(reqs...)
 	b.initResult(len(reqs), len(reqs), notRaw, nil)
@@ -602,6 +618,31 @@ func (b *Batch) DelRange(s, e interface{}, returnKeys bool) {
 	b.initResult(1, 0, notRaw, nil)
 }
 
+// DelRangeSince deletes the rows between begin (inclusive) and end (exclusive)
+// whose latest version was written at or after startTime. The rows that were
+// last written before startTime are left untouched.
+//
+// A new result will be appended to the batch which will contain 0 rows and
+// Result.Err will indicate success or failure.
+//
+// key can be either a byte slice or a string.
+func (b *Batch) DelRangeSince(s, e interface{}, startTime hlc.Timestamp, returnKeys bool) {
+	begin, err := marshalKey(s)
+	if err != nil {
+		b.initResult(0, 0, notRaw, err)
+		return
+	}
+	end, err := marshalKey(e)
+	if err != nil {
+		b.initResult(0, 0, notRaw, err)
+		return
+	}
+	req := roachpb.NewDeleteRange(begin, end, returnKeys).(*roachpb.DeleteRangeRequest)
+	req.PredicateStartTime = startTime
+	b.appendReqs(req)
+	b.initResult(1, 0, notRaw, nil)
+}
+
 // adminMerge is only exported on DB. It is here for symmetry with the
 // other operations.
 func (b *Batch) adminMerge(key interface{}) {
@@ -212,6 +212,27 @@ func TestBatch(t *testing.T) {
 	checkResults(t, expected, b.Results)
 }
 
+func TestBatch_ApproximateMutationBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	b := &client.Batch{}
+	b.Get("aa")
+	b.Scan("a", "b")
+	if n := b.ApproximateMutationBytes(); n != 0 {
+		t.Fatalf("expected no mutation bytes for reads, got %d", n)
+	}
+
+	// The values are prefixed with a 5 byte header (checksum and tag).
+	b.Put("bb", "2")
+	b.CPut("cc", "33", strToValue("4"))
+	b.InitPut("d", "", false /* failOnTombstones */)
+	b.Del("eee", "f")
+	expected := (2 + 6) + (2 + 7 + 6) + (1 + 5) + (3 + 1)
+	if n := b.ApproximateMutationBytes(); n != expected {
+		t.Fatalf("expected %d mutation bytes, got %d", expected, n)
+	}
+}
+
 func TestDB_Scan(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s, db := setup(t)
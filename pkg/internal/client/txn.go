@@ -632,6 +632,35 @@ func (txn *Txn) UpdateDeadlineMaybe(ctx context.Context, deadline hlc.Timestamp)
 	return false
 }
 
+// ExtendDeadline moves the transaction's deadline forward to the passed value
+// if the transaction has a deadline and it is lower than the passed value.
+//
+// Unlike UpdateDeadlineMaybe, this can relax the constraint put on the
+// transaction by whoever set the current deadline; it's the caller's
+// responsibility to ensure that the current deadline is no longer needed.
+func (txn *Txn) ExtendDeadline(ctx context.Context, deadline hlc.Timestamp) bool {
+	if txn.typ != RootTxn {
+		panic(errors.WithContextTags(errors.AssertionFailedf("ExtendDeadline() called on leaf txn"), ctx))
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.deadline == nil || !txn.mu.deadline.Less(deadline) {
+		return false
+	}
+	txn.mu.deadline = new(hlc.Timestamp)
+	*txn.mu.deadline = deadline
+	return true
+}
+
+// Deadline returns the transaction's deadline, if any.
+func (txn *Txn) Deadline() (hlc.Timestamp, bool) {
+	if d := txn.deadline(); d != nil {
+		return *d, true
+	}
+	return hlc.Timestamp{}, false
+}
+
 // resetDeadlineLocked resets the deadline.
 func (txn *Txn) resetDeadlineLocked() {
 	txn.mu.deadline = nil
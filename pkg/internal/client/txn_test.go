@@ -489,6 +489,48 @@ func TestUpdateDeadlineMaybe(t *testing.T) {
 	}
 }
 
+func TestExtendDeadline(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	mc := hlc.NewManualClock(1)
+	clock := hlc.NewClock(mc.UnixNano, time.Nanosecond)
+	db := NewDB(
+		testutils.MakeAmbientCtx(),
+		MakeMockTxnSenderFactory(
+			func(context.Context, *roachpb.Transaction, roachpb.BatchRequest,
+			) (*roachpb.BatchResponse, *roachpb.Error) {
+				return nil, nil
+			}),
+		clock)
+	txn := NewTxn(ctx, db, 0 /* gatewayNodeID */)
+
+	// A transaction without a deadline doesn't get one.
+	if txn.ExtendDeadline(ctx, hlc.Timestamp{WallTime: 10}) {
+		t.Errorf("expected no update, but update happened")
+	}
+	if d, ok := txn.Deadline(); ok {
+		t.Errorf("unexpected deadline: %s", d)
+	}
+
+	deadline := hlc.Timestamp{WallTime: 10, Logical: 1}
+	txn.UpdateDeadlineMaybe(ctx, deadline)
+	if txn.ExtendDeadline(ctx, hlc.Timestamp{WallTime: 9, Logical: 1}) {
+		t.Errorf("expected no update, but update happened")
+	}
+	if d, _ := txn.Deadline(); d != deadline {
+		t.Errorf("unexpected deadline: %s", d)
+	}
+
+	futureDeadline := hlc.Timestamp{WallTime: 11, Logical: 1}
+	if !txn.ExtendDeadline(ctx, futureDeadline) {
+		t.Errorf("expected update, but it didn't happen")
+	}
+	if d, _ := txn.Deadline(); d != futureDeadline {
+		t.Errorf("unexpected deadline: %s", d)
+	}
+}
+
 // Test that, if SetSystemConfigTrigger() fails, the systemConfigTrigger has not
 // been set.
 func TestAnchoringErrorNoTrigger(t *testing.T) {
@@ -363,6 +363,8 @@ const (
 
 	ProtectedTimestampsMetaTableID    = 31
 	ProtectedTimestampsRecordsTableID = 32
+	SessionTracesTableID              = 33
+	PlanChangesTableID                = 34
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
@@ -115,6 +115,26 @@ var CanSendToFollower = func(
 	return false
 }
 
+// WithNearestReplicaRouting returns a context which instructs the DistSender
+// to send the read-only batches that address a single range to the nearest
+// replica of that range instead of its leaseholder. A follower replica serves
+// such a batch if the batch's timestamp is below its closed timestamp, and
+// redirects the DistSender to the leaseholder otherwise. It is used for bounded
+// staleness reads, which pick a timestamp that is likely to be servable by the
+// nearest replica.
+func WithNearestReplicaRouting(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nearestReplicaRoutingKey{}, true)
+}
+
+type nearestReplicaRoutingKey struct{}
+
+// nearestReplicaRouting returns whether the batches sent with the given context
+// should be routed to the nearest replica. See WithNearestReplicaRouting.
+func nearestReplicaRouting(ctx context.Context) bool {
+	enabled, _ := ctx.Value(nearestReplicaRoutingKey{}).(bool)
+	return enabled
+}
+
 var rangeDescriptorCacheSize = settings.RegisterIntSetting(
 	"kv.range_descriptor_cache.size",
 	"maximum number of entries in the range descriptor and leaseholder caches",
@@ -505,6 +525,9 @@ func (ds *DistSender) sendSingleRange(
 	var cachedLeaseHolder roachpb.ReplicaDescriptor
 	canSendToFollower := ds.clusterID != nil &&
 		CanSendToFollower(ds.clusterID.Get(), ds.st, ba)
+	if !canSendToFollower && nearestReplicaRouting(ctx) {
+		canSendToFollower = ba.IsReadOnly() && ba.IsAllTransactional()
+	}
 	if !canSendToFollower && ba.RequiresLeaseHolder() {
 		if storeID, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
 			if i := replicas.FindReplica(storeID); i >= 0 {
@@ -1091,6 +1114,12 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		return resp.reply, resp.pErr
 	}
 
+	// Nearest replica routing only applies to the batches that address a single
+	// range, so the parts of this batch are routed as usual.
+	if nearestReplicaRouting(ctx) {
+		ctx = context.WithValue(ctx, nearestReplicaRoutingKey{}, false)
+	}
+
 	// The batch spans ranges (according to our cached range descriptors).
 	// Verify that this is ok.
 	// TODO(tschottdorf): we should have a mechanism for discovering range
@@ -1194,6 +1223,25 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		canParallelize = canParallelize && !isExpensive
 	}
 
+	// If the batch contains a parallel commit, the partial batch for the range
+	// that holds the transaction record (i.e. the one containing the EndTxn
+	// request) is held back and sent synchronously once all other partial
+	// batches have been dispatched, instead of whichever partial batch happens
+	// to be the last one. This way the writes whose success the parallel commit
+	// depends on are all in flight before the transaction record is staged, and
+	// the latency-critical EndTxn request is never the one that queues behind
+	// throttled partial batches.
+	var etKey roachpb.RKey
+	var heldBack *heldBackPartialBatch
+	if withCommit && canParallelize && !ds.disableParallelBatches {
+		if etArg, ok := ba.GetArg(roachpb.EndTxn); ok && etArg.(*roachpb.EndTxnRequest).IsParallelCommit() {
+			var err error
+			if etKey, err = keys.Addr(etArg.Header().Key); err != nil {
+				return nil, roachpb.NewError(err)
+			}
+		}
+	}
+
 	for ; ri.Valid(); ri.Seek(ctx, seekKey, scanDir) {
 		responseCh := make(chan response, 1)
 		responseChs = append(responseChs, responseCh)
@@ -1228,8 +1276,22 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		lastRange := !ri.NeedAnother(rs)
 		// Send the next partial batch to the first range in the "rs" span.
 		// If we can reserve one of the limited goroutines available for parallel
-		// batch RPCs, send asynchronously.
-		if canParallelize && !lastRange && !ds.disableParallelBatches &&
+		// batch RPCs, send asynchronously. If a partial batch has been held back,
+		// the last one can be sent asynchronously as well since the held back
+		// partial batch is sent synchronously.
+		if etKey != nil && heldBack == nil && !lastRange && ri.Desc().ContainsKey(etKey) {
+			// Hold back the partial batch with the EndTxn request. Its response
+			// channel is only added to responseChs once it is actually sent so
+			// that we don't wait on it if we exit early because of an error.
+			responseChs = responseChs[:len(responseChs)-1]
+			heldBack = &heldBackPartialBatch{
+				rs:         rs,
+				desc:       ri.Desc(),
+				evictToken: ri.Token(),
+				batchIdx:   batchIdx,
+				responseCh: responseCh,
+			}
+		} else if canParallelize && (!lastRange || heldBack != nil) && !ds.disableParallelBatches &&
 			ds.sendPartialBatchAsync(ctx, ba, rs, ri.Desc(), ri.Token(), withCommit, batchIdx, responseCh) {
 			// Sent the batch asynchronously.
 		} else {
@@ -1305,6 +1367,13 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		// a sub-span of the original, causing next() and prev() methods
 		// to potentially return values which invert the span.
 		if lastRange || !nextRS.Key.Less(nextRS.EndKey) {
+			if heldBack != nil {
+				responseChs = append(responseChs, heldBack.responseCh)
+				heldBack.responseCh <- ds.sendPartialBatch(
+					ctx, ba, heldBack.rs, heldBack.desc, heldBack.evictToken, withCommit,
+					heldBack.batchIdx, true, /* needsTruncate */
+				)
+			}
 			return
 		}
 		batchIdx++
@@ -1318,6 +1387,16 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 	return
 }
 
+// heldBackPartialBatch is a partial batch whose sending has been postponed
+// until all other partial batches of a batch have been dispatched.
+type heldBackPartialBatch struct {
+	rs         roachpb.RSpan
+	desc       *roachpb.RangeDescriptor
+	evictToken *EvictionToken
+	batchIdx   int
+	responseCh chan response
+}
+
 // sendPartialBatchAsync sends the partial batch asynchronously if
 // there aren't currently more than the allowed number of concurrent
 // async requests outstanding. Returns whether the partial batch was
@@ -2237,6 +2237,123 @@ func TestMultiRangeWithEndTxn(t *testing.T) {
 	}
 }
 
+// TestMultiRangeParallelCommitSendsTxnRecordRangeLast verifies that the partial
+// batch of a parallel commit that contains the EndTxn request is sent after
+// the partial batches to the other ranges have been dispatched. All partial
+// batches are throttled so that the order in which they are sent is the order
+// in which they are dispatched.
+func TestMultiRangeParallelCommitSendsTxnRecordRangeLast(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	rpcContext := rpc.NewInsecureTestingContext(clock, stopper)
+	g := makeGossip(t, stopper, rpcContext)
+	testCases := []struct {
+		put1, put2, et roachpb.Key
+		exp            [][]roachpb.Method
+	}{
+		{
+			// The EndTxn goes to the first range, so it is held back until the
+			// writes to the second range have been sent.
+			put1: roachpb.Key("b1"),
+			put2: roachpb.Key("b2"),
+			et:   roachpb.Key("a1"),
+			exp:  [][]roachpb.Method{{roachpb.Put, roachpb.Put}, {roachpb.EndTxn}},
+		},
+		{
+			// Writes to the range with the EndTxn are sent along with it.
+			put1: roachpb.Key("a1"),
+			put2: roachpb.Key("b1"),
+			et:   roachpb.Key("a1"),
+			exp:  [][]roachpb.Method{{roachpb.Put}, {roachpb.Put, roachpb.EndTxn}},
+		},
+		{
+			// The EndTxn already goes to the last range.
+			put1: roachpb.Key("a1"),
+			put2: roachpb.Key("a2"),
+			et:   roachpb.Key("b"),
+			exp:  [][]roachpb.Method{{roachpb.Put, roachpb.Put}, {roachpb.EndTxn}},
+		},
+	}
+
+	if err := g.SetNodeDescriptor(newNodeDesc(1)); err != nil {
+		t.Fatal(err)
+	}
+	nd := &roachpb.NodeDescriptor{
+		NodeID:  roachpb.NodeID(1),
+		Address: util.MakeUnresolvedAddr(testAddress.Network(), testAddress.String()),
+	}
+	if err := g.AddInfoProto(gossip.MakeNodeIDKey(roachpb.NodeID(1)), nd, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	descriptor1 := roachpb.RangeDescriptor{
+		RangeID:          2,
+		StartKey:         testMetaEndKey,
+		EndKey:           roachpb.RKey("b"),
+		InternalReplicas: []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1}},
+	}
+	descriptor2 := roachpb.RangeDescriptor{
+		RangeID:          3,
+		StartKey:         roachpb.RKey("b"),
+		EndKey:           roachpb.RKeyMax,
+		InternalReplicas: []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1}},
+	}
+	descDB := mockRangeDescriptorDBForDescs(testMetaRangeDescriptor, descriptor1, descriptor2)
+
+	for i, test := range testCases {
+		var act [][]roachpb.Method
+		var testFn simpleSendFn = func(
+			_ context.Context,
+			_ SendOptions,
+			_ ReplicaSlice,
+			ba roachpb.BatchRequest,
+		) (*roachpb.BatchResponse, error) {
+			var cur []roachpb.Method
+			for _, union := range ba.Requests {
+				cur = append(cur, union.GetInner().Method())
+			}
+			act = append(act, cur)
+			return ba.CreateReply(), nil
+		}
+
+		cfg := DistSenderConfig{
+			AmbientCtx: log.AmbientContext{Tracer: tracing.NewTracer()},
+			Clock:      clock,
+			RPCContext: rpcContext,
+			TestingKnobs: ClientTestingKnobs{
+				TransportFactory: adaptSimpleTransport(testFn),
+			},
+			RangeDescriptorDB: descDB,
+		}
+		ds := NewDistSender(cfg, g)
+		// Throttle all asynchronous partial batches so that they are sent
+		// synchronously, in the order in which they are dispatched.
+		ds.asyncSenderSem = make(chan struct{})
+
+		var ba roachpb.BatchRequest
+		ba.Txn = &roachpb.Transaction{Name: "test"}
+		ba.Add(roachpb.NewPut(test.put1, roachpb.MakeValueFromString("val1")))
+		ba.Add(roachpb.NewPut(test.put2, roachpb.MakeValueFromString("val2")))
+		ba.Add(&roachpb.EndTxnRequest{
+			RequestHeader: roachpb.RequestHeader{Key: test.et},
+			Commit:        true,
+			InFlightWrites: []roachpb.SequencedWrite{
+				{Key: test.put1, Sequence: 1}, {Key: test.put2, Sequence: 2},
+			},
+		})
+
+		if _, pErr := ds.Send(context.Background(), ba); pErr != nil {
+			t.Fatal(pErr)
+		}
+		if !reflect.DeepEqual(test.exp, act) {
+			t.Fatalf("test %d: expected %v, got %v", i, test.exp, act)
+		}
+	}
+}
+
 // TestParallelCommitSplitFromQueryIntents verifies that a parallel-committing
 // batch is split into sub-batches - one containing all pre-commit QueryIntent
 // requests and one containing everything else.
@@ -3036,6 +3153,79 @@ func TestCanSendToFollower(t *testing.T) {
 	}
 }
 
+// TestNearestReplicaRouting tests that the DistSender sends the read-only
+// batches to the nearest replica when the context requests it.
+func TestNearestReplicaRouting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	rpcContext := rpc.NewInsecureTestingContext(clock, stopper)
+	g := makeGossip(t, stopper, rpcContext)
+	for _, n := range testUserRangeDescriptor3Replicas.InternalReplicas {
+		if err := g.AddInfoProto(
+			gossip.MakeNodeIDKey(n.NodeID),
+			newNodeDesc(n.NodeID),
+			gossip.NodeDescriptorTTL,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sentTo ReplicaInfo
+	var testFn simpleSendFn = func(
+		_ context.Context,
+		_ SendOptions,
+		r ReplicaSlice,
+		args roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, error) {
+		sentTo = r[0]
+		reply := &roachpb.BatchResponse{}
+		reply.Error = roachpb.NewErrorf("boom")
+		return reply, nil
+	}
+	cfg := DistSenderConfig{
+		AmbientCtx: log.AmbientContext{Tracer: tracing.NewTracer()},
+		Clock:      clock,
+		RPCContext: rpcContext,
+		TestingKnobs: ClientTestingKnobs{
+			TransportFactory: adaptSimpleTransport(testFn),
+		},
+		RangeDescriptorDB: threeReplicaMockRangeDescriptorDB,
+		NodeDialer:        nodedialer.New(rpcContext, gossip.AddressResolver(g)),
+		RPCRetryOptions: &retry.Options{
+			InitialBackoff: time.Microsecond,
+			MaxBackoff:     time.Microsecond,
+		},
+	}
+	for i, c := range []struct {
+		nearest      bool
+		msg          roachpb.Request
+		expectedNode roachpb.NodeID
+	}{
+		{true, roachpb.NewPut(roachpb.Key("a"), roachpb.Value{}), 2},
+		{true, roachpb.NewGet(roachpb.Key("a")), 1},
+		{false, roachpb.NewGet(roachpb.Key("a")), 2},
+	} {
+		sentTo = ReplicaInfo{}
+		ds := NewDistSender(cfg, g)
+		ds.clusterID = &base.ClusterIDContainer{}
+		// set 2 to be the leaseholder
+		ds.LeaseHolderCache().Update(context.TODO(), 2, 2)
+		ctx := context.Background()
+		if c.nearest {
+			ctx = WithNearestReplicaRouting(ctx)
+		}
+		header := roachpb.Header{Txn: &roachpb.Transaction{}}
+		if _, pErr := client.SendWrappedWith(ctx, ds, header, c.msg); !testutils.IsPError(pErr, "boom") {
+			t.Fatalf("%d: unexpected error: %v", i, pErr)
+		}
+		if sentTo.NodeID != c.expectedNode {
+			t.Fatalf("%d: unexpected replica: %v != %v", i, sentTo.NodeID, c.expectedNode)
+		}
+	}
+}
+
 // TestEvictMetaRange tests that a query on a stale meta2 range should evict it
 // from the cache.
 func TestEvictMetaRange(t *testing.T) {
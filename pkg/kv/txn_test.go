@@ -119,6 +119,48 @@ func BenchmarkSingleRoundtripWithLatency(b *testing.B) {
 	}
 }
 
+// BenchmarkMultiRangeCommitWithLatency runs a number of transactions writing
+// to a key on each of several ranges and committing in the same batch, which
+// makes them perform a parallel commit spanning all of these ranges. Latency
+// is simulated by pausing before each RPC sent.
+func BenchmarkMultiRangeCommitWithLatency(b *testing.B) {
+	for _, latency := range []time.Duration{0, 10 * time.Millisecond} {
+		for _, numRanges := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("latency=%s/ranges=%d", latency, numRanges), func(b *testing.B) {
+				var s localtestcluster.LocalTestCluster
+				s.Latency = latency
+				s.Start(b, testutils.NewNodeTestBaseContext(), InitFactoryForLocalTestCluster)
+				defer s.Stop()
+				defer b.StopTimer()
+				rangeKeys := make([]roachpb.Key, numRanges)
+				for i := range rangeKeys {
+					rangeKeys[i] = roachpb.Key(fmt.Sprintf("key-%d", i))
+					if i == 0 {
+						continue
+					}
+					if err := s.DB.AdminSplit(
+						context.TODO(), rangeKeys[i], rangeKeys[i], hlc.MaxTimestamp, /* expirationTime */
+					); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if err := s.DB.Txn(context.TODO(), func(ctx context.Context, txn *client.Txn) error {
+						b := txn.NewBatch()
+						for _, key := range rangeKeys {
+							b.Put(key, fmt.Sprintf("value-%d", i))
+						}
+						return txn.CommitInBatch(ctx, b)
+					}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
 // TestLostUpdate verifies that transactions are not susceptible to the
 // lost update anomaly.
 //
@@ -35,6 +35,14 @@ type ClientVisibleAmbiguousError interface {
 	ClientVisibleAmbiguousError()
 }
 
+// ClientVisibleUnavailableError is to be implemented by errors visible
+// by layers above and that indicate that the request could not be
+// served by the node it was sent to, without having been evaluated.
+// The client can retry it, possibly against another node.
+type ClientVisibleUnavailableError interface {
+	ClientVisibleUnavailableError()
+}
+
 func (e *UnhandledRetryableError) Error() string {
 	return e.PErr.Message
 }
@@ -273,7 +281,12 @@ func (*NodeUnavailableError) message(_ *Error) string {
 	return "node unavailable; try another peer"
 }
 
+// ClientVisibleUnavailableError implements the
+// ClientVisibleUnavailableError interface.
+func (*NodeUnavailableError) ClientVisibleUnavailableError() {}
+
 var _ ErrorDetailInterface = &NodeUnavailableError{}
+var _ ClientVisibleUnavailableError = &NodeUnavailableError{}
 
 func (e *NotLeaseHolderError) Error() string {
 	return e.message(nil)
@@ -55,6 +55,13 @@ func ReplicaTypeLearner() *ReplicaType {
 	return &t
 }
 
+// ReplicaTypeNonVoter returns a NON_VOTER pointer suitable for use in
+// a nullable proto field.
+func ReplicaTypeNonVoter() *ReplicaType {
+	t := NON_VOTER
+	return &t
+}
+
 // ReplicaDescriptors is a set of replicas, usually the nodes/stores on which
 // replicas of a range are stored.
 type ReplicaDescriptors struct {
@@ -89,8 +96,8 @@ func (d ReplicaDescriptors) String() string {
 	return buf.String()
 }
 
-// All returns every replica in the set, including both voter replicas and
-// learner replicas. Voter replicas are ordered first in the returned slice.
+// All returns every replica in the set, including voter, non-voter and learner
+// replicas. Voter replicas are ordered first in the returned slice.
 func (d ReplicaDescriptors) All() []ReplicaDescriptor {
 	return d.wrapped
 }
@@ -108,12 +115,16 @@ func predLearner(rDesc ReplicaDescriptor) bool {
 	return rDesc.GetType() == LEARNER
 }
 
+func predNonVoter(rDesc ReplicaDescriptor) bool {
+	return rDesc.GetType() == NON_VOTER
+}
+
 // Voters returns the current and future voter replicas in the set. This means
 // that during an atomic replication change, only the replicas that will be
 // voters once the change completes will be returned; "outgoing" voters will not
 // be returned even though they do in the current state retain their voting
 // rights. When no atomic membership change is ongoing, this is simply the set
-// of all non-learners.
+// of all replicas that are neither learners nor non-voters.
 //
 // This may allocate, but it also may return the underlying slice as a
 // performance optimization, so it's not safe to modify the returned value.
@@ -215,6 +226,24 @@ func (d ReplicaDescriptors) Learners() []ReplicaDescriptor {
 	return d.Filter(predLearner)
 }
 
+// NonVoters returns the non-voting replicas in the set. This may allocate, but
+// it also may return the underlying slice as a performance optimization, so
+// it's not safe to modify the returned value.
+//
+// Non-voters are raft learners as far as raft is concerned: they receive the
+// log and apply committed entries, but don't vote and thus don't affect the
+// quorum (or the latency of reaching it). Unlike the learners returned by
+// Learners, they are long-lived and are never promoted to voters. This makes
+// them suitable for placing replicas in remote regions, for example to serve
+// follower reads there, without increasing the write latency of the range.
+//
+// Like learners, non-voters can't become raft leaders and thus can't hold the
+// lease. They are not considered when calculating quorum size and are ignored
+// by the replicate queue when up- or down-replicating voters.
+func (d ReplicaDescriptors) NonVoters() []ReplicaDescriptor {
+	return d.Filter(predNonVoter)
+}
+
 // Filter returns only the replica descriptors for which the supplied method
 // returns true. The memory returned may be shared with the receiver.
 func (d ReplicaDescriptors) Filter(pred func(rDesc ReplicaDescriptor) bool) []ReplicaDescriptor {
@@ -287,7 +316,7 @@ func (d ReplicaDescriptors) InAtomicReplicationChange() bool {
 		switch rDesc.GetType() {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING:
 			return true
-		case VOTER_FULL, LEARNER:
+		case VOTER_FULL, LEARNER, NON_VOTER:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.GetType()))
 		}
@@ -318,7 +347,7 @@ func (d ReplicaDescriptors) ConfState() raftpb.ConfState {
 		case VOTER_DEMOTING:
 			cs.VotersOutgoing = append(cs.VotersOutgoing, id)
 			cs.LearnersNext = append(cs.LearnersNext, id)
-		case LEARNER:
+		case LEARNER, NON_VOTER:
 			cs.Learners = append(cs.Learners, id)
 		default:
 			panic(fmt.Sprintf("unknown ReplicaType %d", typ))
@@ -334,7 +363,7 @@ func (d ReplicaDescriptors) CanMakeProgress(liveFunc func(descriptor ReplicaDesc
 	voters := d.Voters()
 	var c int
 	// Take the fast path when there are only "current and future" voters, i.e.
-	// no learners and no voters of type VOTER_OUTGOING. The config may be joint,
+	// no learners, no non-voters and no voters of type VOTER_OUTGOING. The config may be joint,
 	// but the outgoing conf is subsumed by the incoming one.
 	if n := len(d.wrapped); len(voters) == n {
 		for _, rDesc := range voters {
@@ -34,6 +34,7 @@ var vi = ReplicaTypeVoterIncoming()
 var vo = ReplicaTypeVoterOutgoing()
 var vd = ReplicaTypeVoterDemoting()
 var l = ReplicaTypeLearner()
+var nv = ReplicaTypeNonVoter()
 
 func TestVotersLearnersAll(t *testing.T) {
 
@@ -49,6 +50,8 @@ func TestVotersLearnersAll(t *testing.T) {
 		{rd(vi, 1)},
 		{rd(vo, 1)},
 		{rd(l, 1), rd(vo, 2), rd(vi, 3), rd(vi, 4)},
+		{rd(nv, 1)},
+		{rd(v, 1), rd(nv, 2), rd(l, 3)},
 	}
 	for _, test := range tests {
 		t.Run("", func(t *testing.T) {
@@ -67,10 +70,14 @@ func TestVotersLearnersAll(t *testing.T) {
 				seen[learner] = struct{}{}
 				assert.Equal(t, LEARNER, learner.GetType())
 			}
+			for _, nonVoter := range r.NonVoters() {
+				seen[nonVoter] = struct{}{}
+				assert.Equal(t, NON_VOTER, nonVoter.GetType())
+			}
 
 			all := r.All()
-			// Make sure that VOTER_OUTGOING is the only type that is skipped both
-			// by Learners() and Voters()
+			// Make sure that VOTER_OUTGOING is the only type that is skipped by
+			// Learners(), NonVoters() and Voters()
 			for _, rd := range all {
 				typ := rd.GetType()
 				if _, seen := seen[rd]; !seen {
@@ -189,6 +196,15 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(vo, 1), rd(vd, 2), rd(vi, 3), rd(vi, 4), rd(l, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Non-voters are raft learners.
+		{
+			[]ReplicaDescriptor{rd(v, 1), rd(nv, 2), rd(l, 3)},
+			"Voters:[1] VotersOutgoing:[] Learners:[2 3] LearnersNext:[] AutoLeave:false",
+		},
+		{
+			[]ReplicaDescriptor{rd(v, 1), rd(vi, 2), rd(nv, 3)},
+			"Voters:[1 2] VotersOutgoing:[1] Learners:[3] LearnersNext:[] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
@@ -285,6 +301,24 @@ func TestReplicaDescriptorsCanMakeProgress(t *testing.T) {
 			{false, rd(v, 4)},
 			{false, rd(l, 4)},
 		}, true},
+		// Two out of three voters alive; the live non-voters don't make up for
+		// the dead voter, nor do the dead ones prevent progress.
+		{[]descWithLiveness{
+			{true, rd(v, 1)},
+			{true, rd(v, 2)},
+			{false, rd(v, 3)},
+			{false, rd(nv, 4)},
+			{false, rd(nv, 5)},
+		}, true},
+		// One out of three voters alive, and many live non-voters.
+		{[]descWithLiveness{
+			{true, rd(v, 1)},
+			{false, rd(v, 2)},
+			{false, rd(v, 3)},
+			{true, rd(nv, 4)},
+			{true, rd(nv, 5)},
+			{true, rd(nv, 6)},
+		}, false},
 	} {
 		t.Run("", func(t *testing.T) {
 			rds := make([]ReplicaDescriptor, 0, len(test.rds))
@@ -0,0 +1,112 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// queryProgressSnapshot is a snapshot of the progress of a query, as served
+// by handleQueryProgress.
+type queryProgressSnapshot struct {
+	QueryID string    `json:"queryId"`
+	Time    time.Time `json:"time"`
+	// Diagram is the diagram of the distributed plan of the query, annotated
+	// with the number of rows produced so far by each processor. It is null if
+	// the query isn't running a distributed plan.
+	Diagram json.RawMessage `json:"diagram"`
+	// URL is a link that renders the diagram.
+	URL string `json:"url,omitempty"`
+}
+
+// handleQueryProgress streams the progress of a query running on the local
+// node as newline-delimited JSON snapshots, one per interval, until the query
+// finishes or the client disconnects. The query is specified by the query_id
+// parameter; the interval parameter overrides the interval between snapshots,
+// which defaults to sql.distsql.progress_report_interval.
+func (s *statusServer) handleQueryProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := s.AnnotateCtx(r.Context())
+
+	queryIDStr := r.URL.Query().Get("query_id")
+	queryID, err := sql.StringToClusterWideID(queryIDStr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid query ID %q: %v", queryIDStr, err), http.StatusBadRequest)
+		return
+	}
+	// The progress of a query is only known to its gateway.
+	if nodeID := roachpb.NodeID(queryID.GetNodeID()); nodeID != s.gossip.NodeID.Get() {
+		http.Error(w, fmt.Sprintf("query %s runs on node %d", queryIDStr, nodeID), http.StatusBadRequest)
+		return
+	}
+	interval := execinfra.SettingProgressReportInterval.Get(&s.st.SV)
+	if str := r.URL.Query().Get("interval"); str != "" {
+		if interval, err = time.ParseDuration(str); err != nil {
+			http.Error(w, fmt.Sprintf("invalid interval %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	username := security.RootUser
+	if u, ok := r.Context().Value(webSessionUserKey{}).(string); ok {
+		username = u
+	}
+
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		diagram, u, ok, err := s.sessionRegistry.QueryProgress(queryIDStr, username)
+		if err != nil {
+			if first {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			}
+			// Otherwise, the query finished.
+			return
+		}
+		if first {
+			w.Header().Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+		}
+		snapshot := queryProgressSnapshot{QueryID: queryIDStr, Time: timeutil.Now()}
+		if ok {
+			snapshot.Diagram = json.RawMessage(diagram)
+			snapshot.URL = u.String()
+		}
+		if err := enc.Encode(snapshot); err != nil {
+			log.Error(ctx, err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		case <-s.stopper.ShouldQuiesce():
+			return
+		}
+	}
+}
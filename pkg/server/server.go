@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/blobs"
 	"github.com/cockroachdb/cockroach/pkg/blobs/blobspb"
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -52,6 +53,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/flight"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -87,6 +89,7 @@ import (
 	"github.com/cockroachdb/logtags"
 	raven "github.com/getsentry/raven-go"
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/marusama/semaphore"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -414,6 +417,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		return nil, errors.Wrap(err, "could not create temp storage")
 	}
 	s.stopper.AddCloser(tempEngine)
+	tempFS := engine.NewTempFS(s.cfg.TempStorageConfig)
 	// Remove temporary directory linked to tempEngine after closing
 	// tempEngine.
 	s.stopper.AddCloser(stop.CloserFn(func() {
@@ -599,8 +603,13 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ClusterID:      &s.rpcContext.ClusterID,
 		ClusterName:    s.cfg.ClusterName,
 
-		TempStorage: tempEngine,
-		DiskMonitor: s.cfg.TempStorageConfig.Mon,
+		TempStorage:     tempEngine,
+		TempFS:          tempFS,
+		TempStoragePath: s.cfg.TempStorageConfig.Path,
+		DiskMonitor:     s.cfg.TempStorageConfig.Mon,
+		VecFDSemaphore: semaphore.New(envutil.EnvOrDefaultInt(
+			"COCKROACH_VEC_MAX_OPEN_FDS", colcontainer.DefaultMaxOpenFDs,
+		)),
 
 		ParentMemoryMonitor: &rootSQLMemoryMonitor,
 		BulkAdder: func(
@@ -612,7 +621,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			return bulk.MakeBulkAdder(ctx, db, s.distSender.RangeDescriptorCache(), s.st, ts, opts, bulkMon)
 		},
 
-		Metrics: &distSQLMetrics,
+		Metrics:   &distSQLMetrics,
+		ExprCache: execinfra.NewExprCache(st),
 
 		JobRegistry:  s.jobRegistry,
 		Gossip:       s.gossip,
@@ -628,6 +638,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 
 	s.distSQLServer = distsql.NewServer(ctx, distSQLCfg)
 	execinfrapb.RegisterDistSQLServer(s.grpc.Server, s.distSQLServer)
+	flight.RegisterFlightServiceServer(s.grpc.Server, flight.NewServer(&execCfg))
 
 	s.admin = newAdminServer(s)
 	s.status = newStatusServer(
@@ -704,6 +715,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
 		RoleMemberCache:         &sql.MembershipCache{},
+		MaxCommandSize:          storage.MaxCommandSize,
 		TestingKnobs:            sqlExecutorTestingKnobs,
 
 		DistSQLPlanner: sql.NewDistSQLPlanner(
@@ -778,6 +790,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		s.cfg.HistogramWindowInterval(),
 		&execCfg,
 	)
+	// The stores report the contention they observe to the SQL server, which
+	// attributes it to the transactions running on this node.
+	s.node.storeCfg.ContentionListener = s.pgServer.SQLServer
 
 	// Now that we have a pgwire.Server (which has a sql.Server), we can close a
 	// circular dependency between the rowexec.Server and sql.Server and set
@@ -1591,6 +1606,10 @@ func (s *Server) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Start the background thread for periodically deleting the expired rows
+	// of the tables with a TTL expiration column.
+	sql.StartRowLevelTTLScheduler(ctx, s.stopper, s.execCfg)
+
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
 	// We have to do this after actually starting up the server to be able to
@@ -1664,6 +1683,16 @@ func (s *Server) Start(ctx context.Context) error {
 	s.mux.Handle(loginPath, gwMux)
 	s.mux.Handle(logoutPath, authHandler)
 	s.mux.Handle(statusVars, http.HandlerFunc(s.status.handleVars))
+	var txnsHandler http.Handler = http.HandlerFunc(s.status.handleTransactions)
+	if s.cfg.RequireWebSession() {
+		txnsHandler = newAuthenticationMux(s.authentication, txnsHandler)
+	}
+	s.mux.Handle(statusTransactions, txnsHandler)
+	var progressHandler http.Handler = http.HandlerFunc(s.status.handleQueryProgress)
+	if s.cfg.RequireWebSession() {
+		progressHandler = newAuthenticationMux(s.authentication, progressHandler)
+	}
+	s.mux.Handle(statusQueryProgress, progressHandler)
 	log.Event(ctx, "added http endpoints")
 
 	// Attempt to upgrade cluster version.
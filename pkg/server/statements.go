@@ -13,10 +13,13 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -82,6 +85,21 @@ func (s *statusServer) Statements(
 	return response, nil
 }
 
+// handleTransactions serves the transaction fingerprint statistics collected
+// on the local node.
+func (s *statusServer) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	body, err := marshalToJSON(s.admin.server.pgServer.SQLServer.GetTxnFingerprintStats())
+	if err != nil {
+		log.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+	if _, err := w.Write(body); err != nil {
+		log.Error(r.Context(), err)
+	}
+}
+
 func (s *statusServer) StatementsLocal(ctx context.Context) (*serverpb.StatementsResponse, error) {
 	stmtStats := s.admin.server.pgServer.SQLServer.GetUnscrubbedStmtStats()
 	lastReset := s.admin.server.pgServer.SQLServer.GetStmtStatsLastReset()
@@ -80,6 +80,14 @@ const (
 	// statusVars exposes prometheus metrics for monitoring consumption.
 	statusVars = statusPrefix + "vars"
 
+	// statusTransactions exposes the transaction fingerprint statistics
+	// collected on the local node as JSON.
+	statusTransactions = statusPrefix + "transactions"
+
+	// statusQueryProgress streams the progress of a query running on the local
+	// node as JSON.
+	statusQueryProgress = statusPrefix + "query_progress"
+
 	// raftStateDormant is used when there is no known raft state.
 	raftStateDormant = "StateDormant"
 
@@ -342,14 +350,12 @@ func (s *statusServer) Allocator(
 					if !rep.OwnsValidLease(store.Clock().Now()) {
 						return false, nil
 					}
-					allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
+					decision, allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
 					if err != nil {
 						return true, err
 					}
-					output.DryRuns = append(output.DryRuns, &serverpb.AllocatorDryRun{
-						RangeID: desc.RangeID,
-						Events:  recordedSpansToTraceEvents(allocatorSpans),
-					})
+					output.DryRuns = append(output.DryRuns, allocatorDecisionToDryRun(
+						desc.RangeID, decision, allocatorSpans))
 					return false, nil
 				})
 			return err
@@ -365,14 +371,12 @@ func (s *statusServer) Allocator(
 			if !rep.OwnsValidLease(store.Clock().Now()) {
 				continue
 			}
-			allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
+			decision, allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
 			if err != nil {
 				return err
 			}
-			output.DryRuns = append(output.DryRuns, &serverpb.AllocatorDryRun{
-				RangeID: rep.RangeID,
-				Events:  recordedSpansToTraceEvents(allocatorSpans),
-			})
+			output.DryRuns = append(output.DryRuns, allocatorDecisionToDryRun(
+				rep.RangeID, decision, allocatorSpans))
 		}
 		return nil
 	})
@@ -382,6 +386,29 @@ func (s *statusServer) Allocator(
 	return output, nil
 }
 
+// allocatorDecisionToDryRun converts the result of an allocator dry run on the
+// given range into its protobuf representation.
+func allocatorDecisionToDryRun(
+	rangeID roachpb.RangeID, decision storage.AllocatorDecision, spans []tracing.RecordedSpan,
+) *serverpb.AllocatorDryRun {
+	dryRun := &serverpb.AllocatorDryRun{
+		RangeID:            rangeID,
+		Events:             recordedSpansToTraceEvents(spans),
+		Action:             decision.Action.String(),
+		LeaseTargetStoreID: decision.LeaseTarget,
+		Details:            decision.Details,
+	}
+	if len(decision.Changes) > 0 {
+		changes := make([]string, len(decision.Changes))
+		for i, chg := range decision.Changes {
+			changes[i] = fmt.Sprintf("%s %s", chg.ChangeType, chg.Target)
+		}
+		dryRun.Changes = strings.Join(changes, ", ")
+		dryRun.Reason = string(decision.Reason)
+	}
+	return dryRun
+}
+
 func recordedSpansToTraceEvents(spans []tracing.RecordedSpan) []*serverpb.TraceEvent {
 	var output []*serverpb.TraceEvent
 	var buf bytes.Buffer
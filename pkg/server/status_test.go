@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
@@ -1505,3 +1506,35 @@ func TestListSessionsSecurity(t *testing.T) {
 		}
 	}
 }
+
+func TestAllocatorDecisionToDryRun(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	decision := storage.AllocatorDecision{
+		Action: storage.AllocatorConsiderRebalance,
+		Changes: roachpb.MakeReplicationChanges(roachpb.ADD_REPLICA, roachpb.ReplicationTarget{
+			NodeID: 2, StoreID: 3,
+		}),
+		Reason:  storagepb.ReasonRebalance,
+		Details: `{"Target":"s3"}`,
+	}
+	decision.Changes = append(decision.Changes, roachpb.MakeReplicationChanges(
+		roachpb.REMOVE_REPLICA, roachpb.ReplicationTarget{NodeID: 1, StoreID: 1})...)
+	expected := &serverpb.AllocatorDryRun{
+		RangeID: 5,
+		Action:  "consider rebalance",
+		Changes: "ADD_REPLICA n2,s3, REMOVE_REPLICA n1,s1",
+		Reason:  "rebalance",
+		Details: `{"Target":"s3"}`,
+	}
+	if dryRun := allocatorDecisionToDryRun(5, decision, nil /* spans */); !reflect.DeepEqual(expected, dryRun) {
+		t.Errorf("expected %+v, found %+v", expected, dryRun)
+	}
+
+	// A lease transfer doesn't come with replication changes.
+	decision = storage.AllocatorDecision{Action: storage.AllocatorNoop, LeaseTarget: 2}
+	expected = &serverpb.AllocatorDryRun{RangeID: 5, Action: "noop", LeaseTargetStoreID: 2}
+	if dryRun := allocatorDecisionToDryRun(5, decision, nil /* spans */); !reflect.DeepEqual(expected, dryRun) {
+		t.Errorf("expected %+v, found %+v", expected, dryRun)
+	}
+}
@@ -25,6 +25,15 @@ func (b *BoolSetting) Get(sv *Values) bool {
 	return sv.getInt64(b.slotIdx) != 0
 }
 
+// GetWithOverrides retrieves the bool value in the setting, taking into
+// account the overrides of the setting at narrower scopes, if any.
+func (b *BoolSetting) GetWithOverrides(sv *Values, o Overrides) bool {
+	if v, ok := o.lookup(b); ok {
+		return v != 0
+	}
+	return b.Get(sv)
+}
+
 func (b *BoolSetting) String(sv *Values) string {
 	return EncodeBool(b.Get(sv))
 }
@@ -55,6 +55,11 @@ const (
 	VersionAuthLocalAndTrustRejectMethods
 	VersionPrimaryKeyColumnsOutOfFamilyZero
 	VersionRootPassword
+	VersionRaftCommandCompression
+	VersionSessionTraces
+	VersionRowLevelTTL
+	VersionPlanChanges
+	VersionSkipScans
 
 	// Add new versions here (step one of two).
 )
@@ -388,6 +393,45 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionRootPassword,
 		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 10},
 	},
+	{
+		// VersionRaftCommandCompression allows the payloads of large Raft
+		// commands to be proposed compressed.
+		Key:     VersionRaftCommandCompression,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 11},
+	},
+	{
+		// VersionSessionTraces introduces the system.session_traces table, to
+		// which SET TRACING can persist the session traces.
+		//
+		// In this version and later the system.session_traces table is part of
+		// the system bootstrap schema.
+		Key:     VersionSessionTraces,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 12},
+	},
+	{
+		// VersionRowLevelTTL introduces the ttl_expiration_column storage
+		// parameter and the row-level TTL jobs that delete the expired rows of
+		// the tables that have one.
+		Key:     VersionRowLevelTTL,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 13},
+	},
+	{
+		// VersionPlanChanges introduces the system.plan_changes table, to which
+		// the plan changes of statement fingerprints that degrade their latency
+		// are recorded.
+		//
+		// In this version and later the system.plan_changes table is part of
+		// the system bootstrap schema.
+		Key:     VersionPlanChanges,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 14},
+	},
+	{
+		// VersionSkipScans allows the optimizer to plan index skip scans. The
+		// table readers of older nodes ignore the skip scan fields of their
+		// spec (DistSQL version 25).
+		Key:     VersionSkipScans,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 15},
+	},
 
 	// Add new versions here (step two of two).
 
@@ -32,6 +32,15 @@ func (d *DurationSetting) Get(sv *Values) time.Duration {
 	return time.Duration(sv.getInt64(d.slotIdx))
 }
 
+// GetWithOverrides retrieves the duration value in the setting, taking into
+// account the overrides of the setting at narrower scopes, if any.
+func (d *DurationSetting) GetWithOverrides(sv *Values, o Overrides) time.Duration {
+	if v, ok := o.lookup(d); ok {
+		return time.Duration(v)
+	}
+	return d.Get(sv)
+}
+
 func (d *DurationSetting) String(sv *Values) string {
 	return EncodeDuration(d.Get(sv))
 }
@@ -32,6 +32,15 @@ func (f *FloatSetting) Get(sv *Values) float64 {
 	return math.Float64frombits(uint64(sv.getInt64(f.slotIdx)))
 }
 
+// GetWithOverrides retrieves the float value in the setting, taking into
+// account the overrides of the setting at narrower scopes, if any.
+func (f *FloatSetting) GetWithOverrides(sv *Values, o Overrides) float64 {
+	if v, ok := o.lookup(f); ok {
+		return math.Float64frombits(uint64(v))
+	}
+	return f.Get(sv)
+}
+
 func (f *FloatSetting) String(sv *Values) string {
 	return EncodeFloat(f.Get(sv))
 }
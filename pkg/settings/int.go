@@ -28,6 +28,15 @@ func (i *IntSetting) Get(sv *Values) int64 {
 	return sv.container.getInt64(i.slotIdx)
 }
 
+// GetWithOverrides retrieves the int value in the setting, taking into
+// account the overrides of the setting at narrower scopes, if any.
+func (i *IntSetting) GetWithOverrides(sv *Values, o Overrides) int64 {
+	if v, ok := o.lookup(i); ok {
+		return v
+	}
+	return i.Get(sv)
+}
+
 func (i *IntSetting) String(sv *Values) string {
 	return EncodeInt(i.Get(sv))
 }
@@ -0,0 +1,243 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package settings
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/pkg/errors"
+)
+
+// OverrideScope is a set of scopes, narrower than the whole cluster, at which
+// the value of a setting can be overridden.
+type OverrideScope uint8
+
+const (
+	// TenantScope allows the setting to be overridden for all the sessions of
+	// a tenant. Nothing populates the tenant overrides yet; the scope exists so
+	// that settings can already be designated as tenant-scoped.
+	TenantScope OverrideScope = 1 << iota
+	// SessionScope allows the setting to be overridden for a single SQL
+	// session.
+	SessionScope
+)
+
+// Overrides is a set of values that take precedence over the cluster-wide
+// values of some settings. Overrides at a narrower scope take precedence over
+// those at a wider one: a session override wins over a tenant override, which
+// wins over the cluster-wide value. Only the settings that have been marked
+// with SetOverridable for a scope can be overridden at that scope.
+//
+// Overrides are unrelated to the Override methods of the individual settings,
+// which change the cluster-wide values in tests.
+//
+// The zero value contains no overrides. Overrides is immutable (Set and Reset
+// return modified copies), so it can be shared freely between copies of the
+// session data that contains it.
+type Overrides struct {
+	// session and tenant map the names of settings to their encoded values
+	// (in the format stored in the system.settings table).
+	session map[string]string
+	tenant  map[string]string
+}
+
+func (o Overrides) scope(scope OverrideScope) map[string]string {
+	switch scope {
+	case SessionScope:
+		return o.session
+	case TenantScope:
+		return o.tenant
+	default:
+		panic(errors.Errorf("invalid override scope %d", scope))
+	}
+}
+
+func (o *Overrides) setScope(scope OverrideScope, m map[string]string) {
+	switch scope {
+	case SessionScope:
+		o.session = m
+	case TenantScope:
+		o.tenant = m
+	default:
+		panic(errors.Errorf("invalid override scope %d", scope))
+	}
+}
+
+// Set returns a copy of o in which the setting with the given name is
+// overridden at the given scope with the encoded value. An error is returned
+// if the setting can't be overridden at scope or if the value is invalid.
+func (o Overrides) Set(scope OverrideScope, name, encoded string) (Overrides, error) {
+	s, ok := registry[name]
+	if !ok {
+		return o, errors.Errorf("unknown cluster setting '%s'", name)
+	}
+	if s.overridableScopes()&scope == 0 {
+		return o, errors.Errorf("cluster setting '%s' cannot be overridden at this scope", name)
+	}
+	if _, err := decodeOverride(s, encoded); err != nil {
+		return o, errors.Wrapf(err, "invalid value for cluster setting '%s'", name)
+	}
+	old := o.scope(scope)
+	m := make(map[string]string, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	m[name] = encoded
+	o.setScope(scope, m)
+	return o, nil
+}
+
+// Reset returns a copy of o in which the setting with the given name is not
+// overridden at the given scope.
+func (o Overrides) Reset(scope OverrideScope, name string) Overrides {
+	old := o.scope(scope)
+	if _, ok := old[name]; !ok {
+		return o
+	}
+	var m map[string]string
+	if len(old) > 1 {
+		m = make(map[string]string, len(old)-1)
+		for k, v := range old {
+			if k != name {
+				m[k] = v
+			}
+		}
+	}
+	o.setScope(scope, m)
+	return o
+}
+
+// Get returns the encoded value with which the setting with the given name is
+// overridden at the given scope, if any.
+func (o Overrides) Get(scope OverrideScope, name string) (encoded string, ok bool) {
+	encoded, ok = o.scope(scope)[name]
+	return encoded, ok
+}
+
+// Names returns the sorted names of the settings that are overridden at the
+// given scope.
+func (o Overrides) Names(scope OverrideScope) []string {
+	m := o.scope(scope)
+	if len(m) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup returns the value of the override of s that is in effect according
+// to the precedence of the scopes, if any. The value is in the representation
+// used by Values.
+func (o Overrides) lookup(s extendedSetting) (int64, bool) {
+	encoded, ok := o.session[s.getKey()]
+	if !ok {
+		if encoded, ok = o.tenant[s.getKey()]; !ok {
+			return 0, false
+		}
+	}
+	v, err := decodeOverride(s, encoded)
+	if err != nil {
+		// The value was validated when it was set, but it might have been set by
+		// a node that has a different definition of the setting. Ignore it.
+		return 0, false
+	}
+	return v, true
+}
+
+// decodeOverride parses the encoded value of s and returns it in the
+// representation used by Values. Only the settings stored as int64 values in
+// Values can be overridden.
+func decodeOverride(s extendedSetting, encoded string) (int64, error) {
+	switch setting := s.(type) {
+	case *BoolSetting:
+		b, err := strconv.ParseBool(encoded)
+		if err != nil {
+			return 0, err
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case *EnumSetting:
+		v, err := strconv.ParseInt(encoded, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := setting.enumValues[v]; !ok {
+			return 0, errors.Errorf("unrecognized value %d", v)
+		}
+		return v, nil
+	case numericSetting: // includes *ByteSizeSetting
+		v, err := strconv.ParseInt(encoded, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return v, setting.Validate(v)
+	case *FloatSetting:
+		f, err := strconv.ParseFloat(encoded, 64)
+		if err != nil {
+			return 0, err
+		}
+		return int64(math.Float64bits(f)), setting.Validate(f)
+	case *DurationSetting:
+		d, err := time.ParseDuration(encoded)
+		if err != nil {
+			return 0, err
+		}
+		return int64(d), setting.Validate(d)
+	default:
+		return 0, errors.Errorf("settings of type %s cannot be overridden", ReadableTypes[s.Typ()])
+	}
+}
+
+// StringWithOverrides returns the string representation of the value of s
+// that is in effect given the overrides, in the same format as s.String.
+func StringWithOverrides(s Setting, sv *Values, o Overrides) string {
+	es, ok := s.(extendedSetting)
+	if !ok {
+		return s.String(sv)
+	}
+	v, ok := o.lookup(es)
+	if !ok {
+		return s.String(sv)
+	}
+	switch setting := es.(type) {
+	case *BoolSetting:
+		return EncodeBool(v != 0)
+	case *EnumSetting:
+		return setting.enumValues[v]
+	case *ByteSizeSetting:
+		return humanizeutil.IBytes(v)
+	case *FloatSetting:
+		return EncodeFloat(math.Float64frombits(uint64(v)))
+	case *DurationSetting:
+		return EncodeDuration(time.Duration(v))
+	default:
+		return EncodeInt(v)
+	}
+}
+
+// IsOverridable returns whether the cluster-wide value of s can be overridden
+// at the given scope.
+func IsOverridable(s Setting, scope OverrideScope) bool {
+	if e, ok := s.(extendedSetting); ok {
+		return e.overridableScopes()&scope != 0
+	}
+	return false
+}
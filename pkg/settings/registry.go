@@ -76,6 +76,7 @@ func register(key, desc string, s extendedSetting) {
 		panic(fmt.Sprintf("setting descriptions should start with a lowercase letter: %q", desc))
 	}
 	s.setDescription(desc)
+	s.setKey(key)
 	registry[key] = s
 	s.setSlotIdx(len(registry))
 }
@@ -96,6 +97,19 @@ func Keys() (res []string) {
 	return res
 }
 
+// OverridableKeys returns a sorted string array with the keys of the settings
+// that can be overridden at the given scope.
+func OverridableKeys(scope OverrideScope) (res []string) {
+	for k, v := range registry {
+		if v.isRetired() || v.overridableScopes()&scope == 0 {
+			continue
+		}
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
 // Lookup returns a Setting by name along with its description.
 // For non-reportable setting, it instantiates a MaskedSetting
 // to masquerade for the underlying setting.
@@ -210,8 +210,13 @@ type extendedSetting interface {
 	isRetired() bool
 	setToDefault(sv *Values)
 	setDescription(desc string)
+	setKey(key string)
+	getKey() string
 	setSlotIdx(slotIdx int)
 	getSlotIdx() int
+	// overridableScopes returns the scopes at which the cluster-wide value of
+	// the setting can be overridden.
+	overridableScopes() OverrideScope
 	// isReportable indicates whether the value of the setting can be
 	// included in user-facing reports such as that produced by SHOW ALL
 	// CLUSTER SETTINGS.
@@ -243,8 +248,12 @@ const (
 )
 
 type common struct {
+	key         string
 	description string
 	visibility  Visibility
+	// overridable are the scopes at which the setting can be overridden (see
+	// Overrides).
+	overridable OverrideScope
 	// Each setting has a slotIdx which is used as a handle with Values.
 	slotIdx       int
 	nonReportable bool
@@ -272,6 +281,18 @@ func (i *common) setDescription(s string) {
 	i.description = s
 }
 
+func (i *common) setKey(key string) {
+	i.key = key
+}
+
+func (i *common) getKey() string {
+	return i.key
+}
+
+func (i common) overridableScopes() OverrideScope {
+	return i.overridable
+}
+
 func (i common) Description() string {
 	return i.description
 }
@@ -310,6 +331,12 @@ func (i *common) SetRetired() {
 	i.retired = true
 }
 
+// SetOverridable marks the setting as one whose cluster-wide value can be
+// overridden at the given scopes. See Overrides.
+func (i *common) SetOverridable(scopes OverrideScope) {
+	i.overridable |= scopes
+}
+
 // SetOnChange installs a callback to be called when a setting's value changes.
 // `fn` should avoid doing long-running or blocking work as it is called on the
 // goroutine which handles all settings updates.
@@ -750,3 +750,93 @@ func TestOverride(t *testing.T) {
 	u.ResetRemaining()
 	require.Equal(t, 42.0, overrideFloat.Get(sv))
 }
+
+var sessionInt = func() *settings.IntSetting {
+	s := settings.RegisterNonNegativeIntSetting("session.int", "desc", 1)
+	s.SetOverridable(settings.SessionScope | settings.TenantScope)
+	return s
+}()
+var sessionByteSize = func() *settings.ByteSizeSetting {
+	s := settings.RegisterByteSizeSetting("session.bytesize", "desc", mb)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+var sessionEnum = func() *settings.EnumSetting {
+	s := settings.RegisterEnumSetting("session.enum", "desc", "foo", map[int64]string{1: "foo", 2: "bar"})
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+var tenantBool = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting("tenant.bool", "desc", false)
+	s.SetOverridable(settings.TenantScope)
+	return s
+}()
+
+func TestOverrides(t *testing.T) {
+	sv := &settings.Values{}
+	sv.Init(settings.TestOpaque)
+
+	var o settings.Overrides
+	require.Equal(t, int64(1), sessionInt.GetWithOverrides(sv, o))
+	require.True(t, settings.IsOverridable(sessionInt, settings.SessionScope))
+	require.False(t, settings.IsOverridable(tenantBool, settings.SessionScope))
+	require.False(t, settings.IsOverridable(i1A, settings.SessionScope))
+
+	// Only valid values of overridable settings can be set.
+	_, err := o.Set(settings.SessionScope, "i.1", "3")
+	require.EqualError(t, err, "cluster setting 'i.1' cannot be overridden at this scope")
+	_, err = o.Set(settings.SessionScope, "tenant.bool", "true")
+	require.EqualError(t, err, "cluster setting 'tenant.bool' cannot be overridden at this scope")
+	_, err = o.Set(settings.SessionScope, "session.int", "-3")
+	require.EqualError(t, err, "invalid value for cluster setting 'session.int': cannot set session.int to a negative value: -3")
+	_, err = o.Set(settings.SessionScope, "session.enum", "3")
+	require.EqualError(t, err, "invalid value for cluster setting 'session.enum': unrecognized value 3")
+	_, err = o.Set(settings.SessionScope, "unknown", "3")
+	require.EqualError(t, err, "unknown cluster setting 'unknown'")
+
+	// A session override takes precedence over a tenant override, which takes
+	// precedence over the cluster-wide value.
+	tenant, err := o.Set(settings.TenantScope, "session.int", "2")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), sessionInt.GetWithOverrides(sv, tenant))
+	session, err := tenant.Set(settings.SessionScope, "session.int", "3")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), sessionInt.GetWithOverrides(sv, session))
+	require.Equal(t, "3", settings.StringWithOverrides(sessionInt, sv, session))
+	require.Equal(t, []string{"session.int"}, session.Names(settings.SessionScope))
+	// The overrides that Set was called on are not modified.
+	require.Equal(t, int64(2), sessionInt.GetWithOverrides(sv, tenant))
+	require.Nil(t, tenant.Names(settings.SessionScope))
+
+	session, err = session.Set(settings.SessionScope, "session.bytesize", settings.EncodeInt(2*mb))
+	require.NoError(t, err)
+	require.Equal(t, 2*mb, sessionByteSize.GetWithOverrides(sv, session))
+	require.Equal(t, "2.0 MiB", settings.StringWithOverrides(sessionByteSize, sv, session))
+	session, err = session.Set(settings.SessionScope, "session.enum", "2")
+	require.NoError(t, err)
+	require.Equal(t, "bar", settings.StringWithOverrides(sessionEnum, sv, session))
+	session, err = session.Set(settings.TenantScope, "tenant.bool", "true")
+	require.NoError(t, err)
+	require.True(t, tenantBool.GetWithOverrides(sv, session))
+	require.Equal(t,
+		[]string{"session.bytesize", "session.enum", "session.int"}, session.Names(settings.SessionScope),
+	)
+	encoded, ok := session.Get(settings.SessionScope, "session.bytesize")
+	require.True(t, ok)
+	require.Equal(t, settings.EncodeInt(2*mb), encoded)
+
+	// Resetting a session override uncovers the tenant override, if any.
+	session = session.Reset(settings.SessionScope, "session.int")
+	require.Equal(t, int64(2), sessionInt.GetWithOverrides(sv, session))
+	session = session.Reset(settings.TenantScope, "session.int")
+	require.Equal(t, int64(1), sessionInt.GetWithOverrides(sv, session))
+	require.Equal(t, mb, sessionByteSize.Get(sv))
+}
+
+func TestOverridableKeys(t *testing.T) {
+	require.Equal(t,
+		[]string{"session.bytesize", "session.enum", "session.int"},
+		settings.OverridableKeys(settings.SessionScope),
+	)
+	require.Equal(t, []string{"session.int", "tenant.bool"}, settings.OverridableKeys(settings.TenantScope))
+}
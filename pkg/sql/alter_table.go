@@ -494,6 +494,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 				continue
 			}
 
+			if col.ID == n.tableDesc.TTLExpirationColumnID {
+				return pgerror.Newf(pgcode.InvalidColumnReference,
+					"column %q is the TTL expiration column of table %q", col.Name, n.tableDesc.Name)
+			}
+
 			// If the dropped column uses a sequence, remove references to it from that sequence.
 			if len(col.UsesSequenceIds) > 0 {
 				if err := params.p.removeSequenceDependencies(params.ctx, n.tableDesc, col); err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -49,6 +50,10 @@ type appStats struct {
 	st    *cluster.Settings
 	stmts map[stmtKey]*stmtStats
 	txns  transactionStats
+	// txnFingerprints holds the per-transaction-fingerprint statistics.
+	txnFingerprints map[txnKey]*txnStats
+	// txnFingerprintsAcc accounts for the memory used by txnFingerprints.
+	txnFingerprintsAcc mon.BoundAccount
 }
 
 // stmtStats holds per-statement statistics.
@@ -56,6 +61,9 @@ type stmtStats struct {
 	syncutil.Mutex
 
 	data roachpb.StatementStatistics
+	// plan tracks the sampled plans of the statement and their latencies, to
+	// detect plan regressions.
+	plan planHistory
 }
 
 // transactionStats holds per-application transaction statistics.
@@ -122,7 +130,9 @@ func (s stmtKey) flags() string {
 	return b.String()
 }
 
-// recordStatement saves per-statement statistics.
+// recordStatement saves per-statement statistics. It returns the plan
+// regression of the statement if the sampled plan changed and the latency of
+// the statement degraded since, or nil.
 //
 // samplePlanDescription can be nil, as these are only sampled periodically per unique fingerprint.
 func (a *appStats) recordStatement(
@@ -136,17 +146,18 @@ func (a *appStats) recordStatement(
 	err error,
 	parseLat, planLat, runLat, svcLat, ovhLat float64,
 	bytesRead, rowsRead int64,
-) {
+) *planRegression {
 	if !stmtStatsEnable.Get(&a.st.SV) {
-		return
+		return nil
 	}
 
 	if t := sqlStatsCollectionLatencyThreshold.Get(&a.st.SV); t > 0 && t.Seconds() >= svcLat {
-		return
+		return nil
 	}
 
 	// Get the statistics object.
-	s := a.getStatsForStmt(stmt, distSQLUsed, optUsed, implicitTxn, err, true /* createIfNonexistent */)
+	key := makeStmtKey(stmt, distSQLUsed, optUsed, implicitTxn, err)
+	s := a.getStatsForStmtWithKey(key, true /* createIfNonexistent */)
 
 	// Collect the per-statement statistics.
 	s.Lock()
@@ -158,6 +169,7 @@ func (a *appStats) recordStatement(
 	if samplePlanDescription != nil {
 		s.data.SensitiveInfo.MostRecentPlanDescription = *samplePlanDescription
 		s.data.SensitiveInfo.MostRecentPlanTimestamp = timeutil.Now()
+		s.plan.observe(planGist(samplePlanDescription))
 	}
 	if automaticRetryCount == 0 {
 		s.data.FirstAttemptCount++
@@ -172,7 +184,18 @@ func (a *appStats) recordStatement(
 	s.data.OverheadLat.Record(s.data.Count, ovhLat)
 	s.data.BytesRead = bytesRead
 	s.data.RowsRead = rowsRead
+	var regression *planRegression
+	if s.plan.record(svcLat, planRegressionLatencyThreshold.Get(&a.st.SV)) {
+		regression = &planRegression{
+			fingerprint: key.stmt,
+			oldGist:     s.plan.prevGist,
+			newGist:     s.plan.gist,
+			oldLatency:  s.plan.prevLatency,
+			newLatency:  s.plan.latency,
+		}
+	}
 	s.Unlock()
+	return regression
 }
 
 // getStatsForStmt retrieves the per-stmt stat object.
@@ -184,6 +207,15 @@ func (a *appStats) getStatsForStmt(
 	err error,
 	createIfNonexistent bool,
 ) *stmtStats {
+	key := makeStmtKey(stmt, distSQLUsed, optimizerUsed, implicitTxn, err)
+	return a.getStatsForStmtWithKey(key, createIfNonexistent)
+}
+
+// makeStmtKey returns the key under which the statistics of the statement are
+// collected.
+func makeStmtKey(
+	stmt *Statement, distSQLUsed bool, optimizerUsed bool, implicitTxn bool, err error,
+) stmtKey {
 	// Extend the statement key with various characteristics, so
 	// that we use separate buckets for the different situations.
 	key := stmtKey{failed: err != nil, distSQLUsed: distSQLUsed, optUsed: optimizerUsed, implicitTxn: implicitTxn}
@@ -193,8 +225,7 @@ func (a *appStats) getStatsForStmt(
 	} else {
 		key.stmt = anonymizeStmt(stmt.AST)
 	}
-
-	return a.getStatsForStmtWithKey(key, createIfNonexistent)
+	return key
 }
 
 func (a *appStats) getStatsForStmtWithKey(key stmtKey, createIfNonexistent bool) *stmtStats {
@@ -260,6 +291,15 @@ type sqlStats struct {
 	lastReset time.Time
 	// apps is the container for all the per-application statistics objects.
 	apps map[string]*appStats
+	// activeTxns tracks the transactions in progress on this node, for the
+	// purposes of contention attribution.
+	activeTxns activeTxnRegistry
+	// contentionEvents retains the most recent contention events encountered
+	// by the statements executed on this node.
+	contentionEvents contentionEventRegistry
+	// pool is the monitor against which the memory of the per-application
+	// statistics is accounted.
+	pool *mon.BytesMonitor
 }
 
 func (s *sqlStats) getStatsForApplication(appName string) *appStats {
@@ -269,9 +309,11 @@ func (s *sqlStats) getStatsForApplication(appName string) *appStats {
 		return a
 	}
 	a := &appStats{
-		st:    s.st,
-		stmts: make(map[stmtKey]*stmtStats),
+		st:              s.st,
+		stmts:           make(map[stmtKey]*stmtStats),
+		txnFingerprints: make(map[txnKey]*txnStats),
 	}
+	a.txnFingerprintsAcc = s.pool.MakeBoundAccount()
 	s.apps[appName] = a
 	return a
 }
@@ -310,6 +352,8 @@ func (s *sqlStats) resetStats(ctx context.Context) {
 		// Clear the map, to release the memory; make the new map somewhat already
 		// large for the likely future workload.
 		a.stmts = make(map[stmtKey]*stmtStats, len(a.stmts)/2)
+		a.txnFingerprints = make(map[txnKey]*txnStats, len(a.txnFingerprints)/2)
+		a.txnFingerprintsAcc.Clear(ctx)
 		a.Unlock()
 	}
 	s.lastReset = timeutil.Now()
@@ -0,0 +1,165 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// ArrowBatchWriter receives the results of a query exported by
+// ExportArrowBatches.
+type ArrowBatchWriter interface {
+	// Init is called once, before any batch is written, with the result
+	// columns of the query and their columnar types.
+	Init(cols sqlbase.ResultColumns, typs []coltypes.T) error
+	// WriteBatch is called with every batch of results. The batch is only valid
+	// until WriteBatch returns.
+	WriteBatch(batch coldata.Batch) error
+}
+
+// ExportArrowBatches runs the given SELECT query as user against the given
+// database and writes its results to w in columnar batches. The query runs in
+// its own transaction, which is not retried.
+//
+// The batches of the vectorized flow running the query are written as they
+// are, so the query must be supported by the vectorized engine, and only the
+// columns whose types have a native columnar representation can be exported.
+func ExportArrowBatches(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	user string,
+	database string,
+	query string,
+	w ArrowBatchWriter,
+) (retErr error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return err
+	}
+	if _, ok := stmt.AST.(*tree.Select); !ok {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"only SELECT queries can be exported, got %s", stmt.AST.StatementTag())
+	}
+
+	txn := client.NewTxn(ctx, execCfg.DB, execCfg.NodeID.Get())
+	defer func() {
+		if retErr != nil {
+			txn.CleanupOnError(ctx, retErr)
+			return
+		}
+		retErr = txn.CommitOrCleanup(ctx)
+	}()
+
+	p, cleanup := newInternalPlanner("export-arrow", txn, user, &MemoryMetrics{}, execCfg)
+	defer cleanup()
+	sd := p.SessionData()
+	sd.Database = database
+	sd.DistSQLMode = sessiondata.DistSQLExecMode(DistSQLClusterExecMode.Get(&execCfg.Settings.SV))
+	// The flow has to be vectorized for its batches to be written out.
+	sd.VectorizeMode = sessiondata.VectorizeExperimentalAlways
+
+	p.stmt = &Statement{Statement: stmt}
+	if err := p.makeOptimizerPlan(ctx); err != nil {
+		return err
+	}
+	defer p.curPlan.close(ctx)
+	if len(p.curPlan.subqueryPlans) > 0 || len(p.curPlan.postqueryPlans) > 0 {
+		return unimplemented.New("export-arrow-subqueries", "exporting queries with subqueries")
+	}
+
+	cols := planColumns(p.curPlan.plan)
+	typs := make([]coltypes.T, len(cols))
+	for i := range cols {
+		switch cols[i].Typ.Family() {
+		case types.BoolFamily, types.IntFamily, types.FloatFamily, types.StringFamily, types.BytesFamily:
+			typs[i] = typeconv.FromColumnType(cols[i].Typ)
+		default:
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot export column %q of type %s", cols[i].Name, cols[i].Typ)
+		}
+	}
+	if err := w.Init(cols, typs); err != nil {
+		return err
+	}
+
+	rw := &arrowResultWriter{w: w}
+	recv := MakeDistSQLReceiver(
+		ctx,
+		rw,
+		stmt.AST.StatementType(),
+		execCfg.RangeDescriptorCache,
+		execCfg.LeaseHolderCache,
+		txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+		p.ExtendedEvalContext().Tracing,
+	)
+	defer recv.Release()
+
+	evalCtx := p.ExtendedEvalContext()
+	planCtx := execCfg.DistSQLPlanner.NewPlanningCtx(ctx, evalCtx, txn)
+	planCtx.isLocal = !shouldDistributePlan(ctx, sd.DistSQLMode, execCfg.DistSQLPlanner, p.curPlan.plan)
+	planCtx.planner = p
+	planCtx.stmtType = recv.stmtType
+
+	execCfg.DistSQLPlanner.PlanAndRun(ctx, evalCtx, planCtx, txn, p.curPlan.plan, recv)()
+	return rw.Err()
+}
+
+// arrowResultWriter is the result writer of ExportArrowBatches. It hands the
+// batches of the flow over to an ArrowBatchWriter.
+type arrowResultWriter struct {
+	w   ArrowBatchWriter
+	err error
+}
+
+var _ rowResultWriter = &arrowResultWriter{}
+var _ batchResultWriter = &arrowResultWriter{}
+
+// AddBatch is part of the batchResultWriter interface.
+func (w *arrowResultWriter) AddBatch(ctx context.Context, batch coldata.Batch) error {
+	return w.w.WriteBatch(batch)
+}
+
+// AddRow is part of the rowResultWriter interface. The rows are only pushed
+// when the flow isn't vectorized.
+func (w *arrowResultWriter) AddRow(ctx context.Context, row tree.Datums) error {
+	return pgerror.New(pgcode.FeatureNotSupported,
+		"only the queries that are supported by the vectorized engine can be exported")
+}
+
+// IncrementRowsAffected is part of the rowResultWriter interface.
+func (w *arrowResultWriter) IncrementRowsAffected(n int) {}
+
+// SetError is part of the rowResultWriter interface.
+func (w *arrowResultWriter) SetError(err error) {
+	w.err = err
+}
+
+// Err is part of the rowResultWriter interface.
+func (w *arrowResultWriter) Err() error {
+	return w.err
+}
@@ -11,6 +11,7 @@
 package sql
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -756,6 +758,8 @@ func (sc *SchemaChanger) distBackfill(
 		origNRanges := -1
 		origFractionCompleted := sc.job.FractionCompleted()
 		fractionLeft := 1 - origFractionCompleted
+		fractionCompleted := origFractionCompleted
+		progress := jobspb.SchemaChangeProgress{StartedAt: timeutil.ToUnixMicros(timeutil.Now())}
 		readAsOf := sc.clock.Now()
 		// Index backfilling ingests SSTs that don't play nicely with running txns
 		// since they just add their keys blindly. Running a Scan of the target
@@ -808,14 +812,16 @@ func (sc *SchemaChanger) distBackfill(
 				}
 				if origNRanges == -1 {
 					origNRanges = nRanges
+					progress.TotalRanges = int64(nRanges)
 				}
 
 				if nRanges < origNRanges {
 					fractionRangesFinished := float32(origNRanges-nRanges) / float32(origNRanges)
-					fractionCompleted := origFractionCompleted + fractionLeft*fractionRangesFinished
-					if err := sc.job.FractionProgressed(ctx, jobs.FractionUpdater(fractionCompleted)); err != nil {
-						return jobs.SimplifyInvalidStatusError(err)
-					}
+					fractionCompleted = origFractionCompleted + fractionLeft*fractionRangesFinished
+				}
+				progress.CompletedRanges = int64(origNRanges - nRanges)
+				if err := sc.reportBackfillProgress(ctx, fractionCompleted, &progress); err != nil {
+					return err
 				}
 
 				tc := &TableCollection{
@@ -857,6 +863,9 @@ func (sc *SchemaChanger) distBackfill(
 					if meta.BulkProcessorProgress != nil {
 						todoSpans = roachpb.SubtractSpans(todoSpans,
 							meta.BulkProcessorProgress.CompletedSpans)
+						summary := &meta.BulkProcessorProgress.BulkSummary
+						progress.Rows += summary.Rows + summary.IndexEntries
+						progress.DataSize += summary.DataSize
 					}
 					return nil
 				}
@@ -918,11 +927,74 @@ func (sc *SchemaChanger) distBackfill(
 				return err
 			}
 		}
-		return nil
+		progress.CompletedRanges = progress.TotalRanges
+		return sc.reportBackfillProgress(ctx, fractionCompleted, &progress)
 	})
 	return g.Wait()
 }
 
+// reportBackfillProgress persists the progress of a backfill in the job's
+// progress details along with the fraction completed of the job. Once the
+// backfill has made some progress, it's also summarized in the job's running
+// status so that it's visible in SHOW JOBS.
+func (sc *SchemaChanger) reportBackfillProgress(
+	ctx context.Context, fractionCompleted float32, progress *jobspb.SchemaChangeProgress,
+) error {
+	updateBackfillThroughput(progress, timeutil.Now())
+	if err := sc.job.FractionProgressed(ctx, func(
+		ctx context.Context, details jobspb.ProgressDetails,
+	) float32 {
+		if d, ok := details.(*jobspb.Progress_SchemaChange); ok && d.SchemaChange != nil {
+			*d.SchemaChange = *progress
+		}
+		return fractionCompleted
+	}); err != nil {
+		return jobs.SimplifyInvalidStatusError(err)
+	}
+	if progress.CompletedRanges == 0 && progress.Rows == 0 {
+		return nil
+	}
+	if err := sc.job.RunningStatus(ctx, func(
+		ctx context.Context, details jobspb.Details,
+	) (jobs.RunningStatus, error) {
+		return backfillRunningStatus(progress), nil
+	}); err != nil {
+		return jobs.SimplifyInvalidStatusError(err)
+	}
+	return nil
+}
+
+// updateBackfillThroughput updates the ingestion rate of a backfill and its
+// estimated completion time as of now. The completion is extrapolated from
+// the fraction of ranges backfilled so far.
+func updateBackfillThroughput(progress *jobspb.SchemaChangeProgress, now time.Time) {
+	elapsed := now.Sub(timeutil.FromUnixMicros(progress.StartedAt))
+	if elapsed <= 0 {
+		return
+	}
+	progress.RowsPerSecond = int64(float64(progress.Rows) / elapsed.Seconds())
+	progress.EstimatedCompletion = 0
+	if progress.TotalRanges > 0 && progress.CompletedRanges > 0 {
+		fractionFinished := float64(progress.CompletedRanges) / float64(progress.TotalRanges)
+		progress.EstimatedCompletion = progress.StartedAt +
+			int64(float64(elapsed/time.Microsecond)/fractionFinished)
+	}
+}
+
+// backfillRunningStatus returns the running status of a job summarizing the
+// progress of its backfill.
+func backfillRunningStatus(progress *jobspb.SchemaChangeProgress) jobs.RunningStatus {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s: %d/%d ranges, %d rows (%d rows/s), %s ingested",
+		RunningStatusBackfill, progress.CompletedRanges, progress.TotalRanges,
+		progress.Rows, progress.RowsPerSecond, humanizeutil.IBytes(progress.DataSize))
+	if progress.EstimatedCompletion != 0 && progress.CompletedRanges < progress.TotalRanges {
+		fmt.Fprintf(&buf, ", estimated completion at %s",
+			timeutil.FromUnixMicros(progress.EstimatedCompletion).Format(time.RFC3339))
+	}
+	return jobs.RunningStatus(buf.String())
+}
+
 // update the job running status.
 func (sc *SchemaChanger) updateJobRunningStatus(
 	ctx context.Context, status jobs.RunningStatus,
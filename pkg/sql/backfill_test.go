@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestBackfillProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	progress := jobspb.SchemaChangeProgress{
+		TotalRanges: 8,
+		StartedAt:   timeutil.ToUnixMicros(start),
+	}
+
+	// No estimate can be made before any range is finished.
+	progress.Rows = 1000
+	updateBackfillThroughput(&progress, start.Add(10*time.Second))
+	if progress.RowsPerSecond != 100 {
+		t.Errorf("expected 100 rows/s, got %d", progress.RowsPerSecond)
+	}
+	if progress.EstimatedCompletion != 0 {
+		t.Errorf("expected no estimated completion, got %d", progress.EstimatedCompletion)
+	}
+
+	// A quarter of the ranges were backfilled in 20s, so the whole backfill
+	// should take 80s.
+	progress.Rows = 3000
+	progress.DataSize = 3 << 20
+	progress.CompletedRanges = 2
+	updateBackfillThroughput(&progress, start.Add(20*time.Second))
+	if progress.RowsPerSecond != 150 {
+		t.Errorf("expected 150 rows/s, got %d", progress.RowsPerSecond)
+	}
+	if e, a := start.Add(80*time.Second), timeutil.FromUnixMicros(progress.EstimatedCompletion); !e.Equal(a) {
+		t.Errorf("expected estimated completion at %s, got %s", e, a)
+	}
+	expected := jobs.RunningStatus("populating schema: 2/8 ranges, 3000 rows (150 rows/s), 3.0 MiB " +
+		"ingested, estimated completion at 2019-12-01T10:01:20Z")
+	if status := backfillRunningStatus(&progress); status != expected {
+		t.Errorf("expected running status %q, got %q", expected, status)
+	}
+
+	// The estimate is omitted once the backfill is done.
+	progress.CompletedRanges = progress.TotalRanges
+	expected = "populating schema: 8/8 ranges, 3000 rows (150 rows/s), 3.0 MiB ingested"
+	if status := backfillRunningStatus(&progress); status != expected {
+		t.Errorf("expected running status %q, got %q", expected, status)
+	}
+}
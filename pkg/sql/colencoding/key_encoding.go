@@ -15,10 +15,15 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -29,9 +34,11 @@ import (
 // first table id / index id prefix removed. If matches is false, the key is
 // from a different table, and the returned remainingKey indicates a
 // "seek prefix": the next key that might be part of the table being searched
-// for. The input key will also be mutated if matches is false.
+// for. The input key will also be mutated if matches is false. da is used for
+// the datums that are decoded along the way.
 // See the analog in sqlbase/index_encoding.go.
 func DecodeIndexKeyToCols(
+	da *sqlbase.DatumAlloc,
 	vecs []coldata.Vec,
 	idx uint16,
 	desc *sqlbase.ImmutableTableDescriptor,
@@ -69,8 +76,8 @@ func DecodeIndexKeyToCols(
 			// We don't care about whether this call to DecodeKeyVals found a null or not, because
 			// it is a interleaving ancestor.
 			var isNull bool
-			key, isNull, err = DecodeKeyValsToCols(vecs, idx, indexColIdx[:length], types[:length], colDirs[:length],
-				nil /* unseen */, key)
+			key, isNull, err = DecodeKeyValsToCols(da, vecs, idx, indexColIdx[:length], types[:length],
+				colDirs[:length], nil /* unseen */, key)
 			if err != nil {
 				return nil, false, false, err
 			}
@@ -103,7 +110,7 @@ func DecodeIndexKeyToCols(
 	}
 
 	var isNull bool
-	key, isNull, err = DecodeKeyValsToCols(vecs, idx, indexColIdx, types, colDirs, nil /* unseen */, key)
+	key, isNull, err = DecodeKeyValsToCols(da, vecs, idx, indexColIdx, types, colDirs, nil /* unseen */, key)
 	if err != nil {
 		return nil, false, false, err
 	}
@@ -131,6 +138,7 @@ func DecodeIndexKeyToCols(
 // See the analog in sqlbase/index_encoding.go.
 // DecodeKeyValsToCols additionally returns whether a NULL was encountered when decoding.
 func DecodeKeyValsToCols(
+	da *sqlbase.DatumAlloc,
 	vecs []coldata.Vec,
 	idx uint16,
 	indexColIdx []int,
@@ -155,7 +163,7 @@ func DecodeKeyValsToCols(
 				unseen.Remove(i)
 			}
 			var isNull bool
-			key, isNull, err = decodeTableKeyToCol(vecs[i], idx, &types[j], key, enc)
+			key, isNull, err = decodeTableKeyToCol(da, vecs[i], idx, &types[j], key, enc)
 			foundNull = isNull || foundNull
 		}
 		if err != nil {
@@ -170,7 +178,12 @@ func DecodeKeyValsToCols(
 // See the analog, DecodeTableKey, in sqlbase/column_type_encoding.go.
 // decodeTableKeyToCol also returns whether or not the decoded value was NULL.
 func decodeTableKeyToCol(
-	vec coldata.Vec, idx uint16, valType *types.T, key []byte, dir sqlbase.IndexDescriptor_Direction,
+	da *sqlbase.DatumAlloc,
+	vec coldata.Vec,
+	idx uint16,
+	valType *types.T,
+	key []byte,
+	dir sqlbase.IndexDescriptor_Direction,
 ) ([]byte, bool, error) {
 	if (dir != sqlbase.IndexDescriptor_ASC) && (dir != sqlbase.IndexDescriptor_DESC) {
 		return nil, false, errors.AssertionFailedf("invalid direction: %d", log.Safe(dir))
@@ -182,6 +195,20 @@ func decodeTableKeyToCol(
 	}
 	var rkey []byte
 	var err error
+	if typeconv.FromColumnType(valType) == coltypes.Datum {
+		// The types without a native columnar representation are decoded into
+		// tree.Datums.
+		var (
+			d   tree.Datum
+			enc encoding.Direction
+		)
+		if enc, err = dir.ToEncodingDirection(); err != nil {
+			return nil, false, err
+		}
+		d, rkey, err = sqlbase.DecodeTableKey(da, valType, key, enc)
+		vec.Datum()[idx] = d
+		return rkey, false, err
+	}
 	switch valType.Family() {
 	case types.BoolFamily:
 		var i int64
@@ -230,6 +257,24 @@ func decodeTableKeyToCol(
 			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
 		}
 		vec.Bytes().Set(int(idx), r)
+	case types.INetFamily:
+		// The key encoding of an INET is its vectorized representation wrapped
+		// in a bytes encoding.
+		var r []byte
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		vec.Bytes().Set(int(idx), r)
+	case types.BitFamily:
+		var r bitarray.BitArray
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, r, err = encoding.DecodeBitArrayAscending(key)
+		} else {
+			rkey, r, err = encoding.DecodeBitArrayDescending(key)
+		}
+		vec.Bytes().Set(int(idx), typeconv.EncodeBitArray(nil /* b */, r))
 	case types.DateFamily, types.OidFamily:
 		var t int64
 		if dir == sqlbase.IndexDescriptor_ASC {
@@ -246,6 +291,14 @@ func decodeTableKeyToCol(
 			rkey, t, err = encoding.DecodeTimeDescending(key)
 		}
 		vec.Timestamp()[idx] = t
+	case types.IntervalFamily:
+		var d duration.Duration
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, d, err = encoding.DecodeDurationAscending(key)
+		} else {
+			rkey, d, err = encoding.DecodeDurationDescending(key)
+		}
+		vec.Interval()[idx] = d
 	default:
 		return rkey, false, errors.AssertionFailedf("unsupported type %+v", log.Safe(valType))
 	}
@@ -255,16 +308,21 @@ func decodeTableKeyToCol(
 // UnmarshalColumnValueToCol decodes the value from a roachpb.Value using the
 // type expected by the column, writing into the input Vec at the given row
 // idx. An error is returned if the value's type does
-// not match the column's type.
+// not match the column's type. da is used for the datums that are decoded
+// along the way.
 // See the analog, UnmarshalColumnValue, in sqlbase/column_type_encoding.go
 func UnmarshalColumnValueToCol(
-	vec coldata.Vec, idx uint16, typ *types.T, value roachpb.Value,
+	da *sqlbase.DatumAlloc, vec coldata.Vec, idx uint16, typ *types.T, value roachpb.Value,
 ) error {
 	if value.RawBytes == nil {
 		vec.Nulls().SetNull(idx)
 	}
 
 	var err error
+	if typeconv.FromColumnType(typ) == coltypes.Datum {
+		vec.Datum()[idx], err = sqlbase.UnmarshalColumnValue(da, typ, value)
+		return err
+	}
 	switch typ.Family() {
 	case types.BoolFamily:
 		var v bool
@@ -289,10 +347,16 @@ func UnmarshalColumnValueToCol(
 		vec.Float64()[idx] = v
 	case types.DecimalFamily:
 		err = value.GetDecimalInto(&vec.Decimal()[idx])
-	case types.BytesFamily, types.StringFamily, types.UuidFamily:
+	case types.BytesFamily, types.StringFamily, types.UuidFamily, types.INetFamily, types.JsonFamily:
 		var v []byte
 		v, err = value.GetBytes()
 		vec.Bytes().Set(int(idx), v)
+	case types.BitFamily:
+		var v bitarray.BitArray
+		v, err = value.GetBitArray()
+		if err == nil {
+			vec.Bytes().Set(int(idx), typeconv.EncodeBitArray(nil /* b */, v))
+		}
 	case types.DateFamily, types.OidFamily:
 		var v int64
 		v, err = value.GetInt()
@@ -301,6 +365,17 @@ func UnmarshalColumnValueToCol(
 		var v time.Time
 		v, err = value.GetTime()
 		vec.Timestamp()[idx] = v
+	case types.IntervalFamily:
+		var v duration.Duration
+		v, err = value.GetDuration()
+		vec.Interval()[idx] = v
+	case types.ArrayFamily:
+		var v []byte
+		v, err = value.GetBytes()
+		if err == nil {
+			v, err = transcodeArrayValue(da, nil /* b */, typ.ArrayContents(), v)
+			vec.Bytes().Set(int(idx), v)
+		}
 	default:
 		return errors.AssertionFailedf("unsupported column type: %s", log.Safe(typ.Family()))
 	}
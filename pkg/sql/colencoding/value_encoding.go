@@ -14,29 +14,49 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
 // DecodeTableValueToCol decodes a value encoded by EncodeTableValue, writing
-// the result to the idx'th position of the input exec.Vec.
+// the result to the idx'th position of the input exec.Vec. da is used for the
+// datums that are decoded along the way.
 // See the analog in sqlbase/column_type_encoding.go.
 func DecodeTableValueToCol(
-	vec coldata.Vec, idx uint16, typ encoding.Type, dataOffset int, valTyp *types.T, b []byte,
+	da *sqlbase.DatumAlloc,
+	vec coldata.Vec,
+	idx uint16,
+	typ encoding.Type,
+	dataOffset int,
+	valTyp *types.T,
+	b []byte,
 ) ([]byte, error) {
 	// NULL is special because it is a valid value for any type.
 	if typ == encoding.Null {
 		vec.Nulls().SetNull(idx)
 		return b[dataOffset:], nil
 	}
+	if typeconv.FromColumnType(valTyp) == coltypes.Datum {
+		// The types without a native columnar representation are decoded into
+		// tree.Datums.
+		d, rem, err := sqlbase.DecodeTableValue(da, valTyp, b)
+		vec.Datum()[idx] = d
+		return rem, err
+	}
 	// Bool is special because the value is stored in the value tag.
 	if valTyp.Family() != types.BoolFamily {
 		b = b[dataOffset:]
 	}
-	return decodeUntaggedDatumToCol(vec, idx, valTyp, b)
+	return decodeUntaggedDatumToCol(da, vec, idx, valTyp, b)
 }
 
 // decodeUntaggedDatum is used to decode a Datum whose type is known,
@@ -49,7 +69,9 @@ func DecodeTableValueToCol(
 // If t is types.Bool, the value tag must be present, as its value is encoded in
 // the tag directly.
 // See the analog in sqlbase/column_type_encoding.go.
-func decodeUntaggedDatumToCol(vec coldata.Vec, idx uint16, t *types.T, buf []byte) ([]byte, error) {
+func decodeUntaggedDatumToCol(
+	da *sqlbase.DatumAlloc, vec coldata.Vec, idx uint16, t *types.T, buf []byte,
+) ([]byte, error) {
 	var err error
 	switch t.Family() {
 	case types.BoolFamily:
@@ -58,7 +80,7 @@ func decodeUntaggedDatumToCol(vec coldata.Vec, idx uint16, t *types.T, buf []byt
 		// "Untagged" version of this function.
 		buf, b, err = encoding.DecodeBoolValue(buf)
 		vec.Bool()[idx] = b
-	case types.BytesFamily, types.StringFamily:
+	case types.BytesFamily, types.StringFamily, types.JsonFamily:
 		var data []byte
 		buf, data, err = encoding.DecodeUntaggedBytesValue(buf)
 		vec.Bytes().Set(int(idx), data)
@@ -93,13 +115,49 @@ func decodeUntaggedDatumToCol(vec coldata.Vec, idx uint16, t *types.T, buf []byt
 		if err == nil {
 			vec.Bytes().Set(int(idx), data.GetBytes())
 		}
+	case types.INetFamily:
+		var data ipaddr.IPAddr
+		buf, data, err = encoding.DecodeUntaggedIPAddrValue(buf)
+		if err == nil {
+			vec.Bytes().Set(int(idx), data.ToBuffer(nil /* appendTo */))
+		}
+	case types.BitFamily:
+		var data bitarray.BitArray
+		buf, data, err = encoding.DecodeUntaggedBitArrayValue(buf)
+		if err == nil {
+			vec.Bytes().Set(int(idx), typeconv.EncodeBitArray(nil /* b */, data))
+		}
 	case types.TimestampFamily, types.TimestampTZFamily:
 		var t time.Time
 		buf, t, err = encoding.DecodeUntaggedTimeValue(buf)
 		vec.Timestamp()[idx] = t
+	case types.IntervalFamily:
+		var d duration.Duration
+		buf, d, err = encoding.DecodeUntaggedDurationValue(buf)
+		vec.Interval()[idx] = d
+	case types.ArrayFamily:
+		var data []byte
+		buf, data, err = encoding.DecodeUntaggedBytesValue(buf)
+		if err == nil {
+			data, err = transcodeArrayValue(da, nil /* b */, t.ArrayContents(), data)
+			vec.Bytes().Set(int(idx), data)
+		}
 	default:
 		return buf, errors.AssertionFailedf(
 			"couldn't decode type: %s", log.Safe(t))
 	}
 	return buf, err
 }
+
+// transcodeArrayValue converts the value encoding of an array (without the
+// length prefix) to its vectorized representation (see typeconv.EncodeArray),
+// appending it to b. The elements are decoded using da.
+func transcodeArrayValue(
+	da *sqlbase.DatumAlloc, b []byte, elemType *types.T, value []byte,
+) ([]byte, error) {
+	d, err := sqlbase.DecodeArrayValueContents(da, elemType, value)
+	if err != nil {
+		return nil, err
+	}
+	return typeconv.EncodeArray(b, d)
+}
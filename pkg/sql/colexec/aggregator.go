@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
@@ -32,6 +33,25 @@ var SupportedAggFns = []execinfrapb.AggregatorSpec_Func{
 	execinfrapb.AggregatorSpec_COUNT,
 	execinfrapb.AggregatorSpec_MIN,
 	execinfrapb.AggregatorSpec_MAX,
+	execinfrapb.AggregatorSpec_STRING_AGG,
+	execinfrapb.AggregatorSpec_ARRAY_AGG,
+	execinfrapb.AggregatorSpec_JSON_AGG,
+	execinfrapb.AggregatorSpec_JSONB_AGG,
+	execinfrapb.AggregatorSpec_SQRDIFF,
+	execinfrapb.AggregatorSpec_VARIANCE,
+	execinfrapb.AggregatorSpec_STDDEV,
+}
+
+// isOrderSensitiveAggFn returns whether the result of the aggregate function
+// depends on the order of its input rows. Such aggregates are only supported
+// by the ordered aggregator.
+func isOrderSensitiveAggFn(aggFn execinfrapb.AggregatorSpec_Func) bool {
+	switch aggFn {
+	case execinfrapb.AggregatorSpec_STRING_AGG, execinfrapb.AggregatorSpec_ARRAY_AGG,
+		execinfrapb.AggregatorSpec_JSON_AGG, execinfrapb.AggregatorSpec_JSONB_AGG:
+		return true
+	}
+	return false
 }
 
 // aggregateFunc is an aggregate function that performs computation on a batch
@@ -152,7 +172,11 @@ var _ Operator = &orderedAggregator{}
 // NewOrderedAggregator creates an ordered aggregator on the given grouping
 // columns. aggCols is a slice where each index represents a new aggregation
 // function. The slice at that index specifies the columns of the input batch
-// that the aggregate function should work on.
+// that the aggregate function should work on. constArguments contains the
+// constant arguments of each aggregate function (for example, the delimiter
+// of STRING_AGG), and sqlTypes contains the SQL types of the input columns;
+// both of them are only used by some aggregate functions and can be nil if
+// none of these are used.
 func NewOrderedAggregator(
 	allocator *Allocator,
 	input Operator,
@@ -160,6 +184,8 @@ func NewOrderedAggregator(
 	aggFns []execinfrapb.AggregatorSpec_Func,
 	groupCols []uint32,
 	aggCols [][]uint32,
+	constArguments []tree.Datums,
+	sqlTypes []types.T,
 	isScalar bool,
 ) (Operator, error) {
 	if len(aggFns) != len(aggCols) {
@@ -209,7 +235,9 @@ func NewOrderedAggregator(
 		isScalar:  isScalar,
 	}
 
-	a.aggregateFuncs, a.outputTypes, err = makeAggregateFuncs(a.allocator, aggTypes, aggFns)
+	a.aggregateFuncs, a.outputTypes, err = makeAggregateFuncs(
+		a.allocator, aggTypes, extractAggSQLTypes(aggCols, sqlTypes), aggFns, constArguments,
+	)
 
 	if err != nil {
 		return nil, errors.AssertionFailedf(
@@ -220,8 +248,15 @@ func NewOrderedAggregator(
 	return a, nil
 }
 
+// makeAggregateFuncs creates the aggregate functions with the given input
+// types. aggSQLTyps and constArguments are only needed by some of the
+// aggregate functions (see NewOrderedAggregator).
 func makeAggregateFuncs(
-	allocator *Allocator, aggTyps [][]coltypes.T, aggFns []execinfrapb.AggregatorSpec_Func,
+	allocator *Allocator,
+	aggTyps [][]coltypes.T,
+	aggSQLTyps [][]types.T,
+	aggFns []execinfrapb.AggregatorSpec_Func,
+	constArguments []tree.Datums,
 ) ([]aggregateFunc, []coltypes.T, error) {
 	funcs := make([]aggregateFunc, len(aggFns))
 	outTyps := make([]coltypes.T, len(aggFns))
@@ -235,6 +270,12 @@ func makeAggregateFuncs(
 			funcs[i], err = newAvgAgg(aggTyps[i][0])
 		case execinfrapb.AggregatorSpec_SUM, execinfrapb.AggregatorSpec_SUM_INT:
 			funcs[i], err = newSumAgg(aggTyps[i][0])
+		case execinfrapb.AggregatorSpec_SQRDIFF:
+			funcs[i], err = newVarianceAgg(aggTyps[i][0], varianceKindSqrDiff)
+		case execinfrapb.AggregatorSpec_VARIANCE:
+			funcs[i], err = newVarianceAgg(aggTyps[i][0], varianceKindVariance)
+		case execinfrapb.AggregatorSpec_STDDEV:
+			funcs[i], err = newVarianceAgg(aggTyps[i][0], varianceKindStdDev)
 		case execinfrapb.AggregatorSpec_COUNT_ROWS:
 			funcs[i] = newCountRowAgg()
 		case execinfrapb.AggregatorSpec_COUNT:
@@ -243,6 +284,27 @@ func makeAggregateFuncs(
 			funcs[i], err = newMinAgg(allocator, aggTyps[i][0])
 		case execinfrapb.AggregatorSpec_MAX:
 			funcs[i], err = newMaxAgg(allocator, aggTyps[i][0])
+		case execinfrapb.AggregatorSpec_STRING_AGG:
+			var delimiter []byte
+			if i < len(constArguments) && len(constArguments[i]) > 0 {
+				switch d := constArguments[i][0].(type) {
+				case *tree.DString:
+					delimiter = []byte(*d)
+				case *tree.DBytes:
+					delimiter = []byte(*d)
+				}
+			}
+			funcs[i], err = newStringAgg(allocator, aggTyps[i][0], delimiter)
+		case execinfrapb.AggregatorSpec_ARRAY_AGG:
+			if i >= len(aggSQLTyps) || aggSQLTyps[i] == nil {
+				return nil, nil, errors.AssertionFailedf("array_agg requires the SQL type of its input")
+			}
+			funcs[i], err = newArrayAgg(allocator, &aggSQLTyps[i][0])
+		case execinfrapb.AggregatorSpec_JSON_AGG, execinfrapb.AggregatorSpec_JSONB_AGG:
+			if i >= len(aggSQLTyps) || aggSQLTyps[i] == nil {
+				return nil, nil, errors.AssertionFailedf("%s requires the SQL type of its input", aggFns[i])
+			}
+			funcs[i] = newJSONAgg(allocator, &aggSQLTyps[i][0])
 		default:
 			return nil, nil, errors.Errorf("unsupported columnar aggregate function %s", aggFns[i].String())
 		}
@@ -253,6 +315,10 @@ func makeAggregateFuncs(
 			// TODO(jordan): this is a somewhat of a hack. The aggregate functions
 			// should come with their own output types, somehow.
 			outTyps[i] = coltypes.Int64
+		case execinfrapb.AggregatorSpec_ARRAY_AGG, execinfrapb.AggregatorSpec_JSON_AGG,
+			execinfrapb.AggregatorSpec_JSONB_AGG:
+			// Arrays and JSON values are stored in their encoded form.
+			outTyps[i] = coltypes.Bytes
 		default:
 			// Output types are the input types for now.
 			outTyps[i] = aggTyps[i][0]
@@ -439,16 +505,29 @@ func extractAggTypes(aggCols [][]uint32, colTypes []coltypes.T) [][]coltypes.T {
 	return aggTyps
 }
 
+// extractAggSQLTypes is the same as extractAggTypes for the SQL types of the
+// input columns. It returns nil if sqlTypes is nil.
+func extractAggSQLTypes(aggCols [][]uint32, sqlTypes []types.T) [][]types.T {
+	if sqlTypes == nil {
+		return nil
+	}
+	aggTyps := make([][]types.T, len(aggCols))
+	for aggIdx := range aggCols {
+		aggTyps[aggIdx] = make([]types.T, len(aggCols[aggIdx]))
+		for i, colIdx := range aggCols[aggIdx] {
+			aggTyps[aggIdx][i] = sqlTypes[colIdx]
+		}
+	}
+	return aggTyps
+}
+
 // isAggregateSupported returns whether the aggregate function that operates on
 // columns of types 'inputTypes' (which can be empty in case of COUNT_ROWS) is
 // supported.
 func isAggregateSupported(
 	aggFn execinfrapb.AggregatorSpec_Func, inputTypes []types.T,
 ) (bool, error) {
-	aggTypes, err := typeconv.FromColumnTypes(inputTypes)
-	if err != nil {
-		return false, err
-	}
+	aggTypes := typeconv.FromColumnTypes(inputTypes)
 	switch aggFn {
 	case execinfrapb.AggregatorSpec_SUM:
 		switch inputTypes[0].Family() {
@@ -467,7 +546,9 @@ func isAggregateSupported(
 	_, outputTypes, err := makeAggregateFuncs(
 		nil, /* allocator */
 		[][]coltypes.T{aggTypes},
+		[][]types.T{inputTypes},
 		[]execinfrapb.AggregatorSpec_Func{aggFn},
+		nil, /* constArguments */
 	)
 	if err != nil {
 		return false, err
@@ -20,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
@@ -62,6 +64,8 @@ type aggType struct {
 		aggFns []execinfrapb.AggregatorSpec_Func,
 		groupCols []uint32,
 		aggCols [][]uint32,
+		constArguments []tree.Datums,
+		sqlTypes []types.T,
 		isScalar bool,
 	) (Operator, error)
 	name string
@@ -275,6 +279,8 @@ func TestAggregatorOneFunc(t *testing.T) {
 				tc.aggFns,
 				tc.groupCols,
 				tc.aggCols,
+				nil,   /* constArguments */
+				nil,   /* sqlTypes */
 				false, /* isScalar */
 			)
 			if err != nil {
@@ -302,6 +308,8 @@ func TestAggregatorOneFunc(t *testing.T) {
 									tc.aggFns,
 									tc.groupCols,
 									tc.aggCols,
+									nil,   /* constArguments */
+									nil,   /* sqlTypes */
 									false, /* isScalar */
 								)
 							})
@@ -369,6 +377,57 @@ func TestAggregatorMultiFunc(t *testing.T) {
 			name:          "AvgSumSingleInputBatch",
 			convToDecimal: true,
 		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_SQRDIFF,
+				execinfrapb.AggregatorSpec_VARIANCE,
+			},
+			aggCols: [][]uint32{
+				{1}, {1},
+			},
+			input: tuples{
+				{0, 1.0},
+				{0, 2.0},
+				{0, nil},
+				{0, 3.0},
+				{0, 4.0},
+				{1, 5.0},
+				{2, nil},
+			},
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Decimal},
+			expected: tuples{
+				{5.0, "1.6666666666666666667"},
+				{0.0, nil},
+				{nil, nil},
+			},
+			name:          "SqrDiffVarianceDecimal",
+			convToDecimal: true,
+		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_VARIANCE,
+				execinfrapb.AggregatorSpec_STDDEV,
+			},
+			aggCols: [][]uint32{
+				{1}, {1},
+			},
+			input: tuples{
+				{0, 1.0},
+				{0, 2.0},
+				{0, 3.0},
+				{0, 4.0},
+				{1, 5.0},
+				{1, 5.0},
+				{2, 6.0},
+			},
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Float64},
+			expected: tuples{
+				{5.0 / 3, math.Sqrt(5.0 / 3)},
+				{0.0, 0.0},
+				{nil, nil},
+			},
+			name: "VarianceStdDevFloat",
+		},
 	}
 
 	for _, agg := range aggTypes {
@@ -379,7 +438,7 @@ func TestAggregatorMultiFunc(t *testing.T) {
 				}
 				runTests(t, []tuples{tc.input}, tc.expected, unorderedVerifier,
 					func(input []Operator) (Operator, error) {
-						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, nil /* constArguments */, nil /* sqlTypes */, false /* isScalar */)
 					})
 			})
 		}
@@ -462,7 +521,7 @@ func TestAggregatorAllFunctions(t *testing.T) {
 					tc.expected,
 					orderedVerifier,
 					func(input []Operator) (Operator, error) {
-						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, nil /* constArguments */, nil /* sqlTypes */, false /* isScalar */)
 					})
 			})
 		}
@@ -542,6 +601,8 @@ func TestAggregatorRandom(t *testing.T) {
 									execinfrapb.AggregatorSpec_AVG},
 								[]uint32{0},
 								[][]uint32{{}, {1}, {1}, {1}, {1}, {1}},
+								nil,   /* constArguments */
+								nil,   /* sqlTypes */
 								false, /* isScalar */
 							)
 							if err != nil {
@@ -697,6 +758,8 @@ func BenchmarkAggregator(b *testing.B) {
 											[]execinfrapb.AggregatorSpec_Func{aggFn},
 											[]uint32{0},
 											[][]uint32{[]uint32{1}[:nCols]},
+											nil,   /* constArguments */
+											nil,   /* sqlTypes */
 											false, /* isScalar */
 										)
 										if err != nil {
@@ -839,7 +902,7 @@ func TestHashAggregator(t *testing.T) {
 			t.Fatal(err)
 		}
 		runTests(t, []tuples{tc.input}, tc.expected, unorderedVerifier, func(sources []Operator) (Operator, error) {
-			return NewHashAggregator(testAllocator, sources[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+			return NewHashAggregator(testAllocator, sources[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, nil /* constArguments */, nil /* sqlTypes */, false /* isScalar */)
 		})
 	}
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 )
 
@@ -53,6 +54,58 @@ func (a *Allocator) NewMemBatchWithSize(types []coltypes.T, size int) coldata.Ba
 	return coldata.NewMemBatchWithSize(types, size)
 }
 
+// minDynamicBatchCapacity is the capacity of the first batch allocated by
+// ResetMaybeReallocate. Starting small keeps the batches of wide rows within
+// the memory budget and makes the queries that only need a few rows cheaper,
+// and the capacity quickly grows up to coldata.BatchSize() for narrow rows.
+const minDynamicBatchCapacity = 64
+
+// ResetMaybeReallocate returns a batch of the given types that is ready to be
+// written into from scratch, reusing oldBatch if possible. It is meant to be
+// used by the components that create batches out of rows coming from outside
+// of the vectorized engine (like the cFetcher) every time they have filled up
+// a batch, and it sizes those batches dynamically:
+//   - if oldBatch is nil, a new batch with minDynamicBatchCapacity capacity is
+//     allocated;
+//   - otherwise, a new batch with double the capacity of oldBatch (but at most
+//     coldata.BatchSize()) is allocated if it is estimated to fit within
+//     maxBatchMemSize bytes, based on the memory footprint of the rows that were
+//     written into oldBatch. If that's not the case, oldBatch is reset and
+//     returned.
+//
+// The memory of oldBatch is released when a new batch is allocated, and
+// reallocated is true in that case: the caller must then drop all references
+// to oldBatch and its vectors.
+func (a *Allocator) ResetMaybeReallocate(
+	types []coltypes.T, oldBatch coldata.Batch, maxBatchMemSize int64,
+) (_ coldata.Batch, reallocated bool) {
+	maxCapacity := int(coldata.BatchSize())
+	if oldBatch == nil {
+		capacity := minDynamicBatchCapacity
+		if capacity > maxCapacity {
+			capacity = maxCapacity
+		}
+		return a.NewMemBatchWithSize(types, capacity), true
+	}
+	oldCapacity := oldBatch.Capacity()
+	newCapacity := 2 * oldCapacity
+	if newCapacity > maxCapacity {
+		newCapacity = maxCapacity
+	}
+	if newCapacity > oldCapacity {
+		// The footprint of oldBatch accounts for the actual sizes of the
+		// variable-width values written into it, so it gives us a good estimate
+		// of the width of the rows to come.
+		oldBatchMemSize := getBatchMemSize(oldBatch)
+		if oldBatchMemSize/int64(oldCapacity)*int64(newCapacity) <= maxBatchMemSize {
+			a.ReleaseMemory(oldBatchMemSize)
+			return a.NewMemBatchWithSize(types, newCapacity), true
+		}
+	}
+	oldBatch.ResetInternalBatch()
+	return oldBatch, false
+}
+
 // NewMemColumn returns a new coldata.Vec, initialized with a length.
 func (a *Allocator) NewMemColumn(t coltypes.T, n int) coldata.Vec {
 	estimatedStaticMemoryUsage := int64(estimateBatchSizeBytes([]coltypes.T{t}, n))
@@ -63,12 +116,14 @@ func (a *Allocator) NewMemColumn(t coltypes.T, n int) coldata.Vec {
 }
 
 // AppendColumn appends a newly allocated coldata.Vec of the given type to b.
+// The new vector has the same capacity as the other vectors in b.
 func (a *Allocator) AppendColumn(b coldata.Batch, t coltypes.T) {
-	estimatedStaticMemoryUsage := int64(estimateBatchSizeBytes([]coltypes.T{t}, int(coldata.BatchSize())))
+	capacity := b.Capacity()
+	estimatedStaticMemoryUsage := int64(estimateBatchSizeBytes([]coltypes.T{t}, capacity))
 	if err := a.acc.Grow(a.ctx, estimatedStaticMemoryUsage); err != nil {
 		execerror.VectorizedInternalPanic(err)
 	}
-	col := a.NewMemColumn(t, int(coldata.BatchSize()))
+	col := a.NewMemColumn(t, capacity)
 	b.AppendCol(col)
 }
 
@@ -82,21 +137,13 @@ func (a *Allocator) PerformOperation(destVecs []coldata.Vec, operation func()) {
 		// To simplify the accounting, we perform the operation first and then will
 		// update the memory account. The minor "drift" in accounting that is
 		// caused by this approach is ok.
-		if dest.Type() == coltypes.Bytes {
-			before += int64(dest.Bytes().Size())
-		} else {
-			before += int64(estimateBatchSizeBytes([]coltypes.T{dest.Type()}, dest.Capacity()))
-		}
+		before += getVecMemoryFootprint(dest)
 	}
 
 	operation()
 
 	for _, dest := range destVecs {
-		if dest.Type() == coltypes.Bytes {
-			after += int64(dest.Bytes().Size())
-		} else {
-			after += int64(estimateBatchSizeBytes([]coltypes.T{dest.Type()}, dest.Capacity()))
-		}
+		after += getVecMemoryFootprint(dest)
 	}
 	delta = after - before
 	if delta >= 0 {
@@ -108,18 +155,69 @@ func (a *Allocator) PerformOperation(destVecs []coldata.Vec, operation func()) {
 	}
 }
 
-// TODO(yuzefovich): extend Allocator so that it could free up the memory (and
-// resize the memory account accordingly) when the caller is done with the
-// batches.
+// AdjustMemoryUsage adjusts the number of bytes currently allocated through
+// this allocator by delta bytes. It should be used to account for the memory
+// that is not held in coldata.Vecs.
+func (a *Allocator) AdjustMemoryUsage(delta int64) {
+	if delta >= 0 {
+		if err := a.acc.Grow(a.ctx, delta); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	} else {
+		a.ReleaseMemory(-delta)
+	}
+}
+
+// Used returns the number of bytes currently allocated through this
+// allocator.
+func (a *Allocator) Used() int64 {
+	return a.acc.Used()
+}
+
+// ReleaseMemory reduces the number of bytes currently allocated through this
+// allocator by (at most) size. It should be called once the caller is done
+// with the batches allocated through this allocator (see getBatchMemSize).
+func (a *Allocator) ReleaseMemory(size int64) {
+	if size > a.acc.Used() {
+		// The memory footprint of the batches is only estimated, so we could be
+		// releasing more than was accounted for.
+		size = a.acc.Used()
+	}
+	a.acc.Shrink(a.ctx, size)
+}
+
+// getVecMemoryFootprint returns the memory footprint of the vector as it is
+// estimated by the Allocator.
+func getVecMemoryFootprint(vec coldata.Vec) int64 {
+	if vec.Type() == coltypes.Bytes {
+		return int64(vec.Bytes().Size())
+	}
+	return int64(estimateBatchSizeBytes([]coltypes.T{vec.Type()}, vec.Capacity()))
+}
+
+// getBatchMemSize returns the memory footprint of the batch (including its
+// selection vector) as it is estimated by the Allocator.
+func getBatchMemSize(b coldata.Batch) int64 {
+	if b.Width() == 0 {
+		return 0
+	}
+	size := int64(b.Capacity() * sizeOfUint16)
+	for _, vec := range b.ColVecs() {
+		size += getVecMemoryFootprint(vec)
+	}
+	return size
+}
 
 const (
-	sizeOfBool    = int(unsafe.Sizeof(true))
-	sizeOfInt16   = int(unsafe.Sizeof(int16(0)))
-	sizeOfInt32   = int(unsafe.Sizeof(int32(0)))
-	sizeOfInt64   = int(unsafe.Sizeof(int64(0)))
-	sizeOfFloat64 = int(unsafe.Sizeof(float64(0)))
-	sizeOfTime    = int(unsafe.Sizeof(time.Time{}))
-	sizeOfUint16  = int(unsafe.Sizeof(uint16(0)))
+	sizeOfBool     = int(unsafe.Sizeof(true))
+	sizeOfInt16    = int(unsafe.Sizeof(int16(0)))
+	sizeOfInt32    = int(unsafe.Sizeof(int32(0)))
+	sizeOfInt64    = int(unsafe.Sizeof(int64(0)))
+	sizeOfFloat64  = int(unsafe.Sizeof(float64(0)))
+	sizeOfTime     = int(unsafe.Sizeof(time.Time{}))
+	sizeOfInterval = int(unsafe.Sizeof(duration.Duration{}))
+	sizeOfUint16   = int(unsafe.Sizeof(uint16(0)))
+	sizeOfDatum    = int(unsafe.Sizeof(interface{}(nil)))
 )
 
 // sizeOfBatchSizeSelVector is the size (in bytes) of a selection vector of
@@ -167,6 +265,12 @@ func estimateBatchSizeBytes(vecTypes []coltypes.T, batchLength int) int {
 			// significantly overestimate.
 			// TODO(yuzefovich): figure out whether the caching does take place.
 			acc += sizeOfTime
+		case coltypes.Interval:
+			acc += sizeOfInterval
+		case coltypes.Datum:
+			// We can't tell how much space the datums will take up, so we use the
+			// same estimate as for decimals (on top of the interface{} itself).
+			acc += sizeOfDatum + 50
 		case coltypes.Unhandled:
 			// Placeholder coldata.Vecs of unknown types are allowed.
 		default:
@@ -0,0 +1,72 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetMaybeReallocate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	typs := []coltypes.T{coltypes.Int64, coltypes.Bytes}
+	minCapacity := minDynamicBatchCapacity
+	if maxCapacity := int(coldata.BatchSize()); minCapacity > maxCapacity {
+		minCapacity = maxCapacity
+	}
+
+	t.Run("GrowsToBatchSize", func(t *testing.T) {
+		b, reallocated := testAllocator.ResetMaybeReallocate(typs, nil /* oldBatch */, math.MaxInt64)
+		require.True(t, reallocated)
+		require.Equal(t, minCapacity, b.Capacity())
+		expectedCapacity := minCapacity
+		for expectedCapacity < int(coldata.BatchSize()) {
+			expectedCapacity *= 2
+			if expectedCapacity > int(coldata.BatchSize()) {
+				expectedCapacity = int(coldata.BatchSize())
+			}
+			b.SetLength(uint16(b.Capacity()))
+			b, reallocated = testAllocator.ResetMaybeReallocate(typs, b, math.MaxInt64)
+			require.True(t, reallocated)
+			require.Equal(t, expectedCapacity, b.Capacity())
+			require.Equal(t, uint16(0), b.Length())
+		}
+		// Once the batch has reached the maximum capacity, it is reused.
+		b.SetLength(uint16(b.Capacity()))
+		newB, reallocated := testAllocator.ResetMaybeReallocate(typs, b, math.MaxInt64)
+		require.False(t, reallocated)
+		require.True(t, b == newB)
+		require.Equal(t, uint16(0), newB.Length())
+	})
+
+	t.Run("RespectsMemoryLimit", func(t *testing.T) {
+		b, _ := testAllocator.ResetMaybeReallocate(typs, nil /* oldBatch */, 1 /* maxBatchMemSize */)
+		require.Equal(t, minCapacity, b.Capacity())
+		// Write wide values into the batch so that a batch of double the
+		// capacity wouldn't fit within the limit.
+		bytesVec := b.ColVec(1).Bytes()
+		for i := 0; i < b.Capacity(); i++ {
+			bytesVec.Set(i, make([]byte, 1024))
+		}
+		b.SetLength(uint16(b.Capacity()))
+		limit := getBatchMemSize(b)
+		newB, reallocated := testAllocator.ResetMaybeReallocate(typs, b, limit)
+		require.False(t, reallocated)
+		require.True(t, b == newB)
+		require.Equal(t, minCapacity, newB.Capacity())
+	})
+}
@@ -224,6 +224,103 @@ func TestAndOrOps(t *testing.T) {
 	}
 }
 
+// TestAndOrOpsShortCircuit verifies that the right side of the logical
+// operators isn't evaluated for the tuples for which the left side already
+// determines the result: the right side would error out if it were.
+func TestAndOrOpsShortCircuit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	input := tuples{{true, 0}, {false, 1}, {nil, 1}, {true, 2}, {nil, 2}}
+	for _, tc := range []struct {
+		desc string
+		post execinfrapb.PostProcessSpec
+		// input, if set, overrides the default input.
+		input    tuples
+		expected tuples
+	}{
+		{
+			desc: "AND projection",
+			post: execinfrapb.PostProcessSpec{
+				RenderExprs: []execinfrapb.Expression{{Expr: "@1 = false AND 1 / @2 = 1"}},
+			},
+			expected: tuples{{false}, {true}, {nil}, {false}, {false}},
+		},
+		{
+			desc: "OR projection",
+			post: execinfrapb.PostProcessSpec{
+				RenderExprs: []execinfrapb.Expression{{Expr: "@1 OR 1 / @2 = 1"}},
+			},
+			expected: tuples{{true}, {true}, {true}, {true}, {nil}},
+		},
+		{
+			desc: "OR filter",
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@1 OR 1 / @2 = 1"},
+				Projection:    true,
+				OutputColumns: []uint32{1},
+			},
+			expected: tuples{{0}, {1}, {1}, {2}},
+		},
+		{
+			// The NULL results follow TRUE ones, so with small batches they are
+			// written over the TRUE values of the previous batches.
+			desc: "OR filter with NULLs after TRUEs",
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@1 OR 1 / @2 = 1"},
+				Projection:    true,
+				OutputColumns: []uint32{1},
+			},
+			input:    tuples{{true, 0}, {true, 1}, {nil, 2}, {nil, 2}, {false, 1}, {nil, 2}},
+			expected: tuples{{0}, {1}, {1}},
+		},
+	} {
+		tcInput := input
+		if tc.input != nil {
+			tcInput = tc.input
+		}
+		t.Run(tc.desc, func(t *testing.T) {
+			// NULLs can't be injected because the division by a NULL is NULL and
+			// the expected results would change.
+			runTestsWithoutAllNullsInjection(
+				t,
+				[]tuples{tcInput},
+				[][]coltypes.T{{coltypes.Bool, coltypes.Int64}},
+				tc.expected,
+				orderedVerifier,
+				func(input []Operator) (Operator, error) {
+					spec := &execinfrapb.ProcessorSpec{
+						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Bool, *types.Int}}},
+						Core: execinfrapb.ProcessorCoreUnion{
+							Noop: &execinfrapb.NoopCoreSpec{},
+						},
+						Post: tc.post,
+					}
+					args := NewColOperatorArgs{
+						Spec:                               spec,
+						Inputs:                             input,
+						StreamingMemAccount:                testMemAcc,
+						UseStreamingMemAccountForBuffering: true,
+					}
+					result, err := NewColOperator(ctx, flowCtx, args)
+					if err != nil {
+						return nil, err
+					}
+					return result.Op, nil
+				})
+		})
+	}
+}
+
 func benchmarkLogicalProjOp(
 	b *testing.B, operation string, useSelectionVector bool, hasNulls bool,
 ) {
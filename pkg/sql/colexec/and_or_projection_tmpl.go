@@ -304,7 +304,9 @@ func _SET_SINGLE_VALUE(_IS_OR_OP bool, _USES_SEL bool, _L_HAS_NULLS bool, _R_HAS
 			// Rule 2: both booleans are FALSE.
 			outputColVals[idx] = false
 		} else {
-			// Rule 3.
+			// Rule 3. The value is overwritten too so that a TRUE left in the
+			// output vector by a previous batch isn't mistaken for the result.
+			outputColVals[idx] = false
 			outputNulls.SetNull(idx)
 		}
 		// {{ else }}
@@ -321,6 +323,7 @@ func _SET_SINGLE_VALUE(_IS_OR_OP bool, _USES_SEL bool, _L_HAS_NULLS bool, _R_HAS
 			outputColVals[idx] = true
 		} else {
 			// Rule 3.
+			outputColVals[idx] = false
 			outputNulls.SetNull(idx)
 		}
 		// {{ end }}
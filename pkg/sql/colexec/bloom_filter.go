@@ -0,0 +1,245 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math/bits"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// hashJoinBloomFilterEnabled controls whether the vectorized hash joiners
+// build a bloom filter of the build side equality columns and use it to
+// discard the probe tuples that definitely have no match before probing. It is
+// off by default since the filter only pays off for selective joins.
+var hashJoinBloomFilterEnabled = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting(
+		"sql.distsql.vectorized_hash_join_bloom_filter.enabled",
+		"if set, vectorized hash joiners filter their probe side with a bloom filter built from their build side",
+		false,
+	)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+
+const (
+	// bloomFilterBitsPerKey and bloomFilterNumHashes determine the false
+	// positive rate of the bloom filter, which is about 1% with these values.
+	bloomFilterBitsPerKey = 10
+	bloomFilterNumHashes  = 4
+	// bloomFilterMaxBits limits the size of the bloom filter to 16MiB. Larger
+	// build sides still use the filter, only with a higher false positive rate.
+	bloomFilterMaxBits = 1 << 27
+)
+
+// bloomFilter is a set of 64 bit hashes that can have false positives but no
+// false negatives. The bit positions of each hash are derived from the hash
+// itself by double hashing, so the hashes must be well distributed.
+type bloomFilter struct {
+	bits []uint64
+	// mask is the number of bits in the filter minus one. The number of bits is
+	// always a power of two.
+	mask uint64
+}
+
+// newBloomFilter returns a new bloomFilter sized for numKeys keys.
+func newBloomFilter(numKeys uint64) *bloomFilter {
+	numBits := uint64(64)
+	for numBits < numKeys*bloomFilterBitsPerKey && numBits < bloomFilterMaxBits {
+		numBits <<= 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, numBits/64),
+		mask: numBits - 1,
+	}
+}
+
+// add adds hash to the filter.
+func (bf *bloomFilter) add(hash uint64) {
+	h1, h2 := hash, bits.RotateLeft64(hash, 32)|1
+	for i := 0; i < bloomFilterNumHashes; i++ {
+		pos := h1 & bf.mask
+		bf.bits[pos>>6] |= 1 << (pos & 63)
+		h1 += h2
+	}
+}
+
+// mayContain returns false if hash has definitely not been added to the filter.
+func (bf *bloomFilter) mayContain(hash uint64) bool {
+	h1, h2 := hash, bits.RotateLeft64(hash, 32)|1
+	for i := 0; i < bloomFilterNumHashes; i++ {
+		pos := h1 & bf.mask
+		if bf.bits[pos>>6]&(1<<(pos&63)) == 0 {
+			return false
+		}
+		h1 += h2
+	}
+	return true
+}
+
+// memoryUsage returns the number of bytes used by the filter.
+func (bf *bloomFilter) memoryUsage() int64 {
+	return int64(len(bf.bits)) * 8
+}
+
+// BloomFilterStats are the statistics of the bloom filter that a hash joiner
+// applies to its probe side.
+type BloomFilterStats struct {
+	// InputTuples is the number of probe tuples that the filter was applied to.
+	InputTuples int64
+	// OutputTuples is the number of probe tuples that passed the filter.
+	OutputTuples int64
+}
+
+// bloomFilterOp is an Operator that is placed by the hash joiner directly on
+// top of its probe source. Once the build phase is over, it discards all the
+// tuples whose equality columns definitely have no match on the build side,
+// so that they are never probed (nor copied into the output of the joiner).
+// When the probe source is a scan, the filter is pushed down into its cFetcher
+// instead (see pushDown) and the operator itself is not planned.
+// It must not be used when the probe side is outer since every probe tuple
+// needs to be emitted in that case.
+//
+// The filter works on the full hash values computed by the hash table, so the
+// probe tuples are hashed the same way as the build tuples were.
+type bloomFilterOp struct {
+	OneInputNode
+
+	allocator *Allocator
+	ht        *hashTable
+	eqCols    []uint32
+	stats     *BloomFilterStats
+
+	// filter is nil until buildFilter is called at the end of the build phase.
+	filter *bloomFilter
+	keys   []coldata.Vec
+	hashes []uint64
+	// rowSel is the selection vector used to hash a single row in mayContainRow.
+	rowSel [1]uint16
+}
+
+var _ Operator = &bloomFilterOp{}
+
+func newBloomFilterOp(
+	allocator *Allocator, input Operator, ht *hashTable, eqCols []uint32, stats *BloomFilterStats,
+) *bloomFilterOp {
+	return &bloomFilterOp{
+		OneInputNode: NewOneInputNode(input),
+		allocator:    allocator,
+		ht:           ht,
+		eqCols:       eqCols,
+		stats:        stats,
+		keys:         make([]coldata.Vec, len(eqCols)),
+		hashes:       make([]uint64, coldata.BatchSize()),
+	}
+}
+
+// Init is part of the Operator interface. Note that the input is initialized
+// by the hash joiner itself.
+func (f *bloomFilterOp) Init() {}
+
+// buildFilter populates the filter with the hashes of all the build tuples.
+func (f *bloomFilterOp) buildFilter(hashes []uint64) {
+	f.filter = newBloomFilter(uint64(len(hashes)))
+	f.allocator.AdjustMemoryUsage(f.filter.memoryUsage())
+	for _, h := range hashes {
+		f.filter.add(h)
+	}
+}
+
+// pushDown makes the cFetcher of input apply the filter, if input is a
+// colBatchScan or a simple projection of one. It returns false if the filter
+// couldn't be pushed down.
+func (f *bloomFilterOp) pushDown(input Operator) bool {
+	cols := make([]int, len(f.eqCols))
+	for i, colIdx := range f.eqCols {
+		cols[i] = int(colIdx)
+	}
+	if p, ok := input.(*simpleProjectOp); ok {
+		for i := range cols {
+			cols[i] = int(p.batch.projection[cols[i]])
+		}
+		input = p.input
+	}
+	scan, ok := input.(*colBatchScan)
+	if !ok {
+		return false
+	}
+	scan.rf.setBloomFilter(f, cols)
+	return true
+}
+
+// mayContainRow returns false if the tuple at rowIdx of vecs, whose equality
+// columns are cols, definitely has no match on the build side. All tuples pass
+// until the filter is built.
+func (f *bloomFilterOp) mayContainRow(
+	ctx context.Context, vecs []coldata.Vec, cols []int, rowIdx uint16,
+) bool {
+	if f.filter == nil {
+		return true
+	}
+	for i, colIdx := range cols {
+		f.keys[i] = vecs[colIdx]
+	}
+	f.rowSel[0] = rowIdx
+	f.ht.computeHashes(ctx, f.hashes[:1], f.keys, 1 /* nKeys */, f.rowSel[:])
+	f.stats.InputTuples++
+	if !f.filter.mayContain(f.hashes[0]) {
+		return false
+	}
+	f.stats.OutputTuples++
+	return true
+}
+
+func (f *bloomFilterOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		batch := f.input.Next(ctx)
+		n := batch.Length()
+		if n == 0 || f.filter == nil {
+			return batch
+		}
+
+		for i, colIdx := range f.eqCols {
+			f.keys[i] = batch.ColVec(int(colIdx))
+		}
+		sel := batch.Selection()
+		f.ht.computeHashes(ctx, f.hashes, f.keys, uint64(n), sel)
+
+		idx := uint16(0)
+		if sel != nil {
+			sel = sel[:n]
+			for i, rowIdx := range sel {
+				if f.filter.mayContain(f.hashes[i]) {
+					sel[idx] = rowIdx
+					idx++
+				}
+			}
+		} else {
+			batch.SetSelection(true)
+			sel = batch.Selection()
+			for i := uint16(0); i < n; i++ {
+				if f.filter.mayContain(f.hashes[i]) {
+					sel[idx] = i
+					idx++
+				}
+			}
+		}
+
+		f.stats.InputTuples += int64(n)
+		f.stats.OutputTuples += int64(idx)
+		if idx > 0 {
+			batch.SetLength(idx)
+			return batch
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	const numKeys = 10000
+	bf := newBloomFilter(numKeys)
+	added := make(map[uint64]struct{}, numKeys)
+	for len(added) < numKeys {
+		h := rng.Uint64()
+		added[h] = struct{}{}
+		bf.add(h)
+	}
+	for h := range added {
+		require.True(t, bf.mayContain(h), "false negative for hash %d", h)
+	}
+
+	const numProbes = 10000
+	falsePositives := 0
+	for i := 0; i < numProbes; i++ {
+		h := rng.Uint64()
+		if _, ok := added[h]; !ok && bf.mayContain(h) {
+			falsePositives++
+		}
+	}
+	// The expected false positive rate is about 1%, so 5% leaves plenty of
+	// room for randomness.
+	require.True(t, falsePositives < numProbes/20, "too many false positives: %d", falsePositives)
+}
+
+func TestHashJoinerBloomFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	typs := []coltypes.T{coltypes.Int64}
+	// The probe (left) side has many keys that are not on the build side.
+	var leftTuples, expected tuples
+	for i := 0; i < 100; i++ {
+		leftTuples = append(leftTuples, tuple{i})
+		if i%10 == 0 {
+			expected = append(expected, tuple{i, i})
+		}
+	}
+	leftTuples = append(leftTuples, tuple{nil})
+	var rightTuples tuples
+	for i := 0; i < 100; i += 10 {
+		rightTuples = append(rightTuples, tuple{i})
+	}
+
+	for _, joinType := range []sqlbase.JoinType{sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_OUTER} {
+		t.Run(joinType.String(), func(t *testing.T) {
+			leftSource := newOpTestInput(7 /* batchSize */, leftTuples, typs)
+			rightSource := newOpTestInput(7 /* batchSize */, rightTuples, typs)
+			op, err := NewEqHashJoinerOp(
+				testAllocator,
+				leftSource, rightSource,
+				[]uint32{0}, []uint32{0},
+				[]uint32{0}, []uint32{0},
+				typs, typs,
+				true /* buildRightSide */, true, /* buildDistinct */
+				joinType, false /* nullEquality */, false, /* rejectOnNull */
+			)
+			require.NoError(t, err)
+			hj := op.(*hashJoinEqOp)
+			stats := hj.enableBloomFilter()
+
+			expectedTuples := expected
+			if joinType == sqlbase.JoinType_LEFT_OUTER {
+				expectedTuples = nil
+				for _, l := range leftTuples {
+					if l[0] != nil && l[0].(int)%10 == 0 {
+						expectedTuples = append(expectedTuples, tuple{l[0], l[0]})
+					} else {
+						expectedTuples = append(expectedTuples, tuple{l[0], nil})
+					}
+				}
+			}
+			out := newOpTestOutput(hj, expectedTuples)
+			require.NoError(t, out.VerifyAnyOrder())
+
+			if joinType == sqlbase.JoinType_LEFT_OUTER {
+				// The probe side is outer, so the filter must not be used.
+				require.Nil(t, hj.bloomFilter.op)
+				require.Zero(t, stats.InputTuples)
+				return
+			}
+			require.Equal(t, int64(len(leftTuples)), stats.InputTuples)
+			// The filter has no false negatives, so every tuple with a match must
+			// have passed it.
+			require.True(t, stats.OutputTuples >= int64(len(expected)))
+			require.True(t, stats.OutputTuples < stats.InputTuples)
+		})
+	}
+}
+
+func TestBloomFilterPushDown(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	typs := []coltypes.T{coltypes.Int64}
+	ht := makeHashTable(
+		testAllocator, hashTableBucketSize, typs, []uint32{0}, []uint32{0}, false, /* allowNullEquality */
+	)
+	var stats BloomFilterStats
+	f := newBloomFilterOp(testAllocator, nil /* input */, ht, []uint32{0}, &stats)
+
+	// The filter can only be pushed into a scan, possibly below a projection.
+	require.False(t, f.pushDown(newOpTestInput(1 /* batchSize */, nil, typs)))
+	scan := &colBatchScan{rf: &cFetcher{}}
+	require.True(t, f.pushDown(NewSimpleProjectOp(scan, 2 /* numInputCols */, []uint32{1, 0})))
+	rf := scan.rf
+	require.Equal(t, []int{1}, rf.bloomFilter.cols)
+	require.True(t, rf.hasFilters())
+
+	// The second column of the scan is the equality column of the probe side.
+	const numKeys = 100
+	probeVec := testAllocator.NewMemColumn(coltypes.Int64, 2*numKeys)
+	for i := range probeVec.Int64() {
+		probeVec.Int64()[i] = int64(i)
+	}
+	rf.machine.colvecs = []coldata.Vec{testAllocator.NewMemColumn(coltypes.Int64, 0), probeVec}
+
+	// All the rows pass until the filter is built.
+	require.True(t, rf.rowMayJoin(ctx))
+	require.Zero(t, stats.InputTuples)
+
+	buildVec := testAllocator.NewMemColumn(coltypes.Int64, numKeys)
+	for i := range buildVec.Int64() {
+		buildVec.Int64()[i] = int64(i)
+	}
+	hashes := make([]uint64, numKeys)
+	ht.computeHashes(ctx, hashes, []coldata.Vec{buildVec}, numKeys, nil /* sel */)
+	f.buildFilter(hashes)
+
+	for i := 0; i < numKeys; i++ {
+		rf.machine.rowIdx = uint16(i)
+		require.True(t, rf.rowMayJoin(ctx), "false negative for key %d", i)
+	}
+	for i := numKeys; i < 2*numKeys; i++ {
+		rf.machine.rowIdx = uint16(i)
+		rf.rowMayJoin(ctx)
+	}
+	require.Equal(t, int64(2*numKeys), stats.InputTuples)
+	require.True(t, stats.OutputTuples >= numKeys)
+	require.True(t, stats.OutputTuples < stats.InputTuples)
+}
@@ -48,6 +48,14 @@ func (b *bufferedBatch) SetLength(uint16) {
 		"instead, length field should be accessed directly")
 }
 
+// Capacity is not implemented because bufferedBatch grows its columns as
+// tuples are appended to it.
+func (b *bufferedBatch) Capacity() int {
+	execerror.VectorizedInternalPanic("Capacity() should not be called on bufferedBatch")
+	// This code is unreachable, but the compiler cannot infer that.
+	return 0
+}
+
 func (b *bufferedBatch) Width() int {
 	return len(b.colVecs)
 }
@@ -21,7 +21,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/errors"
 )
 
 type defaultBuiltinFuncOperator struct {
@@ -215,13 +214,6 @@ func NewBuiltinFunctionOperator(
 		}, nil
 	default:
 		outputType := funcExpr.ResolvedType()
-		outputPhysType := typeconv.FromColumnType(outputType)
-		if outputPhysType == coltypes.Unhandled {
-			return nil, errors.Errorf(
-				"unsupported output type %q of %s",
-				outputType.String(), funcExpr.String(),
-			)
-		}
 		return &defaultBuiltinFuncOperator{
 			OneInputNode:   NewOneInputNode(input),
 			allocator:      allocator,
@@ -230,7 +222,7 @@ func NewBuiltinFunctionOperator(
 			outputIdx:      outputIdx,
 			columnTypes:    columnTypes,
 			outputType:     outputType,
-			outputPhysType: outputPhysType,
+			outputPhysType: typeconv.FromColumnType(outputType),
 			converter:      typeconv.GetDatumToPhysicalFn(outputType),
 			row:            make(tree.Datums, len(argumentCols)),
 			argumentCols:   argumentCols,
@@ -11,15 +11,19 @@
 package colexec
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRandomizedCast(t *testing.T) {
@@ -37,6 +41,9 @@ func TestRandomizedCast(t *testing.T) {
 	datumAsDecimal := func(d tree.Datum) interface{} {
 		return tree.MustBeDDecimal(d).Decimal
 	}
+	datumAsString := func(d tree.Datum) interface{} {
+		return string(tree.MustBeDString(d))
+	}
 
 	tc := []struct {
 		fromTyp      *types.T
@@ -51,18 +58,25 @@ func TestRandomizedCast(t *testing.T) {
 		{types.Bool, datumAsBool, types.Bool, datumAsBool, false},
 		{types.Bool, datumAsBool, types.Int, datumAsInt, false},
 		{types.Bool, datumAsBool, types.Float, datumAsFloat, false},
+		{types.Bool, datumAsBool, types.Decimal, datumAsDecimal, false},
+		{types.Bool, datumAsBool, types.String, datumAsString, false},
 		// decimal -> t tests
 		{types.Decimal, datumAsDecimal, types.Bool, datumAsBool, false},
+		// We can generate a decimal outside of the range of the integers.
+		{types.Decimal, datumAsDecimal, types.Int, datumAsInt, true},
+		{types.Decimal, datumAsDecimal, types.String, datumAsString, false},
 		// int -> t tests
 		{types.Int, datumAsInt, types.Bool, datumAsBool, false},
 		{types.Int, datumAsInt, types.Float, datumAsFloat, false},
 		{types.Int, datumAsInt, types.Decimal, datumAsDecimal, false},
+		{types.Int, datumAsInt, types.String, datumAsString, false},
 		// float -> t tests
 		{types.Float, datumAsFloat, types.Bool, datumAsBool, false},
 		// We can sometimes generate a float outside of the range of the integers,
 		// so we want to retry with generation if that occurs.
 		{types.Float, datumAsFloat, types.Int, datumAsInt, true},
 		{types.Float, datumAsFloat, types.Decimal, datumAsDecimal, false},
+		{types.Float, datumAsFloat, types.String, datumAsString, false},
 	}
 
 	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
@@ -99,8 +113,30 @@ func TestRandomizedCast(t *testing.T) {
 			}
 			runTests(t, []tuples{input}, output, orderedVerifier,
 				func(input []Operator) (Operator, error) {
-					return GetCastOperator(testAllocator, input[0], 0 /* inputIdx*/, 1 /* resultIdx */, c.fromTyp, c.toTyp)
+					return GetCastOperator(testAllocator, evalCtx, input[0], 0 /* inputIdx*/, 1 /* resultIdx */, c.fromTyp, c.toTyp)
 				})
 		})
 	}
 }
+
+func TestCastOpDatumError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	op, err := GetCastOperator(
+		testAllocator, evalCtx, newOpTestInput(1 /* batchSize */, tuples{{"1"}, {"a"}}, nil /* typs */),
+		0 /* inputIdx*/, 1 /* resultIdx */, types.String, types.Int,
+	)
+	require.NoError(t, err)
+	op.Init()
+	ctx := context.Background()
+	b := op.Next(ctx)
+	require.Equal(t, int64(1), b.ColVec(1).Int64()[0])
+	// The error must be the same as the one returned by the row-by-row engine.
+	_, expectedErr := tree.PerformCast(evalCtx, tree.NewDString("a"), types.Int)
+	require.Error(t, expectedErr)
+	err = execerror.CatchVectorizedRuntimeError(func() { op.Next(ctx) })
+	require.Error(t, err)
+	require.Equal(t, expectedErr.Error(), err.Error())
+	require.Equal(t, pgerror.GetPGCode(expectedErr), pgerror.GetPGCode(err))
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	semtypes "github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/pkg/errors"
 )
@@ -71,8 +72,14 @@ func _FROM_TYPE_SLICE(col, i, j interface{}) interface{} {
 // Use execgen package to remove unused import warning.
 var _ interface{} = execgen.UNSAFEGET
 
+// GetCastOperator returns an operator that casts the column at index colIdx
+// from fromType to toType and writes the result at index resultIdx. Casts
+// between the numeric types and BOOL use the generated kernels that operate on
+// the physical representation directly, and all other casts are performed by
+// converting the values into datums (see castOpDatum).
 func GetCastOperator(
 	allocator *Allocator,
+	evalCtx *tree.EvalContext,
 	input Operator,
 	colIdx int,
 	resultIdx int,
@@ -88,6 +95,9 @@ func GetCastOperator(
 			toType:       typeconv.FromColumnType(toType),
 		}, nil
 	}
+	if !isNativeCast(fromType, toType) {
+		return newCastOpDatum(allocator, evalCtx, input, colIdx, resultIdx, fromType, toType)
+	}
 	switch from := typeconv.FromColumnType(fromType); from {
 	// {{ range $typ, $overloads := . }}
 	case coltypes._ALLTYPES:
@@ -106,12 +116,122 @@ func GetCastOperator(
 			// {{end}}
 			// {{end}}
 		default:
-			return nil, errors.Errorf("unhandled cast FROM -> TO type: %s -> %s", from, to)
+			return newCastOpDatum(allocator, evalCtx, input, colIdx, resultIdx, fromType, toType)
 		}
 		// {{end}}
 	default:
-		return nil, errors.Errorf("unhandled FROM type: %s", from)
+		return newCastOpDatum(allocator, evalCtx, input, colIdx, resultIdx, fromType, toType)
+	}
+}
+
+// isNativeCast returns whether the cast from fromType to toType only depends on
+// the physical representation of the values, in which case it can be performed
+// by a generated kernel. Other casts, for example the ones that parse or format
+// strings, need the semantics of tree.PerformCast.
+func isNativeCast(fromType, toType *semtypes.T) bool {
+	for _, t := range []*semtypes.T{fromType, toType} {
+		switch t.Family() {
+		case semtypes.BoolFamily, semtypes.IntFamily, semtypes.FloatFamily, semtypes.DecimalFamily:
+		default:
+			return false
+		}
+	}
+	// Casts to a DECIMAL with a precision have to limit the width of the result,
+	// which the generated kernels don't do.
+	return toType.Family() != semtypes.DecimalFamily || toType.Precision() == 0
+}
+
+// castOpDatum is the cast operator used for the casts that don't have a
+// generated kernel. It converts each value into a datum, casts it using
+// tree.PerformCast and converts the result back, so it supports all the casts
+// (and returns the same errors) that the row-by-row engine does.
+type castOpDatum struct {
+	OneInputNode
+	allocator  *Allocator
+	evalCtx    *tree.EvalContext
+	colIdx     int
+	outputIdx  int
+	fromType   *semtypes.T
+	toType     *semtypes.T
+	toPhysType coltypes.T
+	converter  func(tree.Datum) (interface{}, error)
+
+	da sqlbase.DatumAlloc
+}
+
+var _ Operator = &castOpDatum{}
+
+func newCastOpDatum(
+	allocator *Allocator,
+	evalCtx *tree.EvalContext,
+	input Operator,
+	colIdx int,
+	resultIdx int,
+	fromType *semtypes.T,
+	toType *semtypes.T,
+) (Operator, error) {
+	return &castOpDatum{
+		OneInputNode: NewOneInputNode(input),
+		allocator:    allocator,
+		evalCtx:      evalCtx,
+		colIdx:       colIdx,
+		outputIdx:    resultIdx,
+		fromType:     fromType,
+		toType:       toType,
+		toPhysType:   typeconv.FromColumnType(toType),
+		converter:    typeconv.GetDatumToPhysicalFn(toType),
+	}, nil
+}
+
+func (c *castOpDatum) Init() {
+	c.input.Init()
+}
+
+func (c *castOpDatum) Next(ctx context.Context) coldata.Batch {
+	batch := c.input.Next(ctx)
+	if c.outputIdx == batch.Width() {
+		c.allocator.AppendColumn(batch, c.toPhysType)
+	}
+	n := batch.Length()
+	if n == 0 {
+		return batch
 	}
+	vec := batch.ColVec(c.colIdx)
+	projVec := batch.ColVec(c.outputIdx)
+	sel := batch.Selection()
+	c.allocator.PerformOperation(
+		[]coldata.Vec{projVec},
+		func() {
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				d := PhysicalTypeColElemToDatum(vec, rowIdx, c.da, c.fromType)
+				if d == tree.DNull {
+					projVec.Nulls().SetNull(rowIdx)
+					continue
+				}
+				res, err := tree.PerformCast(c.evalCtx, d, c.toType)
+				if err != nil {
+					execerror.NonVectorizedPanic(err)
+				}
+				if res == tree.DNull {
+					projVec.Nulls().SetNull(rowIdx)
+					continue
+				}
+				converted, err := c.converter(res)
+				if err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				coldata.SetValueAt(projVec, converted, rowIdx, c.toPhysType)
+			}
+		},
+	)
+	// Although we didn't change the length of the batch, it is necessary to set
+	// the length anyway (this helps maintaining the invariant of flat bytes).
+	batch.SetLength(n)
+	return batch
 }
 
 type castOpNullAny struct {
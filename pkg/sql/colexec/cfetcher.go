@@ -194,6 +194,30 @@ type cFetcher struct {
 	// fetcher is the underlying fetcher that provides KVs.
 	fetcher *row.KVFetcher
 
+	// typs are the types of the columns of the output batches.
+	typs []coltypes.T
+
+	// memoryLimit is the soft limit on the memory footprint of the output batch.
+	// The capacity of the batch starts small and is grown as long as the batch
+	// stays within this limit (see Allocator.ResetMaybeReallocate).
+	memoryLimit int64
+
+	// keyFilters and valueFilters are the filters that a row must pass to be
+	// selected in the output batch (see setFilters). keyFilters only reference
+	// columns that are decoded from the index key, so they are evaluated before
+	// the values of the row are decoded.
+	keyFilters, valueFilters []cFetcherFilter
+
+	// bloomFilter, if op is set, is the bloom filter of a hash joiner that was
+	// pushed down into the fetcher (see setBloomFilter). It is applied to the
+	// columns cols, after the index key if onKey is set and once the row is
+	// complete otherwise.
+	bloomFilter struct {
+		op    *bloomFilterOp
+		cols  []int
+		onKey bool
+	}
+
 	// machine contains fields that get updated during the run of the fetcher.
 	machine struct {
 		// state is the queue of next states of the state machine. The 0th entry
@@ -203,6 +227,12 @@ type cFetcher struct {
 		// within the current batch. It's incremented as soon as we detect that a row
 		// is finished.
 		rowIdx uint16
+		// rowFiltered is set when the current row failed one of the keyFilters,
+		// in which case its values aren't decoded.
+		rowFiltered bool
+		// numSelected is the number of rows in the current batch that passed the
+		// filters. It is only maintained when there are filters.
+		numSelected uint16
 		// curSpan is the current span that the kv fetcher just returned data from.
 		curSpan roachpb.Span
 		// nextKV is the kv to process next.
@@ -241,9 +271,14 @@ type cFetcher struct {
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
 // index.
 func (rf *cFetcher) Init(
-	allocator *Allocator, reverse, returnRangeInfo bool, isCheck bool, tables ...row.FetcherTableArgs,
+	allocator *Allocator,
+	memoryLimit int64,
+	reverse, returnRangeInfo bool,
+	isCheck bool,
+	tables ...row.FetcherTableArgs,
 ) error {
 	rf.adapter.allocator = allocator
+	rf.memoryLimit = memoryLimit
 	if len(tables) == 0 {
 		return errors.AssertionFailedf("no tables to fetch from")
 	}
@@ -287,14 +322,10 @@ func (rf *cFetcher) Init(
 	typs := make([]coltypes.T, len(colDescriptors))
 	for i := range typs {
 		typs[i] = typeconv.FromColumnType(&colDescriptors[i].Type)
-		if typs[i] == coltypes.Unhandled && tableArgs.ValNeededForCol.Contains(i) {
-			// Only return an error if the type is unhandled and needed. If not needed,
-			// a placeholder Vec will be created.
-			return errors.Errorf("unhandled type %+v", &colDescriptors[i].Type)
-		}
 	}
 
-	rf.machine.batch = allocator.NewMemBatch(typs)
+	rf.typs = typs
+	rf.machine.batch, _ = allocator.ResetMaybeReallocate(typs, nil /* oldBatch */, memoryLimit)
 	rf.machine.colvecs = rf.machine.batch.ColVecs()
 
 	var err error
@@ -471,6 +502,8 @@ func (rf *cFetcher) StartScan(
 	}
 	rf.fetcher = f
 	rf.machine.lastRowPrefix = nil
+	// The batch might still contain the rows of a previous scan.
+	rf.machine.batch.ResetInternalBatch()
 	rf.machine.state[0] = stateInitFetch
 	return nil
 }
@@ -562,6 +595,17 @@ const debugState = false
 // NextBatch is nextBatch with the addition of memory accounting.
 func (rf *cFetcher) NextBatch(ctx context.Context) (coldata.Batch, error) {
 	rf.adapter.ctx = ctx
+	if rf.machine.state[0] == stateResetBatch {
+		// The previous batch was filled up, so we might be able to use a batch of
+		// larger capacity.
+		var reallocated bool
+		rf.machine.batch, reallocated = rf.adapter.allocator.ResetMaybeReallocate(
+			rf.typs, rf.machine.batch, rf.memoryLimit,
+		)
+		if reallocated {
+			rf.machine.colvecs = rf.machine.batch.ColVecs()
+		}
+	}
 	rf.adapter.allocator.PerformOperation(
 		rf.machine.colvecs,
 		rf.nextAdapter,
@@ -573,10 +617,11 @@ func (rf *cFetcher) nextAdapter() {
 	rf.adapter.batch, rf.adapter.err = rf.nextBatch(rf.adapter.ctx)
 }
 
-// nextBatch processes keys until we complete one batch of rows,
-// coldata.BatchSize() in length, which are returned in columnar format as a
-// coldata.Batch. The batch contains one Vec per table column, regardless of
-// the index used; columns that are not needed (as per neededCols) are empty.
+// nextBatch processes keys until we complete one batch of rows (whose length
+// is the capacity of the current batch, at most coldata.BatchSize()), which
+// are returned in columnar format as a coldata.Batch. The batch contains one
+// Vec per table column, regardless of the index used; columns that are not
+// needed (as per neededCols) are empty.
 // The Batch should not be modified and is only valid until the next call.
 // When there are no more rows, the Batch.Length is 0.
 func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
@@ -624,9 +669,7 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 
 		case stateResetBatch:
 			for _, colvec := range rf.machine.colvecs {
-				if colvec.Type() != coltypes.Unhandled {
-					colvec.Nulls().UnsetNulls()
-				}
+				colvec.Nulls().UnsetNulls()
 			}
 			rf.machine.batch.ResetInternalBatch()
 			rf.shiftState()
@@ -649,6 +692,7 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 					indexOrds = rf.table.allIndexColOrdinals
 				}
 				key, matches, foundNull, err = colencoding.DecodeIndexKeyToCols(
+					&rf.table.da,
 					rf.machine.colvecs,
 					rf.machine.rowIdx,
 					rf.table.desc,
@@ -730,18 +774,24 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 				rf.machine.lastRowPrefix = rf.machine.nextKV.Key[:prefixLen+(origRemainingBytesLen-len(remainingBytes))]
 			}
 
-			familyID, err := rf.getCurrentColumnFamilyID()
-			if err != nil {
-				return nil, err
-			}
-			rf.machine.remainingValueColsByIdx.CopyFrom(rf.table.neededValueColsByIdx)
-			// Process the current KV's value component.
-			prettyKey, prettyVal, err := rf.processValue(ctx, familyID)
-			if err != nil {
-				return nil, err
-			}
-			if rf.traceKV {
-				log.VEventf(ctx, 2, "fetched: %s -> %s", prettyKey, prettyVal)
+			// The row doesn't need to be decoded any further if it is filtered out
+			// by its index key. Its KVs are still consumed to find the next row.
+			rf.machine.rowFiltered = !rf.rowMatches(rf.keyFilters) ||
+				(rf.bloomFilter.onKey && !rf.rowMayJoin(ctx))
+			if !rf.machine.rowFiltered {
+				familyID, err := rf.getCurrentColumnFamilyID()
+				if err != nil {
+					return nil, err
+				}
+				rf.machine.remainingValueColsByIdx.CopyFrom(rf.table.neededValueColsByIdx)
+				// Process the current KV's value component.
+				prettyKey, prettyVal, err := rf.processValue(ctx, familyID)
+				if err != nil {
+					return nil, err
+				}
+				if rf.traceKV {
+					log.VEventf(ctx, 2, "fetched: %s -> %s", prettyKey, prettyVal)
+				}
 			}
 			if len(rf.table.desc.Families) == 1 {
 				rf.machine.state[0] = stateFinalizeRow
@@ -816,13 +866,15 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 				return nil, err
 			}
 
-			// Process the current KV's value component.
-			prettyKey, prettyVal, err := rf.processValue(ctx, familyID)
-			if err != nil {
-				return nil, err
-			}
-			if rf.traceKV {
-				log.VEventf(ctx, 2, "fetched: %s -> %s", prettyKey, prettyVal)
+			if !rf.machine.rowFiltered {
+				// Process the current KV's value component.
+				prettyKey, prettyVal, err := rf.processValue(ctx, familyID)
+				if err != nil {
+					return nil, err
+				}
+				if rf.traceKV {
+					log.VEventf(ctx, 2, "fetched: %s -> %s", prettyKey, prettyVal)
+				}
 			}
 
 			if familyID == rf.table.maxColumnFamilyID {
@@ -836,24 +888,38 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 
 		case stateFinalizeRow:
 			// We're finished with a row. Bump the row index, fill the row in with
-			// nulls if necessary, emit the batch if necessary, and move to the next
-			// state.
-			if err := rf.fillNulls(); err != nil {
-				return nil, err
+			// nulls if necessary, select it if it passes the filters, emit the
+			// batch if necessary, and move to the next state.
+			if !rf.machine.rowFiltered {
+				if err := rf.fillNulls(); err != nil {
+					return nil, err
+				}
+				if rf.hasFilters() && rf.rowMatches(rf.valueFilters) &&
+					(rf.bloomFilter.onKey || rf.rowMayJoin(ctx)) {
+					rf.machine.batch.SetSelection(true)
+					rf.machine.batch.Selection()[rf.machine.numSelected] = rf.machine.rowIdx
+					rf.machine.numSelected++
+				}
 			}
+			rf.machine.rowFiltered = false
 			rf.machine.rowIdx++
 			rf.shiftState()
-			if rf.machine.rowIdx >= coldata.BatchSize() {
+			if int(rf.machine.rowIdx) >= rf.machine.batch.Capacity() {
 				rf.pushState(stateResetBatch)
-				rf.machine.batch.SetLength(rf.machine.rowIdx)
-				rf.machine.rowIdx = 0
+				if rf.hasFilters() && rf.machine.numSelected == 0 {
+					// None of the rows passed the filters. Rather than emitting an
+					// empty batch, which would signal the end of the scan, reuse the
+					// batch for the next rows.
+					rf.machine.rowIdx = 0
+					continue
+				}
+				rf.setBatchLength()
 				return rf.machine.batch, nil
 			}
 
 		case stateEmitLastBatch:
 			rf.machine.state[0] = stateFinished
-			rf.machine.batch.SetLength(rf.machine.rowIdx)
-			rf.machine.rowIdx = 0
+			rf.setBatchLength()
 			return rf.machine.batch, nil
 
 		case stateFinished:
@@ -862,6 +928,87 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 	}
 }
 
+// setFilters sets the filters that the rows must pass to be selected in the
+// output batches. The filters must only reference needed columns.
+func (rf *cFetcher) setFilters(filters []cFetcherFilter) {
+	rf.keyFilters, rf.valueFilters = rf.keyFilters[:0], rf.valueFilters[:0]
+	for _, f := range filters {
+		if rf.isKeyCol(f.colIdx) {
+			rf.keyFilters = append(rf.keyFilters, f)
+		} else {
+			rf.valueFilters = append(rf.valueFilters, f)
+		}
+	}
+}
+
+// isKeyCol returns whether the final value of the column is decoded from the
+// index key. The values of composite columns decoded from the key might not be
+// the final ones, so they can only be filtered once the row is complete.
+func (rf *cFetcher) isKeyCol(colIdx int) bool {
+	if rf.table.compositeIndexColOrdinals.Contains(colIdx) {
+		return false
+	}
+	for _, idx := range rf.table.indexColOrdinals {
+		if idx == colIdx {
+			return true
+		}
+	}
+	return false
+}
+
+// setBloomFilter makes the fetcher discard the rows that the bloom filter of
+// a hash joiner rules out. cols are the ordinals of the equality columns of the
+// joiner, which must be needed columns.
+func (rf *cFetcher) setBloomFilter(op *bloomFilterOp, cols []int) {
+	rf.bloomFilter.op, rf.bloomFilter.cols = op, cols
+	rf.bloomFilter.onKey = true
+	for _, colIdx := range cols {
+		if !rf.isKeyCol(colIdx) {
+			rf.bloomFilter.onKey = false
+			break
+		}
+	}
+}
+
+func (rf *cFetcher) hasFilters() bool {
+	return len(rf.keyFilters) > 0 || len(rf.valueFilters) > 0 || rf.bloomFilter.op != nil
+}
+
+// rowMayJoin returns false if the bloom filter rules out the current row.
+func (rf *cFetcher) rowMayJoin(ctx context.Context) bool {
+	if rf.bloomFilter.op == nil {
+		return true
+	}
+	return rf.bloomFilter.op.mayContainRow(
+		ctx, rf.machine.colvecs, rf.bloomFilter.cols, rf.machine.rowIdx,
+	)
+}
+
+// rowMatches returns whether the current row passes all the given filters.
+func (rf *cFetcher) rowMatches(filters []cFetcherFilter) bool {
+	for i := range filters {
+		if !filters[i].matches(rf.machine.colvecs[filters[i].colIdx], rf.machine.rowIdx) {
+			return false
+		}
+	}
+	return true
+}
+
+// setBatchLength sets the length of the output batch before it is emitted and
+// prepares the machine for the next batch. If there are filters, the output
+// batch only contains the selected rows.
+func (rf *cFetcher) setBatchLength() {
+	// The length must first cover all the rows that were written to so that
+	// the offsets of the Bytes vectors are updated for all of them.
+	rf.machine.batch.SetLength(rf.machine.rowIdx)
+	if rf.hasFilters() {
+		rf.machine.batch.SetSelection(true)
+		rf.machine.batch.SetLength(rf.machine.numSelected)
+		rf.machine.numSelected = 0
+	}
+	rf.machine.rowIdx = 0
+}
+
 // shiftState shifts the state queue to the left, removing the first element and
 // clearing the last element.
 func (rf *cFetcher) shiftState() {
@@ -977,6 +1124,7 @@ func (rf *cFetcher) processValue(
 					extraColOrds = table.allExtraValColOrdinals
 				}
 				valueBytes, _, err = colencoding.DecodeKeyValsToCols(
+					&table.da,
 					rf.machine.colvecs,
 					rf.machine.rowIdx,
 					extraColOrds,
@@ -1065,7 +1213,9 @@ func (rf *cFetcher) processValueSingle(
 				return prettyKey, "", nil
 			}
 			typ := &table.cols[idx].Type
-			err := colencoding.UnmarshalColumnValueToCol(rf.machine.colvecs[idx], rf.machine.rowIdx, typ, val)
+			err := colencoding.UnmarshalColumnValueToCol(
+				&table.da, rf.machine.colvecs[idx], rf.machine.rowIdx, typ, val,
+			)
 			if err != nil {
 				return "", "", err
 			}
@@ -1155,8 +1305,9 @@ func (rf *cFetcher) processValueBytes(
 		vec := rf.machine.colvecs[idx]
 
 		valTyp := &table.cols[idx].Type
-		valueBytes, err = colencoding.DecodeTableValueToCol(vec, rf.machine.rowIdx, typ, dataOffset, valTyp,
-			valueBytes)
+		valueBytes, err = colencoding.DecodeTableValueToCol(
+			&table.da, vec, rf.machine.rowIdx, typ, dataOffset, valTyp, valueBytes,
+		)
 		if err != nil {
 			return "", "", err
 		}
@@ -1223,6 +1374,17 @@ func (rf *cFetcher) GetRangesInfo() []roachpb.RangeInfo {
 	return rf.fetcher.GetRangesInfo()
 }
 
+// GetContentionEvents returns the contention events that the KV layer reported
+// for the batches fetched so far.
+func (rf *cFetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	f := rf.fetcher
+	if f == nil {
+		// Not yet initialized.
+		return nil
+	}
+	return f.GetContentionEvents()
+}
+
 // getCurrentColumnFamilyID returns the column family id of the key in
 // rf.machine.nextKV.Key.
 func (rf *cFetcher) getCurrentColumnFamilyID() (sqlbase.FamilyID, error) {
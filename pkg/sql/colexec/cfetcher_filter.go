@@ -0,0 +1,236 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// cFetcherFilterPushdownEnabled controls whether the simple comparisons of
+// the filter of a TableReader are evaluated by the cFetcher while it decodes
+// the KVs instead of by selection operators planned on top of the
+// colBatchScan.
+var cFetcherFilterPushdownEnabled = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting(
+		"sql.distsql.vectorized_scan_filter_pushdown.enabled",
+		"if set, vectorized scans evaluate simple comparisons of columns with constants while decoding rows",
+		true,
+	)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+
+// cFetcherFilter is a comparison of a column with a non-NULL constant that the
+// cFetcher evaluates on the rows it decodes. Only the constant field that
+// corresponds to the physical type of the column is set.
+type cFetcherFilter struct {
+	// colIdx is the ordinal of the column in the table.
+	colIdx int
+	// op is one of EQ, NE, LT, LE, GT and GE, with the column on the left.
+	op tree.ComparisonOperator
+
+	intConst   int64
+	floatConst float64
+	bytesConst []byte
+}
+
+// matches returns whether the filter passes on the rowIdx'th value of vec.
+// NULL values never pass.
+func (f *cFetcherFilter) matches(vec coldata.Vec, rowIdx uint16) bool {
+	if vec.MaybeHasNulls() && vec.Nulls().NullAt(rowIdx) {
+		return false
+	}
+	var cmp int
+	switch vec.Type() {
+	case coltypes.Int16:
+		cmp = compareInts(int64(vec.Int16()[rowIdx]), f.intConst)
+	case coltypes.Int32:
+		cmp = compareInts(int64(vec.Int32()[rowIdx]), f.intConst)
+	case coltypes.Int64:
+		cmp = compareInts(vec.Int64()[rowIdx], f.intConst)
+	case coltypes.Float64:
+		cmp = compareFloats(vec.Float64()[rowIdx], f.floatConst)
+	case coltypes.Bytes:
+		cmp = bytes.Compare(vec.Bytes().Get(int(rowIdx)), f.bytesConst)
+	default:
+		// extractCFetcherFilters never creates filters on other types.
+		return false
+	}
+	switch f.op {
+	case tree.EQ:
+		return cmp == 0
+	case tree.NE:
+		return cmp != 0
+	case tree.LT:
+		return cmp < 0
+	case tree.LE:
+		return cmp <= 0
+	case tree.GT:
+		return cmp > 0
+	case tree.GE:
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareInts(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareFloats compares floats the way SQL does: NaN is smaller than any
+// other value and equal to itself.
+func compareFloats(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	} else if a == b || math.IsNaN(a) && math.IsNaN(b) {
+		return 0
+	} else if math.IsNaN(a) {
+		return -1
+	}
+	return 1
+}
+
+// pushDownCFetcherFilters extracts the conjuncts of the filter of post that
+// the cFetcher can evaluate. It returns them along with the post-processing
+// spec that still needs to be applied on top of the colBatchScan: a copy of
+// post with the remaining conjuncts as its filter, or post itself if nothing
+// was extracted. post is never modified because the specs of a flow can be
+// planned more than once.
+func pushDownCFetcherFilters(
+	flowCtx *execinfra.FlowCtx, post *execinfrapb.PostProcessSpec, typs []types.T,
+) ([]cFetcherFilter, *execinfrapb.PostProcessSpec, error) {
+	if post.Filter.Empty() || !cFetcherFilterPushdownEnabled.GetWithOverrides(
+		&flowCtx.Cfg.Settings.SV, flowCtx.SettingOverrides(),
+	) {
+		return nil, post, nil
+	}
+	var helper execinfra.ExprHelper
+	if err := helper.InitWithCache(
+		flowCtx.ExprCache(), post.Filter, typs, flowCtx.NewEvalCtx(), nil, /* indexVarMap */
+	); err != nil {
+		return nil, nil, err
+	}
+	filters, remaining := extractCFetcherFilters(helper.Expr, typs)
+	if len(filters) == 0 {
+		return nil, post, nil
+	}
+	remainingPost := *post
+	remainingPost.Filter = execinfrapb.Expression{}
+	if remaining != nil {
+		remainingPost.Filter.LocalExpr = remaining
+	}
+	return filters, &remainingPost, nil
+}
+
+// extractCFetcherFilters splits expr into its conjuncts and converts those
+// that compare a column with a constant of the same type family into
+// cFetcherFilters. The conjuncts that can't be converted are returned as
+// remaining, which is nil if all of them were converted.
+func extractCFetcherFilters(
+	expr tree.TypedExpr, typs []types.T,
+) (filters []cFetcherFilter, remaining tree.TypedExpr) {
+	if and, ok := expr.(*tree.AndExpr); ok {
+		leftFilters, leftRemaining := extractCFetcherFilters(and.TypedLeft(), typs)
+		rightFilters, rightRemaining := extractCFetcherFilters(and.TypedRight(), typs)
+		filters = append(leftFilters, rightFilters...)
+		switch {
+		case leftRemaining == nil:
+			remaining = rightRemaining
+		case rightRemaining == nil:
+			remaining = leftRemaining
+		default:
+			remaining = tree.NewTypedAndExpr(leftRemaining, rightRemaining)
+		}
+		return filters, remaining
+	}
+	if f, ok := makeCFetcherFilter(expr, typs); ok {
+		return []cFetcherFilter{f}, nil
+	}
+	return nil, expr
+}
+
+// makeCFetcherFilter converts expr into a cFetcherFilter if it is a supported
+// comparison of a column with a non-NULL constant.
+func makeCFetcherFilter(expr tree.TypedExpr, typs []types.T) (cFetcherFilter, bool) {
+	cmp, ok := expr.(*tree.ComparisonExpr)
+	if !ok {
+		return cFetcherFilter{}, false
+	}
+	op := cmp.Operator
+	switch op {
+	case tree.EQ, tree.NE, tree.LT, tree.LE, tree.GT, tree.GE:
+	default:
+		return cFetcherFilter{}, false
+	}
+	left, right := cmp.TypedLeft(), cmp.TypedRight()
+	if _, ok := left.(tree.Datum); ok {
+		// Normalize the comparison so that the column is on the left.
+		left, right = right, left
+		switch op {
+		case tree.LT:
+			op = tree.GT
+		case tree.LE:
+			op = tree.GE
+		case tree.GT:
+			op = tree.LT
+		case tree.GE:
+			op = tree.LE
+		}
+	}
+	iv, ok := left.(*tree.IndexedVar)
+	if !ok || iv.Idx >= len(typs) {
+		return cFetcherFilter{}, false
+	}
+	f := cFetcherFilter{colIdx: iv.Idx, op: op}
+	switch family := typs[iv.Idx].Family(); d := right.(type) {
+	case *tree.DInt:
+		if family != types.IntFamily {
+			return cFetcherFilter{}, false
+		}
+		f.intConst = int64(*d)
+	case *tree.DFloat:
+		if family != types.FloatFamily {
+			return cFetcherFilter{}, false
+		}
+		f.floatConst = float64(*d)
+	case *tree.DString:
+		if family != types.StringFamily || typs[iv.Idx].Oid() != types.String.Oid() {
+			return cFetcherFilter{}, false
+		}
+		f.bytesConst = []byte(*d)
+	case *tree.DBytes:
+		if family != types.BytesFamily {
+			return cFetcherFilter{}, false
+		}
+		f.bytesConst = []byte(*d)
+	default:
+		// This includes NULL, which never satisfies a comparison and is handled
+		// by the normalization of the expression.
+		return cFetcherFilter{}, false
+	}
+	return f, true
+}
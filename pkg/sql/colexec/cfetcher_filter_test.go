@@ -0,0 +1,104 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCFetcherFilters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	typs := []types.T{*types.Int, *types.Float, *types.String, *types.Decimal, *types.VarChar}
+	col := func(i int) tree.TypedExpr {
+		return tree.NewTypedOrdinalReference(i, &typs[i])
+	}
+	cmp := func(op tree.ComparisonOperator, left, right tree.TypedExpr) tree.TypedExpr {
+		return tree.NewTypedComparisonExpr(op, left, right)
+	}
+	and := func(left, right tree.TypedExpr) tree.TypedExpr {
+		return tree.NewTypedAndExpr(left, right)
+	}
+
+	// All the conjuncts can be pushed down, and comparisons with the constant
+	// on the left are flipped.
+	filters, remaining := extractCFetcherFilters(and(
+		cmp(tree.GT, tree.NewDInt(3), col(0)),
+		and(
+			cmp(tree.LE, col(1), tree.NewDFloat(1.5)),
+			cmp(tree.EQ, col(2), tree.NewDString("foo")),
+		),
+	), typs)
+	require.Nil(t, remaining)
+	require.Equal(t, []cFetcherFilter{
+		{colIdx: 0, op: tree.LT, intConst: 3},
+		{colIdx: 1, op: tree.LE, floatConst: 1.5},
+		{colIdx: 2, op: tree.EQ, bytesConst: []byte("foo")},
+	}, filters)
+
+	// Comparisons of unsupported types, of two columns, and with mismatched
+	// type families remain in the filter.
+	unsupported := []tree.TypedExpr{
+		cmp(tree.EQ, col(3), &tree.DDecimal{}),
+		cmp(tree.EQ, col(4), tree.NewDString("foo")),
+		cmp(tree.LT, col(0), col(0)),
+		cmp(tree.EQ, col(1), tree.NewDInt(1)),
+	}
+	for _, expr := range unsupported {
+		filters, remaining = extractCFetcherFilters(expr, typs)
+		require.Empty(t, filters)
+		require.Equal(t, expr, remaining)
+	}
+	filters, remaining = extractCFetcherFilters(and(
+		unsupported[0], and(cmp(tree.NE, col(0), tree.NewDInt(1)), unsupported[2]),
+	), typs)
+	require.Equal(t, []cFetcherFilter{{colIdx: 0, op: tree.NE, intConst: 1}}, filters)
+	require.Equal(t, tree.NewTypedAndExpr(unsupported[0], unsupported[2]), remaining)
+}
+
+func TestCFetcherFilterMatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ints := coldata.NewMemColumn(coltypes.Int32, 3)
+	copy(ints.Int32(), []int32{1, 2, 3})
+	ints.Nulls().SetNull(2)
+	f := cFetcherFilter{colIdx: 0, op: tree.GE, intConst: 2}
+	require.False(t, f.matches(ints, 0))
+	require.True(t, f.matches(ints, 1))
+	// NULLs never pass.
+	require.False(t, f.matches(ints, 2))
+
+	floats := coldata.NewMemColumn(coltypes.Float64, 3)
+	copy(floats.Float64(), []float64{math.NaN(), -1, 1})
+	f = cFetcherFilter{colIdx: 0, op: tree.LT, floatConst: 0}
+	// NaN is smaller than any other value.
+	require.True(t, f.matches(floats, 0))
+	require.True(t, f.matches(floats, 1))
+	require.False(t, f.matches(floats, 2))
+	f = cFetcherFilter{colIdx: 0, op: tree.EQ, floatConst: math.NaN()}
+	require.True(t, f.matches(floats, 0))
+	require.False(t, f.matches(floats, 1))
+
+	strs := coldata.NewMemColumn(coltypes.Bytes, 2)
+	strs.Bytes().Set(0, []byte("bar"))
+	strs.Bytes().Set(1, []byte("foo"))
+	f = cFetcherFilter{colIdx: 0, op: tree.NE, bytesConst: []byte("foo")}
+	require.True(t, f.matches(strs, 0))
+	require.False(t, f.matches(strs, 1))
+}
@@ -32,6 +32,13 @@ import (
 // should get rid off table readers entirely. We will have to be careful about
 // propagating the metadata though.
 
+// KVReader is implemented by the Operators that read from the KV layer.
+type KVReader interface {
+	// GetContentionEvents returns the contention events that the KV layer
+	// reported for the requests issued by the Operator so far.
+	GetContentionEvents() []roachpb.ContentionEvent
+}
+
 // colBatchScan is the exec.Operator implementation of TableReader. It reads a table
 // from kv, presenting it as coldata.Batches via the exec.Operator interface.
 type colBatchScan struct {
@@ -49,6 +56,7 @@ type colBatchScan struct {
 }
 
 var _ Operator = &colBatchScan{}
+var _ KVReader = &colBatchScan{}
 
 func (s *colBatchScan) Init() {
 	s.ctx = context.Background()
@@ -69,7 +77,9 @@ func (s *colBatchScan) Next(ctx context.Context) coldata.Batch {
 	if err != nil {
 		execerror.VectorizedInternalPanic(err)
 	}
-	bat.SetSelection(false)
+	if !s.rf.hasFilters() {
+		bat.SetSelection(false)
+	}
 	return bat
 }
 
@@ -91,44 +101,66 @@ func (s *colBatchScan) DrainMeta(ctx context.Context) []execinfrapb.ProducerMeta
 	if tfs := execinfra.GetLeafTxnFinalState(ctx, s.flowCtx.Txn); tfs != nil {
 		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
 	}
+	if events := s.GetContentionEvents(); len(events) > 0 {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{ContentionEvents: events})
+	}
 	return trailingMeta
 }
 
-// newColBatchScan creates a new colBatchScan operator.
+// GetContentionEvents is part of the KVReader interface.
+func (s *colBatchScan) GetContentionEvents() []roachpb.ContentionEvent {
+	if !s.init {
+		return nil
+	}
+	return s.rf.GetContentionEvents()
+}
+
+// newColBatchScan creates a new colBatchScan operator. The simple comparisons
+// of the filter of post are evaluated by the colBatchScan itself, so the
+// returned post-processing spec, which doesn't contain them, is the one that
+// must be applied on top of it.
 func newColBatchScan(
 	allocator *Allocator,
 	flowCtx *execinfra.FlowCtx,
 	spec *execinfrapb.TableReaderSpec,
 	post *execinfrapb.PostProcessSpec,
-) (*colBatchScan, error) {
+) (*colBatchScan, *execinfrapb.PostProcessSpec, error) {
 	if flowCtx.NodeID == 0 {
-		return nil, errors.Errorf("attempting to create a colBatchScan with uninitialized NodeID")
+		return nil, nil, errors.Errorf("attempting to create a colBatchScan with uninitialized NodeID")
 	}
 
 	limitHint := execinfra.LimitHint(spec.LimitHint, post)
 
 	returnMutations := spec.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
 	typs := spec.Table.ColumnTypesWithMutations(returnMutations)
-	helper := execinfra.ProcOutputHelper{}
+	filters, post, err := pushDownCFetcherFilters(flowCtx, post, typs)
+	if err != nil {
+		return nil, nil, err
+	}
+	helper := execinfra.ProcOutputHelper{ExprCache: flowCtx.ExprCache()}
 	if err := helper.Init(
 		post,
 		typs,
 		flowCtx.NewEvalCtx(),
 		nil,
 	); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	neededColumns := helper.NeededColumns()
+	for _, f := range filters {
+		neededColumns.Add(f.colIdx)
+	}
 
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
 	fetcher := cFetcher{}
 	if _, _, err := initCRowFetcher(
-		allocator, &fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
-		neededColumns, spec.IsCheck, spec.Visibility,
+		allocator, execinfra.GetWorkMemLimit(flowCtx), &fetcher, &spec.Table, int(spec.IndexIdx),
+		columnIdxMap, spec.Reverse, neededColumns, spec.IsCheck, spec.Visibility,
 	); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	fetcher.setFilters(filters)
 
 	nSpans := len(spec.Spans)
 	spans := make(roachpb.Spans, nSpans)
@@ -141,12 +173,13 @@ func newColBatchScan(
 		rf:         &fetcher,
 		limitHint:  limitHint,
 		maxResults: spec.MaxResults,
-	}, nil
+	}, post, nil
 }
 
 // initCRowFetcher initializes a row.cFetcher. See initRowFetcher.
 func initCRowFetcher(
 	allocator *Allocator,
+	memoryLimit int64,
 	fetcher *cFetcher,
 	desc *sqlbase.TableDescriptor,
 	indexIdx int,
@@ -175,7 +208,7 @@ func initCRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := fetcher.Init(
-		allocator, reverseScan, true /* returnRangeInfo */, isCheck, tableArgs,
+		allocator, memoryLimit, reverseScan, true /* returnRangeInfo */, isCheck, tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
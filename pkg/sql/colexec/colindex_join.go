@@ -0,0 +1,212 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/pkg/errors"
+)
+
+// colIndexJoinBatchSize is the number of spans that colIndexJoin accumulates
+// from its input before it looks them up in the primary index. It matches the
+// batch size of the row-by-row index joiner.
+const colIndexJoinBatchSize = 10000
+
+// colIndexJoin is the exec.Operator implementation of the index joiner. It
+// performs a join between a secondary index, which is read by its input, and
+// the primary index of the same table in order to retrieve the columns that
+// are not stored in the secondary index. The first columns of the input must
+// be the primary key columns of the table.
+//
+// The primary key spans of whole input batches are accumulated and then
+// looked up with a single cFetcher scan whose output batches are returned as
+// is, so the output contains the table columns like the output of
+// colBatchScan.
+type colIndexJoin struct {
+	OneInputNode
+
+	flowCtx *execinfra.FlowCtx
+	rf      *cFetcher
+	// inputTypes are the types of the primary key columns of the input.
+	inputTypes []types.T
+	// batchSize is the number of spans that are looked up at once. It's not a
+	// constant so that it can be lowered in tests.
+	batchSize int
+
+	spanBuilder *span.Builder
+	// spans is the batch of spans that are looked up next.
+	spans roachpb.Spans
+	// scanning is true while the rows of the last batch of spans are being
+	// returned.
+	scanning bool
+	// inputDone is true once the input has been exhausted.
+	inputDone bool
+
+	row   sqlbase.EncDatumRow
+	alloc sqlbase.DatumAlloc
+}
+
+var _ Operator = &colIndexJoin{}
+
+func (s *colIndexJoin) Init() {
+	s.input.Init()
+}
+
+func (s *colIndexJoin) Next(ctx context.Context) coldata.Batch {
+	for {
+		if s.scanning {
+			bat, err := s.rf.NextBatch(ctx)
+			if err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			if bat.Length() > 0 {
+				if !s.rf.hasFilters() {
+					bat.SetSelection(false)
+				}
+				return bat
+			}
+			s.scanning = false
+		}
+		if s.inputDone {
+			return coldata.ZeroBatch
+		}
+		s.spans = s.spans[:0]
+		for !s.inputDone && len(s.spans) < s.batchSize {
+			batch := s.input.Next(ctx)
+			if batch.Length() == 0 {
+				s.inputDone = true
+				break
+			}
+			s.appendSpans(batch)
+		}
+		if len(s.spans) == 0 {
+			return coldata.ZeroBatch
+		}
+		if err := s.rf.StartScan(
+			ctx, s.flowCtx.Txn, s.spans, false /* limitBatches */, 0, /* limitHint */
+			s.flowCtx.TraceKV,
+		); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		s.scanning = true
+	}
+}
+
+// SetBatchSize sets the desired batch size. It should only be used in tests.
+func (s *colIndexJoin) SetBatchSize(batchSize int) {
+	s.batchSize = batchSize
+}
+
+// appendSpans appends the primary key spans of all the rows of batch to the
+// batch of spans to look up.
+func (s *colIndexJoin) appendSpans(batch coldata.Batch) {
+	numKeyCols := len(s.inputTypes)
+	n := batch.Length()
+	sel := batch.Selection()
+	for i := uint16(0); i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		for j := range s.inputTypes {
+			datum := PhysicalTypeColElemToDatum(batch.ColVec(j), rowIdx, s.alloc, &s.inputTypes[j])
+			s.row[j] = sqlbase.DatumToEncDatum(&s.inputTypes[j], datum)
+		}
+		span, containsNull, err := s.spanBuilder.SpanFromEncDatums(s.row, numKeyCols)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		s.spans = append(
+			s.spans, s.spanBuilder.MaybeSplitSpanIntoSeparateFamilies(span, numKeyCols, containsNull)...,
+		)
+	}
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (s *colIndexJoin) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, s.flowCtx.Txn); tfs != nil {
+		return []execinfrapb.ProducerMetadata{{LeafTxnFinalState: tfs}}
+	}
+	return nil
+}
+
+// newColIndexJoin creates a new colIndexJoin operator. Like newColBatchScan,
+// it evaluates the simple comparisons of the filter of post itself and returns
+// the post-processing spec that must be applied on top of it.
+func newColIndexJoin(
+	allocator *Allocator,
+	flowCtx *execinfra.FlowCtx,
+	input Operator,
+	inputTypes []types.T,
+	spec *execinfrapb.JoinReaderSpec,
+	post *execinfrapb.PostProcessSpec,
+) (*colIndexJoin, *execinfrapb.PostProcessSpec, error) {
+	if spec.IndexIdx != 0 {
+		return nil, nil, errors.Errorf("index join must be against primary index")
+	}
+	numKeyCols := len(spec.Table.PrimaryIndex.ColumnIDs)
+	if len(inputTypes) < numKeyCols {
+		return nil, nil, errors.Errorf(
+			"index join input has %d columns, expected at least %d", len(inputTypes), numKeyCols)
+	}
+
+	returnMutations := spec.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
+	typs := spec.Table.ColumnTypesWithMutations(returnMutations)
+	filters, post, err := pushDownCFetcherFilters(flowCtx, post, typs)
+	if err != nil {
+		return nil, nil, err
+	}
+	helper := execinfra.ProcOutputHelper{ExprCache: flowCtx.ExprCache()}
+	if err := helper.Init(
+		post,
+		typs,
+		flowCtx.NewEvalCtx(),
+		nil,
+	); err != nil {
+		return nil, nil, err
+	}
+	neededColumns := helper.NeededColumns()
+	for _, f := range filters {
+		neededColumns.Add(f.colIdx)
+	}
+
+	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
+	fetcher := cFetcher{}
+	if _, _, err := initCRowFetcher(
+		allocator, execinfra.GetWorkMemLimit(flowCtx), &fetcher, &spec.Table, 0, /* indexIdx */
+		columnIdxMap, false /* reverse */, neededColumns, false /* isCheck */, spec.Visibility,
+	); err != nil {
+		return nil, nil, err
+	}
+	fetcher.setFilters(filters)
+
+	spanBuilder := span.MakeBuilder(&spec.Table, &spec.Table.PrimaryIndex)
+	spanBuilder.SetNeededColumns(neededColumns)
+
+	return &colIndexJoin{
+		OneInputNode: NewOneInputNode(input),
+		flowCtx:      flowCtx,
+		rf:           &fetcher,
+		inputTypes:   inputTypes[:numKeyCols],
+		batchSize:    colIndexJoinBatchSize,
+		spanBuilder:  spanBuilder,
+		row:          make(sqlbase.EncDatumRow, numKeyCols),
+	}, post, nil
+}
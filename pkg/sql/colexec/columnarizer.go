@@ -69,9 +69,8 @@ func NewColumnarizer(
 	); err != nil {
 		return nil, err
 	}
-	c.typs, err = typeconv.FromColumnTypes(c.OutputTypes())
-
-	return c, err
+	c.typs = typeconv.FromColumnTypes(c.OutputTypes())
+	return c, nil
 }
 
 // Init is part of the Operator interface.
@@ -0,0 +1,276 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/pkg/errors"
+)
+
+// This file contains the aggregate functions that concatenate all the values
+// of a group: STRING_AGG, ARRAY_AGG, JSON_AGG and JSONB_AGG. All of them
+// output a Bytes column (arrays and JSON values are stored in their encoded
+// form, see typeconv.FromColumnType) and are sensitive to the order of their
+// input, so they must only be used by the ordered aggregator since the hash
+// aggregator doesn't preserve the order of the rows within a group.
+
+// concatAccumulator accumulates the values of a single group.
+type concatAccumulator interface {
+	// add adds the value in the rowIdx'th row of vec to the current group.
+	add(vec coldata.Vec, rowIdx uint16)
+	// result returns the encoded result for the current group, or isNull if the
+	// result is NULL. The returned slice is only valid until the next call to
+	// resetGroup.
+	result() (res []byte, isNull bool)
+	// resetGroup prepares the accumulator for the next group.
+	resetGroup()
+}
+
+// concatAgg is an aggregateFunc that delegates the accumulation of the values
+// of each group to a concatAccumulator. Unlike the other aggregate functions,
+// it doesn't store the carry value in the output vector: the result of a group
+// is only written to the output once the group is complete, which is why
+// SetOutputIndex doesn't need to copy anything.
+type concatAgg struct {
+	allocator *Allocator
+	acc       concatAccumulator
+
+	done   bool
+	groups []bool
+	vec    coldata.Vec
+	col    *coldata.Bytes
+	nulls  *coldata.Nulls
+	curIdx int
+}
+
+var _ aggregateFunc = &concatAgg{}
+
+// newStringAgg returns the STRING_AGG aggregate on a column of type t with the
+// given constant delimiter (which can be empty).
+func newStringAgg(allocator *Allocator, t coltypes.T, delimiter []byte) (aggregateFunc, error) {
+	if t != coltypes.Bytes {
+		return nil, errors.Errorf("unsupported string_agg type %s", t)
+	}
+	return &concatAgg{
+		allocator: allocator,
+		acc:       &stringAggAccumulator{allocator: allocator, delimiter: delimiter},
+	}, nil
+}
+
+// newArrayAgg returns the ARRAY_AGG aggregate on a column of type t.
+func newArrayAgg(allocator *Allocator, t *types.T) (aggregateFunc, error) {
+	if !typeconv.ArrayContentsTypeSupported(t) {
+		return nil, errors.Errorf("unsupported array_agg type %s", t)
+	}
+	return &concatAgg{
+		allocator: allocator,
+		acc:       &arrayAggAccumulator{allocator: allocator, elemType: t},
+	}, nil
+}
+
+// newJSONAgg returns the JSON_AGG (or JSONB_AGG) aggregate on a column of type
+// t.
+func newJSONAgg(allocator *Allocator, t *types.T) aggregateFunc {
+	return &concatAgg{
+		allocator: allocator,
+		acc: &jsonAggAccumulator{
+			allocator: allocator,
+			typ:       t,
+			builder:   json.NewArrayBuilderWithCounter(),
+		},
+	}
+}
+
+func (a *concatAgg) Init(groups []bool, vec coldata.Vec) {
+	a.groups = groups
+	a.vec = vec
+	a.col = vec.Bytes()
+	a.nulls = vec.Nulls()
+	a.Reset()
+}
+
+func (a *concatAgg) Reset() {
+	a.curIdx = -1
+	a.done = false
+	a.nulls.UnsetNulls()
+	a.acc.resetGroup()
+}
+
+func (a *concatAgg) CurrentOutputIndex() int {
+	return a.curIdx
+}
+
+func (a *concatAgg) SetOutputIndex(idx int) {
+	if a.curIdx != -1 {
+		a.curIdx = idx
+		a.nulls.UnsetNullsAfter(uint16(idx + 1))
+	}
+}
+
+func (a *concatAgg) Compute(b coldata.Batch, inputIdxs []uint32) {
+	if a.done {
+		return
+	}
+	inputLen := b.Length()
+	if inputLen == 0 {
+		a.allocator.PerformOperation([]coldata.Vec{a.vec}, a.setCurrentResult)
+		a.curIdx++
+		a.done = true
+		return
+	}
+	vec, sel := b.ColVec(int(inputIdxs[0])), b.Selection()
+	a.allocator.PerformOperation(
+		[]coldata.Vec{a.vec},
+		func() {
+			if sel != nil {
+				for _, i := range sel[:inputLen] {
+					a.addRow(vec, i)
+				}
+			} else {
+				for i := uint16(0); i < inputLen; i++ {
+					a.addRow(vec, i)
+				}
+			}
+		},
+	)
+}
+
+// addRow adds the ith row of vec to its group, writing out the result of the
+// previous group if the row starts a new one.
+func (a *concatAgg) addRow(vec coldata.Vec, i uint16) {
+	if a.groups[i] {
+		// The a.curIdx check is necessary because there is no previous group for
+		// the first row of the input.
+		if a.curIdx >= 0 {
+			a.setCurrentResult()
+		}
+		a.curIdx++
+	}
+	a.acc.add(vec, i)
+}
+
+// setCurrentResult writes the result of the current group at the current
+// output index.
+func (a *concatAgg) setCurrentResult() {
+	if res, isNull := a.acc.result(); isNull {
+		a.nulls.SetNull(uint16(a.curIdx))
+	} else {
+		a.col.Set(a.curIdx, res)
+	}
+	a.acc.resetGroup()
+}
+
+func (a *concatAgg) HandleEmptyInputScalar() {
+	a.nulls.SetNull(0)
+}
+
+// stringAggAccumulator accumulates the non-NULL values of a Bytes column,
+// separated by a delimiter.
+type stringAggAccumulator struct {
+	allocator  *Allocator
+	delimiter  []byte
+	buf        []byte
+	sawNonNull bool
+}
+
+func (s *stringAggAccumulator) add(vec coldata.Vec, rowIdx uint16) {
+	if vec.MaybeHasNulls() && vec.Nulls().NullAt(rowIdx) {
+		return
+	}
+	oldCap := cap(s.buf)
+	if s.sawNonNull {
+		s.buf = append(s.buf, s.delimiter...)
+	}
+	s.buf = append(s.buf, vec.Bytes().Get(int(rowIdx))...)
+	s.sawNonNull = true
+	s.allocator.AdjustMemoryUsage(int64(cap(s.buf) - oldCap))
+}
+
+func (s *stringAggAccumulator) result() ([]byte, bool) {
+	return s.buf, !s.sawNonNull
+}
+
+func (s *stringAggAccumulator) resetGroup() {
+	// The buffer is reused by the next group, so its memory stays accounted
+	// for.
+	s.buf = s.buf[:0]
+	s.sawNonNull = false
+}
+
+// arrayAggAccumulator accumulates the values of a column (including the NULLs)
+// into an array stored in the vectorized array encoding.
+type arrayAggAccumulator struct {
+	allocator *Allocator
+	elemType  *types.T
+	da        sqlbase.DatumAlloc
+	buf       []byte
+}
+
+func (s *arrayAggAccumulator) add(vec coldata.Vec, rowIdx uint16) {
+	oldCap := cap(s.buf)
+	var err error
+	elem := PhysicalTypeColElemToDatum(vec, rowIdx, s.da, s.elemType)
+	if s.buf, err = typeconv.EncodeArrayElem(s.buf, elem); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	s.allocator.AdjustMemoryUsage(int64(cap(s.buf) - oldCap))
+}
+
+func (s *arrayAggAccumulator) result() ([]byte, bool) {
+	// A group always contains at least one row, so the array is never empty.
+	return s.buf, false
+}
+
+func (s *arrayAggAccumulator) resetGroup() {
+	s.buf = s.buf[:0]
+}
+
+// jsonAggAccumulator accumulates the values of a column (including the NULLs)
+// into a JSON array.
+type jsonAggAccumulator struct {
+	allocator *Allocator
+	typ       *types.T
+	da        sqlbase.DatumAlloc
+	builder   *json.ArrayBuilderWithCounter
+	buf       []byte
+}
+
+func (s *jsonAggAccumulator) add(vec coldata.Vec, rowIdx uint16) {
+	j, err := tree.AsJSON(PhysicalTypeColElemToDatum(vec, rowIdx, s.da, s.typ))
+	if err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+	oldSize := s.builder.Size()
+	s.builder.Add(j)
+	s.allocator.AdjustMemoryUsage(int64(s.builder.Size() - oldSize))
+}
+
+func (s *jsonAggAccumulator) result() ([]byte, bool) {
+	oldCap := cap(s.buf)
+	var err error
+	if s.buf, err = json.EncodeJSON(s.buf[:0], s.builder.Build()); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	s.allocator.AdjustMemoryUsage(int64(cap(s.buf) - oldCap))
+	return s.buf, false
+}
+
+func (s *jsonAggAccumulator) resetGroup() {
+	s.allocator.ReleaseMemory(int64(s.builder.Size()))
+	s.builder = json.NewArrayBuilderWithCounter()
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcatAggregates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	encodeArray := func(elems ...tree.Datum) string {
+		arr := tree.NewDArray(types.Int)
+		for _, elem := range elems {
+			require.NoError(t, arr.Append(elem))
+		}
+		b, err := typeconv.EncodeArray(nil, arr)
+		require.NoError(t, err)
+		return string(b)
+	}
+	encodeJSON := func(s string) string {
+		j, err := json.ParseJSON(s)
+		require.NoError(t, err)
+		b, err := json.EncodeJSON(nil, j)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	testCases := []struct {
+		name           string
+		aggFn          execinfrapb.AggregatorSpec_Func
+		colTypes       []coltypes.T
+		sqlTypes       []types.T
+		constArguments []tree.Datums
+		input          tuples
+		expected       tuples
+	}{
+		{
+			name:           "string_agg",
+			aggFn:          execinfrapb.AggregatorSpec_STRING_AGG,
+			colTypes:       []coltypes.T{coltypes.Int64, coltypes.Bytes},
+			constArguments: []tree.Datums{{tree.NewDString(", ")}},
+			input: tuples{
+				{0, "a"},
+				{0, nil},
+				{0, "b"},
+				{1, nil},
+				{2, "c"},
+				{3, ""},
+				{3, "d"},
+			},
+			expected: tuples{{"a, b"}, {nil}, {"c"}, {", d"}},
+		},
+		{
+			name:     "string_agg without delimiter",
+			aggFn:    execinfrapb.AggregatorSpec_STRING_AGG,
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Bytes},
+			input: tuples{
+				{0, "a"},
+				{0, "b"},
+				{1, "c"},
+			},
+			expected: tuples{{"ab"}, {"c"}},
+		},
+		{
+			name:     "array_agg",
+			aggFn:    execinfrapb.AggregatorSpec_ARRAY_AGG,
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Int64},
+			sqlTypes: []types.T{*types.Int, *types.Int},
+			input: tuples{
+				{0, 1},
+				{0, nil},
+				{0, 2},
+				{1, nil},
+				{2, 3},
+			},
+			expected: tuples{
+				{encodeArray(tree.NewDInt(1), tree.DNull, tree.NewDInt(2))},
+				{encodeArray(tree.DNull)},
+				{encodeArray(tree.NewDInt(3))},
+			},
+		},
+		{
+			name:     "json_agg",
+			aggFn:    execinfrapb.AggregatorSpec_JSON_AGG,
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Int64},
+			sqlTypes: []types.T{*types.Int, *types.Int},
+			input: tuples{
+				{0, 1},
+				{0, nil},
+				{0, 2},
+				{1, 3},
+			},
+			expected: tuples{{encodeJSON("[1, null, 2]")}, {encodeJSON("[3]")}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runTestsWithTyps(t, []tuples{tc.input}, [][]coltypes.T{tc.colTypes}, tc.expected, orderedVerifier,
+				func(input []Operator) (Operator, error) {
+					return NewOrderedAggregator(
+						testAllocator,
+						input[0],
+						tc.colTypes,
+						[]execinfrapb.AggregatorSpec_Func{tc.aggFn},
+						[]uint32{0},
+						[][]uint32{{1}},
+						tc.constArguments,
+						tc.sqlTypes,
+						false, /* isScalar */
+					)
+				})
+		})
+	}
+
+	t.Run("hash aggregator", func(t *testing.T) {
+		_, err := NewHashAggregator(
+			testAllocator,
+			newOpTestInput(1 /* batchSize */, tuples{{0, "a"}}, nil /* typs */),
+			[]coltypes.T{coltypes.Int64, coltypes.Bytes},
+			[]execinfrapb.AggregatorSpec_Func{execinfrapb.AggregatorSpec_STRING_AGG},
+			[]uint32{0},
+			[][]uint32{{1}},
+			nil,   /* constArguments */
+			nil,   /* sqlTypes */
+			false, /* isScalar */
+		)
+		require.Error(t, err)
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/pkg/errors"
 )
 
@@ -40,6 +41,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // _TYPES_T is the template type variable for coltypes.T. It will be replaced by
 // coltypes.Foo for each type Foo in the coltypes.T type.
 const _TYPES_T = coltypes.Unhandled
@@ -60,6 +64,13 @@ func NewConstOp(
 	allocator *Allocator, input Operator, t coltypes.T, constVal interface{}, outputIdx int,
 ) (Operator, error) {
 	switch t {
+	case coltypes.Datum:
+		return &constDatumOp{
+			OneInputNode: NewOneInputNode(input),
+			allocator:    allocator,
+			outputIdx:    outputIdx,
+			constVal:     constVal,
+		}, nil
 	// {{range .}}
 	case _TYPES_T:
 		return &const_TYPEOp{
@@ -121,6 +132,49 @@ func (c const_TYPEOp) Next(ctx context.Context) coldata.Batch {
 
 // {{end}}
 
+// constDatumOp is the operator that produces a constant value of
+// coltypes.Datum type. Such values are stored as interface{}'s, so the
+// operator is written by hand.
+type constDatumOp struct {
+	OneInputNode
+
+	allocator *Allocator
+	outputIdx int
+	constVal  interface{}
+}
+
+func (c constDatumOp) Init() {
+	c.input.Init()
+}
+
+func (c constDatumOp) Next(ctx context.Context) coldata.Batch {
+	batch := c.input.Next(ctx)
+	n := batch.Length()
+	if batch.Width() == c.outputIdx {
+		c.allocator.AppendColumn(batch, coltypes.Datum)
+	}
+	if n == 0 {
+		return batch
+	}
+	vec := batch.ColVec(c.outputIdx)
+	col := vec.Datum()
+	c.allocator.PerformOperation(
+		[]coldata.Vec{vec},
+		func() {
+			if sel := batch.Selection(); sel != nil {
+				for _, i := range sel[:n] {
+					col[i] = c.constVal
+				}
+			} else {
+				for i := range col[:n] {
+					col[i] = c.constVal
+				}
+			}
+		},
+	)
+	return batch
+}
+
 // NewConstNullOp creates a new operator that produces a constant (untyped) NULL
 // value at index outputIdx.
 func NewConstNullOp(allocator *Allocator, input Operator, outputIdx int, typ coltypes.T) Operator {
@@ -0,0 +1,110 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// foldConstants returns the expression in which all constant subexpressions
+// have been evaluated, so that they are computed once during the flow setup
+// rather than for every batch (or planned as the less efficient operators
+// which don't know that some of their arguments are constant). It also
+// simplifies the logical expressions that have a constant side.
+//
+// The subexpressions whose evaluation results in an error are left as is so
+// that the error is only returned if the expression is evaluated on some
+// tuples, like it would have been without folding.
+func foldConstants(evalCtx *tree.EvalContext, expr tree.TypedExpr) tree.TypedExpr {
+	v := constantFolder{evalCtx: evalCtx}
+	newExpr, _ := tree.WalkExpr(&v, expr)
+	return newExpr.(tree.TypedExpr)
+}
+
+// constantFolder is a tree.Visitor that replaces the constant subexpressions
+// with the datums they evaluate to.
+type constantFolder struct {
+	evalCtx *tree.EvalContext
+}
+
+var _ tree.Visitor = &constantFolder{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *constantFolder) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if _, ok := expr.(tree.Datum); ok {
+		return false, expr
+	}
+	typedExpr, ok := expr.(tree.TypedExpr)
+	if !ok || !tree.IsConst(v.evalCtx, expr) {
+		return true, expr
+	}
+	d, err := typedExpr.Eval(v.evalCtx)
+	if err != nil {
+		// Some of the subexpressions might still be folded.
+		return true, expr
+	}
+	if !d.ResolvedType().Identical(typedExpr.ResolvedType()) {
+		// The datum doesn't carry the exact type of the expression (for example,
+		// the result of INT2 arithmetic is a DInt and a NULL has the unknown
+		// type), and the type of the projected column must not change.
+		return true, expr
+	}
+	return false, d
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *constantFolder) VisitPost(expr tree.Expr) tree.Expr {
+	switch t := expr.(type) {
+	case *tree.AndExpr:
+		// x AND true = x, x AND false = false.
+		if b, ok := t.Left.(*tree.DBool); ok {
+			if *b {
+				return t.Right
+			}
+			return tree.DBoolFalse
+		}
+		if b, ok := t.Right.(*tree.DBool); ok {
+			if *b {
+				return t.Left
+			}
+			return tree.DBoolFalse
+		}
+	case *tree.OrExpr:
+		// x OR true = true, x OR false = x.
+		if b, ok := t.Left.(*tree.DBool); ok {
+			if *b {
+				return tree.DBoolTrue
+			}
+			return t.Right
+		}
+		if b, ok := t.Right.(*tree.DBool); ok {
+			if *b {
+				return tree.DBoolTrue
+			}
+			return t.Left
+		}
+	}
+	return expr
+}
+
+// evalConstFilter returns whether the filter expression is constant and, if
+// so, whether it always passes (a NULL result never passes). The filters which
+// can't be evaluated are not considered constant so that the error is only
+// returned when there are tuples to filter.
+func evalConstFilter(evalCtx *tree.EvalContext, filter tree.TypedExpr) (isConst, passes bool) {
+	if !tree.IsConst(evalCtx, filter) {
+		return false, false
+	}
+	d, err := filter.Eval(evalCtx)
+	if err != nil {
+		return false, false
+	}
+	b, ok := d.(*tree.DBool)
+	return true, ok && bool(*b)
+}
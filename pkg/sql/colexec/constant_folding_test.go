@@ -0,0 +1,157 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldConstants(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+
+	for _, tc := range []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "@1 + (1 + 2)", expected: "@1 + 3"},
+		{expr: "@1 > length('abc')", expected: "@1 > 3"},
+		{expr: "@1 = 1 AND 1 = 1", expected: "@1 = 1"},
+		{expr: "1 = 2 AND @1 = 1", expected: "false"},
+		{expr: "@1 = 1 OR 1 = 1", expected: "true"},
+		{expr: "1 = 2 OR @1 = 1", expected: "@1 = 1"},
+		{expr: "1 = 1", expected: "true"},
+		// The errors are left to the evaluation.
+		{expr: "@1 = 1 / 0", expected: "@1 = (1 / 0)"},
+		// The NULLs don't have the type of the expression.
+		{expr: "@1 + NULL::INT8", expected: "@1 + NULL::INT8"},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			var helper execinfra.ExprHelper
+			require.NoError(t, helper.Init(execinfrapb.Expression{Expr: tc.expr}, []types.T{*types.Int}, &evalCtx))
+			require.Equal(t, tc.expected, foldConstants(&evalCtx, helper.Expr).String())
+		})
+	}
+}
+
+func TestConstantFoldingPlanning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	input := tuples{{0}, {1}, {2}}
+	for _, tc := range []struct {
+		desc     string
+		post     execinfrapb.PostProcessSpec
+		expected tuples
+		// noop and zero indicate that the filter should be planned as a noop or
+		// as a zero operator, respectively.
+		noop, zero bool
+	}{
+		{
+			desc:     "always true filter",
+			post:     execinfrapb.PostProcessSpec{Filter: execinfrapb.Expression{Expr: "1 < 2 OR @1 = 1"}},
+			expected: input,
+			noop:     true,
+		},
+		{
+			desc:     "always false filter",
+			post:     execinfrapb.PostProcessSpec{Filter: execinfrapb.Expression{Expr: "@1 = 1 AND 1 > 2"}},
+			expected: tuples{},
+			zero:     true,
+		},
+		{
+			desc:     "always NULL filter",
+			post:     execinfrapb.PostProcessSpec{Filter: execinfrapb.Expression{Expr: "NULL::BOOL"}},
+			expected: tuples{},
+			zero:     true,
+		},
+		{
+			desc:     "filter",
+			post:     execinfrapb.PostProcessSpec{Filter: execinfrapb.Expression{Expr: "@1 >= 4 - 3 AND 1 = 1"}},
+			expected: tuples{{1}, {2}},
+		},
+		{
+			desc: "render",
+			post: execinfrapb.PostProcessSpec{
+				RenderExprs: []execinfrapb.Expression{{Expr: "@1 * (2 + 3)"}},
+			},
+			expected: tuples{{0}, {5}, {10}},
+		},
+		{
+			desc: "render with an error",
+			post: execinfrapb.PostProcessSpec{
+				Filter:      execinfrapb.Expression{Expr: "@1 > 2"},
+				RenderExprs: []execinfrapb.Expression{{Expr: "1 / 0"}},
+			},
+			expected: tuples{},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			// NULLs can't be injected because the constant filters ignore them.
+			runTestsWithoutAllNullsInjection(
+				t,
+				[]tuples{input},
+				[][]coltypes.T{{coltypes.Int64}},
+				tc.expected,
+				orderedVerifier,
+				func(inputs []Operator) (Operator, error) {
+					spec := &execinfrapb.ProcessorSpec{
+						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int}}},
+						Core: execinfrapb.ProcessorCoreUnion{
+							Noop: &execinfrapb.NoopCoreSpec{},
+						},
+						Post: tc.post,
+					}
+					args := NewColOperatorArgs{
+						Spec:                               spec,
+						Inputs:                             inputs,
+						StreamingMemAccount:                testMemAcc,
+						UseStreamingMemAccountForBuffering: true,
+					}
+					result, err := NewColOperator(ctx, flowCtx, args)
+					if err != nil {
+						return nil, err
+					}
+					if tc.noop {
+						// The Noop core is the last operator.
+						require.IsType(t, &noopOperator{}, result.Op)
+					}
+					if tc.zero {
+						require.IsType(t, &zeroOperator{}, result.Op)
+					}
+					return result.Op, nil
+				},
+			)
+		})
+	}
+}
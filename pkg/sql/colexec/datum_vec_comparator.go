@@ -0,0 +1,68 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// datumVecComparator is a vecComparator for the vectors of coltypes.Datum
+// type. Such vectors store tree.Datums, so, unlike the templated comparators,
+// it needs an evaluation context to compare the values.
+type datumVecComparator struct {
+	evalCtx *tree.EvalContext
+	vecs    [][]interface{}
+	nulls   []*coldata.Nulls
+}
+
+var _ vecComparator = &datumVecComparator{}
+
+func newDatumVecComparator(evalCtx *tree.EvalContext, numVecs int) *datumVecComparator {
+	return &datumVecComparator{
+		evalCtx: evalCtx,
+		vecs:    make([][]interface{}, numVecs),
+		nulls:   make([]*coldata.Nulls, numVecs),
+	}
+}
+
+func (c *datumVecComparator) compare(vecIdx1, vecIdx2 int, valIdx1, valIdx2 uint16) int {
+	n1 := c.nulls[vecIdx1].MaybeHasNulls() && c.nulls[vecIdx1].NullAt(valIdx1)
+	n2 := c.nulls[vecIdx2].MaybeHasNulls() && c.nulls[vecIdx2].NullAt(valIdx2)
+	if n1 && n2 {
+		return 0
+	} else if n1 {
+		return -1
+	} else if n2 {
+		return 1
+	}
+	left := c.vecs[vecIdx1][valIdx1].(tree.Datum)
+	right := c.vecs[vecIdx2][valIdx2].(tree.Datum)
+	return left.Compare(c.evalCtx, right)
+}
+
+func (c *datumVecComparator) isNull(vecIdx int, valIdx uint16) bool {
+	return c.nulls[vecIdx].MaybeHasNulls() && c.nulls[vecIdx].NullAt(valIdx)
+}
+
+func (c *datumVecComparator) setVec(idx int, vec coldata.Vec) {
+	c.vecs[idx] = vec.Datum()
+	c.nulls[idx] = vec.Nulls()
+}
+
+func (c *datumVecComparator) set(srcVecIdx, dstVecIdx int, srcIdx, dstIdx uint16) {
+	if c.nulls[srcVecIdx].MaybeHasNulls() && c.nulls[srcVecIdx].NullAt(srcIdx) {
+		c.nulls[dstVecIdx].SetNull(dstIdx)
+	} else {
+		c.nulls[dstVecIdx].UnsetNull(dstIdx)
+		c.vecs[dstVecIdx][dstIdx] = c.vecs[srcVecIdx][srcIdx]
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// NewDefaultCmpProjOp returns an operator that evaluates cmpExpr row by row
+// using the datum representation of its arguments. It is used for the
+// comparisons that involve the types without a native columnar representation
+// (for example, tuples), for which there are no templated operators. The left
+// argument is read from the column at index leftIdx, and so is the right one
+// unless rightIdx is -1, in which case the right argument of cmpExpr, which
+// must be a constant, is used. The boolean result is written to the column at
+// index outputIdx.
+func NewDefaultCmpProjOp(
+	allocator *Allocator,
+	evalCtx *tree.EvalContext,
+	cmpExpr *tree.ComparisonExpr,
+	columnTypes []types.T,
+	leftIdx, rightIdx int,
+	outputIdx int,
+	input Operator,
+) Operator {
+	return &defaultCmpProjOp{
+		OneInputNode: NewOneInputNode(input),
+		allocator:    allocator,
+		evalCtx:      evalCtx,
+		cmpExpr:      *cmpExpr,
+		columnTypes:  columnTypes,
+		leftIdx:      leftIdx,
+		rightIdx:     rightIdx,
+		outputIdx:    outputIdx,
+	}
+}
+
+type defaultCmpProjOp struct {
+	OneInputNode
+	allocator *Allocator
+	evalCtx   *tree.EvalContext
+	// cmpExpr is a copy of the comparison expression whose arguments are
+	// replaced with the datums of the row being evaluated.
+	cmpExpr     tree.ComparisonExpr
+	columnTypes []types.T
+	leftIdx     int
+	rightIdx    int
+	outputIdx   int
+
+	da sqlbase.DatumAlloc
+}
+
+var _ Operator = &defaultCmpProjOp{}
+
+func (d *defaultCmpProjOp) Init() {
+	d.input.Init()
+}
+
+func (d *defaultCmpProjOp) Next(ctx context.Context) coldata.Batch {
+	batch := d.input.Next(ctx)
+	n := batch.Length()
+	if d.outputIdx == batch.Width() {
+		d.allocator.AppendColumn(batch, coltypes.Bool)
+	}
+	if n == 0 {
+		return batch
+	}
+	sel := batch.Selection()
+	leftVec := batch.ColVec(d.leftIdx)
+	var rightVec coldata.Vec
+	if d.rightIdx != -1 {
+		rightVec = batch.ColVec(d.rightIdx)
+	}
+	output := batch.ColVec(d.outputIdx)
+	outputCol := output.Bool()
+	outputNulls := output.Nulls()
+	d.allocator.PerformOperation(
+		[]coldata.Vec{output},
+		func() {
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				d.cmpExpr.Left = PhysicalTypeColElemToDatum(
+					leftVec, rowIdx, d.da, &d.columnTypes[d.leftIdx],
+				)
+				if rightVec != nil {
+					d.cmpExpr.Right = PhysicalTypeColElemToDatum(
+						rightVec, rowIdx, d.da, &d.columnTypes[d.rightIdx],
+					)
+				}
+				res, err := d.cmpExpr.Eval(d.evalCtx)
+				if err != nil {
+					execerror.NonVectorizedPanic(err)
+				}
+				if res == tree.DNull {
+					outputNulls.SetNull(rowIdx)
+					continue
+				}
+				outputCol[rowIdx] = bool(tree.MustBeDBool(res))
+			}
+		},
+	)
+	// Although we didn't change the length of the batch, it is necessary to set
+	// the length anyway (this helps maintaining the invariant of flat bytes).
+	batch.SetLength(n)
+	return batch
+}
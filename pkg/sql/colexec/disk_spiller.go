@@ -111,7 +111,7 @@ func newOneInputDiskSpiller(
 	inMemoryMemMonitorName string,
 	diskBackedOpConstructor func(input Operator) Operator,
 ) Operator {
-	diskBackedOpInput := newBufferExportingOperator(allocator, inMemoryOp, input)
+	diskBackedOpInput := newBufferExportingOperator(allocator, inMemoryOp.ExportBuffered, input)
 	return &oneInputDiskSpiller{
 		allocator:              allocator,
 		input:                  input,
@@ -193,9 +193,153 @@ func (d *oneInputDiskSpiller) Child(nth int, verbose bool) execinfra.OpNode {
 	}
 }
 
-// bufferExportingOperator is an Operator that first returns all batches from
-// firstSource, and once firstSource is exhausted, it proceeds on returning all
-// batches from the second source.
+// twoInputBufferingInMemoryOperator is the analog of
+// bufferingInMemoryOperator for the Operators with two inputs.
+type twoInputBufferingInMemoryOperator interface {
+	Operator
+
+	// ExportBuffered is the same as ExportBuffered of
+	// bufferingInMemoryOperator, but it only returns the tuples that have been
+	// buffered up from input, which is one of the two inputs of the operator.
+	ExportBuffered(allocator *Allocator, input Operator) coldata.Batch
+	// isBuffering returns whether the operator is still buffering up its
+	// inputs. Once it is not, the tuples that it has consumed might have
+	// already been processed, so it is not possible to fall back to a
+	// disk-backed operator anymore.
+	isBuffering() bool
+}
+
+// twoInputDiskSpiller is the analog of oneInputDiskSpiller for the Operators
+// with two inputs. The disk-backed operator is given two buffer exporting
+// operators as its inputs, one for each input of the in-memory operator.
+//
+// Unlike oneInputDiskSpiller, twoInputDiskSpiller falls back to the disk-backed
+// operator only if the in-memory one hits the memory limit while it is still
+// buffering up its inputs. An out of memory error that occurs later is
+// propagated further.
+type twoInputDiskSpiller struct {
+	NonExplainable
+
+	initialized bool
+	spilled     bool
+
+	inputOne               Operator
+	inputTwo               Operator
+	inMemoryOp             twoInputBufferingInMemoryOperator
+	inMemoryMemMonitorName string
+	diskBackedOp           Operator
+}
+
+var _ Operator = &twoInputDiskSpiller{}
+
+// newTwoInputDiskSpiller returns a new twoInputDiskSpiller. It takes the same
+// arguments as newOneInputDiskSpiller, except that diskBackedOpConstructor
+// is given the buffer exporting operators for both inputs.
+func newTwoInputDiskSpiller(
+	allocator *Allocator,
+	inputOne, inputTwo Operator,
+	inMemoryOp twoInputBufferingInMemoryOperator,
+	inMemoryMemMonitorName string,
+	diskBackedOpConstructor func(inputOne, inputTwo Operator) Operator,
+) Operator {
+	diskBackedOpInputOne := newBufferExportingOperator(
+		allocator,
+		func(allocator *Allocator) coldata.Batch {
+			return inMemoryOp.ExportBuffered(allocator, inputOne)
+		},
+		inputOne,
+	)
+	diskBackedOpInputTwo := newBufferExportingOperator(
+		allocator,
+		func(allocator *Allocator) coldata.Batch {
+			return inMemoryOp.ExportBuffered(allocator, inputTwo)
+		},
+		inputTwo,
+	)
+	return &twoInputDiskSpiller{
+		inputOne:               inputOne,
+		inputTwo:               inputTwo,
+		inMemoryOp:             inMemoryOp,
+		inMemoryMemMonitorName: inMemoryMemMonitorName,
+		diskBackedOp:           diskBackedOpConstructor(diskBackedOpInputOne, diskBackedOpInputTwo),
+	}
+}
+
+func (d *twoInputDiskSpiller) Init() {
+	if d.initialized {
+		return
+	}
+	d.initialized = true
+	// Note that d.inputOne and d.inputTwo are the inputs to d.inMemoryOp, so
+	// calling Init() only on the latter is sufficient.
+	d.inMemoryOp.Init()
+}
+
+func (d *twoInputDiskSpiller) Next(ctx context.Context) coldata.Batch {
+	if d.spilled {
+		return d.diskBackedOp.Next(ctx)
+	}
+	var batch coldata.Batch
+	if err := execerror.CatchVectorizedRuntimeError(
+		func() {
+			batch = d.inMemoryOp.Next(ctx)
+		},
+	); err != nil {
+		if sqlbase.IsOutOfMemoryError(err) &&
+			strings.Contains(err.Error(), d.inMemoryMemMonitorName) &&
+			d.inMemoryOp.isBuffering() {
+			d.spilled = true
+			d.diskBackedOp.Init()
+			return d.Next(ctx)
+		}
+		// Either not an out of memory error, an OOM error coming from a
+		// different operator, or the in-memory operator is past the point where
+		// it could export the tuples it has consumed, so we propagate it further.
+		execerror.VectorizedInternalPanic(err)
+	}
+	return batch
+}
+
+func (d *twoInputDiskSpiller) ChildCount(verbose bool) int {
+	if verbose {
+		return 4
+	}
+	return 1
+}
+
+func (d *twoInputDiskSpiller) Child(nth int, verbose bool) execinfra.OpNode {
+	// Note: similarly to oneInputDiskSpiller, we return the in-memory operator
+	// as being on the main chain in order to make the output of EXPLAIN (VEC)
+	// less confusing.
+	if verbose {
+		switch nth {
+		case 0:
+			return d.inMemoryOp
+		case 1:
+			return d.inputOne
+		case 2:
+			return d.inputTwo
+		case 3:
+			return d.diskBackedOp
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("invalid index %d", nth))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+	switch nth {
+	case 0:
+		return d.inMemoryOp
+	default:
+		execerror.VectorizedInternalPanic(fmt.Sprintf("invalid index %d", nth))
+		// This code is unreachable, but the compiler cannot infer that.
+		return nil
+	}
+}
+
+// bufferExportingOperator is an Operator that first returns all batches
+// exported by exportBuffered, and once those are exhausted, it proceeds on
+// returning all batches from the second source.
 //
 // NOTE: bufferExportingOperator assumes that both sources will have been
 // initialized when bufferExportingOperator.Init() is called.
@@ -203,8 +347,10 @@ type bufferExportingOperator struct {
 	ZeroInputNode
 	NonExplainable
 
-	allocator       *Allocator
-	firstSource     bufferingInMemoryOperator
+	allocator *Allocator
+	// exportBuffered is the ExportBuffered method of the in-memory operator
+	// that is the first source.
+	exportBuffered  func(*Allocator) coldata.Batch
 	secondSource    Operator
 	firstSourceDone bool
 }
@@ -212,12 +358,12 @@ type bufferExportingOperator struct {
 var _ Operator = &bufferExportingOperator{}
 
 func newBufferExportingOperator(
-	allocator *Allocator, firstSource bufferingInMemoryOperator, secondSource Operator,
+	allocator *Allocator, exportBuffered func(*Allocator) coldata.Batch, secondSource Operator,
 ) Operator {
 	return &bufferExportingOperator{
-		allocator:    allocator,
-		firstSource:  firstSource,
-		secondSource: secondSource,
+		allocator:      allocator,
+		exportBuffered: exportBuffered,
+		secondSource:   secondSource,
 	}
 }
 
@@ -230,7 +376,7 @@ func (b *bufferExportingOperator) Next(ctx context.Context) coldata.Batch {
 	if b.firstSourceDone {
 		return b.secondSource.Next(ctx)
 	}
-	batch := b.firstSource.ExportBuffered(b.allocator)
+	batch := b.exportBuffered(b.allocator)
 	if batch.Length() == 0 {
 		b.firstSourceDone = true
 		return b.Next(ctx)
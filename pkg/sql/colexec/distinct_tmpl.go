@@ -27,10 +27,12 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colsimd"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/pkg/errors"
 )
 
@@ -100,6 +102,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // Dummy import to pull in "tree" package.
 var _ tree.Datum
 
@@ -339,10 +344,14 @@ func (p partitioner_TYPE) partition(colVec coldata.Vec, outputCol []bool, n uint
 			_CHECK_DISTINCT_WITH_NULLS(checkIdx, outputIdx, lastVal, nulls, lastValNull, col, outputCol)
 		}
 	} else {
+		// {{if .SIMDType}}
+		colsimd.Distinct_SIMD_TYPE(col, outputCol)
+		// {{else}}
 		for execgen.RANGE(checkIdx, col, 0, int(n)) {
 			outputIdx := checkIdx
 			_CHECK_DISTINCT(checkIdx, outputIdx, lastVal, col, outputCol)
 		}
+		// {{end}}
 	}
 }
 
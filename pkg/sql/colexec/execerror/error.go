@@ -26,63 +26,38 @@ const panicLineSubstring = "runtime/panic.go"
 // CatchVectorizedRuntimeError executes operation, catches a runtime error if
 // it is coming from the vectorized engine, and returns it. If an error not
 // related to the vectorized engine occurs, it is not recovered from.
+//
+// This is the catcher that must be installed at the boundaries of the
+// vectorized flows (wherever control passes from a component that isn't an
+// Operator into Operator.Next, for example in the Materializer or the Outbox),
+// and it converts the panics according to the following contract:
+//   - errors propagated with NonVectorizedPanic or VectorizedExpectedInternalPanic
+//     are returned as is, with their PG codes.
+//   - StorageErrors are returned as is.
+//   - all other errors and objects propagated by the vectorized engine, which
+//     includes the runtime errors like nil pointer dereferences, are returned as
+//     internal errors, annotated with the function that emitted the panic.
+//   - panics emitted by third-party code (anything outside of CockroachDB,
+//     including the standard library) that was called directly by the vectorized
+//     engine are returned as internal errors which are distinguished from the
+//     ones above, even if the panic object is an error with a PG code.
+//   - panics emitted by any other code are not recovered from.
 func CatchVectorizedRuntimeError(operation func()) (retErr error) {
 	defer func() {
 		if err := recover(); err != nil {
-			stackTrace := string(debug.Stack())
-			scanner := bufio.NewScanner(strings.NewReader(stackTrace))
-			panicLineFound := false
-			for scanner.Scan() {
-				if strings.Contains(scanner.Text(), panicLineSubstring) {
-					panicLineFound = true
-					break
-				}
-			}
-			if !panicLineFound {
-				panic(fmt.Sprintf("panic line %q not found in the stack trace\n%s", panicLineSubstring, stackTrace))
-			}
-			if scanner.Scan() {
-				panicEmittedFrom := strings.TrimSpace(scanner.Text())
-				if isPanicFromVectorizedEngine(panicEmittedFrom) {
-					// We only want to catch runtime errors coming from the vectorized
-					// engine.
-					if e, ok := err.(error); ok {
-						if _, ok := err.(*StorageError); ok {
-							// A StorageError was caused by something below SQL, and represents
-							// an error that we'd simply like to propagate along.
-							// Do nothing.
-						} else {
-							doNotAnnotate := false
-							if nvie, ok := e.(*notVectorizedInternalError); ok {
-								// A notVectorizedInternalError was not caused by the
-								// vectorized engine and represents an error that we don't
-								// want to annotate in case it doesn't have a valid PG code.
-								doNotAnnotate = true
-								// We want to unwrap notVectorizedInternalError so that in case
-								// the original error does have a valid PG code, the code is
-								// correctly propagated.
-								e = nvie.error
-							}
-							if code := pgerror.GetPGCode(e); !doNotAnnotate && code == pgcode.Uncategorized {
-								// Any error without a code already is "surprising" and
-								// needs to be annotated to indicate that it was
-								// unexpected.
-								e = errors.AssertionFailedf("unexpected error from the vectorized runtime: %+v", e)
-							}
-						}
-						retErr = e
-					} else {
-						// Not an error object. Definitely unexpected.
-						surprisingObject := err
-						retErr = errors.AssertionFailedf("unexpected error from the vectorized runtime: %+v", surprisingObject)
-					}
-				} else {
-					// Do not recover from the panic not related to the vectorized
-					// engine.
-					panic(err)
-				}
-			} else {
-				panic(fmt.Sprintf("unexpectedly there is no line below the panic line in the stack trace\n%s", stackTrace))
+			origin, emittedFrom := panicOrigin(string(debug.Stack()))
+			switch origin {
+			case vectorizedEngineOrigin:
+				retErr = vectorizedPanicToError(err, emittedFrom)
+			case thirdPartyOrigin:
+				retErr = errors.WithDetailf(
+					errors.AssertionFailedf("unexpected panic from third-party code in the vectorized runtime: %+v", err),
+					"%s (called from %s)", errorDetailPrefix, emittedFrom,
+				)
+			default:
+				// Do not recover from the panic not related to the vectorized
+				// engine.
+				panic(err)
 			}
 		}
 		// No panic happened, so the operation must have been executed
@@ -92,6 +67,62 @@ func CatchVectorizedRuntimeError(operation func()) (retErr error) {
 	return retErr
 }
 
+// errorDetailPrefix starts the detail with which the internal errors caught by
+// CatchVectorizedRuntimeError are annotated, and which describes where the
+// panic was emitted from.
+const errorDetailPrefix = "panic in the vectorized engine"
+
+// vectorizedPanicToError converts the object of a panic that was emitted by
+// the function emittedFrom of the vectorized engine into an error.
+func vectorizedPanicToError(err interface{}, emittedFrom string) error {
+	e, ok := err.(error)
+	if !ok {
+		// Not an error object. Definitely unexpected.
+		surprisingObject := err
+		return errors.WithDetailf(
+			errors.AssertionFailedf("unexpected error from the vectorized runtime: %+v", surprisingObject),
+			"%s: %s", errorDetailPrefix, emittedFrom,
+		)
+	}
+	if _, ok := err.(*StorageError); ok {
+		// A StorageError was caused by something below SQL, and represents
+		// an error that we'd simply like to propagate along.
+		return e
+	}
+	if nvie, ok := e.(*notVectorizedInternalError); ok {
+		// A notVectorizedInternalError was not caused by the vectorized engine
+		// and represents an error that we don't want to annotate in case it
+		// doesn't have a valid PG code. We want to unwrap it so that in case the
+		// original error does have a valid PG code, the code is correctly
+		// propagated.
+		return nvie.error
+	}
+	if code := pgerror.GetPGCode(e); code == pgcode.Uncategorized {
+		// Any error without a code already is "surprising" and needs to be
+		// annotated to indicate that it was unexpected.
+		e = errors.WithDetailf(
+			errors.AssertionFailedf("unexpected error from the vectorized runtime: %+v", e),
+			"%s: %s", errorDetailPrefix, emittedFrom,
+		)
+	}
+	return e
+}
+
+type panicOriginType int
+
+const (
+	// otherOrigin is any code from which panics are not recovered.
+	otherOrigin panicOriginType = iota
+	// vectorizedEngineOrigin is the code of the vectorized engine (see
+	// isPanicFromVectorizedEngine).
+	vectorizedEngineOrigin
+	// thirdPartyOrigin is the code outside of CockroachDB that was called
+	// directly by the vectorized engine.
+	thirdPartyOrigin
+)
+
+const cockroachPackagePrefix = "github.com/cockroachdb/cockroach/"
+
 const (
 	colPackagePrefix          = "github.com/cockroachdb/cockroach/pkg/col"
 	colexecPackagePrefix      = "github.com/cockroachdb/cockroach/pkg/sql/colexec"
@@ -119,6 +150,76 @@ func isPanicFromVectorizedEngine(panicEmittedFrom string) bool {
 		strings.HasPrefix(panicEmittedFrom, treePackagePrefix)
 }
 
+// panicOrigin determines where the panic that resulted in stackTrace was
+// emitted from. Along with the origin, it returns the short name of the
+// function of the vectorized engine that emitted the panic or that called the
+// third-party code that did.
+func panicOrigin(stackTrace string) (_ panicOriginType, emittedFrom string) {
+	scanner := bufio.NewScanner(strings.NewReader(stackTrace))
+	panicLineFound := false
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), panicLineSubstring) {
+			panicLineFound = true
+			break
+		}
+	}
+	if !panicLineFound {
+		panic(fmt.Sprintf("panic line %q not found in the stack trace\n%s", panicLineSubstring, stackTrace))
+	}
+	// The remainder of the stack trace alternates between the lines with the
+	// functions and the lines with their files, which are indented. The
+	// functions of the runtime package (like the ones that emit the runtime
+	// errors) are skipped, so the first function left is the one that caused
+	// the panic.
+	var panicFrame string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '\t' || line[0] == ' ' {
+			continue
+		}
+		if panicFrame == "" && strings.HasPrefix(line, "runtime.") {
+			continue
+		}
+		if panicFrame == "" {
+			panicFrame = line
+			if isPanicFromVectorizedEngine(panicFrame) {
+				return vectorizedEngineOrigin, shortFuncName(panicFrame)
+			}
+			if strings.HasPrefix(panicFrame, cockroachPackagePrefix) {
+				return otherOrigin, ""
+			}
+			continue
+		}
+		// The panic was emitted from third-party code. Find out which part of
+		// CockroachDB called it.
+		if strings.HasPrefix(line, cockroachPackagePrefix) {
+			if isPanicFromVectorizedEngine(line) {
+				return thirdPartyOrigin, shortFuncName(line)
+			}
+			return otherOrigin, ""
+		}
+	}
+	if panicFrame == "" {
+		panic(fmt.Sprintf("unexpectedly there is no line below the panic line in the stack trace\n%s", stackTrace))
+	}
+	return otherOrigin, ""
+}
+
+// shortFuncName returns the name of the function from a line of a stack
+// trace without the arguments and the package path (but with the package
+// name), e.g. "colexec.(*sortOp).Next".
+func shortFuncName(line string) string {
+	if strings.HasSuffix(line, ")") {
+		if idx := strings.LastIndexByte(line, '('); idx > 0 {
+			line = line[:idx]
+		}
+	}
+	if idx := strings.LastIndexByte(line, '/'); idx >= 0 {
+		line = line[idx+1:]
+	}
+	return line
+}
+
 // StorageError is an error that was created by a component below the sql
 // stack, such as the network or storage layers. A StorageError will be bubbled
 // up all the way past the SQL layer unchanged.
@@ -0,0 +1,112 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execerror
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// makeStackTrace returns a stack trace in the format of debug.Stack of a
+// goroutine that panicked in the first of the given functions, which were
+// called by the following ones.
+func makeStackTrace(funcs ...string) string {
+	var b strings.Builder
+	b.WriteString("goroutine 1 [running]:\n")
+	b.WriteString("runtime/debug.Stack(0x0, 0x0, 0x0)\n\t/go/src/runtime/debug/stack.go:24 +0x9d\n")
+	b.WriteString("panic(0x1, 0x2)\n\t/go/src/runtime/panic.go:679 +0x1b2\n")
+	for _, f := range funcs {
+		b.WriteString(f + "(0xc000010000)\n\t/src/file.go:1 +0x1\n")
+	}
+	return b.String()
+}
+
+func TestPanicOrigin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const (
+		colexecFunc = "github.com/cockroachdb/cockroach/pkg/sql/colexec.(*sortOp).Next"
+		sqlFunc     = "github.com/cockroachdb/cockroach/pkg/sql.(*planner).foo"
+		thirdParty  = "github.com/cockroachdb/apd.(*Context).Quo"
+	)
+	for _, tc := range []struct {
+		funcs       []string
+		origin      panicOriginType
+		emittedFrom string
+	}{
+		{
+			funcs:       []string{colexecFunc, sqlFunc},
+			origin:      vectorizedEngineOrigin,
+			emittedFrom: "colexec.(*sortOp).Next",
+		},
+		{
+			// Runtime errors are attributed to the function that caused them.
+			funcs:       []string{"runtime.goPanicIndex", colexecFunc},
+			origin:      vectorizedEngineOrigin,
+			emittedFrom: "colexec.(*sortOp).Next",
+		},
+		{
+			funcs:  []string{sqlFunc, colexecFunc},
+			origin: otherOrigin,
+		},
+		{
+			funcs:       []string{thirdParty, "strings.Repeat", colexecFunc, sqlFunc},
+			origin:      thirdPartyOrigin,
+			emittedFrom: "colexec.(*sortOp).Next",
+		},
+		{
+			// Third-party code called by other parts of CockroachDB is not the
+			// responsibility of the vectorized engine.
+			funcs:  []string{thirdParty, sqlFunc, colexecFunc},
+			origin: otherOrigin,
+		},
+	} {
+		origin, emittedFrom := panicOrigin(makeStackTrace(tc.funcs...))
+		require.Equal(t, tc.origin, origin, "%v", tc.funcs)
+		require.Equal(t, tc.emittedFrom, emittedFrom, "%v", tc.funcs)
+	}
+}
+
+func TestVectorizedPanicToError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const emittedFrom = "colexec.(*sortOp).Next"
+
+	// Expected errors are propagated as is.
+	expected := pgerror.New(pgcode.DivisionByZero, "division by zero")
+	err := vectorizedPanicToError(newNotVectorizedInternalError(expected), emittedFrom)
+	require.Equal(t, expected, err)
+	storageErr := NewStorageError(errors.New("storage"))
+	require.Equal(t, storageErr, vectorizedPanicToError(storageErr, emittedFrom))
+
+	// Unexpected errors become internal errors with the operator context.
+	for _, obj := range []interface{}{errors.New("surprise"), "not an error"} {
+		err = vectorizedPanicToError(obj, emittedFrom)
+		require.True(t, errors.HasAssertionFailure(err))
+		require.Equal(t, pgcode.Internal, pgerror.GetPGCode(err))
+		require.Contains(t, errors.FlattenDetails(err), emittedFrom)
+	}
+}
+
+func TestShortFuncName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, "colexec.(*sortOp).Next",
+		shortFuncName("github.com/cockroachdb/cockroach/pkg/sql/colexec.(*sortOp).Next(0xc000010000, 0x1)"))
+	require.Equal(t, "strings.Repeat", shortFuncName("strings.Repeat(...)"))
+	require.Equal(t, "colexec.glob..func1", shortFuncName("github.com/cockroachdb/cockroach/pkg/sql/colexec.glob..func1()"))
+}
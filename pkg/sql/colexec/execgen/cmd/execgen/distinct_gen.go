@@ -29,6 +29,7 @@ func genDistinctOps(wr io.Writer) error {
 	s := string(d)
 
 	// Replace the template variables.
+	s = strings.Replace(s, "_SIMD_TYPE", "{{.SIMDType}}", -1)
 	s = strings.Replace(s, "_GOTYPE", "{{.LTyp.GoTypeName}}", -1)
 	s = strings.Replace(s, "_GOTYPESLICE", "{{.LTyp.GoTypeSliceName}}", -1)
 	s = strings.Replace(s, "_TYPES_T", "coltypes.{{.LTyp}}", -1)
@@ -27,6 +27,7 @@ func genHashJoiner(wr io.Writer) error {
 
 	s := string(t)
 
+	s = strings.Replace(s, "_SIMD_TYPE", "{{.Global.SIMDType}}", -1)
 	s = strings.Replace(s, "_TYPES_T", "coltypes.{{.LTyp}}", -1)
 	s = strings.Replace(s, "_TYPE", "{{.LTyp}}", -1)
 	s = strings.Replace(s, "_TemplateType", "{{.LTyp}}", -1)
@@ -76,6 +76,15 @@ func genLikeOps(wr io.Writer) error {
 				return fmt.Sprintf("%s = bytes.HasSuffix(%s, %s)", target, l, r)
 			},
 		},
+		{
+			Name:    "Contains",
+			LTyp:    coltypes.Bytes,
+			RTyp:    coltypes.Bytes,
+			RGoType: "[]byte",
+			AssignFunc: func(_ overload, target, l, r string) string {
+				return fmt.Sprintf("%s = bytes.Contains(%s, %s)", target, l, r)
+			},
+		},
 		{
 			Name:    "Regexp",
 			LTyp:    coltypes.Bytes,
@@ -103,6 +112,15 @@ func genLikeOps(wr io.Writer) error {
 				return fmt.Sprintf("%s = !bytes.HasSuffix(%s, %s)", target, l, r)
 			},
 		},
+		{
+			Name:    "NotContains",
+			LTyp:    coltypes.Bytes,
+			RTyp:    coltypes.Bytes,
+			RGoType: "[]byte",
+			AssignFunc: func(_ overload, target, l, r string) string {
+				return fmt.Sprintf("%s = !bytes.Contains(%s, %s)", target, l, r)
+			},
+		},
 		{
 			Name:    "NotRegexp",
 			LTyp:    coltypes.Bytes,
@@ -162,6 +162,21 @@ func (o overload) UnaryAssign(target, v string) string {
 	return fmt.Sprintf("%s = %s(%s)", target, o.OpStr, v)
 }
 
+// SIMDType returns the type suffix of the colsimd kernels that implement the
+// overload (for example, "Int64" for colsimd.HashInt64), or the empty string
+// if the overload has no such kernels. Only hash overloads and comparison
+// overloads whose inputs are all int64s or all float64s have them.
+func (o overload) SIMDType() string {
+	if o.IsBinOp || (o.IsCmpOp && o.LTyp != o.RTyp) {
+		return ""
+	}
+	switch o.LTyp {
+	case coltypes.Int64, coltypes.Float64:
+		return o.LTyp.String()
+	}
+	return ""
+}
+
 type castOverload struct {
 	FromTyp    coltypes.T
 	ToTyp      coltypes.T
@@ -207,6 +222,32 @@ func floatToInt(intSize int, floatSize int) func(string, string) string {
 	}
 }
 
+func decimalToInt(intSize int) func(string, string) string {
+	return func(to, from string) string {
+		// The range check is only needed if the result is narrower than the int64
+		// returned by apd.
+		rangeCheck := ""
+		if intSize < 64 {
+			rangeCheck = fmt.Sprintf(" || tmpInt < math.MinInt%[1]d || tmpInt > math.MaxInt%[1]d", intSize)
+		}
+		convStr := `
+			{
+				var tmpDec apd.Decimal
+				_, tmpErr := tree.DecimalCtx.RoundToIntegralValue(&tmpDec, &%[2]s)
+				if tmpErr != nil {
+					execerror.NonVectorizedPanic(tmpErr)
+				}
+				tmpInt, tmpErr := tmpDec.Int64()
+				if tmpErr != nil%[4]s {
+					execerror.NonVectorizedPanic(tree.ErrIntOutOfRange)
+				}
+				%[1]s = int%[3]d(tmpInt)
+			}
+		`
+		return fmt.Sprintf(convStr, to, from, intSize, rangeCheck)
+	}
+}
+
 func numToBool(to, from string) string {
 	convStr := `
 		%[1]s = %[2]s != 0
@@ -245,35 +286,7 @@ func init() {
 	for _, leftType := range inputTypes {
 		for _, rightType := range coltypes.CompatibleTypes[leftType] {
 			customizer := typeCustomizers[coltypePair{leftType, rightType}]
-			for _, op := range binOps {
-				// Skip types that don't have associated binary ops.
-				retType, ok := binOpOutputTypes[op][coltypePair{leftType, rightType}]
-				if !ok {
-					continue
-				}
-				ov := &overload{
-					Name:      binaryOpName[op],
-					BinOp:     op,
-					IsBinOp:   true,
-					OpStr:     binaryOpInfix[op],
-					LTyp:      leftType,
-					RTyp:      rightType,
-					LGoType:   leftType.GoTypeName(),
-					RGoType:   rightType.GoTypeName(),
-					RetTyp:    retType,
-					RetGoType: retType.GoTypeName(),
-				}
-				if customizer != nil {
-					if b, ok := customizer.(binOpTypeCustomizer); ok {
-						ov.AssignFunc = b.getBinOpAssignFunc()
-					}
-				}
-				binaryOpOverloads = append(binaryOpOverloads, ov)
-				anyTypeBinaryOpToOverloads[op] = append(anyTypeBinaryOpToOverloads[op], ov)
-				if leftType == rightType {
-					sameTypeBinaryOpToOverloads[op] = append(sameTypeBinaryOpToOverloads[op], ov)
-				}
-			}
+			registerBinOpOverloads(leftType, rightType, binOps)
 			for _, op := range cmpOps {
 				opStr := comparisonOpInfix[op]
 				ov := &overload{
@@ -332,6 +345,15 @@ func init() {
 		}
 		hashOverloads = append(hashOverloads, ov)
 	}
+	// Some binary operators (namely, datetime arithmetic) are supported between
+	// types that aren't comparable with each other.
+	for _, leftType := range inputTypes {
+		for _, rightType := range inputTypes {
+			if !areCompatibleTypes(leftType, rightType) {
+				registerBinOpOverloads(leftType, rightType, binOps)
+			}
+		}
+	}
 
 	// Build cast overloads. We omit cases of type casts that we do not support.
 	castOverloads = make(map[coltypes.T][]castOverload)
@@ -354,15 +376,23 @@ func init() {
 						`
 						return fmt.Sprintf(convStr, to, from)
 					}
+				case coltypes.Decimal:
+					ov.AssignFunc = func(to, from string) string {
+						convStr := `
+							%[1]s = apd.Decimal{}
+							if %[2]s {
+								%[1]s.SetFinite(1, 0)
+							}
+						`
+						return fmt.Sprintf(convStr, to, from)
+					}
 				}
 				castOverloads[from] = append(castOverloads[from], ov)
 			}
 		case coltypes.Bytes:
-			// TODO (rohany): It's unclear what to do here in the bytes case.
-			// There are different conversion rules for the multiple things
-			// that a bytes type can implemented, but we don't know each of the
-			// things is contained here. Additionally, we don't really know
-			// what to do even if it is a bytes to bytes operation here.
+			// There are different conversion rules for the multiple SQL types
+			// that are stored as bytes, so these casts aren't generated and are
+			// performed by castOpDatum instead.
 			for _, to := range inputTypes {
 				ov := castOverload{FromTyp: from, ToTyp: to, ToGoTyp: to.GoTypeName()}
 				switch to {
@@ -382,6 +412,12 @@ func init() {
 					}
 				case coltypes.Decimal:
 					ov.AssignFunc = castIdentity
+				case coltypes.Int16:
+					ov.AssignFunc = decimalToInt(16)
+				case coltypes.Int32:
+					ov.AssignFunc = decimalToInt(32)
+				case coltypes.Int64:
+					ov.AssignFunc = decimalToInt(64)
 				}
 				castOverloads[from] = append(castOverloads[from], ov)
 			}
@@ -453,6 +489,52 @@ func init() {
 	}
 }
 
+// registerBinOpOverloads builds the overloads of the given binary operators
+// that are supported between leftType and rightType.
+func registerBinOpOverloads(leftType, rightType coltypes.T, binOps []tree.BinaryOperator) {
+	customizer := typeCustomizers[coltypePair{leftType, rightType}]
+	for _, op := range binOps {
+		// Skip types that don't have associated binary ops.
+		retType, ok := binOpOutputTypes[op][coltypePair{leftType, rightType}]
+		if !ok {
+			continue
+		}
+		ov := &overload{
+			Name:      binaryOpName[op],
+			BinOp:     op,
+			IsBinOp:   true,
+			OpStr:     binaryOpInfix[op],
+			LTyp:      leftType,
+			RTyp:      rightType,
+			LGoType:   leftType.GoTypeName(),
+			RGoType:   rightType.GoTypeName(),
+			RetTyp:    retType,
+			RetGoType: retType.GoTypeName(),
+		}
+		if customizer != nil {
+			if b, ok := customizer.(binOpTypeCustomizer); ok {
+				ov.AssignFunc = b.getBinOpAssignFunc()
+			}
+		}
+		binaryOpOverloads = append(binaryOpOverloads, ov)
+		anyTypeBinaryOpToOverloads[op] = append(anyTypeBinaryOpToOverloads[op], ov)
+		if leftType == rightType {
+			sameTypeBinaryOpToOverloads[op] = append(sameTypeBinaryOpToOverloads[op], ov)
+		}
+	}
+}
+
+// areCompatibleTypes returns whether rightType is one of the
+// coltypes.CompatibleTypes of leftType.
+func areCompatibleTypes(leftType, rightType coltypes.T) bool {
+	for _, t := range coltypes.CompatibleTypes[leftType] {
+		if t == rightType {
+			return true
+		}
+	}
+	return false
+}
+
 // typeCustomizer is a marker interface for something that implements one or
 // more of binOpTypeCustomizer and cmpOpTypeCustomizer.
 //
@@ -540,6 +622,26 @@ type intFloatCustomizer struct{}
 // timestampCustomizer is necessary since time.Time doesn't have infix operators.
 type timestampCustomizer struct{}
 
+// intervalCustomizer is necessary since duration.Duration doesn't have infix
+// operators.
+type intervalCustomizer struct{}
+
+// timestampIntervalCustomizer supports mixed type expressions with a timestamp
+// left-hand side and an interval right-hand side.
+type timestampIntervalCustomizer struct{}
+
+// intervalTimestampCustomizer supports mixed type expressions with an interval
+// left-hand side and a timestamp right-hand side.
+type intervalTimestampCustomizer struct{}
+
+// intervalIntCustomizer supports mixed type expressions with an interval
+// left-hand side and an int right-hand side.
+type intervalIntCustomizer struct{}
+
+// intIntervalCustomizer supports mixed type expressions with an int left-hand
+// side and an interval right-hand side.
+type intIntervalCustomizer struct{}
+
 func (boolCustomizer) getCmpOpCompareFunc() compareFunc {
 	return func(target, l, r string) string {
 		args := map[string]string{"Target": target, "Left": l, "Right": r}
@@ -623,7 +725,17 @@ func (decimalCustomizer) getBinOpAssignFunc() assignFunc {
 
 func (decimalCustomizer) getHashAssignFunc() assignFunc {
 	return func(op overload, target, v, _ string) string {
-		return fmt.Sprintf(`b := []byte(%[1]s.String())`, v) +
+		// Decimals that compare equal (like 1.0 and 1.00, or 0 and -0) need to
+		// hash to the same value, so the hash is computed from the canonical
+		// form of the decimal, with the trailing zeros removed and without the
+		// sign of zero.
+		return fmt.Sprintf(`
+			var canonical apd.Decimal
+			canonical.Reduce(&%[1]s)
+			if canonical.IsZero() {
+				canonical.Negative = false
+			}
+			b := []byte(canonical.String())`, v) +
 			fmt.Sprintf(hashByteSliceString, target, "b")
 	}
 }
@@ -632,10 +744,12 @@ func (c floatCustomizer) getHashAssignFunc() assignFunc {
 	return func(op overload, target, v, _ string) string {
 		// TODO(yuzefovich): think through whether this is appropriate way to hash
 		// NaNs.
+		// Zero and negative zero compare equal but have different bit patterns,
+		// so both are hashed as zero.
 		return fmt.Sprintf(
 			`
 			f := %[2]s
-			if math.IsNaN(float64(f)) {
+			if math.IsNaN(float64(f)) || f == 0 {
 				f = 0
 			}
 			%[1]s = f%[3]dhash(noescape(unsafe.Pointer(&f)), %[1]s)
@@ -1030,12 +1144,131 @@ func (timestampCustomizer) getHashAssignFunc() assignFunc {
 	}
 }
 
+func (timestampCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Minus:
+			// Inline the code from the TIMESTAMP - TIMESTAMP builtin.
+			return fmt.Sprintf(`
+			{
+				nanos := %[2]s.Sub(%[3]s).Nanoseconds()
+				%[1]s = duration.MakeDuration(nanos, 0, 0)
+			}
+			`, target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalCustomizer) getCmpOpCompareFunc() compareFunc {
+	return func(target, l, r string) string {
+		return fmt.Sprintf("%s = %s.Compare(%s)", target, l, r)
+	}
+}
+
+func (intervalCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(`%s = %s.Add(%s)`, target, l, r)
+		case tree.Minus:
+			return fmt.Sprintf(`%s = %s.Sub(%s)`, target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalCustomizer) getHashAssignFunc() assignFunc {
+	return func(op overload, target, v, _ string) string {
+		// Intervals that compare as equal have the same sortNanos, and the ones
+		// that are too large to be encoded all hash the same.
+		return fmt.Sprintf(`
+		  s, _, _, _ := %[2]s.Encode()
+		  %[1]s = memhash64(noescape(unsafe.Pointer(&s)), %[1]s)
+		`, target, v)
+	}
+}
+
+// The additions of intervals to timestamps are done in the compatible
+// duration.AdditionMode; the expressions that need the legacy mode are not
+// planned by the vectorized engine.
+
+func (timestampIntervalCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(`%[1]s = duration.Add(duration.AdditionModeCompatible, %[2]s, %[3]s).Round(time.Microsecond)`,
+				target, l, r)
+		case tree.Minus:
+			return fmt.Sprintf(`%[1]s = duration.Add(duration.AdditionModeCompatible, %[2]s, %[3]s.Mul(-1)).Round(time.Microsecond)`,
+				target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalTimestampCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(`%[1]s = duration.Add(duration.AdditionModeCompatible, %[3]s, %[2]s).Round(time.Microsecond)`,
+				target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalIntCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Mult:
+			return fmt.Sprintf(`%s = %s.Mul(int64(%s))`, target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intIntervalCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Mult:
+			return fmt.Sprintf(`%s = %s.Mul(int64(%s))`, target, r, l)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
 func registerTypeCustomizers() {
 	typeCustomizers = make(map[coltypePair]typeCustomizer)
 	registerTypeCustomizer(coltypePair{coltypes.Bool, coltypes.Bool}, boolCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Bytes, coltypes.Bytes}, bytesCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Decimal, coltypes.Decimal}, decimalCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Timestamp, coltypes.Timestamp}, timestampCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Interval, coltypes.Interval}, intervalCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Timestamp, coltypes.Interval}, timestampIntervalCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Interval, coltypes.Timestamp}, intervalTimestampCustomizer{})
+	for _, intType := range coltypes.IntTypes {
+		registerTypeCustomizer(coltypePair{coltypes.Interval, intType}, intervalIntCustomizer{})
+		registerTypeCustomizer(coltypePair{intType, coltypes.Interval}, intIntervalCustomizer{})
+	}
 	for _, leftFloatType := range coltypes.FloatTypes {
 		for _, rightFloatType := range coltypes.FloatTypes {
 			registerTypeCustomizer(coltypePair{leftFloatType, rightFloatType}, floatCustomizer{width: 64})
@@ -1109,6 +1342,18 @@ func registerBinOpOutputTypes() {
 			binOpOutputTypes[tree.Div][coltypePair{leftIntType, rightIntType}] = coltypes.Decimal
 		}
 	}
+
+	// Datetime arithmetic.
+	for _, binOp := range []tree.BinaryOperator{tree.Plus, tree.Minus} {
+		binOpOutputTypes[binOp][coltypePair{coltypes.Timestamp, coltypes.Interval}] = coltypes.Timestamp
+		binOpOutputTypes[binOp][coltypePair{coltypes.Interval, coltypes.Interval}] = coltypes.Interval
+	}
+	binOpOutputTypes[tree.Plus][coltypePair{coltypes.Interval, coltypes.Timestamp}] = coltypes.Timestamp
+	binOpOutputTypes[tree.Minus][coltypePair{coltypes.Timestamp, coltypes.Timestamp}] = coltypes.Interval
+	for _, intType := range coltypes.IntTypes {
+		binOpOutputTypes[tree.Mult][coltypePair{coltypes.Interval, intType}] = coltypes.Interval
+		binOpOutputTypes[tree.Mult][coltypePair{intType, coltypes.Interval}] = coltypes.Interval
+	}
 }
 
 // Avoid unused warning for functions which are only used in templates.
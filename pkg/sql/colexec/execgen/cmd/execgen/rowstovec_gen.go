@@ -68,7 +68,8 @@ func genRowsToVec(wr io.Writer) error {
 	conversionsMap := make(map[types.Family]*columnConversion)
 	for _, ct := range types.OidToType {
 		t := typeconv.FromColumnType(ct)
-		if t == coltypes.Unhandled {
+		if t == coltypes.Unhandled || t == coltypes.Datum {
+			// Datum columns are handled separately in the template.
 			continue
 		}
 
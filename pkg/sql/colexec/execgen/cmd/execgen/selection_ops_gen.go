@@ -27,6 +27,7 @@ func getSelectionOpsTmpl() (*template.Template, error) {
 	}
 
 	s := string(t)
+	s = strings.Replace(s, "_SIMD_TYPE", "{{.SIMDType}}", -1)
 	s = strings.Replace(s, "_OP_CONST_NAME", "sel{{.Name}}{{.LTyp}}{{.RTyp}}ConstOp", -1)
 	s = strings.Replace(s, "_OP_NAME", "sel{{.Name}}{{.LTyp}}{{.RTyp}}Op", -1)
 	s = strings.Replace(s, "_R_GO_TYPE", "{{.RGoType}}", -1)
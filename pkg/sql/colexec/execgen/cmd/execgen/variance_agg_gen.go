@@ -0,0 +1,156 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+)
+
+type varianceAggTmplInfo struct {
+	Type coltypes.T
+}
+
+// UpdateSqrDiff returns the code that adds the value v to the running mean and
+// sum of squared differences of the current group. It mirrors the arithmetic
+// of the float and decimal sqrdiff builtins exactly so that the vectorized
+// results are identical to the row-by-row ones.
+func (a varianceAggTmplInfo) UpdateSqrDiff(v string) string {
+	switch a.Type {
+	case coltypes.Decimal:
+		return fmt.Sprintf(
+			`a.scratch.curCount++
+a.scratch.count.SetInt64(a.scratch.curCount)
+if _, err := tree.IntermediateCtx.Sub(&a.scratch.delta, &%[1]s, &a.scratch.curMean); err != nil {
+	execerror.NonVectorizedPanic(err)
+}
+if _, err := tree.IntermediateCtx.Quo(&a.scratch.tmp, &a.scratch.delta, &a.scratch.count); err != nil {
+	execerror.NonVectorizedPanic(err)
+}
+if _, err := tree.IntermediateCtx.Add(&a.scratch.curMean, &a.scratch.curMean, &a.scratch.tmp); err != nil {
+	execerror.NonVectorizedPanic(err)
+}
+if _, err := tree.IntermediateCtx.Sub(&a.scratch.tmp, &%[1]s, &a.scratch.curMean); err != nil {
+	execerror.NonVectorizedPanic(err)
+}
+if _, err := tree.IntermediateCtx.Mul(&a.scratch.delta, &a.scratch.delta, &a.scratch.tmp); err != nil {
+	execerror.NonVectorizedPanic(err)
+}
+if _, err := tree.IntermediateCtx.Add(&a.scratch.curSqrDiff, &a.scratch.curSqrDiff, &a.scratch.delta); err != nil {
+	execerror.NonVectorizedPanic(err)
+}`,
+			v,
+		)
+	case coltypes.Float64:
+		return fmt.Sprintf(
+			`a.scratch.curCount++
+a.scratch.delta = %[1]s - a.scratch.curMean
+a.scratch.curMean += a.scratch.delta / float64(a.scratch.curCount)
+a.scratch.curSqrDiff += a.scratch.delta * (%[1]s - a.scratch.curMean)`,
+			v,
+		)
+	default:
+		execerror.VectorizedInternalPanic("unsupported variance agg type")
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+// AssignResult returns the code that assigns the sum of squared differences,
+// the sample variance or the sample standard deviation of the current group
+// (depending on a.kind) to target.
+func (a varianceAggTmplInfo) AssignResult(target string) string {
+	switch a.Type {
+	case coltypes.Decimal:
+		return fmt.Sprintf(
+			`a.scratch.tmp.Reduce(&a.scratch.curSqrDiff)
+if a.kind == varianceKindSqrDiff {
+	%[1]s.Set(&a.scratch.tmp)
+} else {
+	a.scratch.count.SetInt64(a.scratch.curCount - 1)
+	if _, err := tree.DecimalCtx.Quo(&%[1]s, &a.scratch.tmp, &a.scratch.count); err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+	%[1]s.Reduce(&%[1]s)
+	if a.kind == varianceKindStdDev {
+		if _, err := tree.DecimalCtx.Sqrt(&%[1]s, &%[1]s); err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+	}
+}`,
+			target,
+		)
+	case coltypes.Float64:
+		return fmt.Sprintf(
+			`switch a.kind {
+case varianceKindSqrDiff:
+	%[1]s = a.scratch.curSqrDiff
+case varianceKindVariance:
+	%[1]s = a.scratch.curSqrDiff / (float64(a.scratch.curCount) - 1)
+case varianceKindStdDev:
+	%[1]s = math.Sqrt(a.scratch.curSqrDiff / (float64(a.scratch.curCount) - 1))
+}`,
+			target,
+		)
+	default:
+		execerror.VectorizedInternalPanic("unsupported variance agg type")
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+// Avoid unused warnings. These methods are used in the template.
+var (
+	_ = varianceAggTmplInfo{}.UpdateSqrDiff
+	_ = varianceAggTmplInfo{}.AssignResult
+)
+
+func genVarianceAgg(wr io.Writer) error {
+	t, err := ioutil.ReadFile("pkg/sql/colexec/variance_agg_tmpl.go")
+	if err != nil {
+		return err
+	}
+
+	s := string(t)
+
+	s = strings.Replace(s, "_GOTYPE", "{{.Type.GoTypeName}}", -1)
+	s = strings.Replace(s, "_TYPES_T", "coltypes.{{.Type}}", -1)
+	s = strings.Replace(s, "_TYPE", "{{.Type}}", -1)
+	s = strings.Replace(s, "_TemplateType", "{{.Type}}", -1)
+
+	updateSqrDiffRe := regexp.MustCompile(`_UPDATE_SQR_DIFF\((.*)\)`)
+	s = updateSqrDiffRe.ReplaceAllString(s, "{{.UpdateSqrDiff $1}}")
+	assignResultRe := regexp.MustCompile(`_ASSIGN_RESULT\((.*)\)`)
+	s = assignResultRe.ReplaceAllString(s, "{{.AssignResult $1}}")
+
+	accumulateVariance := makeFunctionRegex("_ACCUMULATE_VARIANCE", 4)
+	s = accumulateVariance.ReplaceAllString(s, `{{template "accumulateVariance" buildDict "Global" . "HasNulls" $4}}`)
+
+	tmpl, err := template.New("variance_agg").Funcs(template.FuncMap{"buildDict": buildDict}).Parse(s)
+	if err != nil {
+		return err
+	}
+
+	// Integer inputs aren't supported since their variance is a decimal, and
+	// aggregates whose output type differs from the input type aren't supported.
+	return tmpl.Execute(wr, []varianceAggTmplInfo{{Type: coltypes.Decimal}, {Type: coltypes.Float64}})
+}
+
+func init() {
+	registerGenerator(genVarianceAgg, "variance_agg.eg.go")
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
 
@@ -28,6 +29,9 @@ const (
 	selectivityTagSuffix   = "selectivity"
 	stallTimeTagSuffix     = "time.stall"
 	executionTimeTagSuffix = "time.execution"
+	maxMemoryTagSuffix     = "mem.max"
+	bloomFilterTagSuffix   = "bloomfilter.selectivity"
+	contentionTagSuffix    = "contention.time"
 )
 
 // Stats is part of SpanStats interface.
@@ -42,12 +46,22 @@ func (vs *VectorizedStats) Stats() map[string]string {
 	if vs.NumBatches > 0 {
 		selectivity = float64(vs.NumTuples) / float64(int64(coldata.BatchSize())*vs.NumBatches)
 	}
-	return map[string]string{
+	stats := map[string]string{
 		batchesOutputTagSuffix: fmt.Sprintf("%d", vs.NumBatches),
 		tuplesOutputTagSuffix:  fmt.Sprintf("%d", vs.NumTuples),
 		selectivityTagSuffix:   fmt.Sprintf("%.2f", selectivity),
 		timeSuffix:             fmt.Sprintf("%v", vs.Time.Round(time.Microsecond)),
 	}
+	if vs.MaxAllocatedMem != 0 {
+		stats[maxMemoryTagSuffix] = humanizeutil.IBytes(vs.MaxAllocatedMem)
+	}
+	if vs.BloomFilterInputTuples != 0 {
+		stats[bloomFilterTagSuffix] = fmt.Sprintf("%.2f", vs.bloomFilterSelectivity())
+	}
+	if vs.ContentionTime != 0 {
+		stats[contentionTagSuffix] = fmt.Sprintf("%v", vs.ContentionTime.Round(time.Microsecond))
+	}
+	return stats
 }
 
 const (
@@ -56,6 +70,9 @@ const (
 	selectivityQueryPlanSuffix   = "selectivity"
 	stallTimeQueryPlanSuffix     = "stall time"
 	executionTimeQueryPlanSuffix = "execution time"
+	maxMemoryQueryPlanSuffix     = "max memory used"
+	bloomFilterQueryPlanSuffix   = "bloom filter selectivity"
+	contentionQueryPlanSuffix    = "contention time"
 )
 
 // StatsForQueryPlan is part of DistSQLSpanStats interface.
@@ -70,10 +87,40 @@ func (vs *VectorizedStats) StatsForQueryPlan() []string {
 	if vs.NumBatches > 0 {
 		selectivity = float64(vs.NumTuples) / float64(int64(coldata.BatchSize())*vs.NumBatches)
 	}
-	return []string{
+	stats := []string{
 		fmt.Sprintf("%s: %d", batchesOutputQueryPlanSuffix, vs.NumBatches),
 		fmt.Sprintf("%s: %d", tuplesOutputQueryPlanSuffix, vs.NumTuples),
 		fmt.Sprintf("%s: %.2f", selectivityQueryPlanSuffix, selectivity),
 		fmt.Sprintf("%s: %v", timeSuffix, vs.Time.Round(time.Microsecond)),
 	}
+	// Only the processors with buffering operators allocate memory that is
+	// tracked separately, so we omit the memory usage for the others.
+	if vs.MaxAllocatedMem != 0 {
+		stats = append(stats, fmt.Sprintf("%s: %s", maxMemoryQueryPlanSuffix, humanizeutil.IBytes(vs.MaxAllocatedMem)))
+	}
+	// Only the hash joiners that used a bloom filter on their probe side report
+	// its selectivity.
+	if vs.BloomFilterInputTuples != 0 {
+		stats = append(stats, fmt.Sprintf(
+			"%s: %.2f (%d/%d tuples)", bloomFilterQueryPlanSuffix, vs.bloomFilterSelectivity(),
+			vs.BloomFilterOutputTuples, vs.BloomFilterInputTuples,
+		))
+	}
+	// Only the scans whose KV requests waited on conflicting transactions
+	// report the time they spent waiting.
+	if vs.ContentionTime != 0 {
+		stats = append(stats, fmt.Sprintf(
+			"%s: %v", contentionQueryPlanSuffix, vs.ContentionTime.Round(time.Microsecond),
+		))
+	}
+	return stats
+}
+
+// bloomFilterSelectivity returns the fraction of the probe tuples that passed
+// through the bloom filter.
+func (vs *VectorizedStats) bloomFilterSelectivity() float64 {
+	if vs.BloomFilterInputTuples == 0 {
+		return 0
+	}
+	return float64(vs.BloomFilterOutputTuples) / float64(vs.BloomFilterInputTuples)
 }
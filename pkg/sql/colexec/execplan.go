@@ -16,6 +16,7 @@ import (
 	"math"
 	"reflect"
 
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
@@ -109,6 +111,18 @@ type NewColOperatorResult struct {
 	IsStreaming            bool
 	BufferingOpMemMonitors []*mon.BytesMonitor
 	BufferingOpMemAccounts []*mon.BoundAccount
+	// BloomFilterStats are the stats of the bloom filters used by the hash
+	// joiners that make up Op (if any).
+	BloomFilterStats []*BloomFilterStats
+	// KVReaders are the operators that make up Op and read from the KV layer
+	// (if any).
+	KVReaders []KVReader
+	// ToClose is a slice of components that need to be closed once the flow
+	// is done, like the disk-backed operators that hold on to temporary files.
+	ToClose []IdempotentCloser
+	// IsWrapped is set when there is no columnar operator equivalent to the
+	// processor, so Op wraps a row-execution processor.
+	IsWrapped bool
 }
 
 // joinerPlanningState is a helper struct used when creating a hash or merge
@@ -157,15 +171,8 @@ func createJoiner(
 
 	post := &spec.Post
 
-	var leftTypes, rightTypes []coltypes.T
-	leftTypes, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-	if err != nil {
-		return err
-	}
-	rightTypes, err = typeconv.FromColumnTypes(spec.Input[1].ColumnTypes)
-	if err != nil {
-		return err
-	}
+	leftTypes := typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
+	rightTypes := typeconv.FromColumnTypes(spec.Input[1].ColumnTypes)
 
 	nLeftCols := uint32(len(leftTypes))
 	nRightCols := uint32(len(rightTypes))
@@ -211,8 +218,13 @@ func createJoiner(
 		for i := uint32(0); i < nLeftCols; i++ {
 			leftOutCols = append(leftOutCols, i)
 		}
-		for i := uint32(0); i < nRightCols; i++ {
-			rightOutCols = append(rightOutCols, i)
+		// The joins that only emit the left tuples (as well as the set
+		// operations) don't output the right columns.
+		if joinType != sqlbase.JoinType_LEFT_SEMI && joinType != sqlbase.JoinType_LEFT_ANTI &&
+			!joinType.IsSetOpJoin() {
+			for i := uint32(0); i < nRightCols; i++ {
+				rightOutCols = append(rightOutCols, i)
+			}
 		}
 	}
 
@@ -246,12 +258,83 @@ func createJoiner(
 			result.InternalMemUsage += internalMemOp.InternalMemoryUsage()
 		}
 
-		err = result.planFilterExpr(ctx, flowCtx.NewEvalCtx(), *onExpr, onExprPlanning.indexVarMap, acc)
+		err = result.planFilterExpr(ctx, flowCtx, *onExpr, onExprPlanning.indexVarMap, acc)
 		onExprPlanning.projectOutExtraCols(result)
 	}
 	return err
 }
 
+// typeIsComparable returns whether the values of typ can be compared (for both
+// equality and ordering) using their physical representation in the vectorized
+// engine.
+func typeIsComparable(typ *types.T) bool {
+	// JSON values are stored in their encoded form which doesn't preserve the
+	// semantics of JSON comparison (e.g. 1 and 1.0 are equal).
+	if typ.Family() == types.JsonFamily {
+		return false
+	}
+	// The values of the types without a native columnar representation can
+	// only be passed through.
+	return typeconv.FromColumnType(typ) != coltypes.Datum
+}
+
+// checkNoDatumColumns returns an error if any of colTypes doesn't have a
+// native columnar representation. It is used for the operators that need to
+// operate on every column of their input.
+func checkNoDatumColumns(colTypes []types.T) error {
+	for i := range colTypes {
+		if typeconv.FromColumnType(&colTypes[i]) == coltypes.Datum {
+			return errors.Newf("%s is not supported", colTypes[i].String())
+		}
+	}
+	return nil
+}
+
+// checkKeyColumns returns an error if any of the columns in cols (which are
+// used as equality or ordering columns) is of a type that cannot be compared
+// by the vectorized engine.
+func checkKeyColumns(colTypes []types.T, cols []uint32) error {
+	for _, col := range cols {
+		if !typeIsComparable(&colTypes[col]) {
+			return errors.Newf("%s is not supported as a key column", colTypes[col].String())
+		}
+	}
+	return nil
+}
+
+// typeIsHashable returns whether the values of typ can be used as the keys of
+// the hash table. In addition to the comparable types, this includes the types
+// without a native columnar representation that have a key encoding (like
+// collated strings), whose keys are compared using that encoding.
+func typeIsHashable(typ *types.T) bool {
+	if typeIsComparable(typ) {
+		return true
+	}
+	return typeconv.FromColumnType(typ) == coltypes.Datum && sqlbase.ColumnTypeIsIndexable(typ)
+}
+
+// checkHashKeyColumns is the same as checkKeyColumns but for the equality
+// columns of the hash joiner.
+func checkHashKeyColumns(colTypes []types.T, cols []uint32) error {
+	for _, col := range cols {
+		if !typeIsHashable(&colTypes[col]) {
+			return errors.Newf("%s is not supported as a key column", colTypes[col].String())
+		}
+	}
+	return nil
+}
+
+// checkOrderingColumns is the same as checkKeyColumns but for the columns of
+// an ordering.
+func checkOrderingColumns(colTypes []types.T, ordering execinfrapb.Ordering) error {
+	for _, col := range ordering.Columns {
+		if !typeIsComparable(&colTypes[col.ColIdx]) {
+			return errors.Newf("%s is not supported as an ordering column", colTypes[col.ColIdx].String())
+		}
+	}
+	return nil
+}
+
 // isSupported checks whether we have a columnar operator equivalent to a
 // processor described by spec. Note that it doesn't perform any other checks
 // (like validity of the number of inputs).
@@ -266,10 +349,29 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		if core.TableReader.IsCheck {
 			return false, errors.Newf("scrub table reader is unsupported in vectorized")
 		}
+		if core.TableReader.SkipScanPrefixLen > 0 {
+			return false, errors.Newf("skip scan table reader is unsupported in vectorized")
+		}
+		return true, nil
+
+	case core.JoinReader != nil:
+		if len(core.JoinReader.LookupColumns) != 0 {
+			return false, errors.Newf("lookup join is unsupported in vectorized")
+		}
+		if core.JoinReader.IndexIdx != 0 {
+			return false, errors.Newf("index join must be against primary index")
+		}
 		return true, nil
 
 	case core.Aggregator != nil:
 		aggSpec := core.Aggregator
+		if err := checkKeyColumns(spec.Input[0].ColumnTypes, aggSpec.GroupCols); err != nil {
+			return false, err
+		}
+		var orderedGroupCols util.FastIntSet
+		for _, col := range aggSpec.OrderedGroupCols {
+			orderedGroupCols.Add(int(col))
+		}
 		for _, agg := range aggSpec.Aggregations {
 			if agg.Distinct {
 				return false, errors.Newf("distinct aggregation not supported")
@@ -277,13 +379,32 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 			if agg.FilterColIdx != nil {
 				return false, errors.Newf("filtering aggregation not supported")
 			}
-			if len(agg.Arguments) > 0 {
-				return false, errors.Newf("aggregates with arguments not supported")
-			}
 			var inputTypes []types.T
 			for _, colIdx := range agg.ColIdx {
 				inputTypes = append(inputTypes, spec.Input[0].ColumnTypes[colIdx])
 			}
+			if len(agg.Arguments) > 0 {
+				// The only supported constant argument is the delimiter of
+				// string_agg, which has the same type as the aggregated column.
+				if agg.Func != execinfrapb.AggregatorSpec_STRING_AGG ||
+					len(agg.Arguments) != 1 || len(agg.ColIdx) != 1 {
+					return false, errors.Newf("aggregates with arguments not supported")
+				}
+				inputTypes = append(inputTypes, inputTypes[0])
+			}
+			if isOrderSensitiveAggFn(agg.Func) {
+				for _, col := range aggSpec.GroupCols {
+					if !orderedGroupCols.Contains(int(col)) {
+						return false, errors.Newf("%s is only supported with ordered grouping columns", agg.Func)
+					}
+				}
+			}
+			switch agg.Func {
+			case execinfrapb.AggregatorSpec_MIN, execinfrapb.AggregatorSpec_MAX:
+				if err := checkKeyColumns(spec.Input[0].ColumnTypes, agg.ColIdx); err != nil {
+					return false, err
+				}
+			}
 			if supported, err := isAggregateSupported(agg.Func, inputTypes); !supported {
 				return false, err
 			}
@@ -291,6 +412,9 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		return true, nil
 
 	case core.Distinct != nil:
+		if err := checkKeyColumns(spec.Input[0].ColumnTypes, core.Distinct.DistinctColumns); err != nil {
+			return false, err
+		}
 		var orderedCols util.FastIntSet
 		for _, col := range core.Distinct.OrderedColumns {
 			orderedCols.Add(int(col))
@@ -310,12 +434,18 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 			core.HashJoiner.Type != sqlbase.JoinType_INNER {
 			return false, errors.Newf("can't plan non-inner hash join with on expressions")
 		}
-		if core.HashJoiner.Type == sqlbase.JoinType_LEFT_ANTI {
-			return false, errors.Newf("LEFT ANTI hash join is unsupported")
+		if err := checkHashKeyColumns(spec.Input[0].ColumnTypes, core.HashJoiner.LeftEqColumns); err != nil {
+			return false, err
+		}
+		if err := checkHashKeyColumns(spec.Input[1].ColumnTypes, core.HashJoiner.RightEqColumns); err != nil {
+			return false, err
 		}
 		return true, nil
 
 	case core.MergeJoiner != nil:
+		if core.MergeJoiner.NullEquality {
+			return false, errors.Newf("merge join with null equality is unsupported")
+		}
 		if !core.MergeJoiner.OnExpr.Empty() {
 			switch core.MergeJoiner.Type {
 			case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
@@ -323,9 +453,25 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 				return false, errors.Errorf("can only plan INNER, LEFT SEMI, and LEFT ANTI merge joins with ON expressions")
 			}
 		}
+		if err := checkOrderingColumns(spec.Input[0].ColumnTypes, core.MergeJoiner.LeftOrdering); err != nil {
+			return false, err
+		}
+		if err := checkOrderingColumns(spec.Input[1].ColumnTypes, core.MergeJoiner.RightOrdering); err != nil {
+			return false, err
+		}
+		// The merge joiner builds its output with the templated code for every
+		// column, so all input columns need to have a columnar representation.
+		for _, input := range spec.Input {
+			if err := checkNoDatumColumns(input.ColumnTypes); err != nil {
+				return false, err
+			}
+		}
 		return true, nil
 
 	case core.Sorter != nil:
+		if err := checkOrderingColumns(spec.Input[0].ColumnTypes, core.Sorter.OutputOrdering); err != nil {
+			return false, err
+		}
 		return true, nil
 
 	case core.Windower != nil:
@@ -342,6 +488,12 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		if wf.Func.AggregateFunc != nil {
 			return false, errors.Newf("aggregate functions used as window functions are not supported")
 		}
+		if err := checkKeyColumns(spec.Input[0].ColumnTypes, core.Windower.PartitionBy); err != nil {
+			return false, err
+		}
+		if err := checkOrderingColumns(spec.Input[0].ColumnTypes, wf.Ordering); err != nil {
+			return false, err
+		}
 
 		switch *wf.Func.WindowFunc {
 		case execinfrapb.WindowerSpec_ROW_NUMBER:
@@ -352,6 +504,23 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		}
 		return true, nil
 
+	case core.InterleavedReaderJoiner != nil:
+		// There is no columnar equivalent of the interleaved reader joiner, so it
+		// is always wrapped. The metadata it produces (the misplanned ranges and
+		// the final state of the leaf txn) is drained through the Columnarizer.
+		return false, errors.Newf("interleaved reader joiner is unsupported in vectorized")
+
+	case core.Sampler != nil:
+		for _, s := range core.Sampler.Sketches {
+			if s.SketchType != execinfrapb.SketchType_HLL_PLUS_PLUS_V1 {
+				return false, errors.Newf("sketch type %s is not supported", s.SketchType)
+			}
+			if len(s.Columns) != 1 {
+				return false, errors.Newf("multi-column sketches are not supported")
+			}
+		}
+		return true, nil
+
 	default:
 		return false, errors.Newf("unsupported processor core %q", core)
 	}
@@ -451,7 +620,7 @@ func NewColOperator(
 		// buffering operator (even if it is a buffering processor). This is not a
 		// problem for memory accounting because each processor does that on its
 		// own, so the used memory will be accounted for.
-		result.Op, result.IsStreaming = c, true
+		result.Op, result.IsStreaming, result.IsWrapped = c, true, true
 		result.MetadataSources = append(result.MetadataSources, c)
 	} else {
 		switch {
@@ -466,12 +635,15 @@ func NewColOperator(
 				return result, err
 			}
 			var scanOp *colBatchScan
-			scanOp, err = newColBatchScan(NewAllocator(ctx, streamingMemAccount), flowCtx, core.TableReader, post)
+			// The filters that colBatchScan evaluates itself are removed from the
+			// post-processing spec that is planned below.
+			scanOp, post, err = newColBatchScan(NewAllocator(ctx, streamingMemAccount), flowCtx, core.TableReader, post)
 			if err != nil {
 				return result, err
 			}
 			result.Op, result.IsStreaming = scanOp, true
 			result.MetadataSources = append(result.MetadataSources, scanOp)
+			result.KVReaders = append(result.KVReaders, scanOp)
 			// colBatchScan is wrapped with a cancel checker below, so we need to
 			// log its creation separately.
 			log.VEventf(ctx, 1, "made op %T\n", result.Op)
@@ -485,6 +657,22 @@ func NewColOperator(
 			result.Op = NewCancelChecker(result.Op)
 			returnMutations := core.TableReader.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
 			result.ColumnTypes = core.TableReader.Table.ColumnTypesWithMutations(returnMutations)
+		case core.JoinReader != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return result, err
+			}
+			var indexJoinOp *colIndexJoin
+			indexJoinOp, post, err = newColIndexJoin(
+				NewAllocator(ctx, streamingMemAccount), flowCtx, inputs[0], spec.Input[0].ColumnTypes,
+				core.JoinReader, post,
+			)
+			if err != nil {
+				return result, err
+			}
+			result.Op, result.IsStreaming = indexJoinOp, true
+			result.MetadataSources = append(result.MetadataSources, indexJoinOp)
+			returnMutations := core.JoinReader.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
+			result.ColumnTypes = core.JoinReader.Table.ColumnTypesWithMutations(returnMutations)
 		case core.Aggregator != nil:
 			if err := checkNumIn(inputs, 1); err != nil {
 				return result, err
@@ -535,12 +723,30 @@ func NewColOperator(
 			aggTyps := make([][]types.T, len(aggSpec.Aggregations))
 			aggCols := make([][]uint32, len(aggSpec.Aggregations))
 			aggFns := make([]execinfrapb.AggregatorSpec_Func, len(aggSpec.Aggregations))
+			constArguments := make([]tree.Datums, len(aggSpec.Aggregations))
 			result.ColumnTypes = make([]types.T, len(aggSpec.Aggregations))
 			for i, agg := range aggSpec.Aggregations {
-				aggTyps[i] = make([]types.T, len(agg.ColIdx))
+				aggTyps[i] = make([]types.T, len(agg.ColIdx), len(agg.ColIdx)+len(agg.Arguments))
 				for j, colIdx := range agg.ColIdx {
 					aggTyps[i][j] = spec.Input[0].ColumnTypes[colIdx]
 				}
+				if len(agg.Arguments) > 0 {
+					constArguments[i] = make(tree.Datums, len(agg.Arguments))
+					for j, argument := range agg.Arguments {
+						h := execinfra.ExprHelper{}
+						// Pass nil types and row - there are no variables in these
+						// expressions.
+						if err := h.Init(argument, nil /* types */, flowCtx.EvalCtx); err != nil {
+							return result, errors.Wrapf(err, "%s", argument)
+						}
+						d, err := h.Eval(nil /* row */)
+						if err != nil {
+							return result, errors.Wrapf(err, "%s", argument)
+						}
+						constArguments[i][j] = d
+						aggTyps[i] = append(aggTyps[i], *d.ResolvedType())
+					}
+				}
 				aggCols[i] = agg.ColIdx
 				aggFns[i] = agg.Func
 				_, retType, err := execinfrapb.GetAggregateInfo(agg.Func, aggTyps[i]...)
@@ -549,11 +755,7 @@ func NewColOperator(
 				}
 				result.ColumnTypes[i] = *retType
 			}
-			var typs []coltypes.T
-			typs, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-			if err != nil {
-				return result, err
-			}
+			typs := typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
 			if needHash {
 				hashAggregatorMemAccount := streamingMemAccount
 				if !useStreamingMemAccountForBuffering {
@@ -561,12 +763,14 @@ func NewColOperator(
 				}
 				result.Op, err = NewHashAggregator(
 					NewAllocator(ctx, hashAggregatorMemAccount), inputs[0], typs, aggFns,
-					aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
+					aggSpec.GroupCols, aggCols, constArguments, spec.Input[0].ColumnTypes,
+					execinfrapb.IsScalarAggregate(aggSpec),
 				)
 			} else {
 				result.Op, err = NewOrderedAggregator(
 					NewAllocator(ctx, streamingMemAccount), inputs[0], typs, aggFns,
-					aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
+					aggSpec.GroupCols, aggCols, constArguments, spec.Input[0].ColumnTypes,
+					execinfrapb.IsScalarAggregate(aggSpec),
 				)
 				result.IsStreaming = true
 			}
@@ -589,11 +793,7 @@ func NewColOperator(
 			}
 
 			result.ColumnTypes = spec.Input[0].ColumnTypes
-			var typs []coltypes.T
-			typs, err = typeconv.FromColumnTypes(result.ColumnTypes)
-			if err != nil {
-				return result, err
-			}
+			typs := typeconv.FromColumnTypes(result.ColumnTypes)
 			result.Op, err = NewOrderedDistinct(inputs[0], core.Distinct.OrderedColumns, typs)
 			result.IsStreaming = true
 
@@ -625,11 +825,37 @@ func NewColOperator(
 					}
 				}
 
+				if core.HashJoiner.Type.IsSetOpJoin() {
+					// INTERSECT ALL and EXCEPT ALL are performed by a separate operator
+					// which only stores the equality columns of the right input and
+					// doesn't spill to disk.
+					setOpMemAccount := streamingMemAccount
+					if !useStreamingMemAccountForBuffering {
+						setOpMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "hash-set-op-limited")
+					}
+					setOp, err := NewHashSetOpOp(
+						NewAllocator(ctx, setOpMemAccount),
+						inputs[0],
+						inputs[1],
+						core.HashJoiner.LeftEqColumns,
+						core.HashJoiner.RightEqColumns,
+						rightTypes,
+						core.HashJoiner.Type,
+					)
+					if err != nil {
+						return onExpr, onExprPlanning, leftOutCols, rightOutCols, err
+					}
+					// The set operation emits all of the left columns.
+					result.Op = NewSimpleProjectOp(setOp, len(leftTypes), leftOutCols)
+					return onExpr, onExprPlanning, leftOutCols, rightOutCols, nil
+				}
+
+				hashJoinerMemMonitorName := fmt.Sprintf("hash-joiner-limited-%d", spec.ProcessorID)
 				hashJoinerMemAccount := streamingMemAccount
 				if !useStreamingMemAccountForBuffering {
-					hashJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "hash-joiner-limited")
+					hashJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, hashJoinerMemMonitorName)
 				}
-				result.Op, err = NewEqHashJoinerOp(
+				inMemoryHashJoiner, err := NewEqHashJoinerOp(
 					NewAllocator(ctx, hashJoinerMemAccount),
 					inputs[0],
 					inputs[1],
@@ -642,8 +868,53 @@ func NewColOperator(
 					core.HashJoiner.RightEqColumnsAreKey,
 					core.HashJoiner.LeftEqColumnsAreKey || core.HashJoiner.RightEqColumnsAreKey,
 					core.HashJoiner.Type,
+					core.HashJoiner.NullEquality,
+					core.HashJoiner.RejectOnNull,
 				)
-				return onExpr, onExprPlanning, leftOutCols, rightOutCols, err
+				if err != nil {
+					return onExpr, onExprPlanning, leftOutCols, rightOutCols, err
+				}
+				hj := inMemoryHashJoiner.(*hashJoinEqOp)
+				if hashJoinBloomFilterEnabled.GetWithOverrides(
+					&flowCtx.Cfg.Settings.SV, flowCtx.SettingOverrides(),
+				) {
+					result.BloomFilterStats = append(result.BloomFilterStats, hj.enableBloomFilter())
+				}
+				result.Op = inMemoryHashJoiner
+				// The hash joiner can fall back to the external hash joiner only if
+				// the temporary storage is available. Whether a NOT IN anti join
+				// emits any tuples depends on all the build tuples, so it can't be
+				// performed one partition at a time. The external hash joiner
+				// might join the partitions using the sort-merge join, which
+				// requires the equality columns to be comparable.
+				if useStreamingMemAccountForBuffering || flowCtx.Cfg.TempFS == nil ||
+					flowCtx.Cfg.VecFDSemaphore == nil || core.HashJoiner.RejectOnNull ||
+					checkKeyColumns(spec.Input[0].ColumnTypes, core.HashJoiner.LeftEqColumns) != nil {
+					return onExpr, onExprPlanning, leftOutCols, rightOutCols, nil
+				}
+				diskSpillerAllocator := NewAllocator(ctx, result.createBufferingUnlimitedMemAccount(
+					ctx, flowCtx, "disk-spiller-hash-joiner-unlimited",
+				))
+				result.Op = newTwoInputDiskSpiller(
+					diskSpillerAllocator,
+					inputs[0], inputs[1], hj,
+					hashJoinerMemMonitorName,
+					func(inputOne, inputTwo Operator) Operator {
+						externalHashJoiner := newExternalHashJoiner(
+							NewAllocator(ctx, result.createBufferingUnlimitedMemAccount(
+								ctx, flowCtx, "external-hash-joiner-unlimited",
+							)),
+							hj.spec,
+							inputOne, inputTwo,
+							execinfra.GetWorkMemLimit(flowCtx),
+							result.makeDiskQueueCfg(flowCtx),
+							flowCtx.Cfg.VecFDSemaphore,
+						)
+						result.ToClose = append(result.ToClose, externalHashJoiner.(IdempotentCloser))
+						return externalHashJoiner
+					},
+				)
+				return onExpr, onExprPlanning, leftOutCols, rightOutCols, nil
 			}
 
 			err = createJoiner(
@@ -656,8 +927,11 @@ func NewColOperator(
 				return result, errors.AssertionFailedf("unexpectedly %s merge join was planned", core.MergeJoiner.Type.String())
 			}
 			// Merge joiner is a streaming operator when equality columns form a key
-			// for both of the inputs.
-			result.IsStreaming = core.MergeJoiner.LeftEqColumnsAreKey && core.MergeJoiner.RightEqColumnsAreKey
+			// for at least one of the inputs (with some restrictions depending on
+			// the join type), since then it doesn't need to buffer whole groups.
+			result.IsStreaming = isMergeJoinerStreaming(
+				core.MergeJoiner.Type, core.MergeJoiner.LeftEqColumnsAreKey, core.MergeJoiner.RightEqColumnsAreKey,
+			)
 
 			createMergeJoinerWithOnExprPlanning := func(
 				result *NewColOperatorResult,
@@ -696,7 +970,7 @@ func NewColOperator(
 							// We don't need to specify indexVarMap because the filter will be
 							// run alongside the merge joiner, and it will have access to all
 							// of the columns from both sides.
-							err := r.planFilterExpr(ctx, flowCtx.NewEvalCtx(), *onExpr, nil /* indexVarMap */, streamingMemAccount)
+							err := r.planFilterExpr(ctx, flowCtx, *onExpr, nil /* indexVarMap */, streamingMemAccount)
 							return r.Op, err
 						}
 					}
@@ -710,17 +984,29 @@ func NewColOperator(
 					// Whether the merge joiner is streaming is already set above.
 					mergeJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "merge-joiner-limited")
 				}
+				leftInput, rightInput := inputs[0], inputs[1]
+				if core.MergeJoiner.RejectOnNull {
+					leftInput, rightInput, err = wrapMergeJoinInputsForRejectOnNull(
+						core.MergeJoiner.Type, leftInput, rightInput,
+						core.MergeJoiner.LeftOrdering.Columns, core.MergeJoiner.RightOrdering.Columns,
+					)
+					if err != nil {
+						return onExpr, onExprPlanning, leftOutCols, rightOutCols, err
+					}
+				}
 				result.Op, err = NewMergeJoinOp(
 					NewAllocator(ctx, mergeJoinerMemAccount),
 					core.MergeJoiner.Type,
-					inputs[0],
-					inputs[1],
+					leftInput,
+					rightInput,
 					leftOutCols,
 					rightOutCols,
 					leftTypes,
 					rightTypes,
 					core.MergeJoiner.LeftOrdering.Columns,
 					core.MergeJoiner.RightOrdering.Columns,
+					core.MergeJoiner.LeftEqColumnsAreKey,
+					core.MergeJoiner.RightEqColumnsAreKey,
 					filterConstructor,
 					filterOnlyOnLeft,
 				)
@@ -737,11 +1023,7 @@ func NewColOperator(
 				return result, err
 			}
 			input := inputs[0]
-			var inputTypes []coltypes.T
-			inputTypes, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-			if err != nil {
-				return result, err
-			}
+			inputTypes := typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
 			orderingCols := core.Sorter.OutputOrdering.Columns
 			matchLen := core.Sorter.OrderingMatchLen
 			if matchLen > 0 {
@@ -753,17 +1035,33 @@ func NewColOperator(
 				} else {
 					sortChunksMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "sort-chunks-limited")
 				}
+				var limit uint64
+				if post.Limit != 0 && post.Filter.Empty() {
+					// There is a limit specified with no post-process filter, so we
+					// know exactly how many tuples the sorter should output and can
+					// stop reading the input once the chunks containing them are
+					// sorted.
+					limit = post.Limit + post.Offset
+				}
 				result.Op, err = NewSortChunks(
 					NewAllocator(ctx, sortChunksMemAccount), input, inputTypes,
-					orderingCols, int(matchLen),
+					orderingCols, int(matchLen), limit,
 				)
 			} else if post.Limit != 0 && post.Filter.Empty() && post.Limit+post.Offset < math.MaxUint16 {
 				// There is a limit specified with no post-process filter, so we know
 				// exactly how many rows the sorter should output. Choose a top K sorter,
 				// which uses a heap to avoid storing more rows than necessary.
 				k := uint16(post.Limit + post.Offset)
+				// Although the top K sorter buffers at most K tuples, these tuples
+				// can be arbitrarily large, so we account for them separately.
+				var topKSorterMemAccount *mon.BoundAccount
+				if useStreamingMemAccountForBuffering {
+					topKSorterMemAccount = streamingMemAccount
+				} else {
+					topKSorterMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "topk-sort-limited")
+				}
 				result.Op = NewTopKSorter(
-					NewAllocator(ctx, streamingMemAccount), input, inputTypes,
+					NewAllocator(ctx, topKSorterMemAccount), input, inputTypes,
 					orderingCols, k,
 				)
 				result.IsStreaming = true
@@ -809,11 +1107,7 @@ func NewColOperator(
 			}
 			wf := core.Windower.WindowFns[0]
 			input := inputs[0]
-			var typs []coltypes.T
-			typs, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-			if err != nil {
-				return result, err
-			}
+			typs := typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
 			tempPartitionColOffset, partitionColIdx := 0, -1
 			if len(core.Windower.PartitionBy) > 0 {
 				// TODO(yuzefovich): add support for hashing partitioner (probably by
@@ -872,6 +1166,31 @@ func NewColOperator(
 
 			result.ColumnTypes = append(spec.Input[0].ColumnTypes, *types.Int)
 
+		case core.Sampler != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return result, err
+			}
+			// The sample reservoir uses a limited account: the sampler disables
+			// the histogram collection instead of erroring out when the limit is
+			// reached.
+			samplerMemAccount := streamingMemAccount
+			if !useStreamingMemAccountForBuffering {
+				samplerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "sampler-limited")
+			}
+			var sampler *samplerOp
+			sampler, err = newSamplerOp(
+				NewAllocator(ctx, streamingMemAccount), samplerMemAccount, flowCtx,
+				core.Sampler, inputs[0], spec.Input[0].ColumnTypes,
+			)
+			if err != nil {
+				return result, err
+			}
+			// The memory usage of the sampler is bounded by the sample size, so
+			// it is considered to be streaming.
+			result.Op, result.IsStreaming = sampler, true
+			result.MetadataSources = append(result.MetadataSources, sampler)
+			result.ColumnTypes = sampler.outputTypes
+
 		default:
 			return result, errors.Newf("unsupported processor core %q", core)
 		}
@@ -892,7 +1211,7 @@ func NewColOperator(
 
 	if !post.Filter.Empty() {
 		if err = result.planFilterExpr(
-			ctx, flowCtx.NewEvalCtx(), post.Filter,
+			ctx, flowCtx, post.Filter,
 			planningState.postFilterPlanning.indexVarMap, streamingMemAccount,
 		); err != nil {
 			return result, err
@@ -913,13 +1232,16 @@ func NewColOperator(
 				helper            execinfra.ExprHelper
 				renderInternalMem int
 			)
-			err := helper.Init(expr, result.ColumnTypes, flowCtx.EvalCtx)
+			err := helper.InitWithCache(
+				flowCtx.ExprCache(), expr, result.ColumnTypes, flowCtx.EvalCtx, nil, /* indexVarMap */
+			)
 			if err != nil {
 				return result, err
 			}
 			var outputIdx int
+			evalCtx := flowCtx.NewEvalCtx()
 			result.Op, outputIdx, result.ColumnTypes, renderInternalMem, err = planProjectionOperators(
-				ctx, flowCtx.NewEvalCtx(), helper.Expr, result.ColumnTypes, result.Op, streamingMemAccount,
+				ctx, evalCtx, foldConstants(evalCtx, helper.Expr), result.ColumnTypes, result.Op, streamingMemAccount,
 			)
 			if err != nil {
 				return result, errors.Wrapf(err, "unable to columnarize render expression %q", expr)
@@ -1097,7 +1419,7 @@ func (r *NewColOperatorResult) createBufferingMemAccount(
 	ctx context.Context, flowCtx *execinfra.FlowCtx, name string,
 ) *mon.BoundAccount {
 	bufferingOpMemMonitor := execinfra.NewLimitedMonitor(
-		ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, name,
+		ctx, flowCtx.EvalCtx.Mon, flowCtx, name,
 	)
 	r.BufferingOpMemMonitors = append(r.BufferingOpMemMonitors, bufferingOpMemMonitor)
 	bufferingMemAccount := bufferingOpMemMonitor.MakeBoundAccount()
@@ -1105,6 +1427,37 @@ func (r *NewColOperatorResult) createBufferingMemAccount(
 	return &bufferingMemAccount
 }
 
+// createBufferingUnlimitedMemAccount instantiates an unlimited memory monitor
+// and a memory account to be used with a buffering disk-backed Operator. The
+// receiver is updated to have references to both objects.
+func (r *NewColOperatorResult) createBufferingUnlimitedMemAccount(
+	ctx context.Context, flowCtx *execinfra.FlowCtx, name string,
+) *mon.BoundAccount {
+	bufferingOpUnlimitedMemMonitor := execinfra.NewMonitor(
+		ctx, flowCtx.EvalCtx.Mon, name,
+	)
+	r.BufferingOpMemMonitors = append(r.BufferingOpMemMonitors, bufferingOpUnlimitedMemMonitor)
+	bufferingMemAccount := bufferingOpUnlimitedMemMonitor.MakeBoundAccount()
+	r.BufferingOpMemAccounts = append(r.BufferingOpMemAccounts, &bufferingMemAccount)
+	return &bufferingMemAccount
+}
+
+// makeDiskQueueCfg returns the configuration of the disk queues used by the
+// disk-backed Operators.
+func (r *NewColOperatorResult) makeDiskQueueCfg(
+	flowCtx *execinfra.FlowCtx,
+) colcontainer.DiskQueueCfg {
+	cfg := colcontainer.DiskQueueCfg{
+		FS:   flowCtx.Cfg.TempFS,
+		Path: flowCtx.Cfg.TempStoragePath,
+	}
+	if flowCtx.Cfg.Metrics != nil {
+		cfg.SpilledBytesWritten = flowCtx.Cfg.Metrics.SpilledBytesWritten
+		cfg.SpilledBytesRead = flowCtx.Cfg.Metrics.SpilledBytesRead
+	}
+	return cfg
+}
+
 // setProjectedByJoinerColumnTypes sets column types on r according to a
 // joiner handled projection.
 // NOTE: r.ColumnTypes is updated.
@@ -1122,7 +1475,7 @@ func (r *NewColOperatorResult) setProjectedByJoinerColumnTypes(
 
 func (r *NewColOperatorResult) planFilterExpr(
 	ctx context.Context,
-	evalCtx *tree.EvalContext,
+	flowCtx *execinfra.FlowCtx,
 	filter execinfrapb.Expression,
 	indexVarMap []int,
 	acc *mon.BoundAccount,
@@ -1131,19 +1484,24 @@ func (r *NewColOperatorResult) planFilterExpr(
 		helper               execinfra.ExprHelper
 		selectionInternalMem int
 	)
-	err := helper.InitWithRemapping(filter, r.ColumnTypes, evalCtx, indexVarMap)
+	evalCtx := flowCtx.NewEvalCtx()
+	err := helper.InitWithCache(flowCtx.ExprCache(), filter, r.ColumnTypes, evalCtx, indexVarMap)
 	if err != nil {
 		return err
 	}
-	if helper.Expr == tree.DNull {
-		// The filter expression is tree.DNull meaning that it is always false, so
-		// we put a zero operator.
-		r.Op = NewZeroOp(r.Op)
+	expr := foldConstants(evalCtx, helper.Expr)
+	if isConst, passes := evalConstFilter(evalCtx, expr); isConst {
+		if !passes {
+			// The filter expression is always false or NULL, so we put a zero
+			// operator.
+			r.Op = NewZeroOp(r.Op)
+		}
+		// Otherwise, the filter is always true and is a noop.
 		return nil
 	}
 	var filterColumnTypes []types.T
 	r.Op, _, filterColumnTypes, selectionInternalMem, err = planSelectionOperators(
-		ctx, evalCtx, helper.Expr, r.ColumnTypes, r.Op, acc,
+		ctx, evalCtx, expr, r.ColumnTypes, r.Op, acc,
 	)
 	if err != nil {
 		return errors.Wrapf(err, "unable to columnarize filter expression %q", filter.Expr)
@@ -1200,30 +1558,25 @@ func planSelectionOperators(
 		rightOp, resultIdx, ct, internalMemUsedRight, err = planSelectionOperators(
 			ctx, evalCtx, t.TypedRight(), ct, leftOp, acc,
 		)
-		return rightOp, resultIdx, ct, internalMemUsedLeft + internalMemUsedRight, err
-	case *tree.OrExpr:
-		// OR expressions are handled by converting them to an equivalent CASE
-		// statement. Since CASE statements don't have a selection form, plan a
-		// projection and then convert the resulting boolean to a selection vector.
-		//
-		// Rewrite the OR expression as an equivalent CASE expression.
-		// "a OR b" becomes "CASE WHEN a THEN true WHEN b THEN true ELSE false END".
-		// This way we can take advantage of the short-circuiting logic built into
-		// the CASE operator. (b should not be evaluated if a is true.)
-		caseExpr, err := tree.NewTypedCaseExpr(
-			nil, /* expr */
-			[]*tree.When{
-				{Cond: t.Left, Val: tree.DBoolTrue},
-				{Cond: t.Right, Val: tree.DBoolTrue},
-			},
-			tree.DBoolFalse,
-			types.Bool)
 		if err != nil {
 			return nil, resultIdx, ct, internalMemUsed, err
 		}
-		op, resultIdx, ct, internalMemUsed, err = planProjectionOperators(
-			ctx, evalCtx, caseExpr, columnTypes, input, acc,
+		// If both sides were planned as simple selections, evaluate them within
+		// a single operator.
+		op = maybeFuseSelectionOperators(leftOp, rightOp)
+		return op, resultIdx, ct, internalMemUsedLeft + internalMemUsedRight, nil
+	case *tree.OrExpr:
+		// OR expressions don't have a selection form, so we plan the logical
+		// projection, which evaluates the right side only for the tuples for
+		// which the left side isn't true, and then convert the resulting boolean
+		// to a selection vector. NULL results are not selected: the projection
+		// writes FALSE for them in addition to setting the null.
+		op, resultIdx, ct, internalMemUsed, err = planLogicalProjectionOp(
+			ctx, evalCtx, expr, columnTypes, input, acc,
 		)
+		if err != nil {
+			return nil, resultIdx, ct, internalMemUsed, err
+		}
 		op = NewBoolVecToSelOp(op, resultIdx)
 		return op, resultIdx, ct, internalMemUsed, err
 	case *tree.CaseExpr:
@@ -1233,6 +1586,19 @@ func planSelectionOperators(
 		op = NewBoolVecToSelOp(op, resultIdx)
 		return op, resultIdx, ct, internalMemUsed, err
 	case *tree.ComparisonExpr:
+		if t.TypedLeft().ResolvedType().Family() == types.JsonFamily || cmpRequiresDatums(t) {
+			// There are no selection operators on JSON columns nor on the columns
+			// of coltypes.Datum type, so we plan a projection and then convert the
+			// resulting boolean to a selection vector.
+			op, resultIdx, ct, internalMemUsed, err = planProjectionOperators(
+				ctx, evalCtx, expr, columnTypes, input, acc,
+			)
+			if err != nil {
+				return nil, resultIdx, ct, internalMemUsed, err
+			}
+			op = NewBoolVecToSelOp(op, resultIdx)
+			return op, resultIdx, ct, internalMemUsed, err
+		}
 		cmpOp := t.Operator
 		leftOp, leftIdx, ct, internalMemUsedLeft, err := planProjectionOperators(
 			ctx, evalCtx, t.TypedLeft(), columnTypes, input, acc,
@@ -1242,10 +1608,17 @@ func planSelectionOperators(
 		}
 		lTyp := &ct[leftIdx]
 		if constArg, ok := t.Right.(tree.Datum); ok {
-			if t.Operator == tree.Like || t.Operator == tree.NotLike {
-				negate := t.Operator == tree.NotLike
-				op, err = GetLikeOperator(
-					evalCtx, leftOp, leftIdx, string(tree.MustBeDString(constArg)), negate)
+			if info, ok := getPatternMatchOpInfo(t.Operator); ok {
+				pattern := string(tree.MustBeDString(constArg))
+				if info.isLike {
+					op, err = GetLikeOperator(
+						evalCtx, leftOp, leftIdx, pattern, info.negate, info.caseInsensitive,
+					)
+				} else {
+					op, err = GetRegexpMatchOperator(
+						evalCtx, leftOp, leftIdx, pattern, info.negate, info.caseInsensitive,
+					)
+				}
 				return op, resultIdx, ct, internalMemUsedLeft, err
 			}
 			if t.Operator == tree.In || t.Operator == tree.NotIn {
@@ -1311,6 +1684,7 @@ func planTypedMaybeNullProjectionOperators(
 // 'toType' that will be output at index 'resultIdx'.
 func planCastOperator(
 	ctx context.Context,
+	evalCtx *tree.EvalContext,
 	acc *mon.BoundAccount,
 	columnTypes []types.T,
 	input Operator,
@@ -1319,7 +1693,7 @@ func planCastOperator(
 	toType *types.T,
 ) (op Operator, resultIdx int, ct []types.T, err error) {
 	outputIdx := len(columnTypes)
-	op, err = GetCastOperator(NewAllocator(ctx, acc), input, inputIdx, outputIdx, fromType, toType)
+	op, err = GetCastOperator(NewAllocator(ctx, acc), evalCtx, input, inputIdx, outputIdx, fromType, toType)
 	ct = append(columnTypes, *toType)
 	return op, outputIdx, ct, err
 }
@@ -1341,6 +1715,9 @@ func planProjectionOperators(
 	case *tree.IndexedVar:
 		return input, t.Idx, columnTypes, internalMemUsed, nil
 	case *tree.ComparisonExpr:
+		if cmpRequiresDatums(t) {
+			return planDefaultCmpProjectionOp(ctx, evalCtx, t, columnTypes, input, acc)
+		}
 		return planProjectionExpr(ctx, evalCtx, t.Operator, t.ResolvedType(), t.TypedLeft(), t.TypedRight(), columnTypes, input, acc)
 	case *tree.BinaryExpr:
 		return planProjectionExpr(ctx, evalCtx, t.Operator, t.ResolvedType(), t.TypedLeft(), t.TypedRight(), columnTypes, input, acc)
@@ -1358,7 +1735,7 @@ func planProjectionOperators(
 		if err != nil {
 			return nil, 0, nil, internalMemUsed, err
 		}
-		op, resultIdx, ct, err = planCastOperator(ctx, acc, ct, op, resultIdx, expr.ResolvedType(), t.Type)
+		op, resultIdx, ct, err = planCastOperator(ctx, evalCtx, acc, ct, op, resultIdx, expr.ResolvedType(), t.Type)
 		return op, resultIdx, ct, internalMemUsed, err
 	case *tree.FuncExpr:
 		var (
@@ -1454,7 +1831,7 @@ func planProjectionOperators(
 				// such case, we need to plan a cast.
 				fromType, toType := &ct[thenIdxs[i]], &ct[caseOutputIdx]
 				caseOps[i], thenIdxs[i], ct, err = planCastOperator(
-					ctx, acc, ct, caseOps[i], thenIdxs[i], fromType, toType,
+					ctx, evalCtx, acc, ct, caseOps[i], thenIdxs[i], fromType, toType,
 				)
 				if err != nil {
 					return nil, resultIdx, ct, internalMemUsed, err
@@ -1482,7 +1859,7 @@ func planProjectionOperators(
 			elseIdx := thenIdxs[len(t.Whens)]
 			fromType, toType := &ct[elseIdx], &ct[caseOutputIdx]
 			elseOp, thenIdxs[len(t.Whens)], ct, err = planCastOperator(
-				ctx, acc, ct, elseOp, elseIdx, fromType, toType,
+				ctx, evalCtx, acc, ct, elseOp, elseIdx, fromType, toType,
 			)
 			if err != nil {
 				return nil, resultIdx, ct, internalMemUsed, err
@@ -1494,6 +1871,24 @@ func planProjectionOperators(
 		return op, caseOutputIdx, ct, internalMemUsed, err
 	case *tree.AndExpr, *tree.OrExpr:
 		return planLogicalProjectionOp(ctx, evalCtx, expr, columnTypes, input, acc)
+	case *tree.Tuple:
+		tupleContentsIdxs := make([]int, len(t.Exprs))
+		ct = columnTypes
+		op = input
+		for i, e := range t.Exprs {
+			var elemInternalMemUsed int
+			op, tupleContentsIdxs[i], ct, elemInternalMemUsed, err = planTypedMaybeNullProjectionOperators(
+				ctx, evalCtx, e.(tree.TypedExpr), &t.ResolvedType().TupleContents()[i], ct, op, acc,
+			)
+			if err != nil {
+				return nil, resultIdx, nil, internalMemUsed, err
+			}
+			internalMemUsed += elemInternalMemUsed
+		}
+		resultIdx = len(ct)
+		ct = append(ct, *t.ResolvedType())
+		op = NewTupleProjOp(NewAllocator(ctx, acc), t.ResolvedType(), ct, tupleContentsIdxs, resultIdx, op)
+		return op, resultIdx, ct, internalMemUsed, nil
 	default:
 		return nil, resultIdx, nil, internalMemUsed, errors.Errorf("unhandled projection expression type: %s", reflect.TypeOf(t))
 	}
@@ -1510,6 +1905,12 @@ func planProjectionExpr(
 	acc *mon.BoundAccount,
 ) (op Operator, resultIdx int, ct []types.T, internalMemUsed int, err error) {
 	resultIdx = -1
+	if err := checkDatetimeBinOp(evalCtx, binOp, left.ResolvedType(), right.ResolvedType()); err != nil {
+		return nil, resultIdx, nil, internalMemUsed, err
+	}
+	if err := checkJSONBinOp(binOp, left, right); err != nil {
+		return nil, resultIdx, nil, internalMemUsed, err
+	}
 	// There are 3 cases. Either the left is constant, the right is constant,
 	// or neither are constant.
 	lConstArg, lConst := left.(tree.Datum)
@@ -1551,11 +1952,22 @@ func planProjectionExpr(
 		// The projection result will be outputted to a new column which is appended
 		// to the input batch.
 		resultIdx = len(ct)
-		if binOp == tree.Like || binOp == tree.NotLike {
-			negate := binOp == tree.NotLike
-			op, err = GetLikeProjectionOperator(
-				NewAllocator(ctx, acc), evalCtx, leftOp, leftIdx, resultIdx,
-				string(tree.MustBeDString(rConstArg)), negate,
+		if info, ok := getPatternMatchOpInfo(binOp); ok {
+			pattern := string(tree.MustBeDString(rConstArg))
+			if info.isLike {
+				op, err = GetLikeProjectionOperator(
+					NewAllocator(ctx, acc), evalCtx, leftOp, leftIdx, resultIdx,
+					pattern, info.negate, info.caseInsensitive,
+				)
+			} else {
+				op, err = GetRegexpMatchProjectionOperator(
+					NewAllocator(ctx, acc), evalCtx, leftOp, leftIdx, resultIdx,
+					pattern, info.negate, info.caseInsensitive,
+				)
+			}
+		} else if ct[leftIdx].Family() == types.JsonFamily && isJSONOperator(binOp, right.ResolvedType()) {
+			op, err = GetJSONProjectionConstOperator(
+				NewAllocator(ctx, acc), binOp, leftOp, leftIdx, resultIdx, rConstArg,
 			)
 		} else if binOp == tree.In || binOp == tree.NotIn {
 			negate := binOp == tree.NotIn
@@ -1609,6 +2021,83 @@ func planProjectionExpr(
 	return op, resultIdx, ct, internalMemUsed, err
 }
 
+// cmpRequiresDatums returns whether the comparison involves an argument of a
+// type that doesn't have a native columnar representation, in which case it
+// can only be evaluated on datums. A constant right argument (for example, the
+// tuple of an IN expression) doesn't count since the templated operators
+// handle it on their own.
+func cmpRequiresDatums(expr *tree.ComparisonExpr) bool {
+	if typeconv.FromColumnType(expr.TypedLeft().ResolvedType()) == coltypes.Datum {
+		return true
+	}
+	if _, rConst := expr.Right.(tree.Datum); rConst {
+		return false
+	}
+	return typeconv.FromColumnType(expr.TypedRight().ResolvedType()) == coltypes.Datum
+}
+
+// planDefaultCmpProjectionOp plans the operators that evaluate the comparison
+// on datums, see NewDefaultCmpProjOp.
+func planDefaultCmpProjectionOp(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	expr *tree.ComparisonExpr,
+	columnTypes []types.T,
+	input Operator,
+	acc *mon.BoundAccount,
+) (op Operator, resultIdx int, ct []types.T, internalMemUsed int, err error) {
+	resultIdx = -1
+	leftOp, leftIdx, ct, internalMemUsedLeft, err := planProjectionOperators(
+		ctx, evalCtx, expr.TypedLeft(), columnTypes, input, acc,
+	)
+	if err != nil {
+		return nil, resultIdx, ct, internalMemUsed, err
+	}
+	internalMemUsed += internalMemUsedLeft
+	op, rightIdx := leftOp, -1
+	if _, rConst := expr.Right.(tree.Datum); !rConst {
+		var internalMemUsedRight int
+		op, rightIdx, ct, internalMemUsedRight, err = planProjectionOperators(
+			ctx, evalCtx, expr.TypedRight(), ct, leftOp, acc,
+		)
+		if err != nil {
+			return nil, resultIdx, ct, internalMemUsed, err
+		}
+		internalMemUsed += internalMemUsedRight
+	}
+	resultIdx = len(ct)
+	ct = append(ct, *expr.ResolvedType())
+	op = NewDefaultCmpProjOp(NewAllocator(ctx, acc), evalCtx, expr, ct, leftIdx, rightIdx, resultIdx, op)
+	return op, resultIdx, ct, internalMemUsed, nil
+}
+
+// checkDatetimeBinOp returns an error if binOp is datetime arithmetic that
+// the vectorized projection operators can't perform the same way as the
+// row-by-row engine. Those operators add intervals to timestamps in
+// duration.AdditionModeCompatible, and they store TIMESTAMP and TIMESTAMPTZ
+// columns alike, so subtracting one from the other (which requires the session
+// time zone) isn't supported.
+func checkDatetimeBinOp(evalCtx *tree.EvalContext, binOp tree.Operator, leftType, rightType *types.T) error {
+	if binOp != tree.Plus && binOp != tree.Minus {
+		return nil
+	}
+	isTimestamp := func(t *types.T) bool {
+		return t.Family() == types.TimestampFamily || t.Family() == types.TimestampTZFamily
+	}
+	if isTimestamp(leftType) && isTimestamp(rightType) {
+		if leftType.Family() != rightType.Family() {
+			return errors.Newf("%s %s %s is not supported", leftType, binOp, rightType)
+		}
+		return nil
+	}
+	if isTimestamp(leftType) || isTimestamp(rightType) {
+		if mode := evalCtx.GetAdditionMode(); mode != duration.AdditionModeCompatible {
+			return errors.Newf("%s %s %s is not supported with %s addition mode", leftType, binOp, rightType, mode)
+		}
+	}
+	return nil
+}
+
 // planLogicalProjectionOp plans all the needed operators for a projection of
 // a logical operation (either AND or OR).
 func planLogicalProjectionOp(
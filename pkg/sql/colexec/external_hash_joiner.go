@@ -0,0 +1,544 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
+	"github.com/marusama/semaphore"
+)
+
+// externalHashJoinerState indicates the current state of the external hash
+// joiner.
+type externalHashJoinerState int
+
+const (
+	// externalHJInitialPartitioning indicates that the operator is reading the
+	// tuples from both inputs and is distributing them among the partitions
+	// according to the hash values of their equality columns. Once both inputs
+	// are exhausted, the operator transitions to externalHJJoinNewPartition.
+	externalHJInitialPartitioning externalHashJoinerState = iota
+	// externalHJJoinNewPartition indicates that the operator should pick the
+	// next partition to join. If the build side of that partition doesn't fit
+	// in memory, the partition is partitioned further, otherwise the operator
+	// transitions to externalHJJoining.
+	externalHJJoinNewPartition
+	// externalHJJoining indicates that the operator is emitting the output of
+	// the join of the current partition. Once the output is exhausted, the
+	// operator transitions to externalHJJoinNewPartition.
+	externalHJJoining
+	// externalHJFinished indicates that all partitions have been joined.
+	externalHJFinished
+)
+
+const (
+	// externalHJMinPartitions is the minimum number of partitions that the
+	// tuples are distributed among in a single round of partitioning.
+	externalHJMinPartitions = 2
+	// externalHJRecursivePartitioningSizeDecreaseThreshold determines whether
+	// partitioning a partition further was successful: if the build side of one
+	// of the new partitions is larger than this fraction of the build side of
+	// the partition that was partitioned, the tuples likely share the same key
+	// and partitioning them again won't help, so the new partition is joined
+	// using the sort-merge join instead.
+	externalHJRecursivePartitioningSizeDecreaseThreshold = 0.95
+	// externalHJHashSeed is the initial hash value of the tuples at recursion
+	// level zero. It differs from the one used by the HashRouter so that the
+	// partitions are not correlated with the distribution of the tuples among
+	// the nodes.
+	externalHJHashSeed = 2
+)
+
+var (
+	// externalHJMaxPartitions is the maximum number of partitions that the
+	// tuples are distributed among in a single round of partitioning.
+	externalHJMaxPartitions = 16
+	// externalHJMaxRecursionLevel is the maximum number of times that a
+	// partition is partitioned further. The partitions at this level are joined
+	// using the sort-merge join.
+	externalHJMaxRecursionLevel = 8
+)
+
+func init() {
+	metamorphic.Register("colexec.externalHJMaxPartitions", func(rng *rand.Rand) interface{} {
+		externalHJMaxPartitions = metamorphic.IntInRange(rng, externalHJMinPartitions, externalHJMaxPartitions)
+		return externalHJMaxPartitions
+	})
+	metamorphic.Register("colexec.externalHJMaxRecursionLevel", func(rng *rand.Rand) interface{} {
+		externalHJMaxRecursionLevel = metamorphic.IntInRange(rng, 1, externalHJMaxRecursionLevel)
+		return externalHJMaxRecursionLevel
+	})
+}
+
+// externalHJPartitionInfo describes a partition that hasn't been joined yet.
+type externalHJPartitionInfo struct {
+	// level is the number of times that the tuples of the partition have been
+	// partitioned further since the initial partitioning.
+	level int
+	// buildSizeBytes is the estimated in-memory size of the tuples of the build
+	// side of the partition.
+	buildSizeBytes int64
+	// sortMerge indicates that the partition has to be joined using the
+	// sort-merge join because partitioning it further doesn't help.
+	sortMerge bool
+}
+
+// externalHashJoiner is an operator that performs the hash join of two inputs
+// when the build side doesn't fit in memory. It is used as the disk-backed
+// fallback of the in-memory hash joiner.
+//
+// The operator distributes the tuples of both inputs among a number of
+// partitions on disk according to the hash values of their equality columns,
+// so that the matching tuples end up in partitions with the same index. Then
+// it joins the partitions one at a time:
+//   - if the build side of the partition fits in memory, the partition is joined
+//     using the in-memory hash joiner.
+//   - otherwise, the tuples of the partition are distributed among new
+//     partitions using a different hash function. This recursive partitioning
+//     doesn't help if the build side is dominated by a single key, so once it
+//     stops decreasing the size of the partitions or the maximum recursion level
+//     is reached, the partition is joined using the sort-merge join.
+//
+// The tuples of the partitions are read from and written to disk queues that
+// acquire their file descriptors from fdSemaphore.
+type externalHashJoiner struct {
+	twoInputNode
+
+	state externalHashJoinerState
+	// unlimitedAllocator is used for the in-memory operators that join the
+	// partitions, so it must not have a memory limit. The build sides of the
+	// partitions are kept under memoryLimit instead.
+	unlimitedAllocator *Allocator
+	spec               hashJoinerSpec
+	memoryLimit        int64
+	diskQueueCfg       colcontainer.DiskQueueCfg
+	fdSemaphore        semaphore.Semaphore
+	// sources are the left and the right sources of the join.
+	sources [2]hashJoinerSourceSpec
+	// buildSideIdx is the index of the build side in sources.
+	buildSideIdx int
+	// canSortMerge is false when the sort-merge join can't be used for the
+	// partitions, in which case they are always joined using the in-memory hash
+	// joiner.
+	canSortMerge bool
+
+	// numPartitions is the number of partitions that the tuples are
+	// distributed among in a single round of partitioning.
+	numPartitions int
+	// partitioners store the partitions of the left and the right inputs.
+	partitioners [2]*colcontainer.PartitionedDiskQueue
+	// partitionInfo contains the information about the partitions that have
+	// tuples and haven't been joined yet.
+	partitionInfo map[int]*externalHJPartitionInfo
+	// numTotalPartitions is the number of partitions that have been created,
+	// including the ones that have already been joined. The partitions created
+	// by recursive partitioning are assigned indices after all existing
+	// partitions, so they are joined after them.
+	numTotalPartitions int
+	// partitionIdxToJoin is the index of the partition that is being joined.
+	partitionIdxToJoin int
+	// numRepartitions is the number of partitions that have been partitioned
+	// further.
+	numRepartitions int
+
+	// partitionInputs read the tuples of the partition that is being joined or
+	// partitioned further.
+	partitionInputs [2]*partitionerToOperator
+	joiner          Operator
+	// memUsedBeforeJoin is the memory used by unlimitedAllocator before the
+	// operators that join the current partition were created. The rest is
+	// released once the partition has been joined.
+	memUsedBeforeJoin int64
+
+	// ht is not fully initialized to a hashTable, only the utility methods are
+	// used.
+	ht hashTable
+
+	scratch struct {
+		// batches hold the tuples of a single partition of the batch that is
+		// being partitioned, for every side.
+		batches [2]coldata.Batch
+		// hashes are the hash values of the tuples of the batch that is being
+		// partitioned.
+		hashes []uint64
+		// selections are the indices of the tuples of the batch that is being
+		// partitioned that belong to every partition.
+		selections [][]uint16
+	}
+
+	closed bool
+}
+
+var _ Operator = &externalHashJoiner{}
+var _ IdempotentCloser = &externalHashJoiner{}
+
+// newExternalHashJoiner returns a disk-backed hash join operator.
+//   - unlimitedAllocator must have an unlimited memory budget.
+//   - spec is the specification of the in-memory hash joiner that the external
+//     hash joiner is the fallback of. Its sources are ignored.
+//   - memoryLimit is the limit on the estimated in-memory size of the build side
+//     of a partition that is joined using the in-memory hash joiner.
+func newExternalHashJoiner(
+	unlimitedAllocator *Allocator,
+	spec hashJoinerSpec,
+	leftInput, rightInput Operator,
+	memoryLimit int64,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+) Operator {
+	// Partitioning further a partition needs a file descriptor for every new
+	// partition as well as one for the partition being read, for both inputs.
+	numPartitions := fdSemaphore.GetLimit()/2 - 1
+	if numPartitions > externalHJMaxPartitions {
+		numPartitions = externalHJMaxPartitions
+	}
+	if numPartitions < externalHJMinPartitions {
+		numPartitions = externalHJMinPartitions
+	}
+	hj := &externalHashJoiner{
+		twoInputNode:       newTwoInputNode(leftInput, rightInput),
+		unlimitedAllocator: unlimitedAllocator,
+		spec:               spec,
+		memoryLimit:        memoryLimit,
+		diskQueueCfg:       diskQueueCfg,
+		fdSemaphore:        fdSemaphore,
+		sources:            [2]hashJoinerSourceSpec{spec.left, spec.right},
+		// The merge joiner never considers NULLs equal.
+		canSortMerge:  !spec.nullEquality,
+		numPartitions: numPartitions,
+		partitionInfo: make(map[int]*externalHJPartitionInfo),
+	}
+	hj.sources[0].source = leftInput
+	hj.sources[1].source = rightInput
+	if spec.buildRightSide {
+		hj.buildSideIdx = 1
+	}
+	return hj
+}
+
+func (hj *externalHashJoiner) Init() {
+	hj.inputOne.Init()
+	hj.inputTwo.Init()
+	for i := range hj.sources {
+		var err error
+		hj.partitioners[i], err = colcontainer.NewPartitionedDiskQueue(
+			hj.sources[i].sourceTypes, hj.diskQueueCfg, hj.fdSemaphore,
+			colcontainer.PartitionerStrategyDefault,
+		)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		hj.partitionInputs[i] = &partitionerToOperator{
+			partitioner: hj.partitioners[i],
+			batch:       hj.unlimitedAllocator.NewMemBatch(hj.sources[i].sourceTypes),
+		}
+		hj.scratch.batches[i] = hj.unlimitedAllocator.NewMemBatch(hj.sources[i].sourceTypes)
+	}
+	hj.scratch.hashes = make([]uint64, coldata.BatchSize())
+	hj.scratch.selections = make([][]uint16, hj.numPartitions)
+	for i := range hj.scratch.selections {
+		hj.scratch.selections[i] = make([]uint16, 0, coldata.BatchSize())
+	}
+	hj.state = externalHJInitialPartitioning
+}
+
+func (hj *externalHashJoiner) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch hj.state {
+		case externalHJInitialPartitioning:
+			hj.partitionInput(ctx, 0 /* sideIdx */, hj.inputOne, 0 /* level */, 0 /* partitionIdxOffset */)
+			hj.partitionInput(ctx, 1 /* sideIdx */, hj.inputTwo, 0 /* level */, 0 /* partitionIdxOffset */)
+			hj.numTotalPartitions = hj.numPartitions
+			hj.closeWritePartitions(ctx)
+			hj.state = externalHJJoinNewPartition
+			continue
+		case externalHJJoinNewPartition:
+			for hj.partitionIdxToJoin < hj.numTotalPartitions && hj.partitionInfo[hj.partitionIdxToJoin] == nil {
+				hj.partitionIdxToJoin++
+			}
+			if hj.partitionIdxToJoin == hj.numTotalPartitions {
+				hj.state = externalHJFinished
+				continue
+			}
+			info := hj.partitionInfo[hj.partitionIdxToJoin]
+			if !info.sortMerge && info.buildSizeBytes > hj.memoryLimit &&
+				info.level < externalHJMaxRecursionLevel {
+				hj.repartition(ctx, hj.partitionIdxToJoin, info)
+				hj.finishPartition()
+				continue
+			}
+			hj.setupJoiner(hj.partitionIdxToJoin, info)
+			hj.state = externalHJJoining
+			continue
+		case externalHJJoining:
+			b := hj.joiner.Next(ctx)
+			if b.Length() == 0 {
+				hj.joiner = nil
+				if used := hj.unlimitedAllocator.Used(); used > hj.memUsedBeforeJoin {
+					hj.unlimitedAllocator.ReleaseMemory(used - hj.memUsedBeforeJoin)
+				}
+				hj.finishPartition()
+				hj.state = externalHJJoinNewPartition
+				continue
+			}
+			return b
+		case externalHJFinished:
+			if err := hj.IdempotentClose(ctx); err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			return coldata.ZeroBatch
+		default:
+			execerror.VectorizedInternalPanic("external hash joiner in unhandled state")
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// partitionInput distributes all tuples of input, which is the sideIdx'th side
+// of the join, among numPartitions partitions starting at partitionIdxOffset
+// according to the hash values of their equality columns at the given
+// recursion level.
+func (hj *externalHashJoiner) partitionInput(
+	ctx context.Context, sideIdx int, input Operator, level int, partitionIdxOffset int,
+) {
+	source := &hj.sources[sideIdx]
+	scratch := hj.scratch.batches[sideIdx]
+	for {
+		batch := input.Next(ctx)
+		n := batch.Length()
+		if n == 0 {
+			return
+		}
+		// Every recursion level uses a different initial hash value, so that the
+		// tuples of a partition are distributed among the new partitions.
+		hashes := hj.scratch.hashes[:n]
+		for i := range hashes {
+			hashes[i] = uint64(externalHJHashSeed + level)
+		}
+		for i, colIdx := range source.eqCols {
+			hj.ht.rehash(
+				ctx, hashes, i, source.sourceTypes[colIdx], batch.ColVec(int(colIdx)),
+				uint64(n), batch.Selection(),
+			)
+		}
+		for i := range hj.scratch.selections {
+			hj.scratch.selections[i] = hj.scratch.selections[i][:0]
+		}
+		if sel := batch.Selection(); sel != nil {
+			for i, selIdx := range sel[:n] {
+				partitionIdx := hashes[i] % uint64(hj.numPartitions)
+				hj.scratch.selections[partitionIdx] = append(hj.scratch.selections[partitionIdx], selIdx)
+			}
+		} else {
+			for i, hash := range hashes {
+				partitionIdx := hash % uint64(hj.numPartitions)
+				hj.scratch.selections[partitionIdx] = append(hj.scratch.selections[partitionIdx], uint16(i))
+			}
+		}
+
+		for i, sel := range hj.scratch.selections {
+			if len(sel) == 0 {
+				continue
+			}
+			// The disk queues ignore the selection vectors, so the tuples of the
+			// partition are copied into the scratch batch first.
+			scratch.ResetInternalBatch()
+			hj.unlimitedAllocator.PerformOperation(scratch.ColVecs(), func() {
+				for colIdx, t := range source.sourceTypes {
+					scratch.ColVec(colIdx).Copy(
+						coldata.CopySliceArgs{
+							SliceArgs: coldata.SliceArgs{
+								ColType:   t,
+								Src:       batch.ColVec(colIdx),
+								Sel:       sel,
+								SrcEndIdx: uint64(len(sel)),
+							},
+						},
+					)
+				}
+			})
+			scratch.SetLength(uint16(len(sel)))
+			partitionIdx := partitionIdxOffset + i
+			if err := hj.partitioners[sideIdx].Enqueue(ctx, partitionIdx, scratch); err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			info, ok := hj.partitionInfo[partitionIdx]
+			if !ok {
+				info = &externalHJPartitionInfo{level: level}
+				hj.partitionInfo[partitionIdx] = info
+			}
+			if sideIdx == hj.buildSideIdx {
+				info.buildSizeBytes += tuplesSizeBytes(scratch, source.sourceTypes)
+			}
+		}
+	}
+}
+
+// repartition distributes the tuples of both sides of the partition at
+// partitionIdx among numPartitions new partitions.
+func (hj *externalHashJoiner) repartition(
+	ctx context.Context, partitionIdx int, info *externalHJPartitionInfo,
+) {
+	partitionIdxOffset := hj.numTotalPartitions
+	level := info.level + 1
+	for sideIdx := range hj.sources {
+		hj.partitionInputs[sideIdx].partitionIdx = partitionIdx
+		hj.partitionInput(ctx, sideIdx, hj.partitionInputs[sideIdx], level, partitionIdxOffset)
+	}
+	hj.numTotalPartitions += hj.numPartitions
+	hj.numRepartitions++
+	hj.closeWritePartitions(ctx)
+
+	for i := partitionIdxOffset; i < hj.numTotalPartitions; i++ {
+		newInfo, ok := hj.partitionInfo[i]
+		if !ok || newInfo.buildSizeBytes <= hj.memoryLimit || !hj.canSortMerge {
+			continue
+		}
+		if level >= externalHJMaxRecursionLevel ||
+			float64(newInfo.buildSizeBytes) > float64(info.buildSizeBytes)*externalHJRecursivePartitioningSizeDecreaseThreshold {
+			newInfo.sortMerge = true
+		}
+	}
+}
+
+// setupJoiner creates the operator that joins the partition at partitionIdx.
+func (hj *externalHashJoiner) setupJoiner(partitionIdx int, info *externalHJPartitionInfo) {
+	hj.memUsedBeforeJoin = hj.unlimitedAllocator.Used()
+	leftSource, rightSource := hj.sources[0], hj.sources[1]
+	leftInput, rightInput := hj.partitionInputs[0], hj.partitionInputs[1]
+	leftInput.partitionIdx, rightInput.partitionIdx = partitionIdx, partitionIdx
+	var err error
+	if info.sortMerge {
+		leftOrdering := makeEqColsOrdering(leftSource.eqCols)
+		rightOrdering := makeEqColsOrdering(rightSource.eqCols)
+		hj.joiner, err = NewMergeJoinOp(
+			hj.unlimitedAllocator, hj.spec.joinType,
+			newExternalSorter(hj.unlimitedAllocator, leftInput, leftSource.sourceTypes, leftOrdering),
+			newExternalSorter(hj.unlimitedAllocator, rightInput, rightSource.sourceTypes, rightOrdering),
+			leftSource.outCols, rightSource.outCols,
+			leftSource.sourceTypes, rightSource.sourceTypes,
+			leftOrdering, rightOrdering,
+			hj.spec.buildDistinct && !hj.spec.buildRightSide, /* leftEqColsAreKey */
+			hj.spec.buildDistinct && hj.spec.buildRightSide,  /* rightEqColsAreKey */
+			nil,   /* filterConstructor */
+			false, /* filterOnlyOnLeft */
+		)
+	} else {
+		hj.joiner, err = NewEqHashJoinerOp(
+			hj.unlimitedAllocator,
+			leftInput, rightInput,
+			leftSource.eqCols, rightSource.eqCols,
+			leftSource.outCols, rightSource.outCols,
+			leftSource.sourceTypes, rightSource.sourceTypes,
+			hj.spec.buildRightSide, hj.spec.buildDistinct,
+			hj.spec.joinType, hj.spec.nullEquality,
+			false, /* rejectOnNull */
+		)
+	}
+	if err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	hj.joiner.Init()
+}
+
+// finishPartition moves on to the partition after the one that has just been
+// joined or partitioned further.
+func (hj *externalHashJoiner) finishPartition() {
+	delete(hj.partitionInfo, hj.partitionIdxToJoin)
+	hj.partitionIdxToJoin++
+}
+
+// closeWritePartitions closes all partitions that are open for writes so that
+// their file descriptors are released before the partitions are read.
+func (hj *externalHashJoiner) closeWritePartitions(ctx context.Context) {
+	for _, p := range hj.partitioners {
+		if err := p.CloseAllOpenWriteFileDescriptors(ctx); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	}
+}
+
+// IdempotentClose is part of the IdempotentCloser interface. It removes all
+// the partitions and releases their file descriptors.
+func (hj *externalHashJoiner) IdempotentClose(ctx context.Context) error {
+	if hj.closed {
+		return nil
+	}
+	hj.closed = true
+	var retErr error
+	for _, p := range hj.partitioners {
+		if p == nil {
+			continue
+		}
+		if err := p.Close(ctx); err != nil && retErr == nil {
+			retErr = err
+		}
+	}
+	return retErr
+}
+
+// makeEqColsOrdering returns the ascending ordering on the equality columns.
+func makeEqColsOrdering(eqCols []uint32) []execinfrapb.Ordering_Column {
+	ordering := make([]execinfrapb.Ordering_Column, len(eqCols))
+	for i, colIdx := range eqCols {
+		ordering[i] = execinfrapb.Ordering_Column{ColIdx: colIdx, Direction: execinfrapb.Ordering_Column_ASC}
+	}
+	return ordering
+}
+
+// tuplesSizeBytes returns the estimated in-memory size of the tuples of the
+// batch, which must not have a selection vector. Unlike estimateBatchSizeBytes,
+// it accounts for the actual lengths of the byte arrays.
+func tuplesSizeBytes(batch coldata.Batch, typs []coltypes.T) int64 {
+	n := int(batch.Length())
+	size := 0
+	for colIdx, t := range typs {
+		if t != coltypes.Bytes {
+			size += estimateBatchSizeBytes([]coltypes.T{t}, n)
+			continue
+		}
+		bytes := batch.ColVec(colIdx).Bytes()
+		for i := 0; i < n; i++ {
+			size += len(bytes.Get(i)) + sizeOfInt32
+		}
+	}
+	return int64(size)
+}
+
+// partitionerToOperator is an Operator that returns the tuples of the
+// partition at partitionIdx of partitioner.
+type partitionerToOperator struct {
+	ZeroInputNode
+	NonExplainable
+
+	partitioner  colcontainer.PartitionedQueue
+	partitionIdx int
+	batch        coldata.Batch
+}
+
+var _ Operator = &partitionerToOperator{}
+
+func (p *partitionerToOperator) Init() {}
+
+func (p *partitionerToOperator) Next(ctx context.Context) coldata.Batch {
+	if err := p.partitioner.Dequeue(ctx, p.partitionIdx, p.batch); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	return p.batch
+}
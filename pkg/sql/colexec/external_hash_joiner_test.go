@@ -0,0 +1,122 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/marusama/semaphore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalHashJoiner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	// A memory limit of 1 byte forces every partition to be partitioned further
+	// until the maximum recursion level or the sort-merge fallback is reached,
+	// whereas the large limit joins the partitions of the initial partitioning
+	// in memory.
+	for _, memoryLimit := range []int64{1, 64 << 20} {
+		for _, tc := range tcs {
+			if tc.rejectOnNull {
+				// The external hash joiner doesn't support rejecting on NULLs.
+				continue
+			}
+			t.Run(fmt.Sprintf("memoryLimit=%d", memoryLimit), func(t *testing.T) {
+				var closers []IdempotentCloser
+				sem := semaphore.New(colcontainer.DefaultMaxOpenFDs)
+				inputs := []tuples{tc.leftTuples, tc.rightTuples}
+				typs := [][]coltypes.T{tc.leftTypes, tc.rightTypes}
+				// Injecting all NULLs isn't meaningful for the test cases that skip
+				// it for the in-memory hash joiner either.
+				runner := runTestsWithTyps
+				if tc.skipAllNullsInjection {
+					runner = runTestsWithoutAllNullsInjection
+				}
+				runner(t, inputs, typs, tc.expectedTuples, unorderedVerifier, func(sources []Operator) (Operator, error) {
+					inMemory, err := NewEqHashJoinerOp(
+						testAllocator, sources[0], sources[1],
+						tc.leftEqCols, tc.rightEqCols,
+						tc.leftOutCols, tc.rightOutCols,
+						tc.leftTypes, tc.rightTypes,
+						tc.rightEqColsAreKey, tc.leftEqColsAreKey || tc.rightEqColsAreKey,
+						tc.joinType, tc.nullEquality, tc.rejectOnNull,
+					)
+					if err != nil {
+						return nil, err
+					}
+					hj := newExternalHashJoiner(
+						testAllocator, inMemory.(*hashJoinEqOp).spec, sources[0], sources[1],
+						memoryLimit, colcontainer.DiskQueueCfg{FS: vfs.NewMem(), Path: "queues"}, sem,
+					)
+					closers = append(closers, hj.(IdempotentCloser))
+					return hj, nil
+				})
+				for _, c := range closers {
+					require.NoError(t, c.IdempotentClose(ctx))
+				}
+				require.Equal(t, 0, sem.GetCount(), "file descriptors weren't released")
+			})
+		}
+	}
+}
+
+// TestExternalHashJoinerFallsBackToSortMerge verifies that a partition whose
+// tuples all have the same join key, and which therefore can't be made smaller
+// by partitioning it further, is joined using the sort-merge join.
+func TestExternalHashJoinerFallsBackToSortMerge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	typs := []coltypes.T{coltypes.Int64}
+	nTuples := int(coldata.BatchSize())
+	leftTuples := make(tuples, nTuples)
+	rightTuples := make(tuples, nTuples)
+	for i := range leftTuples {
+		leftTuples[i] = tuple{1}
+		rightTuples[i] = tuple{1}
+	}
+	leftSource := newOpTestInput(coldata.BatchSize(), leftTuples, typs)
+	rightSource := newOpTestInput(coldata.BatchSize(), rightTuples, typs)
+	inMemory, err := NewEqHashJoinerOp(
+		testAllocator, leftSource, rightSource,
+		[]uint32{0}, []uint32{0}, []uint32{0}, []uint32{},
+		typs, typs, false /* buildRightSide */, false, /* buildDistinct */
+		sqlbase.JoinType_INNER, false /* nullEquality */, false, /* rejectOnNull */
+	)
+	require.NoError(t, err)
+	sem := semaphore.New(colcontainer.DefaultMaxOpenFDs)
+	hj := newExternalHashJoiner(
+		testAllocator, inMemory.(*hashJoinEqOp).spec, leftSource, rightSource,
+		1 /* memoryLimit */, colcontainer.DiskQueueCfg{FS: vfs.NewMem(), Path: "queues"}, sem,
+	).(*externalHashJoiner)
+	hj.Init()
+
+	var count int
+	for b := hj.Next(ctx); b.Length() != 0; b = hj.Next(ctx) {
+		count += int(b.Length())
+	}
+	require.Equal(t, nTuples*nTuples, count)
+	// The partition is partitioned only once more since that doesn't make it
+	// smaller.
+	require.Equal(t, 1, hj.numRepartitions)
+	require.NoError(t, hj.IdempotentClose(ctx))
+	require.Equal(t, 0, sem.GetCount(), "file descriptors weren't released")
+}
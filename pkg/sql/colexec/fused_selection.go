@@ -0,0 +1,104 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+)
+
+// selectionKernel is a stateless selection operator whose predicate can be
+// evaluated on a batch without pulling it from the input. All of the
+// generated comparison selection operators are selection kernels.
+type selectionKernel interface {
+	Operator
+	// Input returns the input of the selection operator.
+	Input() Operator
+	// selectRows updates the selection vector of batch so that it contains only
+	// the tuples that satisfy the predicate, and returns the number of such
+	// tuples. The length of batch is not modified.
+	selectRows(batch coldata.Batch) uint16
+}
+
+// fusedSelOp evaluates a chain of selection kernels on every batch coming from
+// its input. It is planned in place of adjacent selection operators (which
+// are produced by conjunctions of filters), and, unlike the chain of
+// operators, it stops evaluating the predicates as soon as a batch doesn't
+// have any tuples left and pulls the next batch from the input right away.
+type fusedSelOp struct {
+	OneInputNode
+	kernels []selectionKernel
+}
+
+var _ Operator = &fusedSelOp{}
+
+func (p *fusedSelOp) Init() {
+	p.input.Init()
+}
+
+func (p *fusedSelOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		batch := p.input.Next(ctx)
+		n := batch.Length()
+		if n == 0 {
+			return batch
+		}
+		for _, k := range p.kernels {
+			if n = k.selectRows(batch); n == 0 {
+				break
+			}
+			batch.SetLength(n)
+		}
+		if n > 0 {
+			return batch
+		}
+	}
+}
+
+// maybeFuseSelectionOperators returns a single fusedSelOp that is equivalent
+// to the chain of upper on top of lower if both are selection kernels (or
+// fused selection operators themselves) and upper reads directly from lower.
+// Otherwise, upper is returned unchanged.
+func maybeFuseSelectionOperators(lower, upper Operator) Operator {
+	var lowerInput Operator
+	var lowerKernels []selectionKernel
+	switch l := lower.(type) {
+	case *fusedSelOp:
+		lowerInput, lowerKernels = l.input, l.kernels
+	case selectionKernel:
+		lowerInput, lowerKernels = l.Input(), []selectionKernel{l}
+	default:
+		return upper
+	}
+	var upperInput Operator
+	var upperKernels []selectionKernel
+	switch u := upper.(type) {
+	case *fusedSelOp:
+		upperInput, upperKernels = u.input, u.kernels
+	case selectionKernel:
+		upperInput, upperKernels = u.Input(), []selectionKernel{u}
+	default:
+		return upper
+	}
+	if upperInput != lower {
+		// There are other operators (like projections needed by the predicates
+		// of upper) in between, so the kernels can't be fused.
+		return upper
+	}
+	kernels := make([]selectionKernel, 0, len(lowerKernels)+len(upperKernels))
+	kernels = append(kernels, lowerKernels...)
+	kernels = append(kernels, upperKernels...)
+	return &fusedSelOp{
+		OneInputNode: NewOneInputNode(lowerInput),
+		kernels:      kernels,
+	}
+}
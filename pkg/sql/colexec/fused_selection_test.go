@@ -0,0 +1,151 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newSelInt64ConstChain returns a chain of selection operators on top of input
+// that selects the tuples in which the first column is in [lower, upper) and
+// the second column is not equal to ne.
+func newSelInt64ConstChain(input Operator, lower, upper, ne int64) Operator {
+	ge := &selGEInt64Int64ConstOp{
+		selConstOpBase: selConstOpBase{OneInputNode: NewOneInputNode(input), colIdx: 0},
+		constArg:       lower,
+	}
+	lt := &selLTInt64Int64ConstOp{
+		selConstOpBase: selConstOpBase{OneInputNode: NewOneInputNode(ge), colIdx: 0},
+		constArg:       upper,
+	}
+	return &selNEInt64Int64ConstOp{
+		selConstOpBase: selConstOpBase{OneInputNode: NewOneInputNode(lt), colIdx: 1},
+		constArg:       ne,
+	}
+}
+
+// fuseChain fuses the chain of selection operators on top of input into a
+// single operator, the same way the planner does.
+func fuseChain(op Operator, input Operator) Operator {
+	if op == input {
+		return op
+	}
+	return maybeFuseSelectionOperators(fuseChain(op.(selectionKernel).Input(), input), op)
+}
+
+func TestFusedSelOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tups := tuples{
+		{0, 0},
+		{1, 1},
+		{2, 2},
+		{3, 0},
+		{nil, 1},
+		{2, nil},
+		{4, 1},
+		{1, 0},
+	}
+	runTests(t, []tuples{tups}, tuples{{1, 1}, {3, 0}, {1, 0}}, orderedVerifier, func(input []Operator) (Operator, error) {
+		op := fuseChain(newSelInt64ConstChain(input[0], 1 /* lower */, 4 /* upper */, 2 /* ne */), input[0])
+		if _, ok := op.(*fusedSelOp); !ok {
+			t.Fatalf("expected a fused selection operator, got %T", op)
+		}
+		return op, nil
+	})
+	// Make sure that the fused operator keeps pulling batches from its input
+	// until it finds one with selected tuples.
+	runTests(t, []tuples{tups}, tuples{}, orderedVerifier, func(input []Operator) (Operator, error) {
+		return fuseChain(newSelInt64ConstChain(input[0], 5 /* lower */, 10 /* upper */, 2 /* ne */), input[0]), nil
+	})
+}
+
+func TestMaybeFuseSelectionOperators(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	input := newFiniteBatchSource(testAllocator.NewMemBatch([]coltypes.T{coltypes.Int64}), 1)
+	newLT := func(input Operator) Operator {
+		return &selLTInt64Int64ConstOp{
+			selConstOpBase: selConstOpBase{OneInputNode: NewOneInputNode(input)},
+		}
+	}
+
+	// A selection operator on top of a non-selection operator isn't fused.
+	lt1 := newLT(input)
+	require.True(t, maybeFuseSelectionOperators(input, lt1) == lt1)
+
+	// Two adjacent selection operators are fused.
+	lt2 := newLT(lt1)
+	fused, ok := maybeFuseSelectionOperators(lt1, lt2).(*fusedSelOp)
+	require.True(t, ok)
+	require.True(t, fused.input == input)
+	require.Equal(t, []selectionKernel{lt1.(selectionKernel), lt2.(selectionKernel)}, fused.kernels)
+
+	// A fused operator absorbs the selection operator on top of it.
+	lt3 := newLT(fused)
+	fused, ok = maybeFuseSelectionOperators(fused, lt3).(*fusedSelOp)
+	require.True(t, ok)
+	require.True(t, fused.input == input)
+	require.Len(t, fused.kernels, 3)
+
+	// Selection operators that aren't adjacent aren't fused.
+	proj := &simpleProjectOp{OneInputNode: NewOneInputNode(lt1)}
+	lt4 := newLT(proj)
+	require.True(t, maybeFuseSelectionOperators(lt1, lt4) == lt4)
+}
+
+func benchmarkSelectionChain(b *testing.B, fused bool, hasNulls bool) {
+	ctx := context.Background()
+	rng, _ := randutil.NewPseudoRand()
+
+	batch := testAllocator.NewMemBatch([]coltypes.T{coltypes.Int64, coltypes.Int64})
+	col1 := batch.ColVec(0).Int64()
+	col2 := batch.ColVec(1).Int64()
+	for i := 0; i < int(coldata.BatchSize()); i++ {
+		col1[i] = rng.Int63n(100)
+		col2[i] = rng.Int63n(10)
+		if hasNulls && rng.Float64() < nullProbability {
+			batch.ColVec(0).Nulls().SetNull(uint16(i))
+		}
+	}
+	batch.SetLength(coldata.BatchSize())
+	source := NewRepeatableBatchSource(batch)
+	source.Init()
+
+	// The chain selects roughly 45% of the tuples.
+	op := newSelInt64ConstChain(source, 25 /* lower */, 75 /* upper */, 0 /* ne */)
+	if fused {
+		op = fuseChain(op, source)
+	}
+	op.Init()
+
+	b.SetBytes(int64(8 * coldata.BatchSize() * 2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		op.Next(ctx)
+	}
+}
+
+func BenchmarkFusedSelOp(b *testing.B) {
+	for _, fused := range []bool{false, true} {
+		for _, hasNulls := range []bool{false, true} {
+			b.Run(fmt.Sprintf("fused=%t,hasNulls=%t", fused, hasNulls), func(b *testing.B) {
+				benchmarkSelectionChain(b, fused, hasNulls)
+			})
+		}
+	}
+}
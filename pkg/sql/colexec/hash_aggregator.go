@@ -19,6 +19,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
 )
@@ -55,7 +57,9 @@ import (
 
 // NewHashAggregator creates a hash aggregator on the given grouping
 // columns. The input specifications to this function are the same as that of
-// the NewOrderedAggregator function.
+// the NewOrderedAggregator function. Note that the hash aggregator doesn't
+// preserve the order of the rows within a group, so it must not be used with
+// order-sensitive aggregate functions (see isOrderSensitiveAggFn).
 func NewHashAggregator(
 	allocator *Allocator,
 	input Operator,
@@ -63,8 +67,15 @@ func NewHashAggregator(
 	aggFns []execinfrapb.AggregatorSpec_Func,
 	groupCols []uint32,
 	aggCols [][]uint32,
+	constArguments []tree.Datums,
+	sqlTypes []types.T,
 	isScalar bool,
 ) (Operator, error) {
+	for _, aggFn := range aggFns {
+		if isOrderSensitiveAggFn(aggFn) {
+			return nil, errors.AssertionFailedf("%s is not supported by the hash aggregator", aggFn)
+		}
+	}
 	aggTyps := extractAggTypes(aggCols, colTypes)
 
 	// Only keep relevant output columns, those that are used as input to an
@@ -119,7 +130,9 @@ func NewHashAggregator(
 		},
 	)
 
-	funcs, outTyps, err := makeAggregateFuncs(allocator, aggTyps, aggFns)
+	funcs, outTyps, err := makeAggregateFuncs(
+		allocator, aggTyps, extractAggSQLTypes(aggCols, sqlTypes), aggFns, constArguments,
+	)
 	if err != nil {
 		return nil, errors.AssertionFailedf(
 			"this error should have been checked in isAggregateSupported\n%+v", err,
@@ -0,0 +1,196 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
+)
+
+// hashSetOpOp performs INTERSECT ALL and EXCEPT ALL set operations on the
+// equality columns of its inputs. It emits the left tuples: INTERSECT ALL emits
+// min(l, r) and EXCEPT ALL emits max(l-r, 0) of the tuples of each key, where l
+// and r are the numbers of the left and right tuples with that key. NULL keys
+// are considered equal to each other.
+//
+// The right input is fully consumed into a hash table during the build phase.
+// Every key is then assigned to the first tuple with that key in the bucket
+// chain of the hash table (which is found by both the build and the probe
+// lookups), and that tuple stores the number of right tuples with the key. In
+// the probe phase, each left tuple with a key present in the hash table
+// consumes one of those right tuples if there are any left, which is the
+// tuples that INTERSECT ALL emits and EXCEPT ALL discards.
+type hashSetOpOp struct {
+	twoInputNode
+
+	allocator   *Allocator
+	joinType    sqlbase.JoinType
+	leftEqCols  []uint32
+	rightEqCols []uint32
+	rightTypes  []coltypes.T
+
+	ht      *hashTable
+	builder *hashJoinBuilder
+	built   bool
+	// counts stores, at the keyID of the first tuple of every key of the hash
+	// table, the number of right tuples with that key that haven't been
+	// consumed by the left tuples yet.
+	counts []uint64
+}
+
+var _ Operator = &hashSetOpOp{}
+
+// NewHashSetOpOp returns an operator which performs the INTERSECT ALL or
+// EXCEPT ALL set operation (as specified by joinType) on leftEqCols of the
+// left input and rightEqCols of the right input. The output has the same
+// columns as the left input.
+func NewHashSetOpOp(
+	allocator *Allocator,
+	leftSource Operator,
+	rightSource Operator,
+	leftEqCols []uint32,
+	rightEqCols []uint32,
+	rightTypes []coltypes.T,
+	joinType sqlbase.JoinType,
+) (Operator, error) {
+	if !joinType.IsSetOpJoin() {
+		return nil, errors.AssertionFailedf("unexpected join type %s for a set operation", joinType)
+	}
+	if len(leftEqCols) != len(rightEqCols) {
+		return nil, errors.AssertionFailedf(
+			"different number of equality columns: %d on the left and %d on the right",
+			len(leftEqCols), len(rightEqCols),
+		)
+	}
+	return &hashSetOpOp{
+		twoInputNode: newTwoInputNode(leftSource, rightSource),
+		allocator:    allocator,
+		joinType:     joinType,
+		leftEqCols:   leftEqCols,
+		rightEqCols:  rightEqCols,
+		rightTypes:   rightTypes,
+	}, nil
+}
+
+func (op *hashSetOpOp) Init() {
+	op.inputOne.Init()
+	op.inputTwo.Init()
+
+	// Only the equality columns of the right input are stored.
+	op.ht = makeHashTable(
+		op.allocator,
+		hashTableBucketSize,
+		op.rightTypes,
+		op.rightEqCols,
+		nil,  /* outCols */
+		true, /* allowNullEquality */
+	)
+	op.builder = makeHashJoinBuilder(op.ht, hashJoinerSourceSpec{
+		eqCols:      op.rightEqCols,
+		sourceTypes: op.rightTypes,
+		source:      op.inputTwo,
+	})
+}
+
+// build consumes the right input and counts the right tuples of every key.
+func (op *hashSetOpOp) build(ctx context.Context) {
+	op.builder.distinctExec(ctx)
+	op.counts = make([]uint64, op.ht.vals.length+1)
+
+	// Look up every right tuple in the hash table to find the first tuple with
+	// its key, like the probe phase will do.
+	for batchStart := uint64(0); batchStart < op.ht.vals.length; {
+		batchEnd := batchStart + uint64(coldata.BatchSize())
+		if batchEnd > op.ht.vals.length {
+			batchEnd = op.ht.vals.length
+		}
+		batchSize := uint16(batchEnd - batchStart)
+		for i, keyCol := range op.ht.keyCols {
+			op.ht.keys[i] = op.ht.vals.colVecs[keyCol].Window(op.ht.valTypes[keyCol], batchStart, batchEnd)
+		}
+		op.lookup(ctx, batchSize, nil /* sel */)
+		for i := uint16(0); i < batchSize; i++ {
+			op.counts[op.ht.groupID[i]]++
+		}
+		batchStart = batchEnd
+	}
+	op.built = true
+}
+
+// lookup finds, for each of the keys in op.ht.keys, the keyID of the first
+// tuple of the hash table with that key and stores it in op.ht.groupID. The
+// keyID is 0 if the key isn't present in the hash table.
+func (op *hashSetOpOp) lookup(ctx context.Context, batchSize uint16, sel []uint16) {
+	op.ht.lookupInitial(ctx, batchSize, sel)
+	nToCheck := batchSize
+	for nToCheck > 0 {
+		nToCheck = op.ht.distinctCheck(nToCheck, sel)
+		op.ht.findNext(nToCheck)
+	}
+}
+
+func (op *hashSetOpOp) Next(ctx context.Context) coldata.Batch {
+	if !op.built {
+		op.build(ctx)
+	}
+	intersect := op.joinType == sqlbase.IntersectAllJoin
+	for {
+		batch := op.inputOne.Next(ctx)
+		batchSize := batch.Length()
+		if batchSize == 0 {
+			return batch
+		}
+		if op.ht.vals.length == 0 {
+			if intersect {
+				// The intersection with an empty set is empty, so the rest of the
+				// left input doesn't need to be read.
+				return coldata.ZeroBatch
+			}
+			return batch
+		}
+
+		for i, colIdx := range op.leftEqCols {
+			op.ht.keys[i] = batch.ColVec(int(colIdx))
+		}
+		sel := batch.Selection()
+		op.lookup(ctx, batchSize, sel)
+
+		if sel == nil {
+			batch.SetSelection(true)
+			sel = batch.Selection()
+			for i := uint16(0); i < batchSize; i++ {
+				sel[i] = i
+			}
+		}
+		// The selection vector is compacted in place: only the tuples at indices
+		// lower than i have been overwritten.
+		var nResults uint16
+		for i := uint16(0); i < batchSize; i++ {
+			matched := false
+			if keyID := op.ht.groupID[i]; keyID != 0 && op.counts[keyID] > 0 {
+				op.counts[keyID]--
+				matched = true
+			}
+			if matched == intersect {
+				sel[nResults] = sel[i]
+				nResults++
+			}
+		}
+		if nResults > 0 {
+			batch.SetLength(nResults)
+			return batch
+		}
+	}
+}
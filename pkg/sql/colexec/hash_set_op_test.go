@@ -0,0 +1,105 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestHashSetOpOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	typs := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	left := tuples{{1, 10}, {1, 11}, {1, 12}, {2, 20}, {nil, 30}, {nil, 31}, {3, 40}}
+	right := tuples{{1, 100}, {nil, 300}, {1, 101}, {4, 400}}
+	tcs := []struct {
+		desc     string
+		joinType sqlbase.JoinType
+		left     tuples
+		right    tuples
+		eqCols   []uint32
+		expected tuples
+	}{
+		{
+			desc:     "intersect with duplicates",
+			joinType: sqlbase.IntersectAllJoin,
+			left:     left,
+			right:    right,
+			eqCols:   []uint32{0},
+			expected: tuples{{1, 10}, {1, 11}, {nil, 30}},
+		},
+		{
+			desc:     "except with duplicates",
+			joinType: sqlbase.ExceptAllJoin,
+			left:     left,
+			right:    right,
+			eqCols:   []uint32{0},
+			expected: tuples{{1, 12}, {2, 20}, {nil, 31}, {3, 40}},
+		},
+		{
+			desc:     "intersect on multiple columns",
+			joinType: sqlbase.IntersectAllJoin,
+			left:     tuples{{1, 1}, {1, 1}, {1, 2}, {nil, nil}, {nil, nil}},
+			right:    tuples{{1, 2}, {1, 1}, {1, 2}, {nil, nil}},
+			eqCols:   []uint32{0, 1},
+			expected: tuples{{1, 1}, {1, 2}, {nil, nil}},
+		},
+		{
+			desc:     "except on multiple columns",
+			joinType: sqlbase.ExceptAllJoin,
+			left:     tuples{{1, 1}, {1, 1}, {1, 2}, {nil, nil}, {nil, nil}},
+			right:    tuples{{1, 2}, {1, 1}, {1, 2}, {nil, nil}},
+			eqCols:   []uint32{0, 1},
+			expected: tuples{{1, 1}, {nil, nil}},
+		},
+		{
+			desc:     "except with empty right input",
+			joinType: sqlbase.ExceptAllJoin,
+			left:     left,
+			right:    tuples{},
+			eqCols:   []uint32{0},
+			expected: left,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			runTestsWithTyps(
+				t,
+				[]tuples{tc.left, tc.right},
+				[][]coltypes.T{typs, typs},
+				tc.expected,
+				orderedVerifier,
+				func(inputs []Operator) (Operator, error) {
+					return NewHashSetOpOp(
+						testAllocator, inputs[0], inputs[1], tc.eqCols, tc.eqCols, typs, tc.joinType,
+					)
+				},
+			)
+		})
+	}
+
+	// The intersection with an empty set is empty regardless of the NULLs.
+	runTestsWithoutAllNullsInjection(
+		t,
+		[]tuples{left, {}},
+		[][]coltypes.T{typs, typs},
+		tuples{},
+		orderedVerifier,
+		func(inputs []Operator) (Operator, error) {
+			return NewHashSetOpOp(
+				testAllocator, inputs[0], inputs[1], []uint32{0}, []uint32{0}, typs, sqlbase.IntersectAllJoin,
+			)
+		},
+	)
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/colsimd"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+// TestSIMDHashMatchesMemhash verifies that the colsimd hash kernels, which the
+// hash table uses for int64 and float64 keys without nulls, produce the same
+// values as the hash functions used for all other keys. Otherwise the hash
+// router would route equal keys to different outputs depending on whether they
+// had nulls or a selection vector.
+func TestSIMDHashMatchesMemhash(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng, _ := randutil.NewPseudoRand()
+	const n = 1027
+
+	seeds := make([]uint64, n)
+	ints := make([]int64, n)
+	floats := make([]float64, n)
+	for i := range seeds {
+		seeds[i] = rng.Uint64()
+		ints[i] = rng.Int63() - rng.Int63()
+		switch rng.Intn(10) {
+		case 0:
+			floats[i] = math.NaN()
+		case 1:
+			floats[i] = math.Copysign(0, -1)
+		case 2:
+			floats[i] = 0
+		default:
+			floats[i] = rng.NormFloat64()
+		}
+	}
+
+	buckets := make([]uint64, n)
+	copy(buckets, seeds)
+	colsimd.HashInt64(buckets, ints)
+	for i := range ints {
+		if expected := uint64(memhash64(noescape(unsafe.Pointer(&ints[i])), uintptr(seeds[i]))); buckets[i] != expected {
+			t.Fatalf("hash of %d: expected %d, got %d", ints[i], expected, buckets[i])
+		}
+	}
+
+	copy(buckets, seeds)
+	colsimd.HashFloat64(buckets, floats)
+	for i := range floats {
+		f := floats[i]
+		if math.IsNaN(f) {
+			f = 0
+		}
+		if expected := uint64(f64hash(noescape(unsafe.Pointer(&f)), uintptr(seeds[i]))); buckets[i] != expected {
+			t.Fatalf("hash of %f: expected %d, got %d", floats[i], expected, buckets[i])
+		}
+	}
+}
@@ -43,6 +43,11 @@ const (
 	// emitting unmatched rows from its build table after having consumed the
 	// probe table. This happens in the case of an outer join on the build side.
 	hjEmittingUnmatched
+
+	// hjDone represents the state the hashJoiner is in when it has determined
+	// that it won't emit any more tuples. This happens when an anti join that
+	// rejects on NULL finds a NULL key on the build side.
+	hjDone
 )
 
 // hashJoinerSpec is the specification for a hash joiner processor. The hash
@@ -62,6 +67,20 @@ type hashJoinerSpec struct {
 	// buildDistinct indicates whether or not the build table equality column
 	// tuples are distinct. If they are distinct, performance can be optimized.
 	buildDistinct bool
+
+	// joinType is the type of the join. Only LEFT ANTI joins need to be
+	// distinguished by the hash joiner, the other types are described by the
+	// outer flags of the sources.
+	joinType sqlbase.JoinType
+
+	// nullEquality indicates that NULL = NULL should be considered true.
+	nullEquality bool
+
+	// rejectOnNull indicates that this is a LEFT ANTI join with the semantics of
+	// NOT IN on a single equality column: no tuples are emitted if the build
+	// (right) side has a NULL key, and the probe tuples with a NULL key are only
+	// emitted if the build side is empty.
+	rejectOnNull bool
 }
 
 type hashJoinerSourceSpec struct {
@@ -196,6 +215,26 @@ type hashJoinEqOp struct {
 	emittingUnmatchedState struct {
 		rowIdx uint64
 	}
+
+	// bloomFilter, if enabled, is used to discard the probe tuples that have no
+	// match on the build side before probing them.
+	bloomFilter struct {
+		enabled bool
+		// op is the operator applying the filter. It is nil when the filter is
+		// disabled or can't be used for the join type.
+		op    *bloomFilterOp
+		stats BloomFilterStats
+	}
+
+	// exportBufferedState is used when the hash joiner falls back to the
+	// external hash joiner in order to export the buffered build tuples.
+	exportBufferedState struct {
+		batch coldata.Batch
+		// unstoredCols are the columns of the build side that are not stored in
+		// the hash table. They are exported as NULLs.
+		unstoredCols []int
+		exported     uint64
+	}
 }
 
 func (hj *hashJoinEqOp) ChildCount(verbose bool) int {
@@ -214,7 +253,7 @@ func (hj *hashJoinEqOp) Child(nth int, verbose bool) execinfra.OpNode {
 	return nil
 }
 
-var _ Operator = &hashJoinEqOp{}
+var _ twoInputBufferingInMemoryOperator = &hashJoinEqOp{}
 
 func (hj *hashJoinEqOp) Init() {
 	hj.spec.left.source.Init()
@@ -237,7 +276,7 @@ func (hj *hashJoinEqOp) Init() {
 		build.sourceTypes,
 		build.eqCols,
 		build.outCols,
-		false, /* allowNullEquality */
+		hj.spec.nullEquality,
 	)
 
 	hj.builder = makeHashJoinBuilder(
@@ -245,6 +284,21 @@ func (hj *hashJoinEqOp) Init() {
 		build,
 	)
 
+	// The anti join emits exactly the probe tuples that have no match, so the
+	// bloom filter would discard the wrong tuples.
+	if hj.bloomFilter.enabled && !probe.outer && hj.spec.joinType != sqlbase.JoinType_LEFT_ANTI {
+		hj.bloomFilter.op = newBloomFilterOp(
+			hj.allocator, probe.source, hj.ht, probe.eqCols, &hj.bloomFilter.stats,
+		)
+		hj.builder.bloomFilterOp = hj.bloomFilter.op
+		// The filter is best applied by the probe scan itself, which then skips
+		// the decoding of the discarded tuples. Otherwise it is planned on top of
+		// the probe source.
+		if !hj.bloomFilter.op.pushDown(probe.source) {
+			probe.source = hj.bloomFilter.op
+		}
+	}
+
 	hj.prober = makeHashJoinProber(
 		hj.allocator,
 		hj.ht, probe, build,
@@ -252,6 +306,7 @@ func (hj *hashJoinEqOp) Init() {
 		hj.spec.buildDistinct,
 		hj.outputBatchSize,
 	)
+	hj.prober.anti = hj.spec.joinType == sqlbase.JoinType_LEFT_ANTI
 
 	hj.runningState = hjBuilding
 }
@@ -274,6 +329,9 @@ func (hj *hashJoinEqOp) Next(ctx context.Context) coldata.Batch {
 		case hjEmittingUnmatched:
 			hj.emitUnmatched()
 			return hj.prober.batch
+		case hjDone:
+			hj.prober.batch.SetLength(0)
+			return hj.prober.batch
 		default:
 			execerror.VectorizedInternalPanic("hash joiner in unhandled state")
 			// This code is unreachable, but the compiler cannot infer that.
@@ -285,6 +343,23 @@ func (hj *hashJoinEqOp) Next(ctx context.Context) coldata.Batch {
 func (hj *hashJoinEqOp) build(ctx context.Context) {
 	hj.builder.distinctExec(ctx)
 
+	if hj.spec.rejectOnNull && hj.ht.vals.length > 0 {
+		nulls := hj.ht.vals.colVecs[hj.ht.keyCols[0]].Nulls()
+		if nulls.MaybeHasNulls() {
+			for i := uint64(0); i < hj.ht.vals.length; i++ {
+				if nulls.NullAt64(i) {
+					// NOT IN is never true if the build side has a NULL, so no tuples
+					// are emitted.
+					hj.runningState = hjDone
+					return
+				}
+			}
+		}
+		// NULL NOT IN (non-empty set) is never true, so the probe tuples with a
+		// NULL key must not be emitted.
+		hj.prober.rejectNullKeys = true
+	}
+
 	if !hj.spec.buildDistinct {
 		hj.ht.same = make([]uint64, hj.ht.vals.length+1)
 		hj.ht.allocateVisited()
@@ -297,6 +372,65 @@ func (hj *hashJoinEqOp) build(ctx context.Context) {
 	hj.runningState = hjProbing
 }
 
+// ExportBuffered is part of the twoInputBufferingInMemoryOperator interface.
+// The hash table only stores the equality and the output columns of the build
+// side, so all other columns of the exported batches are NULL.
+func (hj *hashJoinEqOp) ExportBuffered(allocator *Allocator, input Operator) coldata.Batch {
+	build := hj.spec.left
+	if hj.spec.buildRightSide {
+		build = hj.spec.right
+	}
+	if input != build.source || hj.exportBufferedState.exported == hj.ht.vals.length {
+		// Nothing is consumed from the probe side while the build side is being
+		// buffered up.
+		return coldata.ZeroBatch
+	}
+	if hj.exportBufferedState.batch == nil {
+		hj.exportBufferedState.batch = allocator.NewMemBatch(build.sourceTypes)
+		stored := make([]bool, len(build.sourceTypes))
+		for _, colIdx := range hj.ht.valCols {
+			stored[colIdx] = true
+		}
+		for colIdx := range stored {
+			if !stored[colIdx] {
+				hj.exportBufferedState.unstoredCols = append(hj.exportBufferedState.unstoredCols, colIdx)
+			}
+		}
+	}
+	batch := hj.exportBufferedState.batch
+	batch.ResetInternalBatch()
+	startIdx := hj.exportBufferedState.exported
+	endIdx := startIdx + uint64(coldata.BatchSize())
+	if endIdx > hj.ht.vals.length {
+		endIdx = hj.ht.vals.length
+	}
+	allocator.PerformOperation(batch.ColVecs(), func() {
+		for i, colIdx := range hj.ht.valCols {
+			batch.ColVec(int(colIdx)).Copy(
+				coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						ColType:     hj.ht.valTypes[i],
+						Src:         hj.ht.vals.colVecs[i],
+						SrcStartIdx: startIdx,
+						SrcEndIdx:   endIdx,
+					},
+				},
+			)
+		}
+	})
+	for _, colIdx := range hj.exportBufferedState.unstoredCols {
+		batch.ColVec(colIdx).Nulls().SetNulls()
+	}
+	batch.SetLength(uint16(endIdx - startIdx))
+	hj.exportBufferedState.exported = endIdx
+	return batch
+}
+
+// isBuffering is part of the twoInputBufferingInMemoryOperator interface.
+func (hj *hashJoinEqOp) isBuffering() bool {
+	return hj.runningState == hjBuilding
+}
+
 func (hj *hashJoinEqOp) emitUnmatched() {
 	// Set all elements in the probe columns of the output batch to null.
 	for i := range hj.prober.spec.outCols {
@@ -426,6 +560,10 @@ type hashTable struct {
 	// each other.
 	allowNullEquality bool
 
+	// probeDatumKey and buildDatumKey are scratch space for the key encodings of
+	// the keys of coltypes.Datum type.
+	probeDatumKey, buildDatumKey []byte
+
 	cancelChecker CancelChecker
 }
 
@@ -570,18 +708,29 @@ func (ht *hashTable) finalizeHash(buckets []uint64, nKeys uint64) {
 func (ht *hashTable) computeBuckets(
 	ctx context.Context, buckets []uint64, keys []coldata.Vec, nKeys uint64, sel []uint16,
 ) {
-	ht.initHash(buckets, nKeys)
-
 	if nKeys == 0 {
 		// No work to do - avoid doing the loops below.
 		return
 	}
 
-	for i, k := range ht.keyCols {
-		ht.rehash(ctx, buckets, i, ht.valTypes[k], keys[i], nKeys, sel)
+	ht.computeHashes(ctx, buckets, keys, nKeys, sel)
+	ht.finalizeHash(buckets, nKeys)
+}
+
+// computeHashes computes the full (i.e. not yet reduced to the bucket size)
+// hash value of each key and stores the result in hashes.
+func (ht *hashTable) computeHashes(
+	ctx context.Context, hashes []uint64, keys []coldata.Vec, nKeys uint64, sel []uint16,
+) {
+	ht.initHash(hashes, nKeys)
+
+	if nKeys == 0 {
+		return
 	}
 
-	ht.finalizeHash(buckets, nKeys)
+	for i, k := range ht.keyCols {
+		ht.rehash(ctx, hashes, i, ht.valTypes[k], keys[i], nKeys, sel)
+	}
 }
 
 // buildNextChains builds the hash map from the computed hash values.
@@ -613,6 +762,10 @@ type hashJoinBuilder struct {
 	// spec holds the specifications for the source operator used in the build
 	// phase.
 	spec hashJoinerSourceSpec
+
+	// bloomFilterOp, if not nil, is populated with the hashes of all the build
+	// keys by distinctExec.
+	bloomFilterOp *bloomFilterOp
 }
 
 func makeHashJoinBuilder(ht *hashTable, spec hashJoinerSourceSpec) *hashJoinBuilder {
@@ -694,7 +847,15 @@ func (builder *hashJoinBuilder) distinctExec(ctx context.Context) {
 
 	// builder.ht.next is used to store the computed hash value of each key.
 	builder.ht.next = make([]uint64, builder.ht.vals.length+1)
-	builder.ht.computeBuckets(ctx, builder.ht.next[1:], keyCols, builder.ht.vals.length, nil)
+	if builder.bloomFilterOp != nil {
+		// The bloom filter needs the full hash values, so we populate it before
+		// the hashes are reduced to the bucket size.
+		builder.ht.computeHashes(ctx, builder.ht.next[1:], keyCols, builder.ht.vals.length, nil)
+		builder.bloomFilterOp.buildFilter(builder.ht.next[1:])
+		builder.ht.finalizeHash(builder.ht.next[1:], builder.ht.vals.length)
+	} else {
+		builder.ht.computeBuckets(ctx, builder.ht.next[1:], keyCols, builder.ht.vals.length, nil)
+	}
 	builder.ht.buildNextChains(ctx)
 }
 
@@ -753,6 +914,14 @@ type hashJoinProber struct {
 	// prevBatch, if not nil, indicates that the previous probe input batch has
 	// not been fully processed.
 	prevBatch coldata.Batch
+
+	// anti indicates that the prober emits the probe tuples that have no match
+	// on the build side instead of the matching ones. It is only used with a
+	// distinct build table.
+	anti bool
+	// rejectNullKeys indicates that the anti join must not emit the probe
+	// tuples with a NULL key.
+	rejectNullKeys bool
 }
 
 func makeHashJoinProber(
@@ -869,7 +1038,11 @@ func (prober *hashJoinProber) exec(ctx context.Context) {
 					prober.ht.findNext(nToCheck)
 				}
 
-				nResults = prober.distinctCollect(batch, batchSize, sel)
+				if prober.anti {
+					nResults = prober.antiCollect(batchSize, sel)
+				} else {
+					nResults = prober.distinctCollect(batch, batchSize, sel)
+				}
 			} else {
 				for nToCheck > 0 {
 					// Continue searching for the build table matching keys while the toCheck
@@ -1045,6 +1218,34 @@ func (prober *hashJoinProber) congregate(nResults uint16, batch coldata.Batch, b
 	prober.batch.SetLength(nResults)
 }
 
+// antiCollect prepares the batch with the probe tuples that have no match in
+// the build table, i.e. whose groupID is 0 after probing a distinct build
+// table. Only the probe columns are emitted by an anti join.
+func (prober *hashJoinProber) antiCollect(batchSize uint16, sel []uint16) uint16 {
+	var nulls *coldata.Nulls
+	if prober.rejectNullKeys {
+		if n := prober.ht.keys[0].Nulls(); n.MaybeHasNulls() {
+			nulls = n
+		}
+	}
+	nResults := uint16(0)
+	for i := uint16(0); i < batchSize; i++ {
+		if prober.ht.groupID[i] != 0 {
+			continue
+		}
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		if nulls != nil && nulls.NullAt(rowIdx) {
+			continue
+		}
+		prober.probeIdx[nResults] = rowIdx
+		nResults++
+	}
+	return nResults
+}
+
 // distinctCheck determines if the current key in the groupID buckets matches the
 // equality column key. If there is a match, then the key is removed from
 // toCheck. If the bucket has reached the end, the key is rejected. The toCheck
@@ -1066,10 +1267,21 @@ func (ht *hashTable) distinctCheck(nToCheck uint16, sel []uint16) uint16 {
 	return nDiffers
 }
 
+// enableBloomFilter makes the hash joiner filter its probe side with a bloom
+// filter built from the build side equality columns. The filter is only used if
+// the probe side is not outer. The returned stats are updated as the probe
+// side is filtered.
+func (hj *hashJoinEqOp) enableBloomFilter() *BloomFilterStats {
+	hj.bloomFilter.enabled = true
+	return &hj.bloomFilter.stats
+}
+
 // NewEqHashJoinerOp creates a new equality hash join operator on the left and
 // right input tables. leftEqCols and rightEqCols specify the equality columns
 // while leftOutCols and rightOutCols specifies the output columns. leftTypes
 // and rightTypes specify the input column types of the two sources.
+// nullEquality and rejectOnNull have the same meaning as in the
+// HashJoinerSpec.
 func NewEqHashJoinerOp(
 	allocator *Allocator,
 	leftSource Operator,
@@ -1083,6 +1295,8 @@ func NewEqHashJoinerOp(
 	buildRightSide bool,
 	buildDistinct bool,
 	joinType sqlbase.JoinType,
+	nullEquality bool,
+	rejectOnNull bool,
 ) (Operator, error) {
 	var leftOuter, rightOuter bool
 	switch joinType {
@@ -1107,9 +1321,24 @@ func NewEqHashJoinerOp(
 		if len(rightOutCols) != 0 {
 			return nil, errors.Errorf("semi-join can't have right-side output columns")
 		}
+	case sqlbase.JoinType_LEFT_ANTI:
+		// Similarly to a semi-join, an anti-join only needs to know whether a row
+		// on the left matches any row on the right.
+		buildRightSide = true
+		buildDistinct = true
+		if len(rightOutCols) != 0 {
+			return nil, errors.Errorf("anti-join can't have right-side output columns")
+		}
 	default:
 		return nil, errors.Errorf("hash join of type %s not supported", joinType)
 	}
+	if rejectOnNull {
+		if joinType != sqlbase.JoinType_LEFT_ANTI || len(leftEqCols) != 1 || nullEquality {
+			return nil, errors.Errorf(
+				"reject on null is only supported for LEFT ANTI joins on a single equality column without null equality",
+			)
+		}
+	}
 
 	spec := hashJoinerSpec{
 		left: hashJoinerSourceSpec{
@@ -1130,6 +1359,9 @@ func NewEqHashJoinerOp(
 
 		buildRightSide: buildRightSide,
 		buildDistinct:  buildDistinct,
+		joinType:       joinType,
+		nullEquality:   nullEquality,
+		rejectOnNull:   rejectOnNull,
 	}
 
 	return &hashJoinEqOp{
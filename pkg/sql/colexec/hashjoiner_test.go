@@ -54,6 +54,11 @@ type hjTestCase struct {
 	expectedTuples tuples
 
 	onExpr execinfrapb.Expression
+
+	nullEquality bool
+	rejectOnNull bool
+
+	skipAllNullsInjection bool
 }
 
 var (
@@ -866,6 +871,147 @@ func init() {
 				{2, 4},
 			},
 		},
+		{
+			leftTypes:  []coltypes.T{coltypes.Int64},
+			rightTypes: []coltypes.T{coltypes.Int64},
+
+			// Test LEFT ANTI join, NULLs never match.
+			joinType: sqlbase.JoinType_LEFT_ANTI,
+
+			leftTuples: tuples{
+				{0},
+				{0},
+				{1},
+				{2},
+				{nil},
+			},
+			rightTuples: tuples{
+				{0},
+				{0},
+				{nil},
+			},
+
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+
+			expectedTuples: tuples{
+				{1},
+				{2},
+				{nil},
+			},
+		},
+		{
+			leftTypes:  []coltypes.T{coltypes.Int64},
+			rightTypes: []coltypes.T{coltypes.Int64},
+
+			// Test null equality.
+			joinType:     sqlbase.JoinType_INNER,
+			nullEquality: true,
+
+			leftTuples: tuples{
+				{nil},
+				{1},
+				{2},
+			},
+			rightTuples: tuples{
+				{nil},
+				{2},
+				{3},
+			},
+
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{0},
+
+			expectedTuples: tuples{
+				{nil, nil},
+				{2, 2},
+			},
+		},
+		{
+			leftTypes:  []coltypes.T{coltypes.Int64},
+			rightTypes: []coltypes.T{coltypes.Int64},
+
+			// Test LEFT ANTI join rejecting on NULL with no NULLs on the right.
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+
+			leftTuples: tuples{
+				{nil},
+				{1},
+				{2},
+				{3},
+			},
+			rightTuples: tuples{
+				{2},
+				{4},
+			},
+
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+
+			expectedTuples: tuples{
+				{1},
+				{3},
+			},
+		},
+		{
+			leftTypes:  []coltypes.T{coltypes.Int64},
+			rightTypes: []coltypes.T{coltypes.Int64},
+
+			// Test LEFT ANTI join rejecting on NULL with a NULL on the right.
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+
+			leftTuples: tuples{
+				{nil},
+				{1},
+				{2},
+			},
+			rightTuples: tuples{
+				{2},
+				{nil},
+			},
+
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+
+			expectedTuples: tuples{},
+			// The expected output here is empty, so will it be during the all nulls
+			// injection, so we want to skip that.
+			skipAllNullsInjection: true,
+		},
+		{
+			leftTypes:  []coltypes.T{coltypes.Int64},
+			rightTypes: []coltypes.T{coltypes.Int64},
+
+			// Test LEFT ANTI join rejecting on NULL with an empty right side.
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+
+			leftTuples: tuples{
+				{nil},
+				{1},
+			},
+			rightTuples: tuples{},
+
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+
+			expectedTuples: tuples{
+				{nil},
+				{1},
+			},
+		},
 	}
 }
 
@@ -877,6 +1023,8 @@ func createSpecForHashJoiner(tc hjTestCase) *execinfrapb.ProcessorSpec {
 		RightEqColumnsAreKey: tc.rightEqColsAreKey,
 		OnExpr:               tc.onExpr,
 		Type:                 tc.joinType,
+		NullEquality:         tc.nullEquality,
+		RejectOnNull:         tc.rejectOnNull,
 	}
 	projection := make([]uint32, 0, len(tc.leftOutCols)+len(tc.rightOutCols))
 	projection = append(projection, tc.leftOutCols...)
@@ -921,7 +1069,15 @@ func TestHashJoiner(t *testing.T) {
 		for _, tc := range tcs {
 			inputs := []tuples{tc.leftTuples, tc.rightTuples}
 			typs := [][]coltypes.T{tc.leftTypes, tc.rightTypes}
-			runTestsWithTyps(t, inputs, typs, tc.expectedTuples, unorderedVerifier, func(sources []Operator) (Operator, error) {
+			var runner testRunner
+			if tc.skipAllNullsInjection {
+				// We're omitting all nulls injection test. See comments for each such
+				// test case.
+				runner = runTestsWithoutAllNullsInjection
+			} else {
+				runner = runTestsWithTyps
+			}
+			runner(t, inputs, typs, tc.expectedTuples, unorderedVerifier, func(sources []Operator) (Operator, error) {
 				spec := createSpecForHashJoiner(tc)
 				args := NewColOperatorArgs{
 					Spec:                               spec,
@@ -954,7 +1110,7 @@ func TestHashJoinerOutputsOnlyRequestedColumns(t *testing.T) {
 			tc.leftOutCols, tc.rightOutCols,
 			tc.leftTypes, tc.rightTypes,
 			tc.rightEqColsAreKey, tc.leftEqColsAreKey || tc.rightEqColsAreKey,
-			tc.joinType)
+			tc.joinType, tc.nullEquality, tc.rejectOnNull)
 		require.NoError(t, err)
 		hjOp.Init()
 		for {
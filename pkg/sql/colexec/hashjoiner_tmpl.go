@@ -27,7 +27,9 @@ import (
 	"reflect"
 	"unsafe"
 
+	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colsimd"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
@@ -36,6 +38,9 @@ import (
 
 // {{/*
 
+// Dummy import to pull in "apd" package.
+var _ apd.Decimal
+
 // Dummy import to pull in "tree" package.
 var _ tree.Datum
 
@@ -178,6 +183,13 @@ func _REHASH_BODY(
 	_HAS_NULLS bool,
 ) { // */}}
 	// {{define "rehashBody"}}
+	// {{ if and (not .HasSel) (not .HasNulls) .Global.SIMDType }}
+	// The keys are contiguous and have no nulls, so they can be hashed by the
+	// data-parallel kernel, which produces the same hash values as the loop
+	// below.
+	ht.cancelChecker.check(ctx)
+	colsimd.Hash_SIMD_TYPE(buckets[:nKeys], keys[:nKeys])
+	// {{ else }}
 	// Early bounds checks.
 	_ = buckets[nKeys-1]
 	// {{ if .HasSel }}
@@ -202,6 +214,7 @@ func _REHASH_BODY(
 		_ASSIGN_HASH(p, v)
 		buckets[i] = uint64(p)
 	}
+	// {{ end }}
 	// {{end}}
 
 	// {{/*
@@ -362,6 +375,8 @@ func (ht *hashTable) rehash(
 	sel []uint16,
 ) {
 	switch t {
+	case coltypes.Datum:
+		ht.rehashDatums(ctx, buckets, col, nKeys, sel)
 	// {{range $hashType := .HashTemplate}}
 	case _TYPES_T:
 		keys, nulls := col._TemplateType(), col.Nulls()
@@ -392,6 +407,8 @@ func (ht *hashTable) rehash(
 // there is no match.
 func (ht *hashTable) checkCol(t coltypes.T, keyColIdx int, nToCheck uint16, sel []uint16) {
 	switch t {
+	case coltypes.Datum:
+		ht.checkDatumCol(keyColIdx, nToCheck, sel)
 	// {{range $neType := .NETemplate}}
 	case _TYPES_T:
 		buildVec := ht.vals.colVecs[ht.keyCols[keyColIdx]]
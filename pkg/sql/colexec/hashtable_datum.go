@@ -0,0 +1,98 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// This file contains the hashTable methods for the key columns of
+// coltypes.Datum type, which can't be generated from the templates. Such keys
+// are hashed and compared using their key encoding, which is the same for all
+// the datums that are equal even if they aren't identical (for example, the
+// collated strings that differ only in ways that their collation ignores).
+// This is also how the row-by-row hash joiner compares its keys.
+
+// encodeDatumKey appends the key encoding of the datum to b.
+func encodeDatumKey(b []byte, d interface{}) []byte {
+	b, err := sqlbase.EncodeTableKey(b, d.(tree.Datum), encoding.Ascending)
+	if err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	return b
+}
+
+// rehashDatums is the same as rehash for a key column of coltypes.Datum type.
+func (ht *hashTable) rehashDatums(
+	ctx context.Context, buckets []uint64, col coldata.Vec, nKeys uint64, sel []uint16,
+) {
+	keys, nulls := col.Datum(), col.Nulls()
+	maybeHasNulls := col.MaybeHasNulls()
+	for i := uint64(0); i < nKeys; i++ {
+		ht.cancelChecker.check(ctx)
+		selIdx := uint16(i)
+		if sel != nil {
+			selIdx = sel[i]
+		}
+		if maybeHasNulls && nulls.NullAt(selIdx) {
+			continue
+		}
+		ht.probeDatumKey = encodeDatumKey(ht.probeDatumKey[:0], keys[selIdx])
+		sh := (*reflect.SliceHeader)(unsafe.Pointer(&ht.probeDatumKey))
+		buckets[i] = uint64(memhash(unsafe.Pointer(sh.Data), uintptr(buckets[i]), uintptr(sh.Len)))
+	}
+}
+
+// checkDatumCol is the same as checkCol for a key column of coltypes.Datum
+// type.
+func (ht *hashTable) checkDatumCol(keyColIdx int, nToCheck uint16, sel []uint16) {
+	buildVec := ht.vals.colVecs[ht.keyCols[keyColIdx]]
+	probeVec := ht.keys[keyColIdx]
+	buildKeys, probeKeys := buildVec.Datum(), probeVec.Datum()
+	probeMaybeHasNulls, buildMaybeHasNulls := probeVec.MaybeHasNulls(), buildVec.MaybeHasNulls()
+	for i := uint16(0); i < nToCheck; i++ {
+		// keyID of 0 is reserved to represent the end of the next chain.
+		toCheck := ht.toCheck[i]
+		keyID := ht.groupID[toCheck]
+		if keyID == 0 {
+			continue
+		}
+		selIdx := toCheck
+		if sel != nil {
+			selIdx = sel[toCheck]
+		}
+		probeIsNull := probeMaybeHasNulls && probeVec.Nulls().NullAt(selIdx)
+		buildIsNull := buildMaybeHasNulls && buildVec.Nulls().NullAt64(keyID-1)
+		if ht.allowNullEquality && probeIsNull && buildIsNull {
+			continue
+		}
+		if probeIsNull {
+			ht.groupID[toCheck] = 0
+		} else if buildIsNull {
+			ht.differs[toCheck] = true
+		} else {
+			ht.probeDatumKey = encodeDatumKey(ht.probeDatumKey[:0], probeKeys[selIdx])
+			ht.buildDatumKey = encodeDatumKey(ht.buildDatumKey[:0], buildKeys[keyID-1])
+			if !bytes.Equal(ht.probeDatumKey, ht.buildDatumKey) {
+				ht.differs[toCheck] = true
+			}
+		}
+	}
+}
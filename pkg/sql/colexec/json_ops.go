@@ -0,0 +1,273 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// JSON columns are represented by flat Bytes vectors that store the encoded
+// form of each value (see json.EncodeJSON). The operators in this file decode
+// the values lazily (json.FromEncoding doesn't decode the containers upfront),
+// so fetching a single key out of a large document doesn't require decoding
+// the whole document.
+
+// projJSONFetchConstOp is an Operator that projects the result of the ->
+// (or ->>, if asText is true) operator applied to a JSON column and a constant
+// key (or index, if byIdx is true).
+type projJSONFetchConstOp struct {
+	projConstOpBase
+
+	key    string
+	idx    int
+	byIdx  bool
+	asText bool
+
+	scratch []byte
+}
+
+var _ Operator = &projJSONFetchConstOp{}
+
+func (p *projJSONFetchConstOp) Init() {
+	p.input.Init()
+}
+
+func (p *projJSONFetchConstOp) fetch(encoded []byte) (json.JSON, error) {
+	j, err := json.FromEncoding(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if p.byIdx {
+		return j.FetchValIdx(p.idx)
+	}
+	return j.FetchValKey(p.key)
+}
+
+func (p *projJSONFetchConstOp) Next(ctx context.Context) coldata.Batch {
+	batch := p.input.Next(ctx)
+	n := batch.Length()
+	if p.outputIdx == batch.Width() {
+		p.allocator.AppendColumn(batch, coltypes.Bytes)
+	}
+	if n == 0 {
+		return batch
+	}
+	vec := batch.ColVec(p.colIdx)
+	col := vec.Bytes()
+	projVec := batch.ColVec(p.outputIdx)
+	projCol := projVec.Bytes()
+	// The result can be NULL even if the input is not (when the key is not
+	// present), so we start out with a copy of the input nulls and then add to
+	// them.
+	if vec.MaybeHasNulls() {
+		nullsCopy := vec.Nulls().Copy()
+		projVec.SetNulls(&nullsCopy)
+	} else {
+		projVec.Nulls().UnsetNulls()
+	}
+	projNulls := projVec.Nulls()
+	sel := batch.Selection()
+	p.allocator.PerformOperation(
+		[]coldata.Vec{projVec},
+		func() {
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				if projNulls.NullAt(rowIdx) {
+					continue
+				}
+				res, err := p.fetch(col.Get(int(rowIdx)))
+				if err != nil {
+					execerror.NonVectorizedPanic(err)
+				}
+				if res == nil {
+					projNulls.SetNull(rowIdx)
+					continue
+				}
+				if p.asText {
+					text, err := res.AsText()
+					if err != nil {
+						execerror.NonVectorizedPanic(err)
+					}
+					if text == nil {
+						projNulls.SetNull(rowIdx)
+						continue
+					}
+					p.scratch = append(p.scratch[:0], *text...)
+				} else {
+					p.scratch, err = json.EncodeJSON(p.scratch[:0], res)
+					if err != nil {
+						execerror.NonVectorizedPanic(err)
+					}
+				}
+				projCol.Set(int(rowIdx), p.scratch)
+			}
+		},
+	)
+	// Although we didn't change the length of the batch, it is necessary to set
+	// the length anyway (this helps maintaining the invariant of flat bytes).
+	batch.SetLength(n)
+	return batch
+}
+
+// projJSONContainsConstOp is an Operator that projects the result of the @>
+// operator applied to a JSON column and a constant JSON value (or <@, if
+// containedBy is true).
+type projJSONContainsConstOp struct {
+	projConstOpBase
+
+	constArg    json.JSON
+	containedBy bool
+}
+
+var _ Operator = &projJSONContainsConstOp{}
+
+func (p *projJSONContainsConstOp) Init() {
+	p.input.Init()
+}
+
+func (p *projJSONContainsConstOp) Next(ctx context.Context) coldata.Batch {
+	batch := p.input.Next(ctx)
+	n := batch.Length()
+	if p.outputIdx == batch.Width() {
+		p.allocator.AppendColumn(batch, coltypes.Bool)
+	}
+	if n == 0 {
+		return batch
+	}
+	vec := batch.ColVec(p.colIdx)
+	col := vec.Bytes()
+	projVec := batch.ColVec(p.outputIdx)
+	projCol := projVec.Bool()
+	hasNulls := vec.MaybeHasNulls()
+	nulls := vec.Nulls()
+	sel := batch.Selection()
+	for i := uint16(0); i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		if hasNulls && nulls.NullAt(rowIdx) {
+			continue
+		}
+		j, err := json.FromEncoding(col.Get(int(rowIdx)))
+		if err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+		var res bool
+		if p.containedBy {
+			res, err = json.Contains(p.constArg, j)
+		} else {
+			res, err = json.Contains(j, p.constArg)
+		}
+		if err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+		projCol[rowIdx] = res
+	}
+	if hasNulls {
+		nullsCopy := nulls.Copy()
+		projVec.SetNulls(&nullsCopy)
+	}
+	return batch
+}
+
+// isJSONOperator returns whether op applied to a JSON column on the left and
+// a constant of type rightType on the right can be planned using
+// GetJSONProjectionConstOperator.
+func isJSONOperator(op tree.Operator, rightType *types.T) bool {
+	switch op {
+	case tree.JSONFetchVal, tree.JSONFetchText:
+		switch rightType.Family() {
+		case types.StringFamily, types.IntFamily:
+			return true
+		}
+	case tree.Contains, tree.ContainedBy:
+		return rightType.Family() == types.JsonFamily
+	}
+	return false
+}
+
+// checkJSONBinOp returns an error if binOp has a JSON argument and cannot be
+// evaluated on the encoded form of the JSON values. Only the operators
+// supported by GetJSONProjectionConstOperator and the NULL checks can be. In
+// particular, the comparisons can't since the encoding doesn't preserve the
+// JSON ordering (e.g. 1 and 1.0 are equal but are encoded differently), so
+// they are left to the row engine.
+func checkJSONBinOp(binOp tree.Operator, left, right tree.TypedExpr) error {
+	leftIsJSON := left.ResolvedType().Family() == types.JsonFamily
+	if !leftIsJSON && right.ResolvedType().Family() != types.JsonFamily {
+		return nil
+	}
+	if _, rConst := right.(tree.Datum); rConst && leftIsJSON && isJSONOperator(binOp, right.ResolvedType()) {
+		return nil
+	}
+	if (binOp == tree.IsDistinctFrom || binOp == tree.IsNotDistinctFrom) && right == tree.DNull {
+		return nil
+	}
+	return errors.Newf("%s with JSON arguments is not supported", binOp)
+}
+
+// GetJSONProjectionConstOperator returns a projection operator that applies
+// op to the JSON column at colIdx and the constant constArg, writing the
+// result into resultIdx. The supported operators are ->, ->>, @> and <@.
+func GetJSONProjectionConstOperator(
+	allocator *Allocator,
+	op tree.Operator,
+	input Operator,
+	colIdx int,
+	resultIdx int,
+	constArg tree.Datum,
+) (Operator, error) {
+	base := projConstOpBase{
+		OneInputNode: NewOneInputNode(input),
+		allocator:    allocator,
+		colIdx:       colIdx,
+		outputIdx:    resultIdx,
+	}
+	switch op {
+	case tree.JSONFetchVal, tree.JSONFetchText:
+		fetchOp := &projJSONFetchConstOp{
+			projConstOpBase: base,
+			asText:          op == tree.JSONFetchText,
+		}
+		switch t := constArg.(type) {
+		case *tree.DString:
+			fetchOp.key = string(*t)
+		case *tree.DInt:
+			fetchOp.idx, fetchOp.byIdx = int(*t), true
+		default:
+			return nil, errors.Errorf("unsupported JSON fetch argument %s", constArg)
+		}
+		return fetchOp, nil
+	case tree.Contains, tree.ContainedBy:
+		j, ok := constArg.(*tree.DJSON)
+		if !ok {
+			return nil, errors.Errorf("unsupported JSON containment argument %s", constArg)
+		}
+		return &projJSONContainsConstOp{
+			projConstOpBase: base,
+			constArg:        j.JSON,
+			containedBy:     op == tree.ContainedBy,
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported JSON operator %s", op)
+}
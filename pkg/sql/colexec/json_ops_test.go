@@ -0,0 +1,96 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// encodeJSONForTest returns the physical representation of the JSON value
+// described by s.
+func encodeJSONForTest(t *testing.T, s string) string {
+	j, err := json.ParseJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.EncodeJSON(nil, j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestJSONProjectionOperators(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	obj := encodeJSONForTest(t, `{"a": 1, "b": "foo", "c": [1, 2]}`)
+	arr := encodeJSONForTest(t, `["x", "y"]`)
+	constJSON := func(s string) tree.Datum {
+		d, err := tree.ParseDJSON(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	for _, tc := range []struct {
+		op       tree.Operator
+		constArg tree.Datum
+		tups     tuples
+		expected tuples
+	}{
+		{
+			op:       tree.JSONFetchVal,
+			constArg: tree.NewDString("c"),
+			tups:     tuples{{obj}, {arr}, {nil}},
+			expected: tuples{{obj, encodeJSONForTest(t, `[1, 2]`)}, {arr, nil}, {nil, nil}},
+		},
+		{
+			op:       tree.JSONFetchText,
+			constArg: tree.NewDString("b"),
+			tups:     tuples{{obj}, {arr}, {nil}},
+			expected: tuples{{obj, "foo"}, {arr, nil}, {nil, nil}},
+		},
+		{
+			op:       tree.JSONFetchText,
+			constArg: tree.NewDInt(1),
+			tups:     tuples{{obj}, {arr}},
+			expected: tuples{{obj, nil}, {arr, "y"}},
+		},
+		{
+			op:       tree.Contains,
+			constArg: constJSON(`{"a": 1}`),
+			tups:     tuples{{obj}, {arr}, {nil}},
+			expected: tuples{{obj, true}, {arr, false}, {nil, nil}},
+		},
+		{
+			op:       tree.ContainedBy,
+			constArg: constJSON(`["x", "y", "z"]`),
+			tups:     tuples{{obj}, {arr}},
+			expected: tuples{{obj, false}, {arr, true}},
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/%s", tc.op, tc.constArg), func(t *testing.T) {
+			runTests(
+				t, []tuples{tc.tups}, tc.expected, orderedVerifier,
+				func(input []Operator) (Operator, error) {
+					return GetJSONProjectionConstOperator(
+						testAllocator, tc.op, input[0], 0 /* colIdx */, 1 /* resultIdx */, tc.constArg,
+					)
+				})
+		})
+	}
+}
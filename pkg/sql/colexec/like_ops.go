@@ -30,11 +30,15 @@ const (
 	likeSuffixNegate
 	likePrefix
 	likePrefixNegate
+	likeContains
+	likeContainsNegate
 	likeRegexp
 	likeRegexpNegate
 )
 
-func getLikeOperatorType(pattern string, negate bool) (likeOpType, string, error) {
+func getLikeOperatorType(
+	pattern string, negate bool, caseInsensitive bool,
+) (likeOpType, string, error) {
 	if pattern == "" {
 		if negate {
 			return likeConstantNegate, "", nil
@@ -47,6 +51,14 @@ func getLikeOperatorType(pattern string, negate bool) (likeOpType, string, error
 		}
 		return likeAlwaysMatch, "", nil
 	}
+	if caseInsensitive {
+		// All of the specialized implementations below compare the raw bytes, so
+		// ILIKE always has to go through the case-insensitive regular expression.
+		if negate {
+			return likeRegexpNegate, pattern, nil
+		}
+		return likeRegexp, pattern, nil
+	}
 	if len(pattern) > 1 && !strings.ContainsAny(pattern[1:len(pattern)-1], "_%") {
 		// There are no wildcards in the middle of the string, so we only need to
 		// use a regular expression if both the first and last characters are
@@ -75,6 +87,17 @@ func getLikeOperatorType(pattern string, negate bool) (likeOpType, string, error
 			return likePrefix, prefix, nil
 		}
 	}
+	if len(pattern) > 2 && pattern[0] == '%' && pattern[len(pattern)-1] == '%' {
+		// The pattern is of the form '%foo%'. If there are no wildcards or escape
+		// characters in the middle, we only need to search for a substring.
+		contains := pattern[1 : len(pattern)-1]
+		if !strings.ContainsAny(contains, "_%\\") {
+			if negate {
+				return likeContainsNegate, contains, nil
+			}
+			return likeContains, contains, nil
+		}
+	}
 	// Default (slow) case: execute as a regular expression match.
 	if negate {
 		return likeRegexpNegate, pattern, nil
@@ -83,12 +106,18 @@ func getLikeOperatorType(pattern string, negate bool) (likeOpType, string, error
 }
 
 // GetLikeOperator returns a selection operator which applies the specified LIKE
-// pattern, or NOT LIKE if the negate argument is true. The implementation
+// pattern, or NOT LIKE if the negate argument is true. If caseInsensitive is
+// true, the operator implements ILIKE (or NOT ILIKE). The implementation
 // varies depending on the complexity of the pattern.
 func GetLikeOperator(
-	ctx *tree.EvalContext, input Operator, colIdx int, pattern string, negate bool,
+	ctx *tree.EvalContext,
+	input Operator,
+	colIdx int,
+	pattern string,
+	negate bool,
+	caseInsensitive bool,
 ) (Operator, error) {
-	likeOpType, pattern, err := getLikeOperatorType(pattern, negate)
+	likeOpType, pattern, err := getLikeOperatorType(pattern, negate, caseInsensitive)
 	if err != nil {
 		return nil, err
 	}
@@ -140,8 +169,18 @@ func GetLikeOperator(
 			selConstOpBase: base,
 			constArg:       pat,
 		}, nil
+	case likeContains:
+		return &selContainsBytesBytesConstOp{
+			selConstOpBase: base,
+			constArg:       pat,
+		}, nil
+	case likeContainsNegate:
+		return &selNotContainsBytesBytesConstOp{
+			selConstOpBase: base,
+			constArg:       pat,
+		}, nil
 	case likeRegexp:
-		re, err := tree.ConvertLikeToRegexp(ctx, pattern, false, '\\')
+		re, err := tree.ConvertLikeToRegexp(ctx, pattern, caseInsensitive, '\\')
 		if err != nil {
 			return nil, err
 		}
@@ -150,7 +189,7 @@ func GetLikeOperator(
 			constArg:       re,
 		}, nil
 	case likeRegexpNegate:
-		re, err := tree.ConvertLikeToRegexp(ctx, pattern, false, '\\')
+		re, err := tree.ConvertLikeToRegexp(ctx, pattern, caseInsensitive, '\\')
 		if err != nil {
 			return nil, err
 		}
@@ -169,7 +208,9 @@ func isWildcard(c byte) bool {
 
 // GetLikeProjectionOperator returns a projection operator which projects the
 // result of the specified LIKE pattern, or NOT LIKE if the negate argument is
-// true. The implementation varies depending on the complexity of the pattern.
+// true. If caseInsensitive is true, the operator implements ILIKE (or NOT
+// ILIKE). The implementation varies depending on the complexity of the
+// pattern.
 func GetLikeProjectionOperator(
 	allocator *Allocator,
 	ctx *tree.EvalContext,
@@ -178,8 +219,9 @@ func GetLikeProjectionOperator(
 	resultIdx int,
 	pattern string,
 	negate bool,
+	caseInsensitive bool,
 ) (Operator, error) {
-	likeOpType, pattern, err := getLikeOperatorType(pattern, negate)
+	likeOpType, pattern, err := getLikeOperatorType(pattern, negate, caseInsensitive)
 	if err != nil {
 		return nil, err
 	}
@@ -233,8 +275,18 @@ func GetLikeProjectionOperator(
 			projConstOpBase: base,
 			constArg:        pat,
 		}, nil
+	case likeContains:
+		return &projContainsBytesBytesConstOp{
+			projConstOpBase: base,
+			constArg:        pat,
+		}, nil
+	case likeContainsNegate:
+		return &projNotContainsBytesBytesConstOp{
+			projConstOpBase: base,
+			constArg:        pat,
+		}, nil
 	case likeRegexp:
-		re, err := tree.ConvertLikeToRegexp(ctx, pattern, false, '\\')
+		re, err := tree.ConvertLikeToRegexp(ctx, pattern, caseInsensitive, '\\')
 		if err != nil {
 			return nil, err
 		}
@@ -243,7 +295,7 @@ func GetLikeProjectionOperator(
 			constArg:        re,
 		}, nil
 	case likeRegexpNegate:
-		re, err := tree.ConvertLikeToRegexp(ctx, pattern, false, '\\')
+		re, err := tree.ConvertLikeToRegexp(ctx, pattern, caseInsensitive, '\\')
 		if err != nil {
 			return nil, err
 		}
@@ -255,3 +307,106 @@ func GetLikeProjectionOperator(
 		return nil, errors.AssertionFailedf("unsupported like op type %d", likeOpType)
 	}
 }
+
+// GetRegexpMatchOperator returns a selection operator which applies the
+// specified POSIX regular expression (the ~ operator), or !~ if the negate
+// argument is true. If caseInsensitive is true, the operator implements ~* (or
+// !~*).
+func GetRegexpMatchOperator(
+	ctx *tree.EvalContext,
+	input Operator,
+	colIdx int,
+	pattern string,
+	negate bool,
+	caseInsensitive bool,
+) (Operator, error) {
+	re, err := tree.ConvertRegMatchToRegexp(ctx, pattern, caseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	base := selConstOpBase{
+		OneInputNode: NewOneInputNode(input),
+		colIdx:       colIdx,
+	}
+	if negate {
+		return &selNotRegexpBytesBytesConstOp{
+			selConstOpBase: base,
+			constArg:       re,
+		}, nil
+	}
+	return &selRegexpBytesBytesConstOp{
+		selConstOpBase: base,
+		constArg:       re,
+	}, nil
+}
+
+// GetRegexpMatchProjectionOperator returns a projection operator which
+// projects the result of matching the specified POSIX regular expression (the
+// ~ operator), or !~ if the negate argument is true. If caseInsensitive is
+// true, the operator implements ~* (or !~*).
+func GetRegexpMatchProjectionOperator(
+	allocator *Allocator,
+	ctx *tree.EvalContext,
+	input Operator,
+	colIdx int,
+	resultIdx int,
+	pattern string,
+	negate bool,
+	caseInsensitive bool,
+) (Operator, error) {
+	re, err := tree.ConvertRegMatchToRegexp(ctx, pattern, caseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	base := projConstOpBase{
+		OneInputNode: NewOneInputNode(input),
+		allocator:    allocator,
+		colIdx:       colIdx,
+		outputIdx:    resultIdx,
+	}
+	if negate {
+		return &projNotRegexpBytesBytesConstOp{
+			projConstOpBase: base,
+			constArg:        re,
+		}, nil
+	}
+	return &projRegexpBytesBytesConstOp{
+		projConstOpBase: base,
+		constArg:        re,
+	}, nil
+}
+
+// patternMatchOpInfo describes how a comparison operator that matches strings
+// against a pattern should be planned.
+type patternMatchOpInfo struct {
+	// isLike is true for the LIKE family of operators and false for the POSIX
+	// regular expression family (~, ~* and their negations).
+	isLike          bool
+	negate          bool
+	caseInsensitive bool
+}
+
+// getPatternMatchOpInfo returns the information about the pattern matching
+// comparison operator op, if op is one that can be planned using the
+// specialized operators in this file.
+func getPatternMatchOpInfo(op tree.Operator) (patternMatchOpInfo, bool) {
+	switch op {
+	case tree.Like:
+		return patternMatchOpInfo{isLike: true}, true
+	case tree.NotLike:
+		return patternMatchOpInfo{isLike: true, negate: true}, true
+	case tree.ILike:
+		return patternMatchOpInfo{isLike: true, caseInsensitive: true}, true
+	case tree.NotILike:
+		return patternMatchOpInfo{isLike: true, negate: true, caseInsensitive: true}, true
+	case tree.RegMatch:
+		return patternMatchOpInfo{}, true
+	case tree.NotRegMatch:
+		return patternMatchOpInfo{negate: true}, true
+	case tree.RegIMatch:
+		return patternMatchOpInfo{caseInsensitive: true}, true
+	case tree.NotRegIMatch:
+		return patternMatchOpInfo{negate: true, caseInsensitive: true}, true
+	}
+	return patternMatchOpInfo{}, false
+}
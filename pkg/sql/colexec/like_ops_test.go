@@ -27,10 +27,11 @@ import (
 func TestLikeOperators(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	for _, tc := range []struct {
-		pattern  string
-		negate   bool
-		tups     tuples
-		expected tuples
+		pattern         string
+		negate          bool
+		caseInsensitive bool
+		tups            tuples
+		expected        tuples
 	}{
 		{
 			pattern:  "def",
@@ -87,12 +88,118 @@ func TestLikeOperators(t *testing.T) {
 			tups:     tuples{{"abc"}, {"def"}, {"ghi"}},
 			expected: tuples{{"abc"}, {"ghi"}},
 		},
+		{
+			pattern:  "%b_%",
+			tups:     tuples{{"abc"}, {"def"}, {"ab"}},
+			expected: tuples{{"abc"}},
+		},
+		{
+			pattern:         "DE%",
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {"def"}, {"DEF"}, {"ghi"}},
+			expected:        tuples{{"def"}, {"DEF"}},
+		},
+		{
+			pattern:         "%E%",
+			negate:          true,
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {"def"}, {"DEF"}, {"ghi"}},
+			expected:        tuples{{"abc"}, {"ghi"}},
+		},
+		{
+			pattern:         "%",
+			negate:          true,
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {nil}},
+			expected:        tuples{},
+		},
 	} {
 		runTests(
 			t, []tuples{tc.tups}, tc.expected, orderedVerifier,
 			func(input []Operator) (Operator, error) {
 				ctx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
-				return GetLikeOperator(&ctx, input[0], 0, tc.pattern, tc.negate)
+				return GetLikeOperator(&ctx, input[0], 0, tc.pattern, tc.negate, tc.caseInsensitive)
+			})
+	}
+}
+
+func TestLikeProjectionOperators(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, tc := range []struct {
+		pattern         string
+		negate          bool
+		caseInsensitive bool
+		tups            tuples
+		expected        tuples
+	}{
+		{
+			pattern:  "%e%",
+			tups:     tuples{{"abc"}, {"def"}, {nil}},
+			expected: tuples{{"abc", false}, {"def", true}, {nil, nil}},
+		},
+		{
+			pattern:  "%e%",
+			negate:   true,
+			tups:     tuples{{"abc"}, {"def"}, {nil}},
+			expected: tuples{{"abc", true}, {"def", false}, {nil, nil}},
+		},
+		{
+			pattern:         "A_C",
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {"ABC"}, {"abd"}},
+			expected:        tuples{{"abc", true}, {"ABC", true}, {"abd", false}},
+		},
+	} {
+		runTests(
+			t, []tuples{tc.tups}, tc.expected, orderedVerifier,
+			func(input []Operator) (Operator, error) {
+				ctx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+				return GetLikeProjectionOperator(
+					testAllocator, &ctx, input[0], 0, 1, tc.pattern, tc.negate, tc.caseInsensitive,
+				)
+			})
+	}
+}
+
+func TestRegexpMatchOperators(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, tc := range []struct {
+		pattern         string
+		negate          bool
+		caseInsensitive bool
+		tups            tuples
+		expected        tuples
+	}{
+		{
+			pattern:  "^d.f$",
+			tups:     tuples{{"abc"}, {"def"}, {"DEF"}},
+			expected: tuples{{"def"}},
+		},
+		{
+			pattern:  "b",
+			negate:   true,
+			tups:     tuples{{"abc"}, {"def"}, {"ghi"}},
+			expected: tuples{{"def"}, {"ghi"}},
+		},
+		{
+			pattern:         "^d.f$",
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {"def"}, {"DEF"}},
+			expected:        tuples{{"def"}, {"DEF"}},
+		},
+		{
+			pattern:         "E",
+			negate:          true,
+			caseInsensitive: true,
+			tups:            tuples{{"abc"}, {"def"}, {"DEF"}},
+			expected:        tuples{{"abc"}},
+		},
+	} {
+		runTests(
+			t, []tuples{tc.tups}, tc.expected, orderedVerifier,
+			func(input []Operator) (Operator, error) {
+				ctx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+				return GetRegexpMatchOperator(&ctx, input[0], 0, tc.pattern, tc.negate, tc.caseInsensitive)
 			})
 	}
 }
@@ -133,6 +240,10 @@ func BenchmarkLikeOps(b *testing.B) {
 		selConstOpBase: base,
 		constArg:       []byte(suffix),
 	}
+	containsOp := &selContainsBytesBytesConstOp{
+		selConstOpBase: base,
+		constArg:       []byte(prefix),
+	}
 	pattern := fmt.Sprintf("^%s.*%s$", prefix, suffix)
 	regexpOp := &selRegexpBytesBytesConstOp{
 		selConstOpBase: base,
@@ -145,6 +256,7 @@ func BenchmarkLikeOps(b *testing.B) {
 	}{
 		{name: "selPrefixBytesBytesConstOp", op: prefixOp},
 		{name: "selSuffixBytesBytesConstOp", op: suffixOp},
+		{name: "selContainsBytesBytesConstOp", op: containsOp},
 		{name: "selRegexpBytesBytesConstOp", op: regexpOp},
 	}
 	for _, tc := range testCases {
@@ -47,6 +47,10 @@ type Materializer struct {
 	outputRow      sqlbase.EncDatumRow
 	outputMetadata *execinfrapb.ProducerMetadata
 
+	// batchOutput, if set, is the receiver that the batches of the input are
+	// pushed to as they are. See SetBatchOutput.
+	batchOutput execinfra.BatchReceiver
+
 	// cancelFlow will return a function to cancel the context of the flow. It is
 	// a function in order to be lazily evaluated, since the context cancellation
 	// function is only available when Starting. This function differs from
@@ -96,7 +100,13 @@ func NewMaterializer(
 			TrailingMetaCallback: func(ctx context.Context) []execinfrapb.ProducerMetadata {
 				var trailingMeta []execinfrapb.ProducerMetadata
 				for _, src := range metadataSourcesQueue {
-					trailingMeta = append(trailingMeta, src.DrainMeta(ctx)...)
+					// Draining runs vectorized code too, so it must not let a panic
+					// escape the flow either.
+					if err := execerror.CatchVectorizedRuntimeError(func() {
+						trailingMeta = append(trailingMeta, src.DrainMeta(ctx)...)
+					}); err != nil {
+						trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{Err: err})
+					}
 				}
 				m.InternalClose()
 				return trailingMeta
@@ -112,6 +122,14 @@ func NewMaterializer(
 
 var _ execinfra.OpNode = &Materializer{}
 
+// SetBatchOutput makes the Materializer push the batches of its input to
+// output as they are, instead of converting them into rows. The input must not
+// produce batches with a selection vector, and the post-processing spec of the
+// Materializer must be empty. The metadata is still pushed with Push.
+func (m *Materializer) SetBatchOutput(output execinfra.BatchReceiver) {
+	m.batchOutput = output
+}
+
 // ChildCount is part of the exec.OpNode interface.
 func (m *Materializer) ChildCount(verbose bool) int {
 	return 1
@@ -129,8 +147,11 @@ func (m *Materializer) Child(nth int, verbose bool) execinfra.OpNode {
 
 // Start is part of the execinfra.RowSource interface.
 func (m *Materializer) Start(ctx context.Context) context.Context {
-	m.input.Init()
-	return m.ProcessorBase.StartInternal(ctx, materializerProcName)
+	ctx = m.ProcessorBase.StartInternal(ctx, materializerProcName)
+	if err := execerror.CatchVectorizedRuntimeError(m.input.Init); err != nil {
+		m.MoveToDraining(err)
+	}
+	return ctx
 }
 
 // nextAdapter calls next() and saves the returned results in m. For internal
@@ -143,6 +164,9 @@ func (m *Materializer) nextAdapter() {
 // next is the logic of Next() extracted in a separate method to be used by an
 // adapter to be able to wrap the latter with a catcher.
 func (m *Materializer) next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	if m.State == execinfra.StateRunning && m.batchOutput != nil {
+		return m.pushBatches()
+	}
 	if m.State == execinfra.StateRunning {
 		if m.batch == nil || m.curIdx >= m.batch.Length() {
 			// Get a fresh batch.
@@ -172,6 +196,28 @@ func (m *Materializer) next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadat
 	return nil, m.DrainHelper()
 }
 
+// pushBatches pushes the batches of the input to batchOutput until either the
+// input is exhausted or the receiver doesn't need any more of them, and then
+// returns like next().
+func (m *Materializer) pushBatches() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for {
+		batch := m.input.Next(m.Ctx)
+		if batch.Length() == 0 {
+			m.MoveToDraining(nil /* err */)
+			return nil, m.DrainHelper()
+		}
+		switch m.batchOutput.PushBatch(batch) {
+		case execinfra.NeedMoreRows:
+		case execinfra.DrainRequested:
+			m.MoveToDraining(nil /* err */)
+			return nil, m.DrainHelper()
+		case execinfra.ConsumerClosed:
+			m.ConsumerClosed()
+			return nil, nil
+		}
+	}
+}
+
 // Next is part of the execinfra.RowSource interface.
 func (m *Materializer) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
 	if err := execerror.CatchVectorizedRuntimeError(m.nextAdapter); err != nil {
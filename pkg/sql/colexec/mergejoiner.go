@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
 )
 
 // group is an ADT representing a contiguous set of rows that match on their
@@ -92,6 +93,11 @@ type mjProberState struct {
 	rBufferedGroup            *bufferedBatch
 	lBufferedGroupNeedToReset bool
 	rBufferedGroupNeedToReset bool
+
+	// streamedGroupInProgress indicates that only a part of the buffered group
+	// of the streamed input (see mjStreamedInput) has been built so far, and
+	// the group continues in the current batch of that input.
+	streamedGroupInProgress bool
 }
 
 // mjState represents the state of the merge joiner.
@@ -124,6 +130,60 @@ const (
 	mjBuild
 )
 
+// mjStreamedInput identifies the input of the merge joiner whose groups are
+// built in chunks of at most one batch beyond the current one when they span
+// multiple batches, rather than buffered in full before being built.
+type mjStreamedInput int
+
+const (
+	// mjStreamedInputNone indicates that the groups of both inputs are
+	// buffered in full.
+	mjStreamedInputNone mjStreamedInput = iota
+	// mjStreamedInputLeft indicates that the groups of the left input are
+	// streamed. This is possible when the right equality columns form a key.
+	mjStreamedInputLeft
+	// mjStreamedInputRight indicates that the groups of the right input are
+	// streamed. This is possible when the left equality columns form a key.
+	mjStreamedInputRight
+)
+
+// getMJStreamedInput returns the input of the merge joiner of the given type
+// whose groups can be streamed.
+//
+// When the equality columns form a key for one of the inputs, the matching
+// groups of that input consist of a single tuple, so the cross product of the
+// groups can be built from a chunk of the group on the other side at a time.
+// This doesn't hold for LEFT SEMI and LEFT ANTI joins if the left group is
+// the one that would be chunked, since they emit the left tuples depending on
+// the whole right group.
+func getMJStreamedInput(
+	joinType sqlbase.JoinType, leftEqColsAreKey bool, rightEqColsAreKey bool,
+) mjStreamedInput {
+	switch {
+	case leftEqColsAreKey && rightEqColsAreKey:
+		// The groups on both sides consist of a single tuple, so there is
+		// nothing to stream.
+		return mjStreamedInputNone
+	case rightEqColsAreKey:
+		return mjStreamedInputLeft
+	case leftEqColsAreKey && joinType != sqlbase.JoinType_LEFT_SEMI &&
+		joinType != sqlbase.JoinType_LEFT_ANTI:
+		return mjStreamedInputRight
+	default:
+		return mjStreamedInputNone
+	}
+}
+
+// isMergeJoinerStreaming returns whether the merge joiner of the given type
+// uses a bounded amount of memory, i.e. whether it never needs to buffer a
+// whole group that spans multiple batches.
+func isMergeJoinerStreaming(
+	joinType sqlbase.JoinType, leftEqColsAreKey bool, rightEqColsAreKey bool,
+) bool {
+	return (leftEqColsAreKey && rightEqColsAreKey) ||
+		getMJStreamedInput(joinType, leftEqColsAreKey, rightEqColsAreKey) != mjStreamedInputNone
+}
+
 type mergeJoinInput struct {
 	// eqCols specify the indices of the source table equality columns during the
 	// merge join.
@@ -163,6 +223,10 @@ type mergeJoinInput struct {
 // The second pass is where the groups and their associated cross products are
 // materialized into the full output.
 
+// The NULLs never match, so they are skipped wherever they are in the inputs,
+// and the merge join doesn't depend on the placement of the NULLs as long as
+// it is the same in both inputs.
+
 // Two buffers are used, one for the group on the left table and one for the
 // group on the right table. These buffers are only used if the group ends with
 // a batch, to make sure that we don't miss any cross product entries while
@@ -172,7 +236,9 @@ type mergeJoinInput struct {
 // NewMergeJoinOp returns a new merge join operator with the given spec that
 // implements sort-merge join. It performs a merge on the left and right input
 // sources, based on the equality columns, assuming both inputs are in sorted
-// order.
+// order. leftEqColsAreKey and rightEqColsAreKey are the hints from the planner
+// that the equality columns form a key for the corresponding input, and they
+// allow for the groups of the other input to be streamed.
 func NewMergeJoinOp(
 	allocator *Allocator,
 	joinType sqlbase.JoinType,
@@ -184,6 +250,8 @@ func NewMergeJoinOp(
 	rightTypes []coltypes.T,
 	leftOrdering []execinfrapb.Ordering_Column,
 	rightOrdering []execinfrapb.Ordering_Column,
+	leftEqColsAreKey bool,
+	rightEqColsAreKey bool,
 	filterConstructor func(Operator) (Operator, error),
 	filterOnlyOnLeft bool,
 ) (Operator, error) {
@@ -200,6 +268,7 @@ func NewMergeJoinOp(
 		filterConstructor,
 		filterOnlyOnLeft,
 	)
+	base.streamedInput = getMJStreamedInput(joinType, leftEqColsAreKey, rightEqColsAreKey)
 	if filterConstructor != nil {
 		switch joinType {
 		case sqlbase.JoinType_INNER:
@@ -274,6 +343,11 @@ func newMergeJoinBase(
 	filterConstructor func(Operator) (Operator, error),
 	filterOnlyOnLeft bool,
 ) (mergeJoinBase, error) {
+	for i := range leftOrdering {
+		if leftOrdering[i].NullsOrder != rightOrdering[i].NullsOrder {
+			return mergeJoinBase{}, errors.Errorf("unmatched placement of NULLs in column orderings")
+		}
+	}
 	lEqCols := make([]uint32, len(leftOrdering))
 	lDirections := make([]execinfrapb.Ordering_Column_Direction, len(leftOrdering))
 	for i, c := range leftOrdering {
@@ -353,6 +427,10 @@ type mergeJoinBase struct {
 	proberState  mjProberState
 	builderState mjBuilderState
 
+	// streamedInput is the input whose groups are streamed rather than buffered
+	// in full.
+	streamedInput mjStreamedInput
+
 	filter *joinerFilter
 }
 
@@ -481,6 +559,24 @@ func (o *mergeJoinBase) nonEmptyBufferedGroup() bool {
 	return o.proberState.lBufferedGroup.length > 0 || o.proberState.rBufferedGroup.length > 0
 }
 
+// isStreamed returns whether the groups of input are streamed.
+func (o *mergeJoinBase) isStreamed(input *mergeJoinInput) bool {
+	if input == &o.left {
+		return o.streamedInput == mjStreamedInputLeft
+	}
+	return o.streamedInput == mjStreamedInputRight
+}
+
+// setBufferedGroupsNeedToReset marks the buffered groups to be reset once the
+// builder is done with them. If the streamed group isn't complete yet, the
+// buffered group of the other input is kept since it needs to be joined with
+// the remaining chunks of the streamed group.
+func (o *mergeJoinBase) setBufferedGroupsNeedToReset() {
+	inProgress := o.proberState.streamedGroupInProgress
+	o.proberState.lBufferedGroupNeedToReset = !inProgress || o.streamedInput == mjStreamedInputLeft
+	o.proberState.rBufferedGroupNeedToReset = !inProgress || o.streamedInput == mjStreamedInputRight
+}
+
 // sourceFinished returns true if either of input sources has no more rows.
 func (o *mergeJoinBase) sourceFinished() bool {
 	return o.proberState.lLength == 0 || o.proberState.rLength == 0
@@ -492,24 +588,37 @@ func (o *mergeJoinBase) sourceFinished() bool {
 // occurrence in batch (or subsequent batches) that doesn't match the current
 // group.
 // NOTE: we will be buffering all batches until we find such non-matching tuple
-// (or until we exhaust the input).
-// TODO(yuzefovich): this can be refactored so that only the right side does
-// unbounded buffering.
+// (or until we exhaust the input), unless the groups of input are streamed.
+// In the latter case, at most one batch is appended to the buffered group, and
+// if the group continues beyond it, the group is reported as incomplete; the
+// next call continues the group in the following batch after the builder has
+// materialized the tuples buffered so far.
 // SIDE EFFECT: can append to the buffered group corresponding to the source.
 func (o *mergeJoinBase) completeBufferedGroup(
 	ctx context.Context, input *mergeJoinInput, batch coldata.Batch, rowIdx int,
-) (_ coldata.Batch, idx int, batchLength int) {
+) (_ coldata.Batch, idx int, batchLength int, complete bool) {
 	batchLength = int(batch.Length())
-	if o.isBufferedGroupFinished(input, batch, rowIdx) {
-		return batch, rowIdx, batchLength
-	}
-
-	isBufferedGroupComplete := false
-	input.distincter.(resetter).reset()
+	streamed := o.isStreamed(input)
 	// Ignore the first row of the distincter in the first pass since we already
 	// know that we are in the same group and, thus, the row is not distinct,
 	// regardless of what the distincter outputs.
 	loopStartIndex := 1
+	if streamed && o.proberState.streamedGroupInProgress {
+		// The buffered group has already been built and reset, but the
+		// distincter still remembers the last tuple of the group, so it can tell
+		// where the group ends in batch.
+		if batchLength == 0 {
+			return batch, rowIdx, batchLength, true
+		}
+		loopStartIndex = 0
+	} else {
+		if o.isBufferedGroupFinished(input, batch, rowIdx) {
+			return batch, rowIdx, batchLength, true
+		}
+		input.distincter.(resetter).reset()
+	}
+
+	isBufferedGroupComplete := false
 	var sel []uint16
 	for !isBufferedGroupComplete {
 		// Note that we're not resetting the distincter on every loop iteration
@@ -549,6 +658,12 @@ func (o *mergeJoinBase) completeBufferedGroup(
 		rowIdx += groupLength
 
 		if !isBufferedGroupComplete {
+			if streamed {
+				// We have just appended all the tuples from batch to the buffered
+				// group, so they have to be built before the group is continued in
+				// the next batch.
+				return batch, rowIdx, batchLength, false
+			}
 			// The buffered group is still not complete which means that we have
 			// just appended all the tuples from batch to it, so we need to get a
 			// fresh batch from the input.
@@ -561,21 +676,25 @@ func (o *mergeJoinBase) completeBufferedGroup(
 		}
 	}
 
-	return batch, rowIdx, batchLength
+	return batch, rowIdx, batchLength, true
 }
 
-// finishProbe completes the buffered groups on both sides of the input.
+// finishProbe completes the buffered groups on both sides of the input. If the
+// groups of one of the inputs are streamed, its buffered group might only be
+// completed by the subsequent calls.
 func (o *mergeJoinBase) finishProbe(ctx context.Context) {
-	o.proberState.lBatch, o.proberState.lIdx, o.proberState.lLength = o.completeBufferedGroup(
+	var lComplete, rComplete bool
+	o.proberState.lBatch, o.proberState.lIdx, o.proberState.lLength, lComplete = o.completeBufferedGroup(
 		ctx,
 		&o.left,
 		o.proberState.lBatch,
 		o.proberState.lIdx,
 	)
-	o.proberState.rBatch, o.proberState.rIdx, o.proberState.rLength = o.completeBufferedGroup(
+	o.proberState.rBatch, o.proberState.rIdx, o.proberState.rLength, rComplete = o.completeBufferedGroup(
 		ctx,
 		&o.right,
 		o.proberState.rBatch,
 		o.proberState.rIdx,
 	)
+	o.proberState.streamedGroupInProgress = !lComplete || !rComplete
 }
@@ -13,6 +13,8 @@ package colexec
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
@@ -44,6 +46,9 @@ type mjTestCase struct {
 	outputBatchSize       uint16
 	skipAllNullsInjection bool
 	onExpr                execinfrapb.Expression
+	rejectOnNull          bool
+	leftEqColsAreKey      bool
+	rightEqColsAreKey     bool
 }
 
 func (tc *mjTestCase) Init() {
@@ -88,10 +93,13 @@ func createSpecForMergeJoiner(tc mjTestCase) *execinfrapb.ProcessorSpec {
 		)
 	}
 	mjSpec := &execinfrapb.MergeJoinerSpec{
-		LeftOrdering:  leftOrdering,
-		RightOrdering: rightOrdering,
-		OnExpr:        tc.onExpr,
-		Type:          tc.joinType,
+		LeftOrdering:         leftOrdering,
+		RightOrdering:        rightOrdering,
+		OnExpr:               tc.onExpr,
+		Type:                 tc.joinType,
+		RejectOnNull:         tc.rejectOnNull,
+		LeftEqColumnsAreKey:  tc.leftEqColsAreKey,
+		RightEqColumnsAreKey: tc.rightEqColsAreKey,
 	}
 	projection := make([]uint32, 0, len(tc.leftOutCols)+len(tc.rightOutCols))
 	projection = append(projection, tc.leftOutCols...)
@@ -1452,6 +1460,51 @@ func TestMergeJoiner(t *testing.T) {
 			rightEqCols:     []uint32{0, 1, 2},
 			expected:        tuples{{2, 3, 1}, {2, nil, 1}, {nil, 1, 3}},
 		},
+		{
+			description:  "LEFT ANTI JOIN rejecting on NULL test, no NULLs on the right",
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+			leftTypes:    []coltypes.T{coltypes.Int64},
+			rightTypes:   []coltypes.T{coltypes.Int64},
+			leftTuples:   tuples{{nil}, {nil}, {1}, {2}, {3}},
+			rightTuples:  tuples{{2}, {4}},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			expected:     tuples{{1}, {3}},
+		},
+		{
+			description:  "LEFT ANTI JOIN rejecting on NULL test, NULL on the right",
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+			leftTypes:    []coltypes.T{coltypes.Int64},
+			rightTypes:   []coltypes.T{coltypes.Int64},
+			leftTuples:   tuples{{nil}, {1}, {2}, {3}},
+			rightTuples:  tuples{{nil}, {2}, {4}},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			expected:     tuples{},
+			// The expected output here is empty, so will it be during the all nulls
+			// injection, so we want to skip that.
+			skipAllNullsInjection: true,
+		},
+		{
+			description:  "LEFT ANTI JOIN rejecting on NULL test, empty right",
+			joinType:     sqlbase.JoinType_LEFT_ANTI,
+			rejectOnNull: true,
+			leftTypes:    []coltypes.T{coltypes.Int64},
+			rightTypes:   []coltypes.T{coltypes.Int64},
+			leftTuples:   tuples{{nil}, {1}, {2}},
+			rightTuples:  tuples{},
+			leftOutCols:  []uint32{0},
+			rightOutCols: []uint32{},
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			expected:     tuples{{nil}, {1}, {2}},
+		},
 		{
 			description:     "3 equality column LEFT ANTI JOIN test with nulls mixed ordering",
 			joinType:        sqlbase.JoinType_LEFT_ANTI,
@@ -1608,6 +1661,77 @@ func TestMergeJoiner(t *testing.T) {
 			onExpr:       execinfrapb.Expression{Expr: "@2 + @3 < 50"},
 			expected:     tuples{{nil, 0}, {2, 20}, {3, nil}},
 		},
+		{
+			description:      "INNER JOIN test with streamed right groups",
+			joinType:         sqlbase.JoinType_INNER,
+			leftTypes:        []coltypes.T{coltypes.Int64, coltypes.Int64},
+			rightTypes:       []coltypes.T{coltypes.Int64, coltypes.Int64},
+			leftTuples:       tuples{{nil, 0}, {1, 10}, {2, 20}, {4, 40}},
+			rightTuples:      tuples{{nil, 1}, {1, 11}, {1, 12}, {1, 13}, {1, 14}, {3, 31}, {4, 41}, {4, 42}, {4, 43}},
+			leftOutCols:      []uint32{0, 1},
+			rightOutCols:     []uint32{1},
+			leftEqCols:       []uint32{0},
+			rightEqCols:      []uint32{0},
+			leftEqColsAreKey: true,
+			expected:         tuples{{1, 10, 11}, {1, 10, 12}, {1, 10, 13}, {1, 10, 14}, {4, 40, 41}, {4, 40, 42}, {4, 40, 43}},
+		},
+		{
+			description:      "FULL OUTER JOIN test with streamed right groups",
+			joinType:         sqlbase.JoinType_FULL_OUTER,
+			leftTypes:        []coltypes.T{coltypes.Int64, coltypes.Int64},
+			rightTypes:       []coltypes.T{coltypes.Int64, coltypes.Int64},
+			leftTuples:       tuples{{nil, 0}, {1, 10}, {2, 20}, {4, 40}},
+			rightTuples:      tuples{{nil, 1}, {1, 11}, {1, 12}, {1, 13}, {3, 31}, {4, 41}, {4, 42}},
+			leftOutCols:      []uint32{1},
+			rightOutCols:     []uint32{1},
+			leftEqCols:       []uint32{0},
+			rightEqCols:      []uint32{0},
+			leftEqColsAreKey: true,
+			expected:         tuples{{0, nil}, {nil, 1}, {10, 11}, {10, 12}, {10, 13}, {20, nil}, {nil, 31}, {40, 41}, {40, 42}},
+		},
+		{
+			description:       "LEFT OUTER JOIN test with streamed left groups",
+			joinType:          sqlbase.JoinType_LEFT_OUTER,
+			leftTypes:         []coltypes.T{coltypes.Int64, coltypes.Int64},
+			rightTypes:        []coltypes.T{coltypes.Int64, coltypes.Int64},
+			leftTuples:        tuples{{nil, 0}, {1, 10}, {1, 11}, {1, 12}, {2, 20}, {3, 30}, {3, 31}, {3, 32}},
+			rightTuples:       tuples{{1, 100}, {3, 300}, {4, 400}},
+			leftOutCols:       []uint32{1},
+			rightOutCols:      []uint32{1},
+			leftEqCols:        []uint32{0},
+			rightEqCols:       []uint32{0},
+			rightEqColsAreKey: true,
+			expected:          tuples{{0, nil}, {10, 100}, {11, 100}, {12, 100}, {20, nil}, {30, 300}, {31, 300}, {32, 300}},
+		},
+		{
+			description:       "LEFT SEMI JOIN test with ON expression and streamed left groups",
+			joinType:          sqlbase.JoinType_LEFT_SEMI,
+			leftTypes:         []coltypes.T{coltypes.Int64, coltypes.Int64},
+			rightTypes:        []coltypes.T{coltypes.Int64, coltypes.Int64},
+			leftTuples:        tuples{{1, 10}, {1, 11}, {1, 12}, {2, 20}, {3, 30}, {3, 31}, {3, 32}},
+			rightTuples:       tuples{{1, 1}, {2, 2}, {3, 3}},
+			leftOutCols:       []uint32{0, 1},
+			rightOutCols:      []uint32{},
+			leftEqCols:        []uint32{0},
+			rightEqCols:       []uint32{0},
+			rightEqColsAreKey: true,
+			onExpr:            execinfrapb.Expression{Expr: "@2 + @4 > 12"},
+			expected:          tuples{{1, 12}, {2, 20}, {3, 30}, {3, 31}, {3, 32}},
+		},
+		{
+			description:      "LEFT ANTI JOIN test with unique left equality columns",
+			joinType:         sqlbase.JoinType_LEFT_ANTI,
+			leftTypes:        []coltypes.T{coltypes.Int64, coltypes.Int64},
+			rightTypes:       []coltypes.T{coltypes.Int64, coltypes.Int64},
+			leftTuples:       tuples{{nil, 0}, {1, 10}, {2, 20}, {4, 40}},
+			rightTuples:      tuples{{1, 11}, {1, 12}, {1, 13}, {3, 31}, {4, 41}, {4, 42}},
+			leftOutCols:      []uint32{0, 1},
+			rightOutCols:     []uint32{},
+			leftEqCols:       []uint32{0},
+			rightEqCols:      []uint32{0},
+			leftEqColsAreKey: true,
+			expected:         tuples{{nil, 0}, {2, 20}},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -1694,6 +1818,8 @@ func TestFullOuterMergeJoinWithMaximumNumberOfGroups(t *testing.T) {
 					typs,
 					[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
 					[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
+					false, /* leftEqColsAreKey */
+					false, /* rightEqColsAreKey */
 					nil,   /* filterConstructor */
 					false, /* filterOnlyOnLeft */
 				)
@@ -1773,6 +1899,8 @@ func TestMergeJoinerMultiBatch(t *testing.T) {
 						typs,
 						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
 						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
+						false, /* leftEqColsAreKey */
+						false, /* rightEqColsAreKey */
 						nil,   /* filterConstructor */
 						false, /* filterOnlyOnLeft */
 					)
@@ -1857,6 +1985,8 @@ func TestMergeJoinerMultiBatchRuns(t *testing.T) {
 						typs,
 						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}, {ColIdx: 1, Direction: execinfrapb.Ordering_Column_ASC}},
 						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}, {ColIdx: 1, Direction: execinfrapb.Ordering_Column_ASC}},
+						false, /* leftEqColsAreKey */
+						false, /* rightEqColsAreKey */
 						nil,   /* filterConstructor */
 						false, /* filterOnlyOnLeft */
 					)
@@ -1991,6 +2121,8 @@ func TestMergeJoinerRandomized(t *testing.T) {
 								typs,
 								[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
 								[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
+								false, /* leftEqColsAreKey */
+								false, /* rightEqColsAreKey */
 								nil,   /* filterConstructor */
 								false, /* filterOnlyOnLeft */
 							)
@@ -2029,6 +2161,148 @@ func TestMergeJoinerRandomized(t *testing.T) {
 	}
 }
 
+// bufferedGroupsLength returns the number of tuples in the buffered groups.
+func (o *mergeJoinBase) bufferedGroupsLength() (left uint64, right uint64) {
+	return o.proberState.lBufferedGroup.length, o.proberState.rBufferedGroup.length
+}
+
+// newStreamedGroupsInput returns the columns of two inputs to be joined on the
+// first column such that the first column is a key for one of them (the key
+// input) while the groups of the other one span multiple batches. The second
+// column contains the ordinals of the tuples.
+func newStreamedGroupsInput(
+	rng *rand.Rand, nValues int, maxGroupSize int,
+) (keyCols []coldata.Vec, keyLen int, otherCols []coldata.Vec, otherLen int) {
+	var keyVals, otherVals []int64
+	for v := int64(0); v < int64(nValues); v++ {
+		if rng.Intn(4) != 0 {
+			keyVals = append(keyVals, v)
+		}
+		for i, n := 0, rng.Intn(maxGroupSize); i < n; i++ {
+			otherVals = append(otherVals, v)
+		}
+	}
+	makeCols := func(vals []int64) []coldata.Vec {
+		cols := []coldata.Vec{
+			testAllocator.NewMemColumn(coltypes.Int64, len(vals)),
+			testAllocator.NewMemColumn(coltypes.Int64, len(vals)),
+		}
+		copy(cols[0].Int64(), vals)
+		ordinals := cols[1].Int64()
+		for i := range ordinals {
+			ordinals[i] = int64(i)
+		}
+		return cols
+	}
+	return makeCols(keyVals), len(keyVals), makeCols(otherVals), len(otherVals)
+}
+
+// TestMergeJoinerStreamedGroups verifies that the merge joiner that streams
+// the groups of one of the inputs (because the equality columns form a key for
+// the other input) produces the same output as the merge joiner that buffers
+// whole groups, and that it buffers at most two batches of the streamed
+// input.
+func TestMergeJoinerStreamedGroups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	rng, _ := randutil.NewPseudoRand()
+	typs := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	ordering := []execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}}
+	maxGroupSize := 3 * int(coldata.BatchSize())
+	for _, joinType := range []sqlbase.JoinType{
+		sqlbase.JoinType_INNER,
+		sqlbase.JoinType_LEFT_OUTER,
+		sqlbase.JoinType_RIGHT_OUTER,
+		sqlbase.JoinType_FULL_OUTER,
+		sqlbase.JoinType_LEFT_SEMI,
+		sqlbase.JoinType_LEFT_ANTI,
+	} {
+		for _, leftIsKey := range []bool{false, true} {
+			streamedInput := getMJStreamedInput(joinType, leftIsKey, !leftIsKey)
+			if streamedInput == mjStreamedInputNone {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s/leftIsKey=%t", joinType, leftIsKey), func(t *testing.T) {
+				keyCols, keyLen, otherCols, otherLen := newStreamedGroupsInput(rng, 8 /* nValues */, maxGroupSize)
+				lCols, lLen, rCols, rLen := otherCols, otherLen, keyCols, keyLen
+				if leftIsKey {
+					lCols, lLen, rCols, rLen = keyCols, keyLen, otherCols, otherLen
+				}
+				var rightOutCols []uint32
+				if joinType != sqlbase.JoinType_LEFT_SEMI && joinType != sqlbase.JoinType_LEFT_ANTI {
+					rightOutCols = []uint32{0, 1}
+				}
+
+				run := func(useKeyHints bool) []string {
+					op, err := NewMergeJoinOp(
+						testAllocator,
+						joinType,
+						newChunkingBatchSource(typs, lCols, uint64(lLen)),
+						newChunkingBatchSource(typs, rCols, uint64(rLen)),
+						[]uint32{0, 1},
+						rightOutCols,
+						typs,
+						typs,
+						ordering,
+						ordering,
+						useKeyHints && leftIsKey,  /* leftEqColsAreKey */
+						useKeyHints && !leftIsKey, /* rightEqColsAreKey */
+						nil,                       /* filterConstructor */
+						false,                     /* filterOnlyOnLeft */
+					)
+					if err != nil {
+						t.Fatal(err)
+					}
+					op.Init()
+					mj := op.(interface {
+						bufferedGroupsLength() (uint64, uint64)
+					})
+					var rows []string
+					for b := op.Next(ctx); b.Length() != 0; b = op.Next(ctx) {
+						for i := 0; i < int(b.Length()); i++ {
+							row := make([]interface{}, b.Width())
+							for j := range row {
+								if !b.ColVec(j).Nulls().NullAt(uint16(i)) {
+									row[j] = b.ColVec(j).Int64()[i]
+								}
+							}
+							rows = append(rows, fmt.Sprint(row))
+						}
+						if !useKeyHints {
+							continue
+						}
+						lBuffered, rBuffered := mj.bufferedGroupsLength()
+						streamedBuffered := rBuffered
+						if streamedInput == mjStreamedInputLeft {
+							streamedBuffered = lBuffered
+						}
+						if streamedBuffered > 2*uint64(coldata.BatchSize()) {
+							t.Fatalf("%d tuples of the streamed input are buffered", streamedBuffered)
+						}
+					}
+					return rows
+				}
+
+				expected := run(false /* useKeyHints */)
+				actual := run(true /* useKeyHints */)
+				if joinType == sqlbase.JoinType_FULL_OUTER {
+					// FULL OUTER JOIN doesn't guarantee any ordering on its output.
+					sort.Strings(expected)
+					sort.Strings(actual)
+				}
+				if len(expected) != len(actual) {
+					t.Fatalf("expected %d rows, got %d", len(expected), len(actual))
+				}
+				for i := range expected {
+					if expected[i] != actual[i] {
+						t.Fatalf("row %d: expected %s, got %s", i, expected[i], actual[i])
+					}
+				}
+			})
+		}
+	}
+}
+
 func newBatchOfIntRows(nCols int, batch coldata.Batch) coldata.Batch {
 	for colIdx := 0; colIdx < nCols; colIdx++ {
 		col := batch.ColVec(colIdx).Int64()
@@ -2201,4 +2475,61 @@ func BenchmarkMergeJoiner(b *testing.B) {
 		})
 	}
 
+	// Unique values on the left side and groups spanning multiple batches on
+	// the right side, with and without the hint that the left equality columns
+	// form a key (which allows for the right groups to be streamed).
+	for _, groupBatches := range []int{4, 16, 64} {
+		for _, leftEqColsAreKey := range []bool{false, true} {
+			nGroups := 4
+			nTuples := groupBatches * int(coldata.BatchSize()) * nGroups
+			leftCols := make([]coldata.Vec, nCols)
+			rightCols := make([]coldata.Vec, nCols)
+			for colIdx := 0; colIdx < nCols; colIdx++ {
+				leftCols[colIdx] = testAllocator.NewMemColumn(coltypes.Int64, nGroups)
+				leftCol := leftCols[colIdx].Int64()
+				for i := range leftCol {
+					leftCol[i] = int64(i)
+				}
+				rightCols[colIdx] = testAllocator.NewMemColumn(coltypes.Int64, nTuples)
+				rightCol := rightCols[colIdx].Int64()
+				for i := range rightCol {
+					rightCol[i] = int64(i * nGroups / nTuples)
+				}
+			}
+			b.Run(fmt.Sprintf("leftKey=%t/rightGroupRows=%d", leftEqColsAreKey, nTuples/nGroups), func(b *testing.B) {
+				// 8 (bytes / int64) * nTuples (number of right rows) * nCols (number
+				// of columns / row).
+				b.SetBytes(int64(8 * nTuples * nCols))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					s, err := NewMergeJoinOp(
+						testAllocator,
+						sqlbase.InnerJoin,
+						newChunkingBatchSource(sourceTypes, leftCols, uint64(nGroups)),
+						newChunkingBatchSource(sourceTypes, rightCols, uint64(nTuples)),
+						[]uint32{0, 1},
+						[]uint32{2, 3},
+						sourceTypes,
+						sourceTypes,
+						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
+						[]execinfrapb.Ordering_Column{{ColIdx: 0, Direction: execinfrapb.Ordering_Column_ASC}},
+						leftEqColsAreKey,
+						false, /* rightEqColsAreKey */
+						nil,   /* filterConstructor */
+						false, /* filterOnlyOnLeft */
+					)
+					if err != nil {
+						b.Fatal(err)
+					}
+					s.Init()
+
+					b.StartTimer()
+					for b := s.Next(ctx); b.Length() != 0; b = s.Next(ctx) {
+					}
+					b.StopTimer()
+				}
+			})
+		}
+	}
 }
@@ -139,6 +139,12 @@ type InternalMemoryOperator interface {
 	InternalMemoryUsage() int
 }
 
+// IdempotentCloser is an object that releases resources on the first call to
+// IdempotentClose but does nothing for any subsequent call.
+type IdempotentCloser interface {
+	IdempotentClose(ctx context.Context) error
+}
+
 // resetter is an interface that operators can implement if they can be reset
 // either for reusing (to keep the already allocated memory) or during tests.
 type resetter interface {
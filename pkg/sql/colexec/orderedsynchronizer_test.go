@@ -18,6 +18,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -152,6 +154,51 @@ func TestOrderedSync(t *testing.T) {
 	}
 }
 
+func TestOrderedSyncDatumOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+
+	a, b, c, d := tree.NewDString("a"), tree.NewDString("b"), tree.NewDString("c"), tree.NewDString("d")
+	typs := []coltypes.T{coltypes.Datum, coltypes.Int64}
+	for _, tc := range []struct {
+		direction encoding.Direction
+		sources   []tuples
+		expected  tuples
+	}{
+		{
+			direction: encoding.Ascending,
+			sources: []tuples{
+				{{a, 0}, {c, 1}},
+				{{nil, 2}, {b, 3}},
+				{{d, 4}},
+			},
+			expected: tuples{{nil, 2}, {a, 0}, {b, 3}, {c, 1}, {d, 4}},
+		},
+		{
+			direction: encoding.Descending,
+			sources: []tuples{
+				{{c, 1}, {a, 0}},
+				{{b, 3}, {nil, 2}},
+				{{d, 4}},
+			},
+			expected: tuples{{d, 4}, {c, 1}, {b, 3}, {a, 0}, {nil, 2}},
+		},
+	} {
+		inputTypes := make([][]coltypes.T, len(tc.sources))
+		for i := range inputTypes {
+			inputTypes[i] = typs
+		}
+		runTestsWithTyps(t, tc.sources, inputTypes, tc.expected, orderedVerifier, func(inputs []Operator) (Operator, error) {
+			return NewOrderedSynchronizer(
+				testAllocator, evalCtx, inputs, typs,
+				sqlbase.ColumnOrdering{{ColIdx: 0, Direction: tc.direction}},
+			), nil
+		})
+	}
+}
+
 func TestOrderedSyncRandomInput(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	numInputs := 3
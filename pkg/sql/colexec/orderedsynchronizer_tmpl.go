@@ -33,7 +33,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	// */}}
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 )
 
@@ -46,6 +48,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // _GOTYPESLICE is the template Go type slice variable for this operator. It
 // will be replaced by the Go slice representation for each type in coltypes.T, for
 // example []int64 for coltypes.Int64.
@@ -61,7 +66,10 @@ const _TYPES_T = coltypes.Unhandled
 // stream of rows, ordered according to a set of columns. The rows in each input
 // stream are assumed to be ordered according to the same set of columns.
 type OrderedSynchronizer struct {
-	allocator   *Allocator
+	allocator *Allocator
+	// evalCtx is used to compare the values of the ordering columns of
+	// coltypes.Datum type.
+	evalCtx     *tree.EvalContext
 	inputs      []Operator
 	ordering    sqlbase.ColumnOrdering
 	columnTypes []coltypes.T
@@ -113,10 +121,15 @@ func (o *OrderedSynchronizer) Child(nth int, verbose bool) execinfra.OpNode {
 
 // NewOrderedSynchronizer creates a new OrderedSynchronizer.
 func NewOrderedSynchronizer(
-	allocator *Allocator, inputs []Operator, typs []coltypes.T, ordering sqlbase.ColumnOrdering,
+	allocator *Allocator,
+	evalCtx *tree.EvalContext,
+	inputs []Operator,
+	typs []coltypes.T,
+	ordering sqlbase.ColumnOrdering,
 ) *OrderedSynchronizer {
 	return &OrderedSynchronizer{
 		allocator:   allocator,
+		evalCtx:     evalCtx,
 		inputs:      inputs,
 		ordering:    ordering,
 		columnTypes: typs,
@@ -159,6 +172,8 @@ func (o *OrderedSynchronizer) Next(ctx context.Context) coldata.Batch {
 					o.outNulls[i].SetNull(outputIdx)
 				} else {
 					switch physType {
+					case coltypes.Datum:
+						o.output.ColVec(i).Datum()[outputIdx] = vec.Datum()[srcRowIdx]
 					// {{range .}}
 					case _TYPES_T:
 						srcCol := vec._TYPE()
@@ -218,6 +233,10 @@ func (o *OrderedSynchronizer) Init() {
 	o.comparators = make([]vecComparator, len(o.ordering))
 	for i := range o.ordering {
 		typ := o.columnTypes[o.ordering[i].ColIdx]
+		if typ == coltypes.Datum {
+			o.comparators[i] = newDatumVecComparator(o.evalCtx, len(o.inputs))
+			continue
+		}
 		o.comparators[i] = GetVecComparator(typ, len(o.inputs))
 	}
 }
@@ -235,8 +254,12 @@ func (o *OrderedSynchronizer) compareRow(batchIdx1 int, batchIdx2 int) int {
 	}
 	for i := range o.ordering {
 		info := o.ordering[i]
-		res := o.comparators[i].compare(batchIdx1, batchIdx2, valIdx1, valIdx2)
+		c := o.comparators[i]
+		res := c.compare(batchIdx1, batchIdx2, valIdx1, valIdx2)
 		if res != 0 {
+			if info.NullsReversed() && c.isNull(batchIdx1, valIdx1) != c.isNull(batchIdx2, valIdx2) {
+				res = -res
+			}
 			switch d := info.Direction; d {
 			case encoding.Ascending:
 				return res
@@ -13,10 +13,12 @@ package colexec
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/stretchr/testify/require"
 )
@@ -246,3 +248,24 @@ func TestIntComparison(t *testing.T) {
 	require.Equal(t, true, performGEInt64Int32(i, 2))
 	require.Equal(t, false, performGEInt64Int64(i, 3))
 }
+
+func TestDatetimeArithmetic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ts := time.Date(2020, time.January, 31, 12, 0, 0, 0, time.UTC)
+	month := duration.MakeDuration(0, 0, 1)
+	dayAndHalf := duration.MakeDuration(int64(12*time.Hour), 1, 0)
+
+	// Adding a month to the end of January results in the end of February.
+	require.Equal(t, time.Date(2020, time.February, 29, 12, 0, 0, 0, time.UTC), performPlusTimestampInterval(ts, month))
+	require.Equal(t, time.Date(2020, time.February, 29, 12, 0, 0, 0, time.UTC), performPlusIntervalTimestamp(month, ts))
+	require.Equal(t, time.Date(2020, time.January, 30, 0, 0, 0, 0, time.UTC), performMinusTimestampInterval(ts, dayAndHalf))
+	// The results are rounded to microseconds.
+	require.Equal(t, ts, performPlusTimestampInterval(ts, duration.MakeDuration(1, 0, 0)))
+
+	require.Equal(t, duration.MakeDuration(int64(36*time.Hour), 0, 0), performMinusTimestampTimestamp(ts, ts.Add(-36*time.Hour)))
+	require.Equal(t, duration.MakeDuration(int64(12*time.Hour), 1, 1), performPlusIntervalInterval(month, dayAndHalf))
+	require.Equal(t, duration.MakeDuration(int64(-12*time.Hour), -1, 1), performMinusIntervalInterval(month, dayAndHalf))
+
+	require.Equal(t, duration.MakeDuration(int64(-36*time.Hour), -3, 0), performMultIntervalInt16(dayAndHalf, -3))
+	require.Equal(t, duration.MakeDuration(0, 0, 3), performMultInt64Interval(3, month))
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// faultKind is a kind of failure injected by a faultInjectionOp.
+type faultKind int
+
+const (
+	// faultExpected is an expected error of the vectorized engine.
+	faultExpected faultKind = iota
+	// faultNonVectorized is an error that originated outside of the vectorized
+	// engine, e.g. in a builtin.
+	faultNonVectorized
+	// faultStorage is an error of the KV layer.
+	faultStorage
+	// faultInternal is an unexpected error of the vectorized engine.
+	faultInternal
+	// faultRuntime is a runtime error, i.e. a bug in the vectorized engine.
+	faultRuntime
+	numFaultKinds
+)
+
+// faultPoint is the method of a faultInjectionOp that fails.
+type faultPoint int
+
+const (
+	faultInInit faultPoint = iota
+	faultInNext
+	faultInDrainMeta
+	numFaultPoints
+)
+
+var errInjectedStorage = errors.New("injected storage error")
+
+// faultInjectionOp is an Operator that passes the batches of its input through
+// and panics the way the vectorized engine does at the given point.
+type faultInjectionOp struct {
+	OneInputNode
+	NonExplainable
+
+	kind  faultKind
+	point faultPoint
+	// failAfter is the number of batches returned before Next fails.
+	failAfter int
+	calls     int
+}
+
+var _ Operator = &faultInjectionOp{}
+var _ execinfrapb.MetadataSource = &faultInjectionOp{}
+
+func (f *faultInjectionOp) Init() {
+	f.input.Init()
+	if f.point == faultInInit {
+		f.fail()
+	}
+}
+
+func (f *faultInjectionOp) Next(ctx context.Context) coldata.Batch {
+	if f.point == faultInNext && f.calls == f.failAfter {
+		f.fail()
+	}
+	f.calls++
+	return f.input.Next(ctx)
+}
+
+func (f *faultInjectionOp) DrainMeta(context.Context) []execinfrapb.ProducerMetadata {
+	if f.point == faultInDrainMeta {
+		f.fail()
+	}
+	return nil
+}
+
+func (f *faultInjectionOp) fail() {
+	switch f.kind {
+	case faultExpected:
+		execerror.VectorizedExpectedInternalPanic(pgerror.New(pgcode.DivisionByZero, "injected"))
+	case faultNonVectorized:
+		execerror.NonVectorizedPanic(pgerror.New(pgcode.NumericValueOutOfRange, "injected"))
+	case faultStorage:
+		execerror.VectorizedInternalPanic(execerror.NewStorageError(errInjectedStorage))
+	case faultInternal:
+		execerror.VectorizedInternalPanic("injected")
+	case faultRuntime:
+		var s []int
+		_ = s[f.calls]
+	}
+}
+
+// checkErr checks that err is what the boundary of a flow returns for the
+// fault.
+func (f *faultInjectionOp) checkErr(t *testing.T, err error) {
+	t.Helper()
+	switch f.kind {
+	case faultExpected:
+		require.Equal(t, pgcode.DivisionByZero, pgerror.GetPGCode(err), "%+v", err)
+	case faultNonVectorized:
+		require.Equal(t, pgcode.NumericValueOutOfRange, pgerror.GetPGCode(err), "%+v", err)
+	case faultStorage:
+		_, ok := err.(*execerror.StorageError)
+		require.True(t, ok, "expected a StorageError, found %+v", err)
+	case faultInternal, faultRuntime:
+		require.True(t, errors.HasAssertionFailure(err), "%+v", err)
+		require.Equal(t, pgcode.Internal, pgerror.GetPGCode(err), "%+v", err)
+		require.Contains(t, errors.FlattenDetails(err), "faultInjectionOp", "%+v", err)
+	}
+}
+
+// TestPanicContainment injects faults at random points of random vectorized
+// flows and checks that no panic escapes the boundary of the flow (the
+// Materializer), and that the faults are returned as errors with the expected
+// classification.
+func TestPanicContainment(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		Cfg:     &execinfra.ServerConfig{Settings: st},
+		EvalCtx: &evalCtx,
+	}
+
+	const (
+		numRuns    = 100
+		maxBatches = 4
+		maxInputs  = 4
+	)
+	rng, _ := randutil.NewPseudoRand()
+	typs := []coltypes.T{coltypes.Int64}
+	newSource := func(numBatches int) Operator {
+		source := NewRepeatableBatchSource(RandomBatch(
+			testAllocator, rng, typs, int(coldata.BatchSize()), 0 /* length */, rng.Float64(),
+		))
+		source.ResetBatchesToReturn(numBatches)
+		return source
+	}
+
+	for run := 0; run < numRuns; run++ {
+		numBatches := rng.Intn(maxBatches) + 1
+		faulty := &faultInjectionOp{
+			OneInputNode: NewOneInputNode(newSource(numBatches)),
+			kind:         faultKind(rng.Intn(int(numFaultKinds))),
+			point:        faultPoint(rng.Intn(int(numFaultPoints))),
+			// The input returns numBatches batches and a zero-length batch.
+			failAfter: rng.Intn(numBatches + 1),
+		}
+		desc := fmt.Sprintf("kind=%d point=%d failAfter=%d", faulty.kind, faulty.point, faulty.failAfter)
+
+		// Stack operators that pass the failures through on top of the faulty
+		// one, possibly running it in its own goroutine.
+		var op Operator = faulty
+		var wg sync.WaitGroup
+		for done := false; !done; {
+			switch rng.Intn(4) {
+			case 0:
+				op = NewNoop(op)
+			case 1:
+				op = NewSimpleProjectOp(op, len(typs), []uint32{0})
+			case 2:
+				inputs := []Operator{op}
+				for i := rng.Intn(maxInputs); i > 0; i-- {
+					inputs = append(inputs, newSource(rng.Intn(maxBatches)+1))
+				}
+				rng.Shuffle(len(inputs), func(i, j int) { inputs[i], inputs[j] = inputs[j], inputs[i] })
+				op = NewParallelUnorderedSynchronizer(inputs, typs, &wg)
+				desc += " sync"
+			default:
+				done = true
+			}
+		}
+
+		m, err := NewMaterializer(
+			flowCtx,
+			1, /* processorID */
+			op,
+			[]types.T{*types.Int},
+			&execinfrapb.PostProcessSpec{},
+			nil, /* output */
+			[]execinfrapb.MetadataSource{faulty},
+			nil, /* outputStatsToTrace */
+			nil, /* cancelFlow */
+		)
+		require.NoError(t, err)
+
+		var errs []error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s: panic escaped the flow: %v", desc, r)
+				}
+			}()
+			m.Start(ctx)
+			for {
+				row, meta := m.Next()
+				if meta != nil && meta.Err != nil {
+					errs = append(errs, meta.Err)
+				}
+				if row == nil && meta == nil {
+					break
+				}
+			}
+		}()
+		wg.Wait()
+
+		require.Len(t, errs, 1, desc)
+		faulty.checkErr(t, errs[0])
+	}
+}
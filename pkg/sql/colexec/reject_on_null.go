@@ -0,0 +1,144 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// wrapMergeJoinInputsForRejectOnNull wraps the inputs of a LEFT ANTI merge
+// join so that the join gets the semantics of NOT IN on a single equality
+// column (see MergeJoinerSpec.RejectOnNull):
+// - if the right input has a NULL key, no left tuples are emitted,
+// - if the right input is empty, all left tuples are emitted,
+// - otherwise, the left tuples with a NULL key are not emitted.
+// The right input must be ordered ascendingly on its equality column, so that
+// its first tuple has a NULL key if any of its tuples does.
+func wrapMergeJoinInputsForRejectOnNull(
+	joinType sqlbase.JoinType,
+	left, right Operator,
+	leftOrdering, rightOrdering []execinfrapb.Ordering_Column,
+) (Operator, Operator, error) {
+	if joinType != sqlbase.JoinType_LEFT_ANTI || len(leftOrdering) != 1 {
+		return nil, nil, errors.Errorf(
+			"reject on null is only supported for LEFT ANTI joins on a single equality column",
+		)
+	}
+	if c := rightOrdering[0]; c.Direction != execinfrapb.Ordering_Column_ASC || c.NullsReversed() {
+		return nil, nil, errors.Errorf("reject on null requires an ascending ordering with NULLs first")
+	}
+	r := &rejectOnNullRightOp{
+		OneInputNode: NewOneInputNode(right),
+		eqCol:        int(rightOrdering[0].ColIdx),
+	}
+	l := &rejectOnNullLeftOp{
+		OneInputNode: NewOneInputNode(left),
+		right:        r,
+		notNull:      newIsNullSelOp(left, int(leftOrdering[0].ColIdx), true /* negate */),
+	}
+	return l, r, nil
+}
+
+// rejectOnNullRightOp is a pass-through Operator on the right input of a merge
+// join that rejects on NULL. It allows the left side to peek at the first
+// right batch before the merge joiner reads it.
+type rejectOnNullRightOp struct {
+	OneInputNode
+
+	eqCol int
+
+	peeked bool
+	// first is the first batch of the input. It is returned by the first call to
+	// Next, or by all the calls if it is empty.
+	first         coldata.Batch
+	returnedFirst bool
+}
+
+var _ Operator = &rejectOnNullRightOp{}
+
+func (r *rejectOnNullRightOp) Init() {
+	r.input.Init()
+}
+
+// peek reads the first batch of the input if it hasn't been read yet and
+// returns whether the input is empty and whether it has a NULL key.
+func (r *rejectOnNullRightOp) peek(ctx context.Context) (empty bool, hasNull bool) {
+	if !r.peeked {
+		r.first = r.input.Next(ctx)
+		r.peeked = true
+	}
+	n := r.first.Length()
+	if n == 0 {
+		return true, false
+	}
+	firstIdx := uint16(0)
+	if sel := r.first.Selection(); sel != nil {
+		firstIdx = sel[0]
+	}
+	nulls := r.first.ColVec(r.eqCol).Nulls()
+	return false, nulls.MaybeHasNulls() && nulls.NullAt(firstIdx)
+}
+
+func (r *rejectOnNullRightOp) Next(ctx context.Context) coldata.Batch {
+	if !r.peeked {
+		r.peek(ctx)
+	}
+	if !r.returnedFirst {
+		r.returnedFirst = true
+		return r.first
+	}
+	if r.first.Length() == 0 {
+		return r.first
+	}
+	return r.input.Next(ctx)
+}
+
+// rejectOnNullLeftOp is an Operator on the left input of a merge join that
+// rejects on NULL. It filters its input according to the first batch of the
+// right input.
+type rejectOnNullLeftOp struct {
+	OneInputNode
+
+	right *rejectOnNullRightOp
+	// notNull is an Operator on top of the input that discards the tuples with
+	// a NULL key.
+	notNull Operator
+
+	checked   bool
+	rejectAll bool
+	passAll   bool
+}
+
+var _ Operator = &rejectOnNullLeftOp{}
+
+func (l *rejectOnNullLeftOp) Init() {
+	// notNull initializes the input.
+	l.notNull.Init()
+}
+
+func (l *rejectOnNullLeftOp) Next(ctx context.Context) coldata.Batch {
+	if !l.checked {
+		l.checked = true
+		l.passAll, l.rejectAll = l.right.peek(ctx)
+	}
+	if l.rejectAll {
+		return coldata.ZeroBatch
+	}
+	if l.passAll {
+		return l.input.Next(ctx)
+	}
+	return l.notNull.Next(ctx)
+}
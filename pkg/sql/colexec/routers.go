@@ -11,8 +11,10 @@
 package colexec
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
@@ -20,7 +22,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
 )
 
 // routerOutput is an interface implemented by router outputs. It exists for
@@ -56,6 +61,8 @@ type routerOutputOp struct {
 
 	mu struct {
 		syncutil.Mutex
+		// allocator is used to allocate the buffered batches of this output only,
+		// so that the memory usage of each output is accounted for separately.
 		allocator *Allocator
 		cond      *sync.Cond
 		done      bool
@@ -63,6 +70,10 @@ type routerOutputOp struct {
 		data      []coldata.Batch
 		numUnread int
 		blocked   bool
+		// returnedMemSize is the memory footprint of the batch returned by the
+		// last call to Next. It is released on the following call to Next, once
+		// the reader is done with that batch.
+		returnedMemSize int64
 	}
 
 	// These fields default to defaultRouterOutputBlockedThreshold and
@@ -126,6 +137,8 @@ func (o *routerOutputOp) Init() {}
 func (o *routerOutputOp) Next(context.Context) coldata.Batch {
 	o.mu.Lock()
 	defer o.mu.Unlock()
+	o.mu.allocator.ReleaseMemory(o.mu.returnedMemSize)
+	o.mu.returnedMemSize = 0
 	if o.mu.done {
 		return coldata.ZeroBatch
 	}
@@ -146,6 +159,8 @@ func (o *routerOutputOp) Next(context.Context) coldata.Batch {
 		// This is the last batch. Set done to protect against further calls to
 		// Next since this is allowed by the interface.
 		o.mu.done = true
+	} else {
+		o.mu.returnedMemSize = getBatchMemSize(b)
 	}
 	return b
 }
@@ -158,6 +173,8 @@ func (o *routerOutputOp) cancel() {
 	o.mu.done = true
 	// Release o.mu.data to GC.
 	o.mu.data = nil
+	o.mu.allocator.ReleaseMemory(o.mu.allocator.Used())
+	o.mu.returnedMemSize = 0
 	// Some goroutine might be waiting on the condition variable, so wake it up.
 	// Note that read goroutines check o.mu.done, so won't wait on the condition
 	// variable after we unlock the mutex.
@@ -268,26 +285,24 @@ func (o *routerOutputOp) reset() {
 	o.mu.data = o.mu.data[:0]
 	o.mu.numUnread = 0
 	o.mu.blocked = false
+	o.mu.allocator.ReleaseMemory(o.mu.allocator.Used())
+	o.mu.returnedMemSize = 0
 	o.mu.Unlock()
 }
 
-// HashRouter hashes values according to provided hash columns and computes a
-// destination for each row. These destinations are exposed as Operators
-// returned by the constructor.
-type HashRouter struct {
+// routerBase contains the state shared by the vectorized routers. Routers read
+// batches from their input, compute the destination output of every row, and
+// push the rows to these outputs. If all outputs are blocked, the router waits
+// until at least one of them is unblocked by a read.
+type routerBase struct {
 	OneInputNode
 	// types are the input coltypes.
 	types []coltypes.T
-	// ht is not fully initialized to a hashTable, only the utility methods are
-	// used.
-	ht hashTable
-	// hashCols is a slice of indices of the columns used for hashing.
-	hashCols []int
 
 	// One output for each stream.
 	outputs []routerOutput
 
-	// unblockedEventsChan is a channel shared between the HashRouter and its
+	// unblockedEventsChan is a channel shared between the router and its
 	// outputs. outputs send events on this channel when they are unblocked by a
 	// read.
 	unblockedEventsChan <-chan struct{}
@@ -298,70 +313,56 @@ type HashRouter struct {
 		bufferedMeta []execinfrapb.ProducerMetadata
 	}
 
-	scratch struct {
-		// buckets is scratch space for the computed hash value of a group of columns
-		// with the same index in the current coldata.Batch.
-		buckets []uint64
-		// selections is scratch space for selection vectors used by router outputs.
-		selections [][]uint16
+	// selections is scratch space for selection vectors used by router
+	// outputs.
+	selections [][]uint16
+}
+
+func makeRouterBase(
+	input Operator, types []coltypes.T, unblockEventsChan <-chan struct{}, outputs []routerOutput,
+) routerBase {
+	r := routerBase{
+		OneInputNode:        NewOneInputNode(input),
+		types:               types,
+		outputs:             outputs,
+		unblockedEventsChan: unblockEventsChan,
+	}
+	r.selections = make([][]uint16, len(outputs))
+	for i := range r.selections {
+		r.selections[i] = make([]uint16, 0, coldata.BatchSize())
 	}
+	return r
 }
 
-// NewHashRouter creates a new hash router that consumes coldata.Batches from
-// input and hashes each row according to hashCols to one of numOutputs outputs.
-// These outputs are exposed as Operators.
-func NewHashRouter(
-	allocator *Allocator, input Operator, types []coltypes.T, hashCols []int, numOutputs int,
-) (*HashRouter, []Operator) {
-	outputs := make([]routerOutput, numOutputs)
-	outputsAsOps := make([]Operator, numOutputs)
+// newRouterOutputs creates an output for each of the given allocators. Each
+// output uses its own allocator, so the allocators should be bound to separate
+// memory accounts. The returned channel is the one that the outputs send
+// unblock events on.
+func newRouterOutputs(
+	allocators []*Allocator, types []coltypes.T,
+) ([]routerOutput, []Operator, chan struct{}) {
+	outputs := make([]routerOutput, len(allocators))
+	outputsAsOps := make([]Operator, len(allocators))
 	// unblockEventsChan is buffered to 2*numOutputs as we don't want the outputs
 	// writing to it to block.
 	// Unblock events only happen after a corresponding block event. Since these
 	// are state changes and are done under lock (including the output sending
 	// on the channel, which is why we want the channel to be buffered in the
-	// first place), every time the HashRouter blocks an output, it *must* read
-	// all unblock events preceding it since these *must* be on the channel.
-	unblockEventsChan := make(chan struct{}, 2*numOutputs)
-	for i := 0; i < numOutputs; i++ {
-		op := newRouterOutputOp(allocator, types, unblockEventsChan)
+	// first place), every time the router blocks an output, it *must* read all
+	// unblock events preceding it since these *must* be on the channel.
+	unblockEventsChan := make(chan struct{}, 2*len(allocators))
+	for i := range allocators {
+		op := newRouterOutputOp(allocators[i], types, unblockEventsChan)
 		outputs[i] = op
 		outputsAsOps[i] = op
 	}
-	router := newHashRouterWithOutputs(input, types, hashCols, unblockEventsChan, outputs)
-	for i := range outputs {
-		outputs[i].(*routerOutputOp).input = router
-	}
-	return router, outputsAsOps
+	return outputs, outputsAsOps, unblockEventsChan
 }
 
-func newHashRouterWithOutputs(
-	input Operator,
-	types []coltypes.T,
-	hashCols []int,
-	unblockEventsChan <-chan struct{},
-	outputs []routerOutput,
-) *HashRouter {
-	r := &HashRouter{
-		OneInputNode:        NewOneInputNode(input),
-		types:               types,
-		hashCols:            hashCols,
-		outputs:             outputs,
-		unblockedEventsChan: unblockEventsChan,
-	}
-	r.scratch.buckets = make([]uint64, coldata.BatchSize())
-	r.scratch.selections = make([][]uint16, len(outputs))
-	for i := range r.scratch.selections {
-		r.scratch.selections[i] = make([]uint16, 0, coldata.BatchSize())
-	}
-	return r
-}
-
-// Run runs the HashRouter. Batches are read from the input and pushed to an
-// output calculated by hashing columns. Cancel the given context to terminate
-// early.
-func (r *HashRouter) Run(ctx context.Context) {
-	r.input.Init()
+// run runs the router. processNextBatch is called to read the next batch from
+// the input and push it to the outputs, and returns whether the input is done.
+// Cancel the given context to terminate early.
+func (r *routerBase) run(ctx context.Context, processNextBatch func(context.Context) bool) {
 	cancelOutputs := func(err error) {
 		if err != nil {
 			r.mu.Lock()
@@ -372,9 +373,13 @@ func (r *HashRouter) Run(ctx context.Context) {
 			o.cancel()
 		}
 	}
+	if err := execerror.CatchVectorizedRuntimeError(r.input.Init); err != nil {
+		cancelOutputs(err)
+		return
+	}
 	var done bool
-	processNextBatch := func() {
-		done = r.processNextBatch(ctx)
+	processNextBatchFn := func() {
+		done = processNextBatch(ctx)
 	}
 	for {
 		// Check for cancellation.
@@ -408,7 +413,7 @@ func (r *HashRouter) Run(ctx context.Context) {
 			}
 		}
 
-		if err := execerror.CatchVectorizedRuntimeError(processNextBatch); err != nil {
+		if err := execerror.CatchVectorizedRuntimeError(processNextBatchFn); err != nil {
 			cancelOutputs(err)
 			return
 		}
@@ -420,6 +425,113 @@ func (r *HashRouter) Run(ctx context.Context) {
 	}
 }
 
+// resetSelections resets the selection vectors of all outputs.
+func (r *routerBase) resetSelections() {
+	for i := range r.selections {
+		r.selections[i] = r.selections[i][:0]
+	}
+}
+
+// pushSelections adds the rows of b specified by the selection vector of each
+// output to that output.
+func (r *routerBase) pushSelections(b coldata.Batch) {
+	for i, o := range r.outputs {
+		if o.addBatch(b, r.selections[i]) {
+			// This batch blocked the output.
+			r.numBlockedOutputs++
+		}
+	}
+}
+
+// pushEndOfData tells all outputs that there is no more data, given the
+// zero-length batch b returned by the input.
+func (r *routerBase) pushEndOfData(b coldata.Batch) {
+	for _, o := range r.outputs {
+		o.addBatch(b, nil)
+	}
+}
+
+// reset resets the router for a benchmark run.
+func (r *routerBase) reset() {
+	if i, ok := r.input.(resetter); ok {
+		i.reset()
+	}
+	r.numBlockedOutputs = 0
+	for moreToRead := true; moreToRead; {
+		select {
+		case <-r.unblockedEventsChan:
+		default:
+			moreToRead = false
+		}
+	}
+	for _, o := range r.outputs {
+		o.(resetter).reset()
+	}
+}
+
+// DrainMeta is part of the MetadataGenerator interface.
+func (r *routerBase) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	meta := r.mu.bufferedMeta
+	r.mu.bufferedMeta = r.mu.bufferedMeta[:0]
+	return meta
+}
+
+// HashRouter hashes values according to provided hash columns and computes a
+// destination for each row. These destinations are exposed as Operators
+// returned by the constructor.
+type HashRouter struct {
+	routerBase
+	// ht is not fully initialized to a hashTable, only the utility methods are
+	// used.
+	ht hashTable
+	// hashCols is a slice of indices of the columns used for hashing.
+	hashCols []int
+
+	scratch struct {
+		// buckets is scratch space for the computed hash value of a group of columns
+		// with the same index in the current coldata.Batch.
+		buckets []uint64
+	}
+}
+
+// NewHashRouter creates a new hash router that consumes coldata.Batches from
+// input and hashes each row according to hashCols to one of the outputs, one
+// for each of the given allocators. These outputs are exposed as Operators.
+func NewHashRouter(
+	allocators []*Allocator, input Operator, types []coltypes.T, hashCols []int,
+) (*HashRouter, []Operator) {
+	outputs, outputsAsOps, unblockEventsChan := newRouterOutputs(allocators, types)
+	router := newHashRouterWithOutputs(input, types, hashCols, unblockEventsChan, outputs)
+	for i := range outputs {
+		outputs[i].(*routerOutputOp).input = router
+	}
+	return router, outputsAsOps
+}
+
+func newHashRouterWithOutputs(
+	input Operator,
+	types []coltypes.T,
+	hashCols []int,
+	unblockEventsChan <-chan struct{},
+	outputs []routerOutput,
+) *HashRouter {
+	r := &HashRouter{
+		routerBase: makeRouterBase(input, types, unblockEventsChan, outputs),
+		hashCols:   hashCols,
+	}
+	r.scratch.buckets = make([]uint64, coldata.BatchSize())
+	return r
+}
+
+// Run runs the HashRouter. Batches are read from the input and pushed to an
+// output calculated by hashing columns. Cancel the given context to terminate
+// early.
+func (r *HashRouter) Run(ctx context.Context) {
+	r.run(ctx, r.processNextBatch)
+}
+
 // processNextBatch reads the next batch from its input, hashes it and adds
 // each column to its corresponding output, returning whether the input is
 // done.
@@ -429,9 +541,7 @@ func (r *HashRouter) processNextBatch(ctx context.Context) bool {
 	if b.Length() == 0 {
 		// Done. Push an empty batch to outputs to tell them the data is done as
 		// well.
-		for _, o := range r.outputs {
-			o.addBatch(b, nil)
-		}
+		r.pushEndOfData(b)
 		return true
 	}
 
@@ -439,10 +549,7 @@ func (r *HashRouter) processNextBatch(ctx context.Context) bool {
 		r.ht.rehash(ctx, r.scratch.buckets, i, r.types[i], b.ColVec(i), uint64(b.Length()), b.Selection())
 	}
 
-	// Reset selections.
-	for i := 0; i < len(r.outputs); i++ {
-		r.scratch.selections[i] = r.scratch.selections[i][:0]
-	}
+	r.resetSelections()
 
 	// finalizeHash has an assumption that bucketSize is a power of 2, so
 	// finalize the hash in our own way. While doing this, we will build a
@@ -452,47 +559,175 @@ func (r *HashRouter) processNextBatch(ctx context.Context) bool {
 		selection = selection[:b.Length()]
 		for i, selIdx := range selection {
 			outputIdx := r.scratch.buckets[i] % uint64(len(r.outputs))
-			r.scratch.selections[outputIdx] = append(r.scratch.selections[outputIdx], selIdx)
+			r.selections[outputIdx] = append(r.selections[outputIdx], selIdx)
 		}
 	} else {
 		for i, hash := range r.scratch.buckets[:b.Length()] {
 			outputIdx := hash % uint64(len(r.outputs))
-			r.scratch.selections[outputIdx] = append(r.scratch.selections[outputIdx], uint16(i))
+			r.selections[outputIdx] = append(r.selections[outputIdx], uint16(i))
 		}
 	}
 
-	for i, o := range r.outputs {
-		if o.addBatch(b, r.scratch.selections[i]) {
-			// This batch blocked the output.
-			r.numBlockedOutputs++
+	r.pushSelections(b)
+	return false
+}
+
+// RangeRouter encodes the values of the columns specified by the range router
+// spec into a key for each row, and sends the row to the output of the span
+// that contains that key. The outputs are exposed as Operators returned by the
+// constructor.
+type RangeRouter struct {
+	routerBase
+	// columnTypes are the semantic types of the input columns, needed to encode
+	// the keys.
+	columnTypes []types.T
+	encodings   []execinfrapb.OutputRouterSpec_RangeRouterSpec_ColumnEncoding
+	spans       []execinfrapb.OutputRouterSpec_RangeRouterSpec_Span
+	// defaultDest, if set, is the output to send any row not matching a span
+	// to. If not set and a non-matching row is encountered, an error is
+	// returned and the router is shut down.
+	defaultDest *int
+
+	da sqlbase.DatumAlloc
+	// key is scratch space for the encoded key of a row.
+	key []byte
+}
+
+// NewRangeRouter creates a new range router that consumes coldata.Batches
+// from input and sends each row to one of the outputs, one for each of the
+// given allocators, according to spec. These outputs are exposed as
+// Operators.
+func NewRangeRouter(
+	allocators []*Allocator,
+	input Operator,
+	typs []coltypes.T,
+	columnTypes []types.T,
+	spec execinfrapb.OutputRouterSpec_RangeRouterSpec,
+) (*RangeRouter, []Operator, error) {
+	outputs, outputsAsOps, unblockEventsChan := newRouterOutputs(allocators, typs)
+	router, err := newRangeRouterWithOutputs(input, typs, columnTypes, spec, unblockEventsChan, outputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range outputs {
+		outputs[i].(*routerOutputOp).input = router
+	}
+	return router, outputsAsOps, nil
+}
+
+func newRangeRouterWithOutputs(
+	input Operator,
+	typs []coltypes.T,
+	columnTypes []types.T,
+	spec execinfrapb.OutputRouterSpec_RangeRouterSpec,
+	unblockEventsChan <-chan struct{},
+	outputs []routerOutput,
+) (*RangeRouter, error) {
+	if len(spec.Encodings) == 0 {
+		return nil, errors.New("missing encodings")
+	}
+	var prevKey []byte
+	// Verify spans are sorted and non-overlapping.
+	for i, span := range spec.Spans {
+		if bytes.Compare(prevKey, span.Start) > 0 {
+			return nil, errors.Errorf("span %d not after previous span", i)
+		}
+		if int(span.Stream) >= len(outputs) {
+			return nil, errors.Errorf("span %d routes to invalid stream %d", i, span.Stream)
 		}
+		prevKey = span.End
 	}
-	return false
+	var defaultDest *int
+	if spec.DefaultDest != nil {
+		i := int(*spec.DefaultDest)
+		if i >= len(outputs) {
+			return nil, errors.Errorf("invalid default stream %d", i)
+		}
+		defaultDest = &i
+	}
+	return &RangeRouter{
+		routerBase:  makeRouterBase(input, typs, unblockEventsChan, outputs),
+		columnTypes: columnTypes,
+		encodings:   spec.Encodings,
+		spans:       spec.Spans,
+		defaultDest: defaultDest,
+	}, nil
 }
 
-// reset resets the HashRouter for a benchmark run.
-func (r *HashRouter) reset() {
-	if i, ok := r.input.(resetter); ok {
-		i.reset()
+// Run runs the RangeRouter. Batches are read from the input and pushed to the
+// outputs of the spans that contain the encoded keys of the rows. Cancel the
+// given context to terminate early.
+func (r *RangeRouter) Run(ctx context.Context) {
+	r.run(ctx, r.processNextBatch)
+}
+
+// processNextBatch reads the next batch from its input and adds each row to
+// the output computed from its key, returning whether the input is done.
+func (r *RangeRouter) processNextBatch(ctx context.Context) bool {
+	b := r.input.Next(ctx)
+	if b.Length() == 0 {
+		// Done. Push an empty batch to outputs to tell them the data is done as
+		// well.
+		r.pushEndOfData(b)
+		return true
 	}
-	r.numBlockedOutputs = 0
-	for moreToRead := true; moreToRead; {
-		select {
-		case <-r.unblockedEventsChan:
-		default:
-			moreToRead = false
+
+	r.resetSelections()
+	selection := b.Selection()
+	if selection != nil {
+		for _, selIdx := range selection[:b.Length()] {
+			outputIdx := r.computeDestination(b, selIdx)
+			r.selections[outputIdx] = append(r.selections[outputIdx], selIdx)
+		}
+	} else {
+		for i := uint16(0); i < b.Length(); i++ {
+			outputIdx := r.computeDestination(b, i)
+			r.selections[outputIdx] = append(r.selections[outputIdx], i)
 		}
 	}
-	for _, o := range r.outputs {
-		o.(resetter).reset()
+
+	r.pushSelections(b)
+	return false
+}
+
+// computeDestination returns the index of the output that the row at rowIdx of
+// b must be sent to.
+func (r *RangeRouter) computeDestination(b coldata.Batch, rowIdx uint16) int {
+	r.key = r.key[:0]
+	for _, enc := range r.encodings {
+		col := enc.Column
+		ct := &r.columnTypes[col]
+		d := sqlbase.DatumToEncDatum(ct, PhysicalTypeColElemToDatum(b.ColVec(int(col)), rowIdx, r.da, ct))
+		var err error
+		r.key, err = d.Encode(ct, &r.da, enc.Encoding, r.key)
+		if err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+	}
+	i := r.spanForKey(r.key)
+	if i == -1 {
+		if r.defaultDest == nil {
+			execerror.VectorizedExpectedInternalPanic(errors.New("no span found for key"))
+		}
+		return *r.defaultDest
 	}
+	return i
 }
 
-// DrainMeta is part of the MetadataGenerator interface.
-func (r *HashRouter) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	meta := r.mu.bufferedMeta
-	r.mu.bufferedMeta = r.mu.bufferedMeta[:0]
-	return meta
+// spanForKey returns the stream of the first span that key is within
+// [start, end). A -1 is returned if no such span is found.
+func (r *RangeRouter) spanForKey(key []byte) int {
+	i := sort.Search(len(r.spans), func(i int) bool {
+		return bytes.Compare(r.spans[i].End, key) > 0
+	})
+
+	// If we didn't find an i where key < end, there is no span.
+	if i == len(r.spans) {
+		return -1
+	}
+	// Make sure the Start is <= key.
+	if bytes.Compare(r.spans[i].Start, key) > 0 {
+		return -1
+	}
+	return int(r.spans[i].Stream)
 }
@@ -20,14 +20,36 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
+// newTestRouterAllocators returns numOutputs allocators bound to separate
+// memory accounts, since router outputs perform memory accounting
+// concurrently. The returned function closes the accounts.
+func newTestRouterAllocators(ctx context.Context, numOutputs int) ([]*Allocator, func()) {
+	allocators := make([]*Allocator, numOutputs)
+	accounts := make([]mon.BoundAccount, numOutputs)
+	for i := range allocators {
+		accounts[i] = testMemMonitor.MakeBoundAccount()
+		allocators[i] = NewAllocator(ctx, &accounts[i])
+	}
+	return allocators, func() {
+		for i := range accounts {
+			accounts[i].Close(ctx)
+		}
+	}
+}
+
 // getDataAndFullSelection is a test helper that generates tuples representing
 // a one-column coltypes.Int64 batch where each element is its ordinal and an
 // accompanying selection vector that selects every index in tuples.
@@ -629,7 +651,7 @@ func TestHashRouterOneOutput(t *testing.T) {
 	typs := []coltypes.T{coltypes.Int64}
 
 	r, routerOutputs := NewHashRouter(
-		testAllocator, newOpFixedSelTestInput(sel, uint16(len(sel)), data), typs, []int{0}, 1, /* numOutputs */
+		[]*Allocator{testAllocator}, newOpFixedSelTestInput(sel, uint16(len(sel)), data), typs, []int{0},
 	)
 
 	if len(routerOutputs) != 1 {
@@ -713,9 +735,11 @@ func TestHashRouterRandom(t *testing.T) {
 			unblockEventsChan := make(chan struct{}, 2*numOutputs)
 			outputs := make([]routerOutput, numOutputs)
 			outputsAsOps := make([]Operator, numOutputs)
+			allocators, closeAccounts := newTestRouterAllocators(ctx, numOutputs)
+			defer closeAccounts()
 			for i := range outputs {
 				op := newRouterOutputOpWithBlockedThresholdAndBatchSize(
-					testAllocator, typs, unblockEventsChan, blockedThreshold, outputSize,
+					allocators[i], typs, unblockEventsChan, blockedThreshold, outputSize,
 				)
 				outputs[i] = op
 				outputsAsOps[i] = op
@@ -788,6 +812,180 @@ func TestHashRouterRandom(t *testing.T) {
 	})
 }
 
+// rangeRouterTestSpec returns a range router spec that routes the rows of a
+// single coltypes.Int64 column with values in [0, 10) to the first output and
+// values in [10, 20) to the second output. Other values are routed to
+// defaultDest, if not nil.
+func rangeRouterTestSpec(defaultDest *int32) execinfrapb.OutputRouterSpec_RangeRouterSpec {
+	key := func(v int64) []byte {
+		return encoding.EncodeVarintAscending(nil, v)
+	}
+	return execinfrapb.OutputRouterSpec_RangeRouterSpec{
+		Spans: []execinfrapb.OutputRouterSpec_RangeRouterSpec_Span{
+			{Start: key(0), End: key(10), Stream: 0},
+			{Start: key(10), End: key(20), Stream: 1},
+		},
+		DefaultDest: defaultDest,
+		Encodings: []execinfrapb.OutputRouterSpec_RangeRouterSpec_ColumnEncoding{
+			{Column: 0, Encoding: sqlbase.DatumEncoding_ASCENDING_KEY},
+		},
+	}
+}
+
+func TestRangeRouterComputesDestination(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	const numVals = 30
+	data := make(tuples, numVals)
+	for i := range data {
+		data[i] = tuple{i}
+	}
+	defaultDest := int32(2)
+	spec := rangeRouterTestSpec(&defaultDest)
+
+	valsPushed := make([][]int64, 3)
+	outputs := make([]routerOutput, len(valsPushed))
+	for i := range outputs {
+		outputIdx := i
+		outputs[i] = callbackRouterOutput{
+			addBatchCb: func(batch coldata.Batch, sel []uint16) bool {
+				for _, j := range sel {
+					valsPushed[outputIdx] = append(valsPushed[outputIdx], batch.ColVec(0).Int64()[j])
+				}
+				return false
+			},
+			cancelCb: func() {
+				t.Fatalf(
+					"output %d canceled, outputs should not be canceled during normal operation", outputIdx,
+				)
+			},
+		}
+	}
+
+	in := newOpTestInput(coldata.BatchSize(), data, nil /* typs */)
+	in.Init()
+	r, err := newRangeRouterWithOutputs(
+		in, []coltypes.T{coltypes.Int64}, []types.T{*types.Int}, spec, nil /* ch */, outputs,
+	)
+	require.NoError(t, err)
+	for !r.processNextBatch(ctx) {
+	}
+
+	for outputIdx, vals := range valsPushed {
+		for _, v := range vals {
+			expectedIdx := 2
+			if v < 10 {
+				expectedIdx = 0
+			} else if v < 20 {
+				expectedIdx = 1
+			}
+			if outputIdx != expectedIdx {
+				t.Fatalf("value %d routed to output %d, expected %d", v, outputIdx, expectedIdx)
+			}
+		}
+	}
+	if total := len(valsPushed[0]) + len(valsPushed[1]) + len(valsPushed[2]); total != numVals {
+		t.Fatalf("expected %d values to be routed, found %d", numVals, total)
+	}
+}
+
+func TestRangeRouterErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	typs := []coltypes.T{coltypes.Int64}
+	columnTypes := []types.T{*types.Int}
+	newOutputs := func() []routerOutput {
+		outputs := make([]routerOutput, 2)
+		for i := range outputs {
+			outputs[i] = callbackRouterOutput{}
+		}
+		return outputs
+	}
+
+	t.Run("UnsortedSpans", func(t *testing.T) {
+		spec := rangeRouterTestSpec(nil /* defaultDest */)
+		spec.Spans[0], spec.Spans[1] = spec.Spans[1], spec.Spans[0]
+		_, err := newRangeRouterWithOutputs(nil /* input */, typs, columnTypes, spec, nil /* ch */, newOutputs())
+		require.True(t, testutils.IsError(err, "not after previous span"), err)
+	})
+
+	t.Run("MissingEncodings", func(t *testing.T) {
+		spec := rangeRouterTestSpec(nil /* defaultDest */)
+		spec.Encodings = nil
+		_, err := newRangeRouterWithOutputs(nil /* input */, typs, columnTypes, spec, nil /* ch */, newOutputs())
+		require.True(t, testutils.IsError(err, "missing encodings"), err)
+	})
+
+	t.Run("NoSpanForKey", func(t *testing.T) {
+		in := newOpTestInput(coldata.BatchSize(), tuples{{1}, {25}}, nil /* typs */)
+		in.Init()
+		r, err := newRangeRouterWithOutputs(
+			in, typs, columnTypes, rangeRouterTestSpec(nil /* defaultDest */), nil /* ch */, newOutputs(),
+		)
+		require.NoError(t, err)
+		err = execerror.CatchVectorizedRuntimeError(func() { r.processNextBatch(ctx) })
+		require.True(t, testutils.IsError(err, "no span found for key"), err)
+	})
+}
+
+func TestRangeRouterOutputs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	rng, _ := randutil.NewPseudoRand()
+	numVals := 1 + rng.Intn(int(coldata.BatchSize())*4)
+	data := make(tuples, numVals)
+	var expected [2]tuples
+	for i := range data {
+		v := rng.Intn(20)
+		data[i] = tuple{v}
+		expected[v/10] = append(expected[v/10], data[i])
+	}
+
+	allocators, closeAccounts := newTestRouterAllocators(ctx, len(expected))
+	defer closeAccounts()
+	r, outputs, err := NewRangeRouter(
+		allocators,
+		newOpTestInput(1+uint16(rng.Intn(int(coldata.BatchSize()))), data, nil /* typs */),
+		[]coltypes.T{coltypes.Int64},
+		[]types.T{*types.Int},
+		rangeRouterTestSpec(nil /* defaultDest */),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		r.Run(ctx)
+		wg.Done()
+	}()
+
+	errs := make([]error, len(outputs))
+	wg.Add(len(outputs))
+	for i := range outputs {
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = newOpTestOutput(outputs[i], expected[i]).Verify()
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	require.Empty(t, r.DrainMeta(ctx))
+	// All batches have been read, so the outputs should have released all of
+	// their memory.
+	for i, a := range allocators {
+		if used := a.Used(); used != 0 {
+			t.Fatalf("expected output %d to release all memory, found %d bytes", i, used)
+		}
+	}
+}
+
 func BenchmarkHashRouter(b *testing.B) {
 	defer leaktest.AfterTest(b)()
 	ctx := context.Background()
@@ -804,7 +1002,9 @@ func BenchmarkHashRouter(b *testing.B) {
 	for _, numOutputs := range []int{2, 4, 8, 16} {
 		for _, numInputBatches := range []int{2, 4, 8, 16} {
 			b.Run(fmt.Sprintf("numOutputs=%d/numInputBatches=%d", numOutputs, numInputBatches), func(b *testing.B) {
-				r, outputs := NewHashRouter(testAllocator, input, types, []int{0}, numOutputs)
+				allocators, closeAccounts := newTestRouterAllocators(ctx, numOutputs)
+				defer closeAccounts()
+				r, outputs := NewHashRouter(allocators, input, types, []int{0})
 				b.SetBytes(8 * int64(coldata.BatchSize()) * int64(numInputBatches))
 				// We expect distribution to not change. This is a sanity check that
 				// we're resetting properly.
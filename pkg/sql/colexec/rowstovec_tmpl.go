@@ -25,12 +25,14 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // {{/*
@@ -41,6 +43,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 const (
 	_FAMILY = types.Family(0)
 	_WIDTH  = int32(0)
@@ -97,6 +102,23 @@ func EncDatumRowsToColVec(
 	allocator.PerformOperation(
 		[]coldata.Vec{vec},
 		func() {
+			if typeconv.FromColumnType(columnType) == coltypes.Datum {
+				// The types without a native columnar representation are stored as
+				// tree.Datums, so no conversion is needed.
+				col := vec.Datum()
+				for i := range rows {
+					if err = rows[i][columnIdx].EnsureDecoded(columnType, alloc); err != nil {
+						return
+					}
+					datum := rows[i][columnIdx].Datum
+					if datum == tree.DNull {
+						vec.Nulls().SetNull(uint16(i))
+					} else {
+						col[i] = datum
+					}
+				}
+				return
+			}
 			switch columnType.Family() {
 			// {{range .}}
 			case _FAMILY:
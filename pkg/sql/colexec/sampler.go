@@ -0,0 +1,454 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// samplerSketch contains the specification and run-time state for each sketch.
+type samplerSketch struct {
+	spec     execinfrapb.SketchSpec
+	sketch   *hyperloglog.Sketch
+	numNulls int64
+	numRows  int64
+}
+
+type samplerState int
+
+const (
+	// samplerSampling is the state in which the sampler consumes its input,
+	// updating the sketches and the sample reservoir.
+	samplerSampling samplerState = iota
+	// samplerEmittingSamples is the state in which the sampler emits the
+	// sampled rows.
+	samplerEmittingSamples
+	// samplerEmittingSketches is the state in which the sampler emits a row for
+	// each of the sketches.
+	samplerEmittingSketches
+	// samplerDone is the state in which the sampler has emitted all of its
+	// output.
+	samplerDone
+)
+
+// The following constants are the same as the ones used by the sampler
+// processor to throttle the collection of automatic statistics.
+const (
+	// samplerThrottleInterval is the number of input rows after which the
+	// sampler considers throttling.
+	samplerThrottleInterval = 10000
+	// samplerMaxIdleSleepTime is the maximum amount of time the sampler sleeps
+	// for throttling at once.
+	samplerMaxIdleSleepTime = 10 * time.Second
+	// At samplerCPUUsageMinThrottle average CPU usage the sampler starts
+	// throttling, and at samplerCPUUsageMaxThrottle it reaches maximum
+	// throttling.
+	samplerCPUUsageMinThrottle = 0.25
+	samplerCPUUsageMaxThrottle = 0.75
+)
+
+// samplerOp is the columnar version of the sampler processor: it returns a
+// random sample of its input rows, chosen using reservoir sampling, as well as
+// the cardinality estimation sketches of the requested columns (see
+// SamplerSpec for more details). The sketches are computed over the columns of
+// the batches, and the input rows are converted into the row representation
+// only when they are added to the reservoir.
+//
+// The output schema is the same as the one of the sampler processor, so the
+// output can be consumed by the sample aggregator processor: the sampled rows,
+// which have NULLs in the sketch columns, are followed by one row per sketch,
+// which has NULLs in the input columns. The metadata about the progress and
+// whether the histogram collection has been disabled is returned by DrainMeta.
+type samplerOp struct {
+	OneInputNode
+
+	allocator       *Allocator
+	flowCtx         *execinfra.FlowCtx
+	inputTypes      []types.T
+	outputTypes     []types.T
+	maxFractionIdle float64
+
+	// memAcc is the account used by the sample reservoir. The histogram
+	// collection is disabled when it is exhausted.
+	memAcc   *mon.BoundAccount
+	sr       stats.SampleReservoir
+	sketches []samplerSketch
+	// sampleCols are the columns which are included in the sampled rows. The
+	// other columns of the sampled rows are NULL.
+	sampleCols util.FastIntSet
+
+	// Output column indices for special columns.
+	rankCol      int
+	sketchIdxCol int
+	numRowsCol   int
+	numNullsCol  int
+	sketchCol    int
+
+	state samplerState
+	rng   *rand.Rand
+	da    sqlbase.DatumAlloc
+	// row is the scratch row used to add the rows to the sample reservoir.
+	row    sqlbase.EncDatumRow
+	buf    []byte
+	intBuf [8]byte
+	// nullKey is the key encoding of NULL that is inserted into the sketches
+	// for the NULL values.
+	nullKey []byte
+
+	rowsProcessed     uint64
+	histogramDisabled bool
+	rowsSinceThrottle int
+	lastWakeupTime    time.Time
+
+	output           coldata.Batch
+	outputPhysTypes  []coltypes.T
+	outputConverters []func(tree.Datum) (interface{}, error)
+	// emitted is the number of sampled rows or sketches (depending on the
+	// state) that have already been emitted.
+	emitted int
+}
+
+var _ Operator = &samplerOp{}
+var _ execinfrapb.MetadataSource = &samplerOp{}
+
+// newSamplerOp returns a new samplerOp. The memory used by the sample
+// reservoir is accounted for by memAcc, which is expected to be limited.
+func newSamplerOp(
+	allocator *Allocator,
+	memAcc *mon.BoundAccount,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.SamplerSpec,
+	input Operator,
+	inputTypes []types.T,
+) (*samplerOp, error) {
+	for _, s := range spec.Sketches {
+		if s.SketchType != execinfrapb.SketchType_HLL_PLUS_PLUS_V1 {
+			return nil, errors.Errorf("unsupported sketch type %s", s.SketchType)
+		}
+		if len(s.Columns) != 1 {
+			return nil, unimplemented.NewWithIssue(34422, "multi-column statistics are not supported yet.")
+		}
+	}
+
+	s := &samplerOp{
+		OneInputNode:    NewOneInputNode(input),
+		allocator:       allocator,
+		flowCtx:         flowCtx,
+		inputTypes:      inputTypes,
+		maxFractionIdle: spec.MaxFractionIdle,
+		memAcc:          memAcc,
+		sketches:        make([]samplerSketch, len(spec.Sketches)),
+		row:             make(sqlbase.EncDatumRow, len(inputTypes)),
+		nullKey:         encoding.EncodeNullAscending(nil),
+	}
+	for i := range spec.Sketches {
+		s.sketches[i] = samplerSketch{
+			spec:   spec.Sketches[i],
+			sketch: hyperloglog.New14(),
+		}
+		if spec.Sketches[i].GenerateHistogram {
+			s.sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+		}
+	}
+	s.sr.Init(int(spec.SampleSize), inputTypes, memAcc, s.sampleCols)
+	for i := range s.row {
+		s.row[i] = sqlbase.DatumToEncDatum(&inputTypes[i], tree.DNull)
+	}
+
+	outputTypes := make([]types.T, 0, len(inputTypes)+5)
+	outputTypes = append(outputTypes, inputTypes...)
+	s.rankCol = len(outputTypes)
+	outputTypes = append(outputTypes, *types.Int)
+	s.sketchIdxCol = len(outputTypes)
+	outputTypes = append(outputTypes, *types.Int)
+	s.numRowsCol = len(outputTypes)
+	outputTypes = append(outputTypes, *types.Int)
+	s.numNullsCol = len(outputTypes)
+	outputTypes = append(outputTypes, *types.Int)
+	s.sketchCol = len(outputTypes)
+	outputTypes = append(outputTypes, *types.Bytes)
+	s.outputTypes = outputTypes
+
+	s.outputPhysTypes = typeconv.FromColumnTypes(outputTypes)
+	s.outputConverters = make([]func(tree.Datum) (interface{}, error), len(outputTypes))
+	for i := range outputTypes {
+		s.outputConverters[i] = typeconv.GetDatumToPhysicalFn(&outputTypes[i])
+	}
+	return s, nil
+}
+
+func (s *samplerOp) Init() {
+	s.input.Init()
+	s.rng, _ = randutil.NewPseudoRand()
+	s.output = s.allocator.NewMemBatch(s.outputPhysTypes)
+	s.lastWakeupTime = timeutil.Now()
+}
+
+func (s *samplerOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch s.state {
+		case samplerSampling:
+			batch := s.input.Next(ctx)
+			if batch.Length() == 0 {
+				s.state = samplerEmittingSamples
+				continue
+			}
+			s.sampleBatch(ctx, batch)
+		case samplerEmittingSamples:
+			samples := s.sr.Get()
+			if s.emitted == len(samples) {
+				// Release the memory for the sampled rows.
+				s.sr = stats.SampleReservoir{}
+				s.state = samplerEmittingSketches
+				s.emitted = 0
+				continue
+			}
+			return s.emitSamples(samples)
+		case samplerEmittingSketches:
+			if s.emitted == len(s.sketches) {
+				s.state = samplerDone
+				continue
+			}
+			return s.emitSketches()
+		case samplerDone:
+			return coldata.ZeroBatch
+		default:
+			execerror.VectorizedInternalPanic("unexpected samplerState")
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// sampleBatch updates the sketches with the values of the batch and adds its
+// rows to the sample reservoir.
+func (s *samplerOp) sampleBatch(ctx context.Context, batch coldata.Batch) {
+	n := batch.Length()
+	sel := batch.Selection()
+	for i := range s.sketches {
+		s.updateSketch(&s.sketches[i], batch.ColVec(int(s.sketches[i].spec.Columns[0])), n, sel)
+	}
+	for i := uint16(0); i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		// Use Int63 so we don't have headaches converting to DInt.
+		rank := uint64(s.rng.Int63())
+		if !s.sr.WouldSample(rank) {
+			continue
+		}
+		for colIdx, ok := s.sampleCols.Next(0); ok; colIdx, ok = s.sampleCols.Next(colIdx + 1) {
+			s.row[colIdx] = sqlbase.DatumToEncDatum(
+				&s.inputTypes[colIdx],
+				PhysicalTypeColElemToDatum(batch.ColVec(colIdx), rowIdx, s.da, &s.inputTypes[colIdx]),
+			)
+		}
+		if err := s.sr.SampleRow(ctx, s.flowCtx.EvalCtx, s.row, rank); err != nil {
+			if code := pgerror.GetPGCode(err); code != pgcode.OutOfMemory {
+				execerror.NonVectorizedPanic(err)
+			}
+			// We hit an out of memory error. Clear the sample reservoir and
+			// disable histogram sample collection. The sample aggregator will be
+			// notified via the metadata.
+			s.sr.Disable()
+			s.histogramDisabled = true
+			log.Info(ctx, "disabling histogram collection due to excessive memory utilization")
+		}
+	}
+	s.rowsProcessed += uint64(n)
+	s.rowsSinceThrottle += int(n)
+	if s.rowsSinceThrottle >= samplerThrottleInterval {
+		s.rowsSinceThrottle = 0
+		s.maybeThrottle(ctx)
+	}
+}
+
+// updateSketch inserts the values of vec into the sketch. The values are
+// encoded in the same way as in the sampler processor, so the sketches
+// produced by both can be merged.
+func (s *samplerOp) updateSketch(sk *samplerSketch, vec coldata.Vec, n uint16, sel []uint16) {
+	colIdx := int(sk.spec.Columns[0])
+	typ := &s.inputTypes[colIdx]
+	isInt := typ.Family() == types.IntFamily
+	nulls := vec.Nulls()
+	maybeHasNulls := vec.MaybeHasNulls()
+	sk.numRows += int64(n)
+	for i := uint16(0); i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		if maybeHasNulls && nulls.NullAt(rowIdx) {
+			sk.numNulls++
+			sk.sketch.Insert(s.nullKey)
+			continue
+		}
+		if isInt {
+			// Fast path for integers, see the comment in the sampler processor.
+			var val int64
+			switch vec.Type() {
+			case coltypes.Int16:
+				val = int64(vec.Int16()[rowIdx])
+			case coltypes.Int32:
+				val = int64(vec.Int32()[rowIdx])
+			default:
+				val = vec.Int64()[rowIdx]
+			}
+			binary.LittleEndian.PutUint64(s.intBuf[:], uint64(val))
+			sk.sketch.Insert(s.intBuf[:])
+			continue
+		}
+		// We need to use a KEY encoding because equal values should have the
+		// same encoding.
+		var err error
+		s.buf, err = sqlbase.EncodeTableKey(
+			s.buf[:0], PhysicalTypeColElemToDatum(vec, rowIdx, s.da, typ), encoding.Ascending,
+		)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		sk.sketch.Insert(s.buf)
+	}
+}
+
+// maybeThrottle sleeps for some time if the sampler is allowed to be idle and
+// the CPU usage is high, in the same way as the sampler processor does.
+func (s *samplerOp) maybeThrottle(ctx context.Context) {
+	if s.maxFractionIdle <= 0 {
+		return
+	}
+	usage := s.flowCtx.Cfg.RuntimeStats.GetCPUCombinedPercentNorm()
+	if usage > samplerCPUUsageMinThrottle {
+		fractionIdle := s.maxFractionIdle
+		if usage < samplerCPUUsageMaxThrottle {
+			fractionIdle *= (usage - samplerCPUUsageMinThrottle) /
+				(samplerCPUUsageMaxThrottle - samplerCPUUsageMinThrottle)
+		}
+		if log.V(1) {
+			log.Infof(
+				ctx, "throttling to fraction idle %.2f (based on usage %.2f)", fractionIdle, usage,
+			)
+		}
+		elapsed := timeutil.Now().Sub(s.lastWakeupTime)
+		wait := time.Duration(float64(elapsed) * fractionIdle / (1 - fractionIdle))
+		if wait > samplerMaxIdleSleepTime {
+			wait = samplerMaxIdleSleepTime
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.flowCtx.Stopper().ShouldStop():
+		case <-ctx.Done():
+		}
+		timer.Stop()
+	}
+	s.lastWakeupTime = timeutil.Now()
+}
+
+// setDatum writes d into the output column colIdx at position rowIdx.
+func (s *samplerOp) setDatum(colIdx int, rowIdx uint16, d tree.Datum) {
+	vec := s.output.ColVec(colIdx)
+	if d == tree.DNull {
+		vec.Nulls().SetNull(rowIdx)
+		return
+	}
+	converted, err := s.outputConverters[colIdx](d)
+	if err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	coldata.SetValueAt(vec, converted, rowIdx, s.outputPhysTypes[colIdx])
+}
+
+// emitSamples returns the next batch of the sampled rows.
+func (s *samplerOp) emitSamples(samples []stats.SampledRow) coldata.Batch {
+	s.output.ResetInternalBatch()
+	n := uint16(0)
+	s.allocator.PerformOperation(s.output.ColVecs(), func() {
+		for ; n < coldata.BatchSize() && s.emitted < len(samples); n++ {
+			sample := &samples[s.emitted]
+			for colIdx := range s.inputTypes {
+				if err := sample.Row[colIdx].EnsureDecoded(&s.inputTypes[colIdx], &s.da); err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				s.setDatum(colIdx, n, sample.Row[colIdx].Datum)
+			}
+			s.setDatum(s.rankCol, n, tree.NewDInt(tree.DInt(sample.Rank)))
+			for _, colIdx := range []int{s.sketchIdxCol, s.numRowsCol, s.numNullsCol, s.sketchCol} {
+				s.output.ColVec(colIdx).Nulls().SetNull(n)
+			}
+			s.emitted++
+		}
+	})
+	s.output.SetLength(n)
+	return s.output
+}
+
+// emitSketches returns the next batch of the sketch rows.
+func (s *samplerOp) emitSketches() coldata.Batch {
+	s.output.ResetInternalBatch()
+	n := uint16(0)
+	s.allocator.PerformOperation(s.output.ColVecs(), func() {
+		for ; n < coldata.BatchSize() && s.emitted < len(s.sketches); n++ {
+			sk := &s.sketches[s.emitted]
+			for colIdx := range s.inputTypes {
+				s.output.ColVec(colIdx).Nulls().SetNull(n)
+			}
+			s.output.ColVec(s.rankCol).Nulls().SetNull(n)
+			s.setDatum(s.sketchIdxCol, n, tree.NewDInt(tree.DInt(s.emitted)))
+			s.setDatum(s.numRowsCol, n, tree.NewDInt(tree.DInt(sk.numRows)))
+			s.setDatum(s.numNullsCol, n, tree.NewDInt(tree.DInt(sk.numNulls)))
+			data, err := sk.sketch.MarshalBinary()
+			if err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			s.setDatum(s.sketchCol, n, tree.NewDBytes(tree.DBytes(data)))
+			s.emitted++
+		}
+	})
+	s.output.SetLength(n)
+	return s.output
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (s *samplerOp) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	return []execinfrapb.ProducerMetadata{{
+		SamplerProgress: &execinfrapb.RemoteProducerMetadata_SamplerProgress{
+			RowsProcessed:     s.rowsProcessed,
+			HistogramDisabled: s.histogramDisabled,
+		},
+	}}
+}
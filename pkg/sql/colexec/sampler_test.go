@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplerOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	typs := []types.T{*types.Int, *types.String}
+	physTypes := typeconv.FromColumnTypes(typs)
+	input := tuples{
+		{1, "a"},
+		{2, "b"},
+		{nil, "a"},
+		{3, nil},
+		{1, "c"},
+		{4, "b"},
+		{nil, nil},
+	}
+	spec := &execinfrapb.SamplerSpec{
+		SampleSize: 3,
+		Sketches: []execinfrapb.SketchSpec{
+			{
+				SketchType:        execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
+				Columns:           []uint32{0},
+				GenerateHistogram: true,
+			},
+			{
+				SketchType: execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
+				Columns:    []uint32{1},
+			},
+		},
+	}
+	// The distinct counts include NULL.
+	expectedDistinct := []uint64{5, 4}
+
+	// A monitor which is too small to hold any sampled rows.
+	limitedMon := mon.MakeMonitorWithLimit(
+		"test-limited", mon.MemoryResource, 1 /* limit */, nil /* curCount */, nil, /* maxHist */
+		1 /* increment */, math.MaxInt64 /* noteworthy */, st,
+	)
+	limitedMon.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer limitedMon.Stop(ctx)
+
+	for _, batchSize := range []uint16{1, 3, coldata.BatchSize()} {
+		for _, outOfMemory := range []bool{false, true} {
+			t.Run(fmt.Sprintf("batchSize=%d/outOfMemory=%t", batchSize, outOfMemory), func(t *testing.T) {
+				memAcc := testMemAcc
+				if outOfMemory {
+					limitedAcc := limitedMon.MakeBoundAccount()
+					defer limitedAcc.Close(ctx)
+					memAcc = &limitedAcc
+				}
+				sampler, err := newSamplerOp(
+					testAllocator, memAcc, flowCtx, spec, newOpTestInput(batchSize, input, physTypes), typs,
+				)
+				require.NoError(t, err)
+				sampler.Init()
+
+				numSamples, numSketches := 0, 0
+				for b := sampler.Next(ctx); b.Length() != 0; b = sampler.Next(ctx) {
+					for i := uint16(0); i < b.Length(); i++ {
+						if b.ColVec(sampler.sketchIdxCol).Nulls().NullAt(i) {
+							// This is a sampled row. The second column isn't sampled
+							// because no histogram is requested for it.
+							require.Zero(t, numSketches, "sampled row after the sketches")
+							require.False(t, b.ColVec(sampler.rankCol).Nulls().NullAt(i))
+							require.True(t, b.ColVec(1).Nulls().NullAt(i))
+							numSamples++
+							continue
+						}
+						// This is a sketch row.
+						require.True(t, b.ColVec(0).Nulls().NullAt(i))
+						require.True(t, b.ColVec(sampler.rankCol).Nulls().NullAt(i))
+						sketchIdx := b.ColVec(sampler.sketchIdxCol).Int64()[i]
+						require.Equal(t, int64(numSketches), sketchIdx)
+						require.Equal(t, int64(len(input)), b.ColVec(sampler.numRowsCol).Int64()[i])
+						require.Equal(t, int64(2), b.ColVec(sampler.numNullsCol).Int64()[i])
+						var sketch hyperloglog.Sketch
+						require.NoError(t, sketch.UnmarshalBinary(b.ColVec(sampler.sketchCol).Bytes().Get(int(i))))
+						require.Equal(t, expectedDistinct[sketchIdx], sketch.Estimate())
+						numSketches++
+					}
+				}
+				require.Equal(t, len(spec.Sketches), numSketches)
+
+				meta := sampler.DrainMeta(ctx)
+				require.Len(t, meta, 1)
+				require.Equal(t, uint64(len(input)), meta[0].SamplerProgress.RowsProcessed)
+				require.Equal(t, outOfMemory, meta[0].SamplerProgress.HistogramDisabled)
+				if outOfMemory {
+					require.Zero(t, numSamples)
+				} else {
+					require.Equal(t, int(spec.SampleSize), numSamples)
+				}
+			})
+		}
+	}
+}
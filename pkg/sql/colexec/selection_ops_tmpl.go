@@ -27,6 +27,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colsimd"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	// {{/*
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/pkg/errors"
 )
 
@@ -56,6 +58,9 @@ var _ = math.MaxInt64
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // Dummy import to pull in "coltypes" package.
 var _ = coltypes.Bool
 
@@ -92,6 +97,11 @@ func _SEL_CONST_LOOP(_HAS_NULLS bool) { // */}}
 		batch.SetSelection(true)
 		sel := batch.Selection()
 		col = execgen.SLICE(col, 0, int(n))
+		// {{if and (not _HAS_NULLS) .SIMDType}}
+		// The column has no nulls, so the selection vector can be computed by
+		// the data-parallel kernel.
+		idx = uint16(colsimd.Select_SIMD_TYPEConst(colsimd._NAME, col, p.constArg, sel))
+		// {{else}}
 		for execgen.RANGE(i, col, 0, int(n)) {
 			var cmp bool
 			arg := execgen.UNSAFEGET(col, i)
@@ -106,6 +116,7 @@ func _SEL_CONST_LOOP(_HAS_NULLS bool) { // */}}
 				idx++
 			}
 		}
+		// {{end}}
 	}
 	// {{end}}
 	// {{end}}
@@ -174,23 +185,27 @@ func (p *_OP_CONST_NAME) Next(ctx context.Context) coldata.Batch {
 			return batch
 		}
 
-		vec := batch.ColVec(p.colIdx)
-		col := vec._L_TYP()
-		var idx uint16
-		n := batch.Length()
-		if vec.MaybeHasNulls() {
-			nulls := vec.Nulls()
-			_SEL_CONST_LOOP(true)
-		} else {
-			_SEL_CONST_LOOP(false)
-		}
-		if idx > 0 {
+		if idx := p.selectRows(batch); idx > 0 {
 			batch.SetLength(idx)
 			return batch
 		}
 	}
 }
 
+func (p *_OP_CONST_NAME) selectRows(batch coldata.Batch) uint16 {
+	vec := batch.ColVec(p.colIdx)
+	col := vec._L_TYP()
+	var idx uint16
+	n := batch.Length()
+	if vec.MaybeHasNulls() {
+		nulls := vec.Nulls()
+		_SEL_CONST_LOOP(true)
+	} else {
+		_SEL_CONST_LOOP(false)
+	}
+	return idx
+}
+
 func (p *_OP_CONST_NAME) Init() {
 	p.input.Init()
 }
@@ -209,26 +224,30 @@ func (p *_OP_NAME) Next(ctx context.Context) coldata.Batch {
 			return batch
 		}
 
-		vec1 := batch.ColVec(p.col1Idx)
-		vec2 := batch.ColVec(p.col2Idx)
-		col1 := vec1._L_TYP()
-		col2 := vec2._R_TYP()
-		n := batch.Length()
-
-		var idx uint16
-		if vec1.MaybeHasNulls() || vec2.MaybeHasNulls() {
-			nulls := vec1.Nulls().Or(vec2.Nulls())
-			_SEL_LOOP(true)
-		} else {
-			_SEL_LOOP(false)
-		}
-		if idx > 0 {
+		if idx := p.selectRows(batch); idx > 0 {
 			batch.SetLength(idx)
 			return batch
 		}
 	}
 }
 
+func (p *_OP_NAME) selectRows(batch coldata.Batch) uint16 {
+	vec1 := batch.ColVec(p.col1Idx)
+	vec2 := batch.ColVec(p.col2Idx)
+	col1 := vec1._L_TYP()
+	col2 := vec2._R_TYP()
+	n := batch.Length()
+
+	var idx uint16
+	if vec1.MaybeHasNulls() || vec2.MaybeHasNulls() {
+		nulls := vec1.Nulls().Or(vec2.Nulls())
+		_SEL_LOOP(true)
+	} else {
+		_SEL_LOOP(false)
+	}
+	return idx
+}
+
 func (p *_OP_NAME) Init() {
 	p.input.Init()
 }
@@ -308,7 +308,8 @@ func (p *sortOp) sort(ctx context.Context) {
 
 	for i := range p.orderingCols {
 		inputVec := p.input.getValues(int(p.orderingCols[i].ColIdx))
-		p.sorters[i] = newSingleSorter(p.inputTypes[p.orderingCols[i].ColIdx], p.orderingCols[i].Direction, inputVec.MaybeHasNulls())
+		ord := p.orderingCols[i]
+		p.sorters[i] = newSingleSorter(p.inputTypes[ord.ColIdx], ord.Direction, inputVec.MaybeHasNulls(), ord.NullsReversed())
 		p.sorters[i].init(inputVec, p.order)
 	}
 
@@ -24,13 +24,15 @@ import (
 // NewSortChunks returns a new sort chunks operator, which sorts its input on
 // the columns given in orderingCols. The inputTypes must correspond 1-1 with
 // the columns in the input operator. The input tuples must be sorted on first
-// matchLen columns.
+// matchLen columns. If limit is non-zero, the operator stops reading its input
+// once it has produced limit tuples.
 func NewSortChunks(
 	allocator *Allocator,
 	input Operator,
 	inputTypes []coltypes.T,
 	orderingCols []execinfrapb.Ordering_Column,
 	matchLen int,
+	limit uint64,
 ) (Operator, error) {
 	if matchLen == len(orderingCols) {
 		// input is already ordered on all orderingCols, so there is nothing more
@@ -50,12 +52,18 @@ func NewSortChunks(
 	if err != nil {
 		return nil, err
 	}
-	return &sortChunksOp{input: chunker, sorter: sorter}, nil
+	return &sortChunksOp{input: chunker, sorter: sorter, limit: limit}, nil
 }
 
 type sortChunksOp struct {
 	input  *chunker
 	sorter resettableOperator
+
+	// limit is the number of tuples the operator needs to produce, or 0 if
+	// there is no limit.
+	limit uint64
+	// emitted is the number of tuples produced so far.
+	emitted uint64
 }
 
 func (c *sortChunksOp) ChildCount(verbose bool) int {
@@ -79,6 +87,11 @@ func (c *sortChunksOp) Init() {
 }
 
 func (c *sortChunksOp) Next(ctx context.Context) coldata.Batch {
+	if c.limit != 0 && c.emitted >= c.limit {
+		// The chunks produced so far contain all the tuples we need, so there is
+		// no need to read the rest of the input.
+		return coldata.ZeroBatch
+	}
 	for {
 		batch := c.sorter.Next(ctx)
 		if batch.Length() == 0 {
@@ -93,6 +106,7 @@ func (c *sortChunksOp) Next(ctx context.Context) coldata.Batch {
 			c.input.emptyBuffer()
 			c.sorter.reset()
 		} else {
+			c.emitted += uint64(batch.Length())
 			return batch
 		}
 	}
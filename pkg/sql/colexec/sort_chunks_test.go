@@ -193,11 +193,38 @@ func TestSortChunks(t *testing.T) {
 	}
 	for _, tc := range tcs {
 		runTests(t, []tuples{tc.tuples}, tc.expected, orderedVerifier, func(input []Operator) (Operator, error) {
-			return NewSortChunks(testAllocator, input[0], tc.typ, tc.ordCols, tc.matchLen)
+			return NewSortChunks(testAllocator, input[0], tc.typ, tc.ordCols, tc.matchLen, 0 /* limit */)
 		})
 	}
 }
 
+// TestSortChunksLimit verifies that the sort chunks operator stops reading its
+// input once it has produced the tuples needed to satisfy its limit.
+func TestSortChunksLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	typs := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	input := newOpTestInput(1 /* batchSize */, tuples{
+		{1, 2}, {1, 1}, {2, 3}, {3, 1}, {3, 0}, {4, 4}, {5, 5},
+	}, typs)
+	op, err := NewSortChunks(
+		testAllocator, input, typs,
+		[]execinfrapb.Ordering_Column{{ColIdx: 0}, {ColIdx: 1}}, 1 /* matchLen */, 3, /* limit */
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := newOpTestOutput(op, tuples{{1, 1}, {1, 2}, {2, 3}})
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	// The chunker reads the first tuple of the fourth chunk to find the end of
+	// the third one, but not the following tuples.
+	if len(input.tuples) != 3 {
+		t.Fatalf("expected 3 tuples left in the input, found %d", len(input.tuples))
+	}
+}
+
 func TestSortChunksRandomized(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	rng, _ := randutil.NewPseudoRand()
@@ -234,7 +261,7 @@ func TestSortChunksRandomized(t *testing.T) {
 				sort.Slice(expected, less(expected, ordCols))
 
 				runTests(t, []tuples{sortedTups}, expected, orderedVerifier, func(input []Operator) (Operator, error) {
-					return NewSortChunks(testAllocator, input[0], typs[:nCols], ordCols, matchLen)
+					return NewSortChunks(testAllocator, input[0], typs[:nCols], ordCols, matchLen, 0 /* limit */)
 				})
 			}
 		}
@@ -246,7 +273,9 @@ func BenchmarkSortChunks(b *testing.B) {
 	ctx := context.Background()
 
 	sorterConstructors := []func(*Allocator, Operator, []coltypes.T, []execinfrapb.Ordering_Column, int) (Operator, error){
-		NewSortChunks,
+		func(allocator *Allocator, input Operator, inputTypes []coltypes.T, orderingCols []execinfrapb.Ordering_Column, matchLen int) (Operator, error) {
+			return NewSortChunks(allocator, input, inputTypes, orderingCols, matchLen, 0 /* limit */)
+		},
 		func(allocator *Allocator, input Operator, inputTypes []coltypes.T, orderingCols []execinfrapb.Ordering_Column, _ int) (Operator, error) {
 			return NewSorter(allocator, input, inputTypes, orderingCols)
 		},
@@ -59,6 +59,15 @@ func TestSort(t *testing.T) {
 			typ:      []coltypes.T{coltypes.Int64, coltypes.Int64},
 			ordCols:  []execinfrapb.Ordering_Column{{ColIdx: 0}, {ColIdx: 1}},
 		},
+		{
+			tuples:   tuples{{nil, nil}, {nil, 3}, {1, nil}, {nil, 1}, {1, 2}, {nil, nil}, {5, nil}},
+			expected: tuples{{1, nil}, {1, 2}, {5, nil}, {nil, nil}, {nil, nil}, {nil, 3}, {nil, 1}},
+			typ:      []coltypes.T{coltypes.Int64, coltypes.Int64},
+			ordCols: []execinfrapb.Ordering_Column{
+				{ColIdx: 0, NullsOrder: execinfrapb.Ordering_Column_NULLS_LAST},
+				{ColIdx: 1, Direction: execinfrapb.Ordering_Column_DESC, NullsOrder: execinfrapb.Ordering_Column_NULLS_FIRST},
+			},
+		},
 		{
 			tuples:   tuples{{1}, {2}, {3}, {4}, {5}, {6}, {7}},
 			expected: tuples{{1}, {2}, {3}, {4}, {5}, {6}, {7}},
@@ -361,22 +370,11 @@ func less(tuples tuples, ordCols []execinfrapb.Ordering_Column) func(i, j int) b
 		for _, col := range ordCols {
 			n1 := tuples[i][col.ColIdx] == nil
 			n2 := tuples[j][col.ColIdx] == nil
-			if col.Direction == execinfrapb.Ordering_Column_ASC {
-				if n1 && n2 {
-					continue
-				} else if n1 {
-					return true
-				} else if n2 {
-					return false
-				}
-			} else {
-				if n1 && n2 {
-					continue
-				} else if n1 {
-					return false
-				} else if n2 {
-					return true
-				}
+			if n1 && n2 {
+				continue
+			} else if n1 || n2 {
+				nullsFirst := (col.Direction == execinfrapb.Ordering_Column_ASC) != col.NullsReversed()
+				return n1 == nullsFirst
 			}
 			if tuples[i][col.ColIdx].(int64) < tuples[j][col.ColIdx].(int64) {
 				return col.Direction == execinfrapb.Ordering_Column_ASC
@@ -399,7 +397,11 @@ func generateColumnOrdering(
 	}
 	orderingCols := make([]execinfrapb.Ordering_Column, nOrderingCols)
 	for i, col := range rng.Perm(nCols)[:nOrderingCols] {
-		orderingCols[i] = execinfrapb.Ordering_Column{ColIdx: uint32(col), Direction: execinfrapb.Ordering_Column_Direction(rng.Intn(2))}
+		orderingCols[i] = execinfrapb.Ordering_Column{
+			ColIdx:     uint32(col),
+			Direction:  execinfrapb.Ordering_Column_Direction(rng.Intn(2)),
+			NullsOrder: execinfrapb.Ordering_Column_NullsOrder(rng.Intn(3)),
+		}
 	}
 	return orderingCols
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // {{/*
@@ -48,6 +49,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // Dummy import to pull in "tree" package.
 var _ tree.Datum
 
@@ -97,7 +101,7 @@ func isSorterSupported(t coltypes.T, dir execinfrapb.Ordering_Column_Direction)
 }
 
 func newSingleSorter(
-	t coltypes.T, dir execinfrapb.Ordering_Column_Direction, hasNulls bool,
+	t coltypes.T, dir execinfrapb.Ordering_Column_Direction, hasNulls bool, nullsReversed bool,
 ) colSorter {
 	switch t {
 	// {{range $typ, $ := . }} {{/* for each type */}}
@@ -108,7 +112,7 @@ func newSingleSorter(
 			switch dir {
 			// {{range .Overloads}} {{/* for each direction */}}
 			case _DIR_ENUM:
-				return &sort_TYPE_DIR_HANDLES_NULLSOp{}
+				return &sort_TYPE_DIR_HANDLES_NULLSOp{nullsReversed: nullsReversed}
 			// {{end}}
 			default:
 				execerror.VectorizedInternalPanic("nulls switch failed")
@@ -134,6 +138,10 @@ type sort_TYPE_DIR_HANDLES_NULLSOp struct {
 	nulls         *coldata.Nulls
 	order         []uint64
 	cancelChecker CancelChecker
+	// nullsReversed indicates that the NULLs are placed on the opposite side to
+	// the default one, that is last in the ascending or first in the
+	// descending order.
+	nullsReversed bool
 }
 
 func (s *sort_TYPE_DIR_HANDLES_NULLSOp) init(col coldata.Vec, order []uint64) {
@@ -169,26 +177,18 @@ func (s *sort_TYPE_DIR_HANDLES_NULLSOp) Less(i, j int) bool {
 	// {{ if eq .Nulls true }}
 	n1 := s.nulls.MaybeHasNulls() && s.nulls.NullAt64(s.order[i])
 	n2 := s.nulls.MaybeHasNulls() && s.nulls.NullAt64(s.order[j])
-	// {{ if eq .DirString "Asc" }}
-	// If ascending, nulls always sort first, so we encode that logic here.
-	if n1 && n2 {
-		return false
-	} else if n1 {
-		return true
-	} else if n2 {
-		return false
-	}
-	// {{ else if eq .DirString "Desc" }}
-	// If descending, nulls always sort last, so we encode that logic here.
 	if n1 && n2 {
 		return false
-	} else if n1 {
-		return false
-	} else if n2 {
-		return true
+	} else if n1 || n2 {
+		// {{ if eq .DirString "Asc" }}
+		// If ascending, nulls sort first by default, so we encode that logic here.
+		return n1 != s.nullsReversed
+		// {{ else if eq .DirString "Desc" }}
+		// If descending, nulls sort last by default, so we encode that logic here.
+		return n2 != s.nullsReversed
+		// {{end}}
 	}
 	// {{end}}
-	// {{end}}
 	var lt bool
 	// We always indirect via the order vector.
 	arg1 := execgen.UNSAFEGET(s.sortCol, int(s.order[i]))
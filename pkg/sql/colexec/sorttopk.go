@@ -69,8 +69,13 @@ type topKSorter struct {
 
 	// state is the current state of the sort.
 	state topKSortState
-	// comparators stores one comparator per ordering column.
+	// comparators stores one comparator per input column. The comparators are
+	// used to compare the ordering columns and to replace rows in topK. The
+	// columns of coltypes.Datum type (which can never be the ordering columns)
+	// don't have comparators.
 	comparators []vecComparator
+	// datumCols are the indices of the columns of coltypes.Datum type.
+	datumCols []int
 	// topK stores the top K rows. It is not sorted internally.
 	topK coldata.Batch
 	// heap is a max heap which stores indices into topK.
@@ -86,8 +91,11 @@ func (t *topKSorter) Init() {
 	t.input.Init()
 	t.topK = t.allocator.NewMemBatchWithSize(t.inputTypes, int(t.k))
 	t.comparators = make([]vecComparator, len(t.inputTypes))
-	for i := range t.inputTypes {
-		typ := t.inputTypes[i]
+	for i, typ := range t.inputTypes {
+		if typ == coltypes.Datum {
+			t.datumCols = append(t.datumCols, i)
+			continue
+		}
 		t.comparators[i] = GetVecComparator(typ, 2)
 	}
 	t.output = t.allocator.NewMemBatchWithSize(t.inputTypes, int(coldata.BatchSize()))
@@ -176,9 +184,7 @@ func (t *topKSorter) spool(ctx context.Context) {
 					}
 					maxIdx := t.heap[0]
 					if t.compareRow(inputVecIdx, topKVecIdx, idx, maxIdx) < 0 {
-						for j := range t.inputTypes {
-							t.comparators[j].set(inputVecIdx, topKVecIdx, idx, maxIdx)
-						}
+						t.replaceRow(inputBatch, idx, maxIdx)
 						heap.Fix(t, 0)
 					}
 				}
@@ -232,8 +238,12 @@ func (t *topKSorter) emit() coldata.Batch {
 func (t *topKSorter) compareRow(vecIdx1, vecIdx2 int, rowIdx1, rowIdx2 uint16) int {
 	for i := range t.orderingCols {
 		info := t.orderingCols[i]
-		res := t.comparators[info.ColIdx].compare(vecIdx1, vecIdx2, rowIdx1, rowIdx2)
+		c := t.comparators[info.ColIdx]
+		res := c.compare(vecIdx1, vecIdx2, rowIdx1, rowIdx2)
 		if res != 0 {
+			if info.NullsReversed() && c.isNull(vecIdx1, rowIdx1) != c.isNull(vecIdx2, rowIdx2) {
+				res = -res
+			}
 			switch d := info.Direction; d {
 			case execinfrapb.Ordering_Column_ASC:
 				return res
@@ -247,9 +257,30 @@ func (t *topKSorter) compareRow(vecIdx1, vecIdx2 int, rowIdx1, rowIdx2 uint16) i
 	return 0
 }
 
+// replaceRow overwrites the row at topKIdx in topK with the row at srcIdx in
+// batch.
+func (t *topKSorter) replaceRow(batch coldata.Batch, srcIdx, topKIdx uint16) {
+	for _, c := range t.comparators {
+		if c != nil {
+			c.set(inputVecIdx, topKVecIdx, srcIdx, topKIdx)
+		}
+	}
+	for _, colIdx := range t.datumCols {
+		src, dst := batch.ColVec(colIdx), t.topK.ColVec(colIdx)
+		if src.MaybeHasNulls() && src.Nulls().NullAt(srcIdx) {
+			dst.Nulls().SetNull(topKIdx)
+		} else {
+			dst.Nulls().UnsetNull(topKIdx)
+			dst.Datum()[topKIdx] = src.Datum()[srcIdx]
+		}
+	}
+}
+
 func (t *topKSorter) updateComparators(vecIdx int, batch coldata.Batch) {
-	for i := range t.inputTypes {
-		t.comparators[i].setVec(vecIdx, batch.ColVec(i))
+	for i, c := range t.comparators {
+		if c != nil {
+			c.setVec(vecIdx, batch.ColVec(i))
+		}
 	}
 }
 
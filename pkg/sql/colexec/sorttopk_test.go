@@ -64,10 +64,18 @@ func TestTopKSorter(t *testing.T) {
 			},
 			k: 3,
 		},
+		{
+			name:     "datum column",
+			tuples:   tuples{{3, "c"}, {1, "a"}, {4, nil}, {2, "b"}, {0, "z"}},
+			expected: tuples{{0, "z"}, {1, "a"}, {2, "b"}},
+			typ:      []coltypes.T{coltypes.Int64, coltypes.Datum},
+			ordCols:  []execinfrapb.Ordering_Column{{ColIdx: 0}},
+			k:        3,
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			runTests(t, []tuples{tc.tuples}, tc.expected, orderedVerifier, func(input []Operator) (Operator, error) {
+			runTestsWithTyps(t, []tuples{tc.tuples}, [][]coltypes.T{tc.typ}, tc.expected, orderedVerifier, func(input []Operator) (Operator, error) {
 				return NewTopKSorter(testAllocator, input[0], tc.typ, tc.ordCols, tc.k), nil
 			})
 		})
@@ -16,6 +16,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -38,6 +40,21 @@ type VectorizedStatsCollector struct {
 	// wrapped Operator is feeding into. It must be started right before
 	// returning a batch when Nexted. It is used by the "output" Operator.
 	outputWatch *timeutil.StopWatch
+	// memMonitors are the monitors of the buffering Operators that make up the
+	// wrapped Operator. They are used to report the maximum amount of memory
+	// allocated.
+	memMonitors []*mon.BytesMonitor
+	// bloomFilterStats are the stats of the bloom filters used by the wrapped
+	// Operator. They are used to report the selectivity of the filters.
+	bloomFilterStats []*BloomFilterStats
+	// kvReaders are the Operators that make up the wrapped Operator and read
+	// from the KV layer. They are used to report the time spent waiting on
+	// conflicting transactions.
+	kvReaders []KVReader
+	// streamID is the ID of the stream that the wrapped Operator (which must be
+	// an Inbox) reads from. It is only valid if isStream is true.
+	streamID execinfrapb.StreamID
+	isStream bool
 }
 
 var _ Operator = &VectorizedStatsCollector{}
@@ -45,9 +62,14 @@ var _ Operator = &VectorizedStatsCollector{}
 // NewVectorizedStatsCollector creates a new VectorizedStatsCollector which
 // wraps op that corresponds to a processor with ProcessorID id. isStall
 // indicates whether stall or execution time is being measured. inputWatch must
-// be non-nil.
+// be non-nil. memMonitors are the memory monitors of the buffering Operators
+// that op consists of (if any).
 func NewVectorizedStatsCollector(
-	op Operator, id int32, isStall bool, inputWatch *timeutil.StopWatch,
+	op Operator,
+	id int32,
+	isStall bool,
+	inputWatch *timeutil.StopWatch,
+	memMonitors []*mon.BytesMonitor,
 ) *VectorizedStatsCollector {
 	if inputWatch == nil {
 		execerror.VectorizedInternalPanic("input watch for VectorizedStatsCollector is nil")
@@ -56,6 +78,7 @@ func NewVectorizedStatsCollector(
 		Operator:        op,
 		VectorizedStats: execpb.VectorizedStats{ID: id, Stall: isStall},
 		inputWatch:      inputWatch,
+		memMonitors:     memMonitors,
 	}
 }
 
@@ -65,6 +88,32 @@ func (vsc *VectorizedStatsCollector) SetOutputWatch(outputWatch *timeutil.StopWa
 	vsc.outputWatch = outputWatch
 }
 
+// SetBloomFilterStats sets the stats of the bloom filters used by the wrapped
+// Operator.
+func (vsc *VectorizedStatsCollector) SetBloomFilterStats(stats []*BloomFilterStats) {
+	vsc.bloomFilterStats = stats
+}
+
+// SetKVReaders sets the Operators that make up the wrapped Operator and read
+// from the KV layer.
+func (vsc *VectorizedStatsCollector) SetKVReaders(kvReaders []KVReader) {
+	vsc.kvReaders = kvReaders
+}
+
+// SetStreamID marks this VectorizedStatsCollector as collecting the stats of
+// the stream with the given ID rather than of a processor. It is used for the
+// Inboxes so that their stats are shown on the corresponding streams.
+func (vsc *VectorizedStatsCollector) SetStreamID(id execinfrapb.StreamID) {
+	vsc.streamID = id
+	vsc.isStream = true
+}
+
+// StreamID returns the ID of the stream which this VectorizedStatsCollector
+// collects the stats of, if any.
+func (vsc *VectorizedStatsCollector) StreamID() (_ execinfrapb.StreamID, ok bool) {
+	return vsc.streamID, vsc.isStream
+}
+
 // Next is part of Operator interface.
 func (vsc *VectorizedStatsCollector) Next(ctx context.Context) coldata.Batch {
 	if vsc.outputWatch != nil {
@@ -97,7 +146,25 @@ func (vsc *VectorizedStatsCollector) Next(ctx context.Context) coldata.Batch {
 	return batch
 }
 
-// FinalizeStats records the time measured by the stop watch into the stats.
+// FinalizeStats records the time measured by the stop watch, the maximum
+// memory usage of the buffering Operators, the number of tuples that went
+// through the bloom filters, and the time the KV requests spent waiting on
+// conflicting transactions into the stats.
 func (vsc *VectorizedStatsCollector) FinalizeStats() {
 	vsc.Time = vsc.inputWatch.Elapsed()
+	vsc.MaxAllocatedMem = 0
+	for _, memMon := range vsc.memMonitors {
+		vsc.MaxAllocatedMem += memMon.MaximumBytes()
+	}
+	vsc.BloomFilterInputTuples, vsc.BloomFilterOutputTuples = 0, 0
+	for _, stats := range vsc.bloomFilterStats {
+		vsc.BloomFilterInputTuples += stats.InputTuples
+		vsc.BloomFilterOutputTuples += stats.OutputTuples
+	}
+	vsc.ContentionTime = 0
+	for _, r := range vsc.kvReaders {
+		for _, ev := range r.GetContentionEvents() {
+			vsc.ContentionTime += ev.Duration
+		}
+	}
 }
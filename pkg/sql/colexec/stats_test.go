@@ -17,9 +17,12 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +32,7 @@ func TestNumBatches(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	nBatches := 10
 	noop := NewNoop(makeFiniteChunksSourceWithBatchSize(nBatches, int(coldata.BatchSize())))
-	vsc := NewVectorizedStatsCollector(noop, 0 /* id */, true /* isStall */, timeutil.NewStopWatch())
+	vsc := NewVectorizedStatsCollector(noop, 0 /* id */, true /* isStall */, timeutil.NewStopWatch(), nil /* memMonitors */)
 	vsc.Init()
 	for {
 		b := vsc.Next(context.Background())
@@ -40,13 +43,49 @@ func TestNumBatches(t *testing.T) {
 	require.Equal(t, nBatches, int(vsc.NumBatches))
 }
 
+// TestMaxAllocatedMem is a unit test for MaxAllocatedMem field of
+// VectorizedStats.
+func TestMaxAllocatedMem(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	memMonitor := execinfra.NewTestMemMonitor(ctx, cluster.MakeTestingClusterSettings())
+	defer memMonitor.Stop(ctx)
+	acc := memMonitor.MakeBoundAccount()
+	defer acc.Close(ctx)
+
+	// The sorter buffers all of its input, so the memory it allocated must be
+	// reflected in the stats even after the account has been cleared.
+	nBatches := 4
+	typs := []coltypes.T{coltypes.Int64}
+	sorter, err := NewSorter(
+		NewAllocator(ctx, &acc), makeFiniteChunksSourceWithBatchSize(nBatches, int(coldata.BatchSize())),
+		typs, []execinfrapb.Ordering_Column{{ColIdx: 0}},
+	)
+	require.NoError(t, err)
+	vsc := NewVectorizedStatsCollector(
+		sorter, 0 /* id */, false /* isStall */, timeutil.NewStopWatch(), []*mon.BytesMonitor{memMonitor},
+	)
+	vsc.Init()
+	for {
+		b := vsc.Next(ctx)
+		if b.Length() == 0 {
+			break
+		}
+	}
+	acc.Clear(ctx)
+	vsc.FinalizeStats()
+	require.Equal(t, nBatches*int(coldata.BatchSize()), int(vsc.NumTuples))
+	require.True(t, vsc.MaxAllocatedMem >= int64(nBatches*int(coldata.BatchSize())*8),
+		"unexpected max allocated memory %d", vsc.MaxAllocatedMem)
+}
+
 // TestNumTuples is a unit test for NumTuples field of VectorizedStats.
 func TestNumTuples(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	nBatches := 10
 	for _, batchSize := range []int{1, 16, 1024} {
 		noop := NewNoop(makeFiniteChunksSourceWithBatchSize(nBatches, batchSize))
-		vsc := NewVectorizedStatsCollector(noop, 0 /* id */, true /* isStall */, timeutil.NewStopWatch())
+		vsc := NewVectorizedStatsCollector(noop, 0 /* id */, true /* isStall */, timeutil.NewStopWatch(), nil /* memMonitors */)
 		vsc.Init()
 		for {
 			b := vsc.Next(context.Background())
@@ -72,14 +111,14 @@ func TestVectorizedStatsCollector(t *testing.T) {
 			OneInputNode: NewOneInputNode(makeFiniteChunksSourceWithBatchSize(nBatches, int(coldata.BatchSize()))),
 			timeSource:   timeSource,
 		}
-		leftInput := NewVectorizedStatsCollector(leftSource, 0 /* id */, true /* isStall */, timeutil.NewTestStopWatch(timeSource.Now))
+		leftInput := NewVectorizedStatsCollector(leftSource, 0 /* id */, true /* isStall */, timeutil.NewTestStopWatch(timeSource.Now), nil /* memMonitors */)
 		leftInput.SetOutputWatch(mjInputWatch)
 
 		rightSource := &timeAdvancingOperator{
 			OneInputNode: NewOneInputNode(makeFiniteChunksSourceWithBatchSize(nBatches, int(coldata.BatchSize()))),
 			timeSource:   timeSource,
 		}
-		rightInput := NewVectorizedStatsCollector(rightSource, 1 /* id */, true /* isStall */, timeutil.NewTestStopWatch(timeSource.Now))
+		rightInput := NewVectorizedStatsCollector(rightSource, 1 /* id */, true /* isStall */, timeutil.NewTestStopWatch(timeSource.Now), nil /* memMonitors */)
 		rightInput.SetOutputWatch(mjInputWatch)
 
 		mergeJoiner, err := NewMergeJoinOp(
@@ -93,6 +132,8 @@ func TestVectorizedStatsCollector(t *testing.T) {
 			[]coltypes.T{coltypes.Int64},
 			[]execinfrapb.Ordering_Column{{ColIdx: 0}},
 			[]execinfrapb.Ordering_Column{{ColIdx: 0}},
+			false, /* leftEqColsAreKey */
+			false, /* rightEqColsAreKey */
 			nil,   /* filterConstructor */
 			false, /* filterOnlyOnLeft */
 		)
@@ -103,7 +144,7 @@ func TestVectorizedStatsCollector(t *testing.T) {
 			OneInputNode: NewOneInputNode(mergeJoiner),
 			timeSource:   timeSource,
 		}
-		mjStatsCollector := NewVectorizedStatsCollector(timeAdvancingMergeJoiner, 2 /* id */, false /* isStall */, mjInputWatch)
+		mjStatsCollector := NewVectorizedStatsCollector(timeAdvancingMergeJoiner, 2 /* id */, false /* isStall */, mjInputWatch, nil /* memMonitors */)
 
 		// The inputs are identical, so the merge joiner should output nBatches
 		// batches with each having coldata.BatchSize() tuples.
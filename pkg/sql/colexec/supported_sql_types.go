@@ -27,6 +27,12 @@ var allSupportedSQLTypes = []types.T{
 	*types.Float4,
 	*types.String,
 	*types.Uuid,
+	*types.INet,
+	*types.VarBit,
+	*types.Jsonb,
 	*types.Timestamp,
 	*types.TimestampTZ,
+	*types.Interval,
+	*types.IntArray,
+	*types.StringArray,
 }
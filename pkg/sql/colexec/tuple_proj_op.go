@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// NewTupleProjOp returns an operator that projects tuples of type tupleTyp
+// whose elements are the values of the tupleContentsIdxs columns. Tuples
+// don't have a native columnar representation, so the output column at index
+// outputIdx is of coltypes.Datum type.
+func NewTupleProjOp(
+	allocator *Allocator,
+	tupleTyp *types.T,
+	columnTypes []types.T,
+	tupleContentsIdxs []int,
+	outputIdx int,
+	input Operator,
+) Operator {
+	return &tupleProjOp{
+		OneInputNode:      NewOneInputNode(input),
+		allocator:         allocator,
+		tupleTyp:          tupleTyp,
+		columnTypes:       columnTypes,
+		tupleContentsIdxs: tupleContentsIdxs,
+		outputIdx:         outputIdx,
+	}
+}
+
+type tupleProjOp struct {
+	OneInputNode
+	allocator         *Allocator
+	tupleTyp          *types.T
+	columnTypes       []types.T
+	tupleContentsIdxs []int
+	outputIdx         int
+
+	da sqlbase.DatumAlloc
+}
+
+var _ Operator = &tupleProjOp{}
+
+func (t *tupleProjOp) Init() {
+	t.input.Init()
+}
+
+func (t *tupleProjOp) Next(ctx context.Context) coldata.Batch {
+	batch := t.input.Next(ctx)
+	n := batch.Length()
+	if t.outputIdx == batch.Width() {
+		t.allocator.AppendColumn(batch, coltypes.Datum)
+	}
+	if n == 0 {
+		return batch
+	}
+	sel := batch.Selection()
+	output := batch.ColVec(t.outputIdx)
+	outputCol := output.Datum()
+	t.allocator.PerformOperation(
+		[]coldata.Vec{output},
+		func() {
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				// A tuple is never NULL, even if all of its elements are.
+				tuple := tree.NewDTupleWithLen(t.tupleTyp, len(t.tupleContentsIdxs))
+				for j, colIdx := range t.tupleContentsIdxs {
+					tuple.D[j] = PhysicalTypeColElemToDatum(
+						batch.ColVec(colIdx), rowIdx, t.da, &t.columnTypes[colIdx],
+					)
+				}
+				outputCol[rowIdx] = tuple
+			}
+		},
+	)
+	// Although we didn't change the length of the batch, it is necessary to set
+	// the length anyway (this helps maintaining the invariant of flat bytes).
+	batch.SetLength(n)
+	return batch
+}
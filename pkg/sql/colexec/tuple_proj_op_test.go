@@ -0,0 +1,91 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestTupleProjOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int, *types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Noop: &execinfrapb.NoopCoreSpec{},
+		},
+	}
+
+	input := tuples{{1, 2}, {3, 3}, {nil, 2}, {3, 4}}
+	for _, tc := range []struct {
+		desc     string
+		post     execinfrapb.PostProcessSpec
+		expected tuples
+	}{
+		{
+			desc: "IN with constant tuples",
+			post: execinfrapb.PostProcessSpec{
+				RenderExprs: []execinfrapb.Expression{{Expr: "(@1, @2) IN ((1, 2), (3, 4))"}},
+			},
+			expected: tuples{{true}, {false}, {nil}, {true}},
+		},
+		{
+			desc: "comparison of tuples",
+			post: execinfrapb.PostProcessSpec{
+				RenderExprs: []execinfrapb.Expression{{Expr: "(@1, @2) = (@2, @1)"}},
+			},
+			expected: tuples{{false}, {true}, {nil}, {false}},
+		},
+		{
+			desc: "filter",
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "(@1, @2) NOT IN ((1, 2), (3, 4))"},
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: tuples{{3, 3}},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			runTests(t, []tuples{input}, tc.expected, orderedVerifier, func(inputs []Operator) (Operator, error) {
+				spec.Post = tc.post
+				args := NewColOperatorArgs{
+					Spec:                               spec,
+					Inputs:                             inputs,
+					StreamingMemAccount:                testMemAcc,
+					UseStreamingMemAccountForBuffering: true,
+				}
+				result, err := NewColOperator(ctx, flowCtx, args)
+				if err != nil {
+					return nil, err
+				}
+				return result.Op, nil
+			})
+		})
+	}
+}
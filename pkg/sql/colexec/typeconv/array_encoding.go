@@ -0,0 +1,98 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package typeconv
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// ARRAY columns are represented in the vectorized engine by flat Bytes vectors
+// (i.e. a single buffer with the elements of all arrays laid out contiguously
+// plus an offsets slice delimiting each array, similar to Arrow's list
+// layout). Every array is stored as the concatenation of its elements, each
+// of which is prefixed with a marker byte: arrayElemNullMarker for NULL
+// elements, and arrayElemMarker followed by the ascending key encoding of the
+// element otherwise.
+//
+// Such an encoding has two useful properties:
+//   - equal arrays have equal encodings, and
+//   - the encodings sort in the same order as tree.DArray.Compare does (NULL
+//     elements sort before all other elements, and an array sorts before any
+//     other array that it is a prefix of),
+//
+// so the operators that work on Bytes columns (the sorter, the hash joiner,
+// the Arrow batch converter, etc.) support arrays without modifications.
+const (
+	arrayElemNullMarker byte = 0x00
+	arrayElemMarker     byte = 0x01
+)
+
+// ArrayContentsTypeSupported returns whether arrays with elements of type t
+// can be represented in the vectorized engine. Only the types that have a
+// lossless key encoding are supported.
+func ArrayContentsTypeSupported(t *types.T) bool {
+	return sqlbase.ColumnTypeIsIndexable(t) && !sqlbase.DatumTypeHasCompositeKeyEncoding(t)
+}
+
+// EncodeArray appends the vectorized representation of the array d to b and
+// returns the resulting buffer.
+func EncodeArray(b []byte, d *tree.DArray) ([]byte, error) {
+	var err error
+	for _, elem := range d.Array {
+		if b, err = EncodeArrayElem(b, elem); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// EncodeArrayElem appends the vectorized representation of a single array
+// element to b and returns the resulting buffer. The concatenation of the
+// encodings of several elements is the encoding of the array of these
+// elements.
+func EncodeArrayElem(b []byte, elem tree.Datum) ([]byte, error) {
+	if elem == tree.DNull {
+		return append(b, arrayElemNullMarker), nil
+	}
+	b = append(b, arrayElemMarker)
+	return sqlbase.EncodeTableKey(b, elem, encoding.Ascending)
+}
+
+// DecodeArray decodes an array with elements of type elemType that was
+// encoded with EncodeArray.
+func DecodeArray(a *sqlbase.DatumAlloc, elemType *types.T, b []byte) (*tree.DArray, error) {
+	res := tree.NewDArray(elemType)
+	var (
+		elem tree.Datum
+		err  error
+	)
+	for len(b) > 0 {
+		switch b[0] {
+		case arrayElemNullMarker:
+			elem, b = tree.DNull, b[1:]
+		case arrayElemMarker:
+			elem, b, err = sqlbase.DecodeTableKey(a, elemType, b[1:], encoding.Ascending)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.AssertionFailedf("unexpected array element marker %x", b[0])
+		}
+		if err := res.Append(elem); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package typeconv
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestArrayEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	var da sqlbase.DatumAlloc
+	makeIntArray := func(elems ...tree.Datum) *tree.DArray {
+		arr := tree.NewDArray(types.Int)
+		for _, elem := range elems {
+			if err := arr.Append(elem); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return arr
+	}
+	// NULL elements sort before all other elements, and an array sorts before
+	// the arrays that it is a prefix of.
+	ordered := []*tree.DArray{
+		makeIntArray(),
+		makeIntArray(tree.DNull),
+		makeIntArray(tree.DNull, tree.NewDInt(1)),
+		makeIntArray(tree.NewDInt(-1)),
+		makeIntArray(tree.NewDInt(1)),
+		makeIntArray(tree.NewDInt(1), tree.DNull),
+		makeIntArray(tree.NewDInt(1), tree.NewDInt(0)),
+	}
+	var prev []byte
+	for i, arr := range ordered {
+		enc, err := EncodeArray(nil /* b */, arr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && bytes.Compare(prev, enc) >= 0 {
+			t.Fatalf("expected encoding of %s to sort after encoding of %s", arr, ordered[i-1])
+		}
+		prev = enc
+	}
+	for _, typ := range []*types.T{types.IntArray, types.StringArray, types.MakeArray(types.Bool)} {
+		if !ArrayContentsTypeSupported(typ.ArrayContents()) {
+			t.Fatalf("expected %s to be supported", typ)
+		}
+		for i := 0; i < 100; i++ {
+			a, ok := sqlbase.RandDatum(rng, typ, false /* nullOk */).(*tree.DArray)
+			if !ok {
+				continue
+			}
+			b, ok := sqlbase.RandDatum(rng, typ, false /* nullOk */).(*tree.DArray)
+			if !ok {
+				continue
+			}
+			encA, err := EncodeArray(nil /* b */, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encB, err := EncodeArray(nil /* b */, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decA, err := DecodeArray(&da, typ.ArrayContents(), encA)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decA.Compare(&evalCtx, a) != 0 {
+				t.Fatalf("expected %s after round trip, found %s", a, decA)
+			}
+			if expected, actual := a.Compare(&evalCtx, b), bytes.Compare(encA, encB); expected != actual {
+				t.Fatalf("comparing %s and %s: expected %d, found %d", a, b, expected, actual)
+			}
+		}
+	}
+	for _, typ := range []*types.T{types.Decimal, types.Jsonb, types.IntArray} {
+		if ArrayContentsTypeSupported(typ) {
+			t.Fatalf("expected arrays of %s to be unsupported", typ)
+		}
+	}
+}
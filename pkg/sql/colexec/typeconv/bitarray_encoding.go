@@ -0,0 +1,41 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package typeconv
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// BIT and VARBIT columns are represented in the vectorized engine by Bytes
+// vectors that contain the ascending key encoding of each bit array. Equal bit
+// arrays have equal encodings and the encodings sort in the same order as
+// bitarray.Compare does, so, as for arrays, the operators that work on Bytes
+// columns support bit arrays without modifications.
+
+// EncodeBitArray appends the vectorized representation of the bit array d to
+// b and returns the resulting buffer.
+func EncodeBitArray(b []byte, d bitarray.BitArray) []byte {
+	return encoding.EncodeBitArrayAscending(b, d)
+}
+
+// DecodeBitArray decodes a bit array that was encoded with EncodeBitArray.
+func DecodeBitArray(b []byte) (bitarray.BitArray, error) {
+	rem, d, err := encoding.DecodeBitArrayAscending(b)
+	if err != nil {
+		return bitarray.BitArray{}, err
+	}
+	if len(rem) != 0 {
+		return bitarray.BitArray{}, errors.AssertionFailedf("%d trailing bytes after bit array", len(rem))
+	}
+	return d, nil
+}
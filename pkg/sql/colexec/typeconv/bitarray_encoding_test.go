@@ -0,0 +1,47 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package typeconv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestBitArrayEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	for i := 0; i < 1000; i++ {
+		a := bitarray.Rand(rng, uint(rng.Intn(200)))
+		b := bitarray.Rand(rng, uint(rng.Intn(200)))
+		if rng.Intn(4) == 0 {
+			// Make sure that we also compare bit arrays that are prefixes of one
+			// another.
+			b = bitarray.Concat(a, b)
+		}
+		encA := EncodeBitArray(nil /* b */, a)
+		encB := EncodeBitArray(nil /* b */, b)
+		decA, err := DecodeBitArray(encA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bitarray.Compare(decA, a) != 0 {
+			t.Fatalf("expected %s after round trip, found %s", a, decA)
+		}
+		if expected, actual := bitarray.Compare(a, b), bytes.Compare(encA, encB); expected != actual {
+			t.Fatalf("comparing %s and %s: expected %d, found %d", a, b, expected, actual)
+		}
+	}
+}
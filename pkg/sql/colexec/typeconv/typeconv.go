@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +32,24 @@ func FromColumnType(ct *types.T) coltypes.T {
 		return coltypes.Bool
 	case types.BytesFamily, types.StringFamily, types.UuidFamily:
 		return coltypes.Bytes
+	case types.INetFamily:
+		// INET values are stored in their value encoding (see
+		// ipaddr.IPAddr.ToBuffer) which sorts in the same order as
+		// ipaddr.IPAddr.Compare does.
+		return coltypes.Bytes
+	case types.BitFamily:
+		// Bit arrays are stored using an order-preserving encoding (see
+		// EncodeBitArray).
+		return coltypes.Bytes
+	case types.JsonFamily:
+		// JSON values are stored in their encoded form (see json.EncodeJSON) and
+		// are decoded lazily by the operators that need to inspect them.
+		return coltypes.Bytes
+	case types.ArrayFamily:
+		// Arrays are stored using an order-preserving encoding (see EncodeArray).
+		if ArrayContentsTypeSupported(ct.ArrayContents()) {
+			return coltypes.Bytes
+		}
 	case types.DateFamily, types.OidFamily:
 		return coltypes.Int64
 	case types.DecimalFamily:
@@ -51,21 +70,22 @@ func FromColumnType(ct *types.T) coltypes.T {
 		return coltypes.Timestamp
 	case types.TimestampTZFamily:
 		return coltypes.Timestamp
+	case types.IntervalFamily:
+		return coltypes.Interval
 	}
-	return coltypes.Unhandled
+	// All other types don't have a native columnar representation, so their
+	// values are stored as tree.Datums.
+	return coltypes.Datum
 }
 
 // FromColumnTypes calls FromColumnType on each element of cts, returning the
 // resulting slice.
-func FromColumnTypes(cts []types.T) ([]coltypes.T, error) {
+func FromColumnTypes(cts []types.T) []coltypes.T {
 	typs := make([]coltypes.T, len(cts))
 	for i := range typs {
 		typs[i] = FromColumnType(&cts[i])
-		if typs[i] == coltypes.Unhandled {
-			return nil, errors.Errorf("unsupported type %s", cts[i].String())
-		}
 	}
-	return typs, nil
+	return typs
 }
 
 // ToColumnType converts a types.T that corresponds to the column type. Note
@@ -90,6 +110,8 @@ func ToColumnType(t coltypes.T) *types.T {
 		return types.Float
 	case coltypes.Timestamp:
 		return types.Timestamp
+	case coltypes.Interval:
+		return types.Interval
 	}
 	execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected coltype %s", t.String()))
 	return nil
@@ -109,6 +131,11 @@ func ToColumnTypes(typs []coltypes.T) []types.T {
 // GetDatumToPhysicalFn returns a function for converting a datum of the given
 // ColumnType to the corresponding Go type.
 func GetDatumToPhysicalFn(ct *types.T) func(tree.Datum) (interface{}, error) {
+	if FromColumnType(ct) == coltypes.Datum {
+		return func(datum tree.Datum) (interface{}, error) {
+			return datum, nil
+		}
+	}
 	switch ct.Family() {
 	case types.BoolFamily:
 		return func(datum tree.Datum) (interface{}, error) {
@@ -208,6 +235,38 @@ func GetDatumToPhysicalFn(ct *types.T) func(tree.Datum) (interface{}, error) {
 			}
 			return d.UUID.GetBytesMut(), nil
 		}
+	case types.INetFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DIPAddr)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DIPAddr, found %s", reflect.TypeOf(datum))
+			}
+			return d.IPAddr.ToBuffer(nil /* appendTo */), nil
+		}
+	case types.BitFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DBitArray)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DBitArray, found %s", reflect.TypeOf(datum))
+			}
+			return EncodeBitArray(nil /* b */, d.BitArray), nil
+		}
+	case types.JsonFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DJSON)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DJSON, found %s", reflect.TypeOf(datum))
+			}
+			return json.EncodeJSON(nil, d.JSON)
+		}
+	case types.ArrayFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DArray)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DArray, found %s", reflect.TypeOf(datum))
+			}
+			return EncodeArray(nil /* b */, d)
+		}
 	case types.TimestampFamily:
 		return func(datum tree.Datum) (interface{}, error) {
 			d, ok := datum.(*tree.DTimestamp)
@@ -224,6 +283,14 @@ func GetDatumToPhysicalFn(ct *types.T) func(tree.Datum) (interface{}, error) {
 			}
 			return d.Time, nil
 		}
+	case types.IntervalFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DInterval)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DInterval, found %s", reflect.TypeOf(datum))
+			}
+			return d.Duration, nil
+		}
 	}
 	// It would probably be more correct to return an error here, rather than a
 	// function which always returns an error. But since the function tends to be
@@ -13,6 +13,7 @@ package colexec
 import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 var zeroBoolColumn = make([]bool, coldata.MaxBatchSize)
@@ -26,3 +27,5 @@ var zeroInt32Column = make([]int32, coldata.MaxBatchSize)
 var zeroInt64Column = make([]int64, coldata.MaxBatchSize)
 
 var zeroFloat64Column = make([]float64, coldata.MaxBatchSize)
+
+var zeroIntervalColumn = make([]duration.Duration, coldata.MaxBatchSize)
@@ -585,6 +585,8 @@ func (s *opTestInput) Next(context.Context) coldata.Batch {
 						newBytes := make([]byte, rng.Intn(16)+1)
 						rng.Read(newBytes)
 						setColVal(vec, int(outputIdx), newBytes)
+					} else if typ == coltypes.Datum {
+						vec.Datum()[outputIdx] = rng.Int63()
 					} else if val, ok := quick.Value(reflect.TypeOf(vec.Col()).Elem(), rng); ok {
 						setColVal(vec, int(outputIdx), val.Interface())
 					} else {
@@ -0,0 +1,251 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// {{/*
+// +build execgen_template
+//
+// This file is the execgen template for variance_agg.eg.go. It's formatted in
+// a special way, so it's both valid Go and a valid text/template input. This
+// permits editing this file with editor support.
+//
+// */}}
+
+package colexec
+
+import (
+	"math"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/pkg/errors"
+)
+
+// {{/*
+// Declarations to make the template compile properly
+
+// Dummy import to pull in "apd" package.
+var _ apd.Decimal
+
+// Dummy import to pull in "math" package.
+var _ = math.Sqrt
+
+// Dummy import to pull in "tree" package.
+var _ tree.Datum
+
+// _UPDATE_SQR_DIFF is the template function for adding the value given by the
+// first input to the running mean and sum of squared differences of the
+// current group.
+func _UPDATE_SQR_DIFF(_ string) {
+	execerror.VectorizedInternalPanic("")
+}
+
+// _ASSIGN_RESULT is the template function for assigning the first input to
+// the result of the aggregation of the current group, which has at least
+// a.kind.minCount() non-null values.
+func _ASSIGN_RESULT(_ string) {
+	execerror.VectorizedInternalPanic("")
+}
+
+// */}}
+
+// varianceAggKind determines what a variance aggregate outputs once it has
+// computed the sum of squared differences from the mean of a group.
+type varianceAggKind int
+
+const (
+	// varianceKindSqrDiff outputs the sum of squared differences itself. It is
+	// the local stage of a distributed VARIANCE or STDDEV.
+	varianceKindSqrDiff varianceAggKind = iota
+	// varianceKindVariance outputs the sample variance.
+	varianceKindVariance
+	// varianceKindStdDev outputs the sample standard deviation.
+	varianceKindStdDev
+)
+
+// minCount returns the minimum number of non-null values a group must have for
+// the result to be non-null.
+func (k varianceAggKind) minCount() int64 {
+	if k == varianceKindSqrDiff {
+		return 1
+	}
+	return 2
+}
+
+func newVarianceAgg(t coltypes.T, kind varianceAggKind) (aggregateFunc, error) {
+	switch t {
+	// {{range .}}
+	case _TYPES_T:
+		return &variance_TYPEAgg{kind: kind}, nil
+	// {{end}}
+	default:
+		return nil, errors.Errorf("unsupported variance agg type %s", t)
+	}
+}
+
+// {{range .}}
+
+// variance_TYPEAgg computes the sum of squared differences from the mean of
+// each group in a single pass using Welford's online algorithm (see
+// http://www.johndcook.com/blog/standard_deviation/), the same way the
+// row-by-row sqrdiff, variance and stddev builtins do.
+type variance_TYPEAgg struct {
+	done bool
+	kind varianceAggKind
+
+	groups  []bool
+	scratch struct {
+		curIdx int
+		// curCount keeps track of the number of non-null elements that we've
+		// seen belonging to the current group.
+		curCount int64
+		// curMean and curSqrDiff keep track of the mean of the elements belonging
+		// to the current group and the sum of their squared differences from it.
+		curMean    _GOTYPE
+		curSqrDiff _GOTYPE
+		// delta, tmp and count are used as scratch space within iterations.
+		delta _GOTYPE
+		tmp   _GOTYPE
+		count _GOTYPE
+		// vec points to the output vector.
+		vec []_GOTYPE
+		// nulls points to the output null vector that we are updating.
+		nulls *coldata.Nulls
+	}
+}
+
+var _ aggregateFunc = &variance_TYPEAgg{}
+
+func (a *variance_TYPEAgg) Init(groups []bool, v coldata.Vec) {
+	a.groups = groups
+	a.scratch.vec = v._TemplateType()
+	a.scratch.nulls = v.Nulls()
+	a.Reset()
+}
+
+func (a *variance_TYPEAgg) Reset() {
+	a.scratch.curIdx = -1
+	a.scratch.curCount = 0
+	a.scratch.curMean = zero_TYPEColumn[0]
+	a.scratch.curSqrDiff = zero_TYPEColumn[0]
+	a.scratch.nulls.UnsetNulls()
+	a.done = false
+}
+
+func (a *variance_TYPEAgg) CurrentOutputIndex() int {
+	return a.scratch.curIdx
+}
+
+func (a *variance_TYPEAgg) SetOutputIndex(idx int) {
+	if a.scratch.curIdx != -1 {
+		a.scratch.curIdx = idx
+		a.scratch.nulls.UnsetNullsAfter(uint16(idx + 1))
+	}
+}
+
+func (a *variance_TYPEAgg) Compute(b coldata.Batch, inputIdxs []uint32) {
+	if a.done {
+		return
+	}
+	inputLen := b.Length()
+	if inputLen == 0 {
+		// The aggregation is finished. Flush the last value. If we haven't found
+		// enough non-nulls for this group so far, the output for this group should
+		// be NULL.
+		if a.scratch.curCount < a.kind.minCount() {
+			a.scratch.nulls.SetNull(uint16(a.scratch.curIdx))
+		} else {
+			_ASSIGN_RESULT("a.scratch.vec[a.scratch.curIdx]")
+		}
+		a.scratch.curIdx++
+		a.done = true
+		return
+	}
+	vec, sel := b.ColVec(int(inputIdxs[0])), b.Selection()
+	col, nulls := vec._TemplateType(), vec.Nulls()
+	if nulls.MaybeHasNulls() {
+		if sel != nil {
+			sel = sel[:inputLen]
+			for _, i := range sel {
+				_ACCUMULATE_VARIANCE(a, nulls, i, true)
+			}
+		} else {
+			col = col[:inputLen]
+			for i := range col {
+				_ACCUMULATE_VARIANCE(a, nulls, i, true)
+			}
+		}
+	} else {
+		if sel != nil {
+			sel = sel[:inputLen]
+			for _, i := range sel {
+				_ACCUMULATE_VARIANCE(a, nulls, i, false)
+			}
+		} else {
+			col = col[:inputLen]
+			for i := range col {
+				_ACCUMULATE_VARIANCE(a, nulls, i, false)
+			}
+		}
+	}
+}
+
+func (a *variance_TYPEAgg) HandleEmptyInputScalar() {
+	a.scratch.nulls.SetNull(0)
+}
+
+// {{end}}
+
+// {{/*
+// _ACCUMULATE_VARIANCE updates the running mean and sum of squared differences
+// of the current group using the value of the ith row. If this is the first
+// row of a new group, then the result is computed for the current group. If
+// not enough non-nulls have been found for the current group, then the output
+// for the current group is set to null.
+func _ACCUMULATE_VARIANCE(a *_AGG_TYPEAgg, nulls *coldata.Nulls, i int, _HAS_NULLS bool) { // */}}
+
+	// {{define "accumulateVariance"}}
+	if a.groups[i] {
+		// If we encounter a new group, and we haven't found enough non-nulls for
+		// the current group, the output for this group should be null. If
+		// a.scratch.curIdx is negative, it means that this is the first group.
+		if a.scratch.curIdx >= 0 {
+			if a.scratch.curCount < a.kind.minCount() {
+				a.scratch.nulls.SetNull(uint16(a.scratch.curIdx))
+			} else {
+				// {{with .Global}}
+				_ASSIGN_RESULT("a.scratch.vec[a.scratch.curIdx]")
+				// {{end}}
+			}
+		}
+		a.scratch.curIdx++
+		a.scratch.curCount = 0
+		// {{with .Global}}
+		a.scratch.curMean = zero_TYPEColumn[0]
+		a.scratch.curSqrDiff = zero_TYPEColumn[0]
+		// {{end}}
+	}
+	var isNull bool
+	// {{ if .HasNulls }}
+	isNull = nulls.NullAt(uint16(i))
+	// {{ else }}
+	isNull = false
+	// {{ end }}
+	if !isNull {
+		// {{with .Global}}
+		_UPDATE_SQR_DIFF("col[i]")
+		// {{end}}
+	}
+	// {{end}}
+
+	// {{/*
+} // */}}
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
 	// */}}
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // {{/*
@@ -48,6 +49,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // Dummy import to pull in "tree" package.
 var _ tree.Datum
 
@@ -71,6 +75,11 @@ type vecComparator interface {
 	// 0, or 1.
 	compare(vecIdx1, vecIdx2 int, valIdx1, valIdx2 uint16) int
 
+	// isNull returns whether the value at valIdx in the vector at vecIdx is
+	// NULL. compare treats NULLs as smaller than all of the other values, so
+	// the callers use it to place the NULLs differently.
+	isNull(vecIdx int, valIdx uint16) bool
+
 	// set sets the value of the vector at dstVecIdx at index dstValIdx to the value
 	// at the vector at srcVecIdx at index srcValIdx.
 	// NOTE: whenever set is used, the caller is responsible for updating the
@@ -104,6 +113,10 @@ func (c *_TYPEVecComparator) compare(vecIdx1, vecIdx2 int, valIdx1, valIdx2 uint
 	return cmp
 }
 
+func (c *_TYPEVecComparator) isNull(vecIdx int, valIdx uint16) bool {
+	return c.nulls[vecIdx].MaybeHasNulls() && c.nulls[vecIdx].NullAt(valIdx)
+}
+
 func (c *_TYPEVecComparator) setVec(idx int, vec coldata.Vec) {
 	c.vecs[idx] = vec._TYPE()
 	c.nulls[idx] = vec.Nulls()
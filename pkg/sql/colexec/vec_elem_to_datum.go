@@ -14,10 +14,14 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
@@ -34,6 +38,9 @@ func PhysicalTypeColElemToDatum(
 			return tree.DNull
 		}
 	}
+	if col.Type() == coltypes.Datum {
+		return col.Datum()[rowIdx].(tree.Datum)
+	}
 	switch ct.Family() {
 	case types.BoolFamily:
 		if col.Bool()[rowIdx] {
@@ -71,10 +78,39 @@ func PhysicalTypeColElemToDatum(
 			execerror.VectorizedInternalPanic(err)
 		}
 		return da.NewDUuid(tree.DUuid{UUID: id})
+	case types.INetFamily:
+		var ipAddr ipaddr.IPAddr
+		if _, err := ipAddr.FromBuffer(col.Bytes().Get(int(rowIdx))); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return da.NewDIPAddr(tree.DIPAddr{IPAddr: ipAddr})
+	case types.BitFamily:
+		ba, err := typeconv.DecodeBitArray(col.Bytes().Get(int(rowIdx)))
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return da.NewDBitArray(tree.DBitArray{BitArray: ba})
+	case types.JsonFamily:
+		// The encoding is decoded lazily, so we need to copy the bytes since the
+		// vector might be reused.
+		b := col.Bytes().Get(int(rowIdx))
+		j, err := json.FromEncoding(append([]byte(nil), b...))
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return da.NewDJSON(tree.DJSON{JSON: j})
+	case types.ArrayFamily:
+		d, err := typeconv.DecodeArray(&da, ct.ArrayContents(), col.Bytes().Get(int(rowIdx)))
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return d
 	case types.TimestampFamily:
 		return da.NewDTimestamp(tree.DTimestamp{Time: col.Timestamp()[rowIdx]})
 	case types.TimestampTZFamily:
 		return da.NewDTimestampTZ(tree.DTimestampTZ{Time: col.Timestamp()[rowIdx]})
+	case types.IntervalFamily:
+		return da.NewDInterval(tree.DInterval{Duration: col.Interval()[rowIdx]})
 	default:
 		execerror.VectorizedInternalPanic(fmt.Sprintf("Unsupported column type %s", ct.String()))
 		// This code is unreachable, but the compiler cannot infer that.
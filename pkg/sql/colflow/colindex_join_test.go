@@ -0,0 +1,209 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Note that this file is not in pkg/sql/colexec because it instantiates a
+// server, and if it were moved into sql/colexec, that would create a cycle
+// with pkg/server.
+
+package colflow_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+// batchSizeSetter is implemented by both the row-based and the vectorized
+// index joiners.
+type batchSizeSetter interface {
+	SetBatchSize(batchSize int)
+}
+
+// TestColIndexJoinAgainstProcessor checks that the vectorized index joiner
+// returns the same rows as the row-based one. The input is split into small
+// batches and the number of spans that are looked up at once is varied, so
+// that the rows are looked up with several scans.
+func TestColIndexJoinAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// Every third row has a NULL b and every fifth row a NULL c.
+	const numRows = 100
+	bFn := func(row int) tree.Datum {
+		if row%3 == 0 {
+			return tree.DNull
+		}
+		return tree.NewDInt(tree.DInt(row % 17))
+	}
+	cFn := func(row int) tree.Datum {
+		if row%5 == 0 {
+			return tree.DNull
+		}
+		return sqlutils.RowEnglishFn(row)
+	}
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT PRIMARY KEY, b INT, c STRING, INDEX b_idx (b)",
+		numRows,
+		sqlutils.ToRowFn(sqlutils.RowIdxFn, bFn, cFn))
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+
+	// The input contains the primary keys of the rows in a random order as
+	// well as some keys that don't exist.
+	rng, _ := randutil.NewPseudoRand()
+	var input sqlbase.EncDatumRows
+	for _, i := range rng.Perm(numRows + 10) {
+		input = append(input, sqlbase.EncDatumRow{sqlbase.IntEncDatum(i + 1)})
+	}
+	inputTypes := sqlbase.OneIntCol
+
+	testCases := []struct {
+		description string
+		post        execinfrapb.PostProcessSpec
+		outputTypes []types.T
+	}{
+		{
+			description: "all columns",
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1, 2},
+			},
+			outputTypes: []types.T{*types.Int, *types.Int, *types.String},
+		},
+		{
+			description: "filter evaluated by the fetcher",
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@2 < 8"},
+				Projection:    true,
+				OutputColumns: []uint32{0, 2},
+			},
+			outputTypes: []types.T{*types.Int, *types.String},
+		},
+		{
+			description: "filter on nullable columns",
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@2 IS NULL OR @3 > 'f'"},
+				Projection:    true,
+				OutputColumns: []uint32{1, 2},
+			},
+			outputTypes: []types.T{*types.Int, *types.String},
+		},
+	}
+
+	defer func(batchSize uint16) { coldata.SetBatchSizeForTests(batchSize) }(coldata.BatchSize())
+	coldata.SetBatchSizeForTests(coldata.MinBatchSize)
+
+	for _, tc := range testCases {
+		for _, batchSize := range []int{1, 5, 10000} {
+			t.Run(fmt.Sprintf("%s/batchSize=%d", tc.description, batchSize), func(t *testing.T) {
+				evalCtx := tree.MakeTestingEvalContext(s.ClusterSettings())
+				defer evalCtx.Stop(ctx)
+				flowCtx := execinfra.FlowCtx{
+					EvalCtx: &evalCtx,
+					Cfg:     &execinfra.ServerConfig{Settings: s.ClusterSettings()},
+					Txn:     client.NewTxn(ctx, s.DB(), s.NodeID()),
+					NodeID:  s.NodeID(),
+				}
+				spec := execinfrapb.ProcessorSpec{
+					Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+					Core: execinfrapb.ProcessorCoreUnion{
+						JoinReader: &execinfrapb.JoinReaderSpec{Table: *tableDesc},
+					},
+					Post: tc.post,
+				}
+
+				proc, err := rowexec.NewProcessor(
+					ctx, &flowCtx, 0 /* processorID */, &spec.Core, &spec.Post,
+					[]execinfra.RowSource{execinfra.NewRepeatableRowSource(inputTypes, input)},
+					[]execinfra.RowReceiver{nil}, nil, /* localProcessors */
+				)
+				require.NoError(t, err)
+				proc.(batchSizeSetter).SetBatchSize(batchSize)
+				procRows := collectRows(ctx, t, proc.(execinfra.RowSource), tc.outputTypes)
+
+				columnarizer, err := colexec.NewColumnarizer(
+					ctx, testAllocator, &flowCtx, 1, /* processorID */
+					execinfra.NewRepeatableRowSource(inputTypes, input),
+				)
+				require.NoError(t, err)
+				result, err := colexec.NewColOperator(ctx, &flowCtx, colexec.NewColOperatorArgs{
+					Spec:                               &spec,
+					Inputs:                             []colexec.Operator{columnarizer},
+					StreamingMemAccount:                testMemAcc,
+					UseStreamingMemAccountForBuffering: true,
+					ProcessorConstructor:               rowexec.NewProcessor,
+				})
+				require.NoError(t, err)
+				// The index joiner is a metadata source, so it can be found even if
+				// it is wrapped by the post-processing operators.
+				var found bool
+				for _, src := range result.MetadataSources {
+					if ij, ok := src.(batchSizeSetter); ok {
+						ij.SetBatchSize(batchSize)
+						found = true
+					}
+				}
+				require.True(t, found)
+				mat, err := colexec.NewMaterializer(
+					&flowCtx, 2 /* processorID */, result.Op, tc.outputTypes,
+					&execinfrapb.PostProcessSpec{}, nil /* output */, result.MetadataSources,
+					nil /* outputStatsToTrace */, nil, /* cancelFlow */
+				)
+				require.NoError(t, err)
+				colOpRows := collectRows(ctx, t, mat, tc.outputTypes)
+
+				require.NotEmpty(t, procRows)
+				require.Equal(t, procRows, colOpRows)
+			})
+		}
+	}
+}
+
+// collectRows runs source to completion and returns its rows, formatted and
+// sorted. It fails the test if source returns an error.
+func collectRows(
+	ctx context.Context, t *testing.T, source execinfra.RowSource, typs []types.T,
+) []string {
+	source.Start(ctx)
+	defer source.ConsumerClosed()
+	var rows []string
+	for {
+		row, meta := source.Next()
+		if meta != nil {
+			require.NoError(t, meta.Err)
+			continue
+		}
+		if row == nil {
+			break
+		}
+		rows = append(rows, row.String(typs))
+	}
+	sort.Strings(rows)
+	return rows
+}
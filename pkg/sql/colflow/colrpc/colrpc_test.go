@@ -509,6 +509,158 @@ func TestOutboxInboxMetadataPropagation(t *testing.T) {
 	}
 }
 
+// compressionRecordingFlowStreamServer records the codec of every data message
+// received over the wrapped stream.
+type compressionRecordingFlowStreamServer struct {
+	flowStreamServer
+	compressions *[]execinfrapb.StreamCompression
+}
+
+func (s compressionRecordingFlowStreamServer) Recv() (*execinfrapb.ProducerMessage, error) {
+	m, err := s.flowStreamServer.Recv()
+	if err == nil && len(m.Data.RawBytes) > 0 {
+		*s.compressions = append(*s.compressions, m.Data.Compression)
+	}
+	return m, err
+}
+
+// TestOutboxInboxCompression verifies that the Outbox compresses batches with
+// the codec negotiated in the consumer's handshake and that the Inbox
+// decompresses them.
+func TestOutboxInboxCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	_, mockServer, addr, err := execinfrapb.StartMockDistSQLServer(
+		hlc.NewClock(hlc.UnixNano, time.Nanosecond), stopper, execinfra.StaticNodeID,
+	)
+	require.NoError(t, err)
+
+	conn, err := grpc.Dial(addr.String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+
+	const numBatches = 4
+	typs := []coltypes.T{coltypes.Int64}
+
+	testCases := []struct {
+		name string
+		// supported are the codecs advertised by the consumer.
+		supported []execinfrapb.StreamCompression
+		expected  execinfrapb.StreamCompression
+	}{
+		{
+			name:      "Supported",
+			supported: execinfrapb.SupportedStreamCompressions,
+			expected:  execinfrapb.StreamCompression_SNAPPY,
+		},
+		{
+			// Unsupported models an older consumer that doesn't advertise any
+			// codecs.
+			name:     "Unsupported",
+			expected: execinfrapb.StreamCompression_NONE,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := execinfrapb.NewDistSQLClient(conn)
+			clientStream, err := client.FlowStream(ctx)
+			require.NoError(t, err)
+
+			serverStreamNotification := <-mockServer.InboundStreams
+			serverStream := serverStreamNotification.Stream
+			require.NoError(t, serverStream.Send(&execinfrapb.ConsumerSignal{
+				Handshake: &execinfrapb.ConsumerHandshake{
+					ConsumerScheduled:     true,
+					SupportedCompressions: tc.supported,
+				},
+			}))
+
+			var (
+				outbox      *Outbox
+				numReturned int
+			)
+			input := &colexec.CallbackOperator{NextCb: func(context.Context) coldata.Batch {
+				if numReturned == numBatches {
+					return coldata.ZeroBatch
+				}
+				if numReturned == 0 {
+					// Wait for the handshake to be processed so that all the batches
+					// are sent with the expected codec.
+					testutils.SucceedsSoon(t, func() error {
+						if c := execinfrapb.StreamCompression(atomic.LoadInt32(&outbox.compression)); c != tc.expected {
+							return fmt.Errorf("expected codec %s, found %s", tc.expected, c)
+						}
+						return nil
+					})
+				}
+				b := testAllocator.NewMemBatch(typs)
+				col := b.ColVec(0).Int64()
+				for i := 0; i < int(coldata.BatchSize()); i++ {
+					col[i] = int64(numReturned)
+				}
+				b.SetLength(coldata.BatchSize())
+				numReturned++
+				return b
+			}}
+
+			outboxMemAcc := testMemMonitor.MakeBoundAccount()
+			defer outboxMemAcc.Close(ctx)
+			outbox, err = NewOutbox(
+				colexec.NewAllocator(ctx, &outboxMemAcc), input, typs, nil,
+			)
+			require.NoError(t, err)
+			outbox.SetPreferredCompression(execinfrapb.StreamCompression_SNAPPY)
+
+			inboxMemAcc := testMemMonitor.MakeBoundAccount()
+			defer inboxMemAcc.Close(ctx)
+			inbox, err := NewInbox(
+				colexec.NewAllocator(ctx, &inboxMemAcc), typs, execinfrapb.StreamID(0),
+			)
+			require.NoError(t, err)
+
+			var compressions []execinfrapb.StreamCompression
+			streamHandlerErrCh := handleStream(
+				serverStream.Context(),
+				inbox,
+				compressionRecordingFlowStreamServer{flowStreamServer: serverStream, compressions: &compressions},
+				func() { close(serverStreamNotification.Donec) },
+			)
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				outbox.runWithStream(ctx, clientStream, nil /* cancelFn */)
+				wg.Done()
+			}()
+
+			for batchNum := 0; ; batchNum++ {
+				b := inbox.Next(ctx)
+				if b.Length() == 0 {
+					require.Equal(t, numBatches, batchNum)
+					break
+				}
+				require.Equal(t, coldata.BatchSize(), b.Length())
+				for _, v := range b.ColVec(0).Int64()[:b.Length()] {
+					require.Equal(t, int64(batchNum), v)
+				}
+			}
+
+			wg.Wait()
+			require.NoError(t, <-streamHandlerErrCh)
+
+			require.Len(t, compressions, numBatches)
+			for _, c := range compressions {
+				require.Equal(t, tc.expected, c)
+			}
+		})
+	}
+}
+
 func BenchmarkOutboxInbox(b *testing.B) {
 	ctx := context.Background()
 	stopper := stop.NewStopper()
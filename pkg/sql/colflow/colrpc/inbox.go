@@ -110,6 +110,10 @@ type Inbox struct {
 	scratch struct {
 		data []*array.Data
 		b    coldata.Batch
+		// decompressed is the buffer that compressed data is decompressed into.
+		// It is reused across calls to Next since the returned batch is only
+		// valid until the next call.
+		decompressed []byte
 	}
 }
 
@@ -323,8 +327,15 @@ func (i *Inbox) Next(ctx context.Context) coldata.Batch {
 			// Protect against Deserialization panics by skipping empty messages.
 			continue
 		}
+		rawBytes, err := execinfrapb.DecompressRawBytes(i.scratch.decompressed, &m.Data)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		if m.Data.Compression != execinfrapb.StreamCompression_NONE {
+			i.scratch.decompressed = rawBytes
+		}
 		i.scratch.data = i.scratch.data[:0]
-		if err := i.serializer.Deserialize(&i.scratch.data, m.Data.RawBytes); err != nil {
+		if err := i.serializer.Deserialize(&i.scratch.data, rawBytes); err != nil {
 			execerror.VectorizedInternalPanic(err)
 		}
 		if err := i.converter.ArrowToBatch(i.scratch.data, i.scratch.b); err != nil {
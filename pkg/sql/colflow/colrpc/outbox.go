@@ -60,9 +60,17 @@ type Outbox struct {
 	draining        uint32
 	metadataSources []execinfrapb.MetadataSource
 
+	// preferredCompression is the codec that the Outbox compresses batches with
+	// if the consumer supports it.
+	preferredCompression execinfrapb.StreamCompression
+	// compression is the codec negotiated with the consumer in its handshake.
+	// It must be accessed atomically.
+	compression int32
+
 	scratch struct {
-		buf *bytes.Buffer
-		msg *execinfrapb.ProducerMessage
+		buf        *bytes.Buffer
+		compressed []byte
+		msg        *execinfrapb.ProducerMessage
 	}
 
 	// A copy of Run's caller ctx, with no StreamID tag.
@@ -99,6 +107,15 @@ func NewOutbox(
 	return o, nil
 }
 
+// SetPreferredCompression sets the codec that the Outbox compresses the
+// batches it sends with. Batches are only compressed once the consumer has
+// advertised support for the codec in its handshake, so batches sent before
+// the handshake is received (or to consumers that don't support the codec) are
+// sent uncompressed. It must be called before Run.
+func (o *Outbox) SetPreferredCompression(c execinfrapb.StreamCompression) {
+	o.preferredCompression = c
+}
+
 // Run starts an outbox by connecting to the provided node and pushing
 // coldata.Batches over the stream after sending a header with the provided flow
 // and stream ID. Note that an extra goroutine is spawned so that Recv may be
@@ -242,7 +259,18 @@ func (o *Outbox) sendBatches(
 			log.Errorf(ctx, "Outbox Serialize data error: %+v", err)
 			return false, err
 		}
-		o.scratch.msg.Data.RawBytes = o.scratch.buf.Bytes()
+		rawBytes := o.scratch.buf.Bytes()
+		compression := execinfrapb.StreamCompression(atomic.LoadInt32(&o.compression))
+		if compression != execinfrapb.StreamCompression_NONE {
+			o.scratch.compressed, err = execinfrapb.CompressRawBytes(o.scratch.compressed, rawBytes, compression)
+			if err != nil {
+				log.Errorf(ctx, "Outbox compression error: %+v", err)
+				return false, err
+			}
+			rawBytes = o.scratch.compressed
+		}
+		o.scratch.msg.Data.RawBytes = rawBytes
+		o.scratch.msg.Data.Compression = compression
 
 		// o.scratch.msg can be reused as soon as Send returns since it returns as
 		// soon as the message is written to the control buffer. The message is
@@ -265,7 +293,14 @@ func (o *Outbox) sendMetadata(ctx context.Context, stream flowStreamClient, errT
 		)
 	}
 	for _, src := range o.metadataSources {
-		for _, meta := range src.DrainMeta(ctx) {
+		var metas []execinfrapb.ProducerMetadata
+		if err := execerror.CatchVectorizedRuntimeError(func() {
+			metas = src.DrainMeta(ctx)
+		}); err != nil {
+			log.Warningf(ctx, "Outbox DrainMeta error: %+v", err)
+			metas = append(metas, execinfrapb.ProducerMetadata{Err: err})
+		}
+		for _, meta := range metas {
 			msg.Data.Metadata = append(msg.Data.Metadata, execinfrapb.LocalMetaToRemoteProducerMeta(ctx, meta))
 		}
 	}
@@ -280,7 +315,7 @@ func (o *Outbox) sendMetadata(ctx context.Context, stream flowStreamClient, errT
 func (o *Outbox) runWithStream(
 	ctx context.Context, stream flowStreamClient, cancelFn context.CancelFunc,
 ) {
-	o.Input().Init()
+	initErr := execerror.CatchVectorizedRuntimeError(o.Input().Init)
 
 	waitCh := make(chan struct{})
 	go func() {
@@ -297,6 +332,10 @@ func (o *Outbox) runWithStream(
 			switch {
 			case msg.Handshake != nil:
 				log.VEventf(ctx, 2, "Outbox received handshake: %v", msg.Handshake)
+				if o.preferredCompression != execinfrapb.StreamCompression_NONE {
+					compression := execinfrapb.NegotiateStreamCompression(o.preferredCompression, msg.Handshake)
+					atomic.StoreInt32(&o.compression, int32(compression))
+				}
 			case msg.DrainRequest != nil:
 				o.moveToDraining(ctx)
 			}
@@ -304,7 +343,12 @@ func (o *Outbox) runWithStream(
 		close(waitCh)
 	}()
 
-	terminatedGracefully, errToSend := o.sendBatches(ctx, stream, cancelFn)
+	terminatedGracefully, errToSend := false, initErr
+	if initErr != nil {
+		log.Warningf(ctx, "Outbox Init error: %+v", initErr)
+	} else {
+		terminatedGracefully, errToSend = o.sendBatches(ctx, stream, cancelFn)
+	}
 	if terminatedGracefully || errToSend != nil {
 		o.moveToDraining(ctx)
 		if err := o.sendMetadata(ctx, stream, errToSend); err != nil {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
@@ -28,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -51,6 +53,9 @@ type vectorizedFlow struct {
 	// bufferingMemAccounts are the memory accounts that are tracking the dynamic
 	// memory usage of the buffering components.
 	bufferingMemAccounts []*mon.BoundAccount
+
+	// toClose are the components that need to be closed once the flow is done.
+	toClose []colexec.IdempotentCloser
 }
 
 var _ flowinfra.Flow = &vectorizedFlow{}
@@ -98,12 +103,14 @@ func (f *vectorizedFlow) Setup(
 		f.streamingMemAccounts = append(f.streamingMemAccounts, creator.streamingMemAccounts...)
 		f.bufferingMemMonitors = append(f.bufferingMemMonitors, creator.bufferingMemMonitors...)
 		f.bufferingMemAccounts = append(f.bufferingMemAccounts, creator.bufferingMemAccounts...)
+		f.toClose = append(f.toClose, creator.toClose...)
 		log.VEventf(ctx, 1, "vectorized flow setup succeeded")
 		return ctx, nil
 	}
 	// It is (theoretically) possible that some of the memory monitoring
 	// infrastructure was created even in case of an error, and we need to clean
 	// that up.
+	closeAll(ctx, creator.toClose)
 	for _, memAcc := range creator.streamingMemAccounts {
 		memAcc.Close(ctx)
 	}
@@ -135,6 +142,7 @@ func (f *vectorizedFlow) Release() {
 
 // Cleanup is part of the flowinfra.Flow interface.
 func (f *vectorizedFlow) Cleanup(ctx context.Context) {
+	closeAll(ctx, f.toClose)
 	// This cleans up all the memory monitoring of the vectorized flow.
 	for _, memAcc := range f.streamingMemAccounts {
 		memAcc.Close(ctx)
@@ -149,15 +157,27 @@ func (f *vectorizedFlow) Cleanup(ctx context.Context) {
 	f.Release()
 }
 
+// closeAll closes all the given components, logging the errors.
+func closeAll(ctx context.Context, toClose []colexec.IdempotentCloser) {
+	for _, c := range toClose {
+		if err := c.IdempotentClose(ctx); err != nil {
+			log.Warningf(ctx, "error closing %T: %v", c, err)
+		}
+	}
+}
+
 // wrapWithVectorizedStatsCollector creates a new exec.VectorizedStatsCollector
 // that wraps op and connects the newly created wrapper with those
 // corresponding to operators in inputs (the latter must have already been
 // wrapped).
 func wrapWithVectorizedStatsCollector(
-	op colexec.Operator, inputs []colexec.Operator, pspec *execinfrapb.ProcessorSpec,
+	op colexec.Operator,
+	inputs []colexec.Operator,
+	pspec *execinfrapb.ProcessorSpec,
+	memMonitors []*mon.BytesMonitor,
 ) (*colexec.VectorizedStatsCollector, error) {
 	inputWatch := timeutil.NewStopWatch()
-	vsc := colexec.NewVectorizedStatsCollector(op, pspec.ProcessorID, len(inputs) == 0, inputWatch)
+	vsc := colexec.NewVectorizedStatsCollector(op, pspec.ProcessorID, len(inputs) == 0, inputWatch, memMonitors)
 	for _, input := range inputs {
 		sc, ok := input.(*colexec.VectorizedStatsCollector)
 		if !ok {
@@ -195,6 +215,13 @@ func finishVectorizedStatsCollectors(
 		if deterministicStats {
 			vsc.VectorizedStats.Time = 0
 		}
+		if streamID, ok := vsc.StreamID(); ok {
+			_, sp := tracing.ChildSpan(ctx, fmt.Sprintf("inbox for stream %d", streamID))
+			sp.SetTag(execinfrapb.StreamIDTagKey, streamID)
+			tracing.SetSpanStats(sp, &vsc.VectorizedStats)
+			sp.Finish()
+			continue
+		}
 		if vsc.ID < 0 {
 			// Ignore stats collectors not associated with a processor.
 			continue
@@ -226,6 +253,19 @@ type flowCreatorHelper interface {
 	getCancelFlowFn() context.CancelFunc
 }
 
+// streamCompressionEnabled controls whether the Outboxes compress the batches
+// they send. The compression only kicks in once the consumer has advertised
+// support for the codec in its handshake.
+var streamCompressionEnabled = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting(
+		"sql.distsql.vectorized_stream_compression.enabled",
+		"if set, batches sent between nodes by vectorized flows are compressed with snappy",
+		false,
+	)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+
 // opDAGWithMetaSources is a helper struct that stores an operator DAG as well
 // as the metadataSources in this DAG that need to be drained.
 type opDAGWithMetaSources struct {
@@ -298,6 +338,14 @@ type vectorizedFlowCreator struct {
 	// bufferingMemAccounts contains all memory accounts of the buffering
 	// components in the vectorized flow.
 	bufferingMemAccounts []*mon.BoundAccount
+	// toClose contains all the components that need to be closed once the
+	// vectorized flow is done.
+	toClose []colexec.IdempotentCloser
+	// numNativeProcessors and numWrappedProcessors count the processors in the
+	// flow that have been planned as columnar operators and those that have
+	// been wrapped into the vectorized flow, respectively.
+	numNativeProcessors  int
+	numWrappedProcessors int
 }
 
 func newVectorizedFlowCreator(
@@ -351,6 +399,11 @@ func (s *vectorizedFlowCreator) setupRemoteOutputStream(
 	if err != nil {
 		return nil, err
 	}
+	if streamCompressionEnabled.GetWithOverrides(
+		&flowCtx.EvalCtx.Settings.SV, flowCtx.SettingOverrides(),
+	) {
+		outbox.SetPreferredCompression(execinfrapb.StreamCompression_SNAPPY)
+	}
 	atomic.AddInt32(&s.numOutboxes, 1)
 	run := func(ctx context.Context, cancelFn context.CancelFunc) {
 		outbox.Run(ctx, s.nodeDialer, stream.TargetNodeID, s.flowID, stream.StreamID, cancelFn)
@@ -371,41 +424,69 @@ func (s *vectorizedFlowCreator) setupRemoteOutputStream(
 	return outbox, nil
 }
 
-// setupRouter sets up a vectorized hash router according to the output router
-// spec. If the outputs are local, these are added to s.streamIDToInputOp to be
-// used as inputs in further planning. metadataSourcesQueue is passed along to
-// any outboxes created to be drained, or stored in streamIDToInputOp for any
-// local outputs to pass that responsibility along. In any case,
-// metadataSourcesQueue will always be fully consumed.
-// NOTE: This method supports only BY_HASH routers. Callers should handle
-// PASS_THROUGH routers separately.
+// setupRouter sets up a vectorized hash or range router according to the
+// output router spec. If the outputs are local, these are added to
+// s.streamIDToInputOp to be used as inputs in further planning.
+// metadataSourcesQueue is passed along to any outboxes created to be drained,
+// or stored in streamIDToInputOp for any local outputs to pass that
+// responsibility along. In any case, metadataSourcesQueue will always be fully
+// consumed.
+// NOTE: This method supports only BY_HASH and BY_RANGE routers. Callers should
+// handle PASS_THROUGH routers separately.
 func (s *vectorizedFlowCreator) setupRouter(
 	ctx context.Context,
 	flowCtx *execinfra.FlowCtx,
 	input colexec.Operator,
 	outputTyps []coltypes.T,
+	outputColumnTypes []types.T,
 	output *execinfrapb.OutputRouterSpec,
 	metadataSourcesQueue []execinfrapb.MetadataSource,
 ) error {
-	if output.Type != execinfrapb.OutputRouterSpec_BY_HASH {
+	if output.Type != execinfrapb.OutputRouterSpec_BY_HASH &&
+		output.Type != execinfrapb.OutputRouterSpec_BY_RANGE {
 		return errors.Errorf("vectorized output router type %s unsupported", output.Type)
 	}
 
-	// TODO(asubiotto): Change hashRouter's hashCols to be uint32s.
-	hashCols := make([]int, len(output.HashColumns))
-	for i := range hashCols {
-		hashCols[i] = int(output.HashColumns[i])
-	}
-	hashRouterMemMonitor := execinfra.NewLimitedMonitor(
-		ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "hash-router-limited",
+	// Every output buffers its batches separately, so every output gets its
+	// own memory account.
+	routerMemMonitor := execinfra.NewLimitedMonitor(
+		ctx, flowCtx.EvalCtx.Mon, flowCtx, "router-limited",
 	)
-	hashRouterMemAccount := hashRouterMemMonitor.MakeBoundAccount()
-	s.bufferingMemMonitors = append(s.bufferingMemMonitors, hashRouterMemMonitor)
-	s.bufferingMemAccounts = append(s.bufferingMemAccounts, &hashRouterMemAccount)
-	router, outputs := colexec.NewHashRouter(
-		colexec.NewAllocator(ctx, &hashRouterMemAccount), input, outputTyps,
-		hashCols, len(output.Streams),
+	s.bufferingMemMonitors = append(s.bufferingMemMonitors, routerMemMonitor)
+	allocators := make([]*colexec.Allocator, len(output.Streams))
+	for i := range allocators {
+		acc := routerMemMonitor.MakeBoundAccount()
+		s.bufferingMemAccounts = append(s.bufferingMemAccounts, &acc)
+		allocators[i] = colexec.NewAllocator(ctx, &acc)
+	}
+
+	var (
+		router interface {
+			execinfra.OpNode
+			execinfrapb.MetadataSource
+			Run(context.Context)
+		}
+		outputs []colexec.Operator
 	)
+	if output.Type == execinfrapb.OutputRouterSpec_BY_HASH {
+		// TODO(asubiotto): Change hashRouter's hashCols to be uint32s.
+		hashCols := make([]int, len(output.HashColumns))
+		for i := range hashCols {
+			hashCols[i] = int(output.HashColumns[i])
+			if outputTyps[hashCols[i]] == coltypes.Datum {
+				return errors.Errorf("hash routing on a column of %s type is unsupported", outputTyps[hashCols[i]])
+			}
+		}
+		router, outputs = colexec.NewHashRouter(allocators, input, outputTyps, hashCols)
+	} else {
+		var err error
+		router, outputs, err = colexec.NewRangeRouter(
+			allocators, input, outputTyps, outputColumnTypes, output.RangeRouterSpec,
+		)
+		if err != nil {
+			return err
+		}
+	}
 	runRouter := func(ctx context.Context, _ context.CancelFunc) {
 		router.Run(ctx)
 	}
@@ -434,7 +515,7 @@ func (s *vectorizedFlowCreator) setupRouter(
 				// information (e.g. output stall time).
 				var err error
 				op, err = wrapWithVectorizedStatsCollector(
-					op, nil /* inputs */, &execinfrapb.ProcessorSpec{ProcessorID: -1},
+					op, nil /* inputs */, &execinfrapb.ProcessorSpec{ProcessorID: -1}, nil, /* memMonitors */
 				)
 				if err != nil {
 					return err
@@ -481,10 +562,7 @@ func (s *vectorizedFlowCreator) setupInput(
 			if err := s.checkInboundStreamID(inputStream.StreamID); err != nil {
 				return nil, nil, err
 			}
-			typs, err := typeconv.FromColumnTypes(input.ColumnTypes)
-			if err != nil {
-				return nil, nil, err
-			}
+			typs := typeconv.FromColumnTypes(input.ColumnTypes)
 			inbox, err := s.remoteComponentCreator.newInbox(
 				colexec.NewAllocator(ctx, s.newStreamingMemAccount(flowCtx)),
 				typs, inputStream.StreamID,
@@ -496,19 +574,21 @@ func (s *vectorizedFlowCreator) setupInput(
 			metaSources = append(metaSources, inbox)
 			op = inbox
 			if s.recordingStats {
-				op, err = wrapWithVectorizedStatsCollector(
+				vsc, err := wrapWithVectorizedStatsCollector(
 					inbox,
 					nil, /* inputs */
-					// TODO(asubiotto): Vectorized stats collectors currently expect a
-					// processor ID. These stats will not be shown until we extend stats
-					// collectors to take in a stream ID.
 					&execinfrapb.ProcessorSpec{
 						ProcessorID: -1,
 					},
+					nil, /* memMonitors */
 				)
 				if err != nil {
 					return nil, nil, err
 				}
+				// The stats of the Inbox are shown on the stream it reads from.
+				vsc.SetStreamID(inputStream.StreamID)
+				s.vectorizedStatsCollectorsQueue = append(s.vectorizedStatsCollectorsQueue, vsc)
+				op = vsc
 			}
 			inputStreamOps = append(inputStreamOps, op)
 		default:
@@ -518,17 +598,14 @@ func (s *vectorizedFlowCreator) setupInput(
 	op = inputStreamOps[0]
 	if len(inputStreamOps) > 1 {
 		statsInputs := inputStreamOps
-		typs, err := typeconv.FromColumnTypes(input.ColumnTypes)
-		if err != nil {
-			return nil, nil, err
-		}
+		typs := typeconv.FromColumnTypes(input.ColumnTypes)
 		if input.Type == execinfrapb.InputSyncSpec_ORDERED {
 			op = colexec.NewOrderedSynchronizer(
-				colexec.NewAllocator(ctx, s.newStreamingMemAccount(flowCtx)),
+				colexec.NewAllocator(ctx, s.newStreamingMemAccount(flowCtx)), flowCtx.NewEvalCtx(),
 				inputStreamOps, typs, execinfrapb.ConvertToColumnOrdering(input.Ordering),
 			)
 		} else {
-			if opt == flowinfra.FuseAggressively {
+			if input.Type == execinfrapb.InputSyncSpec_SERIAL_UNORDERED || opt == flowinfra.FuseAggressively {
 				op = colexec.NewSerialUnorderedSynchronizer(inputStreamOps, typs)
 			} else {
 				op = colexec.NewParallelUnorderedSynchronizer(inputStreamOps, typs, s.waitGroup)
@@ -543,7 +620,9 @@ func (s *vectorizedFlowCreator) setupInput(
 			// TODO(asubiotto): Once we have IDs for synchronizers, plumb them into
 			// this stats collector to display stats.
 			var err error
-			op, err = wrapWithVectorizedStatsCollector(op, statsInputs, &execinfrapb.ProcessorSpec{ProcessorID: -1})
+			op, err = wrapWithVectorizedStatsCollector(
+				op, statsInputs, &execinfrapb.ProcessorSpec{ProcessorID: -1}, nil, /* memMonitors */
+			)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -564,6 +643,7 @@ func (s *vectorizedFlowCreator) setupOutput(
 	pspec *execinfrapb.ProcessorSpec,
 	op colexec.Operator,
 	opOutputTypes []coltypes.T,
+	opOutputColumnTypes []types.T,
 	metadataSourcesQueue []execinfrapb.MetadataSource,
 ) error {
 	output := &pspec.Output[0]
@@ -573,6 +653,7 @@ func (s *vectorizedFlowCreator) setupOutput(
 			flowCtx,
 			op,
 			opOutputTypes,
+			opOutputColumnTypes,
 			output,
 			// Pass in a copy of the queue to reset metadataSourcesQueue for
 			// further appends without overwriting.
@@ -623,6 +704,16 @@ func (s *vectorizedFlowCreator) setupOutput(
 		}
 		// Make the materializer, which will write to the given receiver.
 		columnTypes := s.syncFlowConsumer.Types()
+		batchOutput, ok := s.syncFlowConsumer.(execinfra.BatchReceiver)
+		if ok && batchOutput.AcceptsBatches() {
+			// The batches are pushed to the receiver as they are, so their
+			// selection vectors need to be applied first.
+			op = colexec.NewDeselectorOp(
+				colexec.NewAllocator(ctx, s.newStreamingMemAccount(flowCtx)), op, opOutputTypes,
+			)
+		} else {
+			batchOutput = nil
+		}
 		var outputStatsToTrace func()
 		if s.recordingStats {
 			// Make a copy given that vectorizedStatsCollectorsQueue is reset and
@@ -648,6 +739,9 @@ func (s *vectorizedFlowCreator) setupOutput(
 		if err != nil {
 			return err
 		}
+		if batchOutput != nil {
+			proc.SetBatchOutput(batchOutput)
+		}
 		s.vectorizedStatsCollectorsQueue = s.vectorizedStatsCollectorsQueue[:0]
 		// A materializer is a leaf.
 		s.leaves = append(s.leaves, proc)
@@ -724,6 +818,7 @@ func (s *vectorizedFlowCreator) setupFlow(
 		// them for a proper cleanup.
 		s.bufferingMemMonitors = append(s.bufferingMemMonitors, result.BufferingOpMemMonitors...)
 		s.bufferingMemAccounts = append(s.bufferingMemAccounts, result.BufferingOpMemAccounts...)
+		s.toClose = append(s.toClose, result.ToClose...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to vectorize execution plan")
 		}
@@ -734,6 +829,11 @@ func (s *vectorizedFlowCreator) setupFlow(
 			!result.IsStreaming {
 			return nil, errors.Errorf("non-streaming operator encountered when vectorize=auto")
 		}
+		if result.IsWrapped {
+			s.numWrappedProcessors++
+		} else {
+			s.numNativeProcessors++
+		}
 		// We created a streaming memory account when calling NewColOperator above,
 		// so there is definitely at least one memory account, and it doesn't
 		// matter which one we grow.
@@ -744,28 +844,28 @@ func (s *vectorizedFlowCreator) setupFlow(
 
 		op := result.Op
 		if s.recordingStats {
-			vsc, err := wrapWithVectorizedStatsCollector(op, inputs, pspec)
+			vsc, err := wrapWithVectorizedStatsCollector(op, inputs, pspec, result.BufferingOpMemMonitors)
 			if err != nil {
 				return nil, err
 			}
+			vsc.SetBloomFilterStats(result.BloomFilterStats)
+			vsc.SetKVReaders(result.KVReaders)
 			s.vectorizedStatsCollectorsQueue = append(s.vectorizedStatsCollectorsQueue, vsc)
 			s.procIDs = append(s.procIDs, pspec.ProcessorID)
 			op = vsc
 		}
 
 		if flowCtx.EvalCtx.SessionData.VectorizeMode == sessiondata.VectorizeAuto &&
-			pspec.Output[0].Type == execinfrapb.OutputRouterSpec_BY_HASH {
-			// exec.HashRouter can do unlimited buffering, and it is present in the
-			// flow, so we don't want to run such a flow via the vectorized engine
-			// when vectorize=auto.
-			return nil, errors.Errorf("hash router encountered when vectorize=auto")
-		}
-		opOutputTypes, err := typeconv.FromColumnTypes(result.ColumnTypes)
-		if err != nil {
-			return nil, err
+			(pspec.Output[0].Type == execinfrapb.OutputRouterSpec_BY_HASH ||
+				pspec.Output[0].Type == execinfrapb.OutputRouterSpec_BY_RANGE) {
+			// The vectorized routers can do unlimited buffering, and one is present
+			// in the flow, so we don't want to run such a flow via the vectorized
+			// engine when vectorize=auto.
+			return nil, errors.Errorf("%s router encountered when vectorize=auto", pspec.Output[0].Type)
 		}
+		opOutputTypes := typeconv.FromColumnTypes(result.ColumnTypes)
 		if err = s.setupOutput(
-			ctx, flowCtx, pspec, op, opOutputTypes, metadataSourcesQueue,
+			ctx, flowCtx, pspec, op, opOutputTypes, result.ColumnTypes, metadataSourcesQueue,
 		); err != nil {
 			return nil, err
 		}
@@ -919,11 +1019,15 @@ func (r *noopFlowCreatorHelper) getCancelFlowFn() context.CancelFunc {
 // full flow without running the components asynchronously.
 // It returns a list of the leaf operators of all flows for the purposes of
 // EXPLAIN output.
+// When vectorize=auto, an error is also returned if vectorizing the flow is not
+// expected to be profitable given the estimated number of rows that it reads
+// (see isVectorizationProfitable).
 func SupportsVectorized(
 	ctx context.Context,
 	flowCtx *execinfra.FlowCtx,
 	processorSpecs []execinfrapb.ProcessorSpec,
 	fuseOpt flowinfra.FuseOpt,
+	estimatedRowCount uint64,
 ) (leaves []execinfra.OpNode, err error) {
 	creator := newVectorizedFlowCreator(
 		newNoopFlowCreatorHelper(),
@@ -950,6 +1054,7 @@ func SupportsVectorized(
 	memoryMonitor.Start(ctx, nil, mon.MakeStandaloneBudget(math.MaxInt64))
 	defer memoryMonitor.Stop(ctx)
 	defer func() {
+		closeAll(ctx, creator.toClose)
 		for _, memAcc := range creator.streamingMemAccounts {
 			memAcc.Close(ctx)
 		}
@@ -965,9 +1070,53 @@ func SupportsVectorized(
 	}); vecErr != nil {
 		return leaves, vecErr
 	}
+	if err == nil && flowCtx.EvalCtx.SessionData.VectorizeMode == sessiondata.VectorizeAuto &&
+		!isVectorizationProfitable(
+			estimatedRowCount, flowCtx.EvalCtx.SessionData.VectorizeRowCountThreshold,
+			creator.numNativeProcessors, creator.numWrappedProcessors,
+		) {
+		return leaves, errors.Errorf(
+			"vectorizing the flow with %d wrapped processor(s) is not expected to be "+
+				"profitable for %d rows when vectorize=auto",
+			creator.numWrappedProcessors, estimatedRowCount,
+		)
+	}
 	return leaves, err
 }
 
+// wrappedProcessorCost is the cost of a wrapped processor relative to the
+// benefit of a processor that has a columnar implementation. Every wrapped
+// processor needs to convert its input from batches into rows and its output
+// from rows back into batches, and it doesn't get any faster in exchange.
+const wrappedProcessorCost = 2
+
+// isVectorizationProfitable returns whether running a flow with the given
+// number of native and wrapped processors via the vectorized engine is expected
+// to be faster than running it via the row-by-row engine when the flow reads at
+// most estimatedRowCount rows from a table.
+//
+// The vectorized engine needs to pre-allocate its data structures, so a flow
+// needs to process at least threshold rows to amortize the setup. Every wrapped
+// processor further raises the number of rows that are needed in proportion to
+// the share of the flow it represents, and a flow in which no processor is
+// native is never worth vectorizing. A threshold of zero disables the
+// estimation, and all flows are vectorized.
+func isVectorizationProfitable(
+	estimatedRowCount, threshold uint64, numNative, numWrapped int,
+) bool {
+	if threshold == 0 {
+		return true
+	}
+	if numWrapped == 0 {
+		return estimatedRowCount >= threshold
+	}
+	if numNative == 0 {
+		return false
+	}
+	required := threshold + threshold*wrappedProcessorCost*uint64(numWrapped)/uint64(numNative)
+	return estimatedRowCount >= required
+}
+
 // VectorizeAlwaysException is an object that returns whether or not execution
 // should continue if vectorize=experimental_always and an error occurred when
 // setting up the vectorized flow. Consider the case in which
@@ -229,3 +229,34 @@ func TestDrainOnlyInputDAG(t *testing.T) {
 	// Verify that an outbox was actually created.
 	require.True(t, outboxCreated)
 }
+
+func TestIsVectorizationProfitable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		rowCount, threshold   uint64
+		numNative, numWrapped int
+		expected              bool
+	}{
+		// Without wrapped processors, only the threshold matters.
+		{rowCount: 999, threshold: 1000, numNative: 3, expected: false},
+		{rowCount: 1000, threshold: 1000, numNative: 3, expected: true},
+		// Wrapped processors raise the required row count in proportion to the
+		// share of the flow that they represent.
+		{rowCount: 1000, threshold: 1000, numNative: 2, numWrapped: 1, expected: false},
+		{rowCount: 2000, threshold: 1000, numNative: 2, numWrapped: 1, expected: true},
+		{rowCount: 2000, threshold: 1000, numNative: 1, numWrapped: 1, expected: false},
+		{rowCount: 3000, threshold: 1000, numNative: 1, numWrapped: 1, expected: true},
+		// A flow that is wrapped entirely is never profitable.
+		{rowCount: 1 << 40, threshold: 1000, numWrapped: 1, expected: false},
+		// A zero threshold vectorizes everything.
+		{rowCount: 0, threshold: 0, numWrapped: 1, expected: true},
+		{rowCount: 0, threshold: 0, numNative: 1, numWrapped: 5, expected: true},
+	} {
+		require.Equal(
+			t, tc.expected,
+			isVectorizationProfitable(tc.rowCount, tc.threshold, tc.numNative, tc.numWrapped),
+			"%+v", tc,
+		)
+	}
+}
@@ -253,6 +253,10 @@ type Server struct {
 	// dbCache is a cache for database descriptors, maintained through Gossip
 	// updates.
 	dbCache *databaseCacheHolder
+
+	// planRegressions receives the plan regressions detected by the
+	// connExecutors, which are recorded by a background worker.
+	planRegressions chan planRegression
 }
 
 // Metrics collects timeseries data about SQL activity.
@@ -283,8 +287,10 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 		// dbCache will be updated on Start().
 		dbCache:  newDatabaseCacheHolder(newDatabaseCache(systemCfg)),
 		pool:     pool,
-		sqlStats: sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
+		sqlStats: sqlStats{st: cfg.Settings, apps: make(map[string]*appStats), pool: pool},
 		reCache:  tree.NewRegexpCache(512),
+
+		planRegressions: make(chan planRegression, planRegressionBufferSize),
 	}
 }
 
@@ -300,17 +306,23 @@ func makeMetrics(internal bool) Metrics {
 			// TODO(mrtracy): See HistogramWindowInterval in server/config.go for the 6x factor.
 			DistSQLExecLatency: metric.NewLatency(getMetricMeta(MetaDistSQLExecLatency, internal),
 				6*metricsSampleInterval),
-			SQLExecLatency: metric.NewLatency(getMetricMeta(MetaSQLExecLatency, internal),
+			SQLExecLatency: metric.NewHighResLatency(getMetricMeta(MetaSQLExecLatency, internal),
 				6*metricsSampleInterval),
 			DistSQLServiceLatency: metric.NewLatency(getMetricMeta(MetaDistSQLServiceLatency, internal),
 				6*metricsSampleInterval),
-			SQLServiceLatency: metric.NewLatency(getMetricMeta(MetaSQLServiceLatency, internal),
+			SQLServiceLatency: metric.NewHighResLatency(getMetricMeta(MetaSQLServiceLatency, internal),
 				6*metricsSampleInterval),
 			SQLTxnLatency: metric.NewLatency(getMetricMeta(MetaSQLTxnLatency, internal),
 				6*metricsSampleInterval),
 
-			TxnAbortCount: metric.NewCounter(getMetricMeta(MetaTxnAbort, internal)),
-			FailureCount:  metric.NewCounter(getMetricMeta(MetaFailure, internal)),
+			TxnAbortCount:            metric.NewCounter(getMetricMeta(MetaTxnAbort, internal)),
+			TxnDeadlineExtendedCount: metric.NewCounter(getMetricMeta(MetaTxnDeadlineExtended, internal)),
+			TxnDeadlineExceededCount: metric.NewCounter(getMetricMeta(MetaTxnDeadlineExceeded, internal)),
+			FailureCount:             metric.NewCounter(getMetricMeta(MetaFailure, internal)),
+
+			PreparedStmtsCount:        metric.NewGauge(getMetricMeta(MetaPreparedStmts, internal)),
+			PreparedStmtsBytes:        metric.NewGauge(getMetricMeta(MetaPreparedStmtsBytes, internal)),
+			PreparedStmtsEvictedCount: metric.NewCounter(getMetricMeta(MetaPreparedStmtsEvicted, internal)),
 		},
 		StartedStatementCounters:  makeStartedStatementCounters(internal),
 		ExecutedStatementCounters: makeExecutedStatementCounters(internal),
@@ -332,6 +344,7 @@ func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
 		}
 	})
 	s.PeriodicallyClearSQLStats(ctx, stopper)
+	s.startPlanRegressionRecorder(ctx, stopper)
 }
 
 // ResetSQLStats resets the executor's collected sql statistics.
@@ -504,6 +517,14 @@ func (s *Server) newConnExecutor(
 		memMetrics.SessionMaxBytesHist,
 		-1 /* increment */, noteworthyMemoryUsageBytes, s.cfg.Settings,
 	)
+	prepStmtsMon := mon.MakeMonitorWithLimit(
+		"prepared statements",
+		mon.MemoryResource,
+		prepStmtsSessionMemoryLimit.Get(&s.cfg.Settings.SV),
+		srvMetrics.EngineMetrics.PreparedStmtsBytes,
+		nil, /* maxHist */
+		-1 /* increment */, noteworthyMemoryUsageBytes, s.cfg.Settings,
+	)
 	// The txn monitor is started in txnState.resetForNewSQLTxn().
 	txnMon := mon.MakeMonitor(
 		"txn",
@@ -518,9 +539,10 @@ func (s *Server) newConnExecutor(
 		metrics:     srvMetrics,
 		stmtBuf:     stmtBuf,
 		clientComm:  clientComm,
-		mon:         &sessionRootMon,
-		sessionMon:  &sessionMon,
-		sessionData: sd,
+		mon:          &sessionRootMon,
+		sessionMon:   &sessionMon,
+		prepStmtsMon: &prepStmtsMon,
+		sessionData:  sd,
 		dataMutator: sdMutator,
 		state: txnState{
 			mon:     &txnMon,
@@ -791,7 +813,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	if ex.sessionTracing.Enabled() {
-		if err := ex.sessionTracing.StopTracing(); err != nil {
+		if err := ex.sessionTracing.StopTracing(ctx); err != nil {
 			log.Warningf(ctx, "error stopping tracing: %s", err)
 		}
 	}
@@ -803,10 +825,12 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 
 	if closeType != panicClose {
 		ex.state.mon.Stop(ctx)
+		ex.prepStmtsMon.Stop(ctx)
 		ex.sessionMon.Stop(ctx)
 		ex.mon.Stop(ctx)
 	} else {
 		ex.state.mon.EmergencyStop(ctx)
+		ex.prepStmtsMon.EmergencyStop(ctx)
 		ex.sessionMon.EmergencyStop(ctx)
 		ex.mon.EmergencyStop(ctx)
 	}
@@ -838,6 +862,9 @@ type connExecutor struct {
 	// statistics for result sets (which escape transactions).
 	mon        *mon.BytesMonitor
 	sessionMon *mon.BytesMonitor
+	// prepStmtsMon is a child of sessionMon that tracks the memory used by the
+	// session's prepared statements and portals. Its limit caps that memory.
+	prepStmtsMon *mon.BytesMonitor
 	// memMetrics contains the metrics that statements executed on this connection
 	// will contribute to.
 	memMetrics MemoryMetrics
@@ -919,6 +946,17 @@ type connExecutor struct {
 		// committed or aborted). It is set when txn is started but can remain
 		// unset when txn is executed within another higher-level txn.
 		onTxnFinish func(txnEvent)
+
+		// stmtFingerprints is the set of the fingerprints of the statements
+		// executed by the current transaction, which make up the transaction
+		// fingerprint.
+		stmtFingerprints map[stmtKey]struct{}
+
+		// activeTxn is the registration of the current transaction in the
+		// server's activeTxnRegistry under activeTxnID. It is nil if the
+		// transaction hasn't been registered.
+		activeTxn   *activeTxn
+		activeTxnID uuid.UUID
 	}
 
 	// sessionData contains the user-configurable connection variables.
@@ -1134,6 +1172,7 @@ func (ex *connExecutor) activate(
 	// single threaded, and the point of buffering is just to avoid contention.
 	ex.mon.Start(ctx, parentMon, reserved)
 	ex.sessionMon.Start(ctx, ex.mon, mon.BoundAccount{})
+	ex.prepStmtsMon.Start(ctx, ex.sessionMon, mon.BoundAccount{})
 
 	// Enable the trace if configured.
 	if traceSessionEventLogEnabled.Get(&ex.server.cfg.Settings.SV) {
@@ -1274,6 +1313,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		)
 		res = stmtRes
 		curStmt := Statement{Statement: tcmd.Statement}
+		if tcmd.BatchPos != nil {
+			curStmt.continueImplicitTxn = tcmd.BatchPos.ContinueImplicitTxn
+		}
 
 		ex.phaseTimes[sessionQueryReceived] = tcmd.TimeReceived
 		ex.phaseTimes[sessionStartParse] = tcmd.ParseStart
@@ -1420,6 +1462,16 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		}
 	}
 
+	// Like in Postgres, once a statement of a query string containing multiple
+	// statements fails, the remaining statements are skipped. Most errors
+	// already skip them through the state machine, but not the errors which
+	// don't affect the transaction (e.g. those of observer statements).
+	if advInfo.code == advanceOne && res.Err() != nil {
+		if tcmd, ok := cmd.(ExecStmt); ok && tcmd.BatchPos != nil {
+			advInfo.code = skipBatch
+		}
+	}
+
 	// Decide if we need to close the result or not. We don't need to do it if
 	// we're staying in place or rewinding - the statement will be executed
 	// again.
@@ -1435,9 +1487,12 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if resErr == nil && ok {
 			// Depending on whether the result has the error already or not, we have
 			// to call either Close or CloseWithErr.
-			res.CloseWithErr(pe.errorCause())
+			res.CloseWithErr(maybeAnnotateWithBatchPosition(cmd, pe.errorCause()))
 		} else {
 			ex.recordError(ctx, resErr)
+			if resErr != nil {
+				res.SetError(maybeAnnotateWithBatchPosition(cmd, resErr))
+			}
 			res.Close(stateToTxnStatusIndicator(ex.machine.CurState()))
 		}
 	} else {
@@ -1685,7 +1740,9 @@ func (ex *connExecutor) execCopyIn(
 			ctx, cmd.Conn, cmd.Stmt, txnOpt, ex.server.cfg, resetPlanner,
 			// execInsertPlan
 			func(ctx context.Context, p *planner, res RestrictedCommandResult) error {
-				_, _, err := ex.execWithDistSQLEngine(ctx, p, tree.RowsAffected, res, false /* distribute */)
+				_, _, err := ex.execWithDistSQLEngine(
+					ctx, p, tree.RowsAffected, res, false /* distribute */, nil, /* progress */
+				)
 				return err
 			},
 		)
@@ -1902,6 +1959,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		ExecCfg:           ex.server.cfg,
 		DistSQLPlanner:    ex.server.cfg.DistSQLPlanner,
 		TxnModesSetter:    ex,
+		ExtendTxnDeadline: ex.maybeExtendTxnDeadline,
 		SchemaChangers:    &ex.extraTxnState.schemaChangers,
 		Jobs:              &ex.extraTxnState.jobs,
 		schemaAccessors:   scInterface,
@@ -2158,6 +2216,16 @@ func (ex *connExecutor) cancelQuery(queryID ClusterWideID) bool {
 	return false
 }
 
+// queryProgress is part of the registrySession interface.
+func (ex *connExecutor) queryProgress(queryID ClusterWideID) *queryProgress {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if queryMeta, exists := ex.mu.ActiveQueries[queryID]; exists {
+		return &queryMeta.progress
+	}
+	return nil
+}
+
 // cancelSession is part of the registrySession interface.
 func (ex *connExecutor) cancelSession() {
 	if ex.onCancelSession == nil {
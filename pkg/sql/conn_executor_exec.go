@@ -19,7 +19,9 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -209,7 +211,9 @@ func (ex *connExecutor) execStmtInOpenState(
 		if retEv != nil || retErr != nil {
 			return
 		}
-		if os.ImplicitTxn.Get() {
+		// The implicit transaction of a statement that is followed by other
+		// statements of the same query string is committed after the last one.
+		if os.ImplicitTxn.Get() && !stmt.continueImplicitTxn {
 			retEv, retPayload = ex.handleAutoCommit(ctx, stmt.AST)
 			return
 		}
@@ -376,6 +380,13 @@ func (ex *connExecutor) execStmtInOpenState(
 			p.semaCtx.AsOfTimestamp = asOfTs
 			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
 			ex.state.setHistoricalTimestamp(ctx, *asOfTs)
+			if p.isBoundedStalenessAsOf(stmt.AST) {
+				// Bounded staleness reads pick a timestamp which is likely to be
+				// servable by the nearest replica, so we route their single-range
+				// reads there. This statement's transaction is read-only, so its
+				// context doesn't outlive the statement.
+				ctx = kv.WithNearestReplicaRouting(ctx)
+			}
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
@@ -414,7 +425,8 @@ func (ex *connExecutor) execStmtInOpenState(
 	// contexts.
 	p.cancelChecker = sqlbase.NewCancelChecker(ctx)
 
-	p.autoCommit = os.ImplicitTxn.Get() && !ex.server.cfg.TestingKnobs.DisableAutoCommit
+	p.autoCommit = os.ImplicitTxn.Get() && !stmt.continueImplicitTxn &&
+		!ex.server.cfg.TestingKnobs.DisableAutoCommit
 	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
 		return nil, nil, err
 	}
@@ -577,7 +589,13 @@ func (ex *connExecutor) commitSQLTransaction(
 		return ex.makeErrEvent(err, stmt)
 	}
 
+	ex.maybeExtendTxnDeadline(ctx)
+
+	deadline, hasDeadline := ex.state.mu.txn.Deadline()
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
+		if hasDeadline && isCommitDeadlineExceededError(err, deadline) {
+			ex.metrics.EngineMetrics.TxnDeadlineExceededCount.Inc(1)
+		}
 		return ex.makeErrEvent(err, stmt)
 	}
 
@@ -594,6 +612,28 @@ func (ex *connExecutor) commitSQLTransaction(
 	return eventTxnReleased{}, nil
 }
 
+// maybeExtendTxnDeadline extends the deadline of the current transaction if
+// it is about to be reached (see TableCollection.maybeExtendDeadline). It is
+// called before the statements are executed and before the transaction
+// commits, including when a statement commits it automatically.
+func (ex *connExecutor) maybeExtendTxnDeadline(ctx context.Context) {
+	if txnDeadlineExtensionEnabled.Get(&ex.server.cfg.Settings.SV) &&
+		ex.extraTxnState.tables.maybeExtendDeadline(ctx, ex.state.mu.txn, ex.server.cfg.Clock.Now()) {
+		ex.metrics.EngineMetrics.TxnDeadlineExtendedCount.Inc(1)
+	}
+}
+
+// isCommitDeadlineExceededError returns whether err is the retriable error
+// with which a commit fails when the transaction's timestamp was pushed to or
+// past deadline, the deadline of the transaction when it tried to commit. The
+// retry keeps the pushed timestamp, so the retried transaction tells whether
+// it could have committed by the deadline.
+func isCommitDeadlineExceededError(err error, deadline hlc.Timestamp) bool {
+	var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+	return errors.As(err, &retryErr) && !retryErr.PrevTxnAborted() &&
+		deadline.LessEq(retryErr.Transaction.WriteTimestamp)
+}
+
 // rollbackSQLTransaction executes a ROLLBACK statement: the KV transaction is
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(ctx context.Context) (fsm.Event, fsm.EventPayload) {
@@ -630,6 +670,12 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		planner.curPlan.flags.Set(planFlagImplicitTxn)
 	}
 
+	// Implicit transactions can be retried automatically as long as none of
+	// their results have been sent to the client, so buffer more of them.
+	if planner.autoCommit {
+		res.IncreaseBufferSize(planner.SessionData().ImplicitTxnResultsBufferSize)
+	}
+
 	// Certain statements want their results to go to the client
 	// directly. Configure this here.
 	if planner.curPlan.avoidBuffering {
@@ -684,6 +730,10 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 
 	ex.statsCollector.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 
+	// Planning may have leased new tables; make sure that the deadline they set
+	// isn't about to be reached before the statement runs.
+	ex.maybeExtendTxnDeadline(ctx)
+
 	ex.mu.Lock()
 	queryMeta, ok := ex.mu.ActiveQueries[stmt.queryID]
 	if !ok {
@@ -712,7 +762,9 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		planner.curPlan.flags.Set(planFlagDistSQLLocal)
 	}
 	ex.sessionTracing.TraceExecStart(ctx, "distributed")
-	bytesRead, rowsRead, err := ex.execWithDistSQLEngine(ctx, planner, stmt.AST.StatementType(), res, distributePlan)
+	bytesRead, rowsRead, err := ex.execWithDistSQLEngine(
+		ctx, planner, stmt.AST.StatementType(), res, distributePlan, &queryMeta.progress,
+	)
 	ex.sessionTracing.TraceExecEnd(ctx, res.Err(), res.RowsAffected())
 	ex.statsCollector.phaseTimes[plannerEndExecStmt] = timeutil.Now()
 
@@ -775,6 +827,7 @@ func (ex *connExecutor) execWithDistSQLEngine(
 	stmtType tree.StatementType,
 	res RestrictedCommandResult,
 	distribute bool,
+	progress *queryProgress,
 ) (bytesRead, rowsRead int64, _ error) {
 	recv := MakeDistSQLReceiver(
 		ctx, res, stmtType,
@@ -786,6 +839,7 @@ func (ex *connExecutor) execWithDistSQLEngine(
 		&ex.sessionTracing,
 	)
 	defer recv.Release()
+	recv.progress = progress
 
 	evalCtx := planner.ExtendedEvalContext()
 	var planCtx *PlanningCtx
@@ -826,6 +880,15 @@ func (ex *connExecutor) execWithDistSQLEngine(
 	// Note that we're not cleaning up right away because postqueries might
 	// need to have access to the main query tree.
 	defer cleanup()
+	if len(recv.contentionEvents) > 0 && planner.stmt != nil {
+		stmt := planner.stmt.AnonymizedStr
+		if stmt == "" {
+			stmt = anonymizeStmt(planner.stmt.AST)
+		}
+		ex.server.sqlStats.contentionEvents.record(
+			timeutil.Now(), ex.sessionData.ApplicationName, stmt, recv.contentionEvents,
+		)
+	}
 	if recv.commErr != nil || res.Err() != nil {
 		return recv.bytesRead, recv.rowsRead, recv.commErr
 	}
@@ -1135,16 +1198,17 @@ func (ex *connExecutor) runSetTracing(
 		modes[i] = strVal.RawString()
 	}
 
-	if err := ex.enableTracing(modes); err != nil {
+	if err := ex.enableTracing(ctx, modes); err != nil {
 		res.SetError(err)
 	}
 }
 
-func (ex *connExecutor) enableTracing(modes []string) error {
+func (ex *connExecutor) enableTracing(ctx context.Context, modes []string) error {
 	traceKV := false
 	recordingType := tracing.SnowballRecording
 	enableMode := true
 	showResults := false
+	persist := false
 
 	for _, s := range modes {
 		switch strings.ToLower(s) {
@@ -1160,15 +1224,21 @@ func (ex *connExecutor) enableTracing(modes []string) error {
 			recordingType = tracing.SingleNodeRecording
 		case "cluster":
 			recordingType = tracing.SnowballRecording
+		case "persist":
+			persist = true
 		default:
 			return pgerror.Newf(pgcode.Syntax,
 				"set tracing: unknown mode %q", s)
 		}
 	}
 	if !enableMode {
-		return ex.sessionTracing.StopTracing()
+		return ex.sessionTracing.StopTracing(ctx)
+	}
+	if persist && !cluster.Version.IsActive(ctx, ex.server.cfg.Settings, cluster.VersionSessionTraces) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"all nodes are not the correct version for persisting session traces")
 	}
-	return ex.sessionTracing.StartTracing(recordingType, traceKV, showResults)
+	return ex.sessionTracing.StartTracing(recordingType, traceKV, showResults, persist)
 }
 
 // addActiveQuery adds a running query to the list of running queries.
@@ -1290,10 +1360,62 @@ func (ex *connExecutor) recordTransactionStart() func(txnEvent) {
 	// ex.statsCollector.reset() before executing the statements of the
 	// transaction.
 	ex.phaseTimes[transactionStart] = timeutil.Now()
+	ex.extraTxnState.stmtFingerprints = make(map[stmtKey]struct{})
+	ex.maybeRegisterActiveTxn()
 	implicit := ex.implicitTxn()
 	return func(ev txnEvent) { ex.recordTransaction(ev, implicit) }
 }
 
+// maybeRegisterActiveTxn registers the current KV transaction in the server's
+// activeTxnRegistry, unless it is already registered. The KV transaction can
+// change when the SQL transaction is retried, in which case the contention
+// accumulated so far is carried over to the new registration.
+func (ex *connExecutor) maybeRegisterActiveTxn() {
+	txn := ex.state.mu.txn
+	if txn == nil {
+		return
+	}
+	id := txn.ID()
+	prev := ex.extraTxnState.activeTxn
+	if prev != nil && id == ex.extraTxnState.activeTxnID {
+		return
+	}
+	registry := &ex.server.sqlStats.activeTxns
+	if prev != nil {
+		registry.unregister(ex.extraTxnState.activeTxnID)
+	}
+	ex.extraTxnState.activeTxn = registry.register(id, prev)
+	ex.extraTxnState.activeTxnID = id
+}
+
+// unregisterActiveTxn removes the current transaction from the server's
+// activeTxnRegistry and returns the contention accumulated by it.
+func (ex *connExecutor) unregisterActiveTxn() activeTxnContention {
+	t := ex.extraTxnState.activeTxn
+	if t == nil {
+		return activeTxnContention{}
+	}
+	ex.server.sqlStats.activeTxns.unregister(ex.extraTxnState.activeTxnID)
+	ex.extraTxnState.activeTxn = nil
+	return t.getContention()
+}
+
+// recordStatementFingerprint adds the fingerprint of the statement that was
+// just executed to the fingerprint of the current transaction.
+func (ex *connExecutor) recordStatementFingerprint(stmt *Statement, flags planFlags, err error) {
+	if ex.extraTxnState.stmtFingerprints == nil {
+		// The statement is not executed in a transaction started by this
+		// connExecutor.
+		return
+	}
+	key := makeStmtKey(
+		stmt, flags.IsSet(planFlagDistributed), flags.IsSet(planFlagOptUsed),
+		flags.IsSet(planFlagImplicitTxn), err,
+	)
+	ex.extraTxnState.stmtFingerprints[key] = struct{}{}
+	ex.maybeRegisterActiveTxn()
+}
+
 func (ex *connExecutor) recordTransaction(ev txnEvent, implicit bool) {
 	phaseTimes := &ex.statsCollector.phaseTimes
 	phaseTimes[transactionEnd] = timeutil.Now()
@@ -1301,9 +1423,18 @@ func (ex *connExecutor) recordTransaction(ev txnEvent, implicit bool) {
 	txnEnd := phaseTimes[transactionEnd]
 	txnTime := txnEnd.Sub(txnStart)
 	ex.metrics.EngineMetrics.SQLTxnLatency.RecordValue(txnTime.Nanoseconds())
+	stmtKeys := make([]stmtKey, 0, len(ex.extraTxnState.stmtFingerprints))
+	for k := range ex.extraTxnState.stmtFingerprints {
+		stmtKeys = append(stmtKeys, k)
+	}
+	ex.extraTxnState.stmtFingerprints = nil
 	ex.statsCollector.recordTransaction(
+		ex.Ctx(),
 		txnTime.Seconds(),
 		ev,
 		implicit,
+		stmtKeys,
+		ex.extraTxnState.autoRetryCounter,
+		ex.unregisterActiveTxn(),
 	)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -108,13 +109,46 @@ func (ex *connExecutor) addPreparedStmt(
 		return nil, err
 	}
 
-	if err := prepared.memAcc.Grow(ctx, int64(len(name))); err != nil {
+	if err := ex.growPrepStmtsAcc(ctx, &prepared.memAcc, int64(len(name)), nil /* keep */); err != nil {
+		prepared.memAcc.Close(ctx)
 		return nil, err
 	}
+	prepared.numPrepStmts = ex.metrics.EngineMetrics.PreparedStmtsCount
+	prepared.numPrepStmts.Inc(1)
 	ex.extraTxnState.prepStmtsNamespace.prepStmts[name] = prepared
 	return prepared, nil
 }
 
+// growPrepStmtsAcc grows the given account of a prepared statement or portal.
+// If this exceeds the session's memory limit for prepared statements, the
+// unnamed prepared statement (unless it is keep) and portal are evicted and
+// the account is grown again. Named prepared statements and portals are never
+// evicted since the client expects them to exist until it closes them.
+func (ex *connExecutor) growPrepStmtsAcc(
+	ctx context.Context, acc *mon.BoundAccount, n int64, keep *PreparedStatement,
+) error {
+	err := acc.Grow(ctx, n)
+	if err == nil || !sqlbase.IsOutOfMemoryError(err) {
+		return err
+	}
+	ns := &ex.extraTxnState.prepStmtsNamespace
+	evicted := false
+	if ps, ok := ns.prepStmts[""]; ok && ps != keep {
+		ex.deletePreparedStmt(ctx, "")
+		evicted = true
+	}
+	if _, ok := ns.portals[""]; ok {
+		ex.deletePortal(ctx, "")
+		evicted = true
+	}
+	if !evicted {
+		return err
+	}
+	log.VEventf(ctx, 2, "evicted the unnamed prepared statement and portal: %v", err)
+	ex.metrics.EngineMetrics.PreparedStmtsEvictedCount.Inc(1)
+	return acc.Grow(ctx, n)
+}
+
 // prepare prepares the given statement.
 //
 // placeholderHints may contain partial type information for placeholders.
@@ -138,15 +172,12 @@ func (ex *connExecutor) prepare(
 				TypeHints: placeholderHints,
 			},
 		},
-		memAcc:   ex.sessionMon.MakeBoundAccount(),
+		memAcc:   ex.prepStmtsMon.MakeBoundAccount(),
 		refCount: 1,
 
 		createdAt: timeutil.Now(),
 		origin:    origin,
 	}
-	// NB: if we start caching the plan, we'll want to keep around the memory
-	// account used for the plan, rather than clearing it.
-	defer prepared.memAcc.Clear(ctx)
 
 	if stmt.AST == nil {
 		return prepared, nil
@@ -180,8 +211,9 @@ func (ex *connExecutor) prepare(
 		return nil, err
 	}
 
-	// Account for the memory used by this prepared statement.
-	if err := prepared.memAcc.Grow(ctx, prepared.MemoryEstimate()); err != nil {
+	// Account for the memory used by this prepared statement. The account is
+	// closed once the statement is released.
+	if err := ex.growPrepStmtsAcc(ctx, &prepared.memAcc, prepared.MemoryEstimate(), nil /* keep */); err != nil {
 		return nil, err
 	}
 	ex.updateOptCounters(flags)
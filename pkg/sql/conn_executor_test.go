@@ -389,3 +389,67 @@ func TestAppNameStatisticsInitialization(t *testing.T) {
 		t.Fatalf("query was not counted properly: %+v", counts)
 	}
 }
+
+// TestPreparedStatementMemoryAccounting verifies that prepared statements are
+// reflected in the prepared statement metrics and that the per-session memory
+// limit is enforced.
+func TestPreparedStatementMemoryAccounting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+	ctx := context.TODO()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	getMetric := func(name string) int64 {
+		var v float64
+		sqlDB.QueryRow(t, `SELECT value FROM crdb_internal.node_metrics WHERE name = $1`, name).Scan(&v)
+		return int64(v)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	countBefore := getMetric("sql.prepared_statements.count")
+	const numStmts = 3
+	for i := 0; i < numStmts; i++ {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("PREPARE p%d AS SELECT %d", i, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count := getMetric("sql.prepared_statements.count"); count < countBefore+numStmts {
+		t.Fatalf("expected at least %d prepared statements, found %d", countBefore+numStmts, count)
+	}
+	if bytes := getMetric("sql.prepared_statements.bytes"); bytes <= 0 {
+		t.Fatalf("expected prepared statements to use memory, found %d bytes", bytes)
+	}
+	if _, err := conn.ExecContext(ctx, "DEALLOCATE ALL"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The limit is picked up by sessions created after it is set.
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.prepared_statements.session_memory_limit = '16KiB'`)
+	limitedConn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer limitedConn.Close()
+
+	for i := 0; ; i++ {
+		if i == 10000 {
+			t.Fatal("expected the session memory limit to be exceeded")
+		}
+		_, err := limitedConn.ExecContext(ctx, fmt.Sprintf("PREPARE p%d AS SELECT %d", i, i))
+		if err == nil {
+			continue
+		}
+		if !testutils.IsError(err, "memory budget exceeded") {
+			t.Fatal(err)
+		}
+		break
+	}
+}
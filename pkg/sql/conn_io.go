@@ -134,6 +134,10 @@ type ExecStmt struct {
 	// stats reporting.
 	ParseStart time.Time
 	ParseEnd   time.Time
+
+	// BatchPos is set if the statement is part of a query string containing
+	// multiple statements.
+	BatchPos *StmtBatchPosition
 }
 
 // command implements the Command interface.
@@ -152,6 +156,86 @@ func (e ExecStmt) String() string {
 
 var _ Command = ExecStmt{}
 
+// StmtBatchPosition describes the position of a statement in a query string
+// containing multiple statements, which is sent through the "simple" pgwire
+// protocol.
+type StmtBatchPosition struct {
+	// Index is the 0-based index of the statement in the query string.
+	Index int
+	// NumStmts is the number of statements in the query string.
+	NumStmts int
+	// Position is the 1-based character position at which the statement starts
+	// in the query string. It is reported in the position field of the errors
+	// of the statement, like Postgres does for syntax errors.
+	Position int
+	// ContinueImplicitTxn is set if the statement is followed by a statement
+	// that runs in the same implicit transaction. If the statement runs in an
+	// implicit transaction, the transaction isn't committed after it.
+	ContinueImplicitTxn bool
+}
+
+// CanShareImplicitTxn returns whether the statement can run in the same
+// implicit transaction as the statements around it in a query string
+// containing multiple statements. Like in Postgres, such statements run in a
+// single implicit transaction, so that an error in one of them rolls back the
+// effects of the others. The exceptions are:
+//   - transaction control statements, which control the transaction themselves.
+//   - schema changes, which aren't fully transactional and are therefore still
+//     committed individually.
+//   - observer statements, which don't interact with the transaction.
+//   - statements with an AS OF SYSTEM TIME clause, which determines the
+//     timestamp of the transaction.
+func CanShareImplicitTxn(stmt tree.Statement) bool {
+	switch stmt.(type) {
+	case *tree.BeginTransaction, *tree.CommitTransaction, *tree.RollbackTransaction,
+		*tree.SetTransaction, *tree.Savepoint, *tree.ReleaseSavepoint,
+		*tree.RollbackToSavepoint, tree.ObserverStatement:
+		return false
+	}
+	if stmt.StatementType() == tree.DDL {
+		return false
+	}
+	_, isAsOf := getAsOfClause(stmt)
+	return !isAsOf
+}
+
+// withStmtBatchPosition annotates an error with the position of the statement
+// that caused it in a query string containing multiple statements.
+type withStmtBatchPosition struct {
+	cause error
+	pos   StmtBatchPosition
+}
+
+func (w *withStmtBatchPosition) Error() string { return w.cause.Error() }
+func (w *withStmtBatchPosition) Cause() error  { return w.cause }
+func (w *withStmtBatchPosition) Unwrap() error { return w.cause }
+
+// maybeAnnotateWithBatchPosition annotates the error of the command with the
+// position of its statement if the command is a statement that is part of a
+// query string containing multiple statements.
+func maybeAnnotateWithBatchPosition(cmd Command, err error) error {
+	if e, ok := cmd.(ExecStmt); ok && e.BatchPos != nil && err != nil {
+		return &withStmtBatchPosition{cause: err, pos: *e.BatchPos}
+	}
+	return err
+}
+
+// GetStmtBatchPosition returns the position of the statement that caused the
+// error, if the statement was part of a query string containing multiple
+// statements.
+func GetStmtBatchPosition(err error) (StmtBatchPosition, bool) {
+	v, ok := errors.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withStmtBatchPosition); ok {
+			return w.pos, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return StmtBatchPosition{}, false
+	}
+	return v.(StmtBatchPosition), true
+}
+
 // ExecPortal is the Command for executing a portal.
 type ExecPortal struct {
 	Name string
@@ -721,6 +805,14 @@ type RestrictedCommandResult interface {
 	// to this CommandResult, will be flushed immediately to the client.
 	// This is currently used for sinkless changefeeds.
 	DisableBuffering()
+
+	// IncreaseBufferSize can be called before any rows are added to ensure
+	// that the results of this CommandResult are not flushed to the client
+	// before at least size bytes have accumulated, even if that exceeds the
+	// connection's results buffer size. As long as no results have been
+	// flushed, the statement can be retried automatically. It has no effect if
+	// size is smaller than the connection's results buffer size.
+	IncreaseBufferSize(size int64)
 }
 
 // DescribeResult represents the result of a Describe command (for either
@@ -893,6 +985,11 @@ func (r *bufferedCommandResult) DisableBuffering() {
 	panic("cannot disable buffering here")
 }
 
+// IncreaseBufferSize is part of the RestrictedCommandResult interface.
+func (r *bufferedCommandResult) IncreaseBufferSize(int64) {
+	// All results are buffered anyway.
+}
+
 // SetError is part of the RestrictedCommandResult interface.
 func (r *bufferedCommandResult) SetError(err error) {
 	r.err = err
@@ -0,0 +1,77 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// maxRecordedContentionEvents is the number of contention events retained by
+// a contentionEventRegistry. Once it is reached, the oldest events are
+// overwritten.
+const maxRecordedContentionEvents = 1000
+
+// recordedContentionEvent is a contention event encountered by a statement
+// executed on the local node.
+type recordedContentionEvent struct {
+	roachpb.ContentionEvent
+
+	// recordedAt is the time at which the gateway received the event.
+	recordedAt time.Time
+	app        string
+	stmt       string
+}
+
+// contentionEventRegistry retains the most recent contention events
+// encountered by the statements executed on the local node, including those
+// that the processors of distributed flows encountered on remote nodes.
+type contentionEventRegistry struct {
+	mu struct {
+		syncutil.Mutex
+		// events is a ring buffer of at most maxRecordedContentionEvents events;
+		// next is the position at which the next event is written.
+		events []recordedContentionEvent
+		next   int
+	}
+}
+
+// record adds the contention events encountered by the given statement to the
+// registry.
+func (r *contentionEventRegistry) record(
+	now time.Time, app string, stmt string, events []roachpb.ContentionEvent,
+) {
+	if len(events) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ev := range events {
+		rec := recordedContentionEvent{ContentionEvent: ev, recordedAt: now, app: app, stmt: stmt}
+		if len(r.mu.events) < maxRecordedContentionEvents {
+			r.mu.events = append(r.mu.events, rec)
+			continue
+		}
+		r.mu.events[r.mu.next] = rec
+		r.mu.next = (r.mu.next + 1) % maxRecordedContentionEvents
+	}
+}
+
+// get returns a copy of the events in the registry, oldest first.
+func (r *contentionEventRegistry) get() []recordedContentionEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]recordedContentionEvent, 0, len(r.mu.events))
+	res = append(res, r.mu.events[r.mu.next:]...)
+	return append(res, r.mu.events[:r.mu.next]...)
+}
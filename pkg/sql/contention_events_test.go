@@ -0,0 +1,60 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestContentionEventRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var r contentionEventRegistry
+	now := timeutil.Now()
+	makeEvents := func(n int, start time.Duration) []roachpb.ContentionEvent {
+		events := make([]roachpb.ContentionEvent, n)
+		for i := range events {
+			events[i].Duration = start + time.Duration(i)
+		}
+		return events
+	}
+
+	r.record(now, "app", "SELECT _", nil /* events */)
+	if n := len(r.get()); n != 0 {
+		t.Fatalf("expected no events, found %d", n)
+	}
+
+	r.record(now, "app", "SELECT _", makeEvents(10, 0))
+	if events := r.get(); len(events) != 10 || events[0].Duration != 0 || events[9].Duration != 9 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	// Once the registry is full, the oldest events are overwritten and the
+	// remaining ones are still returned oldest first.
+	r.record(now, "app", "UPDATE t SET v = _", makeEvents(maxRecordedContentionEvents, 10))
+	events := r.get()
+	if len(events) != maxRecordedContentionEvents {
+		t.Fatalf("expected %d events, found %d", maxRecordedContentionEvents, len(events))
+	}
+	for i, ev := range events {
+		if exp := time.Duration(i + 10); ev.Duration != exp {
+			t.Fatalf("%d: expected duration %s, found %s", i, exp, ev.Duration)
+		}
+		if ev.stmt != "UPDATE t SET v = _" {
+			t.Fatalf("%d: unexpected statement %q", i, ev.stmt)
+		}
+	}
+}
@@ -38,9 +38,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -63,6 +65,7 @@ const crdbInternalName = "crdb_internal"
 var crdbInternal = virtualSchema{
 	name: crdbInternalName,
 	tableDefs: map[sqlbase.ID]virtualSchemaDef{
+		sqlbase.CrdbInternalAllocatorDecisionsTableID:   crdbInternalAllocatorDecisionsTable,
 		sqlbase.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
 		sqlbase.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
 		sqlbase.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
@@ -70,6 +73,9 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
 		sqlbase.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
 		sqlbase.CrdbInternalCreateStmtsTableID:          crdbInternalCreateStmtsTable,
+		sqlbase.CrdbInternalContentionEventsTableID:     crdbInternalContentionEventsTable,
+		sqlbase.CrdbInternalErrorCodesTableID:           crdbInternalErrorCodesTable,
+		sqlbase.CrdbInternalRangeEventsTableID:          crdbInternalRangeEventsTable,
 		sqlbase.CrdbInternalFeatureUsageID:              crdbInternalFeatureUsage,
 		sqlbase.CrdbInternalForwardDependenciesTableID:  crdbInternalForwardDependenciesTable,
 		sqlbase.CrdbInternalGossipNodesTableID:          crdbInternalGossipNodesTable,
@@ -97,6 +103,7 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
 		sqlbase.CrdbInternalTablesTableID:               crdbInternalTablesTable,
 		sqlbase.CrdbInternalTxnStatsTableID:             crdbInternalTxnStatsTable,
+		sqlbase.CrdbInternalTxnFingerprintStatsTableID:  crdbInternalTxnFingerprintStatsTable,
 		sqlbase.CrdbInternalZonesTableID:                crdbInternalZonesTable,
 	},
 	validWithNoDatabaseContext: true,
@@ -739,6 +746,69 @@ CREATE TABLE crdb_internal.node_txn_stats (
 	},
 }
 
+var crdbInternalTxnFingerprintStatsTable = virtualSchemaTable{
+	comment: `transaction fingerprint statistics (in-memory, not durable; local node only). ` +
+		`This table is wiped periodically (by default, at least every two hours)`,
+	schema: `
+CREATE TABLE crdb_internal.node_transaction_statistics (
+  node_id                INT NOT NULL,
+  application_name       STRING NOT NULL,
+  fingerprint            STRING NOT NULL,
+  statement_fingerprints STRING[] NOT NULL,
+  count                  INT NOT NULL,
+  committed_count        INT NOT NULL,
+  implicit_count         INT NOT NULL,
+  retry_count            INT NOT NULL,
+  max_retries            INT NOT NULL,
+  service_lat_avg        FLOAT NOT NULL,
+  service_lat_var        FLOAT NOT NULL,
+  contention_time_avg    FLOAT NOT NULL,
+  contention_time_var    FLOAT NOT NULL,
+  contention_caused_time FLOAT NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "access application statistics"); err != nil {
+			return err
+		}
+
+		sqlStats := p.extendedEvalCtx.sqlStatsCollector.sqlStats
+		if sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+
+		nodeID := tree.NewDInt(tree.DInt(int64(p.execCfg.NodeID.Get())))
+
+		for _, s := range sqlStats.getTxnFingerprintStats() {
+			stmts := tree.NewDArray(types.String)
+			for _, stmt := range s.StatementFingerprints {
+				if err := stmts.Append(tree.NewDString(stmt)); err != nil {
+					return err
+				}
+			}
+			if err := addRow(
+				nodeID,
+				tree.NewDString(s.App),
+				tree.NewDString(s.Fingerprint),
+				stmts,
+				tree.NewDInt(tree.DInt(s.Count)),
+				tree.NewDInt(tree.DInt(s.CommittedCount)),
+				tree.NewDInt(tree.DInt(s.ImplicitCount)),
+				tree.NewDInt(tree.DInt(s.RetryCount)),
+				tree.NewDInt(tree.DInt(s.MaxRetries)),
+				tree.NewDFloat(tree.DFloat(s.ServiceLatAvg)),
+				tree.NewDFloat(tree.DFloat(s.ServiceLatVar)),
+				tree.NewDFloat(tree.DFloat(s.ContentionTimeAvg)),
+				tree.NewDFloat(tree.DFloat(s.ContentionTimeVar)),
+				tree.NewDFloat(tree.DFloat(s.ContentionCausedTime)),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalSessionTraceTable exposes the latest trace collected on this
 // session (via SET TRACING={ON/OFF})
 //
@@ -832,6 +902,23 @@ CREATE TABLE crdb_internal.session_variables (
 				return err
 			}
 		}
+		// The cluster settings that can be overridden per session are hidden
+		// unless they are overridden.
+		overrides := p.SessionData().SettingOverrides
+		for _, sName := range settings.OverridableKeys(settings.SessionScope) {
+			s, ok := lookupSessionOverridableSetting(sName)
+			if !ok {
+				continue
+			}
+			_, overridden := overrides.Get(settings.SessionScope, sName)
+			if err := addRow(
+				tree.NewDString(sName),
+				tree.NewDString(settingOverrideVar(sName, s).Get(&p.extendedEvalCtx)),
+				tree.MakeDBool(tree.DBool(!overridden)),
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
@@ -1163,6 +1250,247 @@ CREATE TABLE crdb_internal.builtin_functions (
 	},
 }
 
+// clientVisibleErrors lists the errors originating below SQL that are
+// reported to clients with a dedicated SQLSTATE, along with a
+// representative instance used to compute it.
+var clientVisibleErrors = []struct {
+	name        string
+	err         error
+	description string
+}{
+	{
+		name:        "roachpb.TransactionRetryWithProtoRefreshError",
+		err:         &roachpb.TransactionRetryWithProtoRefreshError{},
+		description: "the transaction conflicted with another one and must be retried from the beginning",
+	},
+	{
+		name:        "roachpb.AmbiguousResultError",
+		err:         &roachpb.AmbiguousResultError{},
+		description: "the outcome of the statement (typically a COMMIT) is unknown; it may or may not have taken effect",
+	},
+	{
+		name:        "roachpb.NodeUnavailableError",
+		err:         &roachpb.NodeUnavailableError{},
+		description: "the node could not serve the request (e.g. because it is draining); the request was not evaluated",
+	},
+	{
+		name:        "context.Canceled",
+		err:         context.Canceled,
+		description: "the request was canceled before it completed",
+	},
+	{
+		name:        "context.DeadlineExceeded",
+		err:         context.DeadlineExceeded,
+		description: "the request did not complete before its deadline",
+	},
+	{
+		name:        "mon.BudgetExceededError",
+		err:         mon.MemoryResource.NewBudgetExceededError(0, 0, 0),
+		description: "the memory budget of the node or of the statement was exceeded",
+	},
+}
+
+// crdbInternalErrorCodesTable exposes the SQLSTATEs with which errors
+// originating below SQL are reported to clients, and whether clients can
+// handle them by retrying the transaction.
+var crdbInternalErrorCodesTable = virtualSchemaTable{
+	comment: "SQLSTATE codes of errors originating below SQL (RAM/static)",
+	schema: `
+CREATE TABLE crdb_internal.error_codes (
+  error       STRING NOT NULL,
+  sqlstate    STRING NOT NULL,
+  retryable   BOOL NOT NULL,
+  description STRING NOT NULL
+)`,
+	populate: func(ctx context.Context, _ *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		for _, e := range clientVisibleErrors {
+			code := pgerror.GetPGCode(e.err)
+			if err := addRow(
+				tree.NewDString(e.name),
+				tree.NewDString(code),
+				tree.MakeDBool(tree.DBool(pgerror.IsRetryableCode(code))),
+				tree.NewDString(e.description),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalRangeEventsTable exposes the lifecycle events of the ranges
+// (splits, merges, replica changes, lease transfers and snapshot applications)
+// recorded in system.rangelog, to help reconstruct the history of a range when
+// debugging availability problems.
+var crdbInternalRangeEventsTable = virtualSchemaTable{
+	comment: `range lifecycle events recorded in system.rangelog (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.range_events (
+  timestamp      TIMESTAMP NOT NULL,
+  range_id       INT NOT NULL,
+  store_id       INT NOT NULL,
+  event_type     STRING NOT NULL,
+  other_range_id INT,
+  reason         STRING,
+  details        STRING,
+  info           JSON
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.range_events"); err != nil {
+			return err
+		}
+		const query = `
+SELECT timestamp, "rangeID", "storeID", "eventType", "otherRangeID", info
+  FROM system.rangelog
+ ORDER BY timestamp, "uniqueID"`
+		rows, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.Query(
+			ctx, "crdb-internal-range-events-table", p.txn, query)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			reason, details, info := tree.DNull, tree.DNull, tree.DNull
+			if infoStr, ok := r[5].(*tree.DString); ok {
+				j, err := json.ParseJSON(string(*infoStr))
+				if err != nil {
+					return errors.Wrapf(err, "decoding info of range log event")
+				}
+				info = tree.NewDJSON(j)
+				if reason, err = jsonTextOrNull(j, "Reason"); err != nil {
+					return err
+				}
+				if details, err = jsonTextOrNull(j, "Details"); err != nil {
+					return err
+				}
+			}
+			if err := addRow(
+				r[0], // timestamp
+				r[1], // range_id
+				r[2], // store_id
+				r[3], // event_type
+				r[4], // other_range_id
+				reason,
+				details,
+				info,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalContentionEventsTable exposes the most recent contention events
+// encountered by the statements executed on this node, as reported by the KV
+// layer of every node that took part in their execution.
+var crdbInternalContentionEventsTable = virtualSchemaTable{
+	comment: `recent contention events encountered by statements executed on this node (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_contention_events (
+  recorded_at      TIMESTAMP NOT NULL,
+  application_name STRING NOT NULL,
+  statement        STRING NOT NULL,
+  key              BYTES NOT NULL,
+  pretty_key       STRING NOT NULL,
+  txn_id           UUID NOT NULL,
+  duration         INTERVAL NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.node_contention_events"); err != nil {
+			return err
+		}
+		sqlStats := p.extendedEvalCtx.sqlStatsCollector.sqlStats
+		if sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+		for _, ev := range sqlStats.contentionEvents.get() {
+			if err := addRow(
+				tree.MakeDTimestamp(ev.recordedAt, time.Microsecond),
+				tree.NewDString(ev.app),
+				tree.NewDString(ev.stmt),
+				tree.NewDBytes(tree.DBytes(ev.Key)),
+				tree.NewDString(ev.Key.String()),
+				tree.NewDUuid(tree.DUuid{UUID: ev.TxnMeta.ID}),
+				&tree.DInterval{Duration: duration.MakeDuration(ev.Duration.Nanoseconds(), 0, 0)},
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalAllocatorDecisionsTable exposes the actions that the allocator
+// would take, without carrying them out, for the ranges whose leases are held
+// by this node.
+var crdbInternalAllocatorDecisionsTable = virtualSchemaTable{
+	comment: `allocator dry run of the ranges led by this node (KV scan; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_allocator_decisions (
+  range_id              INT NOT NULL,
+  action                STRING NOT NULL,
+  changes               STRING,
+  reason                STRING,
+  lease_target_store_id INT,
+  details               JSON
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.node_allocator_decisions"); err != nil {
+			return err
+		}
+		response, err := p.ExecCfg().StatusServer.Allocator(ctx, &serverpb.AllocatorRequest{NodeId: "local"})
+		if err != nil {
+			return err
+		}
+		stringOrNull := func(s string) tree.Datum {
+			if s == "" {
+				return tree.DNull
+			}
+			return tree.NewDString(s)
+		}
+		for _, dryRun := range response.DryRuns {
+			leaseTarget := tree.DNull
+			if dryRun.LeaseTargetStoreID != 0 {
+				leaseTarget = tree.NewDInt(tree.DInt(dryRun.LeaseTargetStoreID))
+			}
+			details := tree.DNull
+			if dryRun.Details != "" {
+				j, err := json.ParseJSON(dryRun.Details)
+				if err != nil {
+					return err
+				}
+				details = tree.NewDJSON(j)
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(dryRun.RangeID)),
+				tree.NewDString(dryRun.Action),
+				stringOrNull(dryRun.Changes),
+				stringOrNull(dryRun.Reason),
+				leaseTarget,
+				details,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// jsonTextOrNull returns the text of the string value of key in the JSON
+// object, or NULL if there is no such value.
+func jsonTextOrNull(j json.JSON, key string) (tree.Datum, error) {
+	v, err := j.FetchValKey(key)
+	if err != nil || v == nil {
+		return tree.DNull, err
+	}
+	text, err := v.AsText()
+	if err != nil || text == nil {
+		return tree.DNull, err
+	}
+	return tree.NewDString(*text), nil
+}
+
 // crdbInternalCreateStmtsTable exposes the CREATE TABLE/CREATE VIEW
 // statements.
 //
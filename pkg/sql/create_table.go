@@ -18,8 +18,10 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -33,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -72,6 +75,11 @@ const (
 	storageParamBool storageParamType = iota
 	storageParamInt
 	storageParamFloat
+	storageParamString
+	// storageParamZoneConfig indicates a storage parameter that is translated
+	// into the field of the zone config of the table with the same name (see
+	// supportedZoneConfigOptions for the required type).
+	storageParamZoneConfig
 	storageParamUnimplemented
 )
 
@@ -105,6 +113,10 @@ var storageParamExpectedTypes = map[string]storageParamType{
 	`log_autovacuum_min_duration`:                 storageParamUnimplemented,
 	`toast.log_autovacuum_min_duration`:           storageParamUnimplemented,
 	`user_catalog_table`:                          storageParamUnimplemented,
+	`num_replicas`:                                storageParamZoneConfig,
+	`gc.ttlseconds`:                               storageParamZoneConfig,
+	`constraints`:                                 storageParamZoneConfig,
+	`ttl_expiration_column`:                       storageParamString,
 }
 
 func (n *createTableNode) startExec(params runParams) error {
@@ -264,6 +276,10 @@ func (n *createTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	if err := applyStorageParamZoneConfig(params, n.n, &desc); err != nil {
+		return err
+	}
+
 	// If we are in an explicit txn or the source has placeholders, we execute the
 	// CTAS query synchronously.
 	if n.n.As() && !params.p.ExtendedEvalContext().TxnImplicit {
@@ -1305,6 +1321,10 @@ func MakeTableDesc(
 		desc.PrimaryIndex.Partitioning = partitioning
 	}
 
+	if err := applyStorageParamTTL(ctx, st, semaCtx, n.StorageParams, &desc); err != nil {
+		return desc, err
+	}
+
 	// Once all the IDs have been allocated, we can add the Sequence dependencies
 	// as maybeAddSequenceDependencies requires ColumnIDs to be correct.
 	// Elements in n.Defs are not necessarily column definitions, so use a separate
@@ -1406,6 +1426,10 @@ func checkStorageParameters(
 			expectedType = types.Int
 		} else if validate == storageParamFloat {
 			expectedType = types.Float
+		} else if validate == storageParamString {
+			expectedType = types.String
+		} else if validate == storageParamZoneConfig {
+			expectedType = supportedZoneConfigOptions[sp.Key].requiredType
 		} else {
 			return unimplemented.NewWithIssuef(43299, "storage parameter %q", k)
 		}
@@ -1418,6 +1442,173 @@ func checkStorageParameters(
 	return nil
 }
 
+// applyStorageParamTTL sets the TTL expiration column of a newly created table
+// from the ttl_expiration_column storage parameter, if any. The column must be
+// a TIMESTAMP or TIMESTAMPTZ column of the table.
+func applyStorageParamTTL(
+	ctx context.Context,
+	st *cluster.Settings,
+	semaCtx *tree.SemaContext,
+	params tree.StorageParams,
+	desc *sqlbase.MutableTableDescriptor,
+) error {
+	for _, sp := range params {
+		if sp.Key != `ttl_expiration_column` {
+			continue
+		}
+		if st != nil {
+			if version := cluster.Version.ActiveVersionOrEmpty(ctx, st); version != (cluster.ClusterVersion{}) &&
+				!version.IsActive(cluster.VersionRowLevelTTL) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"storage parameter %q requires all nodes to be upgraded to %s",
+					tree.ErrString(&sp.Key), cluster.VersionByKey(cluster.VersionRowLevelTTL))
+			}
+		}
+		typedExpr, err := tree.TypeCheckAndRequire(sp.Value, semaCtx, types.String, string(sp.Key))
+		if err != nil {
+			return err
+		}
+		name, ok := typedExpr.(*tree.DString)
+		if !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"storage parameter %q requires a string literal", tree.ErrString(&sp.Key))
+		}
+		col, _, err := desc.FindColumnByName(tree.Name(*name))
+		if err != nil {
+			return err
+		}
+		switch col.Type.Family() {
+		case types.TimestampFamily, types.TimestampTZFamily:
+		default:
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"TTL expiration column %q must be of type TIMESTAMP or TIMESTAMPTZ, not %s",
+				col.Name, col.Type.SQLString())
+		}
+		desc.TTLExpirationColumnID = col.ID
+	}
+	return nil
+}
+
+// applyStorageParamZoneConfig writes the zone config of a newly created table
+// made up of the storage parameters of the CREATE TABLE statement that are
+// translated into zone config fields, if any. Unset fields are inherited from
+// the parent zones as usual.
+func applyStorageParamZoneConfig(
+	params runParams, n *tree.CreateTable, desc *sqlbase.MutableTableDescriptor,
+) error {
+	datums := make(map[tree.Name]tree.Datum)
+	for _, sp := range n.StorageParams {
+		if storageParamExpectedTypes[string(sp.Key)] != storageParamZoneConfig {
+			continue
+		}
+		typedExpr, err := tree.TypeCheckAndRequire(
+			sp.Value, &params.p.semaCtx, supportedZoneConfigOptions[sp.Key].requiredType, string(sp.Key),
+		)
+		if err != nil {
+			return err
+		}
+		datum, err := typedExpr.Eval(params.EvalContext())
+		if err != nil {
+			return err
+		}
+		if datum == tree.DNull {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"unsupported NULL value for %q", tree.ErrString(&sp.Key))
+		}
+		datums[sp.Key] = datum
+	}
+	if len(datums) == 0 {
+		return nil
+	}
+
+	// We iterate over zoneOptionKeys so that the optionStr string constructed
+	// for the event log is deterministic.
+	zone := zonepb.NewZoneConfig()
+	var optionStr strings.Builder
+	if err := func() (err error) {
+		// A setter may fail with an error-via-panic. Catch those.
+		defer func() {
+			if p := recover(); p != nil {
+				if errP, ok := p.(error); ok {
+					err = errP
+				} else {
+					panic(p)
+				}
+			}
+		}()
+		for i := range zoneOptionKeys {
+			name := tree.Name(zoneOptionKeys[i])
+			datum, ok := datums[name]
+			if !ok {
+				continue
+			}
+			supportedZoneConfigOptions[name].setter(zone, datum)
+			if optionStr.Len() > 0 {
+				optionStr.WriteString(", ")
+			}
+			fmt.Fprintf(&optionStr, "%s = %s", &name, datum)
+		}
+		return nil
+	}(); err != nil {
+		return err
+	}
+
+	// Validate the zone config as it will apply to the table, i.e. with the
+	// fields inherited from the database and default zones.
+	getKey := func(key roachpb.Key) (*roachpb.Value, error) {
+		kv, err := params.p.txn.Get(params.ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return kv.Value, nil
+	}
+	completeZone := protoutil.Clone(zone).(*zonepb.ZoneConfig)
+	if err := completeZoneConfig(completeZone, uint32(desc.ID), getKey); err != nil {
+		return err
+	}
+	if err := validateNoRepeatKeysInZone(completeZone); err != nil {
+		return err
+	}
+	if err := validateZoneAttrsAndLocalities(
+		params.ctx, params.extendedEvalCtx.StatusServer.Nodes, completeZone,
+	); err != nil {
+		return err
+	}
+	if err := completeZone.Validate(); err != nil {
+		return pgerror.Newf(pgcode.CheckViolation,
+			"could not validate zone config: %v", err)
+	}
+	if err := zone.ValidateTandemFields(); err != nil {
+		err = errors.Wrap(err, "could not validate zone config")
+		return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+	}
+
+	if _, err := writeZoneConfig(params.ctx, params.p.txn, desc.ID, desc.TableDesc(), zone,
+		params.ExecCfg(), false /* hasNewSubzones */); err != nil {
+		return err
+	}
+
+	// Record the zone config change for auditing, as ALTER TABLE ... CONFIGURE
+	// ZONE would.
+	zs := tree.ZoneSpecifier{TableOrIndex: tree.TableIndexName{Table: n.Table}}
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogSetZoneConfig,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			Target  string
+			Options string `json:",omitempty"`
+			User    string
+		}{
+			Target:  tree.AsStringWithFQNames(&zs, params.Ann()),
+			Options: optionStr.String(),
+			User:    params.SessionData().User,
+		},
+	)
+}
+
 // makeTableDesc creates a table descriptor from a CreateTable statement.
 func makeTableDesc(
 	params runParams,
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -40,7 +41,7 @@ func (d *delegator) delegateShowVar(n *tree.ShowVar) (tree.Statement, error) {
 		)
 	}
 
-	if _, ok := ValidVars[name]; !ok {
+	if _, ok := ValidVars[name]; !ok && !isSessionOverridableSetting(name) {
 		return nil, pgerror.Newf(pgcode.UndefinedObject,
 			"unrecognized configuration parameter %q", origName)
 	}
@@ -52,3 +53,11 @@ func (d *delegator) delegateShowVar(n *tree.ShowVar) (tree.Statement, error) {
 		nm.String(), varName,
 	))
 }
+
+// isSessionOverridableSetting returns whether name is the name of a cluster
+// setting that can be overridden per session, in which case it can be shown
+// like a session variable.
+func isSessionOverridableSetting(name string) bool {
+	s, ok := settings.Lookup(name, settings.LookupForLocalAccess)
+	return ok && settings.IsOverridable(s, settings.SessionScope)
+}
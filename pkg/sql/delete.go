@@ -64,12 +64,6 @@ type deleteRun struct {
 	rowIdxToRetIdx []int
 }
 
-// maxDeleteBatchSize is the max number of entries in the KV batch for
-// the delete operation (including secondary index updates, FK
-// cascading updates, etc), before the current KV batch is executed
-// and a new batch is started.
-const maxDeleteBatchSize = 10000
-
 func (d *deleteNode) startExec(params runParams) error {
 	if err := params.p.maybeSetSystemConfig(d.run.td.tableDesc().GetID()); err != nil {
 		return err
@@ -134,7 +128,7 @@ func (d *deleteNode) BatchedNext(params runParams) (bool, error) {
 		d.run.rowCount++
 
 		// Are we done yet with the current batch?
-		if d.run.td.curBatchSize() >= maxDeleteBatchSize {
+		if d.run.td.batchFull() {
 			break
 		}
 	}
@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -54,5 +55,15 @@ func resetSessionVars(ctx context.Context, m *sessionDataMutator) error {
 			}
 		}
 	}
+	for _, sName := range m.data.SettingOverrides.Names(settings.SessionScope) {
+		_, v, err := getSessionVar(sName, false /* missingOk */)
+		if err != nil {
+			return err
+		}
+		_, defVal := getSessionVarDefaultString(sName, v, m)
+		if err := v.Set(ctx, m, defVal); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,298 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package distsql
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsql/distsqltestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/fileutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+// colOperatorCorpusDir is the directory with the reproductions of the
+// failures of VerifyColOperator. Every failure found by the randomized tests
+// is saved there, and all of the files in it are replayed by
+// TestColOperatorCorpus, so the previously found failures are regression
+// tested once they are committed along with the fix.
+const colOperatorCorpusDir = "testdata/columnar_corpus"
+
+// colOperatorRepro is a machine-readable reproduction of a VerifyColOperator
+// failure. The types and the processor spec are stored as marshaled protos
+// and the datums are stored in the value encoding.
+type colOperatorRepro struct {
+	// Test and Error describe the failure for the humans reading the file and
+	// aren't used when replaying it.
+	Test        string     `json:"test"`
+	Error       string     `json:"error"`
+	AnyOrder    bool       `json:"any_order"`
+	InputTypes  [][][]byte `json:"input_types"`
+	Inputs      []encRows  `json:"inputs"`
+	OutputTypes [][]byte   `json:"output_types"`
+	Spec        []byte     `json:"spec"`
+}
+
+// encRows are the rows with every datum in the value encoding.
+type encRows [][][]byte
+
+func marshalTypes(typs []types.T) ([][]byte, error) {
+	res := make([][]byte, len(typs))
+	for i := range typs {
+		b, err := protoutil.Marshal(&typs[i])
+		if err != nil {
+			return nil, err
+		}
+		res[i] = b
+	}
+	return res, nil
+}
+
+func unmarshalTypes(encoded [][]byte) ([]types.T, error) {
+	res := make([]types.T, len(encoded))
+	for i, b := range encoded {
+		if err := protoutil.Unmarshal(b, &res[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// makeColOperatorRepro returns the reproduction of the failure err of
+// VerifyColOperator called with the given arguments.
+func makeColOperatorRepro(
+	testName string,
+	anyOrder bool,
+	inputTypes [][]types.T,
+	inputs []sqlbase.EncDatumRows,
+	outputTypes []types.T,
+	pspec *execinfrapb.ProcessorSpec,
+	err error,
+) (colOperatorRepro, error) {
+	r := colOperatorRepro{
+		Test:       testName,
+		Error:      err.Error(),
+		AnyOrder:   anyOrder,
+		InputTypes: make([][][]byte, len(inputTypes)),
+		Inputs:     make([]encRows, len(inputs)),
+	}
+	var alloc sqlbase.DatumAlloc
+	for i := range inputs {
+		typs, err := marshalTypes(inputTypes[i])
+		if err != nil {
+			return colOperatorRepro{}, err
+		}
+		r.InputTypes[i] = typs
+		r.Inputs[i] = make(encRows, len(inputs[i]))
+		for j, row := range inputs[i] {
+			r.Inputs[i][j] = make([][]byte, len(row))
+			for k := range row {
+				b, err := row[k].Encode(&inputTypes[i][k], &alloc, sqlbase.DatumEncoding_VALUE, nil /* appendTo */)
+				if err != nil {
+					return colOperatorRepro{}, err
+				}
+				r.Inputs[i][j][k] = b
+			}
+		}
+	}
+	var marshalErr error
+	if r.OutputTypes, marshalErr = marshalTypes(outputTypes); marshalErr != nil {
+		return colOperatorRepro{}, marshalErr
+	}
+	if r.Spec, marshalErr = protoutil.Marshal(pspec); marshalErr != nil {
+		return colOperatorRepro{}, marshalErr
+	}
+	return r, nil
+}
+
+// decode returns the arguments of VerifyColOperator stored in the
+// reproduction.
+func (r *colOperatorRepro) decode() (
+	inputTypes [][]types.T,
+	inputs []sqlbase.EncDatumRows,
+	outputTypes []types.T,
+	pspec *execinfrapb.ProcessorSpec,
+	err error,
+) {
+	if len(r.InputTypes) != len(r.Inputs) {
+		return nil, nil, nil, nil, fmt.Errorf(
+			"%d inputs with %d input types", len(r.Inputs), len(r.InputTypes),
+		)
+	}
+	inputTypes = make([][]types.T, len(r.InputTypes))
+	inputs = make([]sqlbase.EncDatumRows, len(r.Inputs))
+	for i := range r.Inputs {
+		if inputTypes[i], err = unmarshalTypes(r.InputTypes[i]); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		inputs[i] = make(sqlbase.EncDatumRows, len(r.Inputs[i]))
+		for j, row := range r.Inputs[i] {
+			if len(row) != len(inputTypes[i]) {
+				return nil, nil, nil, nil, fmt.Errorf(
+					"input %d row %d has %d columns, expected %d", i, j, len(row), len(inputTypes[i]),
+				)
+			}
+			inputs[i][j] = make(sqlbase.EncDatumRow, len(row))
+			for k, b := range row {
+				inputs[i][j][k] = sqlbase.EncDatumFromEncoded(sqlbase.DatumEncoding_VALUE, b)
+			}
+		}
+	}
+	if outputTypes, err = unmarshalTypes(r.OutputTypes); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	pspec = &execinfrapb.ProcessorSpec{}
+	if err = protoutil.Unmarshal(r.Spec, pspec); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return inputTypes, inputs, outputTypes, pspec, nil
+}
+
+// writeColOperatorRepro writes the reproduction into dir and returns the path
+// of the file. The name of the file is derived from the test name and the
+// contents, so the same failure is only saved once.
+func writeColOperatorRepro(dir string, r colOperatorRepro) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(b)
+	name := fmt.Sprintf("%s-%x.json", fileutil.EscapeFilename(r.Test), hash[:4])
+	path := filepath.Join(dir, name)
+	return path, ioutil.WriteFile(path, b, 0644)
+}
+
+func readColOperatorRepro(path string) (colOperatorRepro, error) {
+	var r colOperatorRepro
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	return r, json.Unmarshal(b, &r)
+}
+
+// saveColOperatorRepro saves the reproduction of the failure err of
+// VerifyColOperator called with the given arguments into the corpus. It is
+// meant to be called by the randomized tests before they fail, and the
+// problems with saving the reproduction are only logged so that they don't
+// hide the original failure.
+func saveColOperatorRepro(
+	t *testing.T,
+	anyOrder bool,
+	inputTypes [][]types.T,
+	inputs []sqlbase.EncDatumRows,
+	outputTypes []types.T,
+	pspec *execinfrapb.ProcessorSpec,
+	err error,
+) {
+	r, reproErr := makeColOperatorRepro(t.Name(), anyOrder, inputTypes, inputs, outputTypes, pspec, err)
+	if reproErr != nil {
+		t.Logf("couldn't make the reproduction of the failure: %v", reproErr)
+		return
+	}
+	path, reproErr := writeColOperatorRepro(colOperatorCorpusDir, r)
+	if reproErr != nil {
+		t.Logf("couldn't save the reproduction of the failure: %v", reproErr)
+		return
+	}
+	t.Logf("saved the reproduction of the failure to %s", path)
+}
+
+// TestColOperatorCorpus replays all of the reproductions of the failures
+// found by the randomized tests.
+func TestColOperatorCorpus(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	paths, err := filepath.Glob(filepath.Join(colOperatorCorpusDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("the corpus is empty")
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			r, err := readColOperatorRepro(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inputTypes, inputs, outputTypes, pspec, err := r.decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := distsqltestutils.VerifyColOperator(r.AnyOrder, inputTypes, inputs, outputTypes, pspec); err != nil {
+				t.Fatalf("%s (originally found by %s with %q)", err, r.Test, r.Error)
+			}
+		})
+	}
+}
+
+func TestColOperatorReproRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	inputTypes := []types.T{*types.Int, *types.String}
+	rows := sqlbase.EncDatumRows{
+		{sqlbase.IntEncDatum(1), sqlbase.StrEncDatum("a")},
+		{sqlbase.NullEncDatum(), sqlbase.StrEncDatum("b")},
+		{sqlbase.IntEncDatum(3), sqlbase.NullEncDatum()},
+	}
+	pspec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+		Core:  execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+		Post:  execinfrapb.PostProcessSpec{Filter: execinfrapb.Expression{Expr: "@1 > 0"}},
+	}
+	r, err := makeColOperatorRepro(
+		t.Name(), false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows},
+		inputTypes, pspec, fmt.Errorf("boom"),
+	)
+	require.NoError(t, err)
+	path, err := writeColOperatorRepro(dir, r)
+	require.NoError(t, err)
+
+	r, err = readColOperatorRepro(path)
+	require.NoError(t, err)
+	require.Equal(t, t.Name(), r.Test)
+	require.Equal(t, "boom", r.Error)
+	require.False(t, r.AnyOrder)
+	decodedTypes, decodedInputs, outputTypes, decodedSpec, err := r.decode()
+	require.NoError(t, err)
+	require.Len(t, decodedTypes, 1)
+	require.Len(t, decodedTypes[0], len(inputTypes))
+	require.Len(t, outputTypes, len(inputTypes))
+	for i := range inputTypes {
+		require.True(t, decodedTypes[0][i].Identical(&inputTypes[i]))
+		require.True(t, outputTypes[i].Identical(&inputTypes[i]))
+	}
+	require.Equal(t, pspec.String(), decodedSpec.String())
+	require.Len(t, decodedInputs, 1)
+	require.Len(t, decodedInputs[0], len(rows))
+	for i := range rows {
+		require.Equal(t, rows[i].String(inputTypes), decodedInputs[0][i].String(inputTypes))
+	}
+	require.NoError(t, distsqltestutils.VerifyColOperator(false /* anyOrder */, decodedTypes, decodedInputs, outputTypes, decodedSpec))
+}
@@ -0,0 +1,348 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package distsql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// makeMultiInputFlowSpec creates a flow in which every one of the inputs is
+// produced by a values processor whose output is routed with the given router
+// type to nMergers noop processors. Every merger merges one stream from every
+// producer with an ordered synchronizer, and the outputs of the mergers are
+// merged again by the final noop processor, which sends the rows to the sync
+// response. All of the inputs must be sorted according to ordering.
+func makeMultiInputFlowSpec(
+	rng *rand.Rand,
+	typs []types.T,
+	inputs []sqlbase.EncDatumRows,
+	ordering execinfrapb.Ordering,
+	routerType execinfrapb.OutputRouterSpec_Type,
+	nMergers int,
+	maxNum int,
+) (execinfrapb.FlowSpec, error) {
+	var rangeRouterSpec execinfrapb.OutputRouterSpec_RangeRouterSpec
+	if routerType == execinfrapb.OutputRouterSpec_BY_RANGE {
+		// Split the values of the first column into nMergers spans at random
+		// points. NULLs are encoded as 0x00, so they belong to the first span.
+		breaks := rng.Perm(maxNum - 1)[:nMergers-1]
+		sort.Ints(breaks)
+		start := []byte{0x00}
+		for i := 0; i < nMergers; i++ {
+			end := []byte(keys.MaxKey)
+			if i < len(breaks) {
+				end = encoding.EncodeVarintAscending(nil, int64(breaks[i]+1))
+			}
+			rangeRouterSpec.Spans = append(rangeRouterSpec.Spans, execinfrapb.OutputRouterSpec_RangeRouterSpec_Span{
+				Start: start, End: end, Stream: int32(i),
+			})
+			start = end
+		}
+		rangeRouterSpec.Encodings = []execinfrapb.OutputRouterSpec_RangeRouterSpec_ColumnEncoding{
+			{Column: 0, Encoding: sqlbase.DatumEncoding_ASCENDING_KEY},
+		}
+	}
+
+	var spec execinfrapb.FlowSpec
+	mergerStreams := make([][]execinfrapb.StreamEndpointSpec, nMergers)
+	for i, rows := range inputs {
+		values, err := execinfra.GenerateValuesSpec(typs, rows, 1+rng.Intn(len(rows)+1))
+		if err != nil {
+			return execinfrapb.FlowSpec{}, err
+		}
+		output := execinfrapb.OutputRouterSpec{
+			Type:            routerType,
+			RangeRouterSpec: rangeRouterSpec,
+		}
+		if routerType == execinfrapb.OutputRouterSpec_BY_HASH {
+			output.HashColumns = []uint32{uint32(rng.Intn(len(typs)))}
+		}
+		for j := 0; j < nMergers; j++ {
+			stream := execinfrapb.StreamEndpointSpec{
+				Type:     execinfrapb.StreamEndpointSpec_LOCAL,
+				StreamID: execinfrapb.StreamID(i*nMergers + j),
+			}
+			output.Streams = append(output.Streams, stream)
+			mergerStreams[j] = append(mergerStreams[j], stream)
+		}
+		spec.Processors = append(spec.Processors, execinfrapb.ProcessorSpec{
+			Core:        execinfrapb.ProcessorCoreUnion{Values: &values},
+			Output:      []execinfrapb.OutputRouterSpec{output},
+			ProcessorID: int32(len(spec.Processors)),
+		})
+	}
+
+	var finalStreams []execinfrapb.StreamEndpointSpec
+	for j := 0; j < nMergers; j++ {
+		stream := execinfrapb.StreamEndpointSpec{
+			Type:     execinfrapb.StreamEndpointSpec_LOCAL,
+			StreamID: execinfrapb.StreamID(len(inputs)*nMergers + j),
+		}
+		finalStreams = append(finalStreams, stream)
+		spec.Processors = append(spec.Processors, execinfrapb.ProcessorSpec{
+			Input: []execinfrapb.InputSyncSpec{{
+				Type:        execinfrapb.InputSyncSpec_ORDERED,
+				Ordering:    ordering,
+				Streams:     mergerStreams[j],
+				ColumnTypes: typs,
+			}},
+			Core: execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+			Output: []execinfrapb.OutputRouterSpec{{
+				Type:    execinfrapb.OutputRouterSpec_PASS_THROUGH,
+				Streams: []execinfrapb.StreamEndpointSpec{stream},
+			}},
+			ProcessorID: int32(len(spec.Processors)),
+		})
+	}
+
+	spec.Processors = append(spec.Processors, execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{
+			Type:        execinfrapb.InputSyncSpec_ORDERED,
+			Ordering:    ordering,
+			Streams:     finalStreams,
+			ColumnTypes: typs,
+		}},
+		Core: execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+		Output: []execinfrapb.OutputRouterSpec{{
+			Type:    execinfrapb.OutputRouterSpec_PASS_THROUGH,
+			Streams: []execinfrapb.StreamEndpointSpec{{Type: execinfrapb.StreamEndpointSpec_SYNC_RESPONSE}},
+		}},
+		ProcessorID: int32(len(spec.Processors)),
+	})
+	return spec, nil
+}
+
+// runSyncFlow runs the flow on the given server with the given vectorize mode
+// and returns the rows it produced.
+func runSyncFlow(
+	ctx context.Context,
+	distSQLSrv *ServerImpl,
+	spec execinfrapb.FlowSpec,
+	typs []types.T,
+	vectorize sessiondata.VectorizeExecMode,
+) (sqlbase.EncDatumRows, error) {
+	req := execinfrapb.SetupFlowRequest{Version: execinfra.Version, Flow: spec}
+	req.EvalContext.Vectorize = int32(vectorize)
+	rb := distsqlutils.NewRowBuffer(typs, nil /* rows */, distsqlutils.RowBufferArgs{})
+	ctx, flow, err := distSQLSrv.SetupSyncFlow(ctx, &distSQLSrv.memMonitor, &req, rb)
+	if err != nil {
+		return nil, err
+	}
+	defer flow.Cleanup(ctx)
+	if err := flow.Start(ctx, func() {}); err != nil {
+		return nil, err
+	}
+	flow.Wait()
+	var res sqlbase.EncDatumRows
+	for {
+		row, meta := rb.Next()
+		if meta != nil {
+			if meta.Err != nil {
+				return nil, meta.Err
+			}
+			continue
+		}
+		if row == nil {
+			return res, nil
+		}
+		res = append(res, row)
+	}
+}
+
+// TestRoutersAndSynchronizersAgainstRowFlow runs randomized flows that fan out
+// multiple inputs with the hash and range routers and merge them back with the
+// ordered synchronizers, and verifies that the vectorized flows produce the
+// same merged output as the row-based ones. There is no vectorized mirror
+// router, so the flows with the mirror router are only run by the row-based
+// flow infrastructure, and their output is only checked against the expected
+// rows.
+func TestRoutersAndSynchronizersAgainstRowFlow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	distSQLSrv := s.DistSQLServer().(*ServerImpl)
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 10
+	maxInputs := 4
+	maxMergers := 4
+	maxRows := 100
+	maxCols := 3
+	maxNum := 10
+	intTyps := make([]types.T, maxCols)
+	for i := range intTyps {
+		intTyps[i] = *types.Int
+	}
+
+	for run := 0; run < nRuns; run++ {
+		for _, routerType := range []execinfrapb.OutputRouterSpec_Type{
+			execinfrapb.OutputRouterSpec_BY_HASH,
+			execinfrapb.OutputRouterSpec_BY_RANGE,
+			execinfrapb.OutputRouterSpec_MIRROR,
+		} {
+			nInputs := 1 + rng.Intn(maxInputs)
+			nMergers := 2 + rng.Intn(maxMergers-1)
+			typs := intTyps[:1+rng.Intn(maxCols)]
+			// Note: we're ordering on all of the columns since otherwise the
+			// merged output is not fully deterministic.
+			ordering := execinfrapb.Ordering{Columns: generateColumnOrdering(rng, len(typs), len(typs))}
+			colOrdering := execinfrapb.ConvertToColumnOrdering(ordering)
+			var alloc sqlbase.DatumAlloc
+			sortRows := func(rows sqlbase.EncDatumRows) {
+				sort.SliceStable(rows, func(i, j int) bool {
+					cmp, err := rows[i].Compare(typs, &alloc, colOrdering, &evalCtx, rows[j])
+					if err != nil {
+						t.Fatal(err)
+					}
+					return cmp < 0
+				})
+			}
+
+			inputs := make([]sqlbase.EncDatumRows, nInputs)
+			var expected sqlbase.EncDatumRows
+			for i := range inputs {
+				inputs[i] = sqlbase.MakeRandIntRowsInRange(rng, rng.Intn(maxRows), len(typs), maxNum, nullProbability)
+				sortRows(inputs[i])
+				copies := 1
+				if routerType == execinfrapb.OutputRouterSpec_MIRROR {
+					copies = nMergers
+				}
+				for c := 0; c < copies; c++ {
+					expected = append(expected, inputs[i]...)
+				}
+			}
+			sortRows(expected)
+
+			spec, err := makeMultiInputFlowSpec(rng, typs, inputs, ordering, routerType, nMergers, maxNum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			modes := []sessiondata.VectorizeExecMode{sessiondata.VectorizeOff}
+			if routerType != execinfrapb.OutputRouterSpec_MIRROR {
+				modes = append(modes, sessiondata.VectorizeExperimentalAlways)
+			}
+			for _, mode := range modes {
+				actual, err := runSyncFlow(ctx, distSQLSrv, spec, typs, mode)
+				if err != nil {
+					fmt.Printf("--- seed = %d router = %s vectorize = %s ---\n", seed, routerType, mode)
+					t.Fatal(err)
+				}
+				if actual.String(typs) != expected.String(typs) {
+					fmt.Printf("--- seed = %d router = %s vectorize = %s ---\n", seed, routerType, mode)
+					for i, rows := range inputs {
+						prettyPrintInput(rows, typs, fmt.Sprintf("t%d", i))
+					}
+					t.Fatalf("expected:\n%s\nactual:\n%s", expected.String(typs), actual.String(typs))
+				}
+			}
+		}
+	}
+}
+
+// TestInterleavedReaderJoinerAgainstRowFlow runs random joins of interleaved
+// tables, which are planned with an interleaved reader joiner, and checks that
+// the results are the same when the joiner is wrapped into a vectorized flow
+// and when the whole flow is run by the row-based engine.
+func TestInterleavedReaderJoinerAgainstRowFlow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 20
+	maxRows := 100
+	maxNum := 10
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.parent (pid INT PRIMARY KEY, a INT)`)
+	sqlDB.Exec(t, `CREATE TABLE d.child (
+  pid INT, cid INT, b INT, PRIMARY KEY (pid, cid)
+) INTERLEAVE IN PARENT d.parent (pid)`)
+	randVal := func() string {
+		if rng.Float64() < nullProbability {
+			return "NULL"
+		}
+		return fmt.Sprint(rng.Intn(maxNum))
+	}
+	for i, n := 0, rng.Intn(maxRows); i < n; i++ {
+		sqlDB.Exec(t, fmt.Sprintf(
+			`UPSERT INTO d.parent VALUES (%d, %s)`, rng.Intn(maxNum), randVal(),
+		))
+	}
+	for i, n := 0, rng.Intn(maxRows); i < n; i++ {
+		sqlDB.Exec(t, fmt.Sprintf(
+			`UPSERT INTO d.child VALUES (%d, %d, %s)`, rng.Intn(maxNum), rng.Intn(maxNum), randVal(),
+		))
+	}
+
+	joinTypes := []string{"INNER", "LEFT", "RIGHT", "FULL"}
+	sqlDB.Exec(t, `SET distsql = always`)
+	for run := 0; run < nRuns; run++ {
+		var filters []string
+		if rng.Intn(2) == 0 {
+			filters = append(filters, fmt.Sprintf("p.a > %d", rng.Intn(maxNum)))
+		}
+		if rng.Intn(2) == 0 {
+			filters = append(filters, fmt.Sprintf("c.b < %d", rng.Intn(maxNum)))
+		}
+		query := fmt.Sprintf(
+			`SELECT p.pid, p.a, c.pid, c.cid, c.b FROM d.parent AS p %s JOIN d.child AS c ON p.pid = c.pid`,
+			joinTypes[rng.Intn(len(joinTypes))],
+		)
+		if len(filters) > 0 {
+			query += " WHERE " + strings.Join(filters, " AND ")
+		}
+		// The ordering includes all of the columns since otherwise the results
+		// are not fully deterministic.
+		query += " ORDER BY 1, 2, 3, 4, 5"
+
+		sqlDB.Exec(t, `SET vectorize = off`)
+		expected := sqlDB.QueryStr(t, query)
+		sqlDB.Exec(t, `SET vectorize = experimental_always`)
+		actual := sqlDB.QueryStr(t, query)
+		if !reflect.DeepEqual(expected, actual) {
+			fmt.Printf("--- seed = %d ---\n", seed)
+			t.Fatalf("query: %s\nexpected:\n%v\nactual:\n%v", query, expected, actual)
+		}
+	}
+}
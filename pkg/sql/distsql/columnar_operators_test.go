@@ -13,6 +13,7 @@ package distsql
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 	"strings"
@@ -22,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsql/distsqltestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -32,6 +35,18 @@ import (
 
 const nullProbability = 0.2
 const randTypesProbability = 0.5
+const limitProbability = 0.3
+
+// spillProbability is the probability of running the vectorized flow under a
+// tiny memory limit which forces the operators to spill to disk.
+const spillProbability = 0.3
+
+func generateMemoryLimit(rng *rand.Rand) int64 {
+	if rng.Float64() < spillProbability {
+		return 1
+	}
+	return 0
+}
 
 func TestAggregatorAgainstProcessor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -49,6 +64,10 @@ func TestAggregatorAgainstProcessor(t *testing.T) {
 	for i, aggFn := range colexec.SupportedAggFns {
 		aggregations[i].Func = aggFn
 		aggregations[i].ColIdx = []uint32{uint32(i + 1)}
+		if aggFn == execinfrapb.AggregatorSpec_STRING_AGG {
+			// The delimiter of string_agg is a constant argument.
+			aggregations[i].Arguments = []execinfrapb.Expression{{Expr: "'-'"}}
+		}
 	}
 	inputTypes := make([]types.T, len(aggregations)+1)
 	inputTypes[0] = *types.Int
@@ -64,10 +83,16 @@ func TestAggregatorAgainstProcessor(t *testing.T) {
 			for {
 				aggTyp = sqlbase.RandType(rng)
 				aggInputTypes := []types.T{*aggTyp}
-				if aggFn == execinfrapb.AggregatorSpec_COUNT_ROWS {
+				switch aggFn {
+				case execinfrapb.AggregatorSpec_COUNT_ROWS:
 					// Count rows takes no arguments.
 					aggregations[i].ColIdx = []uint32{}
 					aggInputTypes = aggInputTypes[:0]
+				case execinfrapb.AggregatorSpec_STRING_AGG:
+					// The delimiter is a string, so the aggregated column must be
+					// one as well.
+					aggTyp = types.String
+					aggInputTypes = []types.T{*aggTyp, *aggTyp}
 				}
 				if isSupportedType(aggTyp) {
 					if _, outputType, err := execinfrapb.GetAggregateInfo(aggFn, aggInputTypes...); err == nil {
@@ -96,17 +121,20 @@ func TestAggregatorAgainstProcessor(t *testing.T) {
 			if !hashAgg {
 				aggregatorSpec.OrderedGroupCols = []uint32{0}
 			}
+			limit, offset := generateLimitAndOffset(rng, nRows)
 			pspec := &execinfrapb.ProcessorSpec{
 				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
 				Core:  execinfrapb.ProcessorCoreUnion{Aggregator: aggregatorSpec},
+				Post:  execinfrapb.PostProcessSpec{Limit: limit, Offset: offset},
 			}
-			if err := verifyColOperator(
+			if err := distsqltestutils.VerifyColOperator(
 				hashAgg, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, outputTypes, pspec,
 			); err != nil {
-				fmt.Printf("--- seed = %d run = %d hash = %t ---\n",
-					seed, run, hashAgg)
+				fmt.Printf("--- seed = %d run = %d hash = %t limit = %d offset = %d ---\n",
+					seed, run, hashAgg, limit, offset)
 				prettyPrintTypes(inputTypes, "t" /* tableName */)
 				prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+				saveColOperatorRepro(t, hashAgg, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, outputTypes, pspec, err)
 				t.Fatal(err)
 			}
 		}
@@ -151,14 +179,19 @@ func TestSorterAgainstProcessor(t *testing.T) {
 			sorterSpec := &execinfrapb.SorterSpec{
 				OutputOrdering: execinfrapb.Ordering{Columns: orderingCols},
 			}
+			// A limit without a filter allows for the top K sorter to be used.
+			limit, offset := generateLimitAndOffset(rng, nRows)
 			pspec := &execinfrapb.ProcessorSpec{
 				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
 				Core:  execinfrapb.ProcessorCoreUnion{Sorter: sorterSpec},
+				Post:  execinfrapb.PostProcessSpec{Limit: limit, Offset: offset},
 			}
-			if err := verifyColOperator(false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec); err != nil {
-				fmt.Printf("--- seed = %d nCols = %d ---\n", seed, nCols)
+			memoryLimit := generateMemoryLimit(rng)
+			if err := distsqltestutils.VerifyColOperatorWithMemoryLimit(memoryLimit, false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec); err != nil {
+				fmt.Printf("--- seed = %d nCols = %d limit = %d offset = %d memory limit = %d ---\n", seed, nCols, limit, offset, memoryLimit)
 				prettyPrintTypes(inputTypes, "t" /* tableName */)
 				prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+				saveColOperatorRepro(t, false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec, err)
 				t.Fatal(err)
 			}
 		}
@@ -216,14 +249,17 @@ func TestSortChunksAgainstProcessor(t *testing.T) {
 					OutputOrdering:   execinfrapb.Ordering{Columns: orderingCols},
 					OrderingMatchLen: uint32(matchLen),
 				}
+				limit, offset := generateLimitAndOffset(rng, nRows)
 				pspec := &execinfrapb.ProcessorSpec{
 					Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
 					Core:  execinfrapb.ProcessorCoreUnion{Sorter: sorterSpec},
+					Post:  execinfrapb.PostProcessSpec{Limit: limit, Offset: offset},
 				}
-				if err := verifyColOperator(false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec); err != nil {
-					fmt.Printf("--- seed = %d nCols = %d ---\n", seed, nCols)
+				if err := distsqltestutils.VerifyColOperator(false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec); err != nil {
+					fmt.Printf("--- seed = %d nCols = %d limit = %d offset = %d ---\n", seed, nCols, limit, offset)
 					prettyPrintTypes(inputTypes, "t" /* tableName */)
 					prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+					saveColOperatorRepro(t, false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec, err)
 					t.Fatal(err)
 				}
 			}
@@ -231,6 +267,145 @@ func TestSortChunksAgainstProcessor(t *testing.T) {
 	}
 }
 
+func TestDistinctAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	var da sqlbase.DatumAlloc
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(context.Background())
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 10
+	nRows := 100
+	maxCols := 4
+	maxNum := 3
+	intTyps := make([]types.T, maxCols)
+	for i := range intTyps {
+		intTyps[i] = *types.Int
+	}
+
+	for run := 0; run < nRuns; run++ {
+		for nCols := 1; nCols <= maxCols; nCols++ {
+			for nDistinctCols := 1; nDistinctCols <= nCols; nDistinctCols++ {
+				for nOrderedCols := 0; nOrderedCols <= nDistinctCols; nOrderedCols++ {
+					var (
+						rows       sqlbase.EncDatumRows
+						inputTypes []types.T
+					)
+					if rng.Float64() < randTypesProbability {
+						inputTypes = generateRandomSupportedTypes(rng, nCols)
+						rows = sqlbase.RandEncDatumRowsOfTypes(rng, nRows, inputTypes)
+					} else {
+						// Small integers produce many duplicates.
+						inputTypes = intTyps[:nCols]
+						rows = sqlbase.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+					}
+
+					// The ordered columns are a prefix of the distinct ones, and the
+					// input is presorted on them.
+					distinctCols := generateColumnOrdering(rng, nCols, nDistinctCols)
+					orderedCols := execinfrapb.ConvertToColumnOrdering(
+						execinfrapb.Ordering{Columns: distinctCols[:nOrderedCols]},
+					)
+					sort.Slice(rows, func(i, j int) bool {
+						cmp, err := rows[i].Compare(inputTypes, &da, orderedCols, &evalCtx, rows[j])
+						if err != nil {
+							t.Fatal(err)
+						}
+						return cmp < 0
+					})
+
+					distinctSpec := &execinfrapb.DistinctSpec{
+						DistinctColumns: make([]uint32, nDistinctCols),
+						OrderedColumns:  make([]uint32, nOrderedCols),
+					}
+					for i, col := range distinctCols {
+						distinctSpec.DistinctColumns[i] = col.ColIdx
+						if i < nOrderedCols {
+							distinctSpec.OrderedColumns[i] = col.ColIdx
+						}
+					}
+					pspec := &execinfrapb.ProcessorSpec{
+						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+						Core:  execinfrapb.ProcessorCoreUnion{Distinct: distinctSpec},
+					}
+					// The output is deterministic only when the distinct is fully
+					// ordered since the first row of each group is emitted.
+					unordered := nOrderedCols < nDistinctCols
+					if err := distsqltestutils.VerifyColOperator(
+						unordered /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec,
+					); err != nil {
+						if unordered && strings.Contains(err.Error(), "unsorted distinct not supported") {
+							// The vectorized engine doesn't support the distinct on
+							// columns the input isn't ordered on yet.
+							continue
+						}
+						fmt.Printf("--- seed = %d run = %d nCols = %d distinct = %v ordered = %v ---\n",
+							seed, run, nCols, distinctSpec.DistinctColumns, distinctSpec.OrderedColumns)
+						prettyPrintTypes(inputTypes, "t" /* tableName */)
+						prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+						saveColOperatorRepro(t, unordered, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec, err)
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLimitAndOffsetAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(context.Background())
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 20
+	maxRows := 50
+	maxCols := 3
+	maxNum := 10
+	intTyps := make([]types.T, maxCols)
+	for i := range intTyps {
+		intTyps[i] = *types.Int
+	}
+
+	for run := 0; run < nRuns; run++ {
+		nRows := rng.Intn(maxRows + 1)
+		nCols := rng.Intn(maxCols) + 1
+		inputTypes := intTyps[:nCols]
+		rows := sqlbase.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+		for _, addFilter := range []bool{false, true} {
+			// Unlike generateLimitAndOffset, we always want to apply a limit or an
+			// offset here.
+			var limit, offset uint64
+			for limit == 0 && offset == 0 {
+				limit = uint64(rng.Intn(nRows + 2))
+				offset = uint64(rng.Intn(nRows + 2))
+			}
+			var filter execinfrapb.Expression
+			if addFilter {
+				// The filter must be applied before the limit and the offset.
+				filter = execinfrapb.Expression{Expr: fmt.Sprintf("@1 < %d", rng.Intn(maxNum))}
+			}
+			pspec := &execinfrapb.ProcessorSpec{
+				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+				Core:  execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+				Post:  execinfrapb.PostProcessSpec{Filter: filter, Limit: limit, Offset: offset},
+			}
+			if err := distsqltestutils.VerifyColOperator(false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec); err != nil {
+				fmt.Printf("--- seed = %d run = %d filter = %q limit = %d offset = %d ---\n",
+					seed, run, filter.Expr, limit, offset)
+				prettyPrintTypes(inputTypes, "t" /* tableName */)
+				prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+				saveColOperatorRepro(t, false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, inputTypes, pspec, err)
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
 func TestHashJoinerAgainstProcessor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
@@ -257,6 +432,9 @@ func TestHashJoinerAgainstProcessor(t *testing.T) {
 		{
 			joinType: sqlbase.JoinType_LEFT_SEMI,
 		},
+		{
+			joinType: sqlbase.JoinType_LEFT_ANTI,
+		},
 	}
 
 	seed := rand.Int()
@@ -304,7 +482,8 @@ func TestHashJoinerAgainstProcessor(t *testing.T) {
 							}
 
 							outputTypes := append(inputTypes, inputTypes...)
-							if testSpec.joinType == sqlbase.JoinType_LEFT_SEMI {
+							if testSpec.joinType == sqlbase.JoinType_LEFT_SEMI ||
+								testSpec.joinType == sqlbase.JoinType_LEFT_ANTI {
 								outputTypes = inputTypes
 							}
 							outputColumns := make([]uint32, len(outputTypes))
@@ -333,25 +512,32 @@ func TestHashJoinerAgainstProcessor(t *testing.T) {
 								OnExpr:         onExpr,
 								Type:           testSpec.joinType,
 							}
+							limit, offset := generateLimitAndOffset(rng, nRows)
 							pspec := &execinfrapb.ProcessorSpec{
 								Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}, {ColumnTypes: inputTypes}},
 								Core:  execinfrapb.ProcessorCoreUnion{HashJoiner: hjSpec},
-								Post:  execinfrapb.PostProcessSpec{Projection: true, OutputColumns: outputColumns, Filter: filter},
+								Post: execinfrapb.PostProcessSpec{
+									Projection: true, OutputColumns: outputColumns, Filter: filter,
+									Limit: limit, Offset: offset,
+								},
 							}
-							if err := verifyColOperator(
+							memoryLimit := generateMemoryLimit(rng)
+							if err := distsqltestutils.VerifyColOperatorWithMemoryLimit(
+								memoryLimit,
 								true, /* anyOrder */
 								[][]types.T{inputTypes, inputTypes},
 								[]sqlbase.EncDatumRows{lRows, rRows},
 								outputTypes,
 								pspec,
 							); err != nil {
-								fmt.Printf("--- join type = %s onExpr = %q filter = %q seed = %d run = %d ---\n",
-									testSpec.joinType.String(), onExpr.Expr, filter.Expr, seed, run)
+								fmt.Printf("--- join type = %s onExpr = %q filter = %q limit = %d offset = %d memory limit = %d seed = %d run = %d ---\n",
+									testSpec.joinType.String(), onExpr.Expr, filter.Expr, limit, offset, memoryLimit, seed, run)
 								fmt.Printf("--- lEqCols = %v rEqCols = %v ---\n", lEqCols, rEqCols)
 								prettyPrintTypes(inputTypes, "left" /* tableName */)
 								prettyPrintTypes(inputTypes, "right" /* tableName */)
 								prettyPrintInput(lRows, inputTypes, "left" /* tableName */)
 								prettyPrintInput(rRows, inputTypes, "right" /* tableName */)
+								saveColOperatorRepro(t, true /* anyOrder */, [][]types.T{inputTypes, inputTypes}, []sqlbase.EncDatumRows{lRows, rRows}, outputTypes, pspec, err)
 								t.Fatal(err)
 							}
 							if onExpr.Expr == "" {
@@ -367,6 +553,213 @@ func TestHashJoinerAgainstProcessor(t *testing.T) {
 	}
 }
 
+func TestHashSetOpsAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 10
+	maxRows := 20
+	maxCols := 3
+	maxNum := 3
+	intTyps := make([]types.T, maxCols)
+	for i := range intTyps {
+		intTyps[i] = *types.Int
+	}
+
+	for run := 0; run < nRuns; run++ {
+		for _, joinType := range []sqlbase.JoinType{sqlbase.IntersectAllJoin, sqlbase.ExceptAllJoin} {
+			for nCols := 1; nCols <= maxCols; nCols++ {
+				var (
+					lRows, rRows sqlbase.EncDatumRows
+					inputTypes   []types.T
+				)
+				// The inputs have different numbers of rows (possibly zero) so that
+				// the multiplicities of the keys differ between them.
+				nLeftRows, nRightRows := rng.Intn(maxRows+1), rng.Intn(maxRows+1)
+				if rng.Float64() < randTypesProbability {
+					inputTypes = generateRandomSupportedTypes(rng, nCols)
+					lRows = sqlbase.RandEncDatumRowsOfTypes(rng, nLeftRows, inputTypes)
+					rRows = sqlbase.RandEncDatumRowsOfTypes(rng, nRightRows, inputTypes)
+					// Random rows rarely have the same values, so some of the right
+					// rows are copied from the left input.
+					for i := range rRows {
+						if len(lRows) > 0 && rng.Float64() < 0.5 {
+							rRows[i] = lRows[rng.Intn(len(lRows))]
+						}
+					}
+				} else {
+					// Small integers produce many duplicates.
+					inputTypes = intTyps[:nCols]
+					lRows = sqlbase.MakeRandIntRowsInRange(rng, nLeftRows, nCols, maxNum, nullProbability)
+					rRows = sqlbase.MakeRandIntRowsInRange(rng, nRightRows, nCols, maxNum, nullProbability)
+				}
+
+				// Set operations use all columns as equality columns, in any order.
+				eqCols := make([]uint32, nCols)
+				for i, col := range rng.Perm(nCols) {
+					eqCols[i] = uint32(col)
+				}
+				hjSpec := &execinfrapb.HashJoinerSpec{
+					LeftEqColumns:  eqCols,
+					RightEqColumns: eqCols,
+					Type:           joinType,
+				}
+				limit, offset := generateLimitAndOffset(rng, nLeftRows+1)
+				pspec := &execinfrapb.ProcessorSpec{
+					Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}, {ColumnTypes: inputTypes}},
+					Core:  execinfrapb.ProcessorCoreUnion{HashJoiner: hjSpec},
+					Post:  execinfrapb.PostProcessSpec{Limit: limit, Offset: offset},
+				}
+				if err := distsqltestutils.VerifyColOperator(
+					true, /* anyOrder */
+					[][]types.T{inputTypes, inputTypes},
+					[]sqlbase.EncDatumRows{lRows, rRows},
+					inputTypes,
+					pspec,
+				); err != nil {
+					fmt.Printf("--- join type = %s eqCols = %v limit = %d offset = %d seed = %d run = %d ---\n",
+						joinType.String(), eqCols, limit, offset, seed, run)
+					prettyPrintTypes(inputTypes, "left" /* tableName */)
+					prettyPrintTypes(inputTypes, "right" /* tableName */)
+					prettyPrintInput(lRows, inputTypes, "left" /* tableName */)
+					prettyPrintInput(rRows, inputTypes, "right" /* tableName */)
+					saveColOperatorRepro(t, true /* anyOrder */, [][]types.T{inputTypes, inputTypes}, []sqlbase.EncDatumRows{lRows, rRows}, inputTypes, pspec, err)
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+// TestCompositeKeysAgainstProcessor verifies that the vectorized operators that
+// use the hash table treat the values of composite types that are equal but
+// have different representations (like 1.0 and 1.00, or the collated strings
+// that differ only in case under a case-insensitive collation) the same way as
+// the row-by-row processors do.
+func TestCompositeKeysAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mustParseDecimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	var collationEnv tree.CollationEnvironment
+	const locale = "en_u_ks_level2"
+	mustMakeCollatedString := func(s string) tree.Datum {
+		d, err := tree.NewDCollatedString(s, locale, &collationEnv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	for _, tc := range []struct {
+		typ    *types.T
+		values tree.Datums
+	}{
+		{
+			typ: types.Decimal,
+			values: tree.Datums{
+				mustParseDecimal("0"), mustParseDecimal("-0"), mustParseDecimal("0.00"),
+				mustParseDecimal("1"), mustParseDecimal("1.0"), mustParseDecimal("1.00"),
+				mustParseDecimal("-2.5"), mustParseDecimal("-2.50"), tree.DNull,
+			},
+		},
+		{
+			typ: types.Float,
+			values: tree.Datums{
+				tree.NewDFloat(0), tree.NewDFloat(tree.DFloat(math.Copysign(0, -1))),
+				tree.NewDFloat(1.5), tree.NewDFloat(tree.DFloat(math.NaN())), tree.DNull,
+			},
+		},
+		{
+			typ: types.MakeCollatedString(types.String, locale),
+			values: tree.Datums{
+				mustMakeCollatedString("a"), mustMakeCollatedString("A"),
+				mustMakeCollatedString("b"), mustMakeCollatedString("B"), tree.DNull,
+			},
+		},
+	} {
+		seed := rand.Int()
+		rng := rand.New(rand.NewSource(int64(seed)))
+		const nRows = 20
+		inputTypes := []types.T{*tc.typ, *types.Int}
+		makeRows := func() sqlbase.EncDatumRows {
+			rows := make(sqlbase.EncDatumRows, nRows)
+			for i := range rows {
+				rows[i] = sqlbase.EncDatumRow{
+					sqlbase.DatumToEncDatum(tc.typ, tc.values[rng.Intn(len(tc.values))]),
+					sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i))),
+				}
+			}
+			return rows
+		}
+		lRows, rRows := makeRows(), makeRows()
+
+		for _, nullEquality := range []bool{false, true} {
+			outputTypes := append(inputTypes, inputTypes...)
+			pspec := &execinfrapb.ProcessorSpec{
+				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}, {ColumnTypes: inputTypes}},
+				Core: execinfrapb.ProcessorCoreUnion{HashJoiner: &execinfrapb.HashJoinerSpec{
+					LeftEqColumns:  []uint32{0},
+					RightEqColumns: []uint32{0},
+					Type:           sqlbase.JoinType_FULL_OUTER,
+					NullEquality:   nullEquality,
+				}},
+				Post: execinfrapb.PostProcessSpec{
+					Projection: true, OutputColumns: []uint32{0, 1, 2, 3},
+				},
+			}
+			if err := distsqltestutils.VerifyColOperator(
+				true, /* anyOrder */
+				[][]types.T{inputTypes, inputTypes},
+				[]sqlbase.EncDatumRows{lRows, rRows},
+				outputTypes,
+				pspec,
+			); err != nil {
+				fmt.Printf("--- hash joiner type = %s nullEquality = %t seed = %d ---\n",
+					tc.typ.SQLString(), nullEquality, seed)
+				prettyPrintInput(lRows, inputTypes, "left" /* tableName */)
+				prettyPrintInput(rRows, inputTypes, "right" /* tableName */)
+				saveColOperatorRepro(t, true /* anyOrder */, [][]types.T{inputTypes, inputTypes}, []sqlbase.EncDatumRows{lRows, rRows}, outputTypes, pspec, err)
+				t.Fatal(err)
+			}
+		}
+
+		if typeconv.FromColumnType(tc.typ) == coltypes.Datum {
+			// The aggregators don't support the keys without a native columnar
+			// representation.
+			continue
+		}
+		pspec := &execinfrapb.ProcessorSpec{
+			Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+			Core: execinfrapb.ProcessorCoreUnion{Aggregator: &execinfrapb.AggregatorSpec{
+				Type:      execinfrapb.AggregatorSpec_NON_SCALAR,
+				GroupCols: []uint32{0},
+				Aggregations: []execinfrapb.AggregatorSpec_Aggregation{
+					{Func: execinfrapb.AggregatorSpec_COUNT_ROWS},
+					{Func: execinfrapb.AggregatorSpec_SUM_INT, ColIdx: []uint32{1}},
+				},
+			}},
+		}
+		if err := distsqltestutils.VerifyColOperator(
+			true, /* anyOrder */
+			[][]types.T{inputTypes},
+			[]sqlbase.EncDatumRows{lRows},
+			[]types.T{*types.Int, *types.Int},
+			pspec,
+		); err != nil {
+			fmt.Printf("--- hash aggregator type = %s seed = %d ---\n", tc.typ.SQLString(), seed)
+			prettyPrintInput(lRows, inputTypes, "t" /* tableName */)
+			saveColOperatorRepro(t, true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{lRows}, []types.T{*types.Int, *types.Int}, pspec, err)
+			t.Fatal(err)
+		}
+	}
+}
+
 // generateEqualityColumns produces a random permutation of nEqCols random
 // columns on a table with nCols columns, so nEqCols must be not greater than
 // nCols.
@@ -462,9 +855,11 @@ func TestMergeJoinerAgainstProcessor(t *testing.T) {
 								lOrderingCols = generateColumnOrdering(rng, nCols, nOrderingCols)
 								rOrderingCols = generateColumnOrdering(rng, nCols, nOrderingCols)
 							}
-							// Set the directions of both columns to be the same.
+							// Set the directions and the placement of the NULLs of both
+							// columns to be the same.
 							for i, lCol := range lOrderingCols {
 								rOrderingCols[i].Direction = lCol.Direction
+								rOrderingCols[i].NullsOrder = lCol.NullsOrder
 							}
 
 							lMatchedCols := execinfrapb.ConvertToColumnOrdering(execinfrapb.Ordering{Columns: lOrderingCols})
@@ -514,24 +909,29 @@ func TestMergeJoinerAgainstProcessor(t *testing.T) {
 								RightOrdering: execinfrapb.Ordering{Columns: rOrderingCols},
 								Type:          testSpec.joinType,
 							}
+							limit, offset := generateLimitAndOffset(rng, nRows)
 							pspec := &execinfrapb.ProcessorSpec{
 								Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}, {ColumnTypes: inputTypes}},
 								Core:  execinfrapb.ProcessorCoreUnion{MergeJoiner: mjSpec},
-								Post:  execinfrapb.PostProcessSpec{Projection: true, OutputColumns: outputColumns, Filter: filter},
+								Post: execinfrapb.PostProcessSpec{
+									Projection: true, OutputColumns: outputColumns, Filter: filter,
+									Limit: limit, Offset: offset,
+								},
 							}
-							if err := verifyColOperator(
+							if err := distsqltestutils.VerifyColOperator(
 								testSpec.anyOrder,
 								[][]types.T{inputTypes, inputTypes},
 								[]sqlbase.EncDatumRows{lRows, rRows},
 								outputTypes,
 								pspec,
 							); err != nil {
-								fmt.Printf("--- join type = %s onExpr = %q filter = %q seed = %d run = %d ---\n",
-									testSpec.joinType.String(), onExpr.Expr, filter.Expr, seed, run)
+								fmt.Printf("--- join type = %s onExpr = %q filter = %q limit = %d offset = %d seed = %d run = %d ---\n",
+									testSpec.joinType.String(), onExpr.Expr, filter.Expr, limit, offset, seed, run)
 								prettyPrintTypes(inputTypes, "left" /* tableName */)
 								prettyPrintTypes(inputTypes, "right" /* tableName */)
 								prettyPrintInput(lRows, inputTypes, "left" /* tableName */)
 								prettyPrintInput(rRows, inputTypes, "right" /* tableName */)
+								saveColOperatorRepro(t, testSpec.anyOrder, [][]types.T{inputTypes, inputTypes}, []sqlbase.EncDatumRows{lRows, rRows}, outputTypes, pspec, err)
 								t.Fatal(err)
 							}
 							if onExpr.Expr == "" {
@@ -560,8 +960,9 @@ func generateColumnOrdering(
 	orderingCols := make([]execinfrapb.Ordering_Column, nOrderingCols)
 	for i, col := range rng.Perm(nCols)[:nOrderingCols] {
 		orderingCols[i] = execinfrapb.Ordering_Column{
-			ColIdx:    uint32(col),
-			Direction: execinfrapb.Ordering_Column_Direction(rng.Intn(2)),
+			ColIdx:     uint32(col),
+			Direction:  execinfrapb.Ordering_Column_Direction(rng.Intn(2)),
+			NullsOrder: execinfrapb.Ordering_Column_NullsOrder(rng.Intn(3)),
 		}
 	}
 	return orderingCols
@@ -675,13 +1076,17 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 						continue
 					}
 
+					limit, offset := generateLimitAndOffset(rng, nRows)
 					pspec := &execinfrapb.ProcessorSpec{
 						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
 						Core:  execinfrapb.ProcessorCoreUnion{Windower: windowerSpec},
+						Post:  execinfrapb.PostProcessSpec{Limit: limit, Offset: offset},
 					}
-					if err := verifyColOperator(true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, append(inputTypes, *types.Int), pspec); err != nil {
+					if err := distsqltestutils.VerifyColOperator(true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, append(inputTypes, *types.Int), pspec); err != nil {
+						fmt.Printf("--- limit = %d offset = %d ---\n", limit, offset)
 						prettyPrintTypes(inputTypes, "t" /* tableName */)
 						prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+						saveColOperatorRepro(t, true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, append(inputTypes, *types.Int), pspec, err)
 						t.Fatal(err)
 					}
 				}
@@ -690,9 +1095,150 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	}
 }
 
+func TestRenderExprsAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(context.Background())
+
+	seed := rand.Int()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	nRuns := 100
+	nRows := 100
+	maxCols := 4
+	maxRenders := 3
+
+	for run := 0; run < nRuns; run++ {
+		nCols := rng.Intn(maxCols) + 1
+		inputTypes := generateRandomSupportedTypes(rng, nCols)
+		rows := sqlbase.RandEncDatumRowsOfTypes(rng, nRows, inputTypes)
+
+		nRenders := rng.Intn(maxRenders) + 1
+		renderExprs := make([]execinfrapb.Expression, nRenders)
+		outputTypes := make([]types.T, nRenders)
+		for i := range renderExprs {
+			renderExprs[i] = generateRenderExpr(rng, inputTypes)
+			var helper execinfra.ExprHelper
+			if err := helper.Init(renderExprs[i], inputTypes, &evalCtx); err != nil {
+				t.Fatalf("seed = %d: generated invalid render expression %q: %s", seed, renderExprs[i].Expr, err)
+			}
+			outputTypes[i] = *helper.Expr.ResolvedType()
+		}
+
+		pspec := &execinfrapb.ProcessorSpec{
+			Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+			Core:  execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+			Post:  execinfrapb.PostProcessSpec{RenderExprs: renderExprs},
+		}
+		if err := distsqltestutils.VerifyColOperator(
+			false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, outputTypes, pspec,
+		); err != nil {
+			if isUnsupportedRenderErr(err) {
+				// Not all operations on all types can be planned by the vectorized
+				// engine yet.
+				continue
+			}
+			fmt.Printf("--- seed = %d run = %d renders = %v ---\n", seed, run, renderExprs)
+			prettyPrintTypes(inputTypes, "t" /* tableName */)
+			prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+			saveColOperatorRepro(t, false /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, outputTypes, pspec, err)
+			t.Fatal(err)
+		}
+	}
+}
+
+// generateRenderExpr generates a random render expression over columns of
+// inputTypes which is either a comparison, an arithmetic operation or a cast.
+// The operands of binary operations have identical types: the other operand is
+// either another column of the same type or a random constant of that type.
+func generateRenderExpr(rng *rand.Rand, inputTypes []types.T) execinfrapb.Expression {
+	colIdx := rng.Intn(len(inputTypes))
+	typ := &inputTypes[colIdx]
+	col := fmt.Sprintf("@%d", colIdx+1)
+
+	isNumeric := false
+	switch typ.Family() {
+	case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+		isNumeric = true
+	}
+
+	r := rng.Float64()
+	switch {
+	case r < 0.2 && (isNumeric || typ.Family() == types.BoolFamily):
+		targets := []string{"INT8", "FLOAT8", "DECIMAL"}
+		if typ.Family() == types.BoolFamily {
+			targets = []string{"INT8"}
+		}
+		return execinfrapb.Expression{
+			Expr: fmt.Sprintf("%s::%s", col, targets[rng.Intn(len(targets))]),
+		}
+	case r < 0.6 && isNumeric:
+		binOps := []string{"+", "-", "*", "/"}
+		return execinfrapb.Expression{
+			Expr: fmt.Sprintf("%s %s %s", col, binOps[rng.Intn(len(binOps))], generateOperand(rng, inputTypes, typ)),
+		}
+	default:
+		cmpOps := []string{"=", "<>", "<", "<=", ">", ">="}
+		return execinfrapb.Expression{
+			Expr: fmt.Sprintf("%s %s %s", col, cmpOps[rng.Intn(len(cmpOps))], generateOperand(rng, inputTypes, typ)),
+		}
+	}
+}
+
+// generateOperand returns either a reference to a random column of type typ
+// or a random non-NULL constant of that type.
+func generateOperand(rng *rand.Rand, inputTypes []types.T, typ *types.T) string {
+	if rng.Float64() < 0.5 {
+		var candidates []int
+		for i := range inputTypes {
+			if inputTypes[i].Identical(typ) {
+				candidates = append(candidates, i)
+			}
+		}
+		return fmt.Sprintf("@%d", candidates[rng.Intn(len(candidates))]+1)
+	}
+	d := sqlbase.RandDatum(rng, typ, false /* nullOk */)
+	// The constants are annotated with their types so that they can be parsed
+	// back (for example, NaN and Infinity floats).
+	return fmt.Sprintf("(%s)", tree.AsStringWithFlags(d, tree.FmtParsable))
+}
+
+// isUnsupportedRenderErr returns whether err indicates that a render
+// expression couldn't be planned by the vectorized engine (as opposed to the
+// vectorized engine producing different results).
+func isUnsupportedRenderErr(err error) bool {
+	msg := err.Error()
+	if strings.HasPrefix(msg, "different") {
+		return false
+	}
+	return strings.Contains(msg, "not supported") || strings.Contains(msg, "unsupported") ||
+		strings.Contains(msg, "unhandled")
+}
+
+// generateLimitAndOffset returns a random limit and offset to be used in a
+// PostProcessSpec of a processor with nRows input rows. With probability
+// 1-limitProbability both are zero (i.e. no limit nor offset is applied).
+// Otherwise, either or both are set to values that may exceed nRows in order
+// to exercise the edge cases.
+func generateLimitAndOffset(rng *rand.Rand, nRows int) (limit, offset uint64) {
+	if rng.Float64() >= limitProbability {
+		return 0, 0
+	}
+	switch rng.Intn(3) {
+	case 0:
+		limit = uint64(rng.Intn(nRows+2) + 1)
+	case 1:
+		offset = uint64(rng.Intn(nRows+2) + 1)
+	default:
+		limit = uint64(rng.Intn(nRows+2) + 1)
+		offset = uint64(rng.Intn(nRows + 2))
+	}
+	return limit, offset
+}
+
 func isSupportedType(typ *types.T) bool {
 	converted := typeconv.FromColumnType(typ)
-	return converted != coltypes.Unhandled
+	return converted != coltypes.Unhandled && converted != coltypes.Datum
 }
 
 // generateRandomSupportedTypes generates nCols random types that are supported
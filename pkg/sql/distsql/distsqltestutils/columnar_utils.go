@@ -8,12 +8,15 @@
 // by the Apache License, Version 2.0, included in the file
 // licenses/APL.txt.
 
-package distsql
+// Package distsqltestutils contains helpers for the tests and the fuzz harness
+// of the distsql package.
+package distsqltestutils
 
 import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/col/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -23,15 +26,58 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/marusama/semaphore"
 	"github.com/pkg/errors"
 )
 
-// verifyColOperator passes inputs through both the processor defined by pspec
+// setupError is returned by VerifyColOperator when the processor or the
+// columnar operator couldn't be created, as opposed to when the results of the
+// two engines don't match.
+type setupError struct {
+	cause error
+}
+
+func (e *setupError) Error() string { return e.cause.Error() }
+
+// Cause implements the causer interface.
+func (e *setupError) Cause() error { return e.cause }
+
+// IsSetupError returns whether VerifyColOperator failed to create either the
+// processor or the columnar operator.
+func IsSetupError(err error) bool {
+	_, ok := err.(*setupError)
+	return ok
+}
+
+// VerifyColOperator passes inputs through both the processor defined by pspec
 // and the corresponding columnar operator and verifies that the results match.
 //
 // anyOrder determines whether the results should be matched in order (when
 // anyOrder is false) or as sets (when anyOrder is true).
-func verifyColOperator(
+func VerifyColOperator(
+	anyOrder bool,
+	inputTypes [][]types.T,
+	inputs []sqlbase.EncDatumRows,
+	outputTypes []types.T,
+	pspec *execinfrapb.ProcessorSpec,
+) error {
+	return VerifyColOperatorWithMemoryLimit(
+		0 /* memoryLimit */, anyOrder, inputTypes, inputs, outputTypes, pspec,
+	)
+}
+
+// VerifyColOperatorWithMemoryLimit is the same as VerifyColOperator, but if
+// memoryLimit is positive, the buffering columnar operators are limited to
+// that many bytes of memory (and the temporary storage is available to them),
+// so that the disk-backed operators spill to disk when run with a tiny limit.
+// The processor always runs with the default memory limit.
+//
+// Not all of the buffering columnar operators can spill to disk, and the ones
+// that can't fail with an out of memory error under a tiny limit. Such errors
+// aren't considered to be mismatches with the processor.
+func VerifyColOperatorWithMemoryLimit(
+	memoryLimit int64,
 	anyOrder bool,
 	inputTypes [][]types.T,
 	inputs []sqlbase.EncDatumRows,
@@ -68,21 +114,34 @@ func verifyColOperator(
 
 	proc, err := rowexec.NewProcessor(ctx, flowCtx, 0, &pspec.Core, &pspec.Post, inputsProc, []execinfra.RowReceiver{nil}, nil)
 	if err != nil {
-		return err
+		return &setupError{cause: err}
 	}
 	outProc, ok := proc.(execinfra.RowSource)
 	if !ok {
 		return errors.New("processor is unexpectedly not a RowSource")
 	}
 
+	// The columnar flow gets its own copy of the server config so that the
+	// memory limit doesn't apply to the processor.
+	colFlowCtx := *flowCtx
+	colCfg := *flowCtx.Cfg
+	colFlowCtx.Cfg = &colCfg
+	forceSpilling := memoryLimit > 0
+	if forceSpilling {
+		colCfg.TestingKnobs.MemoryLimitBytes = memoryLimit
+		colCfg.TempFS = vfs.NewMem()
+		colCfg.TempStoragePath = "spilled"
+		colCfg.VecFDSemaphore = semaphore.New(colcontainer.DefaultMaxOpenFDs)
+	}
+
 	acc := evalCtx.Mon.MakeBoundAccount()
 	defer acc.Close(ctx)
 	testAllocator := colexec.NewAllocator(ctx, &acc)
 	columnarizers := make([]colexec.Operator, len(inputs))
 	for i, input := range inputsColOp {
-		c, err := colexec.NewColumnarizer(ctx, testAllocator, flowCtx, int32(i)+1, input)
+		c, err := colexec.NewColumnarizer(ctx, testAllocator, &colFlowCtx, int32(i)+1, input)
 		if err != nil {
-			return err
+			return &setupError{cause: err}
 		}
 		columnarizers[i] = c
 	}
@@ -91,14 +150,19 @@ func verifyColOperator(
 		Spec:                               pspec,
 		Inputs:                             columnarizers,
 		StreamingMemAccount:                &acc,
-		UseStreamingMemAccountForBuffering: true,
+		UseStreamingMemAccountForBuffering: !forceSpilling,
 		ProcessorConstructor:               rowexec.NewProcessor,
 	}
-	result, err := colexec.NewColOperator(ctx, flowCtx, args)
+	result, err := colexec.NewColOperator(ctx, &colFlowCtx, args)
 	if err != nil {
-		return err
+		return &setupError{cause: err}
 	}
 	defer func() {
+		for _, closer := range result.ToClose {
+			if err := closer.IdempotentClose(ctx); err != nil {
+				panic(err)
+			}
+		}
 		for _, memAccount := range result.BufferingOpMemAccounts {
 			memAccount.Close(ctx)
 		}
@@ -108,7 +172,7 @@ func verifyColOperator(
 	}()
 
 	outColOp, err := colexec.NewMaterializer(
-		flowCtx,
+		&colFlowCtx,
 		int32(len(inputs))+2,
 		result.Op,
 		outputTypes,
@@ -119,7 +183,7 @@ func verifyColOperator(
 		nil, /* cancelFlow */
 	)
 	if err != nil {
-		return err
+		return &setupError{cause: err}
 	}
 
 	outProc.Start(ctx)
@@ -159,6 +223,11 @@ func verifyColOperator(
 		}
 	}
 
+	if forceSpilling && len(procMetas) == 0 && len(colOpMetas) == 1 &&
+		sqlbase.IsOutOfMemoryError(colOpMetas[0].Err) {
+		// The columnar operator doesn't have a disk-backed fallback.
+		return nil
+	}
 	if len(procMetas) != len(colOpMetas) {
 		return errors.Errorf("different number of metas returned:\n"+
 			"processor returned\n%+v\n\ncolumnar operator returned\n%+v",
@@ -190,6 +259,13 @@ func verifyColOperator(
 			procRows, colOpRows, procMetas, colOpMetas)
 	}
 
+	if anyOrder && (pspec.Post.Limit != 0 || pspec.Post.Offset != 0) {
+		// The output is not ordered, so the processor and the columnar operator
+		// are free to return different subsets of rows when a limit or an offset
+		// is applied. Only the number of rows is deterministic in this case.
+		return nil
+	}
+
 	if anyOrder {
 		used := make([]bool, len(colOpRows))
 		for i, expStr := range procRows {
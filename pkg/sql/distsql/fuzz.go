@@ -0,0 +1,239 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build gofuzz
+
+package distsql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsql/distsqltestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+const (
+	fuzzMaxCols = 4
+	fuzzMaxRows = 64
+)
+
+// fuzzTypes are the column types of the fuzzed inputs. All of them are
+// supported natively by the vectorized engine.
+var fuzzTypes = []*types.T{
+	types.Int, types.Bool, types.String, types.Bytes, types.Float, types.Decimal,
+}
+
+// fuzzAggFns are the aggregate functions used by the fuzzed aggregators. The
+// functions that don't produce deterministic results (like ANY_NOT_NULL) are
+// omitted.
+var fuzzAggFns = []execinfrapb.AggregatorSpec_Func{
+	execinfrapb.AggregatorSpec_COUNT_ROWS,
+	execinfrapb.AggregatorSpec_COUNT,
+	execinfrapb.AggregatorSpec_MIN,
+	execinfrapb.AggregatorSpec_MAX,
+}
+
+// fuzzDecoder consumes the fuzzer input one byte at a time. Once the input is
+// exhausted, it keeps returning zeroes, so every input decodes into a valid
+// processor spec.
+type fuzzDecoder struct {
+	data []byte
+}
+
+func (d *fuzzDecoder) byte() byte {
+	if len(d.data) == 0 {
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+// intn returns a number in [0, n).
+func (d *fuzzDecoder) intn(n int) int {
+	return int(d.byte()) % n
+}
+
+func (d *fuzzDecoder) bool() bool {
+	return d.byte()&1 == 1
+}
+
+// datum decodes a datum of the given type. The values are taken from a small
+// domain so that the inputs contain duplicates and NULLs.
+func (d *fuzzDecoder) datum(typ *types.T) tree.Datum {
+	b := d.byte()
+	if b%8 == 0 {
+		return tree.DNull
+	}
+	v := int64(int8(b)) / 8
+	switch typ.Family() {
+	case types.IntFamily:
+		return tree.NewDInt(tree.DInt(v))
+	case types.BoolFamily:
+		return tree.MakeDBool(v%2 == 0)
+	case types.StringFamily:
+		return tree.NewDString(fmt.Sprintf("s%d", v))
+	case types.BytesFamily:
+		return tree.NewDBytes(tree.DBytes(fmt.Sprintf("b%d", v)))
+	case types.FloatFamily:
+		return tree.NewDFloat(tree.DFloat(v) / 4)
+	case types.DecimalFamily:
+		return &tree.DDecimal{Decimal: *apd.New(v, -1)}
+	default:
+		panic(fmt.Sprintf("unexpected type %s", typ))
+	}
+}
+
+// columns decodes a non-empty list of at most n column indices smaller than
+// nCols. If distinct is set, every column appears in the list at most once.
+func (d *fuzzDecoder) columns(n int, nCols int, distinct bool) []uint32 {
+	var cols []uint32
+	var seen [fuzzMaxCols]bool
+	for i, l := 0, 1+d.intn(n); i < l; i++ {
+		col := d.intn(nCols)
+		if distinct && seen[col] {
+			continue
+		}
+		seen[col] = true
+		cols = append(cols, uint32(col))
+	}
+	return cols
+}
+
+// processorSpec decodes a processor spec with a single input along with the
+// input rows and the output types of the processor. The spec is sanitized so
+// that it can be run by both the row-based and the vectorized engines, and
+// anyOrder is returned as true if the order of the output rows isn't
+// deterministic.
+func (d *fuzzDecoder) processorSpec() (
+	pspec *execinfrapb.ProcessorSpec,
+	inputTypes []types.T,
+	rows sqlbase.EncDatumRows,
+	outputTypes []types.T,
+	anyOrder bool,
+) {
+	nCols := 1 + d.intn(fuzzMaxCols)
+	inputTypes = make([]types.T, nCols)
+	for i := range inputTypes {
+		inputTypes[i] = *fuzzTypes[d.intn(len(fuzzTypes))]
+	}
+	pspec = &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+	}
+
+	coreOutputTypes := inputTypes
+	switch d.intn(4) {
+	case 0:
+		pspec.Core.Noop = &execinfrapb.NoopCoreSpec{}
+	case 1:
+		// The ordering includes all of the columns since otherwise the results
+		// are not fully deterministic.
+		var ordering execinfrapb.Ordering
+		for _, col := range d.columns(nCols, nCols, true /* distinct */) {
+			ordering.Columns = append(ordering.Columns, execinfrapb.Ordering_Column{
+				ColIdx:    col,
+				Direction: execinfrapb.Ordering_Column_Direction(d.intn(2)),
+			})
+		}
+		var seen [fuzzMaxCols]bool
+		for _, c := range ordering.Columns {
+			seen[c.ColIdx] = true
+		}
+		for col := 0; col < nCols; col++ {
+			if !seen[col] {
+				ordering.Columns = append(ordering.Columns, execinfrapb.Ordering_Column{ColIdx: uint32(col)})
+			}
+		}
+		pspec.Core.Sorter = &execinfrapb.SorterSpec{OutputOrdering: ordering}
+	case 2:
+		// Only the distinct columns are output since the values of the other
+		// columns depend on which of the duplicate rows is kept.
+		distinctCols := d.columns(nCols, nCols, true /* distinct */)
+		pspec.Core.Distinct = &execinfrapb.DistinctSpec{DistinctColumns: distinctCols}
+		pspec.Post.Projection = true
+		pspec.Post.OutputColumns = distinctCols
+		anyOrder = true
+	case 3:
+		agg := &execinfrapb.AggregatorSpec{}
+		if d.bool() {
+			agg.GroupCols = d.columns(nCols, nCols, true /* distinct */)
+		}
+		coreOutputTypes = nil
+		for i, n := 0, 1+d.intn(fuzzMaxCols); i < n; i++ {
+			aggFn := fuzzAggFns[d.intn(len(fuzzAggFns))]
+			aggregation := execinfrapb.AggregatorSpec_Aggregation{Func: aggFn}
+			switch aggFn {
+			case execinfrapb.AggregatorSpec_COUNT_ROWS:
+				coreOutputTypes = append(coreOutputTypes, *types.Int)
+			case execinfrapb.AggregatorSpec_COUNT:
+				aggregation.ColIdx = []uint32{uint32(d.intn(nCols))}
+				coreOutputTypes = append(coreOutputTypes, *types.Int)
+			default:
+				col := d.intn(nCols)
+				aggregation.ColIdx = []uint32{uint32(col)}
+				coreOutputTypes = append(coreOutputTypes, inputTypes[col])
+			}
+			agg.Aggregations = append(agg.Aggregations, aggregation)
+		}
+		pspec.Core.Aggregator = agg
+		anyOrder = true
+	}
+
+	outputTypes = coreOutputTypes
+	if !pspec.Post.Projection && d.bool() {
+		pspec.Post.Projection = true
+		pspec.Post.OutputColumns = d.columns(2*len(coreOutputTypes), len(coreOutputTypes), false /* distinct */)
+	}
+	if pspec.Post.Projection {
+		outputTypes = make([]types.T, len(pspec.Post.OutputColumns))
+		for i, col := range pspec.Post.OutputColumns {
+			outputTypes[i] = coreOutputTypes[col]
+		}
+	}
+	nRows := d.intn(fuzzMaxRows + 1)
+	if d.bool() {
+		pspec.Post.Limit = uint64(d.intn(nRows + 2))
+	}
+	if d.bool() {
+		pspec.Post.Offset = uint64(d.intn(nRows + 2))
+	}
+
+	rows = make(sqlbase.EncDatumRows, nRows)
+	for i := range rows {
+		rows[i] = make(sqlbase.EncDatumRow, nCols)
+		for j := range rows[i] {
+			rows[i][j] = sqlbase.DatumToEncDatum(&inputTypes[j], d.datum(&inputTypes[j]))
+		}
+	}
+	return pspec, inputTypes, rows, outputTypes, anyOrder
+}
+
+// FuzzColOperator decodes data into a processor spec and its input, runs it
+// with both the processor and the corresponding columnar operator, and panics
+// if their results don't match.
+func FuzzColOperator(data []byte) int {
+	d := fuzzDecoder{data: data}
+	pspec, inputTypes, rows, outputTypes, anyOrder := d.processorSpec()
+	if err := distsqltestutils.VerifyColOperator(
+		anyOrder, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, outputTypes, pspec,
+	); err != nil {
+		if distsqltestutils.IsSetupError(err) {
+			// The spec is not supported by one of the engines.
+			return 0
+		}
+		panic(fmt.Sprintf("%s\nspec: %s\ninput types: %v\ninput:\n%s",
+			err, pspec, inputTypes, rows.String(inputTypes)))
+	}
+	return 1
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -49,6 +50,11 @@ const minFlowDrainWait = 1 * time.Second
 
 var noteworthyMemoryUsageBytes = envutil.EnvOrDefaultInt64("COCKROACH_NOTEWORTHY_DISTSQL_MEMORY_USAGE", 1024*1024 /* 1MB */)
 
+// spillableFlowMemoryPercentage is the percentage of a flow's memory budget
+// (see execinfra.SettingFlowMemoryLimit) that the processors of the flow
+// which can fall back to temp storage can use before spilling to disk.
+const spillableFlowMemoryPercentage = 75
+
 // ServerImpl implements the server for the distributed SQL APIs.
 type ServerImpl struct {
 	execinfra.ServerConfig
@@ -152,6 +158,28 @@ func FlowVerIsCompatible(
 	return flowVer >= minAcceptedVersion && flowVer <= serverVersion
 }
 
+// flowParentSpanContext returns the context of the span that the span of a flow
+// follows from, or nil if there is none. This is the gateway's span propagated
+// in the SetupFlowRequest, if any, which makes the flow's span (and thus its
+// processors' spans) part of the trace tree of the statement. Otherwise, it is
+// the span of the request.
+func (ds *ServerImpl) flowParentSpanContext(
+	ctx context.Context, parentSpan opentracing.Span, req *execinfrapb.SetupFlowRequest,
+) opentracing.SpanContext {
+	if len(req.TraceInfo) > 0 {
+		sc, err := ds.Tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(req.TraceInfo))
+		if err != nil {
+			log.Warningf(ctx, "failed to extract the trace info of flow %s: %v", req.Flow.FlowID, err)
+		} else if !tracing.IsNoopContext(sc) {
+			return sc
+		}
+	}
+	if parentSpan == nil {
+		return nil
+	}
+	return parentSpan.Context()
+}
+
 // setupFlow creates a Flow.
 //
 // Args:
@@ -183,42 +211,32 @@ func (ds *ServerImpl) setupFlow(
 
 	const opName = "flow"
 	var sp opentracing.Span
-	if parentSpan == nil {
-		sp = ds.Tracer.(*tracing.Tracer).StartRootSpan(
-			opName, logtags.FromContext(ctx), tracing.NonRecordableSpan)
-	} else if localState.IsLocal {
+	if parentSpan != nil && localState.IsLocal {
 		// If we're a local flow, we don't need a "follows from" relationship: we're
 		// going to run this flow synchronously.
 		// TODO(andrei): localState.IsLocal is not quite the right thing to use.
 		//  If that field is unset, we might still want to create a child span if
 		//  this flow is run synchronously.
 		sp = tracing.StartChildSpan(opName, parentSpan, logtags.FromContext(ctx), false /* separateRecording */)
-	} else {
+	} else if parentCtx := ds.flowParentSpanContext(ctx, parentSpan, req); parentCtx != nil {
 		// We use FollowsFrom because the flow's span outlives the SetupFlow request.
 		// TODO(andrei): We should use something more efficient than StartSpan; we
 		// should use AmbientContext.AnnotateCtxWithSpan() but that interface
 		// doesn't currently support FollowsFrom relationships.
 		sp = ds.Tracer.StartSpan(
 			opName,
-			opentracing.FollowsFrom(parentSpan.Context()),
+			opentracing.FollowsFrom(parentCtx),
 			tracing.LogTagsFromCtx(ctx),
 		)
+	} else {
+		sp = ds.Tracer.(*tracing.Tracer).StartRootSpan(
+			opName, logtags.FromContext(ctx), tracing.NonRecordableSpan)
 	}
+	sp.SetTag(execinfrapb.FlowIDTagKey, req.Flow.FlowID.String())
+	sp.SetTag(execinfrapb.NodeIDTagKey, nodeID)
 	// sp will be Finish()ed by Flow.Cleanup().
 	ctx = opentracing.ContextWithSpan(ctx, sp)
 
-	// The monitor opened here are closed in Flow.Cleanup().
-	monitor := mon.MakeMonitor(
-		"flow",
-		mon.MemoryResource,
-		ds.Metrics.CurBytesCount,
-		ds.Metrics.MaxBytesHist,
-		-1, /* use default block size */
-		noteworthyMemoryUsageBytes,
-		ds.Settings,
-	)
-	monitor.Start(ctx, parentMonitor, mon.BoundAccount{})
-
 	makeLeaf := func(req *execinfrapb.SetupFlowRequest) (*client.Txn, error) {
 		tis := req.LeafTxnInputState
 		if tis == nil {
@@ -239,7 +257,6 @@ func (ds *ServerImpl) setupFlow(
 	var leafTxn *client.Txn
 	if localState.EvalContext != nil {
 		evalCtx = localState.EvalContext
-		evalCtx.Mon = &monitor
 	} else {
 		if localState.IsLocal {
 			return nil, nil, errors.AssertionFailedf(
@@ -302,7 +319,6 @@ func (ds *ServerImpl) setupFlow(
 			ClusterName: ds.ServerConfig.ClusterName,
 			NodeID:      nodeID,
 			ReCache:     ds.regexpCache,
-			Mon:         &monitor,
 			// Most processors will override this Context with their own context in
 			// ProcessorBase. StartInternal().
 			Context:          ctx,
@@ -323,6 +339,47 @@ func (ds *ServerImpl) setupFlow(
 			evalCtx.SessionData.SequenceState.SetLastSequenceIncremented(
 				*req.EvalContext.SeqState.LastSeqIncremented)
 		}
+		for _, o := range req.EvalContext.SettingOverrides {
+			overrides, err := sd.SettingOverrides.Set(settings.SessionScope, o.Name, o.Value)
+			if err != nil {
+				// The gateway might run a version in which the setting is defined
+				// differently; use the cluster-wide value in that case.
+				log.Warningf(ctx, "ignoring override of cluster setting: %v", err)
+				continue
+			}
+			sd.SettingOverrides = overrides
+		}
+	}
+
+	// The monitors opened here are closed in Flow.Cleanup(). They are set up
+	// once the setting overrides of the session are known, since the memory
+	// budget of the flow can be overridden.
+	flowMemLimit := execinfra.SettingFlowMemoryLimit.GetWithOverrides(
+		&ds.Settings.SV, evalCtx.SessionData.SettingOverrides,
+	)
+	monitor := mon.MakeMonitorWithLimit(
+		"flow",
+		mon.MemoryResource,
+		flowMemLimit,
+		ds.Metrics.CurBytesCount,
+		ds.Metrics.MaxBytesHist,
+		-1, /* use default block size */
+		noteworthyMemoryUsageBytes,
+		ds.Settings,
+	)
+	monitor.Start(ctx, parentMonitor, mon.BoundAccount{})
+	evalCtx.Mon = &monitor
+	var spillMon *mon.BytesMonitor
+	if flowMemLimit > 0 {
+		// The processors that can spill to disk are limited to a share of the
+		// flow's budget, so that they spill before the budget is exhausted
+		// rather than fail the query, and leave the rest of the budget to the
+		// processors that can't spill.
+		m := mon.MakeMonitorInheritWithLimit(
+			"flow-spillable", flowMemLimit/100*spillableFlowMemoryPercentage, &monitor,
+		)
+		m.Start(ctx, &monitor, mon.BoundAccount{})
+		spillMon = &m
 	}
 	// TODO(radu): we should sanity check some of these fields.
 	flowCtx := execinfra.FlowCtx{
@@ -333,6 +390,12 @@ func (ds *ServerImpl) setupFlow(
 		NodeID:         nodeID,
 		TraceKV:        req.TraceKV,
 		Local:          localState.IsLocal,
+		SpillMon:       spillMon,
+	}
+	// The progress of local plans isn't tracked, since they don't have a
+	// diagram to display it on.
+	if !localState.IsLocal && execinfra.SettingProgressReportInterval.Get(&ds.Settings.SV) > 0 {
+		flowCtx.Progress = execinfra.NewFlowProgress()
 	}
 	// req always contains the desired vectorize mode, regardless of whether we
 	// have non-nil localState.EvalContext. We don't want to update EvalContext
@@ -0,0 +1,88 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package distsql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// TestFlowSpanFollowsFromTraceInfo verifies that the span of a flow set up
+// with a SetupFlowRequest that carries trace info is created as a child of
+// the gateway's span, is recorded along with it and is tagged with the flow's
+// and the node's IDs.
+func TestFlowSpanFollowsFromTraceInfo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	distSQLSrv := s.DistSQLServer().(*ServerImpl)
+
+	tracer := distSQLSrv.Tracer.(*tracing.Tracer)
+	gatewaySpan := tracer.StartRootSpan("gateway", nil /* logTags */, tracing.RecordableSpan)
+	defer gatewaySpan.Finish()
+	tracing.StartRecording(gatewaySpan, tracing.SnowballRecording)
+
+	traceInfo := make(opentracing.TextMapCarrier)
+	if err := tracer.Inject(gatewaySpan.Context(), opentracing.TextMap, traceInfo); err != nil {
+		t.Fatal(err)
+	}
+	req := execinfrapb.SetupFlowRequest{Version: execinfra.Version, TraceInfo: traceInfo}
+	req.Flow = execinfrapb.FlowSpec{
+		FlowID: execinfrapb.FlowID{UUID: uuid.MakeV4()},
+		Processors: []execinfrapb.ProcessorSpec{{
+			Core: execinfrapb.ProcessorCoreUnion{Values: &execinfrapb.ValuesCoreSpec{}},
+			Output: []execinfrapb.OutputRouterSpec{{
+				Type:    execinfrapb.OutputRouterSpec_PASS_THROUGH,
+				Streams: []execinfrapb.StreamEndpointSpec{{Type: execinfrapb.StreamEndpointSpec_SYNC_RESPONSE}},
+			}},
+		}},
+	}
+
+	rb := distsqlutils.NewRowBuffer([]types.T{}, nil /* rows */, distsqlutils.RowBufferArgs{})
+	flowCtx, flow, err := distSQLSrv.setupFlow(
+		ctx, nil /* parentSpan */, &distSQLSrv.memMonitor, &req, rb, LocalState{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := tracing.GetRecording(opentracing.SpanFromContext(flowCtx))
+	flow.Cleanup(flowCtx)
+
+	if len(rec) == 0 {
+		t.Fatal("the flow's span is not recording")
+	}
+	gatewaySpanID := tracing.GetRecording(gatewaySpan)[0].SpanID
+	flowSpan := rec[0]
+	if flowSpan.ParentSpanID != gatewaySpanID {
+		t.Fatalf("expected the flow's span to be a child of span %d, found parent %d",
+			gatewaySpanID, flowSpan.ParentSpanID)
+	}
+	if id := flowSpan.Tags[execinfrapb.FlowIDTagKey]; id != req.Flow.FlowID.String() {
+		t.Fatalf("expected flow id tag %s, found %q", req.Flow.FlowID, id)
+	}
+	if id, expected := flowSpan.Tags[execinfrapb.NodeIDTagKey], fmt.Sprint(s.NodeID()); id != expected {
+		t.Fatalf("expected node id tag %s, found %q", expected, id)
+	}
+}
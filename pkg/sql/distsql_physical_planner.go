@@ -113,18 +113,26 @@ var logPlanDiagram = envutil.EnvOrDefaultBool("COCKROACH_DISTSQL_LOG_PLAN", fals
 // If true, for index joins we instantiate a join reader on every node that
 // has a stream (usually from a table reader). If false, there is a single join
 // reader.
-var distributeIndexJoin = settings.RegisterBoolSetting(
-	"sql.distsql.distribute_index_joins",
-	"if set, for index joins we instantiate a join reader on every node that has a "+
-		"stream; if not set, we use a single join reader",
-	true,
-)
-
-var planMergeJoins = settings.RegisterBoolSetting(
-	"sql.distsql.merge_joins.enabled",
-	"if set, we plan merge joins when possible",
-	true,
-)
+var distributeIndexJoin = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting(
+		"sql.distsql.distribute_index_joins",
+		"if set, for index joins we instantiate a join reader on every node that has a "+
+			"stream; if not set, we use a single join reader",
+		true,
+	)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
+
+var planMergeJoins = func() *settings.BoolSetting {
+	s := settings.RegisterBoolSetting(
+		"sql.distsql.merge_joins.enabled",
+		"if set, we plan merge joins when possible",
+		true,
+	)
+	s.SetOverridable(settings.SessionScope)
+	return s
+}()
 
 // livenessProvider provides just the methods of storage.NodeLiveness that the
 // DistSQLPlanner needs, to avoid importing all of storage.
@@ -455,6 +463,11 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		if err != nil {
 			return cannotDistribute, err
 		}
+		if n.hardLimit != 0 {
+			// A UNION ALL with a hard limit only avoids reading its right input if
+			// both inputs are planned on the gateway (see createPlanForSetOp).
+			return cannotDistribute, nil
+		}
 		return recLeft.compose(recRight), nil
 
 	case *valuesNode:
@@ -531,6 +544,15 @@ func (p *PlanningCtx) EvalContext() *tree.EvalContext {
 	return &p.ExtendedEvalCtx.EvalContext
 }
 
+// settingOverrides returns the overrides of cluster settings that are in
+// effect for the session on whose behalf the plan is created.
+func (p *PlanningCtx) settingOverrides() settings.Overrides {
+	if p.ExtendedEvalCtx == nil || p.ExtendedEvalCtx.SessionData == nil {
+		return settings.Overrides{}
+	}
+	return p.ExtendedEvalCtx.SessionData.SettingOverrides
+}
+
 // IsLocal returns true if this PlanningCtx is being used to plan a query that
 // has no remote flows.
 func (p *PlanningCtx) IsLocal() bool {
@@ -875,6 +897,14 @@ func initTableReaderSpec(
 		return nil, execinfrapb.PostProcessSpec{}, err
 	}
 	s.IndexIdx = indexIdx
+	if n.skipScanPrefixLen > 0 {
+		s.SkipScanPrefixLen = uint32(n.skipScanPrefixLen)
+		s.SkipScanGroupLimit = uint64(n.skipScanGroupLimit)
+		s.SkipScanSuffixSpans = make([]execinfrapb.TableReaderSpan, len(n.skipScanSuffixSpans))
+		for i := range n.skipScanSuffixSpans {
+			s.SkipScanSuffixSpans[i].Span = n.skipScanSuffixSpans[i]
+		}
+	}
 
 	// When a TableReader is running scrub checks, do not allow a
 	// post-processor. This is because the outgoing stream is a fixed
@@ -1218,6 +1248,19 @@ func (dsp *DistSQLPlanner) addSorters(p *PhysicalPlan, n *sortNode) {
 	)
 }
 
+// duplicateInsensitiveAggregations are the aggregation functions whose result
+// doesn't depend on how many times each input value is aggregated, on which
+// DISTINCT has no effect.
+var duplicateInsensitiveAggregations = map[execinfrapb.AggregatorSpec_Func]bool{
+	execinfrapb.AggregatorSpec_ANY_NOT_NULL: true,
+	execinfrapb.AggregatorSpec_BIT_AND:      true,
+	execinfrapb.AggregatorSpec_BIT_OR:       true,
+	execinfrapb.AggregatorSpec_BOOL_AND:     true,
+	execinfrapb.AggregatorSpec_BOOL_OR:      true,
+	execinfrapb.AggregatorSpec_MAX:          true,
+	execinfrapb.AggregatorSpec_MIN:          true,
+}
+
 // addAggregators adds aggregators corresponding to a groupNode and updates the plan to
 // reflect the groupNode. An evaluator stage is added if necessary.
 // Invariants assumed:
@@ -1248,7 +1291,10 @@ func (dsp *DistSQLPlanner) addAggregators(
 			return errors.Errorf("unknown aggregate %s", funcStr)
 		}
 		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
-		aggregations[i].Distinct = fholder.isDistinct()
+		// DISTINCT has no effect on some aggregations, in which case it is
+		// dropped so that they can be split into a local and a final stage.
+		aggregations[i].Distinct = fholder.isDistinct() &&
+			!duplicateInsensitiveAggregations[aggregations[i].Func]
 		if fholder.argRenderIdx != noRenderIdx {
 			aggregations[i].ColIdx = []uint32{uint32(p.PlanToStreamColMap[fholder.argRenderIdx])}
 		}
@@ -1292,12 +1338,15 @@ func (dsp *DistSQLPlanner) addAggregators(
 	// We either have a local stage on each stream followed by a final stage, or
 	// just a final stage. We only use a local stage if:
 	//  - the previous stage is distributed on multiple nodes, and
-	//  - all aggregation functions support it. TODO(radu): we could relax this by
-	//    splitting the aggregation into two different paths and joining on the
-	//    results.
-	//  - we have a mix of aggregations that use distinct and aggregations that
-	//    don't use distinct. TODO(arjun): This would require doing the same as
-	//    the todo as above.
+	//  - all aggregation functions without distinct support it. TODO(radu): we
+	//    could relax this by splitting the aggregation into two different paths
+	//    and joining on the results.
+	//  - all aggregations with distinct have a single argument column and no
+	//    filter, and the aggregation isn't scalar. The local stage groups by
+	//    these columns in addition to the grouping columns, and the final stage
+	//    aggregates their distinct values.
+	//  - not all aggregations use distinct, in which case we do local distinct
+	//    processing instead.
 	multiStage := false
 	allDistinct := true
 	anyDistinct := false
@@ -1316,23 +1365,27 @@ func (dsp *DistSQLPlanner) addAggregators(
 		multiStage = true
 		for _, e := range aggregations {
 			if e.Distinct {
-				// We can't do local aggregation for functions with distinct.
-				multiStage = false
 				anyDistinct = true
-			} else {
-				// We can't do local distinct if we have a mix of distinct and
-				// non-distinct aggregations.
-				allDistinct = false
+				// A scalar local stage can't have grouping columns.
+				if n.isScalar || len(e.ColIdx) != 1 || e.FilterColIdx != nil || len(e.Arguments) > 0 {
+					multiStage = false
+				}
+				continue
 			}
+			// We can't do local distinct if we have a mix of distinct and
+			// non-distinct aggregations.
+			allDistinct = false
 			if _, ok := physicalplan.DistAggregationTable[e.Func]; !ok {
 				multiStage = false
-				break
 			}
 		}
 	}
 	if !anyDistinct {
 		allDistinct = false
 	}
+	if allDistinct {
+		multiStage = false
+	}
 
 	var finalAggsSpec execinfrapb.AggregatorSpec
 	var finalAggsPost execinfrapb.PostProcessSpec
@@ -1352,6 +1405,10 @@ func (dsp *DistSQLPlanner) addAggregators(
 				distinctColsMap[c] = struct{}{}
 			}
 		}
+		// The rows of different groups must not be deduplicated.
+		for _, c := range groupCols {
+			distinctColsMap[c] = struct{}{}
+		}
 		orderedColumns := make([]uint32, len(orderedColsMap))
 		idx := 0
 		for o := range orderedColsMap {
@@ -1402,6 +1459,13 @@ func (dsp *DistSQLPlanner) addAggregators(
 		nFinalAgg := 0
 		needRender := false
 		for _, e := range aggregations {
+			if e.Distinct {
+				// The argument is passed through the local stage and the
+				// aggregation is only performed by the final stage.
+				nLocalAgg++
+				nFinalAgg++
+				continue
+			}
 			info := physicalplan.DistAggregationTable[e.Func]
 			nLocalAgg += len(info.LocalStage)
 			nFinalAgg += len(info.FinalStage)
@@ -1430,6 +1494,73 @@ func (dsp *DistSQLPlanner) addAggregators(
 			finalPreRenderTypes = make([]*types.T, 0, nFinalAgg)
 		}
 
+		// passThrough adds a local aggregation that outputs the given input
+		// column, unless there already is one, and returns its index in
+		// localAggs. It is used for the columns the local stage groups by.
+		passThrough := func(col uint32) uint32 {
+			agg := execinfrapb.AggregatorSpec_Aggregation{
+				Func:   execinfrapb.AggregatorSpec_ANY_NOT_NULL,
+				ColIdx: []uint32{col},
+			}
+			// See if there already is an aggregation like the one
+			// we want to add.
+			for j := range localAggs {
+				if localAggs[j].Equals(agg) {
+					return uint32(j)
+				}
+			}
+			// Not already there, add it.
+			localAggs = append(localAggs, agg)
+			intermediateTypes = append(intermediateTypes, inputTypes[col])
+			return uint32(len(localAggs) - 1)
+		}
+
+		// addFinalAgg adds the given final aggregation, unless there already
+		// is an equivalent one, and maps finalIdx to it. argTypes are the
+		// types of its arguments.
+		addFinalAgg := func(finalIdx int, finalAgg execinfrapb.AggregatorSpec_Aggregation, argTypes []types.T) error {
+			for i, prevFinalAgg := range finalAggs {
+				if finalAgg.Equals(prevFinalAgg) {
+					// Found existing, equivalent
+					// final agg.  Map the finalIdx
+					// for the current final agg to
+					// its index (i) in finalAggs.
+					finalIdxMap[finalIdx] = uint32(i)
+					return nil
+				}
+			}
+
+			// Append the final agg if there is no existing
+			// equivalent.
+			finalIdxMap[finalIdx] = uint32(len(finalAggs))
+			finalAggs = append(finalAggs, finalAgg)
+			if needRender {
+				_, outputType, err := execinfrapb.GetAggregateInfo(finalAgg.Func, argTypes...)
+				if err != nil {
+					return err
+				}
+				finalPreRenderTypes = append(finalPreRenderTypes, outputType)
+			}
+			return nil
+		}
+
+		// The local stage also groups by the arguments of the aggregations
+		// with distinct, so that it only outputs their distinct values for
+		// each group.
+		localGroupCols := groupCols
+		var localGroupColSet util.FastIntSet
+		for _, c := range groupCols {
+			localGroupColSet.Add(int(c))
+		}
+		for _, e := range aggregations {
+			if e.Distinct && !localGroupColSet.Contains(int(e.ColIdx[0])) {
+				localGroupColSet.Add(int(e.ColIdx[0]))
+				// Don't append to groupCols, which is still used by the final
+				// stage.
+				localGroupCols = append(localGroupCols[:len(localGroupCols):len(localGroupCols)], e.ColIdx[0])
+			}
+		}
+
 		// Each aggregation can have multiple aggregations in the
 		// local/final stages. We concatenate all these into
 		// localAggs/finalAggs.
@@ -1437,6 +1568,19 @@ func (dsp *DistSQLPlanner) addAggregators(
 		// to all final aggregations.
 		finalIdx := 0
 		for _, e := range aggregations {
+			if e.Distinct {
+				col := e.ColIdx[0]
+				finalAgg := execinfrapb.AggregatorSpec_Aggregation{
+					Func:     e.Func,
+					Distinct: true,
+					ColIdx:   []uint32{passThrough(col)},
+				}
+				if err := addFinalAgg(finalIdx, finalAgg, []types.T{inputTypes[col]}); err != nil {
+					return err
+				}
+				finalIdx++
+				continue
+			}
 			info := physicalplan.DistAggregationTable[e.Func]
 
 			// relToAbsLocalIdx maps each local stage for the given
@@ -1510,42 +1654,15 @@ func (dsp *DistSQLPlanner) addAggregators(
 					Func:   finalInfo.Fn,
 					ColIdx: argIdxs,
 				}
-
-				isNewAgg := true
-				for i, prevFinalAgg := range finalAggs {
-					if finalAgg.Equals(prevFinalAgg) {
-						// Found existing, equivalent
-						// final agg.  Map the finalIdx
-						// for the current final agg to
-						// its index (i) in finalAggs.
-						finalIdxMap[finalIdx] = uint32(i)
-						isNewAgg = false
-						break
-					}
+				argTypes := make([]types.T, len(finalInfo.LocalIdxs))
+				for i := range finalInfo.LocalIdxs {
+					// Map the corresponding local
+					// aggregation output types for
+					// the current aggregation e.
+					argTypes[i] = intermediateTypes[argIdxs[i]]
 				}
-
-				// Append the final agg if there is no existing
-				// equivalent.
-				if isNewAgg {
-					finalIdxMap[finalIdx] = uint32(len(finalAggs))
-					finalAggs = append(finalAggs, finalAgg)
-
-					if needRender {
-						argTypes := make([]types.T, len(finalInfo.LocalIdxs))
-						for i := range finalInfo.LocalIdxs {
-							// Map the corresponding local
-							// aggregation output types for
-							// the current aggregation e.
-							argTypes[i] = intermediateTypes[argIdxs[i]]
-						}
-						_, outputType, err := execinfrapb.GetAggregateInfo(
-							finalInfo.Fn, argTypes...,
-						)
-						if err != nil {
-							return err
-						}
-						finalPreRenderTypes = append(finalPreRenderTypes, outputType)
-					}
+				if err := addFinalAgg(finalIdx, finalAgg, argTypes); err != nil {
+					return err
 				}
 				finalIdx++
 			}
@@ -1558,28 +1675,10 @@ func (dsp *DistSQLPlanner) addAggregators(
 		finalGroupCols := make([]uint32, len(groupCols))
 		finalOrderedGroupCols := make([]uint32, 0, len(orderedGroupCols))
 		for i, groupColIdx := range groupCols {
-			agg := execinfrapb.AggregatorSpec_Aggregation{
-				Func:   execinfrapb.AggregatorSpec_ANY_NOT_NULL,
-				ColIdx: []uint32{groupColIdx},
-			}
-			// See if there already is an aggregation like the one
-			// we want to add.
-			idx := -1
-			for j := range localAggs {
-				if localAggs[j].Equals(agg) {
-					idx = j
-					break
-				}
-			}
-			if idx == -1 {
-				// Not already there, add it.
-				idx = len(localAggs)
-				localAggs = append(localAggs, agg)
-				intermediateTypes = append(intermediateTypes, inputTypes[groupColIdx])
-			}
-			finalGroupCols[i] = uint32(idx)
+			idx := passThrough(groupColIdx)
+			finalGroupCols[i] = idx
 			if orderedGroupColSet.Contains(n.groupCols[i]) {
-				finalOrderedGroupCols = append(finalOrderedGroupCols, uint32(idx))
+				finalOrderedGroupCols = append(finalOrderedGroupCols, idx)
 			}
 		}
 
@@ -1609,7 +1708,7 @@ func (dsp *DistSQLPlanner) addAggregators(
 		localAggsSpec := execinfrapb.AggregatorSpec{
 			Type:             aggType,
 			Aggregations:     localAggs,
-			GroupCols:        groupCols,
+			GroupCols:        localGroupCols,
 			OrderedGroupCols: orderedGroupCols,
 		}
 
@@ -1637,7 +1736,9 @@ func (dsp *DistSQLPlanner) addAggregators(
 			finalIdx := 0
 			for i, e := range aggregations {
 				info := physicalplan.DistAggregationTable[e.Func]
-				if info.FinalRendering == nil {
+				// Aggregations with distinct are entirely performed by a
+				// single final aggregation.
+				if e.Distinct || info.FinalRendering == nil {
 					// mappedIdx corresponds to the index
 					// location of the result for this
 					// final aggregation in finalAggs. This
@@ -1651,6 +1752,7 @@ func (dsp *DistSQLPlanner) addAggregators(
 					if err != nil {
 						return err
 					}
+					finalIdx++
 				} else {
 					// We have multiple final aggregation
 					// values that we need to be mapped to
@@ -1672,8 +1774,8 @@ func (dsp *DistSQLPlanner) addAggregators(
 					if err != nil {
 						return err
 					}
+					finalIdx += len(info.FinalStage)
 				}
-				finalIdx += len(info.FinalStage)
 			}
 			finalAggsPost.RenderExprs = renderExprs
 		} else if len(finalAggs) < len(aggregations) {
@@ -1834,7 +1936,7 @@ func (dsp *DistSQLPlanner) createPlanForIndexJoin(
 	if err != nil {
 		return PhysicalPlan{}, err
 	}
-	if distributeIndexJoin.Get(&dsp.st.SV) && len(plan.ResultRouters) > 1 {
+	if distributeIndexJoin.GetWithOverrides(&dsp.st.SV, planCtx.settingOverrides()) && len(plan.ResultRouters) > 1 {
 		// Instantiate one join reader for every stream.
 		plan.AddNoGroupingStage(
 			execinfrapb.ProcessorCoreUnion{JoinReader: &joinReaderSpec},
@@ -2140,7 +2242,8 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 	//    We are thus breaking up all input rows into K buckets such that rows
 	//    that match on the equality columns end up in the same bucket. If there
 	//    are no equality columns, we cannot distribute rows so we use a single
-	//    joiner.
+	//    joiner. The anti joins that reject on NULL need the entire right side
+	//    in every joiner, so their right routers mirror the rows instead.
 	//
 	//  - The routers of the joiner processors are the result routers of the plan.
 
@@ -2182,7 +2285,7 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 	if numEq := len(n.pred.leftEqualityIndices); numEq != 0 {
 		nodes = findJoinProcessorNodes(leftRouters, rightRouters, p.Processors)
 
-		if planMergeJoins.Get(&dsp.st.SV) && len(n.mergeJoinOrdering) > 0 {
+		if planMergeJoins.GetWithOverrides(&dsp.st.SV, planCtx.settingOverrides()) && len(n.mergeJoinOrdering) > 0 {
 			// TODO(radu): we currently only use merge joins when we have an ordering on
 			// all equality columns. We should relax this by either:
 			//  - implementing a hybrid hash/merge processor which implements merge
@@ -2193,11 +2296,17 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 				// Excellent! We can use the merge joiner.
 				leftMergeOrd = distsqlOrdering(n.mergeJoinOrdering, leftEqCols)
 				rightMergeOrd = distsqlOrdering(n.mergeJoinOrdering, rightEqCols)
+				if n.pred.rejectOnNull &&
+					rightMergeOrd.Columns[0].Direction != execinfrapb.Ordering_Column_ASC {
+					// The merge joiner relies on the right NULLs coming first to reject
+					// on NULL; use the hash joiner instead.
+					leftMergeOrd, rightMergeOrd = execinfrapb.Ordering{}, execinfrapb.Ordering{}
+				}
 			}
 		}
 	} else {
-		// Without column equality, we cannot distribute the join. Run a
-		// single processor.
+		// Without column equality, we cannot distribute the join. Run a single
+		// processor.
 		nodes = []roachpb.NodeID{dsp.nodeDesc.NodeID}
 
 		// If either side has a single stream, put the processor on that node. We
@@ -2226,6 +2335,7 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 			Type:                 joinType,
 			LeftEqColumnsAreKey:  n.pred.leftEqKey,
 			RightEqColumnsAreKey: n.pred.rightEqKey,
+			RejectOnNull:         n.pred.rejectOnNull,
 		}
 	} else {
 		core.MergeJoiner = &execinfrapb.MergeJoinerSpec{
@@ -2235,6 +2345,7 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 			Type:                 joinType,
 			LeftEqColumnsAreKey:  n.pred.leftEqKey,
 			RightEqColumnsAreKey: n.pred.rightEqKey,
+			RejectOnNull:         n.pred.rejectOnNull,
 		}
 	}
 
@@ -2242,6 +2353,18 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 		nodes, core, post, leftEqCols, rightEqCols, leftTypes, rightTypes,
 		leftMergeOrd, rightMergeOrd, leftRouters, rightRouters,
 	)
+	if n.pred.rejectOnNull && len(nodes) > 1 {
+		// Whether a left row is emitted depends on the entire right side (on
+		// whether it is empty or has a NULL), so every joiner gets all the right
+		// rows. The left rows are still distributed by hash; a right row never
+		// matches the left rows of the other joiners, so the duplicates don't
+		// change the result.
+		for _, resultProc := range rightRouters {
+			p.Processors[resultProc].Spec.Output[0] = execinfrapb.OutputRouterSpec{
+				Type: execinfrapb.OutputRouterSpec_MIRROR,
+			}
+		}
+	}
 
 	p.PlanToStreamColMap = joinToStreamColMap
 	p.ResultTypes, err = getTypesForPlanResult(n, joinToStreamColMap)
@@ -2944,6 +3067,20 @@ func (dsp *DistSQLPlanner) createPlanForSetOp(
 			}
 			p.AddSingleGroupStage(
 				dsp.nodeDesc.NodeID, distinctSpec, execinfrapb.PostProcessSpec{}, p.ResultTypes)
+		} else if n.hardLimit != 0 {
+			// With a hard limit, the streams of the left input are read before the
+			// streams of the right input by a serial synchronizer on the gateway,
+			// and the limit stops the flow as soon as enough rows have been read.
+			// Since the whole plan is on the gateway, the processors of both inputs
+			// are fused with the synchronizer, so the right input is not read at
+			// all if the left input produces hardLimit rows.
+			p.AddSingleGroupStage(
+				dsp.nodeDesc.NodeID,
+				execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+				execinfrapb.PostProcessSpec{Limit: n.hardLimit},
+				p.ResultTypes,
+			)
+			p.Processors[p.ResultRouters[0]].Spec.Input[0].Type = execinfrapb.InputSyncSpec_SERIAL_UNORDERED
 		} else {
 			// With UNION ALL, we can end up with multiple streams on the same node.
 			// We don't want to have unnecessary routers and cross-node streams, so
@@ -2993,11 +3130,12 @@ func (dsp *DistSQLPlanner) createPlanForSetOp(
 		//    group uses a hashmap on the remaining columns
 		//  - or: adding a sort processor to complete the order
 		var core execinfrapb.ProcessorCoreUnion
-		if !planMergeJoins.Get(&dsp.st.SV) || len(mergeOrdering.Columns) < len(streamCols) {
+		if !planMergeJoins.GetWithOverrides(&dsp.st.SV, planCtx.settingOverrides()) || len(mergeOrdering.Columns) < len(streamCols) {
 			core.HashJoiner = &execinfrapb.HashJoinerSpec{
 				LeftEqColumns:  eqCols,
 				RightEqColumns: eqCols,
 				Type:           joinType,
+				NullEquality:   true,
 			}
 		} else {
 			core.MergeJoiner = &execinfrapb.MergeJoinerSpec{
@@ -0,0 +1,57 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+)
+
+// createTTLDeleter generates a plan consisting of TTL deleter processors, one
+// for each node that has spans that we are deleting from. Since the spans are
+// partitioned by leaseholder, every span is processed by exactly one node. The
+// plan is finalized. It also returns the number of spans the plan processes,
+// which is the number of spans reported as completed once the plan has run.
+func (dsp *DistSQLPlanner) createTTLDeleter(
+	planCtx *PlanningCtx, spec execinfrapb.TTLDeleterSpec, spans []roachpb.Span,
+) (PhysicalPlan, int, error) {
+	spanPartitions, err := dsp.PartitionSpans(planCtx, spans)
+	if err != nil {
+		return PhysicalPlan{}, 0, err
+	}
+
+	var p PhysicalPlan
+	numSpans := 0
+	p.ResultRouters = make([]physicalplan.ProcessorIdx, len(spanPartitions))
+	for i, sp := range spanPartitions {
+		td := &execinfrapb.TTLDeleterSpec{}
+		*td = spec
+		td.Spans = make([]execinfrapb.TableReaderSpan, len(sp.Spans))
+		for j := range sp.Spans {
+			td.Spans[j].Span = sp.Spans[j]
+		}
+		numSpans += len(sp.Spans)
+
+		proc := physicalplan.Processor{
+			Node: sp.Node,
+			Spec: execinfrapb.ProcessorSpec{
+				Core:   execinfrapb.ProcessorCoreUnion{TTLDeleter: td},
+				Output: []execinfrapb.OutputRouterSpec{{Type: execinfrapb.OutputRouterSpec_PASS_THROUGH}},
+			},
+		}
+
+		pIdx := p.AddProcessor(proc)
+		p.ResultRouters[i] = pIdx
+	}
+	dsp.FinalizePlan(planCtx, &p)
+	return p, numSpans, nil
+}
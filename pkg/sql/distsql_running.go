@@ -13,13 +13,20 @@ package sql
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -33,8 +40,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -50,6 +59,24 @@ const numRunners = 16
 
 const clientRejectedMsg string = "client rejected when attempting to run DistSQL plan"
 
+// settingFlowSetupConnectTimeout bounds the time the gateway waits to connect
+// to a remote node when setting up a flow on it. Flows that can't be set up
+// because of a connection timeout can be replanned on other nodes.
+var settingFlowSetupConnectTimeout = settings.RegisterNonNegativeDurationSetting(
+	"sql.distsql.flow_setup.connect_timeout",
+	"amount of time the gateway waits to connect to a node to set up a flow on it (0 = no timeout)",
+	base.NetworkTimeout,
+)
+
+// settingMaxFlowReplans caps the number of times the remote flows of a query
+// are replanned after failing to be set up.
+var settingMaxFlowReplans = settings.RegisterNonNegativeIntSetting(
+	"sql.distsql.flow_setup.max_replans",
+	"maximum number of times the flows of a query that failed to be set up on remote nodes "+
+		"are replanned on other nodes or on the gateway before the query fails (0 = disabled)",
+	3,
+)
+
 // runnerRequest is the request that is sent (via a channel) to a worker.
 type runnerRequest struct {
 	ctx        context.Context
@@ -57,6 +84,8 @@ type runnerRequest struct {
 	flowReq    *execinfrapb.SetupFlowRequest
 	nodeID     roachpb.NodeID
 	resultChan chan<- runnerResult
+	// connectTimeout, if non-zero, bounds the time spent connecting to the node.
+	connectTimeout time.Duration
 }
 
 // runnerResult is returned by a worker (via a channel) for each received
@@ -64,22 +93,34 @@ type runnerRequest struct {
 type runnerResult struct {
 	nodeID roachpb.NodeID
 	err    error
+	// notSetUp is set when err is known to have prevented the flow from being
+	// set up on the node, in which case the flow can be replanned elsewhere.
+	notSetUp bool
 }
 
 func (req runnerRequest) run() {
 	res := runnerResult{nodeID: req.nodeID}
 
-	conn, err := req.nodeDialer.Dial(req.ctx, req.nodeID, rpc.DefaultClass)
+	dialCtx := req.ctx
+	if req.connectTimeout > 0 {
+		var cancel func()
+		dialCtx, cancel = context.WithTimeout(req.ctx, req.connectTimeout)
+		defer cancel()
+	}
+	conn, err := req.nodeDialer.Dial(dialCtx, req.nodeID, rpc.DefaultClass)
 	if err != nil {
 		res.err = err
+		// The request was never sent, unless the query itself was canceled.
+		res.notSetUp = req.ctx.Err() == nil
 	} else {
 		client := execinfrapb.NewDistSQLClient(conn)
-		// TODO(radu): do we want a timeout here?
 		resp, err := client.SetupFlow(req.ctx, req.flowReq)
 		if err != nil {
 			res.err = err
-		} else {
+			res.notSetUp = grpcutil.RequestDidNotStart(err)
+		} else if resp.Error != nil {
 			res.err = resp.Error.ErrorDetail(req.ctx)
+			res.notSetUp = true
 		}
 	}
 	req.resultChan <- res
@@ -110,7 +151,9 @@ func (dsp *DistSQLPlanner) initRunners() {
 // It will first attempt to set up all remote flows using the dsp workers if
 // available or sequentially if not, and then finally set up the gateway flow,
 // whose output is the DistSQLReceiver provided. This flow is then returned to
-// be run.
+// be run. estimatedRowCount is the maximum number of rows that the plan is
+// expected to read from a table, and it is used to decide whether the flows
+// should be vectorized when vectorize=auto.
 func (dsp *DistSQLPlanner) setupFlows(
 	ctx context.Context,
 	evalCtx *extendedEvalContext,
@@ -118,7 +161,7 @@ func (dsp *DistSQLPlanner) setupFlows(
 	flows map[roachpb.NodeID]*execinfrapb.FlowSpec,
 	recv *DistSQLReceiver,
 	localState distsql.LocalState,
-	vectorizeThresholdMet bool,
+	estimatedRowCount uint64,
 ) (context.Context, flowinfra.Flow, error) {
 	thisNodeID := dsp.nodeDesc.NodeID
 	_, ok := flows[thisNodeID]
@@ -136,6 +179,16 @@ func (dsp *DistSQLPlanner) setupFlows(
 		EvalContext:       evalCtxProto,
 		TraceKV:           evalCtx.Tracing.KVTracingEnabled(),
 	}
+	// Propagate the span of the statement to the remote flows, so that their
+	// spans are created as its children.
+	if sp := opentracing.SpanFromContext(ctx); sp != nil {
+		traceInfo := make(opentracing.TextMapCarrier)
+		if err := sp.Tracer().Inject(sp.Context(), opentracing.TextMap, traceInfo); err != nil {
+			log.VEventf(ctx, 1, "failed to inject the trace info of the flows: %v", err)
+		} else if len(traceInfo) > 0 {
+			setupReq.TraceInfo = traceInfo
+		}
+	}
 
 	// Start all the flows except the flow on this node (there is always a flow on
 	// this node).
@@ -145,6 +198,7 @@ func (dsp *DistSQLPlanner) setupFlows(
 	}
 
 	if evalCtx.SessionData.VectorizeMode != sessiondata.VectorizeOff {
+		vectorizeThresholdMet := estimatedRowCount >= evalCtx.SessionData.VectorizeRowCountThreshold
 		if !vectorizeThresholdMet && evalCtx.SessionData.VectorizeMode == sessiondata.VectorizeAuto {
 			// Vectorization is not justified for this flow because the expected
 			// amount of data is too small and the overhead of pre-allocating data
@@ -171,7 +225,7 @@ func (dsp *DistSQLPlanner) setupFlows(
 							ClusterID:   &dsp.rpcCtx.ClusterID,
 						},
 						NodeID: -1,
-					}, spec.Processors, fuseOpt,
+					}, spec.Processors, fuseOpt, estimatedRowCount,
 				); err != nil {
 					// Vectorization attempt failed with an error.
 					returnVectorizationSetupError := false
@@ -199,12 +253,20 @@ func (dsp *DistSQLPlanner) setupFlows(
 					}
 					// Vectorization is not supported for this flow, so we override the
 					// setting.
+					telemetry.Inc(sqltelemetry.VecFallbackCounter)
 					setupReq.EvalContext.Vectorize = int32(sessiondata.VectorizeOff)
 					break
 				}
 			}
 		}
 	}
+	reqs := make(map[roachpb.NodeID]*execinfrapb.SetupFlowRequest, len(flows)-1)
+	defer func() {
+		for _, req := range reqs {
+			physicalplan.ReleaseSetupFlowRequest(req)
+		}
+	}()
+	connectTimeout := settingFlowSetupConnectTimeout.Get(&dsp.st.SV)
 	for nodeID, flowSpec := range flows {
 		if nodeID == thisNodeID {
 			// Skip this node.
@@ -212,14 +274,15 @@ func (dsp *DistSQLPlanner) setupFlows(
 		}
 		req := setupReq
 		req.Flow = *flowSpec
+		reqs[nodeID] = &req
 		runReq := runnerRequest{
-			ctx:        ctx,
-			nodeDialer: dsp.nodeDialer,
-			flowReq:    &req,
-			nodeID:     nodeID,
-			resultChan: resultChan,
+			ctx:            ctx,
+			nodeDialer:     dsp.nodeDialer,
+			flowReq:        &req,
+			nodeID:         nodeID,
+			resultChan:     resultChan,
+			connectTimeout: connectTimeout,
 		}
-		defer physicalplan.ReleaseSetupFlowRequest(&req)
 
 		// Send out a request to the workers; if no worker is available, run
 		// directly.
@@ -231,23 +294,34 @@ func (dsp *DistSQLPlanner) setupFlows(
 	}
 
 	var firstErr error
+	var failed []runnerResult
 	// Now wait for all the flows to be scheduled on remote nodes. Note that we
 	// are not waiting for the flows themselves to complete.
 	for i := 0; i < len(flows)-1; i++ {
 		res := <-resultChan
-		if firstErr == nil {
+		if res.err == nil {
+			continue
+		}
+		if res.notSetUp {
+			failed = append(failed, res)
+		} else if firstErr == nil {
 			firstErr = res.err
 		}
-		// TODO(radu): accumulate the flows that we failed to set up and move them
-		// into the local flow.
 	}
 	if firstErr != nil {
 		return nil, nil, firstErr
 	}
+	if len(failed) > 0 {
+		if err := dsp.replanFailedFlows(ctx, flows, reqs, failed, connectTimeout); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	// Set up the flow on this node.
 	localReq := setupReq
 	localReq.Flow = *flows[thisNodeID]
+	// The local flow's span is created directly from ctx.
+	localReq.TraceInfo = nil
 	defer physicalplan.ReleaseSetupFlowRequest(&localReq)
 	ctx, flow, err := dsp.distSQLSrv.SetupLocalSyncFlow(ctx, evalCtx.Mon, &localReq, recv, localState)
 	if err != nil {
@@ -257,6 +331,205 @@ func (dsp *DistSQLPlanner) setupFlows(
 	return ctx, flow, nil
 }
 
+// replanFailedFlows moves the flows that failed to be set up on their nodes to
+// other healthy nodes, or into the gateway flow when no such node is available.
+// The gateway flow, which hasn't been set up yet, is rewired to the new
+// location of the flows. A flow can only be moved if none of the flows that
+// are already set up send data to it; otherwise, or once the cap on replans is
+// reached, the error that prevented the flow from being set up is returned.
+//
+// reqs are the setup requests of the remote flows; it is updated with the new
+// locations of the flows, and the flows that are moved into the gateway flow
+// are removed from it.
+func (dsp *DistSQLPlanner) replanFailedFlows(
+	ctx context.Context,
+	flows map[roachpb.NodeID]*execinfrapb.FlowSpec,
+	reqs map[roachpb.NodeID]*execinfrapb.SetupFlowRequest,
+	failed []runnerResult,
+	connectTimeout time.Duration,
+) error {
+	thisNodeID := dsp.nodeDesc.NodeID
+	replans := settingMaxFlowReplans.Get(&dsp.st.SV)
+	// pending contains the nodes of the flows that are still to be replanned,
+	// and unusable the nodes on which flows failed to be set up.
+	pending := make(map[roachpb.NodeID]bool, len(failed))
+	unusable := make(map[roachpb.NodeID]bool, len(failed))
+	for _, res := range failed {
+		pending[res.nodeID] = true
+		unusable[res.nodeID] = true
+	}
+	var candidates []roachpb.NodeID
+	for len(failed) > 0 {
+		res := failed[0]
+		failed = failed[1:]
+		delete(pending, res.nodeID)
+		if replans == 0 || !flowCanBeMoved(flows, res.nodeID, thisNodeID) {
+			return res.err
+		}
+		replans--
+		if candidates == nil {
+			candidates = dsp.flowReplanCandidates(ctx)
+		}
+
+		// The flow can only be moved to another remote node if it doesn't send
+		// data to flows that still have to be moved themselves.
+		target := thisNodeID
+		if !flowSendsTo(flows[res.nodeID], pending) {
+			for _, nodeID := range candidates {
+				if _, ok := flows[nodeID]; !ok && !unusable[nodeID] {
+					target = nodeID
+					break
+				}
+			}
+		}
+		log.VEventf(ctx, 1, "replanning the flow of n%d on n%d: %v", res.nodeID, target, res.err)
+		dsp.distSQLSrv.ServerConfig.Metrics.FlowsReplanned.Inc(1)
+		if target == thisNodeID {
+			mergeIntoGatewayFlow(flows, res.nodeID, thisNodeID)
+			// The processors of the flow are now released with the gateway flow.
+			delete(reqs, res.nodeID)
+			continue
+		}
+
+		moveFlow(flows, res.nodeID, target, thisNodeID)
+		req := reqs[res.nodeID]
+		delete(reqs, res.nodeID)
+		reqs[target] = req
+		resultChan := make(chan runnerResult, 1)
+		runnerRequest{
+			ctx:            ctx,
+			nodeDialer:     dsp.nodeDialer,
+			flowReq:        req,
+			nodeID:         target,
+			resultChan:     resultChan,
+			connectTimeout: connectTimeout,
+		}.run()
+		if retry := <-resultChan; retry.err != nil {
+			if !retry.notSetUp {
+				return retry.err
+			}
+			pending[target] = true
+			unusable[target] = true
+			failed = append(failed, retry)
+		}
+	}
+	return nil
+}
+
+// flowReplanCandidates returns the nodes, in increasing order of their IDs,
+// that are healthy and able to run flows planned by this node.
+func (dsp *DistSQLPlanner) flowReplanCandidates(ctx context.Context) []roachpb.NodeID {
+	candidates := []roachpb.NodeID{}
+	if err := dsp.gossip.IterateInfos(gossip.KeyNodeIDPrefix, func(key string, i gossip.Info) error {
+		var d roachpb.NodeDescriptor
+		if err := i.Value.GetProto(&d); err != nil {
+			return err
+		}
+		if d.NodeID == 0 || d.NodeID == dsp.nodeDesc.NodeID {
+			return nil
+		}
+		if dsp.nodeHealth.check(ctx, d.NodeID) == nil &&
+			dsp.nodeVersionIsCompatible(d.NodeID, dsp.planVersion) {
+			candidates = append(candidates, d.NodeID)
+		}
+		return nil
+	}); err != nil {
+		log.VEventf(ctx, 1, "failed to look up the nodes to replan flows on: %v", err)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	return candidates
+}
+
+// forEachRemoteStream calls fn on each of the remote streams of the processors
+// of the flow. outbound is set for the streams on which the flow sends data.
+func forEachRemoteStream(
+	spec *execinfrapb.FlowSpec, fn func(s *execinfrapb.StreamEndpointSpec, outbound bool),
+) {
+	for i := range spec.Processors {
+		p := &spec.Processors[i]
+		for j := range p.Input {
+			for k := range p.Input[j].Streams {
+				if s := &p.Input[j].Streams[k]; s.Type == execinfrapb.StreamEndpointSpec_REMOTE {
+					fn(s, false /* outbound */)
+				}
+			}
+		}
+		for j := range p.Output {
+			for k := range p.Output[j].Streams {
+				if s := &p.Output[j].Streams[k]; s.Type == execinfrapb.StreamEndpointSpec_REMOTE {
+					fn(s, true /* outbound */)
+				}
+			}
+		}
+	}
+}
+
+// flowSendsTo returns whether the flow sends data to any of the given nodes.
+func flowSendsTo(spec *execinfrapb.FlowSpec, nodes map[roachpb.NodeID]bool) bool {
+	sends := false
+	forEachRemoteStream(spec, func(s *execinfrapb.StreamEndpointSpec, outbound bool) {
+		if outbound && nodes[s.TargetNodeID] {
+			sends = true
+		}
+	})
+	return sends
+}
+
+// flowCanBeMoved returns whether the flow of the given node can be moved
+// without rewiring any flow other than the gateway flow, i.e. whether the
+// gateway flow is the only one that sends data to it.
+func flowCanBeMoved(
+	flows map[roachpb.NodeID]*execinfrapb.FlowSpec, nodeID, gatewayID roachpb.NodeID,
+) bool {
+	target := map[roachpb.NodeID]bool{nodeID: true}
+	for id, spec := range flows {
+		if id != gatewayID && id != nodeID && flowSendsTo(spec, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// moveFlow moves the flow of the given node to another node that doesn't have
+// a flow yet, redirecting the streams on which the gateway flow sends data to
+// it.
+func moveFlow(flows map[roachpb.NodeID]*execinfrapb.FlowSpec, from, to, gatewayID roachpb.NodeID) {
+	forEachRemoteStream(flows[gatewayID], func(s *execinfrapb.StreamEndpointSpec, outbound bool) {
+		if outbound && s.TargetNodeID == from {
+			s.TargetNodeID = to
+		}
+	})
+	flows[to] = flows[from]
+	delete(flows, from)
+}
+
+// mergeIntoGatewayFlow moves the processors of the flow of the given node into
+// the gateway flow. The streams between the two flows become local streams.
+func mergeIntoGatewayFlow(
+	flows map[roachpb.NodeID]*execinfrapb.FlowSpec, nodeID, gatewayID roachpb.NodeID,
+) {
+	spec, gatewaySpec := flows[nodeID], flows[gatewayID]
+	local := make(map[execinfrapb.StreamID]bool)
+	forEachRemoteStream(gatewaySpec, func(s *execinfrapb.StreamEndpointSpec, outbound bool) {
+		if outbound && s.TargetNodeID == nodeID {
+			local[s.StreamID] = true
+		}
+	})
+	forEachRemoteStream(spec, func(s *execinfrapb.StreamEndpointSpec, outbound bool) {
+		if outbound && s.TargetNodeID == gatewayID {
+			local[s.StreamID] = true
+		}
+	})
+	gatewaySpec.Processors = append(gatewaySpec.Processors, spec.Processors...)
+	forEachRemoteStream(gatewaySpec, func(s *execinfrapb.StreamEndpointSpec, _ bool) {
+		if local[s.StreamID] {
+			s.Type = execinfrapb.StreamEndpointSpec_LOCAL
+			s.TargetNodeID = 0
+		}
+	})
+	delete(flows, nodeID)
+}
+
 // Run executes a physical plan. The plan should have been finalized using
 // FinalizePlan.
 //
@@ -321,12 +594,12 @@ func (dsp *DistSQLPlanner) Run(
 		return func() {}
 	}
 
+	var stmtStr string
+	if planCtx.planner != nil && planCtx.planner.stmt != nil {
+		stmtStr = planCtx.planner.stmt.String()
+	}
 	if logPlanDiagram {
 		log.VEvent(ctx, 1, "creating plan diagram")
-		var stmtStr string
-		if planCtx.planner != nil && planCtx.planner.stmt != nil {
-			stmtStr = planCtx.planner.stmt.String()
-		}
 		_, url, err := execinfrapb.GeneratePlanDiagramURL(stmtStr, flows, false /* showInputTypes */)
 		if err != nil {
 			log.Infof(ctx, "Error generating diagram: %s", err)
@@ -335,6 +608,19 @@ func (dsp *DistSQLPlanner) Run(
 		}
 	}
 
+	// The diagram of a distributed plan is retained so that the progress of the
+	// plan can be displayed while it runs. It needs to be generated before the
+	// flows are set up, since that releases their specs.
+	var progressDiagram execinfrapb.FlowDiagram
+	if recv.progress != nil && len(flows) > 1 &&
+		execinfra.SettingProgressReportInterval.Get(&dsp.st.SV) > 0 {
+		var err error
+		progressDiagram, err = execinfrapb.GeneratePlanDiagram(stmtStr, flows, false /* showInputTypes */)
+		if err != nil {
+			log.VEventf(ctx, 1, "error generating diagram: %s", err)
+		}
+	}
+
 	log.VEvent(ctx, 1, "running DistSQL plan")
 
 	dsp.distSQLSrv.ServerConfig.Metrics.QueryStart()
@@ -343,20 +629,24 @@ func (dsp *DistSQLPlanner) Run(
 	recv.outputTypes = plan.ResultTypes
 	recv.resultToStreamColMap = plan.PlanToStreamColMap
 
-	vectorizedThresholdMet := plan.MaxEstimatedRowCount >= evalCtx.SessionData.VectorizeRowCountThreshold
-
 	if len(flows) == 1 {
 		// We ended up planning everything locally, regardless of whether we
 		// intended to distribute or not.
 		localState.IsLocal = true
 	}
 
-	ctx, flow, err := dsp.setupFlows(ctx, evalCtx, leafInputState, flows, recv, localState, vectorizedThresholdMet)
+	ctx, flow, err := dsp.setupFlows(
+		ctx, evalCtx, leafInputState, flows, recv, localState, plan.MaxEstimatedRowCount,
+	)
 	if err != nil {
 		recv.SetError(err)
 		return func() {}
 	}
 
+	if progressDiagram != nil {
+		recv.progress.start(progressDiagram, flow.GetFlowCtx().Progress)
+	}
+
 	if finishedSetupFn != nil {
 		finishedSetupFn()
 	}
@@ -440,6 +730,7 @@ type DistSQLReceiver struct {
 	commErr error
 
 	row    tree.Datums
+	batch  coldata.Batch
 	status execinfra.ConsumerStatus
 	alloc  sqlbase.DatumAlloc
 	closed bool
@@ -465,6 +756,14 @@ type DistSQLReceiver struct {
 	// statement.
 	bytesRead int64
 	rowsRead  int64
+
+	// contentionEvents accumulates the contention events reported by the
+	// processors of the flow.
+	contentionEvents []roachpb.ContentionEvent
+
+	// progress, if set, tracks the progress of the plan that the receiver
+	// consumes the results of.
+	progress *queryProgress
 }
 
 // rowResultWriter is a subset of CommandResult to be used with the
@@ -482,6 +781,16 @@ type metadataResultWriter interface {
 	AddMeta(ctx context.Context, meta *execinfrapb.ProducerMetadata)
 }
 
+// batchResultWriter is implemented by the rowResultWriters that receive the
+// results of vectorized flows in columnar batches rather than in rows. The rows
+// are still written with AddRow when the flow isn't vectorized.
+type batchResultWriter interface {
+	// AddBatch writes a batch of results. The batch contains the result columns
+	// in order, has no selection vector, and is only valid until AddBatch
+	// returns.
+	AddBatch(ctx context.Context, batch coldata.Batch) error
+}
+
 type metadataCallbackWriter struct {
 	rowResultWriter
 	fn func(ctx context.Context, meta *execinfrapb.ProducerMetadata) error
@@ -516,6 +825,7 @@ func (w *errOnlyResultWriter) IncrementRowsAffected(n int) {
 }
 
 var _ execinfra.RowReceiver = &DistSQLReceiver{}
+var _ execinfra.BatchReceiver = &DistSQLReceiver{}
 
 var receiverSyncPool = sync.Pool{
 	New: func() interface{} {
@@ -650,6 +960,12 @@ func (r *DistSQLReceiver) Push(
 			meta.Metrics.Release()
 			meta.Release()
 		}
+		if len(meta.ContentionEvents) > 0 {
+			r.contentionEvents = append(r.contentionEvents, meta.ContentionEvents...)
+		}
+		if meta.RowsProduced != nil && r.progress != nil {
+			r.progress.recordRowsProduced(meta.RowsProduced)
+		}
 		if metaWriter, ok := r.resultWriter.(metadataResultWriter); ok {
 			metaWriter.AddMeta(r.ctx, meta)
 		}
@@ -741,6 +1057,53 @@ var (
 	ErrLimitedResultClosed = errors.New("row count limit closed")
 )
 
+// AcceptsBatches is part of the execinfra.BatchReceiver interface.
+func (r *DistSQLReceiver) AcceptsBatches() bool {
+	_, ok := r.resultWriter.(batchResultWriter)
+	return ok && r.stmtType == tree.Rows && !r.discardRows && !r.noColsRequired
+}
+
+// PushBatch is part of the execinfra.BatchReceiver interface.
+func (r *DistSQLReceiver) PushBatch(batch coldata.Batch) execinfra.ConsumerStatus {
+	if r.resultWriter.Err() == nil && r.ctx.Err() != nil {
+		r.resultWriter.SetError(r.ctx.Err())
+	}
+	if r.resultWriter.Err() != nil {
+		return execinfra.ConsumerClosed
+	}
+	if r.status != execinfra.NeedMoreRows {
+		return r.status
+	}
+
+	// The batch has the columns of the stream, so it is projected onto the
+	// result columns unless they are the same. The projection shares the
+	// vectors of the batch.
+	identity := len(r.resultToStreamColMap) == batch.Width()
+	for i, resIdx := range r.resultToStreamColMap {
+		identity = identity && i == resIdx
+	}
+	if !identity {
+		if r.batch == nil {
+			r.batch = coldata.NewMemBatchWithSize(nil /* types */, 0 /* size */)
+			for range r.resultToStreamColMap {
+				r.batch.AppendCol(nil)
+			}
+		}
+		vecs := r.batch.ColVecs()
+		for i, resIdx := range r.resultToStreamColMap {
+			vecs[i] = batch.ColVec(resIdx)
+		}
+		r.batch.SetLength(batch.Length())
+		batch = r.batch
+	}
+	if err := r.resultWriter.(batchResultWriter).AddBatch(r.ctx, batch); err != nil {
+		r.resultWriter.SetError(err)
+		r.commErr = err
+		r.status = execinfra.ConsumerClosed
+	}
+	return r.status
+}
+
 // ProducerDone is part of the RowReceiver interface.
 func (r *DistSQLReceiver) ProducerDone() {
 	if r.closed {
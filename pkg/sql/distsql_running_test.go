@@ -260,3 +260,103 @@ func TestDistSQLReceiverErrorRanking(t *testing.T) {
 		}
 	}
 }
+
+// TestReplanFailedFlow checks the rewiring of the flows when a flow that failed
+// to be set up is moved to another node or into the gateway flow.
+func TestReplanFailedFlow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const gateway, n2, n3, n4 = roachpb.NodeID(1), roachpb.NodeID(2), roachpb.NodeID(3), roachpb.NodeID(4)
+	remote := func(id execinfrapb.StreamID, target roachpb.NodeID) execinfrapb.StreamEndpointSpec {
+		return execinfrapb.StreamEndpointSpec{
+			Type: execinfrapb.StreamEndpointSpec_REMOTE, StreamID: id, TargetNodeID: target,
+		}
+	}
+	proc := func(in, out []execinfrapb.StreamEndpointSpec) execinfrapb.ProcessorSpec {
+		var p execinfrapb.ProcessorSpec
+		if in != nil {
+			p.Input = []execinfrapb.InputSyncSpec{{Streams: in}}
+		}
+		p.Output = []execinfrapb.OutputRouterSpec{{Streams: out}}
+		return p
+	}
+	// The gateway sends stream 1 to n2 and receives streams 2 and 3 from n2 and
+	// n3. n3 also sends stream 4 to n2 in the second plan.
+	makeFlows := func(n3SendsToN2 bool) map[roachpb.NodeID]*execinfrapb.FlowSpec {
+		n3Out := []execinfrapb.StreamEndpointSpec{remote(3, gateway)}
+		n2In := []execinfrapb.StreamEndpointSpec{{Type: execinfrapb.StreamEndpointSpec_REMOTE, StreamID: 1}}
+		if n3SendsToN2 {
+			n3Out = append(n3Out, remote(4, n2))
+			n2In = append(n2In, execinfrapb.StreamEndpointSpec{Type: execinfrapb.StreamEndpointSpec_REMOTE, StreamID: 4})
+		}
+		return map[roachpb.NodeID]*execinfrapb.FlowSpec{
+			gateway: {Processors: []execinfrapb.ProcessorSpec{
+				proc(nil, []execinfrapb.StreamEndpointSpec{remote(1, n2)}),
+				proc([]execinfrapb.StreamEndpointSpec{
+					{Type: execinfrapb.StreamEndpointSpec_REMOTE, StreamID: 2},
+					{Type: execinfrapb.StreamEndpointSpec_REMOTE, StreamID: 3},
+				}, []execinfrapb.StreamEndpointSpec{{Type: execinfrapb.StreamEndpointSpec_SYNC_RESPONSE}}),
+			}},
+			n2: {Processors: []execinfrapb.ProcessorSpec{
+				proc(n2In, []execinfrapb.StreamEndpointSpec{remote(2, gateway)}),
+			}},
+			n3: {Processors: []execinfrapb.ProcessorSpec{proc(nil, n3Out)}},
+		}
+	}
+	remoteStreams := func(spec *execinfrapb.FlowSpec) string {
+		var res []string
+		forEachRemoteStream(spec, func(s *execinfrapb.StreamEndpointSpec, outbound bool) {
+			if outbound {
+				res = append(res, fmt.Sprintf("%d->n%d", s.StreamID, s.TargetNodeID))
+			} else {
+				res = append(res, fmt.Sprintf("%d<-", s.StreamID))
+			}
+		})
+		return fmt.Sprint(res)
+	}
+
+	t.Run("move", func(t *testing.T) {
+		flows := makeFlows(false /* n3SendsToN2 */)
+		if !flowCanBeMoved(flows, n2, gateway) {
+			t.Fatal("expected the flow of n2 to be movable")
+		}
+		if flowSendsTo(flows[n2], map[roachpb.NodeID]bool{n3: true}) {
+			t.Fatal("expected n2 not to send data to n3")
+		}
+		moveFlow(flows, n2, n4, gateway)
+		if _, ok := flows[n2]; ok || flows[n4] == nil {
+			t.Fatal("expected the flow of n2 to be moved to n4")
+		}
+		if s, e := remoteStreams(flows[gateway]), "[1->n4 2<- 3<-]"; s != e {
+			t.Fatalf("expected gateway streams %s, got %s", e, s)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		flows := makeFlows(false /* n3SendsToN2 */)
+		mergeIntoGatewayFlow(flows, n2, gateway)
+		if _, ok := flows[n2]; ok {
+			t.Fatal("expected the flow of n2 to be merged into the gateway flow")
+		}
+		if n := len(flows[gateway].Processors); n != 3 {
+			t.Fatalf("expected 3 processors in the gateway flow, got %d", n)
+		}
+		// Only the streams from n3 remain remote.
+		if s, e := remoteStreams(flows[gateway]), "[3<-]"; s != e {
+			t.Fatalf("expected gateway streams %s, got %s", e, s)
+		}
+	})
+
+	t.Run("unmovable", func(t *testing.T) {
+		flows := makeFlows(true /* n3SendsToN2 */)
+		if flowCanBeMoved(flows, n2, gateway) {
+			t.Fatal("expected the flow of n2 not to be movable since n3 sends data to it")
+		}
+		if !flowCanBeMoved(flows, n3, gateway) {
+			t.Fatal("expected the flow of n3 to be movable")
+		}
+		if !flowSendsTo(flows[n3], map[roachpb.NodeID]bool{n2: true}) {
+			t.Fatal("expected n3 to send data to n2")
+		}
+	})
+}
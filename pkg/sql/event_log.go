@@ -99,6 +99,10 @@ const (
 	// EventLogCreateStatistics is recorded when statistics are collected for a
 	// table.
 	EventLogCreateStatistics EventLogType = "create_statistics"
+
+	// EventLogPlanRegression is recorded when the plan of a statement
+	// fingerprint changes and its latency degrades.
+	EventLogPlanRegression EventLogType = "plan_regression"
 )
 
 // EventLogSetClusterSettingDetail is the json details for a settings change.
@@ -108,6 +112,16 @@ type EventLogSetClusterSettingDetail struct {
 	User        string
 }
 
+// EventLogPlanRegressionDetail is the json details for a plan regression.
+type EventLogPlanRegressionDetail struct {
+	ApplicationName string
+	Fingerprint     string
+	OldPlan         string
+	NewPlan         string
+	OldLatency      float64
+	NewLatency      float64
+}
+
 // An EventLogger exposes methods used to record events to the event table.
 type EventLogger struct {
 	*InternalExecutor
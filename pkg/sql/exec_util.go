@@ -205,6 +205,24 @@ var VectorizeRowCountThresholdClusterValue = settings.RegisterValidatedIntSettin
 	},
 )
 
+// ImplicitTxnResultsBufferSizeClusterValue controls the cluster default for
+// the minimum amount of results that are buffered for implicit transactions
+// before they are sent to the client. As long as no results have been sent,
+// retryable errors encountered by such transactions are retried automatically
+// instead of being returned to the client.
+var ImplicitTxnResultsBufferSizeClusterValue = settings.RegisterValidatedByteSizeSetting(
+	"sql.defaults.implicit_txn_results_buffer.size",
+	"default minimum size of the buffer that accumulates results of implicit transactions",
+	64<<10, /* 64 KiB */
+	func(v int64) error {
+		if v < 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot set sql.defaults.implicit_txn_results_buffer.size to a negative value: %d", v)
+		}
+		return nil
+	},
+)
+
 // DistSQLClusterExecMode controls the cluster default for when DistSQL is used.
 var DistSQLClusterExecMode = settings.RegisterEnumSetting(
 	"sql.defaults.distsql",
@@ -298,12 +316,42 @@ var (
 		Measurement: "SQL Statements",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaTxnDeadlineExtended = metric.Metadata{
+		Name:        "sql.txn.deadline.extended.count",
+		Help:        "Number of SQL transactions whose deadline was extended by renewing table leases",
+		Measurement: "SQL Transactions",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaTxnDeadlineExceeded = metric.Metadata{
+		Name:        "sql.txn.deadline.exceeded.count",
+		Help:        "Number of SQL transaction commits that failed because the transaction exceeded its deadline",
+		Measurement: "SQL Transactions",
+		Unit:        metric.Unit_COUNT,
+	}
 	MetaFailure = metric.Metadata{
 		Name:        "sql.failure.count",
 		Help:        "Number of statements resulting in a planning or runtime error",
 		Measurement: "SQL Statements",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaPreparedStmts = metric.Metadata{
+		Name:        "sql.prepared_statements.count",
+		Help:        "Number of prepared statements currently held by sessions",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaPreparedStmtsBytes = metric.Metadata{
+		Name:        "sql.prepared_statements.bytes",
+		Help:        "Memory used by the prepared statements and portals currently held by sessions",
+		Measurement: "Memory",
+		Unit:        metric.Unit_BYTES,
+	}
+	MetaPreparedStmtsEvicted = metric.Metadata{
+		Name:        "sql.prepared_statements.evicted.count",
+		Help:        "Number of unnamed prepared statements and portals evicted because a session exceeded its prepared statement memory limit",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
 	MetaSQLTxnLatency = metric.Metadata{
 		Name:        "sql.txn.latency",
 		Help:        "Latency of SQL transactions",
@@ -552,6 +600,11 @@ type ExecutorConfig struct {
 
 	// Role membership cache.
 	RoleMemberCache *MembershipCache
+
+	// MaxCommandSize is the setting limiting the size of the Raft commands,
+	// which mutations use to split their writes into KV batches. It is defined
+	// in the storage package, which can't be imported here. It can be nil.
+	MaxCommandSize *settings.ByteSizeSetting
 }
 
 // Organization returns the value of cluster.organization.
@@ -857,7 +910,25 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 // should be set. The statements that will be checked are Select,
 // ShowTrace (of a Select statement), Scrub, Export, and CreateStats.
 func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
-	var asOf tree.AsOfClause
+	asOf, ok := getAsOfClause(stmt)
+	if !ok {
+		return nil, nil
+	}
+	ts, err := p.EvalAsOfTimestamp(asOf)
+	return &ts, err
+}
+
+// isBoundedStalenessAsOf returns whether the statement performs a bounded
+// staleness read, that is, whether it has an AS OF SYSTEM TIME clause which
+// invokes with_min_timestamp or with_max_staleness.
+func (p *planner) isBoundedStalenessAsOf(stmt tree.Statement) bool {
+	asOf, ok := getAsOfClause(stmt)
+	return ok && tree.IsBoundedStalenessAsOf(asOf, &p.semaCtx)
+}
+
+// getAsOfClause returns the AS OF SYSTEM TIME clause of the statements checked
+// by isAsOf, if they have one.
+func getAsOfClause(stmt tree.Statement) (tree.AsOfClause, bool) {
 	switch s := stmt.(type) {
 	case *tree.Select:
 		selStmt := s.Select
@@ -869,32 +940,30 @@ func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
 
 		sc, ok := selStmt.(*tree.SelectClause)
 		if !ok {
-			return nil, nil
+			return tree.AsOfClause{}, false
 		}
 		if sc.From.AsOf.Expr == nil {
-			return nil, nil
+			return tree.AsOfClause{}, false
 		}
 
-		asOf = sc.From.AsOf
+		return sc.From.AsOf, true
 	case *tree.Scrub:
 		if s.AsOf.Expr == nil {
-			return nil, nil
+			return tree.AsOfClause{}, false
 		}
-		asOf = s.AsOf
+		return s.AsOf, true
 	case *tree.Export:
-		return p.isAsOf(s.Query)
+		return getAsOfClause(s.Query)
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
-			return nil, nil
+			return tree.AsOfClause{}, false
 		}
-		asOf = s.Options.AsOf
+		return s.Options.AsOf, true
 	case *tree.Explain:
-		return p.isAsOf(s.Statement)
+		return getAsOfClause(s.Statement)
 	default:
-		return nil, nil
+		return tree.AsOfClause{}, false
 	}
-	ts, err := p.EvalAsOfTimestamp(asOf)
-	return &ts, err
 }
 
 // isSavepoint returns true if stmt is a SAVEPOINT statement.
@@ -945,6 +1014,9 @@ type queryMeta struct {
 	// If set, this query will not be reported as part of SHOW QUERIES. This is
 	// set based on the statement implementing tree.HiddenFromShowQueries.
 	hidden bool
+
+	// progress tracks the progress of the distributed plan of the query.
+	progress queryProgress
 }
 
 // cancel cancels the query associated with this queryMeta, by closing the associated
@@ -1001,6 +1073,9 @@ func (r *SessionRegistry) deregister(id ClusterWideID) {
 type registrySession interface {
 	user() string
 	cancelQuery(queryID ClusterWideID) bool
+	// queryProgress returns the progress tracker of the given query, or nil if
+	// the session isn't running the query.
+	queryProgress(queryID ClusterWideID) *queryProgress
 	cancelSession()
 	// serialize serializes a Session into a serverpb.Session
 	// that can be served over RPC.
@@ -1031,6 +1106,37 @@ func (r *SessionRegistry) CancelQuery(queryIDStr string, username string) (bool,
 	return false, fmt.Errorf("query ID %s not found", queryID)
 }
 
+// QueryProgress looks up the associated query in the session registry and
+// returns the JSON data and the URL of the diagram of its distributed plan,
+// annotated with the number of rows produced so far by each processor. ok is
+// false if the query isn't running a distributed plan.
+func (r *SessionRegistry) QueryProgress(
+	queryIDStr string, username string,
+) (diagram string, u url.URL, ok bool, _ error) {
+	queryID, err := StringToClusterWideID(queryIDStr)
+	if err != nil {
+		return "", url.URL{}, false, fmt.Errorf("query ID %s malformed: %s", queryID, err)
+	}
+
+	r.Lock()
+	var progress *queryProgress
+	for _, session := range r.sessions {
+		if !(username == security.RootUser || username == session.user()) {
+			// Skip this session.
+			continue
+		}
+		if progress = session.queryProgress(queryID); progress != nil {
+			break
+		}
+	}
+	r.Unlock()
+
+	if progress == nil {
+		return "", url.URL{}, false, fmt.Errorf("query ID %s not found", queryID)
+	}
+	return progress.snapshot()
+}
+
 // CancelSession looks up the specified session in the session registry and cancels it.
 func (r *SessionRegistry) CancelSession(sessionIDBytes []byte, username string) (bool, error) {
 	sessionID := BytesToClusterWideID(sessionIDBytes)
@@ -1214,6 +1320,11 @@ type SessionTracing struct {
 	// results
 	showResults bool
 
+	// persist, when set, indicates that the trace must be written to
+	// system.session_traces when tracing is stopped. persist can be set
+	// manually by SET TRACING = ..., persist
+	persist bool
+
 	// If recording==true, recordingType indicates the type of the current
 	// recording.
 	recordingType tracing.RecordingType
@@ -1270,14 +1381,17 @@ func (st *SessionTracing) getRecording() []tracing.RecordedSpan {
 //   verbose messages around the interaction of SQL with KV. Some of the messages
 //   are per-row.
 // showResults: If set, result rows are reported in the trace.
+// persist: If set, the trace is written to system.session_traces when tracing
+//   is stopped.
 func (st *SessionTracing) StartTracing(
-	recType tracing.RecordingType, kvTracingEnabled, showResults bool,
+	recType tracing.RecordingType, kvTracingEnabled, showResults, persist bool,
 ) error {
 	if st.enabled {
 		// We're already tracing. Only treat as no-op if the same options
 		// are requested.
 		if kvTracingEnabled != st.kvTracingEnabled ||
 			showResults != st.showResults ||
+			persist != st.persist ||
 			recType != st.recordingType {
 			var desiredOptions bytes.Buffer
 			comma := ""
@@ -1289,6 +1403,10 @@ func (st *SessionTracing) StartTracing(
 				fmt.Fprintf(&desiredOptions, "%sresults", comma)
 				comma = ", "
 			}
+			if persist {
+				fmt.Fprintf(&desiredOptions, "%spersist", comma)
+				comma = ", "
+			}
 			recOption := "cluster"
 			if recType == tracing.SingleNodeRecording {
 				recOption = "local"
@@ -1317,6 +1435,7 @@ func (st *SessionTracing) StartTracing(
 	st.enabled = true
 	st.kvTracingEnabled = kvTracingEnabled
 	st.showResults = showResults
+	st.persist = persist
 	st.recordingType = recType
 
 	// Now hijack the conn's ctx with one that has a recording span.
@@ -1352,16 +1471,19 @@ func (st *SessionTracing) StartTracing(
 	return nil
 }
 
-// StopTracing stops the trace that was started with StartTracing().
-// An error is returned if tracing was not active.
-func (st *SessionTracing) StopTracing() error {
+// StopTracing stops the trace that was started with StartTracing(). If the
+// trace was started with the persist option, it is also written to
+// system.session_traces.
+func (st *SessionTracing) StopTracing(ctx context.Context) error {
 	if !st.enabled {
 		// We're not currently tracing. No-op.
 		return nil
 	}
+	persist := st.persist
 	st.enabled = false
 	st.kvTracingEnabled = false
 	st.showResults = false
+	st.persist = false
 	st.recordingType = tracing.NoRecording
 
 	var spans []tracing.RecordedSpan
@@ -1380,7 +1502,14 @@ func (st *SessionTracing) StopTracing() error {
 
 	var err error
 	st.lastRecording, err = generateSessionTraceVTable(spans)
-	return err
+	if err != nil || !persist {
+		return err
+	}
+	cfg := st.ex.server.cfg
+	return persistSessionTrace(
+		ctx, cfg.InternalExecutor, &cfg.Settings.SV, cfg.NodeID.Get(),
+		st.ex.sessionID, st.ex.sessionData.User, st.lastRecording,
+	)
 }
 
 // RecordingType returns which type of tracing is currently being done.
@@ -1860,6 +1989,25 @@ func (m *sessionDataMutator) SetVectorizeRowCountThreshold(val uint64) {
 	m.data.VectorizeRowCountThreshold = val
 }
 
+func (m *sessionDataMutator) SetImplicitTxnResultsBufferSize(val int64) {
+	m.data.ImplicitTxnResultsBufferSize = val
+}
+
+// SetSettingOverride overrides the cluster setting with the given name for
+// the session with the encoded value. An empty value removes the override.
+func (m *sessionDataMutator) SetSettingOverride(name, encoded string) error {
+	if encoded == "" {
+		m.data.SettingOverrides = m.data.SettingOverrides.Reset(settings.SessionScope, name)
+		return nil
+	}
+	overrides, err := m.data.SettingOverrides.Set(settings.SessionScope, name, encoded)
+	if err != nil {
+		return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+	}
+	m.data.SettingOverrides = overrides
+	return nil
+}
+
 func (m *sessionDataMutator) SetOptimizerFKs(val bool) {
 	m.data.OptimizerFKs = val
 }
@@ -1868,6 +2016,10 @@ func (m *sessionDataMutator) SetInsertFastPath(val bool) {
 	m.data.InsertFastPath = val
 }
 
+func (m *sessionDataMutator) SetMutationBatchSize(val int) {
+	m.data.MutationBatchSize = val
+}
+
 func (m *sessionDataMutator) SetSerialNormalizationMode(val sessiondata.SerialNormalizationMode) {
 	m.data.SerialNormalizationMode = val
 }
@@ -1935,7 +2087,8 @@ func newSQLStatsCollector(
 }
 
 // recordStatement records stats for one statement. samplePlanDescription can
-// be nil, as these are only sampled periodically per unique fingerprint.
+// be nil, as these are only sampled periodically per unique fingerprint. It
+// returns the plan regression of the statement that was detected, if any.
 func (s *sqlStatsCollector) recordStatement(
 	stmt *Statement,
 	samplePlanDescription *roachpb.ExplainTreePlanNode,
@@ -1947,15 +2100,25 @@ func (s *sqlStatsCollector) recordStatement(
 	err error,
 	parseLat, planLat, runLat, svcLat, ovhLat float64,
 	bytesRead, rowsRead int64,
-) {
-	s.appStats.recordStatement(
+) *planRegression {
+	return s.appStats.recordStatement(
 		stmt, samplePlanDescription, distSQLUsed, optUsed, implicitTxn, automaticRetryCount, numRows, err,
 		parseLat, planLat, runLat, svcLat, ovhLat, bytesRead, rowsRead)
 }
 
-// recordTransaction records stats for one transaction.
-func (s *sqlStatsCollector) recordTransaction(txnTimeSec float64, ev txnEvent, implicit bool) {
+// recordTransaction records stats for one transaction. stmtKeys are the
+// (deduplicated) fingerprints of the statements executed by the transaction.
+func (s *sqlStatsCollector) recordTransaction(
+	ctx context.Context,
+	txnTimeSec float64,
+	ev txnEvent,
+	implicit bool,
+	stmtKeys []stmtKey,
+	retryCount int,
+	contention activeTxnContention,
+) {
 	s.appStats.recordTransaction(txnTimeSec, ev, implicit)
+	s.appStats.recordTransactionFingerprint(ctx, stmtKeys, txnTimeSec, ev, implicit, retryCount, contention)
 }
 
 func (s *sqlStatsCollector) reset(sqlStats *sqlStats, appStats *appStats, phaseTimes *phaseTimes) {
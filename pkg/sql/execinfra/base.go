@@ -15,6 +15,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -86,6 +87,37 @@ type RowReceiver interface {
 	ProducerDone()
 }
 
+// DrainNotifier is implemented by the RowReceivers that can tell their
+// producers that the consumer no longer needs data rows without waiting for
+// the producers to push another row. This lets producers that do a lot of work
+// between rows (e.g. a TableReader with a selective filter, or the inbound
+// stream of a remote flow) stop early, which matters most when a LIMIT was
+// satisfied by the rows of other producers.
+type DrainNotifier interface {
+	// DrainRequestedC returns a channel that is closed once the consumer has
+	// asked for draining or is closed.
+	DrainRequestedC() <-chan struct{}
+}
+
+// BatchReceiver is implemented by the RowReceivers that can receive the output
+// of a vectorized flow in columnar batches. The root Materializer of a flow
+// pushes the batches to such a receiver as they are instead of converting them
+// into rows, which spares both of them the conversion.
+type BatchReceiver interface {
+	RowReceiver
+
+	// AcceptsBatches returns whether the output of the flow should be pushed
+	// with PushBatch. If it returns false, the rows are pushed as usual.
+	AcceptsBatches() bool
+
+	// PushBatch sends a batch to the consumer. The batch has no selection
+	// vector, and it is only valid until PushBatch returns. The metadata is
+	// still sent with Push.
+	//
+	// The return value has the same meaning as the one of Push.
+	PushBatch(batch coldata.Batch) ConsumerStatus
+}
+
 // RowSource is any component of a flow that produces rows that can be consumed
 // by another component.
 //
@@ -415,10 +447,15 @@ type RowChannel struct {
 	// numSenders is an atomic counter that keeps track of how many senders have
 	// yet to call ProducerDone().
 	numSenders int32
+
+	// drainC is closed the first time ConsumerDone or ConsumerClosed is called.
+	drainC    chan struct{}
+	drainOnce sync.Once
 }
 
 var _ RowReceiver = &RowChannel{}
 var _ RowSource = &RowChannel{}
+var _ DrainNotifier = &RowChannel{}
 
 // InitWithNumSenders initializes the RowChannel with the default buffer size.
 // numSenders is the number of producers that will be pushing to this channel.
@@ -434,6 +471,7 @@ func (rc *RowChannel) InitWithBufSizeAndNumSenders(types []types.T, chanBufSize,
 	rc.types = types
 	rc.dataChan = make(chan RowChannelMsg, chanBufSize)
 	rc.C = rc.dataChan
+	rc.drainC = make(chan struct{})
 	atomic.StoreInt32(&rc.numSenders, int32(numSenders))
 }
 
@@ -489,11 +527,13 @@ func (rc *RowChannel) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata
 // ConsumerDone is part of the RowSource interface.
 func (rc *RowChannel) ConsumerDone() {
 	rc.consumerDone()
+	rc.notifyDrain()
 }
 
 // ConsumerClosed is part of the RowSource interface.
 func (rc *RowChannel) ConsumerClosed() {
 	rc.consumerClosed("RowChannel")
+	rc.notifyDrain()
 	numSenders := atomic.LoadInt32(&rc.numSenders)
 	// Drain (at most) numSenders messages in case senders are blocked trying to
 	// emit a row.
@@ -512,3 +552,14 @@ func (rc *RowChannel) ConsumerClosed() {
 func (rc *RowChannel) Types() []types.T {
 	return rc.types
 }
+
+// DrainRequestedC is part of the DrainNotifier interface.
+func (rc *RowChannel) DrainRequestedC() <-chan struct{} {
+	return rc.drainC
+}
+
+func (rc *RowChannel) notifyDrain() {
+	if rc.drainC != nil {
+		rc.drainOnce.Do(func() { close(rc.drainC) })
+	}
+}
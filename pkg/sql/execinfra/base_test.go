@@ -50,6 +50,46 @@ func TestRunDrain(t *testing.T) {
 	}
 }
 
+// Test that the consumer of a RowChannel asking for draining is noticed by a
+// producer that doesn't push any rows.
+func TestRowChannelDrainRequested(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+
+	rc := &RowChannel{}
+	rc.InitWithNumSenders(sqlbase.OneIntCol, 1)
+	var out ProcOutputHelper
+	if err := out.Init(
+		&execinfrapb.PostProcessSpec{}, sqlbase.OneIntCol, nil /* evalCtx */, rc,
+	); err != nil {
+		t.Fatal(err)
+	}
+	row := sqlbase.EncDatumRow{sqlbase.IntEncDatum(0)}
+
+	select {
+	case <-rc.DrainRequestedC():
+		t.Fatal("unexpected drain request")
+	default:
+	}
+	if outRow, ok, err := out.ProcessRow(ctx, row); err != nil || outRow == nil || !ok {
+		t.Fatalf("expected row to be processed, found %v, %t, %v", outRow, ok, err)
+	}
+
+	rc.ConsumerDone()
+	select {
+	case <-rc.DrainRequestedC():
+	default:
+		t.Fatal("expected drain request")
+	}
+	if outRow, ok, err := out.ProcessRow(ctx, row); err != nil || outRow != nil || ok {
+		t.Fatalf("expected processing to stop, found %v, %t, %v", outRow, ok, err)
+	}
+
+	// Closing the consumer afterwards doesn't close the channel again.
+	rc.ConsumerClosed()
+}
+
 // Benchmark a pipeline of RowChannels.
 func BenchmarkRowChannelPipeline(b *testing.B) {
 	for _, length := range []int{1, 2, 3, 4} {
@@ -58,6 +58,20 @@ func processExpression(
 	if exprSpec.Expr == "" {
 		return nil, nil
 	}
+	typedExpr, err := typeCheckExpression(exprSpec, semaCtx, h)
+	if err != nil {
+		return nil, err
+	}
+	return foldConstants(typedExpr, evalCtx)
+}
+
+// typeCheckExpression parses and type checks the string expression inside an
+// Expression, associating ordinal references with the given helper. Unlike
+// processExpression, it doesn't evaluate the constant subexpressions, so the
+// result doesn't depend on the session.
+func typeCheckExpression(
+	exprSpec execinfrapb.Expression, semaCtx *tree.SemaContext, h *tree.IndexedVarHelper,
+) (tree.TypedExpr, error) {
 	expr, err := parser.ParseExpr(exprSpec.Expr)
 	if err != nil {
 		return nil, err
@@ -77,14 +91,19 @@ func processExpression(
 		// Type checking must succeed by now.
 		return nil, errors.NewAssertionErrorWithWrappedErrf(err, "%s", expr)
 	}
+	return typedExpr, nil
+}
 
+// foldConstants pre-evaluates the constant subexpressions of typedExpr.
+// typedExpr itself is not modified.
+func foldConstants(typedExpr tree.TypedExpr, evalCtx *tree.EvalContext) (tree.TypedExpr, error) {
 	// Pre-evaluate constant expressions. This is necessary to avoid repeatedly
 	// re-evaluating constant values every time the expression is applied.
 	//
 	// TODO(solon): It would be preferable to enhance our expression serialization
 	// format so this wouldn't be necessary.
 	c := tree.MakeConstantEvalVisitor(evalCtx)
-	expr, _ = tree.WalkExpr(&c, typedExpr)
+	expr, _ := tree.WalkExpr(&c, typedExpr)
 	if err := c.Err(); err != nil {
 		return nil, err
 	}
@@ -151,6 +170,19 @@ func (eh *ExprHelper) Init(
 // used to remap the indices of IndexedVars before binding them to a container.
 func (eh *ExprHelper) InitWithRemapping(
 	expr execinfrapb.Expression, types []types.T, evalCtx *tree.EvalContext, indexVarMap []int,
+) error {
+	return eh.InitWithCache(nil /* cache */, expr, types, evalCtx, indexVarMap)
+}
+
+// InitWithCache is like InitWithRemapping, but it first looks up the typed
+// expression in the given cache (which can be nil) and adds it to the cache if
+// it wasn't there.
+func (eh *ExprHelper) InitWithCache(
+	cache *ExprCache,
+	expr execinfrapb.Expression,
+	types []types.T,
+	evalCtx *tree.EvalContext,
+	indexVarMap []int,
 ) error {
 	if expr.Empty() {
 		return nil
@@ -165,17 +197,38 @@ func (eh *ExprHelper) InitWithRemapping(
 		eh.Vars.Rebind(eh.Expr, true /* alsoReset */, false /* normalizeToNonNil */)
 		return nil
 	}
-	var err error
-	semaContext := tree.MakeSemaContext()
-	eh.Expr, err = processExpression(expr, evalCtx, &semaContext, &eh.Vars)
-	if err != nil {
-		return err
+	// The cache contains the typed expressions before their constants are
+	// folded, since the values of the constants can depend on the session (e.g.
+	// current_user() or casts that depend on the time zone). The constants are
+	// folded for every flow.
+	var typedExpr tree.TypedExpr
+	var key exprCacheKey
+	if cache != nil {
+		key = makeExprCacheKey(expr.Expr, types, evalCtx, indexVarMap)
+		if cached := cache.lookup(key); cached != nil {
+			// The IndexedVars of the cached expression have already been remapped,
+			// so we bind them to our eh.Vars directly.
+			typedExpr = replaceIndexedVars(cached, func(iv *tree.IndexedVar) *tree.IndexedVar {
+				return eh.Vars.IndexedVar(iv.Idx)
+			})
+		}
 	}
-	var t transform.ExprTransformContext
-	if t.AggregateInExpr(eh.Expr, evalCtx.SessionData.SearchPath) {
-		return errors.Errorf("expression '%s' has aggregate", eh.Expr)
+	if typedExpr == nil {
+		var err error
+		semaContext := tree.MakeSemaContext()
+		typedExpr, err = typeCheckExpression(expr, &semaContext, &eh.Vars)
+		if err != nil {
+			return err
+		}
+		var t transform.ExprTransformContext
+		if t.AggregateInExpr(typedExpr, evalCtx.SessionData.SearchPath) {
+			return errors.Errorf("expression '%s' has aggregate", typedExpr)
+		}
+		cache.add(key, typedExpr)
 	}
-	return nil
+	var err error
+	eh.Expr, err = foldConstants(typedExpr, evalCtx)
+	return err
 }
 
 // EvalFilter is used for filter expressions; it evaluates the expression and
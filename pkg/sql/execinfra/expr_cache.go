@@ -0,0 +1,189 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfra
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+var exprCacheSize = settings.RegisterNonNegativeIntSetting(
+	"sql.distsql.expression_cache.size",
+	"maximum number of typed expressions that each node caches in order to "+
+		"speed up the setup of recurring flows (0 disables the cache)",
+	1024,
+)
+
+// exprCacheKey identifies a typed expression. Next to the serialized
+// expression, it contains everything that parsing and type checking of the
+// expression depend on. The cached expressions are not constant folded, so
+// they don't depend on the rest of the session.
+type exprCacheKey struct {
+	expr        string
+	types       string
+	indexVarMap string
+	searchPath  string
+}
+
+func makeExprCacheKey(
+	expr string, typs []types.T, evalCtx *tree.EvalContext, indexVarMap []int,
+) exprCacheKey {
+	var b strings.Builder
+	for i := range typs {
+		b.WriteString(typs[i].DebugString())
+		b.WriteByte(';')
+	}
+	key := exprCacheKey{expr: expr, types: b.String()}
+	if indexVarMap != nil {
+		b.Reset()
+		for _, idx := range indexVarMap {
+			b.WriteString(strconv.Itoa(idx))
+			b.WriteByte(',')
+		}
+		key.indexVarMap = b.String()
+	}
+	if evalCtx.SessionData != nil {
+		key.searchPath = evalCtx.SessionData.SearchPath.String()
+	}
+	return key
+}
+
+// ExprCache is a per-node cache of the typed expressions that the processors
+// and the vectorized operators evaluate. Parsing and type checking of the
+// expressions is a significant part of the setup of short flows, and the same
+// flows tend to be set up over and over again on a node by high QPS workloads.
+//
+// The cache is safe for concurrent use, and it is also safe to use it through
+// a nil reference, in which case nothing is cached. It is cleared whenever its
+// size setting changes.
+type ExprCache struct {
+	st *cluster.Settings
+	mu struct {
+		syncutil.Mutex
+		cache *cache.UnorderedCache
+	}
+}
+
+// NewExprCache returns a new ExprCache whose size is controlled by the
+// sql.distsql.expression_cache.size cluster setting.
+func NewExprCache(st *cluster.Settings) *ExprCache {
+	c := &ExprCache{st: st}
+	c.mu.cache = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(s int, key, value interface{}) bool {
+			return int64(s) > exprCacheSize.Get(&st.SV)
+		},
+	})
+	exprCacheSize.SetOnChange(&st.SV, c.Clear)
+	return c
+}
+
+// Clear removes all expressions from the cache.
+func (c *ExprCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.cache.Clear()
+}
+
+// Len returns the number of expressions in the cache.
+func (c *ExprCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.cache.Len()
+}
+
+// lookup returns the cached expression for the given key, if any. The
+// IndexedVars of the returned expression are not bound to any container.
+func (c *ExprCache) lookup(key exprCacheKey) tree.TypedExpr {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.mu.cache.Get(key)
+	if !ok {
+		return nil
+	}
+	return v.(tree.TypedExpr)
+}
+
+// add inserts expr into the cache under the given key. expr is not modified,
+// and its IndexedVars are unbound from their container in the cached copy so
+// that the cache doesn't keep the container alive.
+func (c *ExprCache) add(key exprCacheKey, expr tree.TypedExpr) {
+	if c == nil || exprCacheSize.Get(&c.st.SV) == 0 || dependsOnParseTime(key.expr) {
+		return
+	}
+	expr = replaceIndexedVars(expr, func(iv *tree.IndexedVar) *tree.IndexedVar {
+		return tree.NewTypedOrdinalReference(iv.Idx, iv.ResolvedType())
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.cache.Add(key, expr)
+}
+
+// relativeTimeKeywords are the words that make date and time strings be
+// parsed relative to the current time.
+var relativeTimeKeywords = []string{"now", "today", "tomorrow", "yesterday"}
+
+// dependsOnParseTime returns whether the typed form of the serialized
+// expression might depend on the time at which it is type checked, because
+// string constants that are typed as dates or times are parsed then. This is
+// conservative: it only looks for the relative time keywords in the string.
+func dependsOnParseTime(expr string) bool {
+	expr = strings.ToLower(expr)
+	for _, kw := range relativeTimeKeywords {
+		if strings.Contains(expr, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// ivarReplacer is a tree.Visitor that replaces all IndexedVars in an
+// expression with the results of fn.
+type ivarReplacer struct {
+	fn func(*tree.IndexedVar) *tree.IndexedVar
+}
+
+var _ tree.Visitor = &ivarReplacer{}
+
+func (v *ivarReplacer) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if iv, ok := expr.(*tree.IndexedVar); ok {
+		return false, v.fn(iv)
+	}
+	return true, expr
+}
+
+func (*ivarReplacer) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// replaceIndexedVars returns a copy of expr in which all IndexedVars are
+// replaced with the results of fn. expr itself is not modified.
+func replaceIndexedVars(
+	expr tree.TypedExpr, fn func(*tree.IndexedVar) *tree.IndexedVar,
+) tree.TypedExpr {
+	v := ivarReplacer{fn: fn}
+	newExpr, _ := tree.WalkExpr(&v, expr)
+	return newExpr.(tree.TypedExpr)
+}
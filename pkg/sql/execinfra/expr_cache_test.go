@@ -0,0 +1,148 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExprCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	c := NewExprCache(st)
+
+	typs := []types.T{*types.Int, *types.Int, *types.Int}
+	expr := execinfrapb.Expression{Expr: "@1 + @3 * 2"}
+	eval := func(eh *ExprHelper, a, b, c int) tree.Datum {
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(a))),
+			sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(b))),
+			sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(c))),
+		}
+		d, err := eh.Eval(row)
+		require.NoError(t, err)
+		return d
+	}
+
+	var h1, h2 ExprHelper
+	require.NoError(t, h1.InitWithCache(c, expr, typs, &evalCtx, nil /* indexVarMap */))
+	require.Equal(t, 1, c.Len())
+	require.NoError(t, h2.InitWithCache(c, expr, typs, &evalCtx, nil /* indexVarMap */))
+	require.Equal(t, 1, c.Len())
+
+	// Both helpers must evaluate the expression against their own rows.
+	require.Equal(t, tree.NewDInt(7), eval(&h1, 1, 2, 3))
+	require.Equal(t, tree.NewDInt(14), eval(&h2, 4, 5, 5))
+	require.Equal(t, h1.Expr.String(), h2.Expr.String())
+	require.True(t, h2.Vars.IndexedVarUsed(0))
+	require.False(t, h2.Vars.IndexedVarUsed(1))
+	require.True(t, h2.Vars.IndexedVarUsed(2))
+
+	// The same expression over different types or with a remapping is a
+	// different cache entry.
+	var h3 ExprHelper
+	require.NoError(t, h3.InitWithCache(c, expr, typs, &evalCtx, []int{0, 1, 1}))
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, tree.NewDInt(11), eval(&h3, 1, 5, 6))
+	var h4 ExprHelper
+	floatTyps := []types.T{*types.Float, *types.Int, *types.Float}
+	require.NoError(t, h4.InitWithCache(c, expr, floatTyps, &evalCtx, nil /* indexVarMap */))
+	require.Equal(t, 3, c.Len())
+
+	// Changing the size of the cache clears it, and a size of zero disables it.
+	exprCacheSize.Override(&st.SV, 0)
+	require.Equal(t, 0, c.Len())
+	var h5 ExprHelper
+	require.NoError(t, h5.InitWithCache(c, expr, typs, &evalCtx, nil /* indexVarMap */))
+	require.Equal(t, 0, c.Len())
+	require.Equal(t, tree.NewDInt(7), eval(&h5, 1, 2, 3))
+
+	// A nil cache doesn't cache anything.
+	var nilCache *ExprCache
+	var h6 ExprHelper
+	require.NoError(t, h6.InitWithCache(nilCache, expr, typs, &evalCtx, nil /* indexVarMap */))
+	require.Equal(t, 0, nilCache.Len())
+	require.Equal(t, tree.NewDInt(7), eval(&h6, 1, 2, 3))
+}
+
+func TestExprCacheFoldsConstantsPerFlow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	c := NewExprCache(st)
+	typs := []types.T{*types.Int}
+
+	// Two sessions with different users and time zones.
+	evalCtx1 := tree.MakeTestingEvalContext(st)
+	defer evalCtx1.Stop(ctx)
+	evalCtx1.SessionData.User = "alice"
+	evalCtx2 := tree.MakeTestingEvalContext(st)
+	defer evalCtx2.Stop(ctx)
+	evalCtx2.SessionData.User = "bob"
+	tokyo, err := timeutil.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	evalCtx2.SessionData.DataConversion.Location = tokyo
+
+	row := sqlbase.EncDatumRow{sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(1))}
+	for _, tc := range []struct {
+		expr       string
+		res1, res2 tree.Datum
+	}{
+		{
+			expr: "current_user()",
+			res1: tree.NewDString("alice"),
+			res2: tree.NewDString("bob"),
+		},
+		{
+			// The cast to DATE depends on the time zone.
+			expr: "'2020-01-01 20:00:00+00:00'::TIMESTAMPTZ::DATE",
+			res1: tree.NewDDate(pgdate.MakeCompatibleDateFromDisk(18262)),
+			res2: tree.NewDDate(pgdate.MakeCompatibleDateFromDisk(18263)),
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr := execinfrapb.Expression{Expr: tc.expr}
+			var h1, h2 ExprHelper
+			require.NoError(t, h1.InitWithCache(c, expr, typs, &evalCtx1, nil /* indexVarMap */))
+			require.NoError(t, h2.InitWithCache(c, expr, typs, &evalCtx2, nil /* indexVarMap */))
+			d, err := h1.Eval(row)
+			require.NoError(t, err)
+			require.Equal(t, tc.res1.String(), d.String())
+			d, err = h2.Eval(row)
+			require.NoError(t, err)
+			require.Equal(t, tc.res2.String(), d.String())
+		})
+	}
+	require.Equal(t, 2, c.Len())
+
+	// Expressions whose typing depends on the current time are not cached.
+	var h ExprHelper
+	require.NoError(t, h.InitWithCache(
+		c, execinfrapb.Expression{Expr: "'now'::TIMESTAMPTZ"}, typs, &evalCtx1, nil, /* indexVarMap */
+	))
+	require.Equal(t, 2, c.Len())
+}
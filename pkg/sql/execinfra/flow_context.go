@@ -15,9 +15,11 @@ package execinfra
 import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 )
 
@@ -59,6 +61,18 @@ type FlowCtx struct {
 
 	// Local is true if this flow is being run as part of a local-only query.
 	Local bool
+
+	// SpillMon, if set, is the monitor against which the processors that can
+	// fall back to temp storage account for their memory (see
+	// NewLimitedMonitor). It is a child of EvalCtx.Mon whose limit is a share
+	// of the flow's memory budget, so that these processors spill to disk
+	// before the flow runs out of memory. It is stopped in Flow.Cleanup().
+	SpillMon *mon.BytesMonitor
+
+	// Progress, if set, tracks the number of rows produced by the processors of
+	// the flow. It is nil if progress tracking is disabled (see
+	// SettingProgressReportInterval).
+	Progress *FlowProgress
 }
 
 // NewEvalCtx returns a modifiable copy of the FlowCtx's EvalContext.
@@ -72,6 +86,24 @@ func (ctx *FlowCtx) NewEvalCtx() *tree.EvalContext {
 	return ctx.EvalCtx.Copy()
 }
 
+// ExprCache returns the cache of typed expressions to be used by the processors
+// and operators of this flow. It can be nil.
+func (ctx *FlowCtx) ExprCache() *ExprCache {
+	if ctx.Cfg == nil {
+		return nil
+	}
+	return ctx.Cfg.ExprCache
+}
+
+// SettingOverrides returns the overrides of cluster settings that are in
+// effect for the session on whose behalf this flow runs.
+func (ctx *FlowCtx) SettingOverrides() settings.Overrides {
+	if ctx.EvalCtx == nil || ctx.EvalCtx.SessionData == nil {
+		return settings.Overrides{}
+	}
+	return ctx.EvalCtx.SessionData.SettingOverrides
+}
+
 // TestingKnobs returns the distsql testing knobs for this flow context.
 func (ctx *FlowCtx) TestingKnobs() TestingKnobs {
 	return ctx.Cfg.TestingKnobs
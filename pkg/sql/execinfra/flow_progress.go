@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfra
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// SettingProgressReportInterval is a cluster setting that determines how often
+// remote flows report the number of rows produced by their processors to the
+// gateway, which uses them to display the live progress of a query.
+var SettingProgressReportInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.distsql.progress_report_interval",
+	"interval at which the processors of remote flows report their progress to the gateway "+
+		"(0 disables progress tracking)",
+	time.Second,
+)
+
+// FlowProgress tracks the number of rows produced so far by each processor of
+// a flow. Processors register themselves when they are initialized (see
+// ProcessorBase.InitWithEvalCtx) and their ProcOutputHelper counts the rows
+// that they emit.
+type FlowProgress struct {
+	mu struct {
+		syncutil.Mutex
+		// rows maps processor IDs to their row counters. The counters are
+		// accessed atomically.
+		rows map[int32]*int64
+		// reported contains the row counts returned by the last call to Updates.
+		reported map[int32]int64
+	}
+}
+
+// NewFlowProgress creates a FlowProgress.
+func NewFlowProgress() *FlowProgress {
+	p := &FlowProgress{}
+	p.mu.rows = make(map[int32]*int64)
+	p.mu.reported = make(map[int32]int64)
+	return p
+}
+
+// counter returns the row counter of the given processor.
+func (p *FlowProgress) counter(processorID int32) *int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.mu.rows[processorID]
+	if !ok {
+		c = new(int64)
+		p.mu.rows[processorID] = c
+	}
+	return c
+}
+
+// Rows returns the number of rows produced so far by each processor of the
+// flow, keyed by processor ID.
+func (p *FlowProgress) Rows() map[int32]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res := make(map[int32]int64, len(p.mu.rows))
+	for id, c := range p.mu.rows {
+		res[id] = atomic.LoadInt64(c)
+	}
+	return res
+}
+
+// Updates returns the row counts of the processors that produced rows since
+// the previous call, ordered by processor ID. All the outboxes of a flow
+// share its FlowProgress, so each update is sent to the gateway only once.
+func (p *FlowProgress) Updates() []execinfrapb.RemoteProducerMetadata_RowsProduced {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var res []execinfrapb.RemoteProducerMetadata_RowsProduced
+	for id, c := range p.mu.rows {
+		if rows := atomic.LoadInt64(c); rows != p.mu.reported[id] {
+			p.mu.reported[id] = rows
+			res = append(res, execinfrapb.RemoteProducerMetadata_RowsProduced{
+				ProcessorID: id, Rows: rows,
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ProcessorID < res[j].ProcessorID })
+	return res
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfra
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestFlowProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	p := NewFlowProgress()
+	atomic.AddInt64(p.counter(2), 5)
+	atomic.AddInt64(p.counter(0), 3)
+	// Processors that haven't produced rows yet are not reported.
+	_ = p.counter(1)
+
+	expected := []execinfrapb.RemoteProducerMetadata_RowsProduced{
+		{ProcessorID: 0, Rows: 3},
+		{ProcessorID: 2, Rows: 5},
+	}
+	if updates := p.Updates(); !reflect.DeepEqual(updates, expected) {
+		t.Fatalf("expected %v, got %v", expected, updates)
+	}
+	if updates := p.Updates(); len(updates) != 0 {
+		t.Fatalf("expected no updates, got %v", updates)
+	}
+
+	// Only the counts that changed since the last call are reported.
+	atomic.AddInt64(p.counter(2), 1)
+	expected = []execinfrapb.RemoteProducerMetadata_RowsProduced{{ProcessorID: 2, Rows: 6}}
+	if updates := p.Updates(); !reflect.DeepEqual(updates, expected) {
+		t.Fatalf("expected %v, got %v", expected, updates)
+	}
+
+	if rows, exp := p.Rows(), map[int32]int64{0: 3, 1: 0, 2: 6}; !reflect.DeepEqual(rows, exp) {
+		t.Fatalf("expected %v, got %v", exp, rows)
+	}
+}
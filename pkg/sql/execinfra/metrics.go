@@ -27,6 +27,16 @@ type DistSQLMetrics struct {
 	QueueWaitHist *metric.Histogram
 	MaxBytesHist  *metric.Histogram
 	CurBytesCount *metric.Gauge
+
+	SpilledBytesWritten *metric.Counter
+	SpilledBytesRead    *metric.Counter
+
+	// FlowsQueueTimedOut counts the flows dropped by the flow scheduler
+	// because they spent too long in its queue.
+	FlowsQueueTimedOut *metric.Counter
+	// FlowsReplanned counts the remote flows that were moved to another node
+	// or to the gateway because they failed to be set up.
+	FlowsReplanned *metric.Counter
 }
 
 // MetricStruct implements the metrics.Struct interface.
@@ -71,6 +81,18 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaFlowsQueueTimedOut = metric.Metadata{
+		Name:        "sql.distsql.flows.queue_timeout",
+		Help:        "Number of distributed SQL flows dropped because they spent too long in the queue",
+		Measurement: "Flows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaFlowsReplanned = metric.Metadata{
+		Name:        "sql.distsql.flows.replanned",
+		Help:        "Number of distributed SQL flows replanned on another node because they failed to be set up",
+		Measurement: "Flows",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaMemMaxBytes = metric.Metadata{
 		Name:        "sql.mem.distsql.max",
 		Help:        "Memory usage per sql statement for distsql",
@@ -83,6 +105,18 @@ var (
 		Measurement: "Memory",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaSpilledBytesWritten = metric.Metadata{
+		Name:        "sql.disk.distsql.spilled.bytes.written",
+		Help:        "Number of bytes written to temporary disk storage as a result of spilling",
+		Measurement: "Disk",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaSpilledBytesRead = metric.Metadata{
+		Name:        "sql.disk.distsql.spilled.bytes.read",
+		Help:        "Number of bytes read from temporary disk storage as a result of spilling",
+		Measurement: "Disk",
+		Unit:        metric.Unit_BYTES,
+	}
 )
 
 // See pkg/sql/mem_metrics.go
@@ -100,6 +134,12 @@ func MakeDistSQLMetrics(histogramWindow time.Duration) DistSQLMetrics {
 		QueueWaitHist: metric.NewLatency(metaQueueWaitHist, histogramWindow),
 		MaxBytesHist:  metric.NewHistogram(metaMemMaxBytes, histogramWindow, log10int64times1000, 3),
 		CurBytesCount: metric.NewGauge(metaMemCurBytes),
+
+		SpilledBytesWritten: metric.NewCounter(metaSpilledBytesWritten),
+		SpilledBytesRead:    metric.NewCounter(metaSpilledBytesRead),
+
+		FlowsQueueTimedOut: metric.NewCounter(metaFlowsQueueTimedOut),
+		FlowsReplanned:     metric.NewCounter(metaFlowsReplanned),
 	}
 }
 
@@ -13,6 +13,7 @@ package execinfra
 import (
 	"context"
 	"math"
+	"sync/atomic"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -48,6 +49,10 @@ type ProcOutputHelper struct {
 	// post-processed row directly.
 	output   RowReceiver
 	RowAlloc sqlbase.EncDatumRowAlloc
+	// drainC, if set, is closed once the consumer of output no longer needs
+	// rows (see DrainNotifier). ProcessRow checks it so that processors stop
+	// producing rows even if they don't push any for a while.
+	drainC <-chan struct{}
 
 	filter *ExprHelper
 	// renderExprs has length > 0 if we have a rendering. Only one of renderExprs
@@ -77,6 +82,14 @@ type ProcOutputHelper struct {
 	maxRowIdx uint64
 
 	rowIdx uint64
+
+	// rowsProduced, if set, is the counter of the rows emitted by the processor
+	// that is reported as the progress of the flow (see FlowProgress).
+	rowsProduced *int64
+
+	// ExprCache, if set, is consulted for the typed filter and render
+	// expressions before parsing them.
+	ExprCache *ExprCache
 }
 
 // Reset resets this ProcOutputHelper, retaining allocated memory in its slices.
@@ -102,10 +115,16 @@ func (h *ProcOutputHelper) Init(
 		return errors.Errorf("post-processing has both projection and rendering: %s", post)
 	}
 	h.output = output
+	h.drainC = nil
+	if n, ok := output.(DrainNotifier); ok {
+		h.drainC = n.DrainRequestedC()
+	}
 	h.numInternalCols = len(typs)
 	if post.Filter != (execinfrapb.Expression{}) {
 		h.filter = &ExprHelper{}
-		if err := h.filter.Init(post.Filter, typs, evalCtx); err != nil {
+		if err := h.filter.InitWithCache(
+			h.ExprCache, post.Filter, typs, evalCtx, nil, /* indexVarMap */
+		); err != nil {
 			return err
 		}
 	}
@@ -142,7 +161,9 @@ func (h *ProcOutputHelper) Init(
 		}
 		for i, expr := range post.RenderExprs {
 			h.renderExprs[i] = ExprHelper{}
-			if err := h.renderExprs[i].Init(expr, typs, evalCtx); err != nil {
+			if err := h.renderExprs[i].InitWithCache(
+				h.ExprCache, expr, typs, evalCtx, nil, /* indexVarMap */
+			); err != nil {
 				return err
 			}
 			h.OutputTypes[i] = *h.renderExprs[i].Expr.ResolvedType()
@@ -267,6 +288,14 @@ func (h *ProcOutputHelper) ProcessRow(
 	if h.rowIdx >= h.maxRowIdx {
 		return nil, false, nil
 	}
+	if h.drainC != nil {
+		select {
+		case <-h.drainC:
+			// The consumer asked for draining while we weren't pushing rows.
+			return nil, false, nil
+		default:
+		}
+	}
 
 	if h.filter != nil {
 		// Filtering.
@@ -286,6 +315,9 @@ func (h *ProcOutputHelper) ProcessRow(
 		// Suppress row.
 		return nil, true, nil
 	}
+	if h.rowsProduced != nil {
+		atomic.AddInt64(h.rowsProduced, 1)
+	}
 
 	if len(h.renderExprs) > 0 {
 		// Rendering.
@@ -797,6 +829,10 @@ func (pb *ProcessorBase) InitWithEvalCtx(
 	pb.MemMonitor = memMonitor
 	pb.trailingMetaCallback = opts.TrailingMetaCallback
 	pb.inputsToDrain = opts.InputsToDrain
+	pb.Out.ExprCache = flowCtx.ExprCache()
+	if flowCtx.Progress != nil {
+		pb.Out.rowsProduced = flowCtx.Progress.counter(processorID)
+	}
 	return pb.Out.Init(post, types, pb.EvalCtx, output)
 }
 
@@ -876,16 +912,31 @@ func NewMonitor(ctx context.Context, parent *mon.BytesMonitor, name string) *mon
 	return &monitor
 }
 
+// GetWorkMemLimit returns the number of bytes determining the amount of RAM
+// available to a single processor or operator: SettingWorkMemBytes (possibly
+// overridden for the session of the flow), unless it is overridden by
+// TestingKnobs.MemoryLimitBytes.
+func GetWorkMemLimit(flowCtx *FlowCtx) int64 {
+	limit := flowCtx.Cfg.TestingKnobs.MemoryLimitBytes
+	if limit <= 0 {
+		limit = SettingWorkMemBytes.GetWithOverrides(
+			&flowCtx.Cfg.Settings.SV, flowCtx.SettingOverrides(),
+		)
+	}
+	return limit
+}
+
 // NewLimitedMonitor is a utility function used by processors to create a new
 // limited memory monitor with the given name and start it. The returned
-// monitor must be closed. The limit is determined by SettingWorkMemBytes
-// or config.TestingKnobs.MemoryLimitBytes.
+// monitor must be closed. The limit is determined by GetWorkMemLimit. If the
+// flow has a memory budget and parent is the flow's monitor, the new monitor
+// draws from the flow's SpillMon instead.
 func NewLimitedMonitor(
-	ctx context.Context, parent *mon.BytesMonitor, config *ServerConfig, name string,
+	ctx context.Context, parent *mon.BytesMonitor, flowCtx *FlowCtx, name string,
 ) *mon.BytesMonitor {
-	limit := config.TestingKnobs.MemoryLimitBytes
-	if limit <= 0 {
-		limit = SettingWorkMemBytes.Get(&config.Settings.SV)
+	limit := GetWorkMemLimit(flowCtx)
+	if flowCtx.SpillMon != nil && parent == flowCtx.EvalCtx.Mon {
+		parent = flowCtx.SpillMon
 	}
 	limitedMon := mon.MakeMonitorInheritWithLimit(name, limit, parent)
 	limitedMon.Start(ctx, parent, mon.BoundAccount{})
@@ -30,6 +30,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/marusama/semaphore"
 )
 
 // Version identifies the distsql protocol version.
@@ -58,7 +60,7 @@ import (
 //
 // ATTENTION: When updating these fields, add to version_history.txt explaining
 // what changed.
-const Version execinfrapb.DistSQLVersion = 24
+const Version execinfrapb.DistSQLVersion = 25
 
 // MinAcceptedVersion is the oldest version that the server is
 // compatible with; see above.
@@ -86,11 +88,29 @@ var SettingUseTempStorageSorts = settings.RegisterPublicBoolSetting(
 
 // SettingWorkMemBytes is a cluster setting that determines the maximum amount
 // of RAM that a processor can use.
-var SettingWorkMemBytes = settings.RegisterByteSizeSetting(
-	"sql.distsql.temp_storage.workmem",
-	"maximum amount of memory in bytes a processor can use before falling back to temp storage",
-	64*1024*1024, /* 64MB */
-)
+var SettingWorkMemBytes = func() *settings.ByteSizeSetting {
+	s := settings.RegisterByteSizeSetting(
+		"sql.distsql.temp_storage.workmem",
+		"maximum amount of memory in bytes a processor can use before falling back to temp storage",
+		64*1024*1024, /* 64MB */
+	)
+	s.SetOverridable(settings.SessionScope | settings.TenantScope)
+	return s
+}()
+
+// SettingFlowMemoryLimit is a cluster setting that determines the maximum
+// amount of RAM that all the processors of a flow can use on a single node.
+var SettingFlowMemoryLimit = func() *settings.ByteSizeSetting {
+	s := settings.RegisterByteSizeSetting(
+		"sql.distsql.flow_memory_limit",
+		"maximum amount of memory in bytes the processors of a flow can use on a node; "+
+			"the processors that can use temp storage fall back to it before the limit is reached "+
+			"(0 disables the limit)",
+		0,
+	)
+	s.SetOverridable(settings.SessionScope | settings.TenantScope)
+	return s
+}()
 
 // ServerConfig encompasses the configuration required to create a
 // DistSQLServer.
@@ -116,6 +136,10 @@ type ServerConfig struct {
 	Stopper      *stop.Stopper
 	TestingKnobs TestingKnobs
 
+	// ExprCache caches the typed expressions of the flows set up on this node.
+	// It can be nil, in which case the expressions aren't cached.
+	ExprCache *ExprCache
+
 	// ParentMemoryMonitor is normally the root SQL monitor. It should only be
 	// used when setting up a server, or in tests.
 	ParentMemoryMonitor *mon.BytesMonitor
@@ -124,6 +148,16 @@ type ServerConfig struct {
 	// working set is larger than can be stored in memory.
 	TempStorage diskmap.Factory
 
+	// TempFS is used by the vectorized execution engine to store columnar data
+	// when the working set is larger than can be stored in memory.
+	TempFS vfs.FS
+	// TempStoragePath is the directory in TempFS in which the vectorized
+	// execution engine stores its files.
+	TempStoragePath string
+	// VecFDSemaphore limits the number of files that the spilling operators of
+	// the vectorized execution engine have open at once.
+	VecFDSemaphore semaphore.Semaphore
+
 	// BulkAdder is used by some processors to bulk-ingest data as SSTs.
 	BulkAdder storagebase.BulkAdderFactory
 
@@ -11,6 +11,7 @@
 package execinfrapb
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -71,5 +72,15 @@ func MakeEvalContext(evalCtx *tree.EvalContext) EvalContext {
 			res.SeqState.Seqs = append(res.SeqState.Seqs, &SequenceState_Seq{SeqID: seqID, LatestVal: latestVal})
 		}
 	}
+
+	// Populate the session overrides of cluster settings. The tenant overrides
+	// are known to every node, so they are not sent over.
+	overrides := evalCtx.SessionData.SettingOverrides
+	for _, name := range overrides.Names(settings.SessionScope) {
+		value, _ := overrides.Get(settings.SessionScope, name)
+		res.SettingOverrides = append(res.SettingOverrides, EvalContext_SettingOverride{
+			Name: name, Value: value,
+		})
+	}
 	return res
 }
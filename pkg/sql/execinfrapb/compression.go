@@ -0,0 +1,74 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfrapb
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// SupportedStreamCompressions are the codecs that consumers on this node are
+// able to decompress. They are advertised to producers in the
+// ConsumerHandshake.
+var SupportedStreamCompressions = []StreamCompression{StreamCompression_SNAPPY}
+
+// NegotiateStreamCompression returns the codec that a producer that prefers
+// the given codec should use for a stream whose consumer sent the given
+// handshake. StreamCompression_NONE is returned if the consumer doesn't
+// support the preferred codec.
+func NegotiateStreamCompression(
+	preferred StreamCompression, handshake *ConsumerHandshake,
+) StreamCompression {
+	for _, c := range handshake.SupportedCompressions {
+		if c == preferred {
+			return c
+		}
+	}
+	return StreamCompression_NONE
+}
+
+// CompressRawBytes compresses src with the given codec, reusing dst if it is
+// large enough.
+func CompressRawBytes(dst, src []byte, c StreamCompression) ([]byte, error) {
+	switch c {
+	case StreamCompression_NONE:
+		return src, nil
+	case StreamCompression_SNAPPY:
+		return snappy.Encode(dst[:cap(dst)], src), nil
+	default:
+		return nil, errors.AssertionFailedf("unsupported stream compression %s", c)
+	}
+}
+
+// DecompressRawBytes returns the decompressed RawBytes of the given
+// ProducerData, reusing dst if it is large enough. If the data isn't
+// compressed, RawBytes are returned as is.
+func DecompressRawBytes(dst []byte, data *ProducerData) ([]byte, error) {
+	switch data.Compression {
+	case StreamCompression_NONE:
+		return data.RawBytes, nil
+	case StreamCompression_SNAPPY:
+		n, err := snappy.DecodedLen(data.RawBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing raw bytes")
+		}
+		if cap(dst) < n {
+			dst = make([]byte, n)
+		}
+		res, err := snappy.Decode(dst[:cap(dst)], data.RawBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing raw bytes")
+		}
+		return res, nil
+	default:
+		return nil, errors.Errorf("unsupported stream compression %s", data.Compression)
+	}
+}
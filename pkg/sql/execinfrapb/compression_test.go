@@ -0,0 +1,81 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execinfrapb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestStreamCompressionRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	raw := randutil.RandBytes(rng, 1+rng.Intn(4096))
+	// Repeat the random bytes so that the compressed payload is smaller.
+	raw = bytes.Repeat(raw, 4)
+	for _, c := range []StreamCompression{StreamCompression_NONE, StreamCompression_SNAPPY} {
+		t.Run(c.String(), func(t *testing.T) {
+			compressed, err := CompressRawBytes(nil /* dst */, raw, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c != StreamCompression_NONE && len(compressed) >= len(raw) {
+				t.Fatalf("expected compressed size %d to be less than %d", len(compressed), len(raw))
+			}
+			// Round trip through the proto to check the marshaling of the codec.
+			msg := ProducerMessage{Data: ProducerData{RawBytes: compressed, Compression: c}}
+			enc, err := msg.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decMsg ProducerMessage
+			if err := decMsg.Unmarshal(enc); err != nil {
+				t.Fatal(err)
+			}
+			if decMsg.Data.Compression != c {
+				t.Fatalf("expected codec %s, found %s", c, decMsg.Data.Compression)
+			}
+			res, err := DecompressRawBytes(nil /* dst */, &decMsg.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(raw, res) {
+				t.Fatalf("expected %v after round trip, found %v", raw, res)
+			}
+		})
+	}
+}
+
+func TestNegotiateStreamCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	handshake := ConsumerHandshake{SupportedCompressions: SupportedStreamCompressions}
+	enc, err := handshake.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decHandshake ConsumerHandshake
+	if err := decHandshake.Unmarshal(enc); err != nil {
+		t.Fatal(err)
+	}
+	if c := NegotiateStreamCompression(StreamCompression_SNAPPY, &decHandshake); c != StreamCompression_SNAPPY {
+		t.Fatalf("expected %s, found %s", StreamCompression_SNAPPY, c)
+	}
+	// A consumer that doesn't advertise any codecs (e.g. an older node) only
+	// accepts uncompressed data.
+	if c := NegotiateStreamCompression(StreamCompression_SNAPPY, &ConsumerHandshake{}); c != StreamCompression_NONE {
+		t.Fatalf("expected %s, found %s", StreamCompression_NONE, c)
+	}
+}
@@ -36,10 +36,26 @@ func ConvertToColumnOrdering(specOrdering Ordering) sqlbase.ColumnOrdering {
 		} else {
 			ordering[i].Direction = encoding.Descending
 		}
+		switch c.NullsOrder {
+		case Ordering_Column_NULLS_FIRST:
+			ordering[i].NullsOrder = tree.NullsFirst
+		case Ordering_Column_NULLS_LAST:
+			ordering[i].NullsOrder = tree.NullsLast
+		}
 	}
 	return ordering
 }
 
+// NullsReversed returns whether the NULLs of the column are placed on the
+// opposite side to the default one, that is last in the ascending or first in
+// the descending order.
+func (c Ordering_Column) NullsReversed() bool {
+	if c.Direction == Ordering_Column_DESC {
+		return c.NullsOrder == Ordering_Column_NULLS_FIRST
+	}
+	return c.NullsOrder == Ordering_Column_NULLS_LAST
+}
+
 // ConvertToSpecOrdering converts a sqlbase.ColumnOrdering type
 // to an Ordering type (as defined in data.proto).
 func ConvertToSpecOrdering(columnOrdering sqlbase.ColumnOrdering) Ordering {
@@ -68,6 +84,12 @@ func ConvertToMappedSpecOrdering(
 		} else {
 			specOrdering.Columns[i].Direction = Ordering_Column_DESC
 		}
+		switch c.NullsOrder {
+		case tree.NullsFirst:
+			specOrdering.Columns[i].NullsOrder = Ordering_Column_NULLS_FIRST
+		case tree.NullsLast:
+			specOrdering.Columns[i].NullsOrder = Ordering_Column_NULLS_LAST
+		}
 	}
 	return specOrdering
 }
@@ -214,6 +236,12 @@ type ProducerMetadata struct {
 	BulkProcessorProgress *RemoteProducerMetadata_BulkProcessorProgress
 	// Metrics contains information about goodput of the node.
 	Metrics *RemoteProducerMetadata_Metrics
+	// ContentionEvents contains the contention events that the KV layer
+	// reported for the requests of a processor.
+	ContentionEvents []roachpb.ContentionEvent
+	// RowsProduced reports the number of rows produced so far by a processor of
+	// a remote flow. It is used to display the live progress of a query.
+	RowsProduced *RemoteProducerMetadata_RowsProduced
 }
 
 var (
@@ -282,6 +310,10 @@ func RemoteProducerMetaToLocalMeta(
 		meta.Err = v.Error.ErrorDetail(ctx)
 	case *RemoteProducerMetadata_Metrics_:
 		meta.Metrics = v.Metrics
+	case *RemoteProducerMetadata_ContentionEvents_:
+		meta.ContentionEvents = v.ContentionEvents.Events
+	case *RemoteProducerMetadata_RowsProduced_:
+		meta.RowsProduced = v.RowsProduced
 	default:
 		return *meta, false
 	}
@@ -326,6 +358,16 @@ func LocalMetaToRemoteProducerMeta(
 		rpm.Value = &RemoteProducerMetadata_Metrics_{
 			Metrics: meta.Metrics,
 		}
+	} else if meta.ContentionEvents != nil {
+		rpm.Value = &RemoteProducerMetadata_ContentionEvents_{
+			ContentionEvents: &RemoteProducerMetadata_ContentionEvents{
+				Events: meta.ContentionEvents,
+			},
+		}
+	} else if meta.RowsProduced != nil {
+		rpm.Value = &RemoteProducerMetadata_RowsProduced_{
+			RowsProduced: meta.RowsProduced,
+		}
 	} else {
 		rpm.Value = &RemoteProducerMetadata_Error{
 			Error: NewError(ctx, meta.Err),
@@ -47,6 +47,12 @@ func (ord *Ordering) diagramString() string {
 		} else {
 			buf.WriteByte('+')
 		}
+		switch c.NullsOrder {
+		case Ordering_Column_NULLS_FIRST:
+			buf.WriteString(" nulls first")
+		case Ordering_Column_NULLS_LAST:
+			buf.WriteString(" nulls last")
+		}
 	}
 	return buf.String()
 }
@@ -359,6 +365,8 @@ func (is *InputSyncSpec) summary(showTypes bool) (string, []string) {
 		return "unordered", typs
 	case InputSyncSpec_ORDERED:
 		return "ordered", append(typs, is.Ordering.diagramString())
+	case InputSyncSpec_SERIAL_UNORDERED:
+		return "serial unordered", typs
 	default:
 		return "unknown", []string{}
 	}
@@ -452,6 +460,14 @@ func (s *BulkRowWriterSpec) summary() (string, []string) {
 	return "BulkRowWriterSpec", []string{}
 }
 
+// summary implements the diagramCellType interface.
+func (s *TTLDeleterSpec) summary() (string, []string) {
+	return "TTLDeleter", []string{
+		fmt.Sprintf("%s@%s", s.Table.PrimaryIndex.Name, s.Table.Name),
+		fmt.Sprintf("ExpireBefore: %s", s.ExpireBefore),
+	}
+}
+
 // summary implements the diagramCellType interface.
 func (w *WindowerSpec) summary() (string, []string) {
 	details := make([]string, 0, len(w.WindowFns))
@@ -506,6 +522,9 @@ type diagramProcessor struct {
 	StageID int32         `json:"stage"`
 
 	processorID int32
+	// progressIdx is the index in Core.Details of the row count added by
+	// AddProgress plus one, or zero if no row count was added yet.
+	progressIdx int
 }
 
 type diagramEdge struct {
@@ -526,6 +545,11 @@ type FlowDiagram interface {
 
 	// AddSpans adds stats extracted from the input spans to the diagram.
 	AddSpans([]tracing.RecordedSpan)
+
+	// AddProgress adds the number of rows produced so far by each processor,
+	// keyed by processor ID, to the diagram. The counts replace those added by
+	// previous calls.
+	AddProgress(rows map[int32]int64)
 }
 
 type diagramData struct {
@@ -559,6 +583,24 @@ func (d *diagramData) AddSpans(spans []tracing.RecordedSpan) {
 	}
 }
 
+// AddProgress implements the FlowDiagram interface.
+func (d *diagramData) AddProgress(rows map[int32]int64) {
+	for i := range d.Processors {
+		p := &d.Processors[i]
+		n, ok := rows[p.processorID]
+		if !ok {
+			continue
+		}
+		detail := fmt.Sprintf("rows produced: %d", n)
+		if p.progressIdx > 0 {
+			p.Core.Details[p.progressIdx-1] = detail
+			continue
+		}
+		p.Core.Details = append(p.Core.Details, detail)
+		p.progressIdx = len(p.Core.Details)
+	}
+}
+
 func generateDiagramData(
 	sql string, flows []FlowSpec, nodeNames []string, showInputTypes bool,
 ) (FlowDiagram, error) {
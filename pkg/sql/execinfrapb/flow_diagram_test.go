@@ -403,3 +403,66 @@ func TestPlanDiagramJoin(t *testing.T) {
 
 	compareDiagrams(t, s, expected)
 }
+
+func TestPlanDiagramAddProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := &sqlbase.TableDescriptor{Name: "Table"}
+	flows := map[roachpb.NodeID]*FlowSpec{
+		1: {
+			Processors: []ProcessorSpec{
+				{
+					Core: ProcessorCoreUnion{TableReader: &TableReaderSpec{Table: *desc}},
+					Output: []OutputRouterSpec{{
+						Type:    OutputRouterSpec_PASS_THROUGH,
+						Streams: []StreamEndpointSpec{{StreamID: 0}},
+					}},
+					ProcessorID: 0,
+				},
+				{
+					Input: []InputSyncSpec{{
+						Type:    InputSyncSpec_UNORDERED,
+						Streams: []StreamEndpointSpec{{StreamID: 0}},
+					}},
+					Core: ProcessorCoreUnion{Noop: &NoopCoreSpec{}},
+					Output: []OutputRouterSpec{{
+						Type:    OutputRouterSpec_PASS_THROUGH,
+						Streams: []StreamEndpointSpec{{Type: StreamEndpointSpec_SYNC_RESPONSE}},
+					}},
+					ProcessorID: 1,
+				},
+			},
+		},
+	}
+
+	d, err := GeneratePlanDiagram("SOME SQL HERE", flows, false /* showInputTypes */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the processors that reported progress are annotated, and later
+	// counts replace the earlier ones.
+	d.AddProgress(map[int32]int64{0: 1000})
+	d.AddProgress(map[int32]int64{0: 1234})
+	json, _, err := d.ToURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+		{
+			"sql":"SOME SQL HERE",
+			"nodeNames":["1"],
+			"processors":[
+				{"nodeIdx":0,"inputs":[],"core":{"title":"TableReader/0","details":["primary@Table","rows produced: 1234"]},"outputs":[],"stage":0},
+				{"nodeIdx":0,"inputs":[],"core":{"title":"No-op/1","details":[]},"outputs":[],"stage":0},
+				{"nodeIdx":0,"inputs":[],"core":{"title":"Response","details":[]},"outputs":[],"stage":0}
+			],
+			"edges":[
+				{"sourceProc":0,"sourceOutput":0,"destProc":1,"destInput":0},
+				{"sourceProc":1,"sourceOutput":0,"destProc":2,"destInput":0}
+			]
+		}
+	`
+
+	compareDiagrams(t, json, expected)
+}
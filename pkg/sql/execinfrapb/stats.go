@@ -18,6 +18,13 @@ const StreamIDTagKey = tracing.TagPrefix + "streamid"
 // ProcessorIDTagKey is the key used for processor id tags in tracing spans.
 const ProcessorIDTagKey = tracing.TagPrefix + "processorid"
 
+// FlowIDTagKey is the key used for flow id tags in tracing spans.
+const FlowIDTagKey = tracing.TagPrefix + "flowid"
+
+// NodeIDTagKey is the key used for the tags of the node on which a flow runs
+// in tracing spans.
+const NodeIDTagKey = tracing.TagPrefix + "nodeid"
+
 // DistSQLSpanStats is a tracing.SpanStats that returns a list of stats to
 // output on a query plan.
 type DistSQLSpanStats interface {
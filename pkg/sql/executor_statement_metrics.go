@@ -86,8 +86,26 @@ type EngineMetrics struct {
 	// retry protocol is not in use.
 	TxnAbortCount *metric.Counter
 
+	// TxnDeadlineExtendedCount counts transactions whose deadline was extended
+	// by renewing the table leases they hold.
+	TxnDeadlineExtendedCount *metric.Counter
+	// TxnDeadlineExceededCount counts commits that failed because the
+	// transaction exceeded its deadline.
+	TxnDeadlineExceededCount *metric.Counter
+
 	// FailureCount counts non-retriable errors in open transactions.
 	FailureCount *metric.Counter
+
+	// PreparedStmtsCount is the number of prepared statements that haven't
+	// been released yet.
+	PreparedStmtsCount *metric.Gauge
+	// PreparedStmtsBytes is the memory used by the prepared statements and
+	// portals of all sessions.
+	PreparedStmtsBytes *metric.Gauge
+	// PreparedStmtsEvictedCount counts the unnamed prepared statements and
+	// portals evicted to keep sessions within their prepared statement memory
+	// limit.
+	PreparedStmtsEvictedCount *metric.Counter
 }
 
 // EngineMetrics implements the metric.Struct interface
@@ -167,12 +185,16 @@ func (ex *connExecutor) recordStatementSummary(
 		m.SQLServiceLatency.RecordValue(svcLatRaw.Nanoseconds())
 	}
 
-	ex.statsCollector.recordStatement(
+	if r := ex.statsCollector.recordStatement(
 		stmt, planner.curPlan.savedPlanForStats,
 		flags.IsSet(planFlagDistributed), flags.IsSet(planFlagOptUsed), flags.IsSet(planFlagImplicitTxn),
 		automaticRetryCount, rowsAffected, err,
 		parseLat, planLat, runLat, svcLat, execOverhead, bytesRead, rowsRead,
-	)
+	); r != nil {
+		r.appName = ex.applicationName.Load().(string)
+		ex.server.reportPlanRegression(ctx, *r)
+	}
+	ex.recordStatementFingerprint(stmt, flags, err)
 
 	if log.V(2) {
 		// ages since significant epochs
@@ -239,7 +239,9 @@ func (p *planner) populateExplain(
 				if nodeID == thisNodeID && !isDistSQL {
 					fuseOpt = flowinfra.FuseAggressively
 				}
-				_, err := colflow.SupportsVectorized(params.ctx, flowCtx, flow.Processors, fuseOpt)
+				_, err := colflow.SupportsVectorized(
+					params.ctx, flowCtx, flow.Processors, fuseOpt, physicalPlan.MaxEstimatedRowCount,
+				)
 				isVec = isVec && (err == nil)
 			}
 		}
@@ -96,7 +96,9 @@ func (n *explainVecNode) startExec(params runParams) error {
 		if flow.nodeID == thisNodeID && !willDistributePlan {
 			fuseOpt = flowinfra.FuseAggressively
 		}
-		opChains, err := colflow.SupportsVectorized(params.ctx, flowCtx, flow.flow.Processors, fuseOpt)
+		opChains, err := colflow.SupportsVectorized(
+			params.ctx, flowCtx, flow.flow.Processors, fuseOpt, plan.MaxEstimatedRowCount,
+		)
 		if err != nil {
 			return err
 		}
@@ -147,24 +149,46 @@ func shouldOutput(operator execinfra.OpNode, verbose bool) bool {
 	return !nonExplainable || verbose
 }
 
+// wrappedSuffix is appended to the names of the row-execution processors that
+// were wrapped into the vectorized flow because there is no native columnar
+// implementation of them (or of some of the expressions they evaluate).
+const wrappedSuffix = " (wrapped)"
+
 func formatOpChain(operator execinfra.OpNode, node treeprinter.Node, verbose bool) {
 	seenOps := make(map[reflect.Value]struct{})
 	if shouldOutput(operator, verbose) {
-		doFormatOpChain(operator, node.Child(reflect.TypeOf(operator).String()), verbose, seenOps)
+		doFormatOpChain(operator, node.Child(reflect.TypeOf(operator).String()), verbose, false /* wrapped */, seenOps)
 	} else {
-		doFormatOpChain(operator, node, verbose, seenOps)
+		doFormatOpChain(operator, node, verbose, false /* wrapped */, seenOps)
 	}
 }
+
+// doFormatOpChain adds the children of operator to node. wrapped indicates
+// whether operator is a wrapped row-execution processor: all of the operators
+// between a Columnarizer and the Materializers that feed into its input are.
 func doFormatOpChain(
 	operator execinfra.OpNode,
 	node treeprinter.Node,
 	verbose bool,
+	wrapped bool,
 	seenOps map[reflect.Value]struct{},
 ) {
 	for i := 0; i < operator.ChildCount(verbose); i++ {
 		child := operator.Child(i, verbose)
 		childOpValue := reflect.ValueOf(child)
 		childOpName := reflect.TypeOf(child).String()
+		childWrapped := wrapped
+		switch child.(type) {
+		case *colexec.Materializer:
+			childWrapped = false
+		default:
+			if _, ok := operator.(*colexec.Columnarizer); ok {
+				childWrapped = true
+			}
+		}
+		if childWrapped {
+			childOpName += wrappedSuffix
+		}
 		if _, seenOp := seenOps[childOpValue]; seenOp {
 			// We have already seen this operator, so in order to not repeat the full
 			// chain again, we will simply print out this operator's name and will
@@ -175,9 +199,9 @@ func doFormatOpChain(
 		}
 		seenOps[childOpValue] = struct{}{}
 		if shouldOutput(child, verbose) {
-			doFormatOpChain(child, node.Child(childOpName), verbose, seenOps)
+			doFormatOpChain(child, node.Child(childOpName), verbose, childWrapped, seenOps)
 		} else {
-			doFormatOpChain(child, node, verbose, seenOps)
+			doFormatOpChain(child, node, verbose, childWrapped, seenOps)
 		}
 	}
 }
@@ -0,0 +1,54 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package flight
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightDataRoundtrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	in := FlightData{
+		DataHeader: []byte("header"),
+		DataBody:   bytes.Repeat([]byte{1, 2, 3}, 100),
+	}
+	data, err := in.Marshal()
+	require.NoError(t, err)
+
+	var out FlightData
+	require.NoError(t, out.Unmarshal(data))
+	require.Equal(t, in, out)
+
+	// The encoding of the body, whose field number needs a two byte key, is the
+	// one of the reference implementations.
+	require.Equal(t, []byte{0xc2, 0x3e, 0xac, 0x02}, data[len(in.DataHeader)+2:][:4])
+}
+
+func TestTicketUnmarshal(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// A ticket preceded by an unknown varint field and followed by an unknown
+	// fixed32 field.
+	data := []byte{0x10, 0x96, 0x01, 0x0a, 0x08}
+	data = append(data, "SELECT 1"...)
+	data = append(data, 0x1d, 1, 2, 3, 4)
+
+	var ticket Ticket
+	require.NoError(t, ticket.Unmarshal(data))
+	require.Equal(t, "SELECT 1", string(ticket.Ticket))
+
+	require.Error(t, ticket.Unmarshal(data[:len(data)-1]))
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package flight implements an experimental Arrow Flight endpoint that streams
+// the results of SQL queries as arrow record batches, so that analytics
+// clients can ingest them without going through pgwire.
+package flight
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// enabled controls whether the Flight endpoint accepts requests.
+var enabled = settings.RegisterBoolSetting(
+	"sql.experimental_arrow_flight.enabled",
+	"set to true to serve the results of queries over the Arrow Flight protocol",
+	false,
+)
+
+// Server serves the DoGet method of the Flight service. The ticket of a DoGet
+// request is the text of the SELECT query whose results are streamed.
+//
+// The queries run as the root user against the defaultdb database, so the
+// tables of the other databases must be referred to by their qualified names.
+// The gRPC server only accepts connections authenticated with node or root
+// certificates.
+type Server struct {
+	execCfg *sql.ExecutorConfig
+}
+
+var _ FlightServiceServer = &Server{}
+
+// NewServer creates a Server. The ExecutorConfig doesn't need to be
+// initialized until the first request is served.
+func NewServer(execCfg *sql.ExecutorConfig) *Server {
+	return &Server{execCfg: execCfg}
+}
+
+// DoGet is part of the FlightServiceServer interface.
+func (s *Server) DoGet(ticket *Ticket, stream FlightService_DoGetServer) error {
+	if !enabled.Get(&s.execCfg.Settings.SV) {
+		return status.Error(codes.Unavailable, "the Arrow Flight endpoint is disabled; "+
+			"set the cluster setting sql.experimental_arrow_flight.enabled to enable it")
+	}
+	w := &streamWriter{stream: stream}
+	return sql.ExportArrowBatches(
+		stream.Context(), s.execCfg, security.RootUser, sessiondata.DefaultDatabaseName,
+		string(ticket.Ticket), w,
+	)
+}
+
+// streamWriter is a sql.ArrowBatchWriter that sends the batches to a DoGet
+// stream. The stream starts with the schema of the batches.
+type streamWriter struct {
+	stream FlightService_DoGetServer
+	s      *colserde.MessageSerializer
+}
+
+var _ sql.ArrowBatchWriter = &streamWriter{}
+
+// Init is part of the sql.ArrowBatchWriter interface.
+func (w *streamWriter) Init(cols sqlbase.ResultColumns, typs []coltypes.T) error {
+	names := make([]string, len(cols))
+	for i := range cols {
+		names[i] = cols[i].Name
+	}
+	var err error
+	if w.s, err = colserde.NewMessageSerializer(typs, names); err != nil {
+		return err
+	}
+	return w.stream.Send(&FlightData{DataHeader: w.s.SchemaMessage()})
+}
+
+// WriteBatch is part of the sql.ArrowBatchWriter interface.
+func (w *streamWriter) WriteBatch(batch coldata.Batch) error {
+	metadata, body, err := w.s.BatchMessage(batch)
+	if err != nil {
+		return err
+	}
+	return w.stream.Send(&FlightData{DataHeader: metadata, DataBody: body})
+}
@@ -419,7 +419,10 @@ func (f *FlowBase) Cleanup(ctx context.Context) {
 		panic("flow cleanup called twice")
 	}
 
-	// This closes the monitor opened in ServerImpl.setupFlow.
+	// This closes the monitors opened in ServerImpl.setupFlow.
+	if f.SpillMon != nil {
+		f.SpillMon.Stop(ctx)
+	}
 	f.EvalCtx.Stop(ctx)
 	for _, p := range f.processors {
 		if d, ok := p.(Releasable); ok {
@@ -436,7 +439,11 @@ func (f *FlowBase) Cleanup(ctx context.Context) {
 	}
 	f.status = FlowFinished
 	f.ctxCancel()
-	f.doneFn()
+	// Flows that were never started (e.g. the ones that timed out in the
+	// FlowScheduler's queue) don't have a doneFn.
+	if f.doneFn != nil {
+		f.doneFn()
+	}
 	sp.Finish()
 }
 
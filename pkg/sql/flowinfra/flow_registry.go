@@ -460,6 +460,7 @@ func (fr *FlowRegistry) ConnectInboundStream(
 				ConsumerScheduleDeadline: &deadline,
 				Version:                  execinfra.Version,
 				MinAcceptedVersion:       execinfra.MinAcceptedVersion,
+				SupportedCompressions:    execinfrapb.SupportedStreamCompressions,
 			},
 		}); err != nil {
 			// TODO(andrei): We failed to send a message to the producer; we'll return
@@ -501,9 +502,10 @@ func (fr *FlowRegistry) ConnectInboundStream(
 
 	if err := stream.Send(&execinfrapb.ConsumerSignal{
 		Handshake: &execinfrapb.ConsumerHandshake{
-			ConsumerScheduled:  true,
-			Version:            execinfra.Version,
-			MinAcceptedVersion: execinfra.MinAcceptedVersion,
+			ConsumerScheduled:     true,
+			Version:               execinfra.Version,
+			MinAcceptedVersion:    execinfra.MinAcceptedVersion,
+			SupportedCompressions: execinfrapb.SupportedStreamCompressions,
 		},
 	}); err != nil {
 		return nil, nil, nil, err
@@ -32,12 +32,23 @@ var settingMaxRunningFlows = settings.RegisterPublicIntSetting(
 	500,
 )
 
+// settingFlowQueueTimeout is the maximum time a flow waits in the scheduler's
+// queue. The default matches the default of sql.distsql.flow_stream_timeout:
+// the consumers of a flow that waited longer than that have most likely given
+// up on its streams already.
+var settingFlowQueueTimeout = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.distsql.flow_queue_timeout",
+	"maximum amount of time a flow can spend queued before it is dropped (0 disables the timeout)",
+	10*time.Second,
+)
+
 // FlowScheduler manages running flows and decides when to queue and when to
 // start flows. The main interface it presents is ScheduleFlows, which passes a
 // flow to be run.
 type FlowScheduler struct {
 	log.AmbientContext
 	stopper    *stop.Stopper
+	settings   *cluster.Settings
 	flowDoneCh chan Flow
 	metrics    *execinfra.DistSQLMetrics
 
@@ -68,6 +79,7 @@ func NewFlowScheduler(
 	fs := &FlowScheduler{
 		AmbientContext: ambient,
 		stopper:        stopper,
+		settings:       settings,
 		flowDoneCh:     make(chan Flow, flowDoneChanSize),
 		metrics:        metrics,
 	}
@@ -132,11 +144,50 @@ func (fs *FlowScheduler) ScheduleFlow(ctx context.Context, f Flow) error {
 		})
 }
 
+// nextQueueTimeoutLocked returns how long it will take for the flow at the
+// front of the queue to time out. ok is false if the queue is empty or the
+// timeout is disabled.
+func (fs *FlowScheduler) nextQueueTimeoutLocked() (_ time.Duration, ok bool) {
+	timeout := settingFlowQueueTimeout.Get(&fs.settings.SV)
+	frElem := fs.mu.queue.Front()
+	if timeout == 0 || frElem == nil {
+		return 0, false
+	}
+	return timeout - timeutil.Since(frElem.Value.(*flowWithCtx).enqueueTime), true
+}
+
+// dropTimedOutFlowsLocked removes the flows that have been queued for longer
+// than the queue timeout and cleans them up. The consumers of these flows
+// will get an error once they time out waiting for the flows' streams.
+func (fs *FlowScheduler) dropTimedOutFlowsLocked() {
+	timeout := settingFlowQueueTimeout.Get(&fs.settings.SV)
+	if timeout == 0 {
+		return
+	}
+	for frElem := fs.mu.queue.Front(); frElem != nil; frElem = fs.mu.queue.Front() {
+		n := frElem.Value.(*flowWithCtx)
+		wait := timeutil.Since(n.enqueueTime)
+		if wait < timeout {
+			// The queue is ordered by enqueue time, so the remaining flows
+			// haven't timed out either.
+			return
+		}
+		fs.mu.queue.Remove(frElem)
+		log.Warningf(n.ctx, "flow scheduler dropped flow %s after %s in queue", n.flow.GetID(), wait)
+		fs.metrics.FlowsQueued.Dec(1)
+		fs.metrics.FlowsQueueTimedOut.Inc(1)
+		fs.metrics.QueueWaitHist.RecordValue(int64(wait))
+		n.flow.Cleanup(n.ctx)
+	}
+}
+
 // Start launches the main loop of the scheduler.
 func (fs *FlowScheduler) Start() {
 	ctx := fs.AnnotateCtx(context.Background())
 	fs.stopper.RunWorker(ctx, func(context.Context) {
 		stopped := false
+		var timer timeutil.Timer
+		defer timer.Stop()
 		fs.mu.Lock()
 		defer fs.mu.Unlock()
 
@@ -145,6 +196,13 @@ func (fs *FlowScheduler) Start() {
 				// TODO(radu): somehow error out the flows that are still in the queue.
 				return
 			}
+			var timeoutCh <-chan time.Time
+			if !stopped {
+				if d, ok := fs.nextQueueTimeoutLocked(); ok {
+					timer.Reset(d)
+					timeoutCh = timer.C
+				}
+			}
 			fs.mu.Unlock()
 			select {
 			case <-fs.flowDoneCh:
@@ -170,6 +228,13 @@ func (fs *FlowScheduler) Start() {
 					}
 				}
 
+			case <-timeoutCh:
+				timer.Read = true
+				fs.mu.Lock()
+				if !stopped {
+					fs.dropTimedOutFlowsLocked()
+				}
+
 			case <-fs.stopper.ShouldStop():
 				fs.mu.Lock()
 				stopped = true
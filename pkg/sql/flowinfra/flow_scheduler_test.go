@@ -0,0 +1,118 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package flowinfra
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// schedulerTestFlow is a Flow that keeps running until its done channel is
+// closed.
+type schedulerTestFlow struct {
+	Flow
+	id      execinfrapb.FlowID
+	done    chan struct{}
+	started int32
+	cleaned int32
+}
+
+func newSchedulerTestFlow() *schedulerTestFlow {
+	return &schedulerTestFlow{
+		id:   execinfrapb.FlowID{UUID: uuid.MakeV4()},
+		done: make(chan struct{}),
+	}
+}
+
+func (f *schedulerTestFlow) GetID() execinfrapb.FlowID {
+	return f.id
+}
+
+func (f *schedulerTestFlow) Start(_ context.Context, doneFn func()) error {
+	atomic.StoreInt32(&f.started, 1)
+	go func() {
+		<-f.done
+		doneFn()
+	}()
+	return nil
+}
+
+func (f *schedulerTestFlow) Wait() {
+	<-f.done
+}
+
+func (f *schedulerTestFlow) Cleanup(context.Context) {
+	atomic.StoreInt32(&f.cleaned, 1)
+}
+
+// TestFlowSchedulerQueueTimeout verifies that flows that spend too long in the
+// queue of the FlowScheduler are dropped instead of being run.
+func TestFlowSchedulerQueueTimeout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	st := cluster.MakeTestingClusterSettings()
+	settingMaxRunningFlows.Override(&st.SV, 1)
+	settingFlowQueueTimeout.Override(&st.SV, time.Millisecond)
+	metrics := execinfra.MakeDistSQLMetrics(time.Hour /* histogramWindow */)
+	fs := NewFlowScheduler(log.AmbientContext{}, stopper, st, &metrics)
+	fs.Start()
+
+	running := newSchedulerTestFlow()
+	if err := fs.ScheduleFlow(ctx, running); err != nil {
+		t.Fatal(err)
+	}
+	queued := newSchedulerTestFlow()
+	if err := fs.ScheduleFlow(ctx, queued); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		if atomic.LoadInt32(&queued.cleaned) == 0 {
+			return errors.New("queued flow has not been dropped yet")
+		}
+		return nil
+	})
+	if n := metrics.FlowsQueueTimedOut.Count(); n != 1 {
+		t.Fatalf("expected 1 timed out flow, found %d", n)
+	}
+	if n := metrics.FlowsQueued.Value(); n != 0 {
+		t.Fatalf("expected no queued flows, found %d", n)
+	}
+
+	// Finishing the running flow must not start the dropped one.
+	close(running.done)
+	testutils.SucceedsSoon(t, func() error {
+		if n := metrics.FlowsActive.Value(); n != 0 {
+			return errors.Errorf("expected no active flows, found %d", n)
+		}
+		return nil
+	})
+	if atomic.LoadInt32(&queued.started) != 0 {
+		t.Fatal("dropped flow was started")
+	}
+	close(queued.done)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/pkg/errors"
 )
 
@@ -99,6 +100,7 @@ func processInboundStreamHelper(
 ) error {
 	draining := false
 	var sd StreamDecoder
+	signaler := &drainSignaler{stream: stream}
 
 	sendErrToConsumer := func(err error) {
 		if err != nil {
@@ -109,7 +111,7 @@ func processInboundStreamHelper(
 
 	if firstMsg != nil {
 		if res := processProducerMessage(
-			ctx, stream, dst, &sd, &draining, firstMsg,
+			ctx, signaler, dst, &sd, &draining, firstMsg,
 		); res.err != nil || res.consumerClosed {
 			sendErrToConsumer(res.err)
 			return res.err
@@ -148,7 +150,7 @@ func processInboundStreamHelper(
 			}
 
 			if res := processProducerMessage(
-				ctx, stream, dst, &sd, &draining, msg,
+				ctx, signaler, dst, &sd, &draining, msg,
 			); res.err != nil || res.consumerClosed {
 				sendErrToConsumer(res.err)
 				errChan <- res.err
@@ -157,25 +159,55 @@ func processInboundStreamHelper(
 		}
 	}()
 
+	// If the consumer can tell us when it no longer needs rows, ask the producer
+	// to drain right away rather than when the next message arrives: a remote
+	// producer might not send anything for a long time, e.g. if a LIMIT was
+	// satisfied by the rows of other streams.
+	var drainC <-chan struct{}
+	if n, ok := dst.(execinfra.DrainNotifier); ok {
+		drainC = n.DrainRequestedC()
+	}
+
 	// Check for context cancellation while reading from the stream on another
 	// goroutine.
-	select {
-	case <-f.GetCtxDone():
-		return sqlbase.QueryCanceledError
-	case err := <-errChan:
-		return err
+	for {
+		select {
+		case <-f.GetCtxDone():
+			return sqlbase.QueryCanceledError
+		case err := <-errChan:
+			return err
+		case <-drainC:
+			drainC = nil
+			if err := signaler.send(ctx); err != nil {
+				log.Errorf(ctx, "draining error: %s", err)
+			}
+		}
 	}
 }
 
-// sendDrainSignalToProducer is called when the consumer wants to signal the
-// producer that it doesn't need any more rows and the producer should drain. A
-// signal is sent on stream to the producer to ask it to send metadata.
-func sendDrainSignalToStreamProducer(
-	ctx context.Context, stream execinfrapb.DistSQL_FlowStreamServer,
-) error {
+// drainSignaler sends a signal to the producer of an inbound stream to tell it
+// that the consumer doesn't need any more rows and that the producer should
+// drain, i.e. only send its metadata. The signal is sent at most once, either
+// by the goroutine that reads the stream when the consumer returns
+// DrainRequested to a row, or by the goroutine that watches the consumer.
+type drainSignaler struct {
+	stream execinfrapb.DistSQL_FlowStreamServer
+	mu     struct {
+		syncutil.Mutex
+		sent bool
+	}
+}
+
+func (s *drainSignaler) send(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.sent {
+		return nil
+	}
+	s.mu.sent = true
 	log.VEvent(ctx, 1, "sending drain signal to producer")
 	sig := execinfrapb.ConsumerSignal{DrainRequest: &execinfrapb.DrainRequest{}}
-	return stream.Send(&sig)
+	return s.stream.Send(&sig)
 }
 
 // processProducerMessage is a helper function to process data from the producer
@@ -184,7 +216,7 @@ func sendDrainSignalToStreamProducer(
 // closed), the caller must return the error to the producer.
 func processProducerMessage(
 	ctx context.Context,
-	stream execinfrapb.DistSQL_FlowStreamServer,
+	signaler *drainSignaler,
 	dst execinfra.RowReceiver,
 	sd *StreamDecoder,
 	draining *bool,
@@ -229,7 +261,7 @@ func processProducerMessage(
 			// close the consuming side of the stream and call dst.ProducerDone().
 			if !*draining {
 				*draining = true
-				if err := sendDrainSignalToStreamProducer(ctx, stream); err != nil {
+				if err := signaler.send(ctx); err != nil {
 					log.Errorf(ctx, "draining error: %s", err)
 				}
 			}
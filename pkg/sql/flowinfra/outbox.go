@@ -185,6 +185,24 @@ func (m *Outbox) flush(ctx context.Context) error {
 	return nil
 }
 
+// sendProgress sends the row counts of the processors of the flow that changed
+// since they were last reported, so that the gateway can display the progress
+// of the query.
+func (m *Outbox) sendProgress(ctx context.Context) error {
+	if m.flowCtx.Progress == nil {
+		return nil
+	}
+	updates := m.flowCtx.Progress.Updates()
+	if len(updates) == 0 {
+		return nil
+	}
+	for i := range updates {
+		m.encoder.AddMetadata(ctx, execinfrapb.ProducerMetadata{RowsProduced: &updates[i]})
+		m.numRows++
+	}
+	return m.flush(ctx)
+}
+
 // mainLoop reads from m.RowChannel and writes to the output stream through
 // addRow()/flush() until the producer doesn't have any more data to send or an
 // error happened.
@@ -250,6 +268,16 @@ func (m *Outbox) mainLoop(ctx context.Context) error {
 	var flushTimer timeutil.Timer
 	defer flushTimer.Stop()
 
+	var progressC <-chan time.Time
+	if m.flowCtx.Progress != nil {
+		interval := execinfra.SettingProgressReportInterval.Get(&m.flowCtx.Cfg.Settings.SV)
+		if interval > 0 {
+			progressTicker := time.NewTicker(interval)
+			defer progressTicker.Stop()
+			progressC = progressTicker.C
+		}
+	}
+
 	draining := false
 
 	// TODO(andrei): It's unfortunate that we're spawning a goroutine for every
@@ -297,6 +325,9 @@ func (m *Outbox) mainLoop(ctx context.Context) error {
 						}
 					}
 				}
+				if err := m.sendProgress(ctx); err != nil {
+					return err
+				}
 				return m.flush(ctx)
 			}
 			if !draining || msg.Meta != nil {
@@ -322,6 +353,10 @@ func (m *Outbox) mainLoop(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+		case <-progressC:
+			if err := m.sendProgress(ctx); err != nil {
+				return err
+			}
 		case drainSignal := <-drainCh:
 			if drainSignal.err != nil {
 				// Stop work from proceeding in this flow. This also causes FlowStream
@@ -79,17 +79,23 @@ func (sd *StreamDecoder) AddMessage(ctx context.Context, msg *execinfrapb.Produc
 			return errors.Errorf("received data before header and/or typing info")
 		}
 
+		// Compressed data is decompressed into a new buffer, so it is never
+		// aliased with the memory of the protobuf.
+		rawBytes, err := execinfrapb.DecompressRawBytes(nil /* dst */, &msg.Data)
+		if err != nil {
+			return err
+		}
 		if len(sd.data) == 0 {
 			// We limit the capacity of the slice (using "three-index slices") out of
 			// paranoia: if the slice is going to need to grow later, we don't want to
 			// clobber any memory outside what the protobuf allocated for us
 			// initially (in case this memory might be coming from some buffer).
-			sd.data = msg.Data.RawBytes[:len(msg.Data.RawBytes):len(msg.Data.RawBytes)]
+			sd.data = rawBytes[:len(rawBytes):len(rawBytes)]
 		} else {
 			// This can only happen if we don't retrieve all the rows before
 			// adding another message, which shouldn't be the normal case.
 			// TODO(radu): maybe don't support this case at all?
-			sd.data = append(sd.data, msg.Data.RawBytes...)
+			sd.data = append(sd.data, rawBytes...)
 		}
 	}
 	if msg.Data.NumEmptyRows > 0 {
@@ -173,12 +173,6 @@ func (r *insertRun) processSourceRow(params runParams, rowVals tree.Datums) erro
 	return nil
 }
 
-// maxInsertBatchSize is the max number of entries in the KV batch for
-// the insert operation (including secondary index updates, FK
-// cascading updates, etc), before the current KV batch is executed
-// and a new batch is started.
-const maxInsertBatchSize = 10000
-
 func (n *insertNode) startExec(params runParams) error {
 	if err := params.p.maybeSetSystemConfig(n.run.ti.tableDesc().GetID()); err != nil {
 		return err
@@ -241,7 +235,7 @@ func (n *insertNode) BatchedNext(params runParams) (bool, error) {
 		n.run.rowCount++
 
 		// Are we done yet with the current batch?
-		if n.run.ti.curBatchSize() >= maxInsertBatchSize {
+		if n.run.ti.batchFull() {
 			break
 		}
 	}
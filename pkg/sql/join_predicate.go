@@ -59,6 +59,10 @@ type joinPredicate struct {
 	// hint for optimizing execution.
 	rightEqKey bool
 
+	// If set, this is an anti join on a single pair of equality columns with
+	// the semantics of NOT IN (see HashJoinerSpec.RejectOnNull).
+	rejectOnNull bool
+
 	// This struct must be allocated on the heap and its location stay
 	// stable after construction because it implements
 	// IndexedVarContainer and the IndexedVar objects in sub-expressions
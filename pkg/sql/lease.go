@@ -1669,6 +1669,52 @@ func (m *LeaseManager) Release(desc *sqlbase.ImmutableTableDescriptor) error {
 	return nil
 }
 
+// ExtendLease returns the expiration of the lease that the node holds on the
+// version of a table that was previously acquired and has not been released
+// yet. If that version is the latest one and its lease expires within the
+// lease renewal timeout of the timestamp, the lease is renewed first, which
+// moves the expiration forward. The expiration of an older version is never
+// moved since a newer version might have been published after it.
+//
+// A transaction that uses the table version can use the returned expiration
+// as its deadline, even if it is later than the expiration the table was
+// acquired with.
+func (m *LeaseManager) ExtendLease(
+	ctx context.Context, timestamp hlc.Timestamp, desc *sqlbase.ImmutableTableDescriptor,
+) (hlc.Timestamp, error) {
+	t := m.findTableState(desc.ID, false /* create */)
+	if t == nil {
+		return hlc.Timestamp{}, errors.Errorf("table %d not found", desc.ID)
+	}
+	expiration, latest, err := t.expirationForVersion(desc.Version)
+	if err != nil || !latest {
+		return expiration, err
+	}
+	durationUntilExpiry := time.Duration(expiration.WallTime - timestamp.WallTime)
+	if durationUntilExpiry >= m.LeaseStore.leaseRenewalTimeout {
+		return expiration, nil
+	}
+	if _, err := acquireNodeLease(ctx, m, desc.ID); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	expiration, _, err = t.expirationForVersion(desc.Version)
+	return expiration, err
+}
+
+// expirationForVersion returns the expiration of the given version of the
+// table, and whether it is the latest version known to the node.
+func (t *tableState) expirationForVersion(
+	version sqlbase.DescriptorVersion,
+) (hlc.Timestamp, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.mu.active.find(version)
+	if s == nil {
+		return hlc.Timestamp{}, false, errors.Errorf("version %d of table %d not found", version, t.id)
+	}
+	return s.expiration, s == t.mu.active.findNewest(), nil
+}
+
 // removeOnceDereferenced returns true if the LeaseManager thinks
 // a tableVersionState can be removed after its refcount goes to 0.
 func (m *LeaseManager) removeOnceDereferenced() bool {
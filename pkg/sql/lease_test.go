@@ -1947,3 +1947,68 @@ CREATE TABLE t.after (k CHAR PRIMARY KEY, v CHAR);
 	t.expectLeases(beforeDesc.ID, "")
 	t.expectLeases(afterDesc.ID, "/1/1")
 }
+
+// Test that a transaction that runs longer than the table lease duration can
+// commit even if its timestamp is pushed past the expiration of the leases it
+// acquired, because the leases are renewed and its deadline is extended.
+func TestTxnDeadlineExtension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	params, _ := tests.CreateTestServerParams()
+	params.LeaseManagerConfig = base.NewLeaseManagerConfig()
+	params.LeaseManagerConfig.TableDescriptorLeaseDuration = 500 * time.Millisecond
+	params.LeaseManagerConfig.TableDescriptorLeaseJitterFraction = 0.0
+	params.LeaseManagerConfig.TableDescriptorLeaseRenewalTimeout = 500 * time.Millisecond
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.kv (k CHAR PRIMARY KEY, v CHAR);
+INSERT INTO t.kv VALUES ('a', 'b');
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// runLongTxn runs a transaction whose timestamp is pushed past the
+	// expiration of the lease it acquires on t.kv, and returns the error with
+	// which its commit fails.
+	runLongTxn := func() error {
+		txn, err := sqlDB.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := txn.Exec(`SELECT * FROM t.kv`); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * params.LeaseManagerConfig.TableDescriptorLeaseDuration)
+		// A read of the key by another transaction pushes the timestamp of the
+		// write below past the deadline.
+		if _, err := sqlDB.Exec(`SELECT * FROM t.kv WHERE k = 'a'`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := txn.Exec(`UPDATE t.kv SET v = 'c' WHERE k = 'a'`); err != nil {
+			t.Fatal(err)
+		}
+		return txn.Commit()
+	}
+
+	extended := s.MustGetSQLCounter(sql.MetaTxnDeadlineExtended.Name)
+	exceeded := s.MustGetSQLCounter(sql.MetaTxnDeadlineExceeded.Name)
+	if err := runLongTxn(); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.MustGetSQLCounter(sql.MetaTxnDeadlineExtended.Name); n != extended+1 {
+		t.Fatalf("expected %d extended deadlines, got %d", extended+1, n)
+	}
+
+	// Without the extension, the commit fails.
+	if _, err := sqlDB.Exec(`SET CLUSTER SETTING sql.txn.deadline_extension.enabled = false`); err != nil {
+		t.Fatal(err)
+	}
+	if err := runLongTxn(); !testutils.IsError(err, "RETRY_COMMIT_DEADLINE_EXCEEDED") {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if n := s.MustGetSQLCounter(sql.MetaTxnDeadlineExceeded.Name); n != exceeded+1 {
+		t.Fatalf("expected %d exceeded deadlines, got %d", exceeded+1, n)
+	}
+}
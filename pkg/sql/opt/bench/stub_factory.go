@@ -37,6 +37,8 @@ func (f *stubFactory) ConstructScan(
 	index cat.Index,
 	needed exec.ColumnOrdinalSet,
 	indexConstraint *constraint.Constraint,
+	skipScanPrefixLen int,
+	skipScanGroupLimit int64,
 	hardLimit int64,
 	softLimit int64,
 	reverse bool,
@@ -74,6 +76,7 @@ func (f *stubFactory) ConstructHashJoin(
 	left, right exec.Node,
 	leftEqCols, rightEqCols []exec.ColumnOrdinal,
 	leftEqColsAreKey, rightEqColsAreKey bool,
+	rejectOnNull bool,
 	extraOnCond tree.TypedExpr,
 ) (exec.Node, error) {
 	return struct{}{}, nil
@@ -126,7 +129,7 @@ func (f *stubFactory) ConstructDistinct(
 }
 
 func (f *stubFactory) ConstructSetOp(
-	typ tree.UnionType, all bool, left, right exec.Node,
+	typ tree.UnionType, all bool, left, right exec.Node, hardLimit uint64,
 ) (exec.Node, error) {
 	return struct{}{}, nil
 }
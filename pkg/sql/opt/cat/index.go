@@ -165,6 +165,31 @@ type Index interface {
 	//   [ /us/seattle\x00 -               ]
 	//
 	PartitionByListPrefixes() []tree.Datums
+
+	// PartitionCount returns the number of PARTITION BY LIST partitions defined
+	// on this index. Subpartitions are not included.
+	PartitionCount() int
+
+	// Partition returns the ith PARTITION BY LIST partition within the index
+	// definition, where i < PartitionCount.
+	Partition(i int) Partition
+}
+
+// Partition is an interface to a PARTITION BY LIST partition of an index. It is
+// used to plan scans that only touch the parts of an index that are placed in
+// a given locality (see locality optimized search).
+type Partition interface {
+	// Name is the name of this partition.
+	Name() string
+
+	// Zone returns the zone which constrains placement of the partition's range
+	// replicas. If the partition was not explicitly assigned to a zone, then it
+	// inherits the zone of its owning index.
+	Zone() Zone
+
+	// PartitionByListPrefixes returns the values of this partition, in the same
+	// form as Index.PartitionByListPrefixes. DEFAULT values are not included.
+	PartitionByListPrefixes() []tree.Datums
 }
 
 // IndexColumn describes a single column that is part of an index definition.
@@ -466,8 +466,9 @@ func (b *Builder) canUseDeleteRange(del *memo.DeleteExpr) bool {
 	}
 
 	// Check for simple Scan input operator without a limit; anything else is not
-	// supported by a range delete.
-	if scan, ok := del.Input.(*memo.ScanExpr); !ok || scan.HardLimit != 0 {
+	// supported by a range delete. Skip scans are not supported either, since
+	// their constraint doesn't describe contiguous spans of the index.
+	if scan, ok := del.Input.(*memo.ScanExpr); !ok || scan.HardLimit != 0 || scan.SkipScanPrefixLen != 0 {
 		return false
 	}
 
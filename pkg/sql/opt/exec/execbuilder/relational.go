@@ -494,6 +494,8 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		tab.Index(scan.Index),
 		needed,
 		scan.Constraint,
+		scan.SkipScanPrefixLen,
+		scan.GroupLimit.RowCount(),
 		hardLimit,
 		softLimit,
 		// HardLimit.Reverse() is taken into account by ScanIsReverse.
@@ -756,10 +758,27 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (execPlan, error) {
 		*filters,
 	)
 
+	remainingFilters := memo.ExtractRemainingJoinFilters(*filters, leftEq, rightEq)
+
+	// An anti join with the single filter (left = right) IS NOT false comes
+	// from NOT IN. It can be executed as an equality join that rejects on NULL.
+	rejectOnNull := false
+	if join.Op() == opt.AntiJoinOp && len(leftEq) == 0 {
+		if ok, l, r := memo.ExtractNullRejectingAntiJoinEquality(
+			leftExpr.Relational().OutputCols,
+			rightExpr.Relational().OutputCols,
+			*filters,
+		); ok {
+			leftEq, rightEq = opt.ColList{l}, opt.ColList{r}
+			remainingFilters = nil
+			rejectOnNull = true
+		}
+	}
+
 	left, right, onExpr, outputCols, err := b.initJoinBuild(
 		leftExpr,
 		rightExpr,
-		memo.ExtractRemainingJoinFilters(*filters, leftEq, rightEq),
+		remainingFilters,
 		joinType,
 	)
 	if err != nil {
@@ -784,6 +803,7 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (execPlan, error) {
 		left.root, right.root,
 		leftEqOrdinals, rightEqOrdinals,
 		leftEqColsAreKey, rightEqColsAreKey,
+		rejectOnNull,
 		onExpr,
 	)
 	if err != nil {
@@ -1127,11 +1147,17 @@ func (b *Builder) buildSetOp(set memo.RelExpr) (execPlan, error) {
 
 	var typ tree.UnionType
 	var all bool
+	var hardLimit uint64
 	switch set.Op() {
 	case opt.UnionOp:
 		typ, all = tree.UnionOp, false
 	case opt.UnionAllOp:
 		typ, all = tree.UnionOp, true
+	case opt.LocalityOptimizedSearchOp:
+		// The search stops as soon as it has found as many rows as the expression
+		// can produce.
+		typ, all = tree.UnionOp, true
+		hardLimit = uint64(set.Relational().Cardinality.Max)
 	case opt.IntersectOp:
 		typ, all = tree.IntersectOp, false
 	case opt.IntersectAllOp:
@@ -1144,7 +1170,7 @@ func (b *Builder) buildSetOp(set memo.RelExpr) (execPlan, error) {
 		panic(errors.AssertionFailedf("invalid operator %s", log.Safe(set.Op())))
 	}
 
-	node, err := b.factory.ConstructSetOp(typ, all, left.root, right.root)
+	node, err := b.factory.ConstructSetOp(typ, all, left.root, right.root, hardLimit)
 	if err != nil {
 		return execPlan{}, err
 	}
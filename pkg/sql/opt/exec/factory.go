@@ -50,6 +50,13 @@ type Factory interface {
 	//   - Only the given set of needed columns are part of the result.
 	//   - If indexConstraint is not nil, the scan is restricted to the spans in
 	//     in the constraint.
+	//   - If skipScanPrefixLen > 0, the scan is a skip scan: indexConstraint
+	//     applies to the index columns following the first skipScanPrefixLen
+	//     columns, and the scan seeks to the constrained spans under each
+	//     distinct value of the prefix. indexConstraint can be nil, in which
+	//     case all of the rows under each prefix are scanned.
+	//   - If skipScanGroupLimit > 0, the skip scan only returns up to
+	//     skipScanGroupLimit rows under each distinct value of the prefix.
 	//   - If hardLimit > 0, then only up to hardLimit rows can be returned from
 	//     the scan. If hardLimit > 0, softLimit must be 0.
 	//   - If softLimit > 0, then the scan may be required to return up to all
@@ -63,6 +70,8 @@ type Factory interface {
 		index cat.Index,
 		needed ColumnOrdinalSet,
 		indexConstraint *constraint.Constraint,
+		skipScanPrefixLen int,
+		skipScanGroupLimit int64,
 		hardLimit int64,
 		softLimit int64,
 		reverse bool,
@@ -137,6 +146,12 @@ type Factory interface {
 	// The leftEqColsAreKey/rightEqColsAreKey flags, if set, indicate that the
 	// equality columns form a key in the left/right input.
 	//
+	// The rejectOnNull flag, if set, indicates that the join is an anti join on
+	// a single pair of equality columns with the semantics of NOT IN: no rows
+	// are returned if the right input has a NULL in its equality column, and a
+	// left row with a NULL in its equality column is only returned if the right
+	// input is empty.
+	//
 	// The extraOnCond expression can refer to columns from both inputs using
 	// IndexedVars (first the left columns, then the right columns).
 	ConstructHashJoin(
@@ -144,6 +159,7 @@ type Factory interface {
 		left, right Node,
 		leftEqCols, rightEqCols []ColumnOrdinal,
 		leftEqColsAreKey, rightEqColsAreKey bool,
+		rejectOnNull bool,
 		extraOnCond tree.TypedExpr,
 	) (Node, error)
 
@@ -192,7 +208,14 @@ type Factory interface {
 	// ConstructSetOp returns a node that performs a UNION / INTERSECT / EXCEPT
 	// operation (either the ALL or the DISTINCT version). The left and right
 	// nodes must have the same number of columns.
-	ConstructSetOp(typ tree.UnionType, all bool, left, right Node) (Node, error)
+	//
+	// If hardLimit is non-zero, the operation must be UNION ALL and it is known
+	// to produce at most hardLimit rows. The left input is then executed first,
+	// and the right input is only executed if the left input produced fewer than
+	// hardLimit rows.
+	ConstructSetOp(
+		typ tree.UnionType, all bool, left, right Node, hardLimit uint64,
+	) (Node, error)
 
 	// ConstructSort returns a node that performs a resorting of the rows produced
 	// by the input node.
@@ -458,7 +458,9 @@ type WindowFrame struct {
 func (s *ScanPrivate) IsCanonical() bool {
 	return s.Index == cat.PrimaryIndex &&
 		s.Constraint == nil &&
-		s.HardLimit == 0
+		s.HardLimit == 0 &&
+		s.SkipScanPrefixLen == 0 &&
+		s.GroupLimit == 0
 }
 
 // NeedResults returns true if the mutation operator can return the rows that
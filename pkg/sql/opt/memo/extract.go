@@ -238,6 +238,24 @@ func isJoinEquality(
 	return false, 0, 0
 }
 
+// ExtractNullRejectingAntiJoinEquality returns the pair of columns (one from
+// the left side, one from the right side) of an anti join whose ON condition
+// is the single filter (left = right) IS NOT false, which is how NOT IN is
+// normalized. Such a join can be executed as an equality join that rejects on
+// NULL instead of evaluating the filter on the cross product of its inputs.
+func ExtractNullRejectingAntiJoinEquality(
+	leftCols, rightCols opt.ColSet, on FiltersExpr,
+) (ok bool, left, right opt.ColumnID) {
+	if len(on) != 1 {
+		return false, 0, 0
+	}
+	isNot, ok := on[0].Condition.(*IsNotExpr)
+	if !ok || isNot.Right.Op() != opt.FalseOp {
+		return false, 0, 0
+	}
+	return isJoinEquality(leftCols, rightCols, isNot.Left)
+}
+
 // ExtractRemainingJoinFilters calculates the remaining ON condition after
 // removing equalities that are handled separately. The given function
 // determines if an equality is redundant. The result is empty if there are no
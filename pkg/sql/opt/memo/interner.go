@@ -499,6 +499,11 @@ func (h *hasher) HashShowTraceType(val tree.ShowTraceType) {
 	h.HashString(string(val))
 }
 
+func (h *hasher) HashMaterializeClause(val tree.MaterializeClause) {
+	h.HashBool(val.Set)
+	h.HashBool(val.Materialize)
+}
+
 func (h *hasher) HashJobCommand(val tree.JobCommand) {
 	h.HashInt(int(val))
 }
@@ -824,6 +829,10 @@ func (h *hasher) IsShowTraceTypeEqual(l, r tree.ShowTraceType) bool {
 	return l == r
 }
 
+func (h *hasher) IsMaterializeClauseEqual(l, r tree.MaterializeClause) bool {
+	return l == r
+}
+
 func (h *hasher) IsJobCommandEqual(l, r tree.JobCommand) bool {
 	return l == r
 }
@@ -382,6 +382,17 @@ func TestInterner(t *testing.T) {
 			{val1: tree.ShowTraceKV, val2: tree.ShowTraceRaw, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashMaterializeClause, eqFn: in.hasher.IsMaterializeClauseEqual, variations: []testVariation{
+			{val1: tree.MaterializeClause{}, val2: tree.MaterializeClause{}, equal: true},
+			{val1: tree.MaterializeClause{Set: true}, val2: tree.MaterializeClause{Set: true}, equal: true},
+			{val1: tree.MaterializeClause{Set: true}, val2: tree.MaterializeClause{}, equal: false},
+			{
+				val1:  tree.MaterializeClause{Set: true, Materialize: true},
+				val2:  tree.MaterializeClause{Set: true},
+				equal: false,
+			},
+		}},
+
 		{hashFn: in.hasher.HashWindowFrame, eqFn: in.hasher.IsWindowFrameEqual, variations: []testVariation{
 			{
 				val1:  WindowFrame{tree.RANGE, tree.UnboundedPreceding, tree.CurrentRow, tree.NoExclusion},
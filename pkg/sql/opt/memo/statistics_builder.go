@@ -359,7 +359,7 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 		return sb.colStatIndexJoin(colSet, e.(*IndexJoinExpr))
 
 	case opt.UnionOp, opt.IntersectOp, opt.ExceptOp,
-		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp:
+		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp, opt.LocalityOptimizedSearchOp:
 		return sb.colStatSetNode(colSet, e)
 
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp:
@@ -595,6 +595,22 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 		s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, relProps, constrainedCols))
 	}
 
+	if scan.GroupLimit.IsSet() {
+		// Calculate row count of a partial scan per group
+		// -----------------------------------------------
+		// At most GroupLimit rows are returned for each distinct value of the
+		// skip scan prefix.
+		index := sb.md.Table(scan.Table).Index(scan.Index)
+		var prefixCols opt.ColSet
+		for i := 0; i < scan.SkipScanPrefixLen; i++ {
+			prefixCols.Add(scan.Table.ColumnID(index.Column(i).Ordinal))
+		}
+		distinctCount := sb.colStatTable(scan.Table, prefixCols).DistinctCount
+		if maxRows := distinctCount * float64(scan.GroupLimit.RowCount()); maxRows < s.RowCount {
+			s.ApplySelectivity(maxRows / s.RowCount)
+		}
+	}
+
 	sb.finalizeFromCardinality(relProps)
 }
 
@@ -1570,7 +1586,7 @@ func (sb *statisticsBuilder) buildSetNode(setNode RelExpr, relProps *props.Relat
 	// These calculations are an upper bound on the row count. It's likely that
 	// there is some overlap between the two sets, but not full overlap.
 	switch setNode.Op() {
-	case opt.UnionOp, opt.UnionAllOp:
+	case opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		s.RowCount = leftStats.RowCount + rightStats.RowCount
 
 	case opt.IntersectOp, opt.IntersectAllOp:
@@ -1629,7 +1645,7 @@ func (sb *statisticsBuilder) colStatSetNodeImpl(
 	// These calculations are an upper bound on the distinct count. It's likely
 	// that there is some overlap between the two sets, but not full overlap.
 	switch setNode.Op() {
-	case opt.UnionOp, opt.UnionAllOp:
+	case opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		colStat.DistinctCount = leftColStat.DistinctCount + rightColStat.DistinctCount
 		colStat.NullCount = leftNullCount + rightNullCount
 
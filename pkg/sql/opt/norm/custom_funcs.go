@@ -2006,12 +2006,17 @@ func (c *CustomFuncs) CanAddConstInts(first tree.Datum, second tree.Datum) bool
 // CanInlineWith returns whether or not it's valid to inline binding in expr.
 // This is the case when:
 // 1. binding has no side-effects (because once it's inlined, there's no
-//    guarantee it will be executed fully), and
-// 2. binding is referenced at most once in expr.
+//    guarantee it will be executed fully),
+// 2. binding was not declared AS MATERIALIZED, and
+// 3. binding is referenced at most once in expr, or it was declared AS NOT
+//    MATERIALIZED.
 func (c *CustomFuncs) CanInlineWith(binding, expr memo.RelExpr, private *memo.WithPrivate) bool {
 	if binding.Relational().CanHaveSideEffects {
 		return false
 	}
+	if private.Mtr.Set {
+		return !private.Mtr.Materialize
+	}
 	return c.WithUses(expr)[private.ID] <= 1
 }
 
@@ -2026,8 +2031,8 @@ func (c *CustomFuncs) InlineWith(binding, input memo.RelExpr, priv *memo.WithPri
 				// TODO(justin): it might be worth carefully walking the tree and
 				// renaming variables as we do this replacement so that this projection
 				// is unnecessary (assuming there's at most one reference to the
-				// WithScan, which might be false if we heuristically inline multiple
-				// times in the future).
+				// WithScan, which is false for bindings declared AS NOT MATERIALIZED
+				// that are referenced multiple times).
 				projections := make(memo.ProjectionsExpr, len(t.InCols))
 				for i := range t.InCols {
 					projections[i] = c.f.ConstructProjectionsItem(
@@ -33,7 +33,13 @@ func (b *Builder) constructDistinct(inScope *scope) memo.RelExpr {
 	// This will cause an error for queries like:
 	//   SELECT DISTINCT a FROM t ORDER BY b
 	// Note: this behavior is consistent with PostgreSQL.
-	for _, col := range inScope.ordering {
+	for i, col := range inScope.ordering {
+		if inScope.isNullsOrderingCol(i, private.GroupingCols) {
+			// The column is determined by a projected column, so grouping on it
+			// doesn't change the results.
+			private.GroupingCols.Add(col.ID())
+			continue
+		}
 		if !private.GroupingCols.Contains(col.ID()) {
 			panic(pgerror.Newf(
 				pgcode.InvalidColumnReference,
@@ -71,6 +77,15 @@ func (b *Builder) buildDistinctOn(distinctOnCols opt.ColSet, inScope *scope) (ou
 	//
 	// Note: this behavior is consistent with PostgreSQL.
 
+	// A column that orders the NULLs of a DISTINCT ON column is determined by
+	// it, so it is treated as a DISTINCT ON column as well.
+	for i := range inScope.ordering {
+		if inScope.isNullsOrderingCol(i, distinctOnCols) {
+			distinctOnCols = distinctOnCols.Copy()
+			distinctOnCols.Add(inScope.ordering[i].ID())
+		}
+	}
+
 	// Check that the DISTINCT ON expressions match the initial ORDER BY
 	// expressions.
 	var seen opt.ColSet
@@ -161,10 +161,60 @@ func (b *Builder) analyzeOrderByArg(
 	// Analyze the ORDER BY column(s).
 	start := len(orderByScope.cols)
 	b.analyzeExtraArgument(order.Expr, inScope, projectionsScope, orderByScope)
-	for i := start; i < len(orderByScope.cols); i++ {
-		col := &orderByScope.cols[i]
-		col.descending = order.Direction == tree.Descending
+	desc := order.Direction == tree.Descending
+	if !nullsOrderReversed(order) {
+		for i := start; i < len(orderByScope.cols); i++ {
+			orderByScope.cols[i].descending = desc
+		}
+		return
+	}
+
+	// NULLs sort before the other values by default. To place them on the
+	// other side, each column is preceded in the ordering by a column that
+	// orders on whether its value is NULL:
+	//   SELECT a FROM t ORDER BY a NULLS LAST
+	// is ordered as
+	//   SELECT a FROM t ORDER BY a IS NULL, a
+	cols := append([]scopeColumn(nil), orderByScope.cols[start:]...)
+	orderByScope.cols = orderByScope.cols[:start]
+	for i := range cols {
+		isNull, err := tree.TypeCheck(&tree.ComparisonExpr{
+			Operator: tree.IsNotDistinctFrom,
+			Left:     cols[i].getExpr(),
+			Right:    tree.DNull,
+		}, b.semaCtx, types.Bool)
+		if err != nil {
+			panic(err)
+		}
+		nullsCol := b.addColumn(orderByScope, "" /* alias */, isNull)
+		nullsCol.descending = desc
+		nullsCol.nullsOrdering = true
+
+		cols[i].descending = desc
+		orderByScope.cols = append(orderByScope.cols, cols[i])
+	}
+}
+
+// nullsOrderReversed returns true if the ORDER BY argument places the NULLs
+// after the other values in ascending order, or before them in descending
+// order.
+func nullsOrderReversed(order *tree.Order) bool {
+	if order.Direction == tree.Descending {
+		return order.NullsOrder == tree.NullsFirst
+	}
+	return order.NullsOrder == tree.NullsLast
+}
+
+// isNullsOrderingCol returns true if the i-th column of the scope's ordering
+// was added by analyzeOrderByArg to order the NULLs of the next ordering
+// column, and that column is in cols. Such a column is determined by the next
+// one.
+func (s *scope) isNullsOrderingCol(i int, cols opt.ColSet) bool {
+	if i+1 >= len(s.ordering) || !cols.Contains(s.ordering[i+1].ID()) {
+		return false
 	}
+	col := s.getColumn(s.ordering[i].ID())
+	return col != nil && col.nullsOrdering
 }
 
 // buildOrderByArg sets up the projection of a single ORDER BY argument.
@@ -112,6 +112,7 @@ type cteSource struct {
 	name         tree.AliasClause
 	cols         physical.Presentation
 	originalExpr tree.Statement
+	mtr          tree.MaterializeClause
 	bindingProps *props.Relational
 	expr         memo.RelExpr
 	// If set, this function is called when a CTE is referenced. It can throw an
@@ -45,6 +45,11 @@ type scopeColumn struct {
 	// This field is only used for ordering columns.
 	descending bool
 
+	// nullsOrdering is true if this column orders on whether the value of the
+	// next ordering column is NULL. This field is only used for ordering
+	// columns; see analyzeOrderByArg.
+	nullsOrdering bool
+
 	// scalar is the scalar expression associated with this column. If it is nil,
 	// then the column is a passthrough from an inner scope or a table column.
 	scalar opt.ScalarExpr
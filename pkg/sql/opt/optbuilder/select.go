@@ -641,6 +641,7 @@ func (b *Builder) buildCTEs(with *tree.With, inScope *scope) (outScope *scope) {
 			name:         cte.Name,
 			cols:         cteCols,
 			originalExpr: cte.Stmt,
+			mtr:          cte.Mtr,
 			expr:         cteExpr,
 			bindingProps: cteExpr.Relational(),
 			id:           id,
@@ -699,6 +700,7 @@ func (b *Builder) flushCTEs(expr memo.RelExpr) memo.RelExpr {
 				ID:           ctes[i].id,
 				Name:         string(ctes[i].name.Alias),
 				OriginalExpr: ctes[i].originalExpr,
+				Mtr:          ctes[i].mtr,
 			},
 		)
 	}
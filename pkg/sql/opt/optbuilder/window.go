@@ -96,6 +96,14 @@ func (b *Builder) buildWindow(outScope *scope, inScope *scope) {
 		partitions[i] = b.buildWindowPartition(def.Partitions, i, w.def.Name, inScope, argScope)
 
 		// Build appropriate orderings.
+		if def.Frame != nil && def.Frame.Mode == tree.RANGE && def.Frame.Bounds.HasOffset() {
+			for _, t := range def.OrderBy {
+				if nullsOrderReversed(t) {
+					panic(unimplementedWithIssueDetailf(6224, "",
+						"RANGE with offset PRECEDING/FOLLOWING is not supported with %s", t.NullsOrder))
+				}
+			}
+		}
 		ord := b.buildWindowOrdering(def.OrderBy, i, w.def.Name, inScope, argScope)
 		orderings[i].FromOrdering(ord)
 
@@ -398,6 +406,17 @@ func (b *Builder) buildWindowOrdering(
 		cols := flattenTuples([]tree.TypedExpr{te})
 
 		for _, e := range cols {
+			if nullsOrderReversed(t) {
+				// Order on whether the value is NULL first; see analyzeOrderByArg.
+				nullsCol := b.synthesizeColumn(
+					outScope,
+					fmt.Sprintf("%s_%d_orderby_%d_nulls", funcName, windowIndex+1, j+1),
+					types.Bool,
+					nil, /* expr */
+					b.factory.ConstructIs(b.buildScalar(e, inScope, nil, nil, nil), memo.NullSingleton),
+				)
+				ord = append(ord, opt.MakeOrderingColumn(nullsCol.id, t.Direction == tree.Descending))
+			}
 			col := outScope.findExistingCol(e)
 			if col == nil {
 				col = b.synthesizeColumn(
@@ -73,6 +73,12 @@ func ScanPrivateCanProvide(
 		if s.HardLimit.Reverse() {
 			direction = rev
 		}
+	} else if s.GroupLimit.IsSet() {
+		// Likewise, a limit per group of a skip scan forces a certain direction.
+		direction = fwd
+		if s.GroupLimit.Reverse() {
+			direction = rev
+		}
 	} else if s.Flags.Direction != 0 {
 		direction = fwd
 		if s.Flags.Direction == tree.Descending {
@@ -137,7 +137,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		if len(tab.Indexes) == 0 {
 			panic("cannot partition virtual table")
 		}
-		tab.Indexes[0].partitionBy = stmt.PartitionBy
+		tab.Indexes[0].setPartitionBy(stmt.PartitionBy)
 	}
 
 	// Add check constraints.
@@ -381,12 +381,11 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 
 func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 	idx := &Index{
-		IdxName:     tt.makeIndexName(def.Name, typ),
-		Unique:      typ != nonUniqueIndex,
-		Inverted:    def.Inverted,
-		IdxZone:     &zonepb.ZoneConfig{},
-		table:       tt,
-		partitionBy: def.PartitionBy,
+		IdxName:  tt.makeIndexName(def.Name, typ),
+		Unique:   typ != nonUniqueIndex,
+		Inverted: def.Inverted,
+		IdxZone:  &zonepb.ZoneConfig{},
+		table:    tt,
 	}
 
 	// Look for name suffixes indicating this is a mutation index.
@@ -411,6 +410,7 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 			notNullIndex = false
 		}
 	}
+	idx.setPartitionBy(def.PartitionBy)
 
 	if typ == primaryIndex {
 		var pkOrdinals util.FastIntSet
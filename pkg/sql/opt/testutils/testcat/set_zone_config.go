@@ -19,26 +19,40 @@ import (
 )
 
 // SetZoneConfig is a partial implementation of the ALTER TABLE ... CONFIGURE
-// ZONE USING statement.
+// ZONE USING statement. It also supports ALTER PARTITION ... CONFIGURE ZONE.
 func (tc *Catalog) SetZoneConfig(stmt *tree.SetZoneConfig) *zonepb.ZoneConfig {
 	// Update the table name to include catalog and schema if not provided.
 	tabName := stmt.TableOrIndex.Table
 	tc.qualifyTableName(&tabName)
 	tab := tc.Table(&tabName)
 
-	// Handle special case of primary index.
-	if stmt.TableOrIndex.Index == "" {
-		tab.Indexes[0].IdxZone = makeZoneConfig(stmt.Options)
-		return tab.Indexes[0].IdxZone
+	// The primary index is used if no index is specified.
+	idx := tab.Indexes[0]
+	if stmt.TableOrIndex.Index != "" {
+		idx = nil
+		for _, i := range tab.Indexes {
+			if i.IdxName == string(stmt.TableOrIndex.Index) {
+				idx = i
+				break
+			}
+		}
+		if idx == nil {
+			panic(fmt.Errorf("\"%q\" is not an index", stmt.TableOrIndex.Index))
+		}
 	}
 
-	for _, idx := range tab.Indexes {
-		if idx.IdxName == string(stmt.TableOrIndex.Index) {
-			idx.IdxZone = makeZoneConfig(stmt.Options)
-			return idx.IdxZone
+	if stmt.Partition != "" {
+		for i := range idx.partitions {
+			if idx.partitions[i].PartName == string(stmt.Partition) {
+				idx.partitions[i].PartZone = makeZoneConfig(stmt.Options)
+				return idx.partitions[i].PartZone
+			}
 		}
+		panic(fmt.Errorf("\"%q\" is not a partition of index %q", stmt.Partition, idx.IdxName))
 	}
-	panic(fmt.Errorf("\"%q\" is not an index", stmt.TableOrIndex.Index))
+
+	idx.IdxZone = makeZoneConfig(stmt.Options)
+	return idx.IdxZone
 }
 
 // makeZoneConfig constructs a ZoneConfig from options provided to the CONFIGURE
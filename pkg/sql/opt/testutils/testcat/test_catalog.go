@@ -733,9 +733,10 @@ type Index struct {
 	// table is a back reference to the table this index is on.
 	table *Table
 
-	// partitionBy is the partitioning clause that corresponds to this index. Used
-	// to implement PartitionByListPrefixes.
-	partitionBy *tree.PartitionBy
+	// partitions are the PARTITION BY LIST partitions of the index, in the order
+	// they are defined in the partitioning clause. Used to implement
+	// PartitionByListPrefixes.
+	partitions []Partition
 }
 
 // ID is part of the cat.Index interface.
@@ -800,60 +801,116 @@ func (ti *Index) Span() roachpb.Span {
 
 // PartitionByListPrefixes is part of the cat.Index interface.
 func (ti *Index) PartitionByListPrefixes() []tree.Datums {
-	p := ti.partitionBy
-	if p == nil {
-		return nil
+	var res []tree.Datums
+	for i := range ti.partitions {
+		res = append(res, ti.partitions[i].PartitionByListPrefixes()...)
 	}
-	if len(p.List) == 0 {
-		return nil
+	return res
+}
+
+// PartitionCount is part of the cat.Index interface.
+func (ti *Index) PartitionCount() int {
+	return len(ti.partitions)
+}
+
+// Partition is part of the cat.Index interface.
+func (ti *Index) Partition(i int) cat.Partition {
+	return &ti.partitions[i]
+}
+
+// setPartitionBy creates the partitions of the index from its partitioning
+// clause.
+func (ti *Index) setPartitionBy(p *tree.PartitionBy) {
+	ti.partitions = nil
+	if p == nil {
+		return
 	}
-	var res []tree.Datums
-	semaCtx := tree.MakeSemaContext()
-	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
 	for i := range p.Fields {
 		if i >= len(ti.Columns) || p.Fields[i] != ti.Columns[i].ColName() {
 			panic("partition by columns must be a prefix of the index columns")
 		}
 	}
 	for i := range p.List {
-		// Exprs contains a list of values.
-		for _, e := range p.List[i].Exprs {
-			var vals []tree.Expr
-			switch t := e.(type) {
-			case *tree.Tuple:
-				vals = t.Exprs
-			default:
-				vals = []tree.Expr{e}
-			}
+		ti.partitions = append(ti.partitions, Partition{
+			PartName: string(p.List[i].Name),
+			index:    ti,
+			list:     &p.List[i],
+		})
+	}
+}
+
+// Partition implements the cat.Partition interface for testing purposes.
+type Partition struct {
+	PartName string
 
-			// Cut off at DEFAULT, if present.
-			for i := range vals {
-				if _, ok := vals[i].(tree.DefaultVal); ok {
-					vals = vals[:i]
-				}
+	// PartZone is the zone associated with the partition. If it is nil, the
+	// partition inherits the zone of its index.
+	PartZone *zonepb.ZoneConfig
+
+	// index is a back reference to the index this partition is on.
+	index *Index
+
+	// list is the partition clause that corresponds to this partition.
+	list *tree.ListPartition
+}
+
+var _ cat.Partition = &Partition{}
+
+// Name is part of the cat.Partition interface.
+func (tp *Partition) Name() string {
+	return tp.PartName
+}
+
+// Zone is part of the cat.Partition interface.
+func (tp *Partition) Zone() cat.Zone {
+	if tp.PartZone == nil {
+		return tp.index.Zone()
+	}
+	return tp.PartZone
+}
+
+// PartitionByListPrefixes is part of the cat.Partition interface.
+func (tp *Partition) PartitionByListPrefixes() []tree.Datums {
+	var res []tree.Datums
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	// Exprs contains a list of values.
+	for _, e := range tp.list.Exprs {
+		var vals []tree.Expr
+		switch t := e.(type) {
+		case *tree.Tuple:
+			vals = t.Exprs
+		default:
+			vals = []tree.Expr{e}
+		}
+
+		// Cut off at DEFAULT, if present.
+		for i := range vals {
+			if _, ok := vals[i].(tree.DefaultVal); ok {
+				vals = vals[:i]
 			}
-			if len(vals) == 0 {
-				continue
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		d := make(tree.Datums, len(vals))
+		for i := range vals {
+			c := tree.CastExpr{Expr: vals[i], Type: tp.index.Columns[i].DatumType()}
+			cTyped, err := c.TypeCheck(&semaCtx, nil)
+			if err != nil {
+				panic(err)
 			}
-			d := make(tree.Datums, len(vals))
-			for i := range vals {
-				c := tree.CastExpr{Expr: vals[i], Type: ti.Columns[i].DatumType()}
-				cTyped, err := c.TypeCheck(&semaCtx, nil)
-				if err != nil {
-					panic(err)
-				}
-				d[i], err = cTyped.Eval(&evalCtx)
-				if err != nil {
-					panic(err)
-				}
+			d[i], err = cTyped.Eval(&evalCtx)
+			if err != nil {
+				panic(err)
 			}
+		}
 
-			// TODO(radu): split into multiple prefixes if Subpartition is also by list.
-			// Note that this functionality should be kept in sync with the real catalog
-			// implementation (opt_catalog.go).
+		// TODO(radu): split into multiple prefixes if Subpartition is also by list.
+		// Note that this functionality should be kept in sync with the real catalog
+		// implementation (opt_catalog.go).
 
-			res = append(res, d)
-		}
+		res = append(res, d)
 	}
 	return res
 }
@@ -119,6 +119,11 @@ const (
 	// up with better way to incorporate latency into the coster.
 	latencyCostFactor = cpuCostFactor
 
+	// localityOptimizedSearchRemoteDiscount is the fraction of the cost of the
+	// remote scan of a locality optimized search that is discounted, since the
+	// remote scan is only executed if the local scan doesn't find enough rows.
+	localityOptimizedSearchRemoteDiscount = 0.9
+
 	// hugeCost is used with expressions we want to avoid; these are expressions
 	// that "violate" a hint like forcing a specific index or join algorithm.
 	// If the final expression has this cost or larger, it means that there was no
@@ -183,6 +188,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp:
 		cost = c.computeSetCost(candidate)
 
+	case opt.LocalityOptimizedSearchOp:
+		cost = c.computeLocalityOptimizedSearchCost(candidate.(*memo.LocalityOptimizedSearchExpr))
+
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp:
 		cost = c.computeGroupingCost(candidate, required)
 
@@ -295,7 +303,34 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	if scan.Constraint == nil || scan.Constraint.IsUnconstrained() {
 		preferConstrainedScanCost = cpuCostFactor
 	}
-	return memo.Cost(rowCount)*(seqIOCostFactor+perRowCost) + preferConstrainedScanCost
+	cost := memo.Cost(rowCount)*(seqIOCostFactor+perRowCost) + preferConstrainedScanCost
+	if scan.SkipScanPrefixLen > 0 {
+		cost += c.skipScanSeekCost(scan)
+	}
+	return cost
+}
+
+// skipScanSeekCost returns the cost of the seeks performed by a skip scan: for
+// each distinct value of the skipped prefix, one seek finds the value and one
+// seek is needed for each span of the constraint under it.
+func (c *coster) skipScanSeekCost(scan *memo.ScanExpr) memo.Cost {
+	md := c.mem.Metadata()
+	index := md.Table(scan.Table).Index(scan.Index)
+	var prefixCols opt.ColSet
+	for i := 0; i < scan.SkipScanPrefixLen; i++ {
+		prefixCols.Add(scan.Table.ColumnID(index.Column(i).Ordinal))
+	}
+	// Without a column statistic, assume the worst case of a seek per row.
+	distinctCount := scan.Relational().Stats.RowCount
+	if colStat, ok := c.mem.RequestColStat(scan, prefixCols); ok {
+		distinctCount = colStat.DistinctCount
+	}
+	// Without a constraint, all of the rows under each value are scanned.
+	seeksPerValue := 2
+	if scan.Constraint != nil {
+		seeksPerValue = 1 + scan.Constraint.Spans.Count()
+	}
+	return memo.Cost(distinctCount*float64(seeksPerValue)) * randIOCostFactor
 }
 
 func (c *coster) computeVirtualScanCost(scan *memo.VirtualScanExpr) memo.Cost {
@@ -482,6 +517,25 @@ func (c *coster) computeSetCost(set memo.RelExpr) memo.Cost {
 	return cost
 }
 
+// computeLocalityOptimizedSearchCost returns the cost of a locality optimized
+// search, not including the cost of its inputs. The optimizer adds the cost of
+// both inputs, but the remote input is only executed when the local input
+// doesn't produce enough rows, which is expected to be rare. To reflect this,
+// most of the cost of the remote scan is discounted here, so that the search is
+// preferred over a scan of all the partitions when the local scan is cheap.
+func (c *coster) computeLocalityOptimizedSearchCost(
+	locOptSearch *memo.LocalityOptimizedSearchExpr,
+) memo.Cost {
+	// Add the CPU cost of emitting the rows.
+	cost := memo.Cost(locOptSearch.Relational().Stats.RowCount) * cpuCostFactor
+
+	if remote, ok := locOptSearch.Right.(*memo.ScanExpr); ok {
+		remoteCost := c.computeScanCost(remote, physical.MinRequired)
+		cost -= remoteCost * localityOptimizedSearchRemoteDiscount
+	}
+	return cost
+}
+
 func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.Required) memo.Cost {
 	// Start with some extra fixed overhead, since the grouping operators have
 	// setup overhead that is greater than other operators like Project. This
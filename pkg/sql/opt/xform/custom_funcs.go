@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
@@ -112,6 +113,190 @@ func (c *CustomFuncs) GenerateIndexScans(grp memo.RelExpr, scanPrivate *memo.Sca
 	}
 }
 
+// localityOptimizedSearchMaxRows is the maximum cardinality of a scan for
+// which a locality optimized search is planned. It is the default KV batch
+// size: a scan that can return more rows is likely to need several batches,
+// in which case reading the partitions in parallel is preferable.
+const localityOptimizedSearchMaxRows = 10000
+
+// CanMaybeGenerateLocalityOptimizedScan returns true if it may be possible to
+// generate a locality optimized scan from the given ScanPrivate. It only
+// performs cheap checks; GenerateLocalityOptimizedScan performs the others.
+func (c *CustomFuncs) CanMaybeGenerateLocalityOptimizedScan(scanPrivate *memo.ScanPrivate) bool {
+	if len(c.e.evalCtx.Locality.Tiers) == 0 {
+		return false
+	}
+	// The scan must be constrained, and it must not be limited since a limited
+	// scan returns the first rows in the index order, which the search would
+	// not preserve.
+	if scanPrivate.Constraint == nil || scanPrivate.HardLimit.IsSet() ||
+		scanPrivate.SkipScanPrefixLen > 0 || scanPrivate.GroupLimit.IsSet() {
+		return false
+	}
+	tab := c.e.mem.Metadata().Table(scanPrivate.Table)
+	return tab.Index(scanPrivate.Index).PartitionCount() > 1
+}
+
+// GenerateLocalityOptimizedScan generates a LocalityOptimizedSearch from a
+// constrained scan of a partitioned index, when some of the partitions of the
+// index are placed in the locality of the gateway and others are not. For
+// example, given a table whose primary index is on (region, k) and partitioned
+// by region, with each partition placed in its own region, and a unique
+// constraint on k, the scan in the query:
+//
+//   SELECT * FROM t WHERE k = 1
+//
+// is constrained to the spans [/'east'/1 - /'east'/1] [/'west'/1 - /'west'/1]
+// (using the check constraint on region). If the gateway is in the east
+// region, the search first scans [/'east'/1 - /'east'/1], and only scans the
+// remote span [/'west'/1 - /'west'/1] if no row was found. The scan must have a
+// small maximum cardinality, since the search stops as soon as it has found
+// that many rows.
+func (c *CustomFuncs) GenerateLocalityOptimizedScan(
+	grp memo.RelExpr, scanPrivate *memo.ScanPrivate,
+) {
+	if grp.Relational().Cardinality.Max > localityOptimizedSearchMaxRows {
+		return
+	}
+	tab := c.e.mem.Metadata().Table(scanPrivate.Table)
+	index := tab.Index(scanPrivate.Index)
+
+	var localPartitions util.FastIntSet
+	for i, n := 0, index.PartitionCount(); i < n; i++ {
+		if localityMatchScore(index.Partition(i).Zone(), c.e.evalCtx.Locality) > 0 {
+			localPartitions.Add(i)
+		}
+	}
+	if localPartitions.Empty() || localPartitions.Len() == index.PartitionCount() {
+		return
+	}
+
+	localSpans, remoteSpans := c.splitSpansByLocality(scanPrivate.Constraint, index, localPartitions)
+	if localSpans.Count() == 0 || remoteSpans.Count() == 0 {
+		return
+	}
+
+	localScan, localCols := c.makeLocalityOptimizedScan(scanPrivate, &localSpans)
+	remoteScan, remoteCols := c.makeLocalityOptimizedScan(scanPrivate, &remoteSpans)
+	search := memo.LocalityOptimizedSearchExpr{
+		Left:  localScan,
+		Right: remoteScan,
+		SetPrivate: memo.SetPrivate{
+			LeftCols:  localCols,
+			RightCols: remoteCols,
+			OutCols:   opt.ColSetToList(scanPrivate.Cols),
+		},
+	}
+	c.e.mem.AddLocalityOptimizedSearchToGroup(&search, grp)
+}
+
+// splitSpansByLocality splits the spans of the given constraint on a partitioned
+// index into the spans that only contain keys of the given local partitions and
+// the other spans.
+func (c *CustomFuncs) splitSpansByLocality(
+	cons *constraint.Constraint, index cat.Index, localPartitions util.FastIntSet,
+) (localSpans, remoteSpans constraint.Spans) {
+	type partitionPrefix struct {
+		values tree.Datums
+		local  bool
+	}
+	var prefixes []partitionPrefix
+	for i, n := 0, index.PartitionCount(); i < n; i++ {
+		for _, values := range index.Partition(i).PartitionByListPrefixes() {
+			prefixes = append(prefixes, partitionPrefix{values: values, local: localPartitions.Contains(i)})
+		}
+	}
+
+	keyCtx := constraint.MakeKeyContext(&cons.Columns, c.e.evalCtx)
+	hasPrefix := func(key constraint.Key, values tree.Datums) bool {
+		if key.Length() < len(values) {
+			return false
+		}
+		for i := range values {
+			if keyCtx.Compare(i, key.Value(i), values[i]) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, n := 0, cons.Spans.Count(); i < n; i++ {
+		span := cons.Spans.Get(i)
+		// A span only contains keys of a partition if both of its boundaries
+		// start with the values of the partition. If several partitions match,
+		// the one with the longest prefix is the most specific.
+		var match *partitionPrefix
+		for j := range prefixes {
+			p := &prefixes[j]
+			if (match == nil || len(p.values) > len(match.values)) &&
+				hasPrefix(span.StartKey(), p.values) && hasPrefix(span.EndKey(), p.values) {
+				match = p
+			}
+		}
+		local := match != nil && match.local
+		if local {
+			// The span might still contain keys of a more specific remote
+			// partition nested in the local one, as in ('us', DEFAULT) and
+			// ('us', 'seattle').
+			for j := range prefixes {
+				p := &prefixes[j]
+				if !p.local && len(p.values) > len(match.values) && hasPrefix(
+					constraint.MakeCompositeKey(p.values...), match.values,
+				) {
+					local = false
+					break
+				}
+			}
+		}
+		if local {
+			localSpans.Append(span)
+		} else {
+			remoteSpans.Append(span)
+		}
+	}
+	return localSpans, remoteSpans
+}
+
+// makeLocalityOptimizedScan constructs a scan that is identical to the given
+// one, except that it is constrained to the given spans and that it uses a new
+// instance of the table in the metadata, so that its columns are distinct from
+// the columns of the original scan. It returns the new scan along with its
+// columns, in the same order as the columns of the original scan.
+func (c *CustomFuncs) makeLocalityOptimizedScan(
+	scanPrivate *memo.ScanPrivate, spans *constraint.Spans,
+) (memo.RelExpr, opt.ColList) {
+	md := c.e.mem.Metadata()
+	tabMeta := md.TableMeta(scanPrivate.Table)
+	newTabID := md.AddTable(tabMeta.Table, &tabMeta.Alias)
+	mapCol := func(col opt.ColumnID) opt.ColumnID {
+		return newTabID.ColumnID(scanPrivate.Table.ColumnOrdinal(col))
+	}
+
+	newScanPrivate := *scanPrivate
+	newScanPrivate.Table = newTabID
+	newScanPrivate.Cols = opt.ColSet{}
+	cols := opt.ColSetToList(scanPrivate.Cols)
+	newCols := make(opt.ColList, len(cols))
+	for i, col := range cols {
+		newCols[i] = mapCol(col)
+		newScanPrivate.Cols.Add(newCols[i])
+	}
+
+	oldColumns := &scanPrivate.Constraint.Columns
+	orderingCols := make([]opt.OrderingColumn, oldColumns.Count())
+	for i := range orderingCols {
+		col := oldColumns.Get(i)
+		orderingCols[i] = opt.MakeOrderingColumn(mapCol(col.ID()), col.Descending())
+	}
+	var columns constraint.Columns
+	columns.Init(orderingCols)
+	keyCtx := constraint.MakeKeyContext(&columns, c.e.evalCtx)
+	newScanPrivate.Constraint = &constraint.Constraint{}
+	newScanPrivate.Constraint.Init(&keyCtx, spans)
+
+	return c.e.f.ConstructScan(&newScanPrivate), newCols
+}
+
 // ----------------------------------------------------------------------
 //
 // Select Rules
@@ -356,6 +541,142 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	}
 }
 
+// maxSkipScanPrefixDistinctCount is the maximum estimated number of distinct
+// values of the leading index column for which a skip scan is considered.
+// Every distinct value requires at least one seek, so skip scans over columns
+// with more distinct values are unlikely to beat a full scan.
+const maxSkipScanPrefixDistinctCount = 1000
+
+// canPlanSkipScans returns whether skip scans can be planned. The table
+// readers of older nodes ignore the skip scan fields of their spec, so skip
+// scans are only planned once the cluster has been upgraded.
+func (c *CustomFuncs) canPlanSkipScans() bool {
+	return cluster.Version.IsActive(c.e.evalCtx.Context, c.e.evalCtx.Settings, cluster.VersionSkipScans)
+}
+
+// GenerateSkipScans enumerates all indexes on the Scan operator's table and
+// generates a skip scan (also known as a loose index scan) over each index
+// whose leading column is not referenced by the filters, but whose following
+// columns can be constrained by them. For example, given an index on (a, b),
+// the query:
+//
+//   SELECT * FROM t WHERE b = 1
+//
+// can be executed by seeking to each distinct value of a and only scanning the
+// keys with b = 1 under it:
+//
+//   [/a1/1 - /a1/1] [/a2/1 - /a2/1] ...
+//
+// Since the distinct values of the leading column aren't known in advance,
+// the seeks happen at execution time. A skip scan is only generated when
+// statistics are available and estimate that the leading column has few
+// distinct values, and the coster accounts for the cost of the seeks. Like
+// GenerateConstrainedScans, an IndexJoin is added when the index doesn't cover
+// all of the needed columns.
+func (c *CustomFuncs) GenerateSkipScans(
+	grp memo.RelExpr, scan memo.RelExpr, scanPrivate *memo.ScanPrivate, filters memo.FiltersExpr,
+) {
+	if !c.canPlanSkipScans() {
+		return
+	}
+	md := c.e.mem.Metadata()
+	if md.Table(scanPrivate.Table).IsInterleaved() {
+		return
+	}
+	if !scan.Relational().Stats.Available {
+		return
+	}
+
+	var sb indexScanBuilder
+	sb.init(c, scanPrivate.Table)
+
+	const prefixLen = 1
+	filterCols := c.FilterOuterCols(filters)
+	var iter scanIndexIter
+	iter.init(c.e.mem, scanPrivate)
+	for iter.next() {
+		if iter.index.LaxKeyColumnCount() <= prefixLen {
+			continue
+		}
+		firstIndexCol := scanPrivate.Table.ColumnID(iter.index.Column(0).Ordinal)
+		if filterCols.Contains(firstIndexCol) {
+			// Constraining the leading column is handled by
+			// GenerateConstrainedScans.
+			continue
+		}
+		colStat, ok := c.e.mem.RequestColStat(scan, opt.MakeColSet(firstIndexCol))
+		if !ok || colStat.DistinctCount > maxSkipScanPrefixDistinctCount {
+			continue
+		}
+
+		constraint, remainingFilters, ok := c.tryConstrainIndexSuffix(
+			filters, scanPrivate.Table, iter.indexOrdinal, prefixLen,
+		)
+		if !ok {
+			continue
+		}
+
+		// Construct new skip scan ScanPrivate.
+		newScanPrivate := *scanPrivate
+		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.Constraint = constraint
+		newScanPrivate.SkipScanPrefixLen = prefixLen
+
+		// If the alternate index includes the set of needed columns, then construct
+		// a new Scan operator using that index.
+		if iter.isCovering() {
+			sb.setScan(&newScanPrivate)
+			sb.addSelect(remainingFilters)
+			sb.build(grp)
+			continue
+		}
+
+		// Otherwise, construct an IndexJoin operator that provides the columns
+		// missing from the index.
+		if scanPrivate.Flags.NoIndexJoin {
+			continue
+		}
+		newScanPrivate.Cols = iter.indexCols().Intersection(scanPrivate.Cols)
+		newScanPrivate.Cols.UnionWith(sb.primaryKeyCols())
+		sb.setScan(&newScanPrivate)
+		remainingFilters = sb.addSelectAfterSplit(remainingFilters, newScanPrivate.Cols)
+		sb.addIndexJoin(scanPrivate.Cols)
+		sb.addSelect(remainingFilters)
+		sb.build(grp)
+	}
+}
+
+// tryConstrainIndexSuffix is like tryConstrainIndex, but tries to derive a
+// constraint on the index columns that follow the first prefixLen columns of
+// the index, for use by a skip scan.
+func (c *CustomFuncs) tryConstrainIndexSuffix(
+	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int, prefixLen int,
+) (constraint *constraint.Constraint, remainingFilters memo.FiltersExpr, ok bool) {
+	md := c.e.mem.Metadata()
+	index := md.Table(tabID).Index(indexOrd)
+	columns := make([]opt.OrderingColumn, index.LaxKeyColumnCount()-prefixLen)
+	var notNullCols opt.ColSet
+	for i := range columns {
+		col := index.Column(prefixLen + i)
+		colID := tabID.ColumnID(col.Ordinal)
+		columns[i] = opt.MakeOrderingColumn(colID, col.Descending)
+		if !col.IsNullable() {
+			notNullCols.Add(colID)
+		}
+	}
+
+	var ic idxconstraint.Instance
+	ic.Init(filters, columns, notNullCols, false /* isInverted */, c.e.evalCtx, c.e.f)
+	constraint = ic.Constraint()
+	if constraint.IsUnconstrained() || constraint.IsContradiction() {
+		return nil, nil, false
+	}
+
+	// Make copy of constraint so that idxconstraint instance is not referenced.
+	copy := *constraint
+	return &copy, ic.RemainingFilters(), true
+}
+
 // checkConstraintFilters generates all filters that we can derive from the
 // check constraints. These are constraints that have been validated and are
 // non-nullable. We only use non-nullable check constraints because they
@@ -1047,6 +1368,11 @@ func (c *CustomFuncs) CanLimitConstrainedScan(
 		return false
 	}
 
+	if scanPrivate.GroupLimit != 0 {
+		// The limit per group of the scan already fixes its direction.
+		return false
+	}
+
 	if scanPrivate.Constraint == nil {
 		// This is not a constrained scan, so skip it. The PushLimitIntoScan rule
 		// is responsible for limited unconstrained scans.
@@ -2223,6 +2549,107 @@ func (c *CustomFuncs) OtherAggsAreConst(
 	return true
 }
 
+// GenerateLimitedGroupScans generates a GroupBy over a "partial scan per
+// group" for each index of the input Scan operator's table whose leading
+// columns are the grouping columns, followed by the column that a Min or Max
+// aggregate is applied to. For example, given an index on (a, b), the query:
+//
+//   SELECT a, max(b) FROM t GROUP BY a
+//
+// can be executed by seeking to the last row under each distinct value of a,
+// instead of scanning all of the rows of each group. The GroupBy is kept, but
+// it only aggregates one row per group.
+//
+// A group can only be answered by its first row in the scan direction if that
+// row can't have a NULL aggregated value that should be ignored. Since NULL
+// values sort first, Max is always answered by the last row, whereas Min
+// requires the aggregated column to be NOT NULL.
+func (c *CustomFuncs) GenerateLimitedGroupScans(
+	grp memo.RelExpr,
+	scan memo.RelExpr,
+	scanPrivate *memo.ScanPrivate,
+	aggs memo.AggregationsExpr,
+	agg opt.ScalarExpr,
+	private *memo.GroupingPrivate,
+) {
+	if !c.canPlanSkipScans() {
+		return
+	}
+	md := c.e.mem.Metadata()
+	if md.Table(scanPrivate.Table).IsInterleaved() {
+		return
+	}
+	if !scan.Relational().Stats.Available {
+		return
+	}
+	groupingCols := private.GroupingCols
+	if groupingCols.Empty() {
+		return
+	}
+	isMin := agg.Op() == opt.MinOp
+	col := agg.Child(0).(*memo.VariableExpr).Col
+	if isMin && !scan.Relational().NotNullCols.Contains(col) {
+		return
+	}
+	colStat, ok := c.e.mem.RequestColStat(scan, groupingCols)
+	if !ok || colStat.DistinctCount > maxSkipScanPrefixDistinctCount {
+		return
+	}
+
+	prefixLen := groupingCols.Len()
+	var iter scanIndexIter
+	iter.init(c.e.mem, scanPrivate)
+	for iter.next() {
+		if iter.index.KeyColumnCount() <= prefixLen {
+			continue
+		}
+		var prefixCols opt.ColSet
+		for i := 0; i < prefixLen; i++ {
+			prefixCols.Add(scanPrivate.Table.ColumnID(iter.index.Column(i).Ordinal))
+		}
+		aggIndexCol := iter.index.Column(prefixLen)
+		if !prefixCols.Equals(groupingCols) ||
+			scanPrivate.Table.ColumnID(aggIndexCol.Ordinal) != col {
+			continue
+		}
+
+		// The first row of each group in the scan direction must hold the
+		// smallest value for Min and the largest value for Max.
+		reverse := isMin == aggIndexCol.Descending
+
+		newScanPrivate := *scanPrivate
+		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.SkipScanPrefixLen = prefixLen
+		newScanPrivate.GroupLimit = memo.MakeScanLimit(1, reverse)
+
+		var input memo.RelExpr
+		if iter.isCovering() {
+			input = c.e.f.ConstructScan(&newScanPrivate)
+		} else {
+			// Construct an IndexJoin operator that provides the columns missing
+			// from the index.
+			if scanPrivate.Flags.NoIndexJoin {
+				continue
+			}
+			var sb indexScanBuilder
+			sb.init(c, scanPrivate.Table)
+			newScanPrivate.Cols = iter.indexCols().Intersection(scanPrivate.Cols)
+			newScanPrivate.Cols.UnionWith(sb.primaryKeyCols())
+			input = c.e.f.ConstructIndexJoin(
+				c.e.f.ConstructScan(&newScanPrivate),
+				&memo.IndexJoinPrivate{Table: scanPrivate.Table, Cols: scanPrivate.Cols},
+			)
+		}
+
+		newExpr := memo.GroupByExpr{
+			Input:           input,
+			Aggregations:    aggs,
+			GroupingPrivate: *private,
+		}
+		c.e.mem.AddGroupByToGroup(&newExpr, grp)
+	}
+}
+
 // MakeOrderingChoiceFromColumn constructs a new OrderingChoice with
 // one element in the sequence: the columnID in the order defined by
 // (MIN/MAX) operator. This function was originally created to be used
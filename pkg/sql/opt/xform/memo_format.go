@@ -281,9 +281,15 @@ func (mf *memoFormatter) formatPrivate(e opt.Expr, physProps *physical.Required)
 		if t.Constraint != nil {
 			fmt.Fprintf(mf.buf, ",constrained")
 		}
+		if t.SkipScanPrefixLen > 0 {
+			fmt.Fprintf(mf.buf, ",skip")
+		}
 		if t.HardLimit.IsSet() {
 			fmt.Fprintf(mf.buf, ",lim=%s", t.HardLimit)
 		}
+		if t.GroupLimit.IsSet() {
+			fmt.Fprintf(mf.buf, ",grouplim=%s", t.GroupLimit)
+		}
 
 	case *memo.IndexJoinExpr:
 		fmt.Fprintf(mf.buf, ",cols=%s", t.Cols)
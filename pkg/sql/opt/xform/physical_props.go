@@ -93,7 +93,7 @@ func BuildChildPhysicalProps(
 	case opt.IndexJoinOp:
 		childProps.LimitHint = parentProps.LimitHint
 	case opt.ExceptOp, opt.ExceptAllOp, opt.IntersectOp, opt.IntersectAllOp,
-		opt.UnionOp, opt.UnionAllOp:
+		opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		// TODO(celine): Set operation limits need further thought; for example,
 		// the right child of an ExceptOp should not be limited.
 		childProps.LimitHint = parentProps.LimitHint
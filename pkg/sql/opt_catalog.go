@@ -564,18 +564,31 @@ func newOptTable(
 		}
 
 		// If there is a subzone that applies to the entire index, use that,
-		// else use the table zone. Skip subzones that apply to partitions,
-		// since they apply only to a subset of the index.
+		// else use the table zone. Subzones that apply to partitions are kept
+		// separately, since they apply only to a subset of the index.
 		idxZone := tblZone
+		var partZones map[string]*zonepb.ZoneConfig
 		for j := range tblZone.Subzones {
 			subzone := &tblZone.Subzones[j]
-			if subzone.IndexID == uint32(idxDesc.ID) && subzone.PartitionName == "" {
-				copyZone := subzone.Config
+			if subzone.IndexID != uint32(idxDesc.ID) {
+				continue
+			}
+			copyZone := subzone.Config
+			if subzone.PartitionName == "" {
 				copyZone.InheritFromParent(tblZone)
 				idxZone = &copyZone
+			} else {
+				if partZones == nil {
+					partZones = make(map[string]*zonepb.ZoneConfig)
+				}
+				partZones[subzone.PartitionName] = &copyZone
 			}
 		}
-		ot.indexes[i].init(ot, i, idxDesc, idxZone)
+		// Partitions inherit from the zone of their index.
+		for _, partZone := range partZones {
+			partZone.InheritFromParent(idxZone)
+		}
+		ot.indexes[i].init(ot, i, idxDesc, idxZone, partZones)
 	}
 
 	for i := range ot.desc.OutboundFKs {
@@ -844,6 +857,9 @@ type optIndex struct {
 	numCols       int
 	numKeyCols    int
 	numLaxKeyCols int
+
+	// partitions are the PARTITION BY LIST partitions of the index.
+	partitions []optPartition
 }
 
 var _ cat.Index = &optIndex{}
@@ -851,12 +867,28 @@ var _ cat.Index = &optIndex{}
 // init can be used instead of newOptIndex when we have a pre-allocated instance
 // (e.g. as part of a bigger struct).
 func (oi *optIndex) init(
-	tab *optTable, indexOrdinal int, desc *sqlbase.IndexDescriptor, zone *zonepb.ZoneConfig,
+	tab *optTable,
+	indexOrdinal int,
+	desc *sqlbase.IndexDescriptor,
+	zone *zonepb.ZoneConfig,
+	partZones map[string]*zonepb.ZoneConfig,
 ) {
 	oi.tab = tab
 	oi.desc = desc
 	oi.zone = zone
 	oi.indexOrdinal = indexOrdinal
+	if list := desc.Partitioning.List; len(list) > 0 {
+		oi.partitions = make([]optPartition, len(list))
+		for i := range list {
+			p := &oi.partitions[i]
+			p.index = oi
+			p.desc = &list[i]
+			p.zone = zone
+			if partZone, ok := partZones[list[i].Name]; ok {
+				p.zone = partZone
+			}
+		}
+	}
 	if desc == &tab.desc.PrimaryIndex {
 		// Although the primary index contains all columns in the table, the index
 		// descriptor does not contain columns that are not explicitly part of the
@@ -1003,26 +1035,71 @@ func (oi *optIndex) PartitionByListPrefixes() []tree.Datums {
 	res := make([]tree.Datums, 0, len(list))
 	var a sqlbase.DatumAlloc
 	for i := range list {
-		for _, valueEncBuf := range list[i].Values {
-			t, _, err := sqlbase.DecodePartitionTuple(
-				&a, &oi.tab.desc.TableDescriptor, oi.desc, &oi.desc.Partitioning,
-				valueEncBuf, nil, /* prefixDatums */
-			)
-			if err != nil {
-				panic(errors.NewAssertionErrorWithWrappedErrf(err, "while decoding partition tuple"))
-			}
-			// Ignore the DEFAULT case, where there is nothing to return.
-			if len(t.Datums) > 0 {
-				res = append(res, t.Datums)
-			}
-			// TODO(radu): split into multiple prefixes if Subpartition is also by list.
-			// Note that this functionality should be kept in sync with the test catalog
-			// implementation (test_catalog.go).
+		res = oi.appendPartitionValues(res, &list[i], &a)
+	}
+	return res
+}
+
+// appendPartitionValues decodes the values of the given partition and appends
+// them to res.
+func (oi *optIndex) appendPartitionValues(
+	res []tree.Datums, p *sqlbase.PartitioningDescriptor_List, a *sqlbase.DatumAlloc,
+) []tree.Datums {
+	for _, valueEncBuf := range p.Values {
+		t, _, err := sqlbase.DecodePartitionTuple(
+			a, &oi.tab.desc.TableDescriptor, oi.desc, &oi.desc.Partitioning,
+			valueEncBuf, nil, /* prefixDatums */
+		)
+		if err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "while decoding partition tuple"))
 		}
+		// Ignore the DEFAULT case, where there is nothing to return.
+		if len(t.Datums) > 0 {
+			res = append(res, t.Datums)
+		}
+		// TODO(radu): split into multiple prefixes if Subpartition is also by list.
+		// Note that this functionality should be kept in sync with the test catalog
+		// implementation (test_catalog.go).
 	}
 	return res
 }
 
+// PartitionCount is part of the cat.Index interface.
+func (oi *optIndex) PartitionCount() int {
+	return len(oi.partitions)
+}
+
+// Partition is part of the cat.Index interface.
+func (oi *optIndex) Partition(i int) cat.Partition {
+	return &oi.partitions[i]
+}
+
+// optPartition implements cat.Partition and represents a PARTITION BY LIST
+// partition of an index.
+type optPartition struct {
+	index *optIndex
+	desc  *sqlbase.PartitioningDescriptor_List
+	zone  *zonepb.ZoneConfig
+}
+
+var _ cat.Partition = &optPartition{}
+
+// Name is part of the cat.Partition interface.
+func (op *optPartition) Name() string {
+	return op.desc.Name
+}
+
+// Zone is part of the cat.Partition interface.
+func (op *optPartition) Zone() cat.Zone {
+	return op.zone
+}
+
+// PartitionByListPrefixes is part of the cat.Partition interface.
+func (op *optPartition) PartitionByListPrefixes() []tree.Datums {
+	var a sqlbase.DatumAlloc
+	return op.index.appendPartitionValues(nil /* res */, op.desc, &a)
+}
+
 type optTableStat struct {
 	stat           *stats.TableStatistic
 	columnOrdinals []int
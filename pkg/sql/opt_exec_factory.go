@@ -72,6 +72,8 @@ func (ef *execFactory) ConstructScan(
 	index cat.Index,
 	needed exec.ColumnOrdinalSet,
 	indexConstraint *constraint.Constraint,
+	skipScanPrefixLen int,
+	skipScanGroupLimit int64,
 	hardLimit int64,
 	softLimit int64,
 	reverse bool,
@@ -111,7 +113,19 @@ func (ef *execFactory) ConstructScan(
 	scan.maxResults = maxResults
 	scan.parallelScansEnabled = sqlbase.ParallelScans.Get(&ef.planner.extendedEvalCtx.Settings.SV)
 	var err error
-	scan.spans, err = sb.SpansFromConstraint(indexConstraint, needed, false /* forDelete */)
+	if skipScanPrefixLen > 0 {
+		// The constraint of a skip scan applies to the columns following the
+		// prefix, so the whole index is scanned for distinct prefixes.
+		scan.skipScanPrefixLen = skipScanPrefixLen
+		scan.skipScanGroupLimit = skipScanGroupLimit
+		scan.skipScanSuffixSpans, err = sb.SkipScanSuffixSpans(indexConstraint, skipScanPrefixLen)
+		if err != nil {
+			return nil, err
+		}
+		scan.spans, err = sb.UnconstrainedSpans(false /* forDelete */)
+	} else {
+		scan.spans, err = sb.SpansFromConstraint(indexConstraint, needed, false /* forDelete */)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -260,6 +274,7 @@ func (ef *execFactory) ConstructHashJoin(
 	left, right exec.Node,
 	leftEqCols, rightEqCols []exec.ColumnOrdinal,
 	leftEqColsAreKey, rightEqColsAreKey bool,
+	rejectOnNull bool,
 	extraOnCond tree.TypedExpr,
 ) (exec.Node, error) {
 	p := ef.planner
@@ -287,6 +302,7 @@ func (ef *execFactory) ConstructHashJoin(
 	}
 	pred.leftEqKey = leftEqColsAreKey
 	pred.rightEqKey = rightEqColsAreKey
+	pred.rejectOnNull = rejectOnNull
 
 	pred.onCond = pred.iVarHelper.Rebind(
 		extraOnCond, false /* alsoReset */, false, /* normalizeToNonNil */
@@ -500,9 +516,9 @@ func (ef *execFactory) ConstructDistinct(
 
 // ConstructSetOp is part of the exec.Factory interface.
 func (ef *execFactory) ConstructSetOp(
-	typ tree.UnionType, all bool, left, right exec.Node,
+	typ tree.UnionType, all bool, left, right exec.Node, hardLimit uint64,
 ) (exec.Node, error) {
-	return ef.planner.newUnionNode(typ, all, left.(planNode), right.(planNode))
+	return ef.planner.newUnionNode(typ, all, left.(planNode), right.(planNode), hardLimit)
 }
 
 // ConstructSort is part of the exec.Factory interface.
@@ -54,6 +54,10 @@ type Statement struct {
 	// NumAnnotations indicates the number of annotations in the tree. It is equal
 	// to the maximum annotation index.
 	NumAnnotations tree.AnnotationIdx
+
+	// Offset is the byte offset of SQL in the string that the statement was
+	// parsed from, which may contain multiple statements.
+	Offset int
 }
 
 // Statements is a list of parsed statements.
@@ -114,7 +118,7 @@ func (p *Parser) parseOneWithDepth(depth int, sql string) (Statement, error) {
 	return stmts[0], nil
 }
 
-func (p *Parser) scanOneStmt() (sql string, tokens []sqlSymType, done bool) {
+func (p *Parser) scanOneStmt() (sql string, offset int, tokens []sqlSymType, done bool) {
 	var lval sqlSymType
 	tokens = p.tokBuf[:0]
 
@@ -122,7 +126,7 @@ func (p *Parser) scanOneStmt() (sql string, tokens []sqlSymType, done bool) {
 	for {
 		p.scanner.scan(&lval)
 		if lval.id == 0 {
-			return "", nil, true
+			return "", 0, nil, true
 		}
 		if lval.id != ';' {
 			break
@@ -135,12 +139,12 @@ func (p *Parser) scanOneStmt() (sql string, tokens []sqlSymType, done bool) {
 	tokens = append(tokens, lval)
 	for {
 		if lval.id == ERROR {
-			return p.scanner.in[startPos:], tokens, true
+			return p.scanner.in[startPos:], int(startPos), tokens, true
 		}
 		posBeforeScan := p.scanner.pos
 		p.scanner.scan(&lval)
 		if lval.id == 0 || lval.id == ';' {
-			return p.scanner.in[startPos:posBeforeScan], tokens, (lval.id == 0)
+			return p.scanner.in[startPos:posBeforeScan], int(startPos), tokens, (lval.id == 0)
 		}
 		lval.pos -= startPos
 		tokens = append(tokens, lval)
@@ -152,11 +156,12 @@ func (p *Parser) parseWithDepth(depth int, sql string, nakedIntType *types.T) (S
 	p.scanner.init(sql)
 	defer p.scanner.cleanup()
 	for {
-		sql, tokens, done := p.scanOneStmt()
+		sql, offset, tokens, done := p.scanOneStmt()
 		stmt, err := p.parse(depth+1, sql, tokens, nakedIntType)
 		if err != nil {
 			return nil, err
 		}
+		stmt.Offset = offset
 		if stmt.AST != nil {
 			stmts = append(stmts, stmt)
 		}
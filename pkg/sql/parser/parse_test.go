@@ -873,6 +873,9 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t ORDER BY a NULLS FIRST`},
 		{`SELECT a FROM t ORDER BY a ASC NULLS FIRST`},
 		{`SELECT a FROM t ORDER BY a DESC NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`},
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
@@ -1401,6 +1404,9 @@ func TestParse(t *testing.T) {
 		{`WITH cte AS (SELECT 1) SELECT * FROM cte`},
 		{`WITH cte (x) AS (INSERT INTO abc VALUES (1, 2)), cte2 (y) AS (SELECT x + 1 FROM cte) SELECT * FROM cte, cte2`},
 		{`WITH RECURSIVE cte (x) AS (SELECT 1), cte2 (y) AS (SELECT x + 1 FROM cte) SELECT 1`},
+		{`WITH cte AS MATERIALIZED (SELECT 1) SELECT * FROM cte`},
+		{`WITH cte AS NOT MATERIALIZED (SELECT 1) SELECT * FROM cte`},
+		{`WITH cte (x) AS MATERIALIZED (SELECT 1), cte2 (y) AS NOT MATERIALIZED (SELECT x + 1 FROM cte) SELECT * FROM cte, cte2`},
 	}
 	var p parser.Parser // Verify that the same parser can be reused.
 	for _, d := range testData {
@@ -3175,10 +3181,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`},
 		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`},
 
-		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``},
-		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``},
-		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`, 6224, ``},
-
 		{`CREATE TABLE a(b BOX)`, 21286, `box`},
 		{`CREATE TABLE a(b CIDR)`, 18846, `cidr`},
 		{`CREATE TABLE a(b CIRCLE)`, 21286, `circle`},
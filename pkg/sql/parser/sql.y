@@ -893,6 +893,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <bool> all_or_distinct
 %type <bool> with_comment
+%type <bool> materialize_clause
 %type <empty> join_outer
 %type <tree.JoinCond> join_qual
 %type <str> join_type
@@ -6227,6 +6228,27 @@ common_table_expr:
       Stmt: $5.stmt(),
     }
   }
+| table_alias_name opt_column_list AS materialize_clause '(' preparable_stmt ')'
+  {
+    $$.val = &tree.CTE{
+      Name: tree.AliasClause{Alias: tree.Name($1), Cols: $2.nameList() },
+      Mtr: tree.MaterializeClause{
+        Set: true,
+        Materialize: $4.bool(),
+      },
+      Stmt: $6.stmt(),
+    }
+  }
+
+materialize_clause:
+  MATERIALIZED
+  {
+    $$.val = true
+  }
+| NOT MATERIALIZED
+  {
+    $$.val = false
+  }
 
 opt_with:
   WITH {}
@@ -6306,22 +6328,11 @@ sortby:
   a_expr opt_asc_desc opt_nulls_order
   {
     /* FORCE DOC */
-    dir := $2.dir()
-    nullsOrder := $3.nullsOrder()
-    // We currently only support the opposite of Postgres defaults.
-    if nullsOrder != tree.DefaultNullsOrder {
-      if dir == tree.Descending && nullsOrder == tree.NullsFirst {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-      if dir != tree.Descending && nullsOrder == tree.NullsLast {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-    }
     $$.val = &tree.Order{
       OrderType:  tree.OrderByColumn,
       Expr:       $1.expr(),
-      Direction:  dir,
-      NullsOrder: nullsOrder,
+      Direction:  $2.dir(),
+      NullsOrder: $3.nullsOrder(),
     }
   }
 | PRIMARY KEY table_name opt_asc_desc
@@ -80,6 +80,11 @@ type commandResult struct {
 	// statements.
 	bufferingDisabled bool
 
+	// bufferSize, if larger than the connection's results buffer size, is the
+	// number of bytes that can accumulate in the connection's buffer before
+	// the results are flushed. It is set for implicit transactions.
+	bufferSize int64
+
 	// released is set when the command result has been released so that its
 	// memory can be reused. It is also used to assert against use-after-free
 	// errors.
@@ -183,7 +188,7 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	if r.bufferingDisabled {
 		err = r.conn.Flush(r.pos)
 	} else {
-		_ /* flushed */, err = r.conn.maybeFlush(r.pos)
+		_ /* flushed */, err = r.conn.maybeFlush(r.pos, r.bufferSize)
 	}
 	return err
 }
@@ -194,6 +199,14 @@ func (r *commandResult) DisableBuffering() {
 	r.bufferingDisabled = true
 }
 
+// IncreaseBufferSize is part of the CommandResult interface.
+func (r *commandResult) IncreaseBufferSize(size int64) {
+	r.assertNotReleased()
+	if size > r.bufferSize {
+		r.bufferSize = size
+	}
+}
+
 // SetColumns is part of the CommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
 	r.assertNotReleased()
@@ -374,7 +387,7 @@ func (r *limitedCommandResult) AddRow(ctx context.Context, row tree.Datums) erro
 
 		return r.moreResultsNeeded(ctx)
 	}
-	if _ /* flushed */, err := r.conn.maybeFlush(r.pos); err != nil {
+	if _ /* flushed */, err := r.conn.maybeFlush(r.pos, r.bufferSize); err != nil {
 		return err
 	}
 	return nil
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -696,6 +697,21 @@ func (c *conn) handleSimpleQuery(
 			return nil
 		}
 
+		// Like in Postgres, the statements of a query string containing
+		// multiple statements run in a single implicit transaction when
+		// possible, and their errors point to their position in the query
+		// string.
+		var batchPos *sql.StmtBatchPosition
+		if len(stmts) > 1 {
+			batchPos = &sql.StmtBatchPosition{
+				Index:    i,
+				NumStmts: len(stmts),
+				Position: utf8.RuneCountInString(query[:stmts[i].Offset]) + 1,
+				ContinueImplicitTxn: i+1 < len(stmts) &&
+					sql.CanShareImplicitTxn(stmts[i].AST) && sql.CanShareImplicitTxn(stmts[i+1].AST),
+			}
+		}
+
 		if err := c.stmtBuf.Push(
 			ctx,
 			sql.ExecStmt{
@@ -703,6 +719,7 @@ func (c *conn) handleSimpleQuery(
 				TimeReceived: timeReceived,
 				ParseStart:   startParse,
 				ParseEnd:     endParse,
+				BatchPos:     batchPos,
 			}); err != nil {
 			return err
 		}
@@ -1194,6 +1211,14 @@ func writeErr(
 		msgBuilder.writeTerminatedString(pgErr.Hint)
 	}
 
+	if pos, ok := sql.GetStmtBatchPosition(err); ok {
+		msgBuilder.putErrFieldMsg(pgwirebase.ServerErrFieldPosition)
+		msgBuilder.writeTerminatedString(strconv.Itoa(pos.Position))
+		msgBuilder.putErrFieldMsg(pgwirebase.ServerErrFieldWhere)
+		msgBuilder.writeTerminatedString(
+			fmt.Sprintf("statement %d of %d in the query string", pos.Index+1, pos.NumStmts))
+	}
+
 	if pgErr.Source != nil {
 		errCtx := pgErr.Source
 		if errCtx.File != "" {
@@ -1308,9 +1333,12 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 }
 
 // maybeFlush flushes the buffer to the network connection if it exceeded
-// sessionArgs.ConnResultsBufferSize.
-func (c *conn) maybeFlush(pos sql.CmdPos) (bool, error) {
-	if int64(c.writerState.buf.Len()) <= c.sessionArgs.ConnResultsBufferSize {
+// sessionArgs.ConnResultsBufferSize, or bufferSize if that is larger.
+func (c *conn) maybeFlush(pos sql.CmdPos, bufferSize int64) (bool, error) {
+	if bufferSize < c.sessionArgs.ConnResultsBufferSize {
+		bufferSize = c.sessionArgs.ConnResultsBufferSize
+	}
+	if int64(c.writerState.buf.Len()) <= bufferSize {
 		return false, nil
 	}
 	return true, c.Flush(pos)
@@ -11,6 +11,7 @@
 package pgerror_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -96,6 +97,27 @@ func TestFlatten(t *testing.T) {
 				t.CheckEqual(e.Code, pgcode.StatementCompletionUnknown)
 			},
 		},
+		{
+			errors.Wrap(&roachpb.NodeUnavailableError{}, "woo"),
+			func(t testutils.T, e *pgerror.Error) {
+				t.CheckRegexpEqual(e.Message, "woo: node unavailable")
+				t.CheckEqual(e.Code, pgcode.InternalConnectionFailure)
+			},
+		},
+		{
+			errors.Wrap(context.Canceled, "woo"),
+			func(t testutils.T, e *pgerror.Error) {
+				t.CheckEqual(e.Message, "woo: context canceled")
+				t.CheckEqual(e.Code, pgcode.QueryCanceled)
+			},
+		},
+		{
+			errors.Wrap(context.DeadlineExceeded, "woo"),
+			func(t testutils.T, e *pgerror.Error) {
+				t.CheckEqual(e.Message, "woo: context deadline exceeded")
+				t.CheckEqual(e.Code, pgcode.QueryCanceled)
+			},
+		},
 	}
 	tt := testutils.T{T: t}
 
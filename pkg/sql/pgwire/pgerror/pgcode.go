@@ -11,6 +11,7 @@
 package pgerror
 
 import (
+	"context"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -90,6 +91,8 @@ func GetPGCodeInternal(err error, computeDefaultCode func(err error) (code strin
 // - the existing code for Error instances
 // - SerializationFailure for roachpb retry errors that can be reported to clients
 // - StatementCompletionUnknown for ambiguous commit errors
+// - InternalConnectionFailure for roachpb errors from unavailable nodes
+// - QueryCanceled for context cancellation and deadline errors
 // - InternalError for assertion failures
 // - FeatureNotSupportedError for unimplemented errors.
 func ComputeDefaultCode(err error) string {
@@ -102,6 +105,12 @@ func ComputeDefaultCode(err error) string {
 		return pgcode.SerializationFailure
 	case ClientVisibleAmbiguousError:
 		return pgcode.StatementCompletionUnknown
+	case ClientVisibleUnavailableError:
+		return pgcode.InternalConnectionFailure
+	}
+
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return pgcode.QueryCanceled
 	}
 
 	if errors.IsAssertionFailure(err) {
@@ -126,6 +135,27 @@ type ClientVisibleAmbiguousError interface {
 	ClientVisibleAmbiguousError()
 }
 
+// ClientVisibleUnavailableError mirrors
+// roachpb.ClientVisibleUnavailableError but is defined here to avoid an
+// import cycle.
+type ClientVisibleUnavailableError interface {
+	ClientVisibleUnavailableError()
+}
+
+// IsRetryableCode returns whether an error with the given code can be
+// handled by the client by retrying the transaction. This is the case
+// for serialization failures, and for requests which a node was unable
+// to serve without evaluating them. Notably, StatementCompletionUnknown
+// is not retryable: the client must first determine whether the
+// transaction committed.
+func IsRetryableCode(code string) bool {
+	switch code {
+	case pgcode.SerializationFailure, pgcode.InternalConnectionFailure:
+		return true
+	}
+	return false
+}
+
 // combineCodes combines the inner and outer codes.
 func combineCodes(innerCode, outerCode string) string {
 	if outerCode == pgcode.Uncategorized {
@@ -75,3 +75,22 @@ func TestPGCode(t *testing.T) {
 	}
 
 }
+
+func TestIsRetryableCode(t *testing.T) {
+	testData := []struct {
+		code      string
+		retryable bool
+	}{
+		{pgcode.SerializationFailure, true},
+		{pgcode.InternalConnectionFailure, true},
+		{pgcode.StatementCompletionUnknown, false},
+		{pgcode.QueryCanceled, false},
+		{pgcode.OutOfMemory, false},
+		{pgcode.Uncategorized, false},
+	}
+	for _, d := range testData {
+		if res := pgerror.IsRetryableCode(d.code); res != d.retryable {
+			t.Errorf("%s: expected %t, got %t", d.code, d.retryable, res)
+		}
+	}
+}
@@ -1921,3 +1921,101 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestPGWireMultiStatementQuery verifies that the statements of a query
+// string containing multiple statements run in a single implicit transaction
+// and that an error points to the statement that caused it.
+func TestPGWireMultiStatementQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY)`)
+
+	testCases := []struct {
+		query string
+		// expectedPos is the position of the failing statement, or 0 if the
+		// query is expected to succeed.
+		expectedPos   int
+		expectedWhere string
+		// expectedRows is the number of rows in t after the query.
+		expectedRows int
+	}{
+		{
+			query:        `INSERT INTO t VALUES (1); INSERT INTO t VALUES (2)`,
+			expectedRows: 2,
+		},
+		{
+			// The error rolls back the previous statements of the query string.
+			query:         `INSERT INTO t VALUES (3); INSERT INTO t VALUES (4); SELECT 1/0`,
+			expectedPos:   53,
+			expectedWhere: "statement 3 of 3 in the query string",
+			expectedRows:  2,
+		},
+		{
+			// The statements after the failing one are skipped.
+			query:         `INSERT INTO t VALUES (3); SELECT 1/0; INSERT INTO t VALUES (4)`,
+			expectedPos:   27,
+			expectedWhere: "statement 2 of 3 in the query string",
+			expectedRows:  2,
+		},
+		{
+			// Even if the error doesn't abort the transaction.
+			query:         `SET TRACING = bogus; INSERT INTO t VALUES (3)`,
+			expectedPos:   1,
+			expectedWhere: "statement 1 of 2 in the query string",
+			expectedRows:  2,
+		},
+		{
+			query:         `INSERT INTO t VALUES (3);  INSERT INTO t VALUES (1)`,
+			expectedPos:   27,
+			expectedWhere: "statement 2 of 2 in the query string",
+			expectedRows:  2,
+		},
+		{
+			// Positions are counted in characters.
+			query:         `SELECT 'é';SELECT 1/0`,
+			expectedPos:   12,
+			expectedWhere: "statement 2 of 2 in the query string",
+			expectedRows:  2,
+		},
+		{
+			// Transaction control statements end the implicit transaction.
+			query:         `INSERT INTO t VALUES (3); BEGIN; INSERT INTO t VALUES (1); COMMIT`,
+			expectedPos:   34,
+			expectedWhere: "statement 3 of 4 in the query string",
+			expectedRows:  3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			// Use a single connection so that the session is in a clean state.
+			conn, err := db.Conn(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			_, err = conn.ExecContext(context.Background(), tc.query)
+			if tc.expectedPos == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				pqErr, ok := errors.UnwrapAll(err).(*pq.Error)
+				if !ok {
+					t.Fatalf("expected a pq error, got %v", err)
+				}
+				if pos := strconv.Itoa(tc.expectedPos); pqErr.Position != pos {
+					t.Errorf("expected position %s, got %s", pos, pqErr.Position)
+				}
+				if pqErr.Where != tc.expectedWhere {
+					t.Errorf("expected where %q, got %q", tc.expectedWhere, pqErr.Where)
+				}
+				// The error may have left an explicit transaction open.
+				_, _ = conn.ExecContext(context.Background(), `ROLLBACK`)
+			}
+			sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t`, [][]string{{strconv.Itoa(tc.expectedRows)}})
+		})
+	}
+}
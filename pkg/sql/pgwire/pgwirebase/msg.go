@@ -67,6 +67,8 @@ const (
 	ServerErrFieldSrcFile     ServerErrFieldType = 'F'
 	ServerErrFieldSrcLine     ServerErrFieldType = 'L'
 	ServerErrFieldSrcFunction ServerErrFieldType = 'R'
+	ServerErrFieldPosition    ServerErrFieldType = 'P'
+	ServerErrFieldWhere       ServerErrFieldType = 'W'
 )
 
 // PrepareType represents a subtype for prepare messages.
@@ -0,0 +1,261 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+// planRegressionLatencyThreshold is the factor by which the mean service
+// latency of a statement fingerprint has to degrade after a plan change for
+// the change to be reported.
+var planRegressionLatencyThreshold = func() *settings.FloatSetting {
+	s := settings.RegisterNonNegativeFloatSetting(
+		"sql.metrics.statement_details.plan_regression.latency_threshold",
+		"factor by which the mean latency of a fingerprint must degrade after its plan changes "+
+			"for the change to be recorded in system.plan_changes (0 = disabled)",
+		2,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// planChangesMaxCount is the number of plan changes kept in the
+// system.plan_changes ring buffer.
+var planChangesMaxCount = settings.RegisterPositiveIntSetting(
+	"sql.metrics.statement_details.plan_regression.max_count",
+	"maximum number of plan changes kept in system.plan_changes; "+
+		"the oldest changes are deleted when new ones are recorded",
+	1000,
+)
+
+// planRegressionMinExecutions is the number of executions with a plan after
+// which its mean latency is considered representative of the plan.
+const planRegressionMinExecutions = 10
+
+// planRegressionBufferSize is the number of detected plan regressions that can
+// be waiting to be recorded. Regressions detected while the buffer is full are
+// only logged.
+const planRegressionBufferSize = 64
+
+// planGistAttrs are the attributes of the plan nodes that are included in plan
+// gists. The other attributes, such as the spans of the scans, depend on the
+// values of the placeholders and constants of the statements rather than on
+// the shape of their plans.
+var planGistAttrs = map[string]bool{
+	"table": true,
+	"type":  true,
+}
+
+// planGist returns a compact description of the shape of a sampled plan,
+// e.g. "render(join[type=inner](scan[table=t@primary],scan[table=u@primary]))".
+func planGist(plan *roachpb.ExplainTreePlanNode) string {
+	var b strings.Builder
+	writePlanGist(&b, plan)
+	return b.String()
+}
+
+func writePlanGist(b *strings.Builder, plan *roachpb.ExplainTreePlanNode) {
+	b.WriteString(plan.Name)
+	first := true
+	for _, attr := range plan.Attrs {
+		if !planGistAttrs[attr.Key] {
+			continue
+		}
+		if first {
+			b.WriteByte('[')
+		} else {
+			b.WriteByte(',')
+		}
+		first = false
+		b.WriteString(attr.Key)
+		b.WriteByte('=')
+		b.WriteString(attr.Value)
+	}
+	if !first {
+		b.WriteByte(']')
+	}
+	if len(plan.Children) > 0 {
+		b.WriteByte('(')
+		for i, child := range plan.Children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writePlanGist(b, child)
+		}
+		b.WriteByte(')')
+	}
+}
+
+// planHistory tracks the plan of a statement fingerprint, along with the plan
+// it had before, and the mean service latency of both.
+type planHistory struct {
+	// gist is the gist of the most recently sampled plan.
+	gist string
+	// count and latency are the number of executions since gist was sampled and
+	// their mean service latency.
+	count   int64
+	latency float64
+	// prevGist and prevLatency describe the last plan that was executed at least
+	// planRegressionMinExecutions times before gist.
+	prevGist    string
+	prevLatency float64
+	// reported is set once the regression of gist has been reported, so that
+	// every plan change is reported at most once.
+	reported bool
+}
+
+// observe records that the plan of the fingerprint was sampled. The executions
+// recorded from now on are attributed to that plan.
+func (h *planHistory) observe(gist string) {
+	if gist == h.gist {
+		return
+	}
+	if h.count >= planRegressionMinExecutions {
+		h.prevGist, h.prevLatency = h.gist, h.latency
+	}
+	h.gist, h.count, h.latency, h.reported = gist, 0, 0, false
+}
+
+// record records an execution of the fingerprint and returns whether the
+// current plan is a regression from the previous one that hasn't been reported
+// yet.
+func (h *planHistory) record(svcLat float64, threshold float64) bool {
+	h.count++
+	h.latency += (svcLat - h.latency) / float64(h.count)
+	if threshold == 0 || h.reported || h.count < planRegressionMinExecutions ||
+		h.prevGist == "" || h.prevGist == h.gist {
+		return false
+	}
+	if h.latency <= h.prevLatency*threshold {
+		return false
+	}
+	h.reported = true
+	return true
+}
+
+// planRegression describes a plan change of a statement fingerprint after which
+// its mean service latency degraded.
+type planRegression struct {
+	appName     string
+	fingerprint string
+	oldGist     string
+	newGist     string
+	oldLatency  float64
+	newLatency  float64
+}
+
+// reportPlanRegression hands a detected plan regression over to the worker
+// that records them, without blocking the statement execution.
+func (s *Server) reportPlanRegression(ctx context.Context, r planRegression) {
+	select {
+	case s.planRegressions <- r:
+	default:
+		log.Warningf(ctx, "dropping plan regression of %q: mean latency went from %.6fs to %.6fs",
+			r.fingerprint, r.oldLatency, r.newLatency)
+	}
+}
+
+// startPlanRegressionRecorder starts a background worker that records the plan
+// regressions detected by the connExecutors in the event log and in
+// system.plan_changes.
+func (s *Server) startPlanRegressionRecorder(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case r := <-s.planRegressions:
+				if err := s.recordPlanRegression(ctx, r); err != nil {
+					log.Warningf(ctx, "failed to record plan regression: %v", err)
+				}
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// recordPlanRegression writes a plan regression to the event log and to
+// system.plan_changes, and then deletes the oldest plan changes beyond the
+// retention limit.
+//
+// The rows are written as the node user, since the users can only read
+// system.plan_changes.
+func (s *Server) recordPlanRegression(ctx context.Context, r planRegression) error {
+	log.Infof(ctx, "plan of %q changed from %s to %s; mean latency went from %.6fs to %.6fs",
+		r.fingerprint, r.oldGist, r.newGist, r.oldLatency, r.newLatency)
+	if !cluster.Version.IsActive(ctx, s.cfg.Settings, cluster.VersionPlanChanges) {
+		return nil
+	}
+	nodeID := s.cfg.NodeID.Get()
+	ie := s.cfg.InternalExecutor
+	if err := s.cfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		if err := MakeEventLogger(s.cfg).InsertEventRecord(
+			ctx,
+			txn,
+			EventLogPlanRegression,
+			0, /* targetID */
+			int32(nodeID),
+			EventLogPlanRegressionDetail{
+				ApplicationName: r.appName,
+				Fingerprint:     r.fingerprint,
+				OldPlan:         r.oldGist,
+				NewPlan:         r.newGist,
+				OldLatency:      r.oldLatency,
+				NewLatency:      r.newLatency,
+			},
+		); err != nil {
+			return err
+		}
+		_, err := ie.ExecWithUser(
+			ctx, "record-plan-change", txn, security.NodeUser,
+			`INSERT INTO system.plan_changes (
+  recorded, node_id, app_name, fingerprint, old_gist, new_gist, old_latency, new_latency
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			tree.MakeDTimestampTZ(timeutil.Now(), time.Microsecond),
+			int64(nodeID),
+			r.appName,
+			r.fingerprint,
+			r.oldGist,
+			r.newGist,
+			r.oldLatency,
+			r.newLatency,
+		)
+		return err
+	}); err != nil {
+		return errors.Wrap(err, "failed to record the plan change")
+	}
+
+	// Evict the oldest plan changes. The IDs are generated by unique_rowid(), so
+	// they roughly increase with the time at which the changes were recorded.
+	if _, err := ie.ExecWithUser(
+		ctx, "evict-plan-changes", nil /* txn */, security.NodeUser,
+		`DELETE FROM system.plan_changes WHERE id <= (
+  SELECT id FROM system.plan_changes ORDER BY id DESC LIMIT 1 OFFSET $1
+)`,
+		planChangesMaxCount.Get(&s.cfg.Settings.SV),
+	); err != nil {
+		return errors.Wrap(err, "failed to evict the oldest plan changes")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestPlanGist(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	scan := func(table, spans string) *roachpb.ExplainTreePlanNode {
+		return &roachpb.ExplainTreePlanNode{
+			Name: "scan",
+			Attrs: []*roachpb.ExplainTreePlanNode_Attr{
+				{Key: "table", Value: table},
+				{Key: "spans", Value: spans},
+			},
+		}
+	}
+	join := func(left, right *roachpb.ExplainTreePlanNode) *roachpb.ExplainTreePlanNode {
+		return &roachpb.ExplainTreePlanNode{
+			Name:     "hash-join",
+			Attrs:    []*roachpb.ExplainTreePlanNode_Attr{{Key: "type", Value: "inner"}},
+			Children: []*roachpb.ExplainTreePlanNode{left, right},
+		}
+	}
+
+	plan := join(scan("t@primary", "/1-/2"), scan("u@primary", "ALL"))
+	const expected = "hash-join[type=inner](scan[table=t@primary],scan[table=u@primary])"
+	if gist := planGist(plan); gist != expected {
+		t.Fatalf("expected %s, got %s", expected, gist)
+	}
+	if planGist(join(scan("t@primary", "/3-/4"), scan("u@primary", "ALL"))) != planGist(plan) {
+		t.Fatal("expected the gist to be independent of the spans")
+	}
+	if planGist(join(scan("t@t_a_idx", "/1-/2"), scan("u@primary", "ALL"))) == planGist(plan) {
+		t.Fatal("expected the gist to depend on the scanned indexes")
+	}
+}
+
+func TestPlanHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const threshold = 2
+	run := func(h *planHistory, n int, lat float64) (regressions int) {
+		for i := 0; i < n; i++ {
+			if h.record(lat, threshold) {
+				regressions++
+			}
+		}
+		return regressions
+	}
+
+	var h planHistory
+	h.observe("a")
+	if n := run(&h, 20, 1); n != 0 {
+		t.Fatalf("expected no regression without a previous plan, got %d", n)
+	}
+
+	// A plan change that doesn't degrade the latency beyond the threshold.
+	h.observe("b")
+	if n := run(&h, 20, 1.5); n != 0 {
+		t.Fatalf("expected no regression below the threshold, got %d", n)
+	}
+	if h.prevGist != "a" || h.prevLatency != 1 {
+		t.Fatalf("unexpected previous plan %s with latency %f", h.prevGist, h.prevLatency)
+	}
+
+	// A plan change that degrades the latency is reported once, after enough
+	// executions.
+	h.observe("c")
+	if n := run(&h, planRegressionMinExecutions-1, 10); n != 0 {
+		t.Fatalf("expected no regression before %d executions, got %d", planRegressionMinExecutions, n)
+	}
+	if n := run(&h, 20, 10); n != 1 {
+		t.Fatalf("expected a single regression, got %d", n)
+	}
+	if h.prevGist != "b" || h.gist != "c" {
+		t.Fatalf("unexpected plans %s and %s", h.prevGist, h.gist)
+	}
+
+	// A plan that was only executed a few times doesn't replace the previous
+	// plan, and switching back to the previous plan isn't a regression.
+	h.observe("d")
+	run(&h, 1, 100)
+	h.observe("c")
+	if h.prevGist != "c" {
+		t.Fatalf("expected the previous plan to be c, got %s", h.prevGist)
+	}
+	if n := run(&h, 20, 100); n != 0 {
+		t.Fatalf("expected no regression against the same plan, got %d", n)
+	}
+
+	// The detection is disabled with a threshold of 0.
+	h = planHistory{}
+	h.observe("a")
+	run(&h, 20, 1)
+	h.observe("b")
+	for i := 0; i < 20; i++ {
+		if h.record(10, 0 /* threshold */) {
+			t.Fatal("expected no regression with a threshold of 0")
+		}
+	}
+}
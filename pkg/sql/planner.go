@@ -67,6 +67,11 @@ type extendedEvalContext struct {
 
 	TxnModesSetter txnModesSetter
 
+	// ExtendTxnDeadline, if set, extends the deadline of the session's
+	// transaction if it is about to be reached. Mutations call it before they
+	// commit the transaction automatically.
+	ExtendTxnDeadline func(context.Context)
+
 	SchemaChangers *schemaChangerCollection
 
 	Jobs *jobsCollection
@@ -15,14 +15,24 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 )
 
+// prepStmtsSessionMemoryLimit caps the memory that the prepared statements and
+// portals of a session can use. The setting is read when a session starts.
+var prepStmtsSessionMemoryLimit = settings.RegisterPublicByteSizeSetting(
+	"sql.prepared_statements.session_memory_limit",
+	"maximum memory that the prepared statements and portals of a session can use (0 disables the limit)",
+	0,
+)
+
 // PreparedStatementOrigin is an enum representing the source of where
 // the prepare statement was made.
 type PreparedStatementOrigin int
@@ -58,6 +68,8 @@ type PreparedStatement struct {
 	// statement.
 	refCount int
 	memAcc   mon.BoundAccount
+	// numPrepStmts, if set, is decremented once refCount hits 0.
+	numPrepStmts *metric.Gauge
 
 	// createdAt is the timestamp this prepare statement was made at.
 	// Used for reporting on `pg_prepared_statements`.
@@ -87,6 +99,9 @@ func (p *PreparedStatement) decRef(ctx context.Context) {
 	p.refCount--
 	if p.refCount == 0 {
 		p.memAcc.Close(ctx)
+		if p.numPrepStmts != nil {
+			p.numPrepStmts.Dec(1)
+		}
 	}
 }
 
@@ -153,11 +168,11 @@ func (ex *connExecutor) newPreparedPortal(
 		Stmt:       stmt,
 		Qargs:      qargs,
 		OutFormats: outFormats,
-		memAcc:     ex.sessionMon.MakeBoundAccount(),
+		memAcc:     ex.prepStmtsMon.MakeBoundAccount(),
 		refCount:   1,
 	}
 	sz := int64(uintptr(len(name)) + unsafe.Sizeof(*portal))
-	if err := portal.memAcc.Grow(ctx, sz); err != nil {
+	if err := ex.growPrepStmtsAcc(ctx, &portal.memAcc, sz, stmt); err != nil {
 		return nil, err
 	}
 	// The portal keeps a reference to the PreparedStatement, so register it.
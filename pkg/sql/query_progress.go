@@ -0,0 +1,75 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// queryProgress tracks the progress of the distributed plan of a running
+// query, i.e. the number of rows produced so far by each of its processors.
+type queryProgress struct {
+	mu struct {
+		syncutil.Mutex
+		// diagram is the diagram of the plan. It is nil until the plan starts
+		// running.
+		diagram execinfrapb.FlowDiagram
+		// local tracks the processors of the flow that runs on the gateway.
+		local *execinfra.FlowProgress
+		// remote contains the row counts reported by the processors of the remote
+		// flows, keyed by processor ID.
+		remote map[int32]int64
+	}
+}
+
+// start is called when the plan of the query starts running.
+func (p *queryProgress) start(diagram execinfrapb.FlowDiagram, local *execinfra.FlowProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.diagram = diagram
+	p.mu.local = local
+	p.mu.remote = make(map[int32]int64)
+}
+
+// recordRowsProduced records a row count reported by a processor of a remote
+// flow.
+func (p *queryProgress) recordRowsProduced(rows *execinfrapb.RemoteProducerMetadata_RowsProduced) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mu.remote != nil {
+		p.mu.remote[rows.ProcessorID] = rows.Rows
+	}
+}
+
+// snapshot returns the JSON data and the URL of the diagram of the plan,
+// annotated with the number of rows produced so far by each processor. ok is
+// false if the plan hasn't started running.
+func (p *queryProgress) snapshot() (json string, u url.URL, ok bool, _ error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mu.diagram == nil {
+		return "", url.URL{}, false, nil
+	}
+	rows := make(map[int32]int64, len(p.mu.remote))
+	if p.mu.local != nil {
+		rows = p.mu.local.Rows()
+	}
+	for id, n := range p.mu.remote {
+		rows[id] = n
+	}
+	p.mu.diagram.AddProgress(rows)
+	json, u, err := p.mu.diagram.ToURL()
+	return json, u, err == nil, err
+}
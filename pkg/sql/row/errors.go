@@ -45,6 +45,11 @@ func (f *singleKVFetcher) GetRangesInfo() []roachpb.RangeInfo {
 	panic(errors.AssertionFailedf("GetRangesInfo() called on singleKVFetcher"))
 }
 
+// GetContentionEvents implements the kvBatchFetcher interface.
+func (f *singleKVFetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	return nil
+}
+
 // ConvertBatchError returns a user friendly constraint violation error.
 func ConvertBatchError(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor, b *client.Batch,
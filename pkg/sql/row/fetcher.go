@@ -44,6 +44,9 @@ type kvBatchFetcher interface {
 	nextBatch(ctx context.Context) (ok bool, kvs []roachpb.KeyValue,
 		batchResponse []byte, origSpan roachpb.Span, err error)
 	GetRangesInfo() []roachpb.RangeInfo
+	// GetContentionEvents returns the contention events that the KV layer
+	// reported for the batches fetched so far.
+	GetContentionEvents() []roachpb.ContentionEvent
 }
 
 type tableInfo struct {
@@ -202,6 +205,11 @@ type Fetcher struct {
 	// when beginning a new scan.
 	traceKV bool
 
+	// scanParallelism is the maximum number of KV batches that StartScan issues
+	// concurrently when scanning multiple spans without a limit. See
+	// SetScanParallelism.
+	scanParallelism int
+
 	// -- Fields updated during a scan --
 
 	kvFetcher      *KVFetcher
@@ -463,6 +471,17 @@ func (rf *Fetcher) StartScan(
 	}
 
 	rf.traceKV = traceKV
+	if rf.scanParallelism > 1 && len(spans) > 1 && limitBatches && limitHint == 0 &&
+		txn.Type() == client.LeafTxn {
+		f, err := makeParallelKVBatchFetcher(
+			txn, spans, rf.reverse, limitBatches, 0 /* firstBatchLimit */, rf.returnRangeInfo,
+			rf.scanParallelism,
+		)
+		if err != nil {
+			return err
+		}
+		return rf.StartScanFrom(ctx, &f)
+	}
 	f, err := makeKVBatchFetcher(
 		txn, spans, rf.reverse, limitBatches, rf.firstBatchLimit(limitHint), rf.returnRangeInfo,
 	)
@@ -472,6 +491,18 @@ func (rf *Fetcher) StartScan(
 	return rf.StartScanFrom(ctx, &f)
 }
 
+// SetScanParallelism sets the maximum number of KV batches that StartScan
+// issues concurrently. Batches are only issued concurrently when scanning
+// multiple spans with batch limits but without a limit hint, i.e. when
+// DistSender can't parallelize the scan on its own and the whole scan is
+// expected to be read, and only within leaf transactions, which allow
+// concurrent requests. The spans are then split into up to n groups whose
+// batches are fetched concurrently, while the rows are still returned in the
+// order of the spans.
+func (rf *Fetcher) SetScanParallelism(n int) {
+	rf.scanParallelism = n
+}
+
 // StartInconsistentScan initializes and starts an inconsistent scan, where each
 // KV batch can be read at a different historical timestamp.
 //
@@ -1493,6 +1524,17 @@ func (rf *Fetcher) GetBytesRead() int64 {
 	return f.bytesRead
 }
 
+// GetContentionEvents returns the contention events that the KV layer reported
+// for the batches fetched by the underlying KVFetcher.
+func (rf *Fetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	f := rf.kvFetcher
+	if f == nil {
+		// Not yet initialized.
+		return nil
+	}
+	return f.GetContentionEvents()
+}
+
 // Only unique secondary indexes have extra columns to decode (namely the
 // primary index columns).
 func hasExtraCols(table *tableInfo) bool {
@@ -334,6 +334,96 @@ func TestNextRowBatchLimiting(t *testing.T) {
 	}
 }
 
+// TestNextRowParallelScan checks that a scan of multiple spans within a leaf
+// transaction whose batches are issued concurrently returns the rows in the
+// order of the spans.
+func TestNextRowParallelScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	const nRows = 200
+	sqlutils.CreateTable(
+		t, sqlDB, "foo",
+		"k INT PRIMARY KEY, v INT",
+		nRows,
+		sqlutils.ToRowFn(sqlutils.RowIdxFn, sqlutils.RowModuloFn(7)),
+	)
+	tableDesc := sqlbase.GetImmutableTableDescriptor(kvDB, sqlutils.TestDB, "foo")
+	var valNeededForCol util.FastIntSet
+	valNeededForCol.AddRange(0, 1)
+	args := []initFetcherArgs{
+		{
+			tableDesc:       tableDesc,
+			indexIdx:        0,
+			valNeededForCol: valNeededForCol,
+		},
+	}
+
+	// Scan the first 5 rows of every 10 rows, with small batches so that every
+	// span needs multiple batches.
+	defer SetKVBatchSize(3)()
+	prefix := sqlbase.MakeIndexKeyPrefix(tableDesc.TableDesc(), tableDesc.PrimaryIndex.ID)
+	key := func(k int64) roachpb.Key {
+		return encoding.EncodeVarintAscending(append([]byte(nil), prefix...), k)
+	}
+	var spans roachpb.Spans
+	var expected []int64
+	for k := int64(0); k < nRows; k += 10 {
+		spans = append(spans, roachpb.Span{Key: key(k), EndKey: key(k + 5)})
+		for i := k; i < k+5; i++ {
+			if i >= 1 {
+				expected = append(expected, i)
+			}
+		}
+	}
+
+	rootTxn := client.NewTxn(ctx, kvDB, s.NodeID())
+	leafInputState := rootTxn.GetLeafTxnInputState(ctx)
+	leafTxn := client.NewLeafTxn(ctx, kvDB, s.NodeID(), &leafInputState)
+
+	alloc := &sqlbase.DatumAlloc{}
+	for _, reverse := range []bool{false, true} {
+		for _, parallelism := range []int{1, 3, 8, 100} {
+			t.Run(fmt.Sprintf("reverse=%t/parallelism=%d", reverse, parallelism), func(t *testing.T) {
+				rf, err := initFetcher(args, reverse, alloc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rf.SetScanParallelism(parallelism)
+				if err := rf.StartScan(
+					ctx, leafTxn, spans, true /* limitBatches */, 0 /* limitHint */, false, /* traceKV */
+				); err != nil {
+					t.Fatal(err)
+				}
+				var actual []int64
+				for {
+					datums, _, _, err := rf.NextRowDecoded(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if datums == nil {
+						break
+					}
+					actual = append(actual, int64(*datums[0].(*tree.DInt)))
+				}
+				exp := expected
+				if reverse {
+					exp = make([]int64, len(expected))
+					for i := range expected {
+						exp[len(expected)-i-1] = expected[i]
+					}
+				}
+				if !reflect.DeepEqual(exp, actual) {
+					t.Fatalf("expected rows %v, got %v", exp, actual)
+				}
+			})
+		}
+	}
+}
+
 // Regression test for #29374. Ensure that RowFetcher can handle multi-span
 // fetches where individual batches end in the middle of a multi-column family
 // row with not-null columns.
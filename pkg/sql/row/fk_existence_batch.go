@@ -155,3 +155,8 @@ func (f *SpanKVFetcher) nextBatch(
 func (f *SpanKVFetcher) GetRangesInfo() []roachpb.RangeInfo {
 	panic(errors.AssertionFailedf("GetRangesInfo() called on SpanKVFetcher"))
 }
+
+// GetContentionEvents implements the kvBatchFetcher interface.
+func (f *SpanKVFetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	return nil
+}
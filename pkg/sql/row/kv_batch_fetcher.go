@@ -13,12 +13,14 @@ package row
 import (
 	"bytes"
 	"context"
+	"math/rand"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
 	"github.com/cockroachdb/errors"
 )
 
@@ -28,6 +30,13 @@ import (
 // TODO(radu): parameters like this should be configurable
 var kvBatchSize int64 = 10000
 
+func init() {
+	metamorphic.Register("row.kvBatchSize", func(rng *rand.Rand) interface{} {
+		kvBatchSize = int64(metamorphic.IntInRange(rng, 1, int(kvBatchSize)))
+		return kvBatchSize
+	})
+}
+
 // SetKVBatchSize changes the kvBatchFetcher batch size, and returns a function that restores it.
 func SetKVBatchSize(val int64) func() {
 	oldVal := kvBatchSize
@@ -74,6 +83,10 @@ type txnKVFetcher struct {
 	rangeInfos       []roachpb.RangeInfo
 	origSpan         roachpb.Span
 	remainingBatches [][]byte
+
+	// contentionEvents accumulates the contention events reported by the KV
+	// layer for the batches fetched so far.
+	contentionEvents []roachpb.ContentionEvent
 }
 
 var _ kvBatchFetcher = &txnKVFetcher{}
@@ -85,6 +98,11 @@ func (f *txnKVFetcher) GetRangesInfo() []roachpb.RangeInfo {
 	return f.rangeInfos
 }
 
+// GetContentionEvents implements the kvBatchFetcher interface.
+func (f *txnKVFetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	return f.contentionEvents
+}
+
 // getBatchSize returns the max size of the next batch.
 func (f *txnKVFetcher) getBatchSize() int64 {
 	return f.getBatchSizeForIdx(f.batchIdx)
@@ -146,16 +164,21 @@ func makeKVBatchFetcher(
 	firstBatchLimit int64,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
-	sendFn := func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
+	return makeKVBatchFetcherWithSendFunc(
+		makeTxnSendFunc(txn), spans, reverse, useBatchLimit, firstBatchLimit, returnRangeInfo,
+	)
+}
+
+// makeTxnSendFunc returns a sendFunc that sends batches through the given
+// transaction.
+func makeTxnSendFunc(txn *client.Txn) sendFunc {
+	return func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
 		res, err := txn.Send(ctx, ba)
 		if err != nil {
 			return nil, err.GoError()
 		}
 		return res, nil
 	}
-	return makeKVBatchFetcherWithSendFunc(
-		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, returnRangeInfo,
-	)
 }
 
 // makeKVBatchFetcherWithSendFunc is like makeKVBatchFetcher but uses a custom
@@ -269,6 +292,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	}
 	if br != nil {
 		f.responses = br.Responses
+		f.contentionEvents = append(f.contentionEvents, br.ContentionEvents...)
 	} else {
 		f.responses = nil
 	}
@@ -0,0 +1,174 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package row
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+)
+
+// parallelKVBatchFetcher is a kvBatchFetcher that splits its spans into
+// contiguous groups, each of which is fetched by its own txnKVFetcher, and
+// issues the KV batches of the groups concurrently.
+//
+// The batches are still returned in the order of the spans: all the batches
+// of a group are returned before those of the next group. Whenever the first
+// group runs out of buffered batches, the fetcher fetches the next batch of
+// every group that has run out of buffered batches as well, so that at most
+// one KV batch per group is buffered at any time.
+//
+// Since the batches are sent concurrently, the fetcher can only be used with
+// transactions that allow concurrent requests, i.e. leaf transactions.
+type parallelKVBatchFetcher struct {
+	// groups are the fetchers of the groups of spans that haven't been fully
+	// returned yet, in the order in which they are returned.
+	groups []*txnKVFetcher
+	// done are the fetchers of the groups that have been fully returned. They
+	// are kept around for their range info and contention events.
+	done []*txnKVFetcher
+}
+
+var _ kvBatchFetcher = &parallelKVBatchFetcher{}
+
+// makeParallelKVBatchFetcher initializes a parallelKVBatchFetcher for the
+// given spans. The transaction must be a leaf transaction.
+func makeParallelKVBatchFetcher(
+	txn *client.Txn,
+	spans roachpb.Spans,
+	reverse bool,
+	useBatchLimit bool,
+	firstBatchLimit int64,
+	returnRangeInfo bool,
+	parallelism int,
+) (parallelKVBatchFetcher, error) {
+	return makeParallelKVBatchFetcherWithSendFunc(
+		makeTxnSendFunc(txn), spans, reverse, useBatchLimit, firstBatchLimit, returnRangeInfo,
+		parallelism,
+	)
+}
+
+// makeParallelKVBatchFetcherWithSendFunc creates a parallelKVBatchFetcher that
+// splits the spans into at most parallelism groups. The arguments are
+// otherwise the same as those of makeKVBatchFetcherWithSendFunc.
+func makeParallelKVBatchFetcherWithSendFunc(
+	sendFn sendFunc,
+	spans roachpb.Spans,
+	reverse bool,
+	useBatchLimit bool,
+	firstBatchLimit int64,
+	returnRangeInfo bool,
+	parallelism int,
+) (parallelKVBatchFetcher, error) {
+	if parallelism > len(spans) {
+		parallelism = len(spans)
+	}
+	f := parallelKVBatchFetcher{
+		groups: make([]*txnKVFetcher, 0, parallelism),
+	}
+	for i := 0; i < parallelism; i++ {
+		// Balance the number of spans across the groups.
+		start, end := i*len(spans)/parallelism, (i+1)*len(spans)/parallelism
+		group, err := makeKVBatchFetcherWithSendFunc(
+			sendFn, spans[start:end], reverse, useBatchLimit, firstBatchLimit, returnRangeInfo,
+		)
+		if err != nil {
+			return parallelKVBatchFetcher{}, err
+		}
+		f.groups = append(f.groups, &group)
+	}
+	if reverse {
+		// Reverse scans return the last group first.
+		for i, j := 0, len(f.groups)-1; i < j; i, j = i+1, j-1 {
+			f.groups[i], f.groups[j] = f.groups[j], f.groups[i]
+		}
+	}
+	return f, nil
+}
+
+// hasBufferedBatch returns whether the next call to nextBatch returns a batch
+// that was already fetched.
+func (f *txnKVFetcher) hasBufferedBatch() bool {
+	return len(f.remainingBatches) > 0 || len(f.responses) > 0
+}
+
+// needsFetch returns whether the next call to nextBatch needs to fetch a
+// batch.
+func (f *txnKVFetcher) needsFetch() bool {
+	return !f.hasBufferedBatch() && !f.fetchEnd
+}
+
+// fetch fetches the next batch of every group that needs one, concurrently.
+func (f *parallelKVBatchFetcher) fetch(ctx context.Context) error {
+	g := ctxgroup.WithContext(ctx)
+	for _, group := range f.groups {
+		if !group.needsFetch() {
+			continue
+		}
+		group := group
+		g.GoCtx(func(ctx context.Context) error {
+			return group.fetch(ctx)
+		})
+	}
+	return g.Wait()
+}
+
+// nextBatch is part of the kvBatchFetcher interface.
+func (f *parallelKVBatchFetcher) nextBatch(
+	ctx context.Context,
+) (ok bool, kvs []roachpb.KeyValue, batchResponse []byte, origSpan roachpb.Span, err error) {
+	for len(f.groups) > 0 {
+		group := f.groups[0]
+		if group.hasBufferedBatch() {
+			return group.nextBatch(ctx)
+		}
+		if group.fetchEnd {
+			f.done = append(f.done, group)
+			f.groups = f.groups[1:]
+			continue
+		}
+		if err := f.fetch(ctx); err != nil {
+			return false, nil, nil, roachpb.Span{}, err
+		}
+	}
+	return false, nil, nil, roachpb.Span{}, nil
+}
+
+// GetRangesInfo is part of the kvBatchFetcher interface.
+func (f *parallelKVBatchFetcher) GetRangesInfo() []roachpb.RangeInfo {
+	var rangeInfos []roachpb.RangeInfo
+	f.forEachGroup(func(group *txnKVFetcher) {
+		for _, ri := range group.GetRangesInfo() {
+			rangeInfos = roachpb.InsertRangeInfo(rangeInfos, ri)
+		}
+	})
+	return rangeInfos
+}
+
+// GetContentionEvents is part of the kvBatchFetcher interface.
+func (f *parallelKVBatchFetcher) GetContentionEvents() []roachpb.ContentionEvent {
+	var events []roachpb.ContentionEvent
+	f.forEachGroup(func(group *txnKVFetcher) {
+		events = append(events, group.GetContentionEvents()...)
+	})
+	return events
+}
+
+func (f *parallelKVBatchFetcher) forEachGroup(fn func(*txnKVFetcher)) {
+	for _, group := range f.done {
+		fn(group)
+	}
+	for _, group := range f.groups {
+		fn(group)
+	}
+}
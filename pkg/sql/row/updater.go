@@ -465,18 +465,20 @@ func (ru *Updater) UpdateRow(
 				batch.CPutAllowingIfNotExists(newEntry.Key, &newEntry.Value, expValue)
 			}
 		} else {
-			// Remove all inverted index entries, and re-add them.
-			for j := range ru.oldIndexEntries[i] {
-				if traceKV {
-					log.VEventf(ctx, 2, "Del %s", ru.oldIndexEntries[i][j].Key)
-				}
-				batch.Del(ru.oldIndexEntries[i][j].Key)
-			}
-			putFn := insertInvertedPutFn
-			// We're adding all of the inverted index entries from the row being updated.
-			for j := range ru.newIndexEntries[i] {
-				putFn(ctx, batch, &ru.newIndexEntries[i][j].Key, &ru.newIndexEntries[i][j].Value, traceKV)
-			}
+			// Only remove the inverted index entries of the old document that the
+			// new one doesn't have, and only add the new ones. Documents usually only
+			// change in part, so most entries are left untouched.
+			sqlbase.DiffInvertedIndexEntries(ru.oldIndexEntries[i], ru.newIndexEntries[i],
+				func(entry *sqlbase.IndexEntry) {
+					if traceKV {
+						log.VEventf(ctx, 2, "Del %s", entry.Key)
+					}
+					batch.Del(entry.Key)
+				},
+				func(entry *sqlbase.IndexEntry) {
+					insertInvertedPutFn(ctx, batch, &entry.Key, &entry.Value, traceKV)
+				},
+			)
 		}
 	}
 
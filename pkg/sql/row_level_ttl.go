@@ -0,0 +1,286 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+var rowLevelTTLEnabled = settings.RegisterBoolSetting(
+	"sql.row_level_ttl.enabled",
+	"if set, the expired rows of the tables with a TTL expiration column are deleted periodically",
+	true,
+)
+
+var rowLevelTTLJobInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.row_level_ttl.job_interval",
+	"the interval at which a row-level TTL job is started for each table with a TTL expiration column",
+	5*time.Minute,
+)
+
+var rowLevelTTLDeleteBatchSize = settings.RegisterPositiveIntSetting(
+	"sql.row_level_ttl.delete_batch_size",
+	"the maximum number of rows scanned and deleted by a row-level TTL job in each transaction",
+	500,
+)
+
+var rowLevelTTLDeleteRateLimit = settings.RegisterNonNegativeIntSetting(
+	"sql.row_level_ttl.delete_rate_limit",
+	"the maximum number of expired rows deleted per second by each node running a row-level TTL job "+
+		"(0 = no limit)",
+	1000,
+)
+
+// rowLevelTTLResumer implements the jobs.Resumer interface for row-level TTL
+// jobs, which delete the expired rows of a table.
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &rowLevelTTLResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) Resume(
+	ctx context.Context, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RowLevelTTLDetails)
+
+	// Only the first of the row-level TTL jobs of a table that aren't finished
+	// runs, so that the rows of a table are only ever deleted by one job.
+	running, err := runningRowLevelTTLJobs(ctx, execCfg.InternalExecutor, nil /* txn */)
+	if err != nil {
+		return err
+	}
+	if ids := running[details.TableID]; len(ids) > 0 && ids[0] != *r.job.ID() {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"row-level TTL job %d is already running for table %d", ids[0], details.TableID)
+	}
+
+	evalCtx := p.ExtendedEvalContext()
+	dsp := p.DistSQLPlanner()
+	sv := &execCfg.Settings.SV
+	expireBefore := execCfg.Clock.Now()
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		desc, err := sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		if err != nil {
+			return err
+		}
+		if desc.TTLExpirationColumnID == 0 {
+			return nil
+		}
+		if len(desc.InboundFKs) > 0 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot delete expired rows of table %q which is referenced by foreign keys", desc.Name)
+		}
+
+		spec := execinfrapb.TTLDeleterSpec{
+			Table:         *desc,
+			BatchSize:     rowLevelTTLDeleteBatchSize.Get(sv),
+			RowsPerSecond: rowLevelTTLDeleteRateLimit.Get(sv),
+			ExpireBefore:  expireBefore,
+		}
+		planCtx := dsp.NewPlanningCtx(ctx, evalCtx, txn)
+		plan, numSpans, err := dsp.createTTLDeleter(
+			planCtx, spec, []roachpb.Span{desc.PrimaryIndexSpan()},
+		)
+		if err != nil {
+			return err
+		}
+
+		completedSpans := 0
+		metaFn := func(ctx context.Context, meta *execinfrapb.ProducerMetadata) error {
+			if meta.BulkProcessorProgress == nil {
+				return nil
+			}
+			completedSpans += len(meta.BulkProcessorProgress.CompletedSpans)
+			rows := meta.BulkProcessorProgress.BulkSummary.Rows
+			return r.job.FractionProgressed(ctx,
+				func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+					prog := details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+					prog.RowsDeleted += rows
+					return float32(completedSpans) / float32(numSpans)
+				},
+			)
+		}
+		cbw := metadataCallbackWriter{rowResultWriter: &errOnlyResultWriter{}, fn: metaFn}
+		recv := MakeDistSQLReceiver(
+			ctx,
+			&cbw,
+			tree.Rows, /* stmtType - doesn't matter here since no result are produced */
+			execCfg.RangeDescriptorCache,
+			execCfg.LeaseHolderCache,
+			nil, /* txn - the flow does not run wholly in a txn */
+			func(ts hlc.Timestamp) {
+				_ = execCfg.Clock.Update(ts)
+			},
+			evalCtx.Tracing,
+		)
+		defer recv.Release()
+
+		dsp.Run(
+			planCtx,
+			nil, /* txn - the processors manage their own transactions */
+			&plan, recv, evalCtx,
+			nil, /* finishedSetupFn */
+		)()
+		return cbw.Err()
+	})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnFailOrCancel(context.Context, *client.Txn) error {
+	return nil
+}
+
+// OnSuccess is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnSuccess(context.Context, *client.Txn) error {
+	return nil
+}
+
+// OnTerminal is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnTerminal(context.Context, jobs.Status, chan<- tree.Datums) {}
+
+// runningRowLevelTTLJobs returns the IDs of the pending, running and paused
+// row-level TTL jobs of each table, in the order in which they were created.
+func runningRowLevelTTLJobs(
+	ctx context.Context, ie *InternalExecutor, txn *client.Txn,
+) (map[sqlbase.ID][]int64, error) {
+	const stmt = `SELECT id, payload FROM system.jobs WHERE status IN ($1, $2, $3) ORDER BY created`
+	rows, err := ie.Query(
+		ctx,
+		"get-row-level-ttl-jobs",
+		txn,
+		stmt,
+		jobs.StatusPending,
+		jobs.StatusRunning,
+		jobs.StatusPaused,
+	)
+	if err != nil {
+		return nil, err
+	}
+	running := make(map[sqlbase.ID][]int64)
+	for _, row := range rows {
+		payload, err := jobs.UnmarshalPayload(row[1])
+		if err != nil {
+			return nil, err
+		}
+		if payload.Type() != jobspb.TypeRowLevelTTL {
+			continue
+		}
+		tableID := payload.GetRowLevelTTL().TableID
+		running[tableID] = append(running[tableID], int64(*row[0].(*tree.DInt)))
+	}
+	return running, nil
+}
+
+// StartRowLevelTTLScheduler starts a background worker that periodically
+// starts a row-level TTL job for each table with a TTL expiration column,
+// unless the table already has one that isn't finished.
+func StartRowLevelTTLScheduler(ctx context.Context, stopper *stop.Stopper, execCfg *ExecutorConfig) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		for {
+			timer.Reset(rowLevelTTLJobInterval.Get(&execCfg.Settings.SV))
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-stopper.ShouldStop():
+				return
+			}
+			if !rowLevelTTLEnabled.Get(&execCfg.Settings.SV) ||
+				!cluster.Version.IsActive(ctx, execCfg.Settings, cluster.VersionRowLevelTTL) {
+				continue
+			}
+			if err := scheduleRowLevelTTLJobs(ctx, execCfg); err != nil {
+				log.Warningf(ctx, "failed to schedule row-level TTL jobs: %v", err)
+			}
+		}
+	})
+}
+
+// scheduleRowLevelTTLJobs creates a row-level TTL job for each table with a TTL
+// expiration column that doesn't have one that isn't finished, and starts the
+// jobs. The jobs are created in the transaction that checks for the existing
+// ones, so that the nodes of the cluster that schedule jobs concurrently don't
+// create several jobs for the same table.
+func scheduleRowLevelTTLJobs(ctx context.Context, execCfg *ExecutorConfig) error {
+	var created []*jobs.Job
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		created = created[:0]
+		descs, err := GetAllDescriptors(ctx, txn)
+		if err != nil {
+			return err
+		}
+		var running map[sqlbase.ID][]int64
+		for _, desc := range descs {
+			table, ok := desc.(*sqlbase.TableDescriptor)
+			if !ok || table.TTLExpirationColumnID == 0 || table.Dropped() {
+				continue
+			}
+			if running == nil {
+				if running, err = runningRowLevelTTLJobs(ctx, execCfg.InternalExecutor, txn); err != nil {
+					return err
+				}
+			}
+			if len(running[table.ID]) > 0 {
+				continue
+			}
+			job, err := execCfg.JobRegistry.CreateJobWithTxn(ctx, jobs.Record{
+				Description:   fmt.Sprintf("row-level TTL deletion of the expired rows of table %q", table.Name),
+				Username:      security.NodeUser,
+				DescriptorIDs: sqlbase.IDs{table.ID},
+				Details:       jobspb.RowLevelTTLDetails{TableID: table.ID},
+				Progress:      jobspb.RowLevelTTLProgress{},
+			}, txn)
+			if err != nil {
+				return err
+			}
+			created = append(created, job)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, job := range created {
+		if _, err := execCfg.JobRegistry.StartJob(ctx, nil /* resultsCh */, job); err != nil {
+			log.Warningf(ctx, "failed to start row-level TTL job %d: %v", *job.ID(), err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{job: job}
+	})
+}
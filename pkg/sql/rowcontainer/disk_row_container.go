@@ -104,6 +104,9 @@ func MakeDiskRowContainer(
 	// kept around in d.valueIdxs to have them ready in hot paths.
 	// For composite columns that are specified in d.ordering, the Datum is
 	// encoded both in the key for comparison and in the value for decoding.
+	// The key encoding places the NULLs according to the direction, so for
+	// the columns with the reversed placement of the NULLs the key is prefixed
+	// with a NULL marker encoded in the opposite direction.
 	orderingIdxs := make(map[int]struct{})
 	for _, orderInfo := range d.ordering {
 		orderingIdxs[orderInfo.ColIdx] = struct{}{}
@@ -143,6 +146,9 @@ func (d *DiskRowContainer) AddRow(ctx context.Context, row sqlbase.EncDatumRow)
 
 	for i, orderInfo := range d.ordering {
 		col := orderInfo.ColIdx
+		if orderInfo.NullsReversed() {
+			d.scratchKey = encodeReversedNullMarker(d.scratchKey, orderInfo, row[col].IsNull())
+		}
 		var err error
 		d.scratchKey, err = row[col].Encode(&d.types[col], &d.datumAlloc, d.encodings[i], d.scratchKey)
 		if err != nil {
@@ -243,6 +249,10 @@ func (d *DiskRowContainer) Close(ctx context.Context) {
 // call to keyValToRow().
 func (d *DiskRowContainer) keyValToRow(k []byte, v []byte) (sqlbase.EncDatumRow, error) {
 	for i, orderInfo := range d.ordering {
+		if orderInfo.NullsReversed() {
+			// Skip over the NULL marker.
+			k = k[1:]
+		}
 		// Types with composite key encodings are decoded from the value.
 		if sqlbase.HasCompositeKeyEncoding(d.types[orderInfo.ColIdx].Family()) {
 			// Skip over the encoded key.
@@ -272,6 +282,22 @@ func (d *DiskRowContainer) keyValToRow(k []byte, v []byte) (sqlbase.EncDatumRow,
 	return d.scratchEncRow, nil
 }
 
+// encodeReversedNullMarker appends the marker which places the NULLs of a
+// column with the reversed placement of the NULLs on the requested side. The
+// marker is encoded in the direction opposite to the direction of the column.
+func encodeReversedNullMarker(b []byte, orderInfo sqlbase.ColumnOrderInfo, isNull bool) []byte {
+	if orderInfo.Direction == encoding.Ascending {
+		if isNull {
+			return encoding.EncodeNullDescending(b)
+		}
+		return encoding.EncodeNotNullDescending(b)
+	}
+	if isNull {
+		return encoding.EncodeNullAscending(b)
+	}
+	return encoding.EncodeNotNullAscending(b)
+}
+
 // diskRowIterator iterates over the rows in a DiskRowContainer.
 type diskRowIterator struct {
 	rowContainer *DiskRowContainer
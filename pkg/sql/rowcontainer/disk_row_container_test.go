@@ -98,6 +98,23 @@ func TestDiskRowContainer(t *testing.T) {
 				Direction: encoding.Ascending,
 			},
 		},
+		{
+			sqlbase.ColumnOrderInfo{
+				ColIdx:     0,
+				Direction:  encoding.Ascending,
+				NullsOrder: tree.NullsLast,
+			},
+			sqlbase.ColumnOrderInfo{
+				ColIdx:     2,
+				Direction:  encoding.Descending,
+				NullsOrder: tree.NullsFirst,
+			},
+			sqlbase.ColumnOrderInfo{
+				ColIdx:     1,
+				Direction:  encoding.Descending,
+				NullsOrder: tree.NullsLast,
+			},
+		},
 	}
 
 	rng := rand.New(rand.NewSource(timeutil.Now().UnixNano()))
@@ -54,6 +54,10 @@ type chunkBackfiller interface {
 
 	// flush must be called after the last chunk to finish buffered work.
 	flush(ctx context.Context) error
+
+	// bulkSummary returns the totals of the data ingested so far. It must be
+	// called before close.
+	bulkSummary() roachpb.BulkOpSummary
 }
 
 // backfiller is a processor that implements a distributed backfill of
@@ -132,7 +136,7 @@ func (b *backfiller) doRun(ctx context.Context) *execinfrapb.ProducerMetadata {
 	if err != nil {
 		return &execinfrapb.ProducerMetadata{Err: err}
 	}
-	finishedSpans, err := b.mainLoop(ctx, mutations)
+	finishedSpans, summary, err := b.mainLoop(ctx, mutations)
 	if err != nil {
 		return &execinfrapb.ProducerMetadata{Err: err}
 	}
@@ -152,16 +156,18 @@ func (b *backfiller) doRun(ctx context.Context) *execinfrapb.ProducerMetadata {
 	}
 	var prog execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
 	prog.CompletedSpans = append(prog.CompletedSpans, finishedSpans...)
+	prog.BulkSummary = summary
 	return &execinfrapb.ProducerMetadata{BulkProcessorProgress: &prog}
 }
 
-// mainLoop invokes runChunk on chunks of rows.
+// mainLoop invokes runChunk on chunks of rows and returns the spans that were
+// finished along with the totals of the data ingested.
 // It does not close the output.
 func (b *backfiller) mainLoop(
 	ctx context.Context, mutations []sqlbase.DescriptorMutation,
-) (roachpb.Spans, error) {
+) (roachpb.Spans, roachpb.BulkOpSummary, error) {
 	if err := b.chunks.prepare(ctx); err != nil {
-		return nil, err
+		return nil, roachpb.BulkOpSummary{}, err
 	}
 	defer b.chunks.close(ctx)
 
@@ -195,7 +201,7 @@ func (b *backfiller) mainLoop(
 			var err error
 			todo.Key, err = b.chunks.runChunk(ctx, mutations, todo, b.spec.ChunkSize, b.spec.ReadAsOf)
 			if err != nil {
-				return nil, err
+				return nil, roachpb.BulkOpSummary{}, err
 			}
 			chunks++
 			running := timeutil.Since(start)
@@ -224,12 +230,13 @@ func (b *backfiller) mainLoop(
 
 	log.VEventf(ctx, 3, "%s backfiller flushing...", b.name)
 	if err := b.chunks.flush(ctx); err != nil {
-		return nil, err
+		return nil, roachpb.BulkOpSummary{}, err
 	}
-	log.VEventf(ctx, 2, "%s backfiller finished %d spans in %d chunks in %s",
-		b.name, totalSpans, totalChunks, timeutil.Since(start))
+	summary := b.chunks.bulkSummary()
+	log.VEventf(ctx, 2, "%s backfiller finished %d spans in %d chunks in %s, ingesting %d bytes",
+		b.name, totalSpans, totalChunks, timeutil.Since(start), summary.DataSize)
 
-	return finishedSpans, nil
+	return finishedSpans, summary, nil
 }
 
 // GetResumeSpans returns a ResumeSpanList from a job.
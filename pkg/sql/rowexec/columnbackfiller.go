@@ -77,6 +77,11 @@ func (cb *columnBackfiller) flush(ctx context.Context) error {
 func (cb *columnBackfiller) CurrentBufferFill() float32 {
 	return 0
 }
+func (cb *columnBackfiller) bulkSummary() roachpb.BulkOpSummary {
+	// The column backfiller writes through regular transactions rather than
+	// ingesting data.
+	return roachpb.BulkOpSummary{}
+}
 
 // runChunk implements the chunkBackfiller interface.
 func (cb *columnBackfiller) runChunk(
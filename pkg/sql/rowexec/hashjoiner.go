@@ -91,6 +91,17 @@ type hashJoiner struct {
 	// INTERSECT and EXCEPT.
 	nullEquality bool
 
+	// rejectOnNull indicates that this is a LEFT ANTI join with the semantics of
+	// NOT IN: no rows are emitted if the right side has a NULL in its equality
+	// column, and the left rows with a NULL in their equality column are only
+	// emitted if the right side is empty. The right side is always stored in
+	// this mode.
+	rejectOnNull bool
+	// storedSideNonEmpty and rightHasNull are only used when rejectOnNull is
+	// set.
+	storedSideNonEmpty bool
+	rightHasNull       bool
+
 	useTempStorage bool
 	storedRows     rowcontainer.HashRowContainer
 
@@ -181,9 +192,9 @@ func newHashJoiner(
 		// The hashJoiner will overflow to disk if this limit is not enough.
 		limit := h.FlowCtx.Cfg.TestingKnobs.MemoryLimitBytes
 		if limit <= 0 {
-			limit = execinfra.SettingWorkMemBytes.Get(&st.SV)
+			limit = execinfra.SettingWorkMemBytes.GetWithOverrides(&st.SV, flowCtx.SettingOverrides())
 		}
-		h.MemMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "hashjoiner-limited")
+		h.MemMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx, "hashjoiner-limited")
 		h.diskMonitor = execinfra.NewMonitor(ctx, flowCtx.Cfg.DiskMonitor, "hashjoiner-disk")
 		// Override initialBufferSize to be half of this processor's memory
 		// limit. We consume up to h.initialBufferSize bytes from each input
@@ -207,9 +218,16 @@ func newHashJoiner(
 		nil /* ordering */, h.rightSource.OutputTypes(), h.EvalCtx, h.MemMonitor, 0, /* rowCapacity */
 	)
 
+	h.nullEquality = spec.NullEquality
 	if h.joinType == sqlbase.IntersectAllJoin || h.joinType == sqlbase.ExceptAllJoin {
 		h.nullEquality = true
 	}
+	if spec.RejectOnNull {
+		if err := validateRejectOnNull(h.joinType, len(spec.LeftEqColumns), h.nullEquality); err != nil {
+			return nil, err
+		}
+		h.rejectOnNull = true
+	}
 
 	return h, nil
 }
@@ -277,6 +295,11 @@ func (h *hashJoiner) build() (hashJoinerState, sqlbase.EncDatumRow, *execinfrapb
 		return hjConsumingStoredSide, nil, nil
 	}
 
+	if h.rejectOnNull {
+		// Whether any left row is emitted depends on the whole right side, so
+		// the right side must be fully consumed before probing.
+		return setStoredSideTransition(rightSide)
+	}
 	if h.forcedStoredSide != nil {
 		return setStoredSideTransition(*h.forcedStoredSide)
 	}
@@ -378,6 +401,13 @@ func (h *hashJoiner) consumeStoredSide() (
 			return hjConsumingStoredSide, row, nil
 		}
 
+		if h.rightHasNull {
+			// A NULL on the right side means that NOT IN is never true, so no
+			// rows can be emitted.
+			h.MoveToDraining(nil /* err */)
+			return hjStateUnknown, nil, h.DrainHelper()
+		}
+
 		if row == nil {
 			// The stored side has been fully consumed, move on to hjReadingProbeSide.
 			// If storedRows is in-memory, pre-reserve the memory needed to mark.
@@ -405,6 +435,7 @@ func (h *hashJoiner) consumeStoredSide() (
 			h.MoveToDraining(err)
 			return hjStateUnknown, nil, h.DrainHelper()
 		}
+		h.storedSideNonEmpty = true
 	}
 }
 
@@ -712,6 +743,18 @@ func (h *hashJoiner) receiveNext(
 			return row, nil, false, nil
 		}
 
+		if h.rejectOnNull {
+			if side == rightSide {
+				// The caller will stop the join once it sees rightHasNull.
+				h.rightHasNull = true
+				return nil, nil, false, nil
+			}
+			if h.storedSideNonEmpty {
+				// NULL NOT IN (non-empty set) is never true.
+				continue
+			}
+		}
+
 		if renderedRow, shouldEmit := h.shouldEmitUnmatched(row, side); shouldEmit {
 			return renderedRow, nil, true, nil
 		}
@@ -100,6 +100,7 @@ func TestHashJoiner(t *testing.T) {
 					RightEqColumns: c.rightEqCols,
 					Type:           c.joinType,
 					OnExpr:         c.onExpr,
+					RejectOnNull:   c.rejectOnNull,
 				}
 				h, err := newHashJoiner(&flowCtx, 0 /* processorID */, spec, leftInput, rightInput, &post, out)
 				if err != nil {
@@ -117,6 +117,10 @@ func (ib *indexBackfiller) CurrentBufferFill() float32 {
 	return ib.adder.CurrentBufferFill()
 }
 
+func (ib *indexBackfiller) bulkSummary() roachpb.BulkOpSummary {
+	return ib.adder.GetSummary()
+}
+
 func (ib *indexBackfiller) wrapDupError(ctx context.Context, orig error) error {
 	if orig == nil {
 		return nil
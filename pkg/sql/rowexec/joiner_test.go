@@ -19,16 +19,17 @@ import (
 )
 
 type joinerTestCase struct {
-	leftEqCols  []uint32
-	rightEqCols []uint32
-	joinType    sqlbase.JoinType
-	onExpr      execinfrapb.Expression
-	outCols     []uint32
-	leftTypes   []types.T
-	leftInput   sqlbase.EncDatumRows
-	rightTypes  []types.T
-	rightInput  sqlbase.EncDatumRows
-	expected    sqlbase.EncDatumRows
+	leftEqCols   []uint32
+	rightEqCols  []uint32
+	joinType     sqlbase.JoinType
+	onExpr       execinfrapb.Expression
+	rejectOnNull bool
+	outCols      []uint32
+	leftTypes    []types.T
+	leftInput    sqlbase.EncDatumRows
+	rightTypes   []types.T
+	rightInput   sqlbase.EncDatumRows
+	expected     sqlbase.EncDatumRows
 }
 
 func joinerTestCases() []joinerTestCase {
@@ -810,6 +811,77 @@ func joinerTestCases() []joinerTestCase {
 				{v[2], v[2]},
 			},
 		},
+		{
+			// Ensure that anti-joins that reject on NULL drop the left rows
+			// with NULLs when the right input is not empty.
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			joinType:     sqlbase.LeftAntiJoin,
+			rejectOnNull: true,
+			outCols:      []uint32{0, 1},
+			leftTypes:    sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[0]},
+				{v[1], v[1]},
+				{v[2], v[2]},
+				{v[3], v[3]},
+			},
+			rightTypes: sqlbase.TwoIntCols,
+			rightInput: sqlbase.EncDatumRows{
+				{v[2], v[4]},
+				{v[4], v[4]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[1]},
+				{v[3], v[3]},
+			},
+		},
+		{
+			// Ensure that anti-joins that reject on NULL emit no rows when the
+			// right input has a NULL.
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			joinType:     sqlbase.LeftAntiJoin,
+			rejectOnNull: true,
+			outCols:      []uint32{0, 1},
+			leftTypes:    sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[0]},
+				{v[1], v[1]},
+				{v[2], v[2]},
+				{v[3], v[3]},
+			},
+			rightTypes: sqlbase.TwoIntCols,
+			rightInput: sqlbase.EncDatumRows{
+				{null, v[4]},
+				{v[2], v[4]},
+			},
+			expected: sqlbase.EncDatumRows{},
+		},
+		{
+			// Ensure that anti-joins that reject on NULL emit all the left rows
+			// (including the ones with NULLs) when the right input is empty.
+			leftEqCols:   []uint32{0},
+			rightEqCols:  []uint32{0},
+			joinType:     sqlbase.LeftAntiJoin,
+			rejectOnNull: true,
+			outCols:      []uint32{0, 1},
+			leftTypes:    sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[0]},
+				{v[1], v[1]},
+				{v[2], v[2]},
+				{v[3], v[3]},
+			},
+			rightTypes: sqlbase.TwoIntCols,
+			rightInput: sqlbase.EncDatumRows{},
+			expected: sqlbase.EncDatumRows{
+				{null, v[0]},
+				{v[1], v[1]},
+				{v[2], v[2]},
+				{v[3], v[3]},
+			},
+		},
 	}
 
 	return testCases
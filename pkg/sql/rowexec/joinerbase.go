@@ -189,6 +189,22 @@ func shouldEmitUnmatchedRow(side joinSide, joinType sqlbase.JoinType) bool {
 	}
 }
 
+// validateRejectOnNull checks that the reject on null mode (which gives an
+// anti join the semantics of NOT IN) is only requested for LEFT ANTI joins on
+// a single pair of equality columns that do not consider NULLs equal.
+func validateRejectOnNull(joinType sqlbase.JoinType, numEqCols int, nullEquality bool) error {
+	if joinType != sqlbase.LeftAntiJoin {
+		return errors.Errorf("reject on null is only supported for LEFT ANTI joins, got %s", joinType)
+	}
+	if numEqCols != 1 {
+		return errors.Errorf("reject on null requires exactly one equality column, got %d", numEqCols)
+	}
+	if nullEquality {
+		return errors.Errorf("reject on null is incompatible with null equality")
+	}
+	return nil
+}
+
 // render constructs a row with columns from both sides. The ON condition is
 // evaluated; if it fails, returns nil.
 // Note the left and right merged equality columns (i.e. from a USING clause
@@ -230,9 +230,9 @@ func newJoinReader(
 		// joinReader will overflow to disk if this limit is not enough.
 		limit := flowCtx.Cfg.TestingKnobs.MemoryLimitBytes
 		if limit <= 0 {
-			limit = execinfra.SettingWorkMemBytes.Get(&st.SV)
+			limit = execinfra.SettingWorkMemBytes.GetWithOverrides(&st.SV, flowCtx.SettingOverrides())
 		}
-		jr.MemMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "joiner-limited")
+		jr.MemMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx, "joiner-limited")
 		jr.diskMonitor = execinfra.NewMonitor(ctx, flowCtx.Cfg.DiskMonitor, "joinreader-disk")
 		drc := rowcontainer.NewDiskBackedIndexedRowContainer(
 			nil, /* ordering */
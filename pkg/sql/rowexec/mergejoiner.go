@@ -40,6 +40,16 @@ type mergeJoiner struct {
 	matchedRight            util.FastIntSet
 	matchedRightCount       int
 
+	// rejectOnNull indicates that this is a LEFT ANTI join with the semantics of
+	// NOT IN (see MergeJoinerSpec.RejectOnNull). Since the right ordering is
+	// ascending, the first right row tells whether the right side is empty or
+	// has a NULL, which is checked before any row is emitted.
+	rejectOnNull struct {
+		enabled    bool
+		checked    bool
+		rightEmpty bool
+	}
+
 	streamMerger streamMerger
 }
 
@@ -61,7 +71,7 @@ func newMergeJoiner(
 	leftEqCols := make([]uint32, 0, len(spec.LeftOrdering.Columns))
 	rightEqCols := make([]uint32, 0, len(spec.RightOrdering.Columns))
 	for i, c := range spec.LeftOrdering.Columns {
-		if spec.RightOrdering.Columns[i].Direction != c.Direction {
+		if r := spec.RightOrdering.Columns[i]; r.Direction != c.Direction || r.NullsOrder != c.NullsOrder {
 			return nil, errors.New("unmatched column orderings")
 		}
 		leftEqCols = append(leftEqCols, c.ColIdx)
@@ -72,6 +82,15 @@ func newMergeJoiner(
 		leftSource:  leftSource,
 		rightSource: rightSource,
 	}
+	if spec.RejectOnNull {
+		if err := validateRejectOnNull(spec.Type, len(leftEqCols), spec.NullEquality); err != nil {
+			return nil, err
+		}
+		if c := spec.RightOrdering.Columns[0]; c.Direction != execinfrapb.Ordering_Column_ASC || c.NullsReversed() {
+			return nil, errors.New("reject on null requires an ascending ordering with NULLs first")
+		}
+		m.rejectOnNull.enabled = true
+	}
 
 	if sp := opentracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && tracing.IsRecording(sp) {
 		m.leftSource = newInputStatCollector(m.leftSource)
@@ -146,6 +165,24 @@ func (m *mergeJoiner) nextRow() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetad
 	// batch of rows from the left and right side of the join. The state machine
 	// returns a result for every row that should be output.
 
+	if m.rejectOnNull.enabled && !m.rejectOnNull.checked {
+		// Peek at the first right group, which is left for the stream merger to
+		// return in its first batch.
+		var meta *execinfrapb.ProducerMetadata
+		m.streamMerger.rightGroup, meta = m.streamMerger.right.nextGroup(m.Ctx, m.EvalCtx)
+		if meta != nil {
+			return nil, meta
+		}
+		m.rejectOnNull.checked = true
+		if len(m.streamMerger.rightGroup) == 0 {
+			m.rejectOnNull.rightEmpty = true
+		} else if m.streamMerger.rightGroup[0][m.eqCols[rightSide][0]].IsNull() {
+			// NULLs sort first, so the right side has a NULL and NOT IN is never
+			// true.
+			return nil, nil
+		}
+	}
+
 	for {
 		for m.leftIdx < len(m.leftRows) {
 			// We have unprocessed rows from the left-side batch.
@@ -196,7 +233,8 @@ func (m *mergeJoiner) nextRow() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetad
 			// If we didn't match any rows on the right-side of the batch and this is
 			// a left outer join, full outer join, anti join, or EXCEPT ALL, emit an
 			// unmatched left-side row.
-			if m.matchedRightCount == 0 && shouldEmitUnmatchedRow(leftSide, m.joinType) {
+			if m.matchedRightCount == 0 && shouldEmitUnmatchedRow(leftSide, m.joinType) &&
+				!m.rejectsUnmatchedLeftRow(lrow) {
 				return m.renderUnmatchedRow(lrow, leftSide), nil
 			}
 
@@ -238,6 +276,14 @@ func (m *mergeJoiner) nextRow() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetad
 	}
 }
 
+// rejectsUnmatchedLeftRow returns whether an unmatched left row must not be
+// emitted because of the reject on null mode: NULL NOT IN (non-empty set) is
+// never true.
+func (m *mergeJoiner) rejectsUnmatchedLeftRow(lrow sqlbase.EncDatumRow) bool {
+	return m.rejectOnNull.enabled && !m.rejectOnNull.rightEmpty &&
+		lrow[m.eqCols[leftSide][0]].IsNull()
+}
+
 func (m *mergeJoiner) close() {
 	if m.InternalClose() {
 		ctx := m.Ctx
@@ -630,6 +630,99 @@ func TestMergeJoiner(t *testing.T) {
 				{null, v[2]},
 			},
 		},
+		{
+			// Ensure that an anti-join that rejects on NULL doesn't emit the left
+			// rows with NULLs when the right input is not empty.
+			spec: execinfrapb.MergeJoinerSpec{
+				LeftOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				RightOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				Type:         sqlbase.LeftAntiJoin,
+				RejectOnNull: true,
+			},
+			outCols:   []uint32{0, 1},
+			leftTypes: sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[2]},
+				{v[1], v[3]},
+				{v[2], v[3]},
+			},
+			rightTypes: sqlbase.TwoIntCols,
+			rightInput: sqlbase.EncDatumRows{
+				{v[2], v[4]},
+				{v[2], v[5]},
+			},
+			expectedTypes: sqlbase.TwoIntCols,
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[3]},
+			},
+		},
+		{
+			// Ensure that an anti-join that rejects on NULL emits no rows when
+			// the right input has a NULL.
+			spec: execinfrapb.MergeJoinerSpec{
+				LeftOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				RightOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				Type:         sqlbase.LeftAntiJoin,
+				RejectOnNull: true,
+			},
+			outCols:   []uint32{0, 1},
+			leftTypes: sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[2]},
+				{v[1], v[3]},
+				{v[2], v[3]},
+			},
+			rightTypes: sqlbase.TwoIntCols,
+			rightInput: sqlbase.EncDatumRows{
+				{null, v[3]},
+				{v[2], v[4]},
+			},
+			expectedTypes: sqlbase.TwoIntCols,
+			expected:      sqlbase.EncDatumRows{},
+		},
+		{
+			// Ensure that an anti-join that rejects on NULL emits all the left
+			// rows when the right input is empty.
+			spec: execinfrapb.MergeJoinerSpec{
+				LeftOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				RightOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: encoding.Ascending},
+					}),
+				Type:         sqlbase.LeftAntiJoin,
+				RejectOnNull: true,
+			},
+			outCols:   []uint32{0, 1},
+			leftTypes: sqlbase.TwoIntCols,
+			leftInput: sqlbase.EncDatumRows{
+				{null, v[2]},
+				{v[1], v[3]},
+				{v[2], v[3]},
+			},
+			rightTypes:    sqlbase.TwoIntCols,
+			rightInput:    sqlbase.EncDatumRows{},
+			expectedTypes: sqlbase.TwoIntCols,
+			expected: sqlbase.EncDatumRows{
+				{null, v[2]},
+				{v[1], v[3]},
+				{v[2], v[3]},
+			},
+		},
 		{
 			// Ensure that OnExprs are satisfied for semi-joins.
 			spec: execinfrapb.MergeJoinerSpec{
@@ -248,6 +248,12 @@ func NewProcessor(
 		}
 		return newBulkRowWriterProcessor(flowCtx, processorID, *core.BulkRowWriter, inputs[0], outputs[0])
 	}
+	if core.TTLDeleter != nil {
+		if err := checkNumInOut(inputs, outputs, 0, 1); err != nil {
+			return nil, err
+		}
+		return newTTLDeleter(flowCtx, processorID, *core.TTLDeleter, outputs[0])
+	}
 	if core.MetadataTestSender != nil {
 		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
 			return nil, err
@@ -48,6 +48,7 @@ type rowFetcher interface {
 	Reset()
 	GetBytesRead() int64
 	GetRangesInfo() []roachpb.RangeInfo
+	GetContentionEvents() []roachpb.ContentionEvent
 	NextRowWithErrors(context.Context) (sqlbase.EncDatumRow, error)
 }
 
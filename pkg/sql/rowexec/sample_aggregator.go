@@ -58,6 +58,7 @@ type sampleAggregator struct {
 }
 
 var _ execinfra.Processor = &sampleAggregator{}
+var _ execinfra.RowSource = &sampleAggregator{}
 
 const sampleAggregatorProcName = "sample aggregator"
 
@@ -92,7 +93,7 @@ func newSampleAggregator(
 	// Limit the memory use by creating a child monitor with a hard limit.
 	// The processor will disable histogram collection if this limit is not
 	// enough.
-	memMonitor := execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "sample-aggregator-mem")
+	memMonitor := execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx, "sample-aggregator-mem")
 	rankCol := len(input.OutputTypes()) - 5
 	s := &sampleAggregator{
 		spec:         spec,
@@ -125,8 +126,9 @@ func newSampleAggregator(
 	s.sr.Init(int(spec.SampleSize), input.OutputTypes()[:rankCol], &s.memAcc, sampleCols)
 
 	if err := s.Init(
-		nil, post, []types.T{}, flowCtx, processorID, output, memMonitor,
+		s, post, []types.T{}, flowCtx, processorID, output, memMonitor,
 		execinfra.ProcStateOpts{
+			InputsToDrain: []execinfra.RowSource{input},
 			TrailingMetaCallback: func(context.Context) []execinfrapb.ProducerMetadata {
 				s.close()
 				return nil
@@ -138,24 +140,26 @@ func newSampleAggregator(
 	return s, nil
 }
 
-func (s *sampleAggregator) pushTrailingMeta(ctx context.Context) {
-	execinfra.SendTraceData(ctx, s.Out.Output())
+// Start is part of the RowSource interface.
+func (s *sampleAggregator) Start(ctx context.Context) context.Context {
+	s.input.Start(ctx)
+	return s.StartInternal(ctx, sampleAggregatorProcName)
 }
 
-// Run is part of the Processor interface.
-func (s *sampleAggregator) Run(ctx context.Context) {
-	s.input.Start(ctx)
-	s.StartInternal(ctx, sampleAggregatorProcName)
-
-	earlyExit, err := s.mainLoop(s.Ctx)
-	if err != nil {
-		execinfra.DrainAndClose(s.Ctx, s.Out.Output(), err, s.pushTrailingMeta, s.input)
-	} else if !earlyExit {
-		s.pushTrailingMeta(s.Ctx)
-		s.input.ConsumerClosed()
-		s.Out.Close()
+// Next is part of the RowSource interface. The sample aggregator doesn't
+// produce any rows: the first call consumes the whole input and writes the
+// statistics, and then only the metadata is returned.
+func (s *sampleAggregator) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	if s.State == execinfra.StateRunning {
+		s.MoveToDraining(s.mainLoop(s.Ctx))
 	}
-	s.MoveToDraining(nil /* err */)
+	return nil, s.DrainHelper()
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (s *sampleAggregator) ConsumerClosed() {
+	// The consumer is done, Next() will not be called again.
+	s.close()
 }
 
 func (s *sampleAggregator) close() {
@@ -165,14 +169,15 @@ func (s *sampleAggregator) close() {
 	}
 }
 
-func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, err error) {
+func (s *sampleAggregator) mainLoop(ctx context.Context) error {
 	var job *jobs.Job
 	jobID := s.spec.JobID
 	// Some tests run this code without a job, so check if the jobID is 0.
 	if jobID != 0 {
+		var err error
 		job, err = s.FlowCtx.Cfg.JobRegistry.LoadJob(ctx, s.spec.JobID)
 		if err != nil {
-			return false, err
+			return err
 		}
 	}
 
@@ -215,7 +220,7 @@ func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, err er
 					}
 
 					if err := progFn(fractionCompleted); err != nil {
-						return false, err
+						return err
 					}
 				}
 				if meta.SamplerProgress.HistogramDisabled {
@@ -224,9 +229,12 @@ func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, err er
 					// don't create a biased histogram.
 					s.sr.Disable()
 				}
-			} else if !emitHelper(ctx, &s.Out, nil /* row */, meta, s.pushTrailingMeta, s.input) {
-				// No cleanup required; emitHelper() took care of it.
-				return true, nil
+			} else if meta.Err != nil {
+				return meta.Err
+			} else {
+				// Pass the other metadata through once the input has been
+				// consumed.
+				s.AppendTrailingMeta(*meta)
 			}
 			continue
 		}
@@ -242,12 +250,12 @@ func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, err er
 			// This must be a sampled row.
 			rank, err := row[s.rankCol].GetInt()
 			if err != nil {
-				return false, errors.NewAssertionErrorWithWrappedErrf(err, "decoding rank column")
+				return errors.NewAssertionErrorWithWrappedErrf(err, "decoding rank column")
 			}
 			// Retain the rows with the top ranks.
 			if err := s.sr.SampleRow(ctx, s.EvalCtx, row[:s.rankCol], uint64(rank)); err != nil {
 				if code := pgerror.GetPGCode(err); code != pgcode.OutOfMemory {
-					return false, err
+					return err
 				}
 				// We hit an out of memory error. Clear the sample reservoir and
 				// disable histogram sample collection.
@@ -259,45 +267,45 @@ func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, err er
 		// This is a sketch row.
 		sketchIdx, err := row[s.sketchIdxCol].GetInt()
 		if err != nil {
-			return false, err
+			return err
 		}
 		if sketchIdx < 0 || sketchIdx > int64(len(s.sketches)) {
-			return false, errors.Errorf("invalid sketch index %d", sketchIdx)
+			return errors.Errorf("invalid sketch index %d", sketchIdx)
 		}
 
 		numRows, err := row[s.numRowsCol].GetInt()
 		if err != nil {
-			return false, err
+			return err
 		}
 		s.sketches[sketchIdx].numRows += numRows
 
 		numNulls, err := row[s.numNullsCol].GetInt()
 		if err != nil {
-			return false, err
+			return err
 		}
 		s.sketches[sketchIdx].numNulls += numNulls
 
 		// Decode the sketch.
 		if err := row[s.sketchCol].EnsureDecoded(&s.inTypes[s.sketchCol], &da); err != nil {
-			return false, err
+			return err
 		}
 		d := row[s.sketchCol].Datum
 		if d == tree.DNull {
-			return false, errors.AssertionFailedf("NULL sketch data")
+			return errors.AssertionFailedf("NULL sketch data")
 		}
 		if err := tmpSketch.UnmarshalBinary([]byte(*d.(*tree.DBytes))); err != nil {
-			return false, err
+			return err
 		}
 		if err := s.sketches[sketchIdx].sketch.Merge(&tmpSketch); err != nil {
-			return false, errors.NewAssertionErrorWithWrappedErrf(err, "merging sketch data")
+			return errors.NewAssertionErrorWithWrappedErrf(err, "merging sketch data")
 		}
 	}
 	// Report progress one last time so we don't write results if the job was
 	// canceled.
-	if err = progFn(1.0); err != nil {
-		return false, err
+	if err := progFn(1.0); err != nil {
+		return err
 	}
-	return false, s.writeResults(ctx)
+	return s.writeResults(ctx)
 }
 
 // writeResults inserts the new statistics into system.table_statistics.
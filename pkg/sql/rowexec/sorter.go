@@ -64,7 +64,7 @@ func (s *sorterBase) init(
 	if useTempStorage {
 		// Limit the memory use by creating a child monitor with a hard limit.
 		// The processor will overflow to disk if this limit is not enough.
-		memMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "sortall-limited")
+		memMonitor = execinfra.NewLimitedMonitor(ctx, flowCtx.EvalCtx.Mon, flowCtx, "sortall-limited")
 	} else {
 		memMonitor = execinfra.NewMonitor(ctx, flowCtx.EvalCtx.Mon, "sorter-mem")
 	}
@@ -222,10 +222,11 @@ func newSorter(
 	// ordering in order to avoid loading all the rows into memory. If we're
 	// scanning an index with a prefix matching an ordering prefix, we can only
 	// accumulate values for equal fields in this prefix, sort the accumulated
-	// chunk and then output.
-	// TODO(irfansharif): Add optimization for case where both ordering match
-	// length and limit is specified.
-	return newSortChunksProcessor(flowCtx, processorID, spec, input, post, output)
+	// chunk and then output. If a limit is specified as well, we stop reading
+	// the input once the chunks that contain the first count rows have been
+	// sorted, and only keep the rows of the last of those chunks that can be
+	// part of the output.
+	return newSortChunksProcessor(flowCtx, processorID, spec, input, post, output, count)
 }
 
 // sortAllProcessor reads in all values into the wrapped rows and
@@ -457,6 +458,13 @@ type sortChunksProcessor struct {
 	// sortChunksProcessor accumulates rows that are equal on a prefix, until it
 	// encounters a row that is greater. It stores that greater row in nextChunkRow
 	nextChunkRow sqlbase.EncDatumRow
+
+	// count is the number of rows the processor needs to produce, or 0 if
+	// there is no limit.
+	count uint64
+	// emitted is the number of rows of the chunks that have been sorted so far.
+	// Once it reaches count, no more chunks are read from the input.
+	emitted uint64
 }
 
 var _ execinfra.Processor = &sortChunksProcessor{}
@@ -471,10 +479,11 @@ func newSortChunksProcessor(
 	input execinfra.RowSource,
 	post *execinfrapb.PostProcessSpec,
 	out execinfra.RowReceiver,
+	count uint64,
 ) (execinfra.Processor, error) {
 	ordering := execinfrapb.ConvertToColumnOrdering(spec.OutputOrdering)
 
-	proc := &sortChunksProcessor{}
+	proc := &sortChunksProcessor{count: count}
 	if err := proc.sorterBase.init(
 		proc, flowCtx, processorID, input, post, out, ordering, spec.OrderingMatchLen,
 		execinfra.ProcStateOpts{
@@ -507,7 +516,9 @@ func (s *sortChunksProcessor) chunkCompleted(
 	return false, nil
 }
 
-// fill one chunk of rows from the input and sort them.
+// fill one chunk of rows from the input and sort them. If there is a limit,
+// only the rows of the chunk that can be part of the output are kept, using a
+// max-heap like the sortTopKProcessor does.
 //
 // Metadata is buffered in s.trailingMeta. Returns true if a valid chunk of rows
 // has been read and sorted, false otherwise (if the input had no more rows or
@@ -515,6 +526,12 @@ func (s *sortChunksProcessor) chunkCompleted(
 // this returns false.
 func (s *sortChunksProcessor) fill() (bool, error) {
 	ctx := s.Ctx
+	// k is the number of rows of the chunk that can be part of the output.
+	k := uint64(math.MaxUint64)
+	if s.count != 0 {
+		k = s.count - s.emitted
+	}
+	heapCreated := false
 
 	var meta *execinfrapb.ProducerMetadata
 
@@ -566,12 +583,26 @@ func (s *sortChunksProcessor) fill() (bool, error) {
 			break
 		}
 
-		if err := s.rows.AddRow(ctx, nextChunkRow); err != nil {
+		if uint64(s.rows.Len()) < k {
+			if err := s.rows.AddRow(ctx, nextChunkRow); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if !heapCreated {
+			// Arrange the k rows into a max-heap.
+			s.rows.InitTopK()
+			heapCreated = true
+		}
+		// Replace the max row if the new row is smaller, maintaining the
+		// max-heap.
+		if err := s.rows.MaybeReplaceMax(ctx, nextChunkRow); err != nil {
 			return false, err
 		}
 	}
 
 	s.rows.Sort(ctx)
+	s.emitted += uint64(s.rows.Len())
 
 	return true, nil
 }
@@ -593,6 +624,12 @@ func (s *sortChunksProcessor) Next() (sqlbase.EncDatumRow, *execinfrapb.Producer
 		}
 		// If we don't have an active chunk, clear and refill it.
 		if !ok {
+			if s.count != 0 && s.emitted >= s.count {
+				// The chunks sorted so far contain all the rows we need to produce,
+				// so there is no need to read the rest of the input.
+				s.MoveToDraining(nil /* err */)
+				break
+			}
 			if err := s.rows.UnsafeReset(ctx); err != nil {
 				s.MoveToDraining(err)
 				break
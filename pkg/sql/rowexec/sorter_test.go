@@ -41,6 +41,8 @@ func TestSorter(t *testing.T) {
 		v[i] = sqlbase.IntEncDatum(i)
 	}
 
+	null := sqlbase.NullEncDatum()
+
 	asc := encoding.Ascending
 	desc := encoding.Descending
 
@@ -201,6 +203,61 @@ func TestSorter(t *testing.T) {
 				{v[4], v[4], v[4]},
 				{v[4], v[4], v[5]},
 			},
+		}, {
+			name: "SortMatchOrderingLimit",
+			// Specified match ordering length and limit. Only the smallest row of
+			// the third chunk is part of the output.
+			spec: execinfrapb.SorterSpec{
+				OrderingMatchLen: 2,
+				OutputOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: asc},
+						{ColIdx: 1, Direction: asc},
+						{ColIdx: 2, Direction: asc},
+					}),
+			},
+			post:  execinfrapb.PostProcessSpec{Limit: 4},
+			types: sqlbase.ThreeIntCols,
+			input: sqlbase.EncDatumRows{
+				{v[0], v[1], v[2]},
+				{v[0], v[1], v[0]},
+				{v[1], v[0], v[5]},
+				{v[1], v[1], v[5]},
+				{v[1], v[1], v[3]},
+				{v[1], v[1], v[4]},
+				{v[3], v[4], v[3]},
+				{v[3], v[4], v[2]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], v[1], v[0]},
+				{v[0], v[1], v[2]},
+				{v[1], v[0], v[5]},
+				{v[1], v[1], v[3]},
+			},
+		}, {
+			name: "SortMatchOrderingOffsetLimit",
+			// Specified match ordering length, offset and limit.
+			spec: execinfrapb.SorterSpec{
+				OrderingMatchLen: 1,
+				OutputOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: asc},
+						{ColIdx: 1, Direction: desc},
+					}),
+			},
+			post:  execinfrapb.PostProcessSpec{Offset: 1, Limit: 2},
+			types: sqlbase.TwoIntCols,
+			input: sqlbase.EncDatumRows{
+				{v[0], v[1]},
+				{v[0], v[3]},
+				{v[0], v[2]},
+				{v[1], v[0]},
+				{v[1], v[4]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], v[2]},
+				{v[0], v[1]},
+			},
 		}, {
 			name: "SortInputOrderingNoLimit",
 			// Specified input ordering but no specified limit.
@@ -266,6 +323,34 @@ func TestSorter(t *testing.T) {
 				{v[0], v[2], v[2], v[4]},
 				{v[1], v[2], v[2], v[5]},
 			},
+		}, {
+			name: "SortNullsOrder",
+			spec: execinfrapb.SorterSpec{
+				OutputOrdering: execinfrapb.ConvertToSpecOrdering(
+					sqlbase.ColumnOrdering{
+						{ColIdx: 0, Direction: asc, NullsOrder: tree.NullsLast},
+						{ColIdx: 1, Direction: desc, NullsOrder: tree.NullsFirst},
+					}),
+			},
+			types: sqlbase.TwoIntCols,
+			input: sqlbase.EncDatumRows{
+				{null, v[1]},
+				{v[1], v[2]},
+				{v[0], null},
+				{null, null},
+				{v[1], null},
+				{v[0], v[3]},
+				{null, v[4]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], null},
+				{v[0], v[3]},
+				{v[1], null},
+				{v[1], v[2]},
+				{null, null},
+				{null, v[4]},
+				{null, v[1]},
+			},
 		},
 	}
 
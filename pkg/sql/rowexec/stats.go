@@ -77,6 +77,8 @@ const (
 	MaxDiskTagSuffix = "disk.max"
 	// bytesReadTagSuffix is the tag suffix for the bytes read stat.
 	bytesReadTagSuffix = "bytes.read"
+	// contentionTimeTagSuffix is the tag suffix for the contention time stat.
+	contentionTimeTagSuffix = "contention.time"
 )
 
 // Stats is a utility method that returns a map of the InputStats` stats to
@@ -97,6 +99,8 @@ const (
 	MaxDiskQueryPlanSuffix = "max disk used"
 	// bytesReadQueryPlanSuffix is the tag suffix for the bytes read.
 	bytesReadQueryPlanSuffix = "bytes read"
+	// contentionTimeQueryPlanSuffix is the tag suffix for the contention time.
+	contentionTimeQueryPlanSuffix = "contention time"
 )
 
 // StatsForQueryPlan is a utility method that returns a list of the InputStats'
@@ -115,6 +119,16 @@ func (is InputStats) RoundStallTime() time.Duration {
 	return is.StallTime.Round(time.Microsecond)
 }
 
+// contentionTime returns the total time that the given contention events
+// waited for.
+func contentionTime(events []roachpb.ContentionEvent) time.Duration {
+	var t time.Duration
+	for i := range events {
+		t += events[i].Duration
+	}
+	return t
+}
+
 // rowFetcherStatCollector is a wrapper on top of a row.Fetcher that collects stats.
 //
 // Only row.Fetcher methods that collect stats are overridden.
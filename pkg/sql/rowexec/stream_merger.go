@@ -150,6 +150,11 @@ func CompareEncDatumRowForMerge(
 			if leftOrdering[i].Direction == encoding.Descending {
 				cmp = -cmp
 			}
+			if leftOrdering[i].NullsReversed() && (lhs[lIdx].IsNull() || rhs[rIdx].IsNull()) {
+				// Exactly one of the datums is NULL, and the NULLs are placed on the
+				// opposite side to the one Compare assumes.
+				cmp = -cmp
+			}
 			return cmp, nil
 		}
 	}
@@ -178,7 +183,7 @@ func makeStreamMerger(
 			"ordering lengths don't match: %d and %d", len(leftOrdering), len(rightOrdering))
 	}
 	for i, ord := range leftOrdering {
-		if ord.Direction != rightOrdering[i].Direction {
+		if ord.Direction != rightOrdering[i].Direction || ord.NullsOrder != rightOrdering[i].NullsOrder {
 			return streamMerger{}, errors.New("Ordering mismatch")
 		}
 	}
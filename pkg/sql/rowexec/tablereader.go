@@ -45,6 +45,29 @@ type tableReader struct {
 	// See TableReaderSpec.MaxTimestampAgeNanos.
 	maxTimestampAge time.Duration
 
+	// skipScan is set when the tableReader performs a skip scan. See
+	// TableReaderSpec.SkipScanPrefixLen.
+	skipScan struct {
+		prefixLen   int
+		suffixSpans roachpb.Spans
+		// groupLimit, if non-zero, is the maximum number of rows read under each
+		// prefix. See TableReaderSpec.SkipScanGroupLimit.
+		groupLimit int64
+		reverse    bool
+		// currentSpan is the number of spans of tableReader.spans, in the
+		// direction of the scan, in which all the prefixes have been found.
+		currentSpan int
+		// groupSpans are the spans scanned under the current prefix.
+		groupSpans roachpb.Spans
+		// inGroup is set while the rows under the current prefix are read, and
+		// rowsInGroup is the number of rows read so far.
+		inGroup     bool
+		rowsInGroup int64
+		// misplannedRanges accumulates the misplanned ranges of all the scans,
+		// since the range info of the fetcher is reset when a scan begins.
+		misplannedRanges []roachpb.RangeInfo
+	}
+
 	ignoreMisplannedRanges bool
 
 	// fetcher wraps a row.Fetcher, allowing the tableReader to add a stat
@@ -121,6 +144,8 @@ func newTableReader(
 		return nil, err
 	}
 
+	fetcher.SetScanParallelism(int(sqlbase.ScanParallelism.Get(&flowCtx.Cfg.Settings.SV)))
+
 	nSpans := len(spec.Spans)
 	if cap(tr.spans) >= nSpans {
 		tr.spans = tr.spans[:nSpans]
@@ -131,6 +156,19 @@ func newTableReader(
 		tr.spans[i] = s.Span
 	}
 
+	if spec.SkipScanPrefixLen > 0 {
+		if tr.maxTimestampAge != 0 {
+			return nil, errors.Errorf("skip scans are not supported with a max timestamp age")
+		}
+		tr.skipScan.prefixLen = int(spec.SkipScanPrefixLen)
+		tr.skipScan.groupLimit = int64(spec.SkipScanGroupLimit)
+		tr.skipScan.reverse = spec.Reverse
+		tr.skipScan.suffixSpans = make(roachpb.Spans, len(spec.SkipScanSuffixSpans))
+		for i, s := range spec.SkipScanSuffixSpans {
+			tr.skipScan.suffixSpans[i] = s.Span
+		}
+	}
+
 	if sp := opentracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && tracing.IsRecording(sp) {
 		tr.fetcher = newRowFetcherStatCollector(&fetcher)
 		tr.FinishTrace = tr.outputStatsToTrace
@@ -155,6 +193,11 @@ func (tr *tableReader) Start(ctx context.Context) context.Context {
 
 	ctx = tr.StartInternal(ctx, tableReaderProcName)
 
+	if tr.skipScan.prefixLen > 0 {
+		// The scans of a skip scan are started lazily by Next.
+		return ctx
+	}
+
 	limitBatches := execinfra.ScanShouldLimitBatches(tr.maxResults, tr.limitHint, tr.FlowCtx)
 	log.VEventf(ctx, 1, "starting scan with limitBatches %t", limitBatches)
 	var err error
@@ -194,11 +237,31 @@ func (tr *tableReader) Release() {
 // Next is part of the RowSource interface.
 func (tr *tableReader) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
 	for tr.State == execinfra.StateRunning {
+		if tr.skipScan.prefixLen > 0 && !tr.skipScan.inGroup {
+			ok, err := tr.startSkipScanGroup()
+			if !ok || err != nil {
+				tr.MoveToDraining(err)
+				break
+			}
+		}
 		row, _, _, err := tr.fetcher.NextRow(tr.Ctx)
+		if row == nil && err == nil && tr.skipScan.inGroup {
+			// There are no more rows under the current prefix, so move on to the
+			// next one.
+			tr.skipScan.inGroup = false
+			continue
+		}
 		if row == nil || err != nil {
 			tr.MoveToDraining(err)
 			break
 		}
+		if tr.skipScan.inGroup {
+			tr.skipScan.rowsInGroup++
+			if tr.skipScan.rowsInGroup == tr.skipScan.groupLimit {
+				// The rest of the rows under the current prefix aren't needed.
+				tr.skipScan.inGroup = false
+			}
+		}
 
 		// When tracing is enabled, number of rows read is tracked twice (once
 		// here, and once through InputStats). This is done so that non-tracing
@@ -212,6 +275,106 @@ func (tr *tableReader) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadat
 	return nil, tr.DrainHelper()
 }
 
+// startSkipScanGroup finds the next distinct prefix of a skip scan and starts
+// a scan of the suffix spans under it. It returns false when there are no more
+// prefixes to scan.
+func (tr *tableReader) startSkipScanGroup() (bool, error) {
+	ss := &tr.skipScan
+	for ss.currentSpan < len(tr.spans) {
+		cur := &tr.spans[ss.currentSpan]
+		if ss.reverse {
+			cur = &tr.spans[len(tr.spans)-1-ss.currentSpan]
+		}
+		tr.accumulateMisplannedRanges()
+		// Start a scan to get the first prefix within the current span in the
+		// direction of the scan.
+		if err := tr.fetcher.StartScan(
+			tr.Ctx, tr.FlowCtx.Txn, roachpb.Spans{*cur},
+			true /* limitBatches */, 1 /* limitHint */, tr.FlowCtx.TraceKV,
+		); err != nil {
+			return false, err
+		}
+		key, err := tr.fetcher.PartialKey(ss.prefixLen)
+		if err != nil {
+			return false, err
+		}
+		if key == nil {
+			// No more rows in this span, so move to the next one.
+			ss.currentSpan++
+			continue
+		}
+		// The key belongs to the fetcher, so it is copied before starting another
+		// scan.
+		prefix := append(roachpb.Key(nil), key...)
+		prefixEnd := prefix.PrefixEnd()
+
+		ss.groupSpans = ss.groupSpans[:0]
+		for _, suffix := range ss.suffixSpans {
+			span := roachpb.Span{
+				Key:    append(prefix[:len(prefix):len(prefix)], suffix.Key...),
+				EndKey: prefixEnd,
+			}
+			if len(suffix.EndKey) > 0 {
+				span.EndKey = append(prefix[:len(prefix):len(prefix)], suffix.EndKey...)
+			}
+			// The current span may only cover part of the rows under the prefix
+			// (e.g. when it was split at a range boundary).
+			if span.Key.Compare(cur.Key) < 0 {
+				span.Key = cur.Key
+			}
+			if span.EndKey.Compare(cur.EndKey) > 0 {
+				span.EndKey = cur.EndKey
+			}
+			if span.Valid() {
+				ss.groupSpans = append(ss.groupSpans, span)
+			}
+		}
+
+		// Skip all the keys with this prefix for the next seek. All of them sort
+		// at or after the prefix and before its PrefixEnd.
+		if ss.reverse {
+			cur.EndKey = prefix
+		} else {
+			cur.Key = prefixEnd
+		}
+		if !cur.Valid() {
+			ss.currentSpan++
+		}
+		if len(ss.groupSpans) == 0 {
+			continue
+		}
+
+		limitHint := tr.limitHint
+		if ss.groupLimit != 0 && (limitHint == 0 || ss.groupLimit < limitHint) {
+			limitHint = ss.groupLimit
+		}
+		limitBatches := execinfra.ScanShouldLimitBatches(tr.maxResults, limitHint, tr.FlowCtx)
+		if err := tr.fetcher.StartScan(
+			tr.Ctx, tr.FlowCtx.Txn, ss.groupSpans,
+			limitBatches, limitHint, tr.FlowCtx.TraceKV,
+		); err != nil {
+			return false, err
+		}
+		ss.inGroup = true
+		ss.rowsInGroup = 0
+		return true, nil
+	}
+	return false, nil
+}
+
+// accumulateMisplannedRanges adds the misplanned ranges of the last scan to the
+// ones of the previous scans of a skip scan. It must be called before starting
+// a new scan.
+func (tr *tableReader) accumulateMisplannedRanges() {
+	if tr.ignoreMisplannedRanges {
+		return
+	}
+	ranges := execinfra.MisplannedRanges(tr.Ctx, tr.fetcher.GetRangesInfo(), tr.FlowCtx.NodeID)
+	for _, r := range ranges {
+		tr.skipScan.misplannedRanges = roachpb.InsertRangeInfo(tr.skipScan.misplannedRanges, r)
+	}
+}
+
 // ConsumerClosed is part of the RowSource interface.
 func (tr *tableReader) ConsumerClosed() {
 	// The consumer is done, Next() will not be called again.
@@ -226,15 +389,26 @@ const tableReaderTagPrefix = "tablereader."
 func (trs *TableReaderStats) Stats() map[string]string {
 	inputStatsMap := trs.InputStats.Stats(tableReaderTagPrefix)
 	inputStatsMap[tableReaderTagPrefix+bytesReadTagSuffix] = humanizeutil.IBytes(trs.BytesRead)
+	if trs.ContentionTime != 0 {
+		inputStatsMap[tableReaderTagPrefix+contentionTimeTagSuffix] = trs.ContentionTime.Round(time.Microsecond).String()
+	}
 	return inputStatsMap
 }
 
 // StatsForQueryPlan implements the DistSQLSpanStats interface.
 func (trs *TableReaderStats) StatsForQueryPlan() []string {
-	return append(
+	stats := append(
 		trs.InputStats.StatsForQueryPlan("" /* prefix */),
 		fmt.Sprintf("%s: %s", bytesReadQueryPlanSuffix, humanizeutil.IBytes(trs.BytesRead)),
 	)
+	// Only the table readers whose requests waited on conflicting transactions
+	// report the time they spent waiting.
+	if trs.ContentionTime != 0 {
+		stats = append(stats, fmt.Sprintf(
+			"%s: %v", contentionTimeQueryPlanSuffix, trs.ContentionTime.Round(time.Microsecond),
+		))
+	}
+	return stats
 }
 
 // outputStatsToTrace outputs the collected tableReader stats to the trace. Will
@@ -246,8 +420,9 @@ func (tr *tableReader) outputStatsToTrace() {
 	}
 	if sp := opentracing.SpanFromContext(tr.Ctx); sp != nil {
 		tracing.SetSpanStats(sp, &TableReaderStats{
-			InputStats: is,
-			BytesRead:  tr.fetcher.GetBytesRead(),
+			InputStats:     is,
+			BytesRead:      tr.fetcher.GetBytesRead(),
+			ContentionTime: contentionTime(tr.fetcher.GetContentionEvents()),
 		})
 	}
 }
@@ -256,6 +431,12 @@ func (tr *tableReader) generateMeta(ctx context.Context) []execinfrapb.ProducerM
 	var trailingMeta []execinfrapb.ProducerMetadata
 	if !tr.ignoreMisplannedRanges {
 		ranges := execinfra.MisplannedRanges(ctx, tr.fetcher.GetRangesInfo(), tr.FlowCtx.NodeID)
+		if tr.skipScan.prefixLen > 0 {
+			for _, r := range ranges {
+				tr.skipScan.misplannedRanges = roachpb.InsertRangeInfo(tr.skipScan.misplannedRanges, r)
+			}
+			ranges = tr.skipScan.misplannedRanges
+		}
 		if ranges != nil {
 			trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{Ranges: ranges})
 		}
@@ -263,6 +444,9 @@ func (tr *tableReader) generateMeta(ctx context.Context) []execinfrapb.ProducerM
 	if tfs := execinfra.GetLeafTxnFinalState(ctx, tr.FlowCtx.Txn); tfs != nil {
 		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
 	}
+	if events := tr.fetcher.GetContentionEvents(); len(events) > 0 {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{ContentionEvents: events})
+	}
 
 	meta := execinfrapb.GetProducerMeta()
 	meta.Metrics = execinfrapb.GetMetricsMeta()
@@ -117,6 +117,39 @@ func TestTableReader(t *testing.T) {
 			},
 			expected: "[[2 5] [1 5] [0 5] [2 4] [1 4] [0 4]]",
 		},
+		{
+			// Skip scan over a, reading the rows with 3 <= b < 5 under each a.
+			spec: execinfrapb.TableReaderSpec{
+				Spans:             []execinfrapb.TableReaderSpan{{Span: td.PrimaryIndexSpan()}},
+				SkipScanPrefixLen: 1,
+				SkipScanSuffixSpans: []execinfrapb.TableReaderSpan{{Span: roachpb.Span{
+					Key:    encoding.EncodeVarintAscending(nil, 3),
+					EndKey: encoding.EncodeVarintAscending(nil, 5),
+				}}},
+			},
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@1 < 3"}, // a < 3
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[0 3] [0 4] [1 3] [1 4] [2 3] [2 4]]",
+		},
+		{
+			// Reverse skip scan over a, reading the last row under each a.
+			spec: execinfrapb.TableReaderSpec{
+				Reverse:             true,
+				Spans:               []execinfrapb.TableReaderSpan{{Span: td.PrimaryIndexSpan()}},
+				SkipScanPrefixLen:   1,
+				SkipScanSuffixSpans: []execinfrapb.TableReaderSpan{{}},
+				SkipScanGroupLimit:  1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Filter:        execinfrapb.Expression{Expr: "@1 < 3"}, // a < 3
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[2 9] [1 9] [0 9]]",
+		},
 	}
 
 	for _, c := range testCases {
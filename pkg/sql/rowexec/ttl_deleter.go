@@ -0,0 +1,231 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowexec
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	"golang.org/x/time/rate"
+)
+
+// ttlDeleter is a processor that deletes the rows of a table whose TTL
+// expiration column holds a time before the expire_before time of its spec.
+//
+// The spans are scanned in chunks of at most batch_size rows, and the expired
+// rows of each chunk are deleted in the transaction that scans the chunk, so
+// that a row that is updated concurrently is never deleted based on a stale
+// expiration time. The processor reports the spans it has finished and the
+// number of rows it has deleted through BulkProcessorProgress metadata after
+// each span.
+type ttlDeleter struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.TTLDeleterSpec
+	output      execinfra.RowReceiver
+
+	desc    *sqlbase.ImmutableTableDescriptor
+	evalCtx *tree.EvalContext
+	fetcher row.Fetcher
+	alloc   sqlbase.DatumAlloc
+	// expirationColIdx is the index of the TTL expiration column in the rows
+	// returned by the fetcher.
+	expirationColIdx int
+	// limiter limits the rate at which rows are deleted, if set.
+	limiter *rate.Limiter
+}
+
+var _ execinfra.Processor = &ttlDeleter{}
+
+func newTTLDeleter(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.TTLDeleterSpec,
+	output execinfra.RowReceiver,
+) (*ttlDeleter, error) {
+	if spec.BatchSize <= 0 {
+		return nil, errors.AssertionFailedf("invalid TTL deletion batch size %d", spec.BatchSize)
+	}
+	desc := sqlbase.NewImmutableTableDescriptor(spec.Table)
+	if len(desc.InboundFKs) > 0 {
+		// The rows are deleted without checking foreign keys.
+		return nil, errors.AssertionFailedf(
+			"cannot delete expired rows of table %q which is referenced by foreign keys", desc.Name)
+	}
+	colIdxMap := desc.ColumnIdxMap()
+	expirationColIdx, ok := colIdxMap[desc.TTLExpirationColumnID]
+	if !ok {
+		return nil, errors.AssertionFailedf("table %q has no TTL expiration column", desc.Name)
+	}
+
+	d := &ttlDeleter{
+		flowCtx:          flowCtx,
+		processorID:      processorID,
+		spec:             spec,
+		output:           output,
+		desc:             desc,
+		evalCtx:          flowCtx.NewEvalCtx(),
+		expirationColIdx: expirationColIdx,
+	}
+	if spec.RowsPerSecond > 0 {
+		// A chunk deletes at most BatchSize rows, which must fit in the burst.
+		d.limiter = rate.NewLimiter(rate.Limit(spec.RowsPerSecond), int(spec.BatchSize))
+	}
+
+	// The deleter needs all the columns of the deletable indexes, so fetch them
+	// all.
+	var valNeededForCol util.FastIntSet
+	valNeededForCol.AddRange(0, len(desc.Columns)-1)
+	tableArgs := row.FetcherTableArgs{
+		Desc:            desc,
+		Index:           &desc.PrimaryIndex,
+		ColIdxMap:       colIdxMap,
+		Cols:            desc.Columns,
+		ValNeededForCol: valNeededForCol,
+	}
+	if err := d.fetcher.Init(
+		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &d.alloc, tableArgs,
+	); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// OutputTypes is part of the processor interface.
+func (*ttlDeleter) OutputTypes() []types.T {
+	// No output types.
+	return nil
+}
+
+// Run is part of the Processor interface.
+func (d *ttlDeleter) Run(ctx context.Context) {
+	ctx = logtags.AddTag(ctx, "ttlDeleter", int(d.desc.ID))
+	ctx, span := execinfra.ProcessorSpan(ctx, "ttlDeleter")
+	defer tracing.FinishSpan(span)
+	if err := d.doRun(ctx); err != nil {
+		d.output.Push(nil /* row */, &execinfrapb.ProducerMetadata{Err: err})
+	}
+	execinfra.SendTraceData(ctx, d.output)
+	d.output.ProducerDone()
+}
+
+// doRun deletes the expired rows of the spans one after the other, and pushes
+// the progress metadata of each span once it's finished.
+func (d *ttlDeleter) doRun(ctx context.Context) error {
+	var total int64
+	for i := range d.spec.Spans {
+		sp := d.spec.Spans[i].Span
+		log.VEventf(ctx, 2, "TTL deleter starting span %d of %d: %s", i+1, len(d.spec.Spans), sp)
+		var deleted int64
+		for todo := sp; todo.Key != nil; {
+			var n int64
+			var err error
+			todo.Key, n, err = d.deleteChunk(ctx, todo)
+			if err != nil {
+				return err
+			}
+			deleted += n
+			if d.limiter != nil && n > 0 {
+				if err := d.limiter.WaitN(ctx, int(n)); err != nil {
+					return err
+				}
+			}
+		}
+		total += deleted
+		prog := execinfrapb.RemoteProducerMetadata_BulkProcessorProgress{
+			CompletedSpans: []roachpb.Span{sp},
+			BulkSummary:    roachpb.BulkOpSummary{Rows: deleted},
+		}
+		meta := &execinfrapb.ProducerMetadata{BulkProcessorProgress: &prog}
+		if status := d.output.Push(nil /* row */, meta); status != execinfra.NeedMoreRows {
+			log.VEventf(ctx, 1, "TTL deleter stopping after span %d: consumer status %d", i+1, status)
+			return nil
+		}
+	}
+	log.VEventf(ctx, 2, "TTL deleter deleted %d expired rows in %d spans", total, len(d.spec.Spans))
+	return nil
+}
+
+// deleteChunk scans up to BatchSize rows of the span and deletes the expired
+// ones in a single transaction. It returns the key at which the scan of the
+// span must resume, which is nil once the span is exhausted, and the number of
+// rows deleted.
+func (d *ttlDeleter) deleteChunk(
+	ctx context.Context, sp roachpb.Span,
+) (resume roachpb.Key, deleted int64, _ error) {
+	expireBefore := d.spec.ExpireBefore.GoTime()
+	err := d.flowCtx.Cfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		resume, deleted = nil, 0
+		rd, err := row.MakeDeleter(
+			txn, d.desc, nil /* fkTables */, d.desc.Columns, row.SkipFKs, d.evalCtx, &d.alloc,
+		)
+		if err != nil {
+			return err
+		}
+		if err := d.fetcher.StartScan(
+			ctx, txn, roachpb.Spans{sp}, true /* limitBatches */, d.spec.BatchSize, false, /* traceKV */
+		); err != nil {
+			return err
+		}
+		b := txn.NewBatch()
+		for i := int64(0); i < d.spec.BatchSize; i++ {
+			datums, _, _, err := d.fetcher.NextRowDecoded(ctx)
+			if err != nil {
+				return err
+			}
+			if datums == nil {
+				break
+			}
+			if !ttlExpired(datums[d.expirationColIdx], expireBefore) {
+				continue
+			}
+			if err := rd.DeleteRow(ctx, b, datums, row.SkipFKs, false /* traceKV */); err != nil {
+				return err
+			}
+			deleted++
+		}
+		resume = d.fetcher.Key()
+		if deleted == 0 {
+			return nil
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return row.ConvertBatchError(ctx, d.desc, b)
+		}
+		return nil
+	})
+	return resume, deleted, err
+}
+
+// ttlExpired returns whether the value of a TTL expiration column is before the
+// given time. NULL values never expire.
+func ttlExpired(d tree.Datum, expireBefore time.Time) bool {
+	switch t := d.(type) {
+	case *tree.DTimestamp:
+		return t.Time.Before(expireBefore)
+	case *tree.DTimestampTZ:
+		return t.Time.Before(expireBefore)
+	default:
+		return false
+	}
+}
@@ -206,7 +206,7 @@ func newWindower(
 	// windower will overflow to disk if this limit is not enough.
 	limit := flowCtx.Cfg.TestingKnobs.MemoryLimitBytes
 	if limit <= 0 {
-		limit = execinfra.SettingWorkMemBytes.Get(&st.SV)
+		limit = execinfra.SettingWorkMemBytes.GetWithOverrides(&st.SV, flowCtx.SettingOverrides())
 		if limit < memRequiredByWindower {
 			return nil, errors.Errorf(
 				"window functions require %d bytes of RAM but only %d are in the budget. "+
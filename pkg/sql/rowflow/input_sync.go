@@ -388,3 +388,85 @@ func makeOrderedSync(
 	}
 	return s, nil
 }
+
+// serialSynchronizer receives rows from multiple streams and produces a single
+// stream of rows by returning all the rows of each stream, one stream at a
+// time, in the order in which the streams are specified. Unlike the
+// orderedSynchronizer, it doesn't read anything from a stream until all the
+// previous streams have been exhausted, so if the consumer stops early (e.g.
+// because of a limit), the later streams are never read. The streams are only
+// started once they are read, so if they are fused with their processors, the
+// processors of the later streams don't do any work.
+type serialSynchronizer struct {
+	sources []execinfra.RowSource
+	types   []types.T
+
+	// ctx is the context that the sources are started with.
+	ctx context.Context
+	// srcIdx is the index of the source that is currently being read. All the
+	// sources before it have been exhausted, and the ones after it haven't been
+	// started.
+	srcIdx int
+}
+
+var _ execinfra.RowSource = &serialSynchronizer{}
+
+// OutputTypes is part of the RowSource interface.
+func (s *serialSynchronizer) OutputTypes() []types.T {
+	return s.types
+}
+
+// Start is part of the RowSource interface.
+func (s *serialSynchronizer) Start(ctx context.Context) context.Context {
+	s.ctx = ctx
+	s.sources[0].Start(ctx)
+	return ctx
+}
+
+// Next is part of the RowSource interface.
+func (s *serialSynchronizer) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for s.srcIdx < len(s.sources) {
+		row, meta := s.sources[s.srcIdx].Next()
+		if row != nil || meta != nil {
+			return row, meta
+		}
+		s.srcIdx++
+		if s.srcIdx < len(s.sources) {
+			s.sources[s.srcIdx].Start(s.ctx)
+		}
+	}
+	return nil, nil
+}
+
+// ConsumerDone is part of the RowSource interface.
+func (s *serialSynchronizer) ConsumerDone() {
+	if s.srcIdx >= len(s.sources) {
+		return
+	}
+	s.sources[s.srcIdx].ConsumerDone()
+	// The sources that haven't been started yet haven't produced anything, so
+	// they are closed instead of being started only to be drained.
+	for i := s.srcIdx + 1; i < len(s.sources); i++ {
+		s.sources[i].ConsumerClosed()
+	}
+	s.sources = s.sources[:s.srcIdx+1]
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (s *serialSynchronizer) ConsumerClosed() {
+	for i := s.srcIdx; i < len(s.sources); i++ {
+		s.sources[i].ConsumerClosed()
+	}
+	s.srcIdx = len(s.sources)
+}
+
+// makeSerialSync creates a serialSynchronizer that reads the sources in order.
+func makeSerialSync(sources []execinfra.RowSource) (execinfra.RowSource, error) {
+	if len(sources) < 1 {
+		return nil, errors.Errorf("no sources for serial synchronizer")
+	}
+	return &serialSynchronizer{
+		sources: sources,
+		types:   sources[0].OutputTypes(),
+	}, nil
+}
@@ -181,6 +181,116 @@ func TestOrderedSyncDrainBeforeNext(t *testing.T) {
 	}
 }
 
+func TestSerialSync(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	v := [5]sqlbase.EncDatum{}
+	for i := range v {
+		v[i] = sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
+	}
+	sourceRows := []sqlbase.EncDatumRows{
+		{{v[0]}, {v[1]}},
+		{},
+		{{v[2]}, {v[3]}},
+		{{v[4]}},
+	}
+	makeSources := func() []*distsqlutils.RowBuffer {
+		var bufs []*distsqlutils.RowBuffer
+		for _, rows := range sourceRows {
+			bufs = append(bufs, distsqlutils.NewRowBuffer(sqlbase.OneIntCol, rows, distsqlutils.RowBufferArgs{}))
+		}
+		return bufs
+	}
+	// started records which sources have been started.
+	var started []bool
+	toRowSources := func(bufs []*distsqlutils.RowBuffer) []execinfra.RowSource {
+		started = make([]bool, len(bufs))
+		res := make([]execinfra.RowSource, len(bufs))
+		for i := range bufs {
+			res[i] = &startRecordingSource{RowSource: bufs[i], started: &started[i]}
+		}
+		return res
+	}
+	ctx := context.Background()
+
+	t.Run("all", func(t *testing.T) {
+		src, err := makeSerialSync(toRowSources(makeSources()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src.Start(ctx)
+		var retRows sqlbase.EncDatumRows
+		for {
+			row, meta := src.Next()
+			if meta != nil {
+				t.Fatalf("unexpected metadata: %v", meta)
+			}
+			if row == nil {
+				break
+			}
+			retRows = append(retRows, row)
+		}
+		expected := sqlbase.EncDatumRows{{v[0]}, {v[1]}, {v[2]}, {v[3]}, {v[4]}}
+		if expStr, retStr := expected.String(sqlbase.OneIntCol), retRows.String(sqlbase.OneIntCol); expStr != retStr {
+			t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s", expStr, retStr)
+		}
+		for i := range started {
+			if !started[i] {
+				t.Errorf("source %d was not started", i)
+			}
+		}
+	})
+
+	t.Run("short-circuit", func(t *testing.T) {
+		bufs := makeSources()
+		src, err := makeSerialSync(toRowSources(bufs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src.Start(ctx)
+		for i := 0; i < 2; i++ {
+			if row, meta := src.Next(); row == nil || meta != nil {
+				t.Fatalf("expected a row, got %v, %v", row, meta)
+			}
+		}
+		src.ConsumerDone()
+		if bufs[0].ConsumerStatus != execinfra.DrainRequested {
+			t.Errorf("source 0: expected DrainRequested, found %d", bufs[0].ConsumerStatus)
+		}
+		// Drain the synchronizer, as a consumer would after ConsumerDone.
+		for {
+			row, meta := src.Next()
+			if row == nil && meta == nil {
+				break
+			}
+		}
+		// The sources after the first one must not have been started nor read.
+		for i := 1; i < len(bufs); i++ {
+			if started[i] {
+				t.Errorf("source %d was started", i)
+			}
+			if n := len(bufs[i].Mu.Records); n != len(sourceRows[i]) {
+				t.Errorf("source %d: expected %d buffered rows, found %d", i, len(sourceRows[i]), n)
+			}
+			if bufs[i].ConsumerStatus != execinfra.ConsumerClosed {
+				t.Errorf("source %d: expected ConsumerClosed, found %d", i, bufs[i].ConsumerStatus)
+			}
+		}
+	})
+}
+
+// startRecordingSource is a RowSource that records whether it was started.
+type startRecordingSource struct {
+	execinfra.RowSource
+	started *bool
+}
+
+// Start is part of the RowSource interface.
+func (s *startRecordingSource) Start(ctx context.Context) context.Context {
+	*s.started = true
+	return s.RowSource.Start(ctx)
+}
+
 func TestUnorderedSync(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -71,6 +71,16 @@ type scanNode struct {
 	spans   []roachpb.Span
 	reverse bool
 
+	// if non-zero, skipScanPrefixLen indicates that the scanNode performs a skip
+	// scan: for each distinct value of the first skipScanPrefixLen index
+	// columns within spans, only the skipScanSuffixSpans under that value are
+	// scanned. See span.Builder.SkipScanSuffixSpans.
+	skipScanPrefixLen   int
+	skipScanSuffixSpans []roachpb.Span
+	// if non-zero, skipScanGroupLimit is the maximum number of rows returned
+	// under each distinct prefix of a skip scan.
+	skipScanGroupLimit int64
+
 	reqOrdering ReqOrdering
 
 	// filter that can be evaluated using only this table/index; it contains
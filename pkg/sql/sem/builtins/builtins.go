@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -46,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
@@ -1763,7 +1765,7 @@ CockroachDB supports the following flags:
 			Types:      tree.ArgTypes{},
 			ReturnType: tree.FixedReturnType(types.TimestampTZ),
 			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
-				ts, err := recentTimestamp(ctx)
+				ts, err := recentTimestamp(ctx, tree.FollowerReadTimestampFunctionName)
 				if err != nil {
 					return nil, err
 				}
@@ -1777,6 +1779,79 @@ historical reads against a time which is recent but sufficiently old for reads
 to be performed against the closest replica as opposed to the currently
 leaseholder for a given range.
 
+Note that this function requires an enterprise license on a CCL distribution to
+return without an error.`,
+		},
+	),
+
+	tree.FollowerReadTimestampExperimentalFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				ts, err := recentTimestamp(ctx, tree.FollowerReadTimestampExperimentalFunctionName)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDTimestampTZ(ts, time.Microsecond), nil
+			},
+			Info: fmt.Sprintf("Same as %s. This name is deprecated.", tree.FollowerReadTimestampFunctionName),
+		},
+	),
+
+	tree.WithMinTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"min_timestamp", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				minTS := tree.MustBeDTimestampTZ(args[0]).Time
+				ts, err := boundedStalenessTimestamp(ctx, tree.WithMinTimestampFunctionName, minTS)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDTimestampTZ(ts, time.Microsecond), nil
+			},
+			Info: `Returns the timestamp of a bounded staleness read which observes
+all the data written up to min_timestamp.
+
+This function is intended to be used with an AS OF SYSTEM TIME clause. It picks
+the more recent of min_timestamp and of the timestamp returned by
+follower_read_timestamp(), and the reads of such a query which address a single
+range are sent to the closest replica of that range, which serves them if its
+closed timestamp allows it to, and redirects them to the leaseholder otherwise.
+
+Note that this function requires an enterprise license on a CCL distribution to
+return without an error.`,
+		},
+	),
+
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"max_staleness", types.Interval}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				maxStaleness := args[0].(*tree.DInterval).Duration
+				if maxStaleness.Compare(duration.Duration{}) < 0 {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"interval duration for %s must be greater or equal to 0",
+						tree.WithMaxStalenessFunctionName)
+				}
+				minTS := duration.Add(ctx, ctx.GetStmtTimestamp(), maxStaleness.Mul(-1))
+				ts, err := boundedStalenessTimestamp(ctx, tree.WithMaxStalenessFunctionName, minTS)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDTimestampTZ(ts, time.Microsecond), nil
+			},
+			Info: `Returns the timestamp of a bounded staleness read which observes
+all the data written up to max_staleness before the statement timestamp.
+
+This function is intended to be used with an AS OF SYSTEM TIME clause. It
+behaves like with_min_timestamp(statement_timestamp() - max_staleness).
+
 Note that this function requires an enterprise license on a CCL distribution to
 return without an error.`,
 		},
@@ -3114,6 +3189,25 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.decode_key": makeBuiltin(
+		tree.FunctionProperties{Category: categorySystemInfo},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.Jsonb),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				j, err := decodeTableKey(ctx, roachpb.Key(tree.MustBeDBytes(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDJSON(j), nil
+			},
+			Info: "Decode a key, such as the start or end key of a range, into the table, " +
+				"the index and the values of the index columns that it is made of. Keys " +
+				"that only contain a prefix of the index columns, as range boundaries often " +
+				"do, are decoded as far as possible.",
+		},
+	),
+
 	"crdb_internal.force_error": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -5267,6 +5361,84 @@ var errInsufficientPriv = pgerror.New(
 	pgcode.InsufficientPrivilege, "insufficient privilege",
 )
 
+// decodeTableKey implements the crdb_internal.decode_key builtin. It returns
+// a JSON object with the table and index that the key belongs to and the
+// values of the index columns that the key contains, in index order. Any bytes
+// that follow the index columns, like the column family ID of a primary index
+// key or the key of an interleaved child table, are returned pretty-printed.
+func decodeTableKey(ctx *tree.EvalContext, key roachpb.Key) (json.JSON, error) {
+	if key.Compare(keys.TableDataMin) < 0 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "%s is not a table key", key)
+	}
+	builder := json.NewObjectBuilder(6)
+	remaining, tableID, err := encoding.DecodeUvarintAscending(key)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "%s is not a table key", key)
+	}
+	builder.Add("table_id", json.FromInt64(int64(tableID)))
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx.Context, ctx.Txn, sqlbase.ID(tableID))
+	if err != nil {
+		return nil, err
+	}
+	builder.Add("table", json.FromString(tableDesc.Name))
+	if len(remaining) == 0 {
+		return builder.Build(), nil
+	}
+	remaining, indexID, err := encoding.DecodeUvarintAscending(remaining)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid index ID in %s", key)
+	}
+	builder.Add("index_id", json.FromInt64(int64(indexID)))
+	indexDesc, err := tableDesc.FindIndexByID(sqlbase.IndexID(indexID))
+	if err != nil {
+		return nil, err
+	}
+	builder.Add("index", json.FromString(indexDesc.Name))
+
+	colIDs, dirs := indexDesc.FullColumnIDs()
+	values := json.NewArrayBuilder(len(colIDs))
+	var alloc sqlbase.DatumAlloc
+	for i, colID := range colIDs {
+		if len(remaining) == 0 {
+			break
+		}
+		if _, ok := encoding.DecodeIfInterleavedSentinel(remaining); ok {
+			break
+		}
+		col, err := tableDesc.FindColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+		enc := sqlbase.DatumEncoding_ASCENDING_KEY
+		if dirs[i] == sqlbase.IndexDescriptor_DESC {
+			enc = sqlbase.DatumEncoding_DESCENDING_KEY
+		}
+		var ed sqlbase.EncDatum
+		ed, remaining, err = sqlbase.EncDatumFromBuffer(col.DatumType(), enc, remaining)
+		if err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"cannot decode column %s in %s", col.Name, key)
+		}
+		if err := ed.EnsureDecoded(col.DatumType(), &alloc); err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"cannot decode column %s in %s", col.Name, key)
+		}
+		value, err := tree.AsJSON(ed.Datum)
+		if err != nil {
+			return nil, err
+		}
+		column := json.NewObjectBuilder(2)
+		column.Add("name", json.FromString(col.Name))
+		column.Add("value", value)
+		values.Add(column.Build())
+	}
+	builder.Add("columns", values.Build())
+	if len(remaining) > 0 {
+		builder.Add("remaining", json.FromString(encoding.PrettyPrintValue(nil /* valDirs */, remaining, "/")))
+	}
+	return builder.Build(), nil
+}
+
 func checkPrivilegedUser(ctx *tree.EvalContext) error {
 	if ctx.SessionData.User != security.RootUser {
 		return errInsufficientPriv
@@ -5280,11 +5452,10 @@ func checkPrivilegedUser(ctx *tree.EvalContext) error {
 // if an enterprise license is not installed.
 var EvalFollowerReadOffset func(clusterID uuid.UUID, _ *cluster.Settings) (time.Duration, error)
 
-func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
+func recentTimestamp(ctx *tree.EvalContext, funcName string) (time.Time, error) {
 	if EvalFollowerReadOffset == nil {
-		return time.Time{}, pgerror.New(pgcode.FeatureNotSupported,
-			tree.FollowerReadTimestampFunctionName+
-				" is only available in ccl distribution")
+		return time.Time{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only available in ccl distribution", funcName)
 	}
 	offset, err := EvalFollowerReadOffset(ctx.ClusterID, ctx.Settings)
 	if err != nil {
@@ -5292,3 +5463,20 @@ func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
 	}
 	return ctx.StmtTimestamp.Add(offset), nil
 }
+
+// boundedStalenessTimestamp returns the timestamp at which a bounded staleness
+// read which must observe all the data written up to minTS is performed: the
+// most recent timestamp which is likely to be servable by the closest replica
+// (see recentTimestamp), unless it is older than minTS.
+func boundedStalenessTimestamp(
+	ctx *tree.EvalContext, funcName string, minTS time.Time,
+) (time.Time, error) {
+	ts, err := recentTimestamp(ctx, funcName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ts.Before(minTS) {
+		ts = minTS
+	}
+	return ts, nil
+}
@@ -24,14 +24,63 @@ import (
 	"github.com/pkg/errors"
 )
 
-// FollowerReadTimestampFunctionName is the name of the function which can be
-// used with AOST clauses to generate a timestamp likely to be safe for follower
-// reads.
-const FollowerReadTimestampFunctionName = "experimental_follower_read_timestamp"
+const (
+	// FollowerReadTimestampFunctionName is the name of the function which can be
+	// used with AOST clauses to generate a timestamp likely to be safe for
+	// follower reads.
+	FollowerReadTimestampFunctionName = "follower_read_timestamp"
+	// FollowerReadTimestampExperimentalFunctionName is the name of the old
+	// "experimental_"-prefixed function, which we keep for backwards
+	// compatibility.
+	FollowerReadTimestampExperimentalFunctionName = "experimental_follower_read_timestamp"
+	// WithMinTimestampFunctionName is the name of the function which can be used
+	// with AOST clauses to perform a bounded staleness read at a timestamp no
+	// older than a given timestamp.
+	WithMinTimestampFunctionName = "with_min_timestamp"
+	// WithMaxStalenessFunctionName is the name of the function which can be used
+	// with AOST clauses to perform a bounded staleness read at a timestamp no
+	// staler than a given interval.
+	WithMaxStalenessFunctionName = "with_max_staleness"
+)
 
-var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions or " +
+var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions, " +
+	WithMinTimestampFunctionName + ", " + WithMaxStalenessFunctionName + " or " +
 	FollowerReadTimestampFunctionName + " are allowed")
 
+// resolveAsOfFunc returns the expression of an AS OF SYSTEM TIME clause and the
+// name of the function it invokes if the expression is a function invocation
+// (or nil otherwise). An error is returned if that function isn't allowed in
+// AS OF SYSTEM TIME clauses.
+func resolveAsOfFunc(asOf AsOfClause, semaCtx *SemaContext) (*FuncExpr, string, error) {
+	fe, ok := asOf.Expr.(*FuncExpr)
+	if !ok {
+		return nil, "", nil
+	}
+	def, err := fe.Func.Resolve(semaCtx.SearchPath)
+	if err != nil {
+		return nil, "", errInvalidExprForAsOf
+	}
+	switch def.Name {
+	case FollowerReadTimestampFunctionName, FollowerReadTimestampExperimentalFunctionName,
+		WithMinTimestampFunctionName, WithMaxStalenessFunctionName:
+		return fe, def.Name, nil
+	default:
+		return nil, "", errInvalidExprForAsOf
+	}
+}
+
+// IsBoundedStalenessAsOf returns whether the AS OF SYSTEM TIME clause performs
+// a bounded staleness read, that is, whether its expression is an invocation of
+// with_min_timestamp or with_max_staleness. Such reads are routed to the
+// nearest replica of the range they read from when possible.
+func IsBoundedStalenessAsOf(asOf AsOfClause, semaCtx *SemaContext) bool {
+	_, name, err := resolveAsOfFunc(asOf, semaCtx)
+	if err != nil {
+		return false
+	}
+	return name == WithMinTimestampFunctionName || name == WithMaxStalenessFunctionName
+}
+
 // EvalAsOfTimestamp evaluates the timestamp argument to an AS OF SYSTEM TIME query.
 func EvalAsOfTimestamp(
 	asOf AsOfClause, semaCtx *SemaContext, evalCtx *EvalContext,
@@ -43,20 +92,18 @@ func EvalAsOfTimestamp(
 	defer scalarProps.Restore(*scalarProps)
 	scalarProps.Require("AS OF SYSTEM TIME", RejectSpecial|RejectSubqueries)
 
-	// In order to support the follower reads feature we permit this expression
-	// to be a simple invocation of the `FollowerReadTimestampFunction`.
-	// Over time we could expand the set of allowed functions or expressions.
-	// All non-function expressions must be const and must TypeCheck into a
-	// string.
+	// In order to support the follower reads and bounded staleness reads
+	// features we permit this expression to be a simple invocation of the
+	// follower_read_timestamp, with_min_timestamp or with_max_staleness
+	// functions. Over time we could expand the set of allowed functions or
+	// expressions. All non-function expressions must be const and must
+	// TypeCheck into a string.
 	var te TypedExpr
-	if fe, ok := asOf.Expr.(*FuncExpr); ok {
-		def, err := fe.Func.Resolve(semaCtx.SearchPath)
-		if err != nil {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
-		}
-		if def.Name != FollowerReadTimestampFunctionName {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
-		}
+	fe, _, err := resolveAsOfFunc(asOf, semaCtx)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if fe != nil {
 		if te, err = fe.TypeCheck(semaCtx, types.TimestampTZ); err != nil {
 			return hlc.Timestamp{}, err
 		}
@@ -2526,6 +2526,15 @@ func ConvertLikeToRegexp(
 	return re, nil
 }
 
+// ConvertRegMatchToRegexp compiles the specified POSIX regular expression
+// pattern as used by the ~ and ~* operators (and their negations).
+func ConvertRegMatchToRegexp(
+	ctx *EvalContext, pattern string, caseInsensitive bool,
+) (*regexp.Regexp, error) {
+	key := regexpKey{s: pattern, caseInsensitive: caseInsensitive}
+	return ctx.ReCache.GetRegexp(key)
+}
+
 func matchLike(ctx *EvalContext, left, right Datum, caseInsensitive bool) (Datum, error) {
 	if left == DNull || right == DNull {
 		return DNull, nil
@@ -189,8 +189,7 @@ func TestEval(t *testing.T) {
 			// inputTyps has no relation to the actual expression result type. Used
 			// for generating a batch.
 			inputTyps := []types.T{*types.Int}
-			inputColTyps, err := typeconv.FromColumnTypes(inputTyps)
-			require.NoError(t, err)
+			inputColTyps := typeconv.FromColumnTypes(inputTyps)
 
 			batchesReturned := 0
 			args := colexec.NewColOperatorArgs{
@@ -85,7 +85,8 @@ const (
 	// optimizer).
 	ExplainOpt
 
-	// ExplainVec shows the physical vectorized plan for a query and whether a
+	// ExplainVec shows the physical vectorized plan for a query (including the
+	// row-execution processors that have to be wrapped into it) and whether a
 	// query would be run in "auto" vectorized mode.
 	ExplainVec
 )
@@ -19,9 +19,21 @@ type With struct {
 // CTE represents a common table expression inside of a WITH clause.
 type CTE struct {
 	Name AliasClause
+	Mtr  MaterializeClause
 	Stmt Statement
 }
 
+// MaterializeClause represents the optional [NOT] MATERIALIZED clause of a
+// common table expression.
+type MaterializeClause struct {
+	// Set is true if the clause was specified, in which case Materialize
+	// overrides the default materialization behavior.
+	Set bool
+
+	// Materialize is true for MATERIALIZED and false for NOT MATERIALIZED.
+	Materialize bool
+}
+
 // Format implements the NodeFormatter interface.
 func (node *With) Format(ctx *FmtCtx) {
 	if node == nil {
@@ -36,7 +48,14 @@ func (node *With) Format(ctx *FmtCtx) {
 			ctx.WriteString(", ")
 		}
 		ctx.FormatNode(&cte.Name)
-		ctx.WriteString(" AS (")
+		ctx.WriteString(" AS ")
+		if cte.Mtr.Set {
+			if !cte.Mtr.Materialize {
+				ctx.WriteString("NOT ")
+			}
+			ctx.WriteString("MATERIALIZED ")
+		}
+		ctx.WriteString("(")
 		ctx.FormatNode(cte.Stmt)
 		ctx.WriteString(")")
 	}
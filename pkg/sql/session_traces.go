@@ -0,0 +1,132 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+// sessionTracesMaxCount is the number of traces kept in the
+// system.session_traces ring buffer.
+var sessionTracesMaxCount = settings.RegisterPositiveIntSetting(
+	"sql.trace.session_traces.max_count",
+	"maximum number of session traces kept in system.session_traces; "+
+		"the oldest traces are deleted when new ones are persisted",
+	100,
+)
+
+// sessionTracesTTL is the age after which the persisted traces are deleted.
+var sessionTracesTTL = settings.RegisterNonNegativeDurationSetting(
+	"sql.trace.session_traces.ttl",
+	"amount of time for which the session traces are kept in system.session_traces "+
+		"(0 to keep them until they are evicted by newer traces)",
+	7*24*time.Hour,
+)
+
+// sessionTracesMaxMessages limits the size of a single persisted trace.
+var sessionTracesMaxMessages = settings.RegisterPositiveIntSetting(
+	"sql.trace.session_traces.max_messages",
+	"maximum number of messages of a session trace persisted to system.session_traces; "+
+		"the remaining messages are dropped",
+	10000,
+)
+
+// sessionTracesInsertBatchSize is the number of trace messages inserted by a
+// single statement.
+const sessionTracesInsertBatchSize = 100
+
+// persistSessionTrace writes the rows of a session trace to
+// system.session_traces and then deletes the traces that are beyond the
+// retention limits.
+//
+// The rows are written in their own transactions as the node user, since the
+// users can only read the table.
+func persistSessionTrace(
+	ctx context.Context,
+	ie *InternalExecutor,
+	sv *settings.Values,
+	nodeID roachpb.NodeID,
+	sessionID ClusterWideID,
+	user string,
+	rows []traceRow,
+) error {
+	if maxMessages := sessionTracesMaxMessages.Get(sv); int64(len(rows)) > maxMessages {
+		rows = rows[:maxMessages]
+	}
+	traceID := builtins.GenerateUniqueInt(nodeID)
+	recorded := tree.MakeDTimestampTZ(timeutil.Now(), time.Microsecond)
+	session := tree.NewDString(sessionID.String())
+	username := tree.NewDString(user)
+
+	const numCols = 5 + traceNumCols
+	var stmt strings.Builder
+	args := make([]interface{}, 0, sessionTracesInsertBatchSize*numCols)
+	for start := 0; start < len(rows); start += sessionTracesInsertBatchSize {
+		stmt.Reset()
+		stmt.WriteString("INSERT INTO system.session_traces VALUES ")
+		args = args[:0]
+		for i := start; i < len(rows) && i < start+sessionTracesInsertBatchSize; i++ {
+			if i > start {
+				stmt.WriteString(", ")
+			}
+			stmt.WriteByte('(')
+			for j := 0; j < numCols; j++ {
+				if j > 0 {
+					stmt.WriteString(", ")
+				}
+				fmt.Fprintf(&stmt, "$%d", len(args)+j+1)
+			}
+			stmt.WriteByte(')')
+			args = append(args, tree.NewDInt(traceID), tree.NewDInt(tree.DInt(i)), recorded, session, username)
+			for _, d := range rows[i] {
+				args = append(args, d)
+			}
+		}
+		if _, err := ie.ExecWithUser(
+			ctx, "persist-session-trace", nil /* txn */, security.NodeUser, stmt.String(), args...,
+		); err != nil {
+			return errors.Wrap(err, "failed to persist the session trace")
+		}
+	}
+
+	// Evict the oldest traces. The trace IDs are generated by unique_rowid(),
+	// so they roughly increase with the time at which the traces were persisted.
+	if _, err := ie.ExecWithUser(
+		ctx, "evict-session-traces", nil /* txn */, security.NodeUser,
+		`DELETE FROM system.session_traces WHERE trace_id <= (
+  SELECT DISTINCT trace_id FROM system.session_traces ORDER BY trace_id DESC LIMIT 1 OFFSET $1
+)`,
+		sessionTracesMaxCount.Get(sv),
+	); err != nil {
+		return errors.Wrap(err, "failed to evict the oldest session traces")
+	}
+	if ttl := sessionTracesTTL.Get(sv); ttl > 0 {
+		if _, err := ie.ExecWithUser(
+			ctx, "expire-session-traces", nil /* txn */, security.NodeUser,
+			`DELETE FROM system.session_traces WHERE recorded < now() - $1::INTERVAL`,
+			ttl,
+		); err != nil {
+			return errors.Wrap(err, "failed to delete the expired session traces")
+		}
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
@@ -77,6 +78,11 @@ type SessionData struct {
 	// VectorizeRowCountThreshold indicates the row count above which the
 	// vectorized execution engine will be used if possible.
 	VectorizeRowCountThreshold uint64
+	// ImplicitTxnResultsBufferSize is the minimum number of bytes of results of
+	// an implicit transaction that are buffered before being sent to the
+	// client. Retryable errors are retried automatically as long as no results
+	// have been sent.
+	ImplicitTxnResultsBufferSize int64
 	// ForceSavepointRestart overrides the default SAVEPOINT behavior
 	// for compatibility with certain ORMs. When this flag is set,
 	// the savepoint name will no longer be compared against the magic
@@ -101,6 +107,13 @@ type SessionData struct {
 	// InsertFastPath is true if the fast path for insert (with VALUES input) may
 	// be used.
 	InsertFastPath bool
+	// MutationBatchSize is the max number of rows that a mutation statement
+	// writes in one KV batch before the batch is executed and a new one is
+	// started in the same transaction. If it is 0, the default is used.
+	MutationBatchSize int
+	// SettingOverrides contains the values of the cluster settings that are
+	// overridden for this session.
+	SettingOverrides settings.Overrides
 }
 
 // DataConversionConfig contains the parameters that influence
@@ -264,7 +277,9 @@ const (
 	VectorizeOff VectorizeExecMode = iota
 	// VectorizeAuto means that that any supported queries that use only
 	// streaming operators (i.e. those that do not require any buffering) will be
-	// run using the columnar execution.
+	// run using the columnar execution if doing so is expected to be profitable
+	// given the estimated row count of the query and the number of processors
+	// that don't have a columnar implementation.
 	VectorizeAuto
 	// VectorizeExperimentalOn means that any supported queries will be run using
 	// the columnar execution on.
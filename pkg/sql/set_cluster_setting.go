@@ -67,22 +67,9 @@ func (p *planner) SetClusterSetting(
 			expr := n.Value
 			expr = unresolvedNameToStrVal(expr)
 
-			var requiredType *types.T
-			switch setting.(type) {
-			case *settings.StringSetting, *settings.StateMachineSetting, *settings.ByteSizeSetting:
-				requiredType = types.String
-			case *settings.BoolSetting:
-				requiredType = types.Bool
-			case *settings.IntSetting:
-				requiredType = types.Int
-			case *settings.FloatSetting:
-				requiredType = types.Float
-			case *settings.EnumSetting:
-				requiredType = types.Any
-			case *settings.DurationSetting:
-				requiredType = types.Interval
-			default:
-				return nil, errors.Errorf("unsupported setting type %T", setting)
+			requiredType, err := settingValueType(setting)
+			if err != nil {
+				return nil, err
 			}
 
 			var dummyHelper tree.IndexedVarHelper
@@ -101,6 +88,27 @@ func (p *planner) SetClusterSetting(
 	return &setClusterSettingNode{name: name, st: st, setting: setting, value: value}, nil
 }
 
+// settingValueType returns the type that the values of the given setting are
+// type checked against.
+func settingValueType(setting settings.Setting) (*types.T, error) {
+	switch setting.(type) {
+	case *settings.StringSetting, *settings.StateMachineSetting, *settings.ByteSizeSetting:
+		return types.String, nil
+	case *settings.BoolSetting:
+		return types.Bool, nil
+	case *settings.IntSetting:
+		return types.Int, nil
+	case *settings.FloatSetting:
+		return types.Float, nil
+	case *settings.EnumSetting:
+		return types.Any, nil
+	case *settings.DurationSetting:
+		return types.Interval, nil
+	default:
+		return nil, errors.Errorf("unsupported setting type %T", setting)
+	}
+}
+
 func (n *setClusterSettingNode) startExec(params runParams) error {
 	if !params.p.ExtendedEvalContext().TxnImplicit {
 		return errors.Errorf("SET CLUSTER SETTING cannot be used inside a transaction")
@@ -49,6 +49,19 @@ func (p *planner) SetVar(ctx context.Context, n *tree.SetVar) (planNode, error)
 		return nil, err
 	}
 
+	// The values of session variables are strings, except for the values of
+	// the cluster settings that are overridden per session which are type
+	// checked as in SET CLUSTER SETTING.
+	typ, requireType := types.String, false
+	if _, isVar := varGen[name]; !isVar {
+		if s, ok := lookupSessionOverridableSetting(name); ok {
+			if typ, err = settingValueType(s); err != nil {
+				return nil, err
+			}
+			requireType = true
+		}
+	}
+
 	var typedValues []tree.TypedExpr
 	if len(n.Values) > 0 {
 		isReset := false
@@ -68,7 +81,7 @@ func (p *planner) SetVar(ctx context.Context, n *tree.SetVar) (planNode, error)
 
 				var dummyHelper tree.IndexedVarHelper
 				typedValue, err := p.analyzeExpr(
-					ctx, expr, nil, dummyHelper, types.String, false, "SET SESSION "+name)
+					ctx, expr, nil, dummyHelper, typ, requireType, "SET SESSION "+name)
 				if err != nil {
 					return nil, wrapSetVarError(name, expr.String(), "%v", err)
 				}
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
@@ -149,6 +150,15 @@ func ShowCreateTable(
 	); err != nil {
 		return "", err
 	}
+	if desc.TTLExpirationColumnID != 0 {
+		col, err := desc.FindColumnByID(desc.TTLExpirationColumnID)
+		if err != nil {
+			return "", err
+		}
+		f.WriteString(" WITH (ttl_expiration_column = ")
+		lex.EncodeSQLString(&f.Buffer, col.Name)
+		f.WriteString(")")
+	}
 
 	return f.CloseAndGetString(), nil
 }
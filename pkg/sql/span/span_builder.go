@@ -224,6 +224,75 @@ func (s *Builder) UnconstrainedSpans(forDelete bool) (roachpb.Spans, error) {
 	return s.SpansFromConstraint(nil, exec.ColumnOrdinalSet{}, forDelete)
 }
 
+// SkipScanSuffixSpans generates the spans of a skip scan from an optimizer
+// constraint on the index columns following the first prefixLen columns. The
+// returned spans contain neither the index key prefix nor the encoded prefix
+// columns; they are relative to each distinct prefix found by the scan. An
+// empty EndKey indicates that the span extends to the end of the prefix. A nil
+// constraint results in a single empty span.
+func (s *Builder) SkipScanSuffixSpans(
+	c *constraint.Constraint, prefixLen int,
+) (roachpb.Spans, error) {
+	if len(s.index.Interleave.Ancestors) > 0 {
+		return nil, errors.AssertionFailedf("skip scans are not supported on interleaved indexes")
+	}
+	if c == nil || c.IsUnconstrained() {
+		// Scan all of the rows under each prefix.
+		return roachpb.Spans{{}}, nil
+	}
+	spans := make(roachpb.Spans, 0, c.Spans.Count())
+	for i := 0; i < c.Spans.Count(); i++ {
+		cs := c.Spans.Get(i)
+		var span roachpb.Span
+		var err error
+		span.Key, err = s.encodeSuffixKey(cs.StartKey(), prefixLen)
+		if err != nil {
+			return nil, err
+		}
+		if cs.StartKey().Length() > 0 && cs.StartBoundary() == constraint.ExcludeBoundary {
+			span.Key = span.Key.PrefixEnd()
+		}
+		span.EndKey, err = s.encodeSuffixKey(cs.EndKey(), prefixLen)
+		if err != nil {
+			return nil, err
+		}
+		if cs.EndKey().Length() > 0 && cs.EndBoundary() == constraint.IncludeBoundary {
+			if end := span.EndKey.PrefixEnd(); !end.Equal(span.EndKey) {
+				span.EndKey = end
+			} else {
+				// The key consists of 0xff bytes only (e.g. a NULL in a descending
+				// column), so nothing sorts after it under the prefix.
+				span.EndKey = nil
+			}
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+// encodeSuffixKey encodes a constraint.Key on the index columns following the
+// first prefixLen columns, without any key prefix.
+func (s *Builder) encodeSuffixKey(ck constraint.Key, prefixLen int) (roachpb.Key, error) {
+	var key []byte
+	for i := 0; i < ck.Length(); i++ {
+		var err error
+		// For extra columns (like implicit columns), the direction
+		// is ascending.
+		dir := encoding.Ascending
+		if prefixLen+i < len(s.index.ColumnDirections) {
+			dir, err = s.index.ColumnDirections[prefixLen+i].ToEncodingDirection()
+			if err != nil {
+				return nil, err
+			}
+		}
+		key, err = sqlbase.EncodeTableKey(key, ck.Value(i), dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
 // appendSpansFromConstraintSpan converts a constraint.Span to one or more
 // roachpb.Spans and appends them to the provided spans. It appends multiple
 // spans in the case that multiple, non-adjacent column families should be
@@ -1026,6 +1026,16 @@ func decodeArray(a *DatumAlloc, elementType *types.T, b []byte) (tree.Datum, []b
 	return decodeArrayNoMarshalColumnValue(a, elementType, b)
 }
 
+// DecodeArrayValueContents decodes the contents of an array value, i.e. the
+// bytes stored by encoding.EncodeArrayValue without the length prefix.
+func DecodeArrayValueContents(a *DatumAlloc, elementType *types.T, b []byte) (*tree.DArray, error) {
+	d, _, err := decodeArrayNoMarshalColumnValue(a, elementType, b)
+	if err != nil {
+		return nil, err
+	}
+	return d.(*tree.DArray), nil
+}
+
 // decodeArrayNoMarshalColumnValue skips the step where the MarshalColumnValue
 // is stripped from the bytes. This is required for single-column family arrays.
 func decodeArrayNoMarshalColumnValue(
@@ -156,5 +156,10 @@ const (
 	PgCatalogStatActivityTableID
 	PgCatalogSecurityLabelTableID
 	PgCatalogSharedSecurityLabelTableID
-	MinVirtualID = PgCatalogSharedSecurityLabelTableID
+	CrdbInternalTxnFingerprintStatsTableID
+	CrdbInternalErrorCodesTableID
+	CrdbInternalRangeEventsTableID
+	CrdbInternalContentionEventsTableID
+	CrdbInternalAllocatorDecisionsTableID
+	MinVirtualID = CrdbInternalAllocatorDecisionsTableID
 )
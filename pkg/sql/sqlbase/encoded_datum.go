@@ -437,6 +437,14 @@ func (r EncDatumRow) Compare(
 		panic(fmt.Sprintf("length mismatch: %d types, %d lhs, %d rhs\n%+v\n%+v\n%+v", len(types), len(r), len(rhs), types, r, rhs))
 	}
 	for _, c := range ordering {
+		if c.NullsOrder != tree.DefaultNullsOrder {
+			if cmp, ok := c.compareNulls(r[c.ColIdx].IsNull(), rhs[c.ColIdx].IsNull()); ok {
+				if cmp != 0 {
+					return cmp, nil
+				}
+				continue
+			}
+		}
 		cmp, err := r[c.ColIdx].Compare(&types[c.ColIdx], a, evalCtx, &rhs[c.ColIdx])
 		if err != nil {
 			return 0, err
@@ -463,6 +471,12 @@ func (r EncDatumRow) CompareToDatums(
 		if err := r[c.ColIdx].EnsureDecoded(&types[c.ColIdx], a); err != nil {
 			return 0, err
 		}
+		if cmp, ok := c.compareNulls(r[c.ColIdx].Datum == tree.DNull, rhs[c.ColIdx] == tree.DNull); ok {
+			if cmp != 0 {
+				return cmp, nil
+			}
+			continue
+		}
 		cmp := r[c.ColIdx].Datum.Compare(evalCtx, rhs[c.ColIdx])
 		if cmp != 0 {
 			if c.Direction == encoding.Descending {
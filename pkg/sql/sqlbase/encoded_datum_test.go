@@ -325,6 +325,8 @@ func TestEncDatumRowCompare(t *testing.T) {
 		v[i] = DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
 	}
 
+	null := DatumToEncDatum(types.Int, tree.DNull)
+
 	asc := encoding.Ascending
 	desc := encoding.Descending
 
@@ -342,75 +344,111 @@ func TestEncDatumRowCompare(t *testing.T) {
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{1, desc}},
+			ord:  ColumnOrdering{{ColIdx: 1, Direction: desc}},
 			cmp:  0,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{0, asc}, {1, desc}},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc}, {ColIdx: 1, Direction: desc}},
 			cmp:  0,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{2, asc}},
+			ord:  ColumnOrdering{{ColIdx: 2, Direction: asc}},
 			cmp:  -1,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[3]},
 			row2: EncDatumRow{v[0], v[1], v[2]},
-			ord:  ColumnOrdering{{2, asc}},
+			ord:  ColumnOrdering{{ColIdx: 2, Direction: asc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{2, asc}, {0, asc}, {1, asc}},
+			ord:  ColumnOrdering{{ColIdx: 2, Direction: asc}, {ColIdx: 0, Direction: asc}, {ColIdx: 1, Direction: asc}},
 			cmp:  -1,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{0, asc}, {2, desc}},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc}, {ColIdx: 2, Direction: desc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[0], v[1], v[2]},
 			row2: EncDatumRow{v[0], v[1], v[3]},
-			ord:  ColumnOrdering{{1, desc}, {0, asc}, {2, desc}},
+			ord:  ColumnOrdering{{ColIdx: 1, Direction: desc}, {ColIdx: 0, Direction: asc}, {ColIdx: 2, Direction: desc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[2], v[3], v[4]},
 			row2: EncDatumRow{v[1], v[3], v[0]},
-			ord:  ColumnOrdering{{0, asc}},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[2], v[3], v[4]},
 			row2: EncDatumRow{v[1], v[3], v[0]},
-			ord:  ColumnOrdering{{1, desc}, {0, asc}},
+			ord:  ColumnOrdering{{ColIdx: 1, Direction: desc}, {ColIdx: 0, Direction: asc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[2], v[3], v[4]},
 			row2: EncDatumRow{v[1], v[3], v[0]},
-			ord:  ColumnOrdering{{1, asc}, {0, asc}},
+			ord:  ColumnOrdering{{ColIdx: 1, Direction: asc}, {ColIdx: 0, Direction: asc}},
 			cmp:  1,
 		},
 		{
 			row1: EncDatumRow{v[2], v[3], v[4]},
 			row2: EncDatumRow{v[1], v[3], v[0]},
-			ord:  ColumnOrdering{{1, asc}, {0, desc}},
+			ord:  ColumnOrdering{{ColIdx: 1, Direction: asc}, {ColIdx: 0, Direction: desc}},
 			cmp:  -1,
 		},
 		{
 			row1: EncDatumRow{v[2], v[3], v[4]},
 			row2: EncDatumRow{v[1], v[3], v[0]},
-			ord:  ColumnOrdering{{0, desc}, {1, asc}},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: desc}, {ColIdx: 1, Direction: asc}},
+			cmp:  -1,
+		},
+		{
+			row1: EncDatumRow{null, v[1], v[2]},
+			row2: EncDatumRow{v[0], v[1], v[2]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc}},
+			cmp:  -1,
+		},
+		{
+			row1: EncDatumRow{null, v[1], v[2]},
+			row2: EncDatumRow{v[0], v[1], v[2]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: desc}},
+			cmp:  1,
+		},
+		{
+			row1: EncDatumRow{null, v[1], v[2]},
+			row2: EncDatumRow{v[0], v[1], v[2]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc, NullsOrder: tree.NullsLast}},
+			cmp:  1,
+		},
+		{
+			row1: EncDatumRow{null, v[1], v[2]},
+			row2: EncDatumRow{v[0], v[1], v[2]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: desc, NullsOrder: tree.NullsFirst}},
 			cmp:  -1,
 		},
+		{
+			row1: EncDatumRow{v[0], null, v[2]},
+			row2: EncDatumRow{v[0], v[1], v[2]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc}, {ColIdx: 1, Direction: desc, NullsOrder: tree.NullsLast}},
+			cmp:  1,
+		},
+		{
+			row1: EncDatumRow{null, v[1], v[2]},
+			row2: EncDatumRow{null, v[1], v[3]},
+			ord:  ColumnOrdering{{ColIdx: 0, Direction: asc, NullsOrder: tree.NullsLast}, {ColIdx: 2, Direction: desc}},
+			cmp:  1,
+		},
 	}
 
 	a := &DatumAlloc{}
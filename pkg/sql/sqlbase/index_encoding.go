@@ -11,6 +11,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -782,6 +783,59 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	return nil, errors.AssertionFailedf("trying to apply inverted index to non JSON type")
 }
 
+// sortAndDedupeInvertedKeys sorts the inverted index keys of a row and removes
+// duplicates, which documents with repeated values (e.g. the JSON array
+// [1, 1]) generate. The entries of an inverted index are thus written in key
+// order, at most once each, and the entries of two versions of a row can be
+// diffed in a single pass (see DiffInvertedIndexEntries).
+func sortAndDedupeInvertedKeys(keys [][]byte) [][]byte {
+	if len(keys) <= 1 {
+		return keys
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	n := 1
+	for i := 1; i < len(keys); i++ {
+		if !bytes.Equal(keys[i], keys[n-1]) {
+			keys[n] = keys[i]
+			n++
+		}
+	}
+	return keys[:n]
+}
+
+// DiffInvertedIndexEntries compares the entries of an inverted index for the
+// old and new versions of a row, as returned by EncodeSecondaryIndex, and
+// calls del for every old entry that isn't part of the new entries and put for
+// every new entry that isn't part of the old entries. An entry whose value
+// changed is passed to del and then to put. Unchanged entries, which usually
+// make up most of the entries of an updated document, are skipped.
+func DiffInvertedIndexEntries(oldEntries, newEntries []IndexEntry, del, put func(*IndexEntry)) {
+	i, j := 0, 0
+	for i < len(oldEntries) && j < len(newEntries) {
+		switch c := bytes.Compare(oldEntries[i].Key, newEntries[j].Key); {
+		case c < 0:
+			del(&oldEntries[i])
+			i++
+		case c > 0:
+			put(&newEntries[j])
+			j++
+		default:
+			if !newEntries[j].Value.EqualData(oldEntries[i].Value) {
+				del(&oldEntries[i])
+				put(&newEntries[j])
+			}
+			i++
+			j++
+		}
+	}
+	for ; i < len(oldEntries); i++ {
+		del(&oldEntries[i])
+	}
+	for ; j < len(newEntries); j++ {
+		put(&newEntries[j])
+	}
+}
+
 // EncodePrimaryIndex constructs a list of k/v pairs for a row encoded as a primary index.
 // This function mirrors the encoding logic in prepareInsertOrUpdateBatch in pkg/sql/row/writer.go.
 // It is somewhat duplicated here due to the different arguments that prepareOrInsertUpdateBatch needs
@@ -878,6 +932,7 @@ func EncodeSecondaryIndex(
 	var err error
 	if secondaryIndex.Type == IndexDescriptor_INVERTED {
 		secondaryKeys, err = EncodeInvertedIndexKeys(tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
+		secondaryKeys = sortAndDedupeInvertedKeys(secondaryKeys)
 	} else {
 		var secondaryIndexKey []byte
 		secondaryIndexKey, containsNull, err = EncodeIndexKey(
@@ -0,0 +1,147 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// invertedEntries returns the inverted index entries of a JSON document, the
+// way EncodeSecondaryIndex does.
+func invertedEntries(tb testing.TB, doc string) []IndexEntry {
+	j, err := json.ParseJSON(doc)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	keys, err := EncodeInvertedIndexTableKeys(tree.NewDJSON(j), []byte("prefix/"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	keys = sortAndDedupeInvertedKeys(keys)
+	entries := make([]IndexEntry, len(keys))
+	for i := range keys {
+		entries[i].Key = roachpb.Key(keys[i])
+	}
+	return entries
+}
+
+func entryKeys(entries []IndexEntry) []string {
+	keys := make([]string, len(entries))
+	for i := range entries {
+		keys[i] = entries[i].Key.String()
+	}
+	return keys
+}
+
+func TestDiffInvertedIndexEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		old, new string
+		// del and put are documents whose entries are the expected deleted and
+		// added entries.
+		del, put string
+	}{
+		{old: `{"a": 1}`, new: `{"a": 1}`, del: `{}`, put: `{}`},
+		{old: `{"a": 1}`, new: `{"a": 2}`, del: `{"a": 1}`, put: `{"a": 2}`},
+		{old: `{"a": 1, "b": [1, 2]}`, new: `{"a": 1, "b": [2, 3]}`, del: `{"b": [1]}`, put: `{"b": [3]}`},
+		{old: `{"b": [1, 1, 2]}`, new: `{"b": [2, 2]}`, del: `{"b": [1]}`, put: `{}`},
+		{old: `{"a": "x", "c": true}`, new: `{"b": "y"}`, del: `{"a": "x", "c": true}`, put: `{"b": "y"}`},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s->%s", tc.old, tc.new), func(t *testing.T) {
+			var del, put []IndexEntry
+			DiffInvertedIndexEntries(invertedEntries(t, tc.old), invertedEntries(t, tc.new),
+				func(e *IndexEntry) { del = append(del, *e) },
+				func(e *IndexEntry) { put = append(put, *e) },
+			)
+			// The entries of an empty object aren't part of the diffs.
+			expDel, expPut := invertedEntries(t, tc.del), invertedEntries(t, tc.put)
+			if tc.del == `{}` {
+				expDel = nil
+			}
+			if tc.put == `{}` {
+				expPut = nil
+			}
+			if a, e := strings.Join(entryKeys(del), ","), strings.Join(entryKeys(expDel), ","); a != e {
+				t.Errorf("expected deletions %s, found %s", e, a)
+			}
+			if a, e := strings.Join(entryKeys(put), ","), strings.Join(entryKeys(expPut), ","); a != e {
+				t.Errorf("expected additions %s, found %s", e, a)
+			}
+		})
+	}
+}
+
+func TestSortAndDedupeInvertedKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	entries := invertedEntries(t, `{"b": [3, 1, 3, 2, 1], "a": {"c": [true, true]}}`)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, found %d: %v", len(entries), entryKeys(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if bytes.Compare(entries[i-1].Key, entries[i].Key) >= 0 {
+			t.Fatalf("entries not sorted and unique: %v", entryKeys(entries))
+		}
+	}
+}
+
+// BenchmarkInvertedIndexUpdate measures the write amplification of updating
+// one field of a large JSON document with an inverted index, by reporting the
+// number of KV operations issued for the inverted index entries per update,
+// both when all the entries are rewritten and when only the changed ones are.
+func BenchmarkInvertedIndexUpdate(b *testing.B) {
+	for _, numFields := range []int{10, 100, 1000} {
+		var oldDoc, newDoc strings.Builder
+		oldDoc.WriteString("{")
+		newDoc.WriteString("{")
+		for i := 0; i < numFields; i++ {
+			if i > 0 {
+				oldDoc.WriteString(", ")
+				newDoc.WriteString(", ")
+			}
+			fmt.Fprintf(&oldDoc, `"f%d": [%d, "v%d"]`, i, i, i)
+			if i == 0 {
+				fmt.Fprintf(&newDoc, `"f%d": [%d, "updated"]`, i, i)
+			} else {
+				fmt.Fprintf(&newDoc, `"f%d": [%d, "v%d"]`, i, i, i)
+			}
+		}
+		oldDoc.WriteString("}")
+		newDoc.WriteString("}")
+		oldEntries, newEntries := invertedEntries(b, oldDoc.String()), invertedEntries(b, newDoc.String())
+
+		b.Run(fmt.Sprintf("fields=%d/rewrite", numFields), func(b *testing.B) {
+			ops := 0
+			for i := 0; i < b.N; i++ {
+				ops += len(oldEntries) + len(newEntries)
+			}
+			b.ReportMetric(float64(ops)/float64(b.N), "kv-ops/op")
+		})
+		b.Run(fmt.Sprintf("fields=%d/diff", numFields), func(b *testing.B) {
+			ops := 0
+			count := func(*IndexEntry) { ops++ }
+			for i := 0; i < b.N; i++ {
+				DiffInvertedIndexEntries(oldEntries, newEntries, count, count)
+			}
+			b.ReportMetric(float64(ops)/float64(b.N), "kv-ops/op")
+		})
+	}
+}
@@ -20,6 +20,42 @@ import (
 type ColumnOrderInfo struct {
 	ColIdx    int
 	Direction encoding.Direction
+	// NullsOrder overrides the placement of the NULLs. By default, NULLs are
+	// ordered before all of the other values, so they come first in the
+	// ascending and last in the descending order.
+	NullsOrder tree.NullsOrder
+}
+
+// NullsReversed returns whether the NULLs are placed on the opposite side to
+// the default one, that is last in the ascending or first in the descending
+// order.
+func (c ColumnOrderInfo) NullsReversed() bool {
+	if c.Direction == encoding.Descending {
+		return c.NullsOrder == tree.NullsFirst
+	}
+	return c.NullsOrder == tree.NullsLast
+}
+
+// compareNulls compares lhs and rhs on the column if the placement of the
+// NULLs is overridden and at least one of them is NULL. The direction of the
+// column has already been applied to the result. ok is false if the values
+// have to be compared as usual.
+func (c ColumnOrderInfo) compareNulls(lhsNull, rhsNull bool) (cmp int, ok bool) {
+	if c.NullsOrder == tree.DefaultNullsOrder || (!lhsNull && !rhsNull) {
+		return 0, false
+	}
+	switch {
+	case lhsNull && rhsNull:
+		return 0, true
+	case lhsNull:
+		cmp = -1
+	default:
+		cmp = 1
+	}
+	if c.NullsOrder == tree.NullsLast {
+		cmp = -cmp
+	}
+	return cmp, true
 }
 
 // ColumnOrdering is used to describe a desired column ordering. For example,
@@ -40,6 +76,12 @@ func CompareDatums(ordering ColumnOrdering, evalCtx *tree.EvalContext, lhs, rhs
 		// not sure this always holds as `CASE` expressions can return different
 		// types for a column for different rows. Investigate how other RDBMs
 		// handle this.
+		if cmp, ok := c.compareNulls(lhs[c.ColIdx] == tree.DNull, rhs[c.ColIdx] == tree.DNull); ok {
+			if cmp != 0 {
+				return cmp
+			}
+			continue
+		}
 		if cmp := lhs[c.ColIdx].Compare(evalCtx, rhs[c.ColIdx]); cmp != 0 {
 			if c.Direction == encoding.Descending {
 				cmp = -cmp
@@ -19,3 +19,12 @@ var ParallelScans = settings.RegisterBoolSetting(
 	"parallelizes scanning different ranges when the maximum result size can be deduced",
 	true,
 )
+
+// ScanParallelism controls the number of KV batches that a table reader scanning
+// multiple spans without a limit issues concurrently.
+var ScanParallelism = settings.RegisterNonNegativeIntSetting(
+	"sql.parallel_scans.max_concurrent_batches",
+	"maximum number of KV batches that a table reader scanning multiple spans without a limit "+
+		"issues concurrently; 1 makes such scans sequential",
+	4,
+)
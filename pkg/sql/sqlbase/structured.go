@@ -1769,6 +1769,12 @@ func (desc *TableDescriptor) ValidateTable() error {
 		if err := desc.validatePartitioning(); err != nil {
 			return err
 		}
+		if desc.TTLExpirationColumnID != 0 {
+			if _, ok := columnIDs[desc.TTLExpirationColumnID]; !ok {
+				return errors.AssertionFailedf("TTL expiration column %d does not exist",
+					errors.Safe(desc.TTLExpirationColumnID))
+			}
+		}
 	}
 
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
@@ -241,6 +241,49 @@ CREATE TABLE system.protected_ts_records (
    verified  BOOL NOT NULL DEFAULT (false),
    FAMILY "primary" (id, ts, meta_type, meta, num_spans, spans, verified)
 );`
+
+	// session_traces stores the session traces persisted by SET TRACING. Every
+	// trace is a sequence of rows with the same trace_id, which have the same
+	// columns as crdb_internal.session_trace. The table is a ring buffer: the
+	// oldest traces are deleted when new ones are persisted.
+	SessionTracesTableSchema = `
+CREATE TABLE system.session_traces (
+   trace_id    INT8 NOT NULL,
+   ordinal     INT8 NOT NULL,        -- the position of the message in the trace
+   recorded    TIMESTAMPTZ NOT NULL, -- the time at which the trace was persisted
+   session_id  STRING NOT NULL,
+   username    STRING NOT NULL,
+   span_idx    INT8 NOT NULL,
+   message_idx INT8 NOT NULL,
+   timestamp   TIMESTAMPTZ NOT NULL,
+   duration    INTERVAL,
+   operation   STRING,
+   loc         STRING NOT NULL,
+   tag         STRING NOT NULL,
+   message     STRING NOT NULL,
+   age         INTERVAL NOT NULL,
+   PRIMARY KEY (trace_id, ordinal),
+   FAMILY "primary" (trace_id, ordinal, recorded, session_id, username, span_idx, message_idx, timestamp, duration, operation, loc, tag, message, age)
+);`
+
+	// plan_changes stores the plan changes of statement fingerprints after
+	// which the mean service latency of the fingerprint degraded beyond
+	// sql.metrics.statement_details.plan_regression.latency_threshold. The
+	// gists describe the shape of the old and new plans.
+	PlanChangesTableSchema = `
+CREATE TABLE system.plan_changes (
+   id          INT8 NOT NULL DEFAULT unique_rowid(),
+   recorded    TIMESTAMPTZ NOT NULL,
+   node_id     INT8 NOT NULL,
+   app_name    STRING NOT NULL,
+   fingerprint STRING NOT NULL,
+   old_gist    STRING NOT NULL,
+   new_gist    STRING NOT NULL,
+   old_latency FLOAT8 NOT NULL, -- mean service latency with the old plan, in seconds
+   new_latency FLOAT8 NOT NULL, -- mean service latency with the new plan, in seconds
+   PRIMARY KEY (id),
+   FAMILY "primary" (id, recorded, node_id, app_name, fingerprint, old_gist, new_gist, old_latency, new_latency)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -289,6 +332,8 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.ReportsMetaTableID:                   privilege.ReadWriteData,
 	keys.ProtectedTimestampsMetaTableID:       privilege.ReadData,
 	keys.ProtectedTimestampsRecordsTableID:    privilege.ReadData,
+	keys.SessionTracesTableID:                 privilege.ReadData,
+	keys.PlanChangesTableID:                   privilege.ReadData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -1241,6 +1286,99 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	SessionTracesTable = TableDescriptor{
+		Name:                    "session_traces",
+		ID:                      keys.SessionTracesTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "trace_id", ID: 1, Type: *types.Int},
+			{Name: "ordinal", ID: 2, Type: *types.Int},
+			{Name: "recorded", ID: 3, Type: *types.TimestampTZ},
+			{Name: "session_id", ID: 4, Type: *types.String},
+			{Name: "username", ID: 5, Type: *types.String},
+			{Name: "span_idx", ID: 6, Type: *types.Int},
+			{Name: "message_idx", ID: 7, Type: *types.Int},
+			{Name: "timestamp", ID: 8, Type: *types.TimestampTZ},
+			{Name: "duration", ID: 9, Type: *types.Interval, Nullable: true},
+			{Name: "operation", ID: 10, Type: *types.String, Nullable: true},
+			{Name: "loc", ID: 11, Type: *types.String},
+			{Name: "tag", ID: 12, Type: *types.String},
+			{Name: "message", ID: 13, Type: *types.String},
+			{Name: "age", ID: 14, Type: *types.Interval},
+		},
+		NextColumnID: 15,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ColumnNames: []string{
+					"trace_id", "ordinal", "recorded", "session_id", "username", "span_idx", "message_idx",
+					"timestamp", "duration", "operation", "loc", "tag", "message", "age",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Version:          1,
+			Unique:           true,
+			ColumnNames:      []string{"trace_id", "ordinal"},
+			ColumnIDs:        []ColumnID{1, 2},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.SessionTracesTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	PlanChangesTable = TableDescriptor{
+		Name:                    "plan_changes",
+		ID:                      keys.PlanChangesTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Int, DefaultExpr: &uniqueRowIDString},
+			{Name: "recorded", ID: 2, Type: *types.TimestampTZ},
+			{Name: "node_id", ID: 3, Type: *types.Int},
+			{Name: "app_name", ID: 4, Type: *types.String},
+			{Name: "fingerprint", ID: 5, Type: *types.String},
+			{Name: "old_gist", ID: 6, Type: *types.String},
+			{Name: "new_gist", ID: 7, Type: *types.String},
+			{Name: "old_latency", ID: 8, Type: *types.Float},
+			{Name: "new_latency", ID: 9, Type: *types.Float},
+		},
+		NextColumnID: 10,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ColumnNames: []string{
+					"id", "recorded", "node_id", "app_name", "fingerprint",
+					"old_gist", "new_gist", "old_latency", "new_latency",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Version:          1,
+			Unique:           true,
+			ColumnNames:      []string{"id"},
+			ColumnIDs:        []ColumnID{1},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC},
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.PlanChangesTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -1293,6 +1431,8 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(keys.SystemDatabaseID, &ReplicationCriticalLocalitiesTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ProtectedTimestampsMetaTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ProtectedTimestampsRecordsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &SessionTracesTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &PlanChangesTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
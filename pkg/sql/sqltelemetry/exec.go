@@ -19,3 +19,8 @@ var DistSQLExecCounter = telemetry.GetCounterOnce("sql.exec.query.is-distributed
 // VecExecCounter is to be incremented whenever a query runs with the vectorized
 // execution engine.
 var VecExecCounter = telemetry.GetCounterOnce("sql.exec.query.is-vectorized")
+
+// VecFallbackCounter is to be incremented whenever a query that was requested
+// to run with the vectorized execution engine falls back to the row-by-row
+// engine because one of its flows couldn't be vectorized.
+var VecFallbackCounter = telemetry.GetCounterOnce("sql.exec.query.vectorize-fallback")
@@ -34,6 +34,11 @@ type Statement struct {
 	// Given that the PreparedStatement can be modified during planning, it is
 	// not safe for use on multiple threads.
 	Prepared *PreparedStatement
+
+	// continueImplicitTxn is set if the statement is followed by other
+	// statements of the same query string that run in the same implicit
+	// transaction. See StmtBatchPosition.
+	continueImplicitTxn bool
 }
 
 func (s Statement) String() string {
@@ -95,6 +95,13 @@ func (sr *SampleReservoir) Push(x interface{}) { panic("unimplemented") }
 // Pop is part of heap.Interface, but we're not using it.
 func (sr *SampleReservoir) Pop() interface{} { panic("unimplemented") }
 
+// WouldSample returns whether a row with the given rank would be added to the
+// reservoir by SampleRow. It allows the callers to avoid constructing the rows
+// that would be dropped anyway.
+func (sr *SampleReservoir) WouldSample(rank uint64) bool {
+	return len(sr.samples) < cap(sr.samples) || (len(sr.samples) > 0 && rank < sr.samples[0].Rank)
+}
+
 // SampleRow looks at a row and either drops it or adds it to the reservoir.
 func (sr *SampleReservoir) SampleRow(
 	ctx context.Context, evalCtx *tree.EvalContext, row sqlbase.EncDatumRow, rank uint64,
@@ -15,14 +15,17 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -386,6 +389,48 @@ func (tc *TableCollection) getTableVersionByID(
 	return table, nil
 }
 
+// txnDeadlineExtensionEnabled controls whether the deadlines of transactions
+// that hold table leases are extended by renewing the leases.
+var txnDeadlineExtensionEnabled = settings.RegisterBoolSetting(
+	"sql.txn.deadline_extension.enabled",
+	"if set, the deadline of a transaction that is about to be reached when the "+
+		"transaction commits is extended by renewing the table leases it holds",
+	true,
+)
+
+// maybeExtendDeadline moves the deadline of txn forward if it expires within
+// the lease renewal timeout of now, by renewing the leases on the tables used
+// by the transaction. The deadline is the earliest expiration of these
+// leases, so without the renewal a transaction that runs longer than the
+// lease duration fails to commit. It returns whether the deadline was
+// extended.
+func (tc *TableCollection) maybeExtendDeadline(
+	ctx context.Context, txn *client.Txn, now hlc.Timestamp,
+) bool {
+	deadline, ok := txn.Deadline()
+	if !ok || len(tc.leasedTables) == 0 ||
+		time.Duration(deadline.WallTime-now.WallTime) >= tc.leaseMgr.leaseRenewalTimeout {
+		return false
+	}
+	var newDeadline hlc.Timestamp
+	for _, table := range tc.leasedTables {
+		expiration, err := tc.leaseMgr.ExtendLease(ctx, now, table)
+		if err != nil {
+			// The transaction keeps its deadline, which it might not meet.
+			log.VEventf(ctx, 2, "failed to extend lease on table '%s': %v", table.Name, err)
+			return false
+		}
+		if newDeadline.IsEmpty() || expiration.Less(newDeadline) {
+			newDeadline = expiration
+		}
+	}
+	if !txn.ExtendDeadline(ctx, newDeadline) {
+		return false
+	}
+	log.VEventf(ctx, 2, "extended txn deadline from %s to %s", deadline, newDeadline)
+	return true
+}
+
 // getMutableTableVersionByID is a variant of sqlbase.GetTableDescFromID which returns a mutable
 // table descriptor of the table modified in the same transaction.
 func (tc *TableCollection) getMutableTableVersionByID(
@@ -14,11 +14,15 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
 )
 
 // expressionCarrier handles visiting sub-expressions.
@@ -103,6 +107,10 @@ type extendedTableWriter interface {
 	// This should flush the current batch.
 	flushAndStartNewBatch(context.Context) error
 
+	// batchFull returns whether the current batch should be ended before
+	// more rows are added to it.
+	batchFull() bool
+
 	// curBatchSize returns an upper bound for the amount of KV work
 	// needed for the current batch. This cannot reflect the actual KV
 	// batch size because the actual KV batch will be constructed only
@@ -125,11 +133,93 @@ type tableWriterBase struct {
 	b *client.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// maxBatchSize is the number of rows after which the current batch is
+	// executed and a new one is started (see batchFull).
+	maxBatchSize int
+	// maxCommandSize is the max size of a Raft command (see
+	// ExecutorConfig.MaxCommandSize), or 0 if it is unknown. The batches are
+	// executed before their mutations would exceed half of it, and the rows
+	// whose mutations alone exceed it are rejected.
+	maxCommandSize int
+	// maxRowSize is the size of the largest mutations of a single row added to
+	// a batch so far. It is used to project the size of the current batch after
+	// the next row.
+	maxRowSize int
+	// extendTxnDeadline, if set, extends the deadline of txn if it is about to
+	// be reached. It is called before each batch is run, so that long mutations
+	// and their automatic commit don't fail on a deadline set when the
+	// statement started.
+	extendTxnDeadline func(context.Context)
 }
 
-func (tb *tableWriterBase) init(txn *client.Txn) {
+// defaultMutationBatchSize is the default value of the mutation_batch_size
+// session variable, which is the max number of rows processed by a mutation
+// statement in one KV batch before the batch is executed and a new one is
+// started in the same transaction.
+const defaultMutationBatchSize = 10000
+
+// maxCommandSizeSettingName is the name of the cluster setting behind
+// ExecutorConfig.MaxCommandSize, which is mentioned in the errors of the rows
+// exceeding it.
+const maxCommandSizeSettingName = "kv.raft.command.max_size"
+
+func (tb *tableWriterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) {
 	tb.txn = txn
 	tb.b = txn.NewBatch()
+	tb.maxBatchSize = defaultMutationBatchSize
+	if evalCtx == nil {
+		return
+	}
+	if evalCtx.SessionData != nil && evalCtx.SessionData.MutationBatchSize > 0 {
+		tb.maxBatchSize = evalCtx.SessionData.MutationBatchSize
+	}
+	if p, ok := evalCtx.Planner.(*planner); ok {
+		tb.extendTxnDeadline = p.extendedEvalCtx.ExtendTxnDeadline
+		if maxSize := p.ExecCfg().MaxCommandSize; maxSize != nil && evalCtx.Settings != nil {
+			tb.maxCommandSize = int(maxSize.Get(&evalCtx.Settings.SV))
+		}
+	}
+}
+
+// batchFull returns whether the current batch should be executed before more
+// rows are added to it, either because it has maxBatchSize rows or because its
+// mutations would exceed half of the max size of a Raft command if the next
+// row was as large as the largest row so far.
+func (tb *tableWriterBase) batchFull() bool {
+	if tb.batchSize >= tb.maxBatchSize {
+		return true
+	}
+	if tb.maxCommandSize <= 0 {
+		return false
+	}
+	batchBytes := tb.b.ApproximateMutationBytes()
+	return batchBytes > 0 && batchBytes+tb.maxRowSize > tb.maxCommandSize/2
+}
+
+// checkRowSize returns an error if the mutations added to the current batch
+// since it had rowStart mutation bytes, which are the mutations of a single
+// row, exceed the max size of a Raft command. Such a row can't be written
+// regardless of how the statement is split into batches, so it is better to
+// fail before the batch is sent.
+func (tb *tableWriterBase) checkRowSize(
+	rowStart int, tableDesc *sqlbase.ImmutableTableDescriptor,
+) error {
+	if tb.maxCommandSize <= 0 {
+		return nil
+	}
+	rowSize := tb.b.ApproximateMutationBytes() - rowStart
+	if rowSize > tb.maxRowSize {
+		tb.maxRowSize = rowSize
+	}
+	if rowSize > tb.maxCommandSize {
+		err := pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"the KV writes of a row of table %q take %s, which exceeds the maximum command size %s",
+			tableDesc.Name, humanizeutil.IBytes(int64(rowSize)), humanizeutil.IBytes(int64(tb.maxCommandSize)),
+		)
+		return errors.WithHintf(err, "the maximum command size is controlled by the %s cluster setting",
+			maxCommandSizeSettingName)
+	}
+	return nil
 }
 
 // flushAndStartNewBatch shares the common flushAndStartNewBatch()
@@ -137,6 +227,7 @@ func (tb *tableWriterBase) init(txn *client.Txn) {
 func (tb *tableWriterBase) flushAndStartNewBatch(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor,
 ) error {
+	tb.maybeExtendTxnDeadline(ctx)
 	if err := tb.txn.Run(ctx, tb.b); err != nil {
 		return row.ConvertBatchError(ctx, tableDesc, tb.b)
 	}
@@ -157,6 +248,7 @@ func (tb *tableWriterBase) finalize(
 		// An auto-txn can commit the transaction with the batch. This is an
 		// optimization to avoid an extra round-trip to the transaction
 		// coordinator.
+		tb.maybeExtendTxnDeadline(ctx)
 		err = tb.txn.CommitInBatch(ctx, tb.b)
 	} else {
 		err = tb.txn.Run(ctx, tb.b)
@@ -168,6 +260,14 @@ func (tb *tableWriterBase) finalize(
 	return nil
 }
 
+// maybeExtendTxnDeadline extends the deadline of the transaction, if the
+// tableWriter was initialized by a session that can do so.
+func (tb *tableWriterBase) maybeExtendTxnDeadline(ctx context.Context) {
+	if tb.extendTxnDeadline != nil {
+		tb.extendTxnDeadline(ctx)
+	}
+}
+
 func (tb *tableWriterBase) enableAutoCommit() {
 	tb.autoCommit = autoCommitEnabled
 }
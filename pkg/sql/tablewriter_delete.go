@@ -41,8 +41,8 @@ func (*tableDeleter) desc() string { return "deleter" }
 func (td *tableDeleter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
 
 // init is part of the tableWriter interface.
-func (td *tableDeleter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	td.tableWriterBase.init(txn)
+func (td *tableDeleter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	td.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -61,7 +61,11 @@ func (td *tableDeleter) atBatchEnd(_ context.Context, _ bool) error { return nil
 
 func (td *tableDeleter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	td.batchSize++
-	return td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV)
+	rowStart := td.b.ApproximateMutationBytes()
+	if err := td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV); err != nil {
+		return err
+	}
+	return td.checkRowSize(rowStart, td.rd.Helper.TableDesc)
 }
 
 // fastPathDeleteAvailable returns true if the fastDelete optimization can be used.
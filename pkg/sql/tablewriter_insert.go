@@ -30,15 +30,19 @@ type tableInserter struct {
 func (*tableInserter) desc() string { return "inserter" }
 
 // init is part of the tableWriter interface.
-func (ti *tableInserter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	ti.tableWriterBase.init(txn)
+func (ti *tableInserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	ti.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
 // row is part of the tableWriter interface.
 func (ti *tableInserter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
 	ti.batchSize++
-	return ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV)
+	rowStart := ti.b.ApproximateMutationBytes()
+	if err := ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV); err != nil {
+		return err
+	}
+	return ti.checkRowSize(rowStart, ti.tableDesc())
 }
 
 // atBatchEnd is part of the extendedTableWriter interface.
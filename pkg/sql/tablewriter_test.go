@@ -0,0 +1,95 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestTableWriterBatchFullBoundary verifies that the batches of a tableWriter
+// are executed before the next row would make their mutations exceed half of
+// the max command size, rather than once they exceed it.
+func TestTableWriterBatchFullBoundary(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc := sqlbase.NewImmutableTableDescriptor(sqlbase.TableDescriptor{Name: "t"})
+	value := strings.Repeat("a", 100)
+	addRow := func(tb *tableWriterBase, i int) error {
+		tb.batchSize++
+		rowStart := tb.b.ApproximateMutationBytes()
+		tb.b.Put(fmt.Sprintf("k%04d", i), value)
+		return tb.checkRowSize(rowStart, tableDesc)
+	}
+
+	// Measure the size of the mutations of a row.
+	var probe client.Batch
+	probe.Put(fmt.Sprintf("k%04d", 0), value)
+	rowSize := probe.ApproximateMutationBytes()
+
+	testCases := []struct {
+		maxCommandSize int
+		// expectedRows is the number of rows of every batch.
+		expectedRows int
+	}{
+		// Three rows fill exactly half of the max command size.
+		{maxCommandSize: 2 * 3 * rowSize, expectedRows: 3},
+		// A third row would cross it by a single byte.
+		{maxCommandSize: 2 * (3*rowSize - 1), expectedRows: 2},
+		// Every row is at least half of it.
+		{maxCommandSize: 2 * rowSize, expectedRows: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("maxCommandSize=%d", tc.maxCommandSize), func(t *testing.T) {
+			tb := tableWriterBase{
+				b:              &client.Batch{},
+				maxBatchSize:   defaultMutationBatchSize,
+				maxCommandSize: tc.maxCommandSize,
+			}
+			for i := 0; i < 12; i++ {
+				if err := addRow(&tb, i); err != nil {
+					t.Fatal(err)
+				}
+				if !tb.batchFull() {
+					continue
+				}
+				if tb.batchSize != tc.expectedRows {
+					t.Fatalf("expected batches of %d rows, got %d", tc.expectedRows, tb.batchSize)
+				}
+				if size := tb.b.ApproximateMutationBytes(); size > tc.maxCommandSize/2 {
+					t.Fatalf("batch of %d bytes exceeds half of the max command size %d",
+						size, tc.maxCommandSize)
+				}
+				tb.b = &client.Batch{}
+				tb.batchSize = 0
+			}
+		})
+	}
+
+	t.Run("row too large", func(t *testing.T) {
+		tb := tableWriterBase{
+			b:              &client.Batch{},
+			maxBatchSize:   defaultMutationBatchSize,
+			maxCommandSize: rowSize - 1,
+		}
+		err := addRow(&tb, 0)
+		if code := pgerror.GetPGCode(err); code != pgcode.ProgramLimitExceeded {
+			t.Fatalf("expected error code %s, got %v", pgcode.ProgramLimitExceeded, err)
+		}
+	})
+}
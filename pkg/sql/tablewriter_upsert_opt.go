@@ -114,7 +114,7 @@ type optTableUpserter struct {
 
 // init is part of the tableWriter interface.
 func (tu *optTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 	tableDesc := tu.tableDesc()
 
 	tu.insertRows.Init(
@@ -257,6 +257,17 @@ func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bo
 	tu.batchSize++
 	tu.resultCount++
 
+	rowStart := tu.b.ApproximateMutationBytes()
+	if err := tu.upsertRow(ctx, row, traceKV); err != nil {
+		return err
+	}
+	return tu.checkRowSize(rowStart, tu.tableDesc())
+}
+
+// upsertRow inserts or updates the given row, depending on whether it
+// conflicts with an existing row.
+func (tu *optTableUpserter) upsertRow(ctx context.Context, row tree.Datums, traceKV bool) error {
+
 	// Consult the canary column to determine whether to insert or update. For
 	// more details on how canary columns work, see the block comment on
 	// Builder.buildInsert in opt/optbuilder/insert.go.
@@ -22,11 +22,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/logtags"
+	"github.com/pkg/errors"
 )
 
 func TestTrace(t *testing.T) {
@@ -567,3 +569,65 @@ func TestKVTraceDistSQL(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestSessionTracePersistence verifies that SET TRACING with the persist
+// option writes the session trace to system.session_traces, and that only
+// the newest traces are kept.
+func TestSessionTracePersistence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	sqlDB.SetMaxOpenConns(1)
+	r := sqlutils.MakeSQLRunner(sqlDB)
+
+	r.Exec(t, "SET tracing = on, persist")
+	r.Exec(t, "SELECT 42")
+	r.ExpectErr(t, "tracing is already started with different options", "SET tracing = on")
+	r.Exec(t, "SET tracing = off")
+
+	// The persisted trace has the same messages as the session trace.
+	var shown, persisted int
+	r.QueryRow(t, "SELECT count(*) FROM [SHOW TRACE FOR SESSION]").Scan(&shown)
+	r.QueryRow(t, "SELECT count(*) FROM system.session_traces").Scan(&persisted)
+	if shown == 0 || shown != persisted {
+		t.Fatalf("expected %d persisted trace messages, found %d", shown, persisted)
+	}
+	r.CheckQueryResults(t,
+		`SELECT count(DISTINCT trace_id), count(DISTINCT session_id), max(username)
+     FROM system.session_traces`,
+		[][]string{{"1", "1", security.RootUser}},
+	)
+	var found bool
+	r.QueryRow(t,
+		"SELECT count(*) > 0 FROM system.session_traces WHERE message LIKE '%SELECT 42%'",
+	).Scan(&found)
+	if !found {
+		t.Fatal("the executed statement is missing from the persisted trace")
+	}
+
+	// Traces which aren't persisted aren't written to the table.
+	r.Exec(t, "SET tracing = on; SELECT 43; SET tracing = off")
+	r.CheckQueryResults(t, "SELECT count(DISTINCT trace_id) FROM system.session_traces", [][]string{{"1"}})
+
+	// Only the newest traces are kept.
+	r.Exec(t, "SET CLUSTER SETTING sql.trace.session_traces.max_count = 2")
+	var lastTraceID int64
+	testutils.SucceedsSoon(t, func() error {
+		r.Exec(t, "SET tracing = on, persist; SELECT 44; SET tracing = off")
+		var count int
+		r.QueryRow(t,
+			"SELECT count(DISTINCT trace_id), max(trace_id) FROM system.session_traces",
+		).Scan(&count, &lastTraceID)
+		if count != 2 {
+			return errors.Errorf("expected 2 persisted traces, found %d", count)
+		}
+		return nil
+	})
+	r.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT count(*) > 0 FROM system.session_traces
+     WHERE trace_id = %d AND message LIKE '%%SELECT 44%%'`, lastTraceID),
+		[][]string{{"true"}},
+	)
+}
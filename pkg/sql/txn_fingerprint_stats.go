@@ -0,0 +1,343 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// txnKey is the fingerprint of a transaction. Two transactions have the same
+// fingerprint if they executed the same set of statement fingerprints (see
+// makeTxnKey).
+type txnKey uint64
+
+// overflowTxnKey is the key under which the transactions of an application
+// are recorded once it has reached maxTxnFingerprintsPerApp distinct
+// fingerprints, or once the memory of its fingerprints can no longer be
+// accounted for.
+const overflowTxnKey txnKey = 0
+
+// maxTxnFingerprintsPerApp bounds the number of distinct transaction
+// fingerprints recorded per application.
+var maxTxnFingerprintsPerApp = settings.RegisterPositiveIntSetting(
+	"sql.metrics.transaction_details.max_fingerprints_per_app",
+	"the maximum number of transaction fingerprints recorded per application; "+
+		"the transactions with other fingerprints are aggregated together",
+	1000,
+)
+
+// String returns the hex representation of the fingerprint.
+func (k txnKey) String() string {
+	if k == overflowTxnKey {
+		return "overflow"
+	}
+	return fmt.Sprintf("%016x", uint64(k))
+}
+
+// makeTxnKey sorts stmtKeys in place and returns the fingerprint of a
+// transaction that executed those statement fingerprints. The caller is
+// expected to have deduplicated stmtKeys.
+func makeTxnKey(stmtKeys []stmtKey) txnKey {
+	sort.Slice(stmtKeys, func(i, j int) bool {
+		return stmtKeys[i].String() < stmtKeys[j].String()
+	})
+	h := fnv.New64a()
+	for _, k := range stmtKeys {
+		_, _ = h.Write([]byte(k.String()))
+		// Delimit the statement fingerprints so that different sets can't hash
+		// the same byte sequence.
+		_, _ = h.Write([]byte{0})
+	}
+	if k := txnKey(h.Sum64()); k != overflowTxnKey {
+		return k
+	}
+	// Steer clear of the overflow bucket.
+	return overflowTxnKey + 1
+}
+
+// txnStats holds per-transaction-fingerprint statistics.
+type txnStats struct {
+	syncutil.Mutex
+
+	// stmtKeys are the statement fingerprints that make up the transaction
+	// fingerprint, in sorted order.
+	stmtKeys []stmtKey
+	data     txnFingerprintStats
+}
+
+// memoryEstimate returns an estimate of the memory used by a txnStats with the
+// its statement fingerprints, including its entry in the fingerprint map.
+func (s *txnStats) memoryEstimate() int64 {
+	sz := unsafe.Sizeof(txnKey(0)) + unsafe.Sizeof(s) + unsafe.Sizeof(*s) +
+		uintptr(len(s.stmtKeys))*unsafe.Sizeof(stmtKey{})
+	for i := range s.stmtKeys {
+		sz += uintptr(len(s.stmtKeys[i].stmt))
+	}
+	return int64(sz)
+}
+
+// txnFingerprintStats are the statistics collected for a transaction
+// fingerprint. All latencies are in seconds.
+type txnFingerprintStats struct {
+	Count          int64
+	CommittedCount int64
+	ImplicitCount  int64
+	// RetryCount is the total number of automatic retries performed by the
+	// transactions with this fingerprint.
+	RetryCount int64
+	MaxRetries int64
+	ServiceLat roachpb.NumericStat
+	// ContentionTime is the time the transactions with this fingerprint spent
+	// waiting on conflicting transactions.
+	ContentionTime roachpb.NumericStat
+	// ContentionCausedTime is the total time that other transactions spent
+	// waiting on the transactions with this fingerprint.
+	ContentionCausedTime float64
+}
+
+// recordTransactionFingerprint saves per-transaction-fingerprint statistics
+// for a finished transaction that executed the given (deduplicated)
+// statement fingerprints.
+func (a *appStats) recordTransactionFingerprint(
+	ctx context.Context,
+	stmtKeys []stmtKey,
+	txnTimeSec float64,
+	ev txnEvent,
+	implicit bool,
+	retryCount int,
+	contention activeTxnContention,
+) {
+	if !txnStatsEnable.Get(&a.st.SV) || len(stmtKeys) == 0 {
+		return
+	}
+	key := makeTxnKey(stmtKeys)
+
+	a.Lock()
+	s := a.getTxnStatsLocked(ctx, key, stmtKeys)
+	a.Unlock()
+
+	s.Lock()
+	defer s.Unlock()
+	s.data.Count++
+	if ev == txnCommit {
+		s.data.CommittedCount++
+	}
+	if implicit {
+		s.data.ImplicitCount++
+	}
+	s.data.RetryCount += int64(retryCount)
+	if int64(retryCount) > s.data.MaxRetries {
+		s.data.MaxRetries = int64(retryCount)
+	}
+	s.data.ServiceLat.Record(s.data.Count, txnTimeSec)
+	s.data.ContentionTime.Record(s.data.Count, contention.waited.Seconds())
+	s.data.ContentionCausedTime += contention.caused.Seconds()
+}
+
+// getTxnStatsLocked returns the statistics of the given transaction
+// fingerprint, creating them if they don't exist yet. Once the application
+// has reached its fingerprint limit or its memory can no longer be accounted
+// for, the statistics of the overflow bucket are returned instead. a must be
+// locked.
+func (a *appStats) getTxnStatsLocked(
+	ctx context.Context, key txnKey, stmtKeys []stmtKey,
+) *txnStats {
+	if s, ok := a.txnFingerprints[key]; ok {
+		return s
+	}
+	if int64(len(a.txnFingerprints)) < maxTxnFingerprintsPerApp.Get(&a.st.SV) {
+		s := &txnStats{stmtKeys: stmtKeys}
+		if err := a.txnFingerprintsAcc.Grow(ctx, s.memoryEstimate()); err == nil {
+			a.txnFingerprints[key] = s
+			return s
+		}
+	}
+	// The overflow bucket doesn't hold any statement fingerprints and is
+	// allowed to exceed the limit, so it is not accounted for.
+	s, ok := a.txnFingerprints[overflowTxnKey]
+	if !ok {
+		s = &txnStats{}
+		a.txnFingerprints[overflowTxnKey] = s
+	}
+	return s
+}
+
+// TxnFingerprintStatistics are the statistics collected on the local node for
+// a transaction fingerprint of an application. All latencies are in seconds.
+type TxnFingerprintStatistics struct {
+	App                   string   `json:"app"`
+	Fingerprint           string   `json:"fingerprint"`
+	StatementFingerprints []string `json:"statement_fingerprints"`
+	Count                 int64    `json:"count"`
+	CommittedCount        int64    `json:"committed_count"`
+	ImplicitCount         int64    `json:"implicit_count"`
+	RetryCount            int64    `json:"retry_count"`
+	MaxRetries            int64    `json:"max_retries"`
+	ServiceLatAvg         float64  `json:"service_lat_avg"`
+	ServiceLatVar         float64  `json:"service_lat_var"`
+	ContentionTimeAvg     float64  `json:"contention_time_avg"`
+	ContentionTimeVar     float64  `json:"contention_time_var"`
+	ContentionCausedTime  float64  `json:"contention_caused_time"`
+}
+
+// getTxnFingerprintStats returns the transaction fingerprint statistics of all
+// applications, ordered by application name and fingerprint.
+func (s *sqlStats) getTxnFingerprintStats() []TxnFingerprintStatistics {
+	s.Lock()
+	defer s.Unlock()
+	var ret []TxnFingerprintStatistics
+	for appName, a := range s.apps {
+		a.Lock()
+		for key, stats := range a.txnFingerprints {
+			stats.Lock()
+			stmts := make([]string, len(stats.stmtKeys))
+			for i := range stats.stmtKeys {
+				stmts[i] = stats.stmtKeys[i].String()
+			}
+			d := &stats.data
+			ret = append(ret, TxnFingerprintStatistics{
+				App:                   appName,
+				Fingerprint:           key.String(),
+				StatementFingerprints: stmts,
+				Count:                 d.Count,
+				CommittedCount:        d.CommittedCount,
+				ImplicitCount:         d.ImplicitCount,
+				RetryCount:            d.RetryCount,
+				MaxRetries:            d.MaxRetries,
+				ServiceLatAvg:         d.ServiceLat.Mean,
+				ServiceLatVar:         d.ServiceLat.GetVariance(d.Count),
+				ContentionTimeAvg:     d.ContentionTime.Mean,
+				ContentionTimeVar:     d.ContentionTime.GetVariance(d.Count),
+				ContentionCausedTime:  d.ContentionCausedTime,
+			})
+			stats.Unlock()
+		}
+		a.Unlock()
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].App != ret[j].App {
+			return ret[i].App < ret[j].App
+		}
+		return ret[i].Fingerprint < ret[j].Fingerprint
+	})
+	return ret
+}
+
+// activeTxnContention is the contention observed so far for a transaction
+// in progress.
+type activeTxnContention struct {
+	// waited is the time the transaction spent waiting on other transactions.
+	waited time.Duration
+	// caused is the time other transactions spent waiting on this one.
+	caused time.Duration
+}
+
+// activeTxn tracks a transaction in progress that has its gateway on the local
+// node, so that contention reported by the KV layer can be attributed to it.
+type activeTxn struct {
+	mu struct {
+		syncutil.Mutex
+		activeTxnContention
+	}
+}
+
+// activeTxnRegistry maps the IDs of the transactions in progress on the local
+// node to their activeTxn. Contention is reported by the KV layer on the node
+// where it occurred, so it can only be attributed to the transactions of that
+// node.
+type activeTxnRegistry struct {
+	mu struct {
+		syncutil.Mutex
+		txns map[uuid.UUID]*activeTxn
+	}
+}
+
+// register adds the transaction with the given ID to the registry, carrying
+// over the contention accumulated by prev (if non-nil), and returns its
+// activeTxn.
+func (r *activeTxnRegistry) register(id uuid.UUID, prev *activeTxn) *activeTxn {
+	t := &activeTxn{}
+	if prev != nil {
+		t.mu.activeTxnContention = prev.getContention()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.txns == nil {
+		r.mu.txns = make(map[uuid.UUID]*activeTxn)
+	}
+	r.mu.txns[id] = t
+	return t
+}
+
+// unregister removes the transaction with the given ID from the registry.
+func (r *activeTxnRegistry) unregister(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.txns, id)
+}
+
+func (r *activeTxnRegistry) get(id uuid.UUID) *activeTxn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.txns[id]
+}
+
+// recordContention attributes the time that pusher (nil for
+// non-transactional requests) spent waiting on pushee.
+func (r *activeTxnRegistry) recordContention(
+	pusher *enginepb.TxnMeta, pushee enginepb.TxnMeta, waited time.Duration,
+) {
+	if pusher != nil {
+		if t := r.get(pusher.ID); t != nil {
+			t.mu.Lock()
+			t.mu.waited += waited
+			t.mu.Unlock()
+		}
+	}
+	if t := r.get(pushee.ID); t != nil {
+		t.mu.Lock()
+		t.mu.caused += waited
+		t.mu.Unlock()
+	}
+}
+
+func (t *activeTxn) getContention() activeTxnContention {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.mu.activeTxnContention
+}
+
+var _ storagebase.ContentionListener = &Server{}
+
+// OnContention is part of the storagebase.ContentionListener interface.
+func (s *Server) OnContention(
+	_ context.Context, pusher *enginepb.TxnMeta, pushee enginepb.TxnMeta, waited time.Duration,
+) {
+	s.sqlStats.activeTxns.recordContention(pusher, pushee, waited)
+}
+
+// GetTxnFingerprintStats returns the transaction fingerprint statistics
+// collected on the local node.
+func (s *Server) GetTxnFingerprintStats() []TxnFingerprintStatistics {
+	return s.sqlStats.getTxnFingerprintStats()
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+func TestMakeTxnKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	a := stmtKey{stmt: "SELECT _", optUsed: true}
+	b := stmtKey{stmt: "UPDATE t SET v = _", optUsed: true}
+	c := stmtKey{stmt: "UPDATE t SET v = _", optUsed: true, failed: true}
+
+	if makeTxnKey([]stmtKey{a, b}) != makeTxnKey([]stmtKey{b, a}) {
+		t.Fatal("expected the fingerprint to be independent of the statement order")
+	}
+	if makeTxnKey([]stmtKey{a, b}) == makeTxnKey([]stmtKey{a, c}) {
+		t.Fatal("expected different statement fingerprints to produce different fingerprints")
+	}
+	if makeTxnKey([]stmtKey{a}) == makeTxnKey([]stmtKey{a, b}) {
+		t.Fatal("expected different statement sets to produce different fingerprints")
+	}
+}
+
+func TestTxnFingerprintStatsOverflow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	maxTxnFingerprintsPerApp.Override(&st.SV, 2)
+	pool := mon.MakeUnlimitedMonitor(
+		ctx, "test", mon.MemoryResource, nil /* curCount */, nil /* maxHist */, math.MaxInt64, st,
+	)
+	defer pool.Stop(ctx)
+
+	s := sqlStats{st: st, apps: make(map[string]*appStats), pool: &pool}
+	a := s.getStatsForApplication("app")
+	for i := 0; i < 5; i++ {
+		stmtKeys := []stmtKey{{stmt: fmt.Sprintf("SELECT %d", i), optUsed: true}}
+		a.recordTransactionFingerprint(
+			ctx, stmtKeys, 1 /* txnTimeSec */, txnCommit, true /* implicit */, 0, /* retryCount */
+			activeTxnContention{},
+		)
+	}
+
+	stats := s.getTxnFingerprintStats()
+	if len(stats) != 3 {
+		t.Fatalf("expected 2 fingerprints and the overflow bucket, got %+v", stats)
+	}
+	var overflow *TxnFingerprintStatistics
+	for i := range stats {
+		if stats[i].Fingerprint == overflowTxnKey.String() {
+			overflow = &stats[i]
+		}
+	}
+	if overflow == nil || overflow.Count != 3 {
+		t.Fatalf("expected 3 transactions in the overflow bucket, got %+v", stats)
+	}
+	if a.txnFingerprintsAcc.Used() == 0 {
+		t.Fatal("expected the fingerprints to be accounted for")
+	}
+
+	s.resetStats(ctx)
+	if used := a.txnFingerprintsAcc.Used(); used != 0 {
+		t.Fatalf("expected the reset to release the fingerprints' memory, got %d bytes", used)
+	}
+	a.txnFingerprintsAcc.Close(ctx)
+}
+
+func TestActiveTxnRegistryContention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var r activeTxnRegistry
+	pusherID, pusheeID, remoteID := uuid.MakeV4(), uuid.MakeV4(), uuid.MakeV4()
+	pusher := r.register(pusherID, nil /* prev */)
+	pushee := r.register(pusheeID, nil /* prev */)
+
+	r.recordContention(&enginepb.TxnMeta{ID: pusherID}, enginepb.TxnMeta{ID: pusheeID}, time.Second)
+	// Non-transactional pushers only count against the pushee.
+	r.recordContention(nil /* pusher */, enginepb.TxnMeta{ID: pusheeID}, time.Second)
+	// Transactions that aren't registered on this node are ignored.
+	r.recordContention(&enginepb.TxnMeta{ID: pusherID}, enginepb.TxnMeta{ID: remoteID}, time.Second)
+
+	if c := pusher.getContention(); c.waited != 2*time.Second || c.caused != 0 {
+		t.Fatalf("unexpected pusher contention %+v", c)
+	}
+	if c := pushee.getContention(); c.waited != 0 || c.caused != 2*time.Second {
+		t.Fatalf("unexpected pushee contention %+v", c)
+	}
+
+	// A restarted transaction gets a new ID but keeps its contention.
+	newPusherID := uuid.MakeV4()
+	r.unregister(pusherID)
+	restarted := r.register(newPusherID, pusher)
+	r.recordContention(&enginepb.TxnMeta{ID: pusherID}, enginepb.TxnMeta{ID: pusheeID}, time.Second)
+	if c := restarted.getContention(); c.waited != 2*time.Second {
+		t.Fatalf("unexpected restarted contention %+v", c)
+	}
+	if c := pushee.getContention(); c.caused != 3*time.Second {
+		t.Fatalf("unexpected pushee contention %+v", c)
+	}
+}
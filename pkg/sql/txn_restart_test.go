@@ -1584,3 +1584,61 @@ func TestTxnAutoRetriesDisabledAfterResultsHaveBeenSentToClient(t *testing.T) {
 		})
 	}
 }
+
+// Test that an implicit txn whose results overflow the connection's results
+// buffer (16KiB by default) is still retried automatically as long as they fit
+// in implicit_txn_results_buffer_size.
+func TestImplicitTxnAutoRetriesWithLargeResults(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := tests.CreateTestServerParams()
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The query returns about 60KiB of results, and the last row asks for a
+	// retry until the txn is 1s old.
+	const numRows = 4000
+	query := fmt.Sprintf(`
+		SELECT
+			CASE x
+			WHEN %[1]d THEN crdb_internal.force_retry('1s')
+			ELSE x
+			END
+		FROM generate_series(1, %[1]d) AS t(x)`, numRows)
+	run := func() error {
+		rows, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if n != numRows {
+			return errors.Errorf("expected %d rows, got %d", numRows, n)
+		}
+		return nil
+	}
+
+	// With a buffer as small as the connection's, the results have been sent to
+	// the client by the time the retry is needed.
+	_, err = conn.ExecContext(ctx, `SET implicit_txn_results_buffer_size = 16384`)
+	require.NoError(t, err)
+	if err := run(); !isRetryableErr(err) {
+		t.Fatalf("expected retriable error, got: %v", err)
+	}
+
+	// With a larger buffer, the txn is retried without the client noticing.
+	_, err = conn.ExecContext(ctx, `SET implicit_txn_results_buffer_size = 1048576`)
+	require.NoError(t, err)
+	require.NoError(t, run())
+}
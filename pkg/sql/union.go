@@ -77,10 +77,16 @@ type unionNode struct {
 	unionType tree.UnionType
 	// all indicates if the operation is the ALL or DISTINCT version
 	all bool
+
+	// hardLimit, if non-zero, indicates that the UNION ALL produces at most
+	// hardLimit rows. In that case, the left input is read to completion before
+	// the right input, and the right input is not read at all if the left input
+	// produces hardLimit rows. It is used by locality optimized search.
+	hardLimit uint64
 }
 
 func (p *planner) newUnionNode(
-	typ tree.UnionType, all bool, left, right planNode,
+	typ tree.UnionType, all bool, left, right planNode, hardLimit uint64,
 ) (planNode, error) {
 	emitAll := false
 	switch typ {
@@ -93,6 +99,9 @@ func (p *planner) newUnionNode(
 	default:
 		return nil, errors.Errorf("%v is not supported", typ)
 	}
+	if hardLimit != 0 && !emitAll {
+		return nil, errors.Errorf("a hard limit is only supported for UNION ALL")
+	}
 
 	leftColumns := planColumns(left)
 	rightColumns := planColumns(right)
@@ -141,6 +150,7 @@ func (p *planner) newUnionNode(
 		emitAll:   emitAll,
 		unionType: typ,
 		all:       all,
+		hardLimit: hardLimit,
 	}
 	return node, nil
 }
@@ -121,12 +121,6 @@ type updateRun struct {
 	numPassthrough int
 }
 
-// maxUpdateBatchSize is the max number of entries in the KV batch for
-// the update operation (including secondary index updates, FK
-// cascading updates, etc), before the current KV batch is executed
-// and a new batch is started.
-const maxUpdateBatchSize = 10000
-
 func (u *updateNode) startExec(params runParams) error {
 	if err := params.p.maybeSetSystemConfig(u.run.tu.tableDesc().GetID()); err != nil {
 		return err
@@ -191,7 +185,7 @@ func (u *updateNode) BatchedNext(params runParams) (bool, error) {
 		u.run.rowCount++
 
 		// Are we done yet with the current batch?
-		if u.run.tu.curBatchSize() >= maxUpdateBatchSize {
+		if u.run.tu.batchFull() {
 			break
 		}
 	}
@@ -423,6 +423,29 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`implicit_txn_results_buffer_size`: {
+		GetStringVal: makeIntGetStringValFn(`implicit_txn_results_buffer_size`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			if b < 0 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"cannot set implicit_txn_results_buffer_size to a negative value: %d", b)
+			}
+			m.SetImplicitTxnResultsBufferSize(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatInt(evalCtx.SessionData.ImplicitTxnResultsBufferSize, 10)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return strconv.FormatInt(ImplicitTxnResultsBufferSizeClusterValue.Get(sv), 10)
+		},
+	},
+
 	// CockroachDB extension.
 	`vectorize_row_count_threshold`: {
 		GetStringVal: makeIntGetStringValFn(`vectorize_row_count_threshold`),
@@ -501,6 +524,33 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`mutation_batch_size`: {
+		GetStringVal: makeIntGetStringValFn(`mutation_batch_size`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			if b <= 0 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"mutation_batch_size must be positive: %d", b)
+			}
+			m.SetMutationBatchSize(int(b))
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			size := evalCtx.SessionData.MutationBatchSize
+			if size <= 0 {
+				size = defaultMutationBatchSize
+			}
+			return strconv.Itoa(size)
+		},
+		GlobalDefault: func(_ *settings.Values) string {
+			return strconv.Itoa(defaultMutationBatchSize)
+		},
+	},
+
 	// CockroachDB extension.
 	`experimental_serial_normalization`: {
 		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
@@ -993,7 +1043,7 @@ func makeIntGetStringValFn(name string) getStringValFn {
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
 func IsSessionVariableConfigurable(varName string) (exists, configurable bool) {
-	v, exists := varGen[varName]
+	exists, v, _ := getSessionVar(varName, true /* missingOk */)
 	return exists, v.Set != nil
 }
 
@@ -1035,6 +1085,9 @@ func getSessionVar(name string, missingOk bool) (bool, sessionVar, error) {
 
 	v, ok := varGen[name]
 	if !ok {
+		if s, ok := lookupSessionOverridableSetting(name); ok {
+			return true, settingOverrideVar(name, s), nil
+		}
 		if missingOk {
 			return false, sessionVar{}, nil
 		}
@@ -1045,6 +1098,55 @@ func getSessionVar(name string, missingOk bool) (bool, sessionVar, error) {
 	return true, v, nil
 }
 
+// lookupSessionOverridableSetting returns the cluster setting with the given
+// name if it can be overridden per session.
+func lookupSessionOverridableSetting(name string) (settings.WritableSetting, bool) {
+	s, ok := settings.Lookup(name, settings.LookupForLocalAccess)
+	if !ok || !settings.IsOverridable(s, settings.SessionScope) {
+		return nil, false
+	}
+	ws, ok := s.(settings.WritableSetting)
+	return ws, ok
+}
+
+// settingOverrideVar returns the session variable through which the cluster
+// setting s is overridden for a session. The variable has the same name as
+// the setting and shows the value of the setting that is in effect for the
+// session. Setting it overrides the cluster-wide value, and resetting it
+// reverts to the cluster-wide value.
+func settingOverrideVar(name string, s settings.WritableSetting) sessionVar {
+	return sessionVar{
+		// The variable only shows up in SHOW ALL while it is overridden.
+		Hidden: true,
+		Get: func(evalCtx *extendedEvalContext) string {
+			return settings.StringWithOverrides(
+				s, &evalCtx.Settings.SV, evalCtx.SessionData.SettingOverrides,
+			)
+		},
+		GetStringVal: func(
+			ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr,
+		) (string, error) {
+			if len(values) != 1 {
+				return "", newSingleArgVarError(name)
+			}
+			d, err := values[0].Eval(&evalCtx.EvalContext)
+			if err != nil {
+				return "", err
+			}
+			encoded, err := toSettingString(ctx, evalCtx.Settings, name, s, d, nil /* prev */)
+			if err != nil {
+				return "", wrapSetVarError(name, d.String(), "%v", err)
+			}
+			return encoded, nil
+		},
+		Set: func(_ context.Context, m *sessionDataMutator, val string) error {
+			return m.SetSettingOverride(name, val)
+		},
+		// The empty string removes the override.
+		GlobalDefault: func(*settings.Values) string { return "" },
+	}
+}
+
 // GetSessionVar implements the EvalSessionAccessor interface.
 func (p *planner) GetSessionVar(
 	_ context.Context, varName string, missingOk bool,
@@ -175,6 +175,15 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 			v.observer.spans(name, "spans", n.index, n.spans)
 		}
 		if v.observer.attr != nil {
+			if n.skipScanPrefixLen > 0 {
+				v.observer.attr(name, "skip scan prefix", fmt.Sprintf("%d", n.skipScanPrefixLen))
+			}
+			if n.skipScanGroupLimit > 0 {
+				v.observer.attr(name, "limit per group", fmt.Sprintf("%d", n.skipScanGroupLimit))
+				if n.reverse {
+					v.observer.attr(name, "reverse", "")
+				}
+			}
 			// Only print out "parallel" when it makes sense. i.e. don't print if
 			// we know we will get only one result from the scan. There are cases
 			// in which "parallel" will be printed out even though the spans cover
@@ -311,6 +320,9 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 				if n.pred.rightEqKey {
 					v.observer.attr(name, "right cols are key", "")
 				}
+				if n.pred.rejectOnNull {
+					v.observer.attr(name, "reject on null", "")
+				}
 			}
 			if len(n.mergeJoinOrdering) > 0 {
 				// The ordering refers to equality columns
@@ -430,6 +442,9 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		n.plan = v.visit(n.plan)
 
 	case *unionNode:
+		if v.observer.attr != nil && n.hardLimit != 0 {
+			v.observer.attr(name, "limit", fmt.Sprintf("%d", n.hardLimit))
+		}
 		n.left = v.visit(n.left)
 		n.right = v.visit(n.right)
 
@@ -746,6 +761,9 @@ func nodeName(plan planNode) string {
 	// Some nodes have custom names depending on attributes.
 	switch n := plan.(type) {
 	case *scanNode:
+		if n.skipScanGroupLimit > 0 {
+			return "partial scan per group"
+		}
 		if n.reverse {
 			return "revscan"
 		}
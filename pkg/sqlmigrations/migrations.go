@@ -284,6 +284,20 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		workFn:              migrateSystemNamespace,
 		includedInBootstrap: cluster.VersionByKey(cluster.VersionNamespaceTableWithSchemas),
 	},
+	{
+		// Introduced in v20.1.
+		name:                "create system.session_traces table",
+		workFn:              createSessionTracesTable,
+		includedInBootstrap: cluster.VersionByKey(cluster.VersionSessionTraces),
+		newDescriptorIDs:    staticIDs(keys.SessionTracesTableID),
+	},
+	{
+		// Introduced in v20.1.
+		name:                "create system.plan_changes table",
+		workFn:              createPlanChangesTable,
+		includedInBootstrap: cluster.VersionByKey(cluster.VersionPlanChanges),
+		newDescriptorIDs:    staticIDs(keys.PlanChangesTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -662,6 +676,16 @@ func createProtectedTimestampsRecordsTable(ctx context.Context, r runner) error
 		"failed to create system.protected_ts_records")
 }
 
+func createSessionTracesTable(ctx context.Context, r runner) error {
+	return errors.Wrap(createSystemTable(ctx, r, sqlbase.SessionTracesTable),
+		"failed to create system.session_traces")
+}
+
+func createPlanChangesTable(ctx context.Context, r runner) error {
+	return errors.Wrap(createSystemTable(ctx, r, sqlbase.PlanChangesTable),
+		"failed to create system.plan_changes")
+}
+
 func createNewSystemNamespaceDescriptor(ctx context.Context, r runner) error {
 
 	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
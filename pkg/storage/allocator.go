@@ -498,6 +498,24 @@ func (a *Allocator) AllocateTarget(
 	}
 }
 
+// AllocateNonVoterTarget returns a suitable store for a new non-voting replica
+// with the required attributes. Nodes already accommodating either voters or
+// non-voters are ruled out as targets, and the diversity of the new replica is
+// scored against all of the existing replicas. Non-voters don't affect the
+// quorum, so the zone's replica count doesn't limit how many can be added; it
+// is up to the caller to decide how many non-voters a range should have.
+func (a *Allocator) AllocateNonVoterTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	rangeID roachpb.RangeID,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+) (*roachpb.StoreDescriptor, string, error) {
+	existingReplicas := make([]roachpb.ReplicaDescriptor, 0, len(existingVoters)+len(existingNonVoters))
+	existingReplicas = append(existingReplicas, existingVoters...)
+	existingReplicas = append(existingReplicas, existingNonVoters...)
+	return a.AllocateTarget(ctx, zone, rangeID, existingReplicas)
+}
+
 func (a *Allocator) allocateTargetFromList(
 	ctx context.Context,
 	sl StoreList,
@@ -764,9 +782,18 @@ func (a Allocator) RebalanceTarget(
 }
 
 func (a *Allocator) scorerOptions() scorerOptions {
+	highWatermark := diskRebalanceHighWatermark.Get(&a.storePool.st.SV)
+	lowWatermark := diskRebalanceLowWatermark.Get(&a.storePool.st.SV)
+	if lowWatermark > highWatermark {
+		// A misconfigured low watermark disables the hysteresis rather than
+		// letting stores be underfull and overfull at the same time.
+		lowWatermark = highWatermark
+	}
 	return scorerOptions{
-		deterministic:           a.storePool.deterministic,
-		rangeRebalanceThreshold: rangeRebalanceThreshold.Get(&a.storePool.st.SV),
+		deterministic:              a.storePool.deterministic,
+		rangeRebalanceThreshold:    rangeRebalanceThreshold.Get(&a.storePool.st.SV),
+		diskRebalanceHighWatermark: highWatermark,
+		diskRebalanceLowWatermark:  lowWatermark,
 	}
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/constraint"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -75,26 +76,78 @@ var rangeRebalanceThreshold = func() *settings.FloatSetting {
 	return s
 }()
 
+// diskRebalanceHighWatermark is the fraction of a store's disk capacity above
+// which the store is considered overfull and replicas are moved off of it.
+var diskRebalanceHighWatermark = func() *settings.FloatSetting {
+	s := settings.RegisterValidatedFloatSetting(
+		"kv.allocator.disk_rebalance_high_watermark",
+		"fraction of a store's disk capacity above which replicas are moved off of the store",
+		0.85,
+		validateFraction,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// diskRebalanceLowWatermark is the fraction of a store's disk capacity below
+// which the store is considered underfull. Replicas that are moved off of an
+// overfull store purely because of its disk usage are only moved to underfull
+// stores, which provides hysteresis between the two watermarks so that
+// replicas don't ping-pong between stores hovering around a single threshold.
+var diskRebalanceLowWatermark = func() *settings.FloatSetting {
+	s := settings.RegisterValidatedFloatSetting(
+		"kv.allocator.disk_rebalance_low_watermark",
+		"fraction of a store's disk capacity below which the store accepts replicas moved off of stores "+
+			"above kv.allocator.disk_rebalance_high_watermark",
+		0.7,
+		validateFraction,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+func validateFraction(v float64) error {
+	if v <= 0 || v > 1 {
+		return errors.Errorf("cannot set to a value outside of (0, 1]: %f", v)
+	}
+	return nil
+}
+
 type scorerOptions struct {
 	deterministic           bool
 	rangeRebalanceThreshold float64
 	qpsRebalanceThreshold   float64 // only considered if non-zero
+	// diskRebalanceHighWatermark and diskRebalanceLowWatermark are the disk
+	// usage fractions between which a store is considered balanced. Disk usage
+	// is only considered if diskRebalanceHighWatermark is non-zero.
+	diskRebalanceHighWatermark float64
+	diskRebalanceLowWatermark  float64
 }
 
 type balanceDimensions struct {
 	ranges rangeCountStatus
+	disk   diskUsageStatus
 }
 
+// diskWeight is the weight of the disk usage dimension in the total balance
+// score. It is larger than the largest possible difference of range count
+// statuses so that moving replicas off of stores with overfull disks takes
+// precedence over balancing range counts.
+const diskWeight = 3
+
 func (bd *balanceDimensions) totalScore() float64 {
-	return float64(bd.ranges)
+	return diskWeight*float64(bd.disk) + float64(bd.ranges)
 }
 
 func (bd balanceDimensions) String() string {
-	return strconv.Itoa(int(bd.ranges))
+	return fmt.Sprintf("%d/%d", bd.ranges, bd.disk)
 }
 
 func (bd balanceDimensions) compactString(options scorerOptions) string {
-	return fmt.Sprintf("%d", bd.ranges)
+	if options.diskRebalanceHighWatermark == 0 {
+		return strconv.Itoa(int(bd.ranges))
+	}
+	return fmt.Sprintf("(ranges=%d, disk=%d)", bd.ranges, bd.disk)
 }
 
 // candidate store for allocation.
@@ -469,12 +522,14 @@ func removeCandidates(
 		diversityScore := diversityRemovalScore(s.Node.NodeID, existingNodeLocalities)
 		balanceScore := balanceScore(sl, s.Capacity, options)
 		var convergesScore int
-		if !rebalanceFromConvergesOnMean(sl, s.Capacity) {
+		if !rebalanceFromConvergesOnMean(sl, s.Capacity) && balanceScore.disk != diskOverfull {
 			// If removing this candidate replica does not converge the store
 			// stats to their means, we make it less attractive for removal by
 			// adding 1 to the constraint score. Note that when selecting a
 			// candidate for removal the candidates with the lowest scores are
-			// more likely to be removed.
+			// more likely to be removed. Replicas on stores with overfull disks
+			// don't get the boost since we want to move them off regardless of
+			// range counts.
 			convergesScore = 1
 		}
 		candidates = append(candidates, candidate{
@@ -521,6 +576,9 @@ func rebalanceCandidates(
 	}
 	existingStores := make(map[roachpb.StoreID]existingStore)
 	var needRebalanceFrom bool
+	// needDiskRebalance is set if an existing replica is on a store whose disk
+	// usage is above the high watermark.
+	var needDiskRebalance bool
 	curDiversityScore := rangeDiversityScore(existingNodeLocalities)
 	for _, store := range allStores.stores {
 		for _, repl := range existingReplicas {
@@ -543,6 +601,14 @@ func rebalanceCandidates(
 				}
 				needRebalanceFrom = true
 			}
+			if !fullDisk && diskUsage(store.Capacity, options) == diskOverfull {
+				if !needDiskRebalance {
+					log.VEventf(ctx, 2, "s%d: should-rebalance(disk-overfull): fractionUsed=%.2f, "+
+						"high-watermark=%.2f", store.StoreID, store.Capacity.FractionUsed(),
+						options.diskRebalanceHighWatermark)
+				}
+				needDiskRebalance = true
+			}
 			existingStores[store.StoreID] = existingStore{
 				cand: candidate{
 					store:          store,
@@ -675,7 +741,7 @@ func rebalanceCandidates(
 			}
 		}
 	}
-	if !needRebalance && !shouldRebalanceCheck {
+	if !needRebalance && !needDiskRebalance && !shouldRebalanceCheck {
 		return nil
 	}
 
@@ -686,6 +752,12 @@ func rebalanceCandidates(
 	for _, comparable := range comparableStores {
 		var existingCandidates candidateList
 		var candidates candidateList
+		// diskDriven is set if the replicas in this group should be moved because
+		// of the disk usage of their stores.
+		var diskDriven bool
+		// minExistingDisk is the worst disk usage status of the stores of the
+		// existing replicas in this group.
+		minExistingDisk := diskUnderfull
 		for _, existingDesc := range comparable.existing {
 			existing, ok := existingStores[existingDesc.StoreID]
 			if !ok {
@@ -699,8 +771,15 @@ func rebalanceCandidates(
 				continue
 			}
 			balanceScore := balanceScore(comparable.sl, existing.cand.store.Capacity, options)
+			if balanceScore.disk == diskOverfull {
+				diskDriven = true
+			}
+			if balanceScore.disk < minExistingDisk {
+				minExistingDisk = balanceScore.disk
+			}
 			var convergesScore int
-			if !rebalanceFromConvergesOnMean(comparable.sl, existing.cand.store.Capacity) {
+			if !rebalanceFromConvergesOnMean(comparable.sl, existing.cand.store.Capacity) &&
+				balanceScore.disk != diskOverfull {
 				// Similarly to in removeCandidates, any replica whose removal
 				// would not converge the range stats to their means is given a
 				// constraint score boost of 1 to make it less attractive for
@@ -725,14 +804,33 @@ func rebalanceCandidates(
 			s := cand.store
 			cand.fullDisk = !rebalanceToMaxCapacityCheck(s)
 			cand.balanceScore = balanceScore(comparable.sl, s.Capacity, options)
+			if diskDriven && !needRebalance && cand.balanceScore.disk != diskUnderfull {
+				// Replicas that are moved off of a store because of its disk usage
+				// are only moved to stores below the low watermark. Otherwise, the
+				// replicas could end up moving back and forth between stores with
+				// disk usages around the high watermark.
+				log.VEventf(ctx, 3, "not considering %+v as a candidate for range %+v: disk usage %.2f "+
+					"is above the low watermark %.2f", s, existingReplicas, s.Capacity.FractionUsed(),
+					options.diskRebalanceLowWatermark)
+				continue
+			}
+			if !needRebalance && cand.balanceScore.disk < minExistingDisk {
+				// Similarly, don't move replicas to balance range counts if that
+				// moves them up the disk usage gradient, as they could get pushed
+				// right back once the store gets over the high watermark.
+				log.VEventf(ctx, 3, "not considering %+v as a candidate for range %+v: disk usage %.2f "+
+					"is worse than that of the existing replicas", s, existingReplicas,
+					s.Capacity.FractionUsed())
+				continue
+			}
 			if rebalanceToConvergesOnMean(comparable.sl, s.Capacity) {
 				// This is the counterpart of !rebalanceFromConvergesOnMean from
 				// the existing candidates. Candidates whose addition would
 				// converge towards the range count mean are promoted.
 				cand.convergesScore = 1
-			} else if !needRebalance {
+			} else if !needRebalance && !diskDriven {
 				// Only consider this candidate if we must rebalance due to constraint,
-				// disk fullness, or diversity reasons.
+				// disk fullness or usage, or diversity reasons.
 				log.VEventf(ctx, 3, "not considering %+v as a candidate for range %+v: score=%s storeList=%+v",
 					s, existingReplicas, cand.balanceScore, comparable.sl)
 				continue
@@ -744,6 +842,11 @@ func rebalanceCandidates(
 		if len(existingCandidates) == 0 || len(candidates) == 0 {
 			continue
 		}
+		if !needRebalance && !shouldRebalanceCheck && !diskDriven {
+			// The rebalance is only needed because of the disk usage of a store in
+			// another group.
+			continue
+		}
 
 		if options.deterministic {
 			sort.Sort(sort.Reverse(byScoreAndID(existingCandidates)))
@@ -1184,6 +1287,14 @@ const (
 	underfull rangeCountStatus = 1
 )
 
+type diskUsageStatus int
+
+const (
+	diskOverfull  diskUsageStatus = -1
+	diskBalanced  diskUsageStatus = 0
+	diskUnderfull diskUsageStatus = 1
+)
+
 // balanceScore returns an arbitrarily scaled score where higher scores are for
 // stores where the range is a better fit based on various balance factors
 // like range count, disk usage, and QPS.
@@ -1196,9 +1307,26 @@ func balanceScore(sl StoreList, sc roachpb.StoreCapacity, options scorerOptions)
 	} else {
 		dimensions.ranges = balanced
 	}
+	dimensions.disk = diskUsage(sc, options)
 	return dimensions
 }
 
+// diskUsage returns the status of a store's disk usage with respect to the
+// disk rebalancing watermarks.
+func diskUsage(sc roachpb.StoreCapacity, options scorerOptions) diskUsageStatus {
+	if options.diskRebalanceHighWatermark == 0 {
+		return diskBalanced
+	}
+	fractionUsed := sc.FractionUsed()
+	if fractionUsed >= options.diskRebalanceHighWatermark {
+		return diskOverfull
+	}
+	if fractionUsed < options.diskRebalanceLowWatermark {
+		return diskUnderfull
+	}
+	return diskBalanced
+}
+
 func overfullRangeThreshold(options scorerOptions, mean float64) float64 {
 	return overfullThreshold(mean, options.rangeRebalanceThreshold)
 }
@@ -1508,6 +1508,36 @@ func TestBalanceScore(t *testing.T) {
 	}
 }
 
+func TestDiskUsage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	options := scorerOptions{
+		diskRebalanceHighWatermark: 0.85,
+		diskRebalanceLowWatermark:  0.7,
+	}
+	testCases := []struct {
+		available int64
+		expected  diskUsageStatus
+	}{
+		{100, diskUnderfull},
+		{31, diskUnderfull},
+		{30, diskBalanced},
+		{16, diskBalanced},
+		{15, diskOverfull},
+		{0, diskOverfull},
+	}
+	for i, tc := range testCases {
+		sc := roachpb.StoreCapacity{Capacity: 100, Available: tc.available}
+		if a, e := diskUsage(sc, options), tc.expected; a != e {
+			t.Errorf("%d: diskUsage(%+v) got %d; want %d", i, sc, a, e)
+		}
+		// Disk usage is ignored if the high watermark is unset.
+		if a, e := diskUsage(sc, scorerOptions{}), diskBalanced; a != e {
+			t.Errorf("%d: diskUsage(%+v) without watermarks got %d; want %d", i, sc, a, e)
+		}
+	}
+}
+
 func TestRebalanceConvergesOnMean(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -474,6 +474,32 @@ func TestAllocatorTwoDatacenters(t *testing.T) {
 	}
 }
 
+func TestAllocatorNonVoterTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper, g, _, a, _ := createTestAllocator(1, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(multiDCStores, t)
+	ctx := context.Background()
+	voters := []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1}}
+	result, _, err := a.AllocateNonVoterTarget(ctx, &multiDCConfig, firstRangeID, voters, nil)
+	if err != nil {
+		t.Fatalf("Unable to perform allocation: %+v", err)
+	}
+	if result.Node.NodeID != 2 || result.StoreID != 2 {
+		t.Errorf("expected NodeID 2 and StoreID 2: %+v", result)
+	}
+	// Verify that no result is forthcoming if the only other store already has
+	// a non-voter.
+	nonVoters := []roachpb.ReplicaDescriptor{
+		{NodeID: 2, StoreID: 2, Type: roachpb.ReplicaTypeNonVoter()},
+	}
+	result, _, err = a.AllocateNonVoterTarget(ctx, &multiDCConfig, firstRangeID, voters, nonVoters)
+	if err == nil {
+		t.Errorf("expected error on allocation without available stores: %+v", result)
+	}
+}
+
 func TestAllocatorExistingReplica(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -715,6 +741,95 @@ func TestAllocatorRebalance(t *testing.T) {
 	}
 }
 
+// TestAllocatorRebalanceDiskUsage verifies that replicas are moved off of
+// stores whose disk usage is above the high watermark, and only to stores
+// whose disk usage is below the low watermark.
+func TestAllocatorRebalanceDiskUsage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// All stores have the same range count so that any rebalancing is driven
+	// by disk usage.
+	stores := []*roachpb.StoreDescriptor{
+		{
+			// Above the high watermark.
+			StoreID:  1,
+			Node:     roachpb.NodeDescriptor{NodeID: 1},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 10, RangeCount: 10},
+		},
+		{
+			// Between the watermarks.
+			StoreID:  2,
+			Node:     roachpb.NodeDescriptor{NodeID: 2},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 20, RangeCount: 10},
+		},
+		{
+			StoreID:  3,
+			Node:     roachpb.NodeDescriptor{NodeID: 3},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 50, RangeCount: 10},
+		},
+		{
+			StoreID:  4,
+			Node:     roachpb.NodeDescriptor{NodeID: 4},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 40, RangeCount: 10},
+		},
+		{
+			// Between the watermarks.
+			StoreID:  5,
+			Node:     roachpb.NodeDescriptor{NodeID: 5},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 22, RangeCount: 10},
+		},
+	}
+
+	stopper, g, sp, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+	ctx := context.Background()
+
+	rebalanceTarget := func(storeID roachpb.StoreID) (*roachpb.StoreDescriptor, bool) {
+		var rangeUsageInfo RangeUsageInfo
+		target, _, details, ok := a.RebalanceTarget(
+			ctx,
+			zonepb.EmptyCompleteZoneConfig(),
+			nil,
+			firstRangeID,
+			[]roachpb.ReplicaDescriptor{{NodeID: roachpb.NodeID(storeID), StoreID: storeID}},
+			rangeUsageInfo,
+			storeFilterThrottled,
+		)
+		if ok && log.V(1) {
+			log.Infof(ctx, "rebalancing s%d to %v; details: %s", storeID, target, details)
+		}
+		return target, ok
+	}
+
+	// The replica on store 1 must be moved to store 3 or 4, but never to the
+	// stores between the watermarks.
+	for i := 0; i < 10; i++ {
+		target, ok := rebalanceTarget(1)
+		if !ok {
+			t.Fatalf("%d: expected replica on s1 to be rebalanced", i)
+		}
+		if target.StoreID != 3 && target.StoreID != 4 {
+			t.Errorf("%d: expected store 3 or 4; got %d", i, target.StoreID)
+		}
+	}
+
+	// None of the other replicas need to be moved.
+	for _, storeID := range []roachpb.StoreID{2, 3, 4, 5} {
+		if target, ok := rebalanceTarget(storeID); ok {
+			t.Errorf("expected no rebalancing of replica on s%d; got %d", storeID, target.StoreID)
+		}
+	}
+
+	// Raising the high watermark above store 1's disk usage stops the
+	// rebalancing.
+	diskRebalanceHighWatermark.Override(&sp.st.SV, 0.95)
+	if target, ok := rebalanceTarget(1); ok {
+		t.Errorf("expected no rebalancing of replica on s1; got %d", target.StoreID)
+	}
+}
+
 // TestAllocatorRebalanceTarget could help us to verify whether we'll rebalance
 // to a target that we'll immediately remove.
 func TestAllocatorRebalanceTarget(t *testing.T) {
@@ -5737,6 +5852,135 @@ func TestAllocatorFullDisks(t *testing.T) {
 	}
 }
 
+// TestAllocatorDiskUsageRebalancing models a cluster with stores of different
+// sizes holding the same number of ranges, and verifies that replicas are moved
+// off of the small stores until their disk usage is below the high watermark,
+// without replicas being moved back to them.
+func TestAllocatorDiskUsageRebalancing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	st := cluster.MakeTestingClusterSettings()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+
+	rpcContext := rpc.NewContext(
+		log.AmbientContext{Tracer: st.Tracer},
+		&base.Config{Insecure: true},
+		clock,
+		stopper,
+		st,
+	)
+	server := rpc.NewServer(rpcContext) // never started
+	g := gossip.NewTest(1, rpcContext, server, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	TimeUntilStoreDead.Override(&st.SV, TestTimeUntilStoreDeadOff)
+
+	const generations = 20
+	const nodes = 10
+	const smallNodes = 3
+	const smallCapacity = 700 << 20
+	const largeCapacity = 2 << 30
+	const rangesPerNode = 40
+	const rangeSize = 16 << 20
+
+	mockNodeLiveness := newMockNodeLiveness(storagepb.NodeLivenessStatus_LIVE)
+	sp := NewStorePool(
+		log.AmbientContext{Tracer: st.Tracer},
+		st,
+		g,
+		clock,
+		func() int {
+			return nodes
+		},
+		mockNodeLiveness.nodeLivenessFunc,
+		false, /* deterministic */
+	)
+	alloc := MakeAllocator(sp, func(string) (time.Duration, bool) {
+		return 0, false
+	})
+	options := alloc.scorerOptions()
+
+	var wg sync.WaitGroup
+	g.RegisterCallback(gossip.MakePrefixPattern(gossip.KeyStorePrefix),
+		func(_ string, _ roachpb.Value) { wg.Done() },
+		// Redundant callbacks are required by this test.
+		gossip.Redundant)
+	gossipStore := func(ts *testStore) {
+		wg.Add(1)
+		if err := g.AddInfoProto(gossip.MakeStoreKey(ts.StoreID), &ts.StoreDescriptor, 0); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+	}
+
+	// Initialize testStores. The disk usage of the small stores starts out
+	// above the high watermark, and that of the large ones below the low
+	// watermark.
+	var testStores [nodes]testStore
+	for i := 0; i < len(testStores); i++ {
+		capacity := int64(largeCapacity)
+		if i < smallNodes {
+			capacity = smallCapacity
+		}
+		testStores[i].immediateCompaction = true
+		testStores[i].StoreID = roachpb.StoreID(i)
+		testStores[i].Node = roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i)}
+		testStores[i].Capacity = roachpb.StoreCapacity{Capacity: capacity, Available: capacity}
+		for j := 0; j < rangesPerNode; j++ {
+			testStores[i].add(rangeSize)
+		}
+		gossipStore(&testStores[i])
+	}
+	if s := diskUsage(testStores[0].Capacity, options); s != diskOverfull {
+		t.Fatalf("expected small stores to start out overfull; got %d: %+v", s, testStores[0].Capacity)
+	}
+
+	for i := 0; i < generations; i++ {
+		for k := 0; k < len(testStores); k++ {
+			ts := &testStores[k]
+			var rangeUsageInfo RangeUsageInfo
+			target, _, details, ok := alloc.RebalanceTarget(
+				ctx,
+				zonepb.EmptyCompleteZoneConfig(),
+				nil,
+				firstRangeID,
+				[]roachpb.ReplicaDescriptor{{NodeID: ts.Node.NodeID, StoreID: ts.StoreID}},
+				rangeUsageInfo,
+				storeFilterThrottled,
+			)
+			if !ok {
+				continue
+			}
+			if log.V(1) {
+				log.Infof(ctx, "rebalancing to %v; details: %s", target, details)
+			}
+			ots := &testStores[int(target.StoreID)]
+			if int(target.StoreID) < smallNodes {
+				t.Errorf("generation %d: replica on s%d moved back to small store s%d: %+v",
+					i, ts.StoreID, ots.StoreID, ots.Capacity)
+			}
+			if diskUsage(ts.Capacity, options) == diskOverfull &&
+				diskUsage(ots.Capacity, options) != diskUnderfull {
+				t.Errorf("generation %d: replica on overfull s%d moved to s%d above the low watermark: %+v",
+					i, ts.StoreID, ots.StoreID, ots.Capacity)
+			}
+			ts.rebalance(ots, rangeSize)
+			gossipStore(ts)
+			gossipStore(ots)
+		}
+	}
+
+	for i := range testStores {
+		if fractionUsed := testStores[i].Capacity.FractionUsed(); fractionUsed >= options.diskRebalanceHighWatermark {
+			t.Errorf("expected s%d to be below the high watermark %.2f; got %.2f",
+				i, options.diskRebalanceHighWatermark, fractionUsed)
+		}
+	}
+}
+
 func Example_rebalancing() {
 	stopper := stop.NewStopper()
 	defer stopper.Stop(context.TODO())
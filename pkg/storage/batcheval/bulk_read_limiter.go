@@ -0,0 +1,176 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// bulkReadBurst is the maximum number of bytes that a BulkReadLimiter admits
+	// at once. Larger reads wait for the limiter in chunks of this size.
+	bulkReadBurst = 8 << 20 // 8MiB
+	// bulkReadMinRate is the rate below which a BulkReadLimiter never throttles
+	// bulk reads, however loaded the node is, so that they always make progress.
+	bulkReadMinRate = 1 << 20 // 1MiB/s
+	// bulkReadIncreaseFraction is the fraction of the maximum rate by which the
+	// rate of a BulkReadLimiter grows back every time the node is found to be
+	// keeping up with its foreground traffic.
+	bulkReadIncreaseFraction = 0.1
+)
+
+// BulkReadLimiter paces the data read on a store by elastic bulk work, i.e.
+// by ExportRequests issued by backups and by the catch-up scans of
+// rangefeeds. Both can read whole ranges as fast as the leaseholder lets them
+// and starve the foreground traffic of the node of CPU.
+//
+// The rate of the limiter is capped by a configured maximum and adapts to the
+// load of the node: Adjust halves it whenever the measured goroutine
+// scheduling latency exceeds its target and grows it back linearly when it
+// doesn't, so that bulk reads yield to foreground traffic.
+//
+// A nil *BulkReadLimiter doesn't limit anything.
+type BulkReadLimiter struct {
+	limiter *rate.Limiter
+	mu      struct {
+		syncutil.Mutex
+		// maxRate is the configured maximum rate in bytes per second. It is not
+		// positive if the rate isn't capped.
+		maxRate int64
+		// rate is the current rate in bytes per second, or 0 if bulk reads are
+		// currently not limited.
+		rate int64
+	}
+	// readBytes is the number of bytes admitted by the limiter.
+	readBytes *metric.Counter
+	// currentRate is the current rate of the limiter.
+	currentRate *metric.Gauge
+}
+
+// NewBulkReadLimiter creates a BulkReadLimiter that admits at most
+// maxBytesPerSec bytes per second, or doesn't cap the rate if maxBytesPerSec
+// is not positive.
+func NewBulkReadLimiter(
+	maxBytesPerSec int64, readBytes *metric.Counter, currentRate *metric.Gauge,
+) *BulkReadLimiter {
+	l := &BulkReadLimiter{
+		limiter:     rate.NewLimiter(rate.Inf, bulkReadBurst),
+		readBytes:   readBytes,
+		currentRate: currentRate,
+	}
+	l.SetMaxRate(maxBytesPerSec)
+	return l
+}
+
+// SetMaxRate updates the maximum rate of the limiter.
+func (l *BulkReadLimiter) SetMaxRate(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.maxRate = bytesPerSec
+	if bytesPerSec <= 0 {
+		l.setRateLocked(0)
+	} else if l.mu.rate == 0 || l.mu.rate > bytesPerSec {
+		l.setRateLocked(bytesPerSec)
+	}
+}
+
+// Adjust adapts the rate of the limiter to the measured goroutine scheduling
+// latency of the node. If the latency exceeds the target, the rate is halved,
+// down to bulkReadMinRate; otherwise it grows back towards the maximum rate.
+// A target that is not positive disables the adaptation.
+func (l *BulkReadLimiter) Adjust(schedulingLatency, target time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if target <= 0 {
+		l.setRateLocked(l.mu.maxRate)
+		return
+	}
+	if schedulingLatency > target {
+		r := l.mu.rate
+		if r == 0 {
+			// The rate isn't capped, so start backing off from the maximum rate of
+			// the limiter that would otherwise take effect if there's one, or from
+			// the burst otherwise.
+			r = l.mu.maxRate
+			if r <= 0 {
+				r = bulkReadBurst
+			}
+		}
+		r /= 2
+		if r < bulkReadMinRate {
+			r = bulkReadMinRate
+		}
+		l.setRateLocked(r)
+		return
+	}
+	if l.mu.rate == 0 {
+		return
+	}
+	if l.mu.maxRate <= 0 {
+		// Without a cap, grow back by doubling and stop limiting once the rate
+		// exceeds what the limiter admits in one go.
+		if r := 2 * l.mu.rate; r < bulkReadBurst {
+			l.setRateLocked(r)
+		} else {
+			l.setRateLocked(0)
+		}
+		return
+	}
+	r := l.mu.rate + int64(float64(l.mu.maxRate)*bulkReadIncreaseFraction)
+	if r > l.mu.maxRate {
+		r = l.mu.maxRate
+	}
+	l.setRateLocked(r)
+}
+
+func (l *BulkReadLimiter) setRateLocked(bytesPerSec int64) {
+	if bytesPerSec <= 0 {
+		bytesPerSec = 0
+		l.limiter.SetLimit(rate.Inf)
+	} else {
+		l.limiter.SetLimit(rate.Limit(bytesPerSec))
+	}
+	l.mu.rate = bytesPerSec
+	l.currentRate.Update(bytesPerSec)
+}
+
+// Rate returns the current rate of the limiter in bytes per second, or 0 if
+// bulk reads are currently not limited.
+func (l *BulkReadLimiter) Rate() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mu.rate
+}
+
+// Wait blocks until the limiter admits reading the given number of bytes or
+// the context is canceled.
+func (l *BulkReadLimiter) Wait(ctx context.Context, bytes int64) error {
+	if l == nil {
+		return nil
+	}
+	for bytes > 0 {
+		n := bytes
+		if n > bulkReadBurst {
+			n = bulkReadBurst
+		}
+		if err := l.limiter.WaitN(ctx, int(n)); err != nil {
+			return err
+		}
+		l.readBytes.Inc(n)
+		bytes -= n
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+func TestBulkReadLimiter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+
+	// A nil limiter doesn't limit anything.
+	var nilLimiter *BulkReadLimiter
+	if err := nilLimiter.Wait(ctx, 1<<40); err != nil {
+		t.Fatal(err)
+	}
+
+	read := metric.NewCounter(metric.Metadata{Name: "read"})
+	rate := metric.NewGauge(metric.Metadata{Name: "rate"})
+	l := NewBulkReadLimiter(0 /* maxBytesPerSec */, read, rate)
+
+	// Reads larger than the burst are admitted in chunks.
+	const bytes = 3*bulkReadBurst + 1
+	if err := l.Wait(ctx, bytes); err != nil {
+		t.Fatal(err)
+	}
+	if c := read.Count(); c != bytes {
+		t.Fatalf("expected %d read bytes, found %d", bytes, c)
+	}
+
+	expectRate := func(exp int64) {
+		t.Helper()
+		if r := l.Rate(); r != exp {
+			t.Fatalf("expected rate %d, found %d", exp, r)
+		}
+		if r := rate.Value(); r != exp {
+			t.Fatalf("expected rate gauge %d, found %d", exp, r)
+		}
+	}
+	const target = 5 * time.Millisecond
+	const max = 64 << 20
+
+	// Without a maximum rate, an overloaded node starts limiting bulk reads and
+	// stops once it keeps up again.
+	expectRate(0)
+	l.Adjust(10*time.Millisecond, target)
+	expectRate(bulkReadBurst / 2)
+	l.Adjust(time.Millisecond, target)
+	expectRate(0)
+
+	// With a maximum rate, the rate is halved while the node is overloaded, but
+	// never below the minimum rate, and grows back linearly.
+	l.SetMaxRate(max)
+	expectRate(max)
+	for i := 0; i < 10; i++ {
+		l.Adjust(10*time.Millisecond, target)
+	}
+	expectRate(bulkReadMinRate)
+	l.Adjust(time.Millisecond, target)
+	expectRate(bulkReadMinRate + max/10)
+	for i := 0; i < 10; i++ {
+		l.Adjust(time.Millisecond, target)
+	}
+	expectRate(max)
+
+	// Lowering the maximum rate takes effect immediately, and disabling the
+	// adaptation restores the maximum rate.
+	l.Adjust(10*time.Millisecond, target)
+	l.SetMaxRate(max / 4)
+	expectRate(max / 4)
+	l.Adjust(10*time.Millisecond, target)
+	expectRate(max / 8)
+	l.Adjust(10*time.Millisecond, 0 /* target */)
+	expectRate(max / 4)
+
+	// A canceled wait gives up.
+	l.SetMaxRate(1)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(cancelCtx, bytes); err == nil {
+		t.Fatal("expected an error waiting with a canceled context")
+	}
+	if c := read.Count(); c != bytes {
+		t.Fatalf("expected %d read bytes, found %d", bytes, c)
+	}
+}
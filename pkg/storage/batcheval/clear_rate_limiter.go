@@ -0,0 +1,85 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"golang.org/x/time/rate"
+)
+
+// clearRateBurst is the maximum number of bytes that a ClearRateLimiter admits
+// at once. Larger clears wait for the limiter in chunks of this size.
+const clearRateBurst = 8 << 20 // 8MiB
+
+// ClearRateLimiter paces the removal of data by ClearRange and RevertRange
+// commands on a store. Dropping or truncating a huge table issues a
+// ClearRange for every one of its ranges, and clearing all of them at once
+// can trigger a storm of compactions that hurts the latency of foreground
+// traffic.
+//
+// A nil *ClearRateLimiter doesn't limit anything.
+type ClearRateLimiter struct {
+	limiter *rate.Limiter
+	// queuedBytes is the number of bytes waiting for the limiter.
+	queuedBytes *metric.Gauge
+	// clearedBytes is the number of bytes admitted by the limiter.
+	clearedBytes *metric.Counter
+}
+
+// NewClearRateLimiter creates a ClearRateLimiter that admits bytesPerSec
+// bytes per second, or doesn't limit at all if bytesPerSec is not positive.
+func NewClearRateLimiter(
+	bytesPerSec int64, queuedBytes *metric.Gauge, clearedBytes *metric.Counter,
+) *ClearRateLimiter {
+	return &ClearRateLimiter{
+		limiter:      rate.NewLimiter(clearRateLimit(bytesPerSec), clearRateBurst),
+		queuedBytes:  queuedBytes,
+		clearedBytes: clearedBytes,
+	}
+}
+
+func clearRateLimit(bytesPerSec int64) rate.Limit {
+	if bytesPerSec <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(bytesPerSec)
+}
+
+// SetLimit updates the rate of the limiter.
+func (l *ClearRateLimiter) SetLimit(bytesPerSec int64) {
+	l.limiter.SetLimit(clearRateLimit(bytesPerSec))
+}
+
+// Wait blocks until the limiter admits clearing the given number of bytes or
+// the context is canceled.
+func (l *ClearRateLimiter) Wait(ctx context.Context, bytes int64) error {
+	if l == nil || bytes <= 0 {
+		return nil
+	}
+	l.queuedBytes.Inc(bytes)
+	// Whatever hasn't been admitted when we return is no longer queued.
+	defer func() { l.queuedBytes.Dec(bytes) }()
+	for bytes > 0 {
+		n := bytes
+		if n > clearRateBurst {
+			n = clearRateBurst
+		}
+		if err := l.limiter.WaitN(ctx, int(n)); err != nil {
+			return err
+		}
+		l.clearedBytes.Inc(n)
+		l.queuedBytes.Dec(n)
+		bytes -= n
+	}
+	return nil
+}
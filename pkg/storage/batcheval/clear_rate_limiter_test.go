@@ -0,0 +1,62 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+func TestClearRateLimiter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+
+	// A nil limiter doesn't limit anything.
+	var nilLimiter *ClearRateLimiter
+	if err := nilLimiter.Wait(ctx, 1<<40); err != nil {
+		t.Fatal(err)
+	}
+
+	queued := metric.NewGauge(metric.Metadata{Name: "queued"})
+	cleared := metric.NewCounter(metric.Metadata{Name: "cleared"})
+	l := NewClearRateLimiter(0 /* bytesPerSec */, queued, cleared)
+
+	// Clears larger than the burst are admitted in chunks.
+	const bytes = 3*clearRateBurst + 1
+	if err := l.Wait(ctx, bytes); err != nil {
+		t.Fatal(err)
+	}
+	if c := cleared.Count(); c != bytes {
+		t.Fatalf("expected %d cleared bytes, found %d", bytes, c)
+	}
+	if q := queued.Value(); q != 0 {
+		t.Fatalf("expected no queued bytes, found %d", q)
+	}
+
+	// With a limit in place, a canceled wait gives up and the bytes that were
+	// not admitted are no longer reported as queued.
+	l.SetLimit(1)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(cancelCtx, bytes); err == nil {
+		t.Fatal("expected an error waiting with a canceled context")
+	}
+	if c := cleared.Count(); c != bytes {
+		t.Fatalf("expected %d cleared bytes, found %d", bytes, c)
+	}
+	if q := queued.Value(); q != 0 {
+		t.Fatalf("expected no queued bytes, found %d", q)
+	}
+}
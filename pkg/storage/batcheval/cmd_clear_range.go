@@ -73,6 +73,12 @@ func ClearRange(
 	}
 	cArgs.Stats.Subtract(statsDelta)
 
+	// Pace the removal of the data so that dropping a huge table doesn't
+	// overwhelm the store with compactions.
+	if err := cArgs.EvalCtx.GetLimiters().ClearRate.Wait(ctx, statsDelta.Total()); err != nil {
+		return result.Result{}, err
+	}
+
 	// If the total size of data to be cleared is less than
 	// clearRangeBytesThreshold, clear the individual values manually,
 	// instead of using a range tombstone (inefficient for small ranges).
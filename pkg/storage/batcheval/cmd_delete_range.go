@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
 )
 
 func init() {
@@ -51,9 +52,30 @@ func DeleteRange(
 	if !args.Inline {
 		timestamp = h.Timestamp
 	}
-	deleted, resumeSpan, num, err := engine.MVCCDeleteRange(
-		ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, cArgs.MaxKeys, timestamp, h.Txn, args.ReturnKeys,
+	var (
+		deleted    []roachpb.Key
+		resumeSpan *roachpb.Span
+		num        int64
+		err        error
 	)
+	if !args.PredicateStartTime.IsEmpty() {
+		if args.Inline {
+			return result.Result{}, errors.New("predicate DeleteRange cannot be used on inline values")
+		}
+		if timestamp.Less(args.PredicateStartTime) {
+			return result.Result{}, errors.Errorf(
+				"predicate start time %s is above the request timestamp %s", args.PredicateStartTime, timestamp,
+			)
+		}
+		deleted, resumeSpan, num, err = engine.MVCCPredicateDeleteRange(
+			ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, cArgs.MaxKeys, timestamp, h.Txn,
+			args.ReturnKeys, args.PredicateStartTime,
+		)
+	} else {
+		deleted, resumeSpan, num, err = engine.MVCCDeleteRange(
+			ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, cArgs.MaxKeys, timestamp, h.Txn, args.ReturnKeys,
+		)
+	}
 	if err == nil {
 		reply.Keys = deleted
 	}
@@ -66,7 +66,7 @@ func (m *mockEvalCtx) DB() *client.DB {
 	panic("unimplemented")
 }
 func (m *mockEvalCtx) GetLimiters() *Limiters {
-	panic("unimplemented")
+	return &Limiters{}
 }
 func (m *mockEvalCtx) AbortSpan() *abortspan.AbortSpan {
 	return m.abortSpan
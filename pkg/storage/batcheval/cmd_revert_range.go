@@ -88,12 +88,19 @@ func RevertRange(
 
 	log.VEventf(ctx, 2, "clearing keys with timestamp (%v, %v]", args.TargetTime, cArgs.Header.Timestamp)
 
+	statsBefore := *cArgs.Stats
 	resume, err := engine.MVCCClearTimeRange(
 		ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, args.TargetTime, cArgs.Header.Timestamp, cArgs.MaxKeys,
 	)
 	if err != nil {
 		return result.Result{}, err
 	}
+	// The number of cleared bytes is only known once the keys have been
+	// cleared, so the pacing happens before the batch is proposed instead.
+	cleared := statsBefore.Total() - cArgs.Stats.Total()
+	if err := cArgs.EvalCtx.GetLimiters().ClearRate.Wait(ctx, cleared); err != nil {
+		return result.Result{}, err
+	}
 
 	if resume != nil {
 		log.VEventf(ctx, 2, "hit limit while clearing keys, resume span [%v, %v)", resume.Key, resume.EndKey)
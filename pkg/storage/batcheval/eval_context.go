@@ -40,6 +40,11 @@ type Limiters struct {
 	// is a temporary state at the beginning of a rangefeed which is expensive
 	// because it uses an engine iterator.
 	ConcurrentRangefeedIters limit.ConcurrentRequestLimiter
+	// ClearRate paces the data removal done by ClearRange and RevertRange.
+	ClearRate *ClearRateLimiter
+	// BulkReadRate paces the data read by ExportRequests and rangefeed
+	// catch-up scans, yielding to foreground traffic.
+	BulkReadRate *BulkReadLimiter
 }
 
 // EvalContext is the interface through which command evaluation accesses the
@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+const (
+	// bulkReadPacerSampleInterval is the interval at which the bulk read pacer
+	// samples the goroutine scheduling latency.
+	bulkReadPacerSampleInterval = 10 * time.Millisecond
+	// bulkReadPacerSamples is the number of samples over which the scheduling
+	// latency is measured every time the pacer adjusts the bulk read limiter.
+	bulkReadPacerSamples = 50
+	// bulkReadPacerPercentile is the percentile of the samples that is compared
+	// to the target latency. The largest samples are ignored so that isolated
+	// hiccups (e.g. GC pauses) don't throttle bulk reads.
+	bulkReadPacerPercentile = 0.9
+)
+
+// startBulkReadPacer starts a goroutine that measures the goroutine scheduling
+// latency of the node and adjusts the rate of the store's bulk read limiter
+// accordingly. The scheduling latency is estimated by how late a timer fires:
+// when the node runs more goroutines than it has CPUs, a goroutine that
+// becomes runnable waits in the run queue of the scheduler and its timer
+// fires late.
+func (s *Store) startBulkReadPacer(ctx context.Context) {
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		samples := make([]time.Duration, 0, bulkReadPacerSamples)
+		for {
+			start := timeutil.Now()
+			timer.Reset(bulkReadPacerSampleInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-s.stopper.ShouldStop():
+				return
+			}
+			samples = append(samples, timeutil.Since(start)-bulkReadPacerSampleInterval)
+			if len(samples) < bulkReadPacerSamples {
+				continue
+			}
+			latency := schedulingLatencyPercentile(samples, bulkReadPacerPercentile)
+			samples = samples[:0]
+			s.metrics.BulkReadSchedulingLatency.Update(latency.Nanoseconds())
+			s.limiters.BulkReadRate.Adjust(
+				latency, bulkIOReadTargetSchedulingLatency.Get(&s.cfg.Settings.SV),
+			)
+		}
+	})
+}
+
+// schedulingLatencyPercentile returns the given percentile of the samples,
+// which it sorts.
+func schedulingLatencyPercentile(samples []time.Duration, p float64) time.Duration {
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	idx := int(float64(len(samples)-1) * p)
+	if samples[idx] < 0 {
+		return 0
+	}
+	return samples[idx]
+}
+
+// pacedIterator is a SimpleIterator that waits for a BulkReadLimiter as it
+// reads data. It is used by the catch-up scans of rangefeeds.
+type pacedIterator struct {
+	engine.SimpleIterator
+	ctx     context.Context
+	limiter *batcheval.BulkReadLimiter
+	// pending is the number of bytes read since the last wait for the limiter.
+	pending int64
+	// err is the error returned by the limiter, if any. It is returned by
+	// Valid.
+	err error
+}
+
+// pacedIteratorChunk is the number of bytes that a pacedIterator reads before
+// waiting for its limiter.
+const pacedIteratorChunk = 256 << 10 // 256KiB
+
+var _ engine.SimpleIterator = &pacedIterator{}
+
+// Valid implements the SimpleIterator interface.
+func (i *pacedIterator) Valid() (bool, error) {
+	if i.err != nil {
+		return false, i.err
+	}
+	return i.SimpleIterator.Valid()
+}
+
+// Next implements the SimpleIterator interface.
+func (i *pacedIterator) Next() {
+	i.account()
+	i.SimpleIterator.Next()
+}
+
+// NextKey implements the SimpleIterator interface.
+func (i *pacedIterator) NextKey() {
+	i.account()
+	i.SimpleIterator.NextKey()
+}
+
+// account adds the size of the current key/value pair to the bytes read and
+// waits for the limiter once a chunk has been read.
+func (i *pacedIterator) account() {
+	if i.err != nil {
+		return
+	}
+	if ok, _ := i.SimpleIterator.Valid(); !ok {
+		return
+	}
+	i.pending += int64(i.SimpleIterator.UnsafeKey().EncodedSize() + len(i.SimpleIterator.UnsafeValue()))
+	if i.pending < pacedIteratorChunk {
+		return
+	}
+	i.err = i.limiter.Wait(i.ctx, i.pending)
+	i.pending = 0
+}
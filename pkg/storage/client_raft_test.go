@@ -1575,12 +1575,14 @@ func TestStoreRangeUpReplicate(t *testing.T) {
 	})
 
 	var generated int64
-	var learnerApplied, raftApplied int64
+	var learnerApplied, raftApplied, applyLatencies, ingestLatencies int64
 	for _, s := range mtc.stores {
 		m := s.Metrics()
 		generated += m.RangeSnapshotsGenerated.Count()
 		learnerApplied += m.RangeSnapshotsLearnerApplied.Count()
 		raftApplied += m.RangeSnapshotsNormalApplied.Count()
+		applyLatencies += m.RangeSnapshotApplyLatency.TotalCount()
+		ingestLatencies += m.RangeSnapshotIngestLatency.TotalCount()
 	}
 	if generated == 0 {
 		t.Fatalf("expected at least 1 snapshot, but found 0")
@@ -1593,6 +1595,13 @@ func TestStoreRangeUpReplicate(t *testing.T) {
 	if raftApplied > learnerApplied {
 		t.Fatalf("expected more learner snaps %d than raft snaps %d", learnerApplied, raftApplied)
 	}
+	// The latency of every applied snapshot is recorded, except for the empty
+	// snapshots discarded by raft.
+	if applyLatencies == 0 || applyLatencies > learnerApplied+raftApplied {
+		t.Fatalf("expected between 1 and %d snapshot apply latencies, but found %d",
+			learnerApplied+raftApplied, applyLatencies)
+	}
+	require.Equal(t, applyLatencies, ingestLatencies)
 }
 
 // TestUnreplicateFirstRange verifies that multiTestContext still functions in
@@ -31,6 +31,17 @@ var consistencyCheckInterval = settings.RegisterNonNegativeDurationSetting(
 	24*time.Hour,
 )
 
+// consistencyCheckRepairStatsEnabled controls whether the consistency checker
+// queue repairs the MVCC stats of the ranges whose persisted stats disagree
+// with a recomputation from the data. If it's disabled, the disagreement is
+// only logged.
+var consistencyCheckRepairStatsEnabled = settings.RegisterBoolSetting(
+	"server.consistency_check.repair_stats.enabled",
+	"if set, the consistency checker repairs the MVCC stats of the ranges that "+
+		"disagree with a recomputation from the data",
+	true,
+)
+
 var testingAggressiveConsistencyChecks = envutil.EnvOrDefaultBool("COCKROACH_CONSISTENCY_AGGRESSIVE", false)
 
 type consistencyQueue struct {
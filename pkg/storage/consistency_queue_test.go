@@ -379,10 +379,14 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 // The upreplication here is immaterial and serves only to add realism to the test.
 func TestConsistencyQueueRecomputeStats(t *testing.T) {
 	defer leaktest.AfterTest(t)()
-	testutils.RunTrueAndFalse(t, "hadEstimates", testConsistencyQueueRecomputeStatsImpl)
+	testutils.RunTrueAndFalse(t, "hadEstimates", func(t *testing.T, hadEstimates bool) {
+		testutils.RunTrueAndFalse(t, "repairDisabled", func(t *testing.T, repairDisabled bool) {
+			testConsistencyQueueRecomputeStatsImpl(t, hadEstimates, repairDisabled)
+		})
+	})
 }
 
-func testConsistencyQueueRecomputeStatsImpl(t *testing.T, hadEstimates bool) {
+func testConsistencyQueueRecomputeStatsImpl(t *testing.T, hadEstimates, repairDisabled bool) {
 	ctx := context.Background()
 
 	path, cleanup := testutils.TempDir(t)
@@ -547,6 +551,14 @@ func testConsistencyQueueRecomputeStatsImpl(t *testing.T, hadEstimates bool) {
 		t.Fatal(err)
 	}
 
+	if repairDisabled {
+		if _, err := tc.ServerConn(0).Exec(
+			`SET CLUSTER SETTING server.consistency_check.repair_stats.enabled = false`,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	// Force a run of the consistency queue, otherwise it might take a while.
 	ts := tc.Servers[0]
 	store, pErr := ts.Stores().GetStore(ts.GetFirstStoreID())
@@ -557,16 +569,27 @@ func testConsistencyQueueRecomputeStatsImpl(t *testing.T, hadEstimates bool) {
 		t.Fatal(err)
 	}
 
-	// The stats should magically repair themselves. We'll first do a quick check
-	// and then a full recomputation.
 	repl, err := ts.Stores().GetReplicaForRangeID(rangeID)
 	if err != nil {
 		t.Fatal(err)
 	}
 	ms := repl.GetMVCCStats()
+	if repairDisabled {
+		// The garbage in the stats is left alone.
+		if ms.SysCount < sysCountGarbage {
+			t.Fatalf("expected the SysCount of %d to still contain the garbage", ms.SysCount)
+		}
+		return
+	}
+
+	// The stats should magically repair themselves. We'll first do a quick check
+	// and then a full recomputation.
 	if ms.SysCount >= sysCountGarbage {
 		t.Fatalf("still have a SysCount of %d", ms.SysCount)
 	}
+	if n := store.Metrics().ConsistencyQueueStatsRepairs.Count(); n == 0 {
+		t.Fatal("expected the stats repair to be counted")
+	}
 
 	if delta := computeDelta(db0); delta != (enginepb.MVCCStats{}) {
 		t.Fatalf("stats still in need of adjustment: %+v", delta)
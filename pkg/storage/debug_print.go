@@ -172,7 +172,10 @@ func tryRaftLogEntry(kv engine.MVCCKeyValue) (string, error) {
 		if len(ent.Data) == 0 {
 			return fmt.Sprintf("%s: EMPTY\n", &ent), nil
 		}
-		_, cmdData := DecodeRaftCommand(ent.Data)
+		_, cmdData, err := DecodeRaftCommand(ent.Data)
+		if err != nil {
+			return "", err
+		}
 		if err := protoutil.Unmarshal(cmdData, &cmd); err != nil {
 			return "", err
 		}
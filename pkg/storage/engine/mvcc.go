@@ -2237,6 +2237,44 @@ func MVCCDeleteRange(
 	timestamp hlc.Timestamp,
 	txn *roachpb.Transaction,
 	returnKeys bool,
+) ([]roachpb.Key, *roachpb.Span, int64, error) {
+	return mvccDeleteRange(
+		ctx, rw, ms, key, endKey, max, timestamp, txn, returnKeys, hlc.Timestamp{}, /* predicateStartTime */
+	)
+}
+
+// MVCCPredicateDeleteRange is like MVCCDeleteRange, but it only deletes the
+// keys whose latest version was written at or after predicateStartTime. The
+// keys that were last written before predicateStartTime (as well as the keys
+// that are already deleted) are left untouched. Note that max limits the
+// number of keys that are scanned rather than the number of keys that are
+// deleted.
+func MVCCPredicateDeleteRange(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	key, endKey roachpb.Key,
+	max int64,
+	timestamp hlc.Timestamp,
+	txn *roachpb.Transaction,
+	returnKeys bool,
+	predicateStartTime hlc.Timestamp,
+) ([]roachpb.Key, *roachpb.Span, int64, error) {
+	return mvccDeleteRange(
+		ctx, rw, ms, key, endKey, max, timestamp, txn, returnKeys, predicateStartTime,
+	)
+}
+
+func mvccDeleteRange(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	key, endKey roachpb.Key,
+	max int64,
+	timestamp hlc.Timestamp,
+	txn *roachpb.Transaction,
+	returnKeys bool,
+	predicateStartTime hlc.Timestamp,
 ) ([]roachpb.Key, *roachpb.Span, int64, error) {
 	// In order to detect the potential write intent by another concurrent
 	// transaction with a newer timestamp, we need to use the max timestamp for
@@ -2256,6 +2294,17 @@ func MVCCDeleteRange(
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	if !predicateStartTime.IsEmpty() {
+		// Filter out (in place) the keys that were last written before the
+		// predicate's start time.
+		filtered := kvs[:0]
+		for i := range kvs {
+			if !kvs[i].Value.Timestamp.Less(predicateStartTime) {
+				filtered = append(filtered, kvs[i])
+			}
+		}
+		kvs = filtered
+	}
 
 	buf := newPutBuffer()
 	iter := rw.NewIterator(IterOptions{Prefix: true})
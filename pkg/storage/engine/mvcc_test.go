@@ -2017,6 +2017,75 @@ func TestMVCCDeleteRangeReturnKeys(t *testing.T) {
 	}
 }
 
+func TestMVCCPredicateDeleteRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			// testKey1 and testKey2 are written before the predicate's start time,
+			// testKey2 and testKey3 are (re)written after it, and testKey4 is
+			// deleted after it.
+			for _, kv := range []struct {
+				key   roachpb.Key
+				ts    int64
+				value roachpb.Value
+			}{
+				{testKey1, 1, value1},
+				{testKey2, 1, value2},
+				{testKey2, 3, value3},
+				{testKey3, 4, value3},
+				{testKey4, 1, value4},
+			} {
+				if err := MVCCPut(ctx, engine, nil, kv.key, hlc.Timestamp{WallTime: kv.ts}, kv.value, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := MVCCDelete(ctx, engine, nil, testKey4, hlc.Timestamp{WallTime: 3}, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			deleted, resumeSpan, num, err := MVCCPredicateDeleteRange(
+				ctx, engine, nil, keyMin, keyMax, math.MaxInt64, hlc.Timestamp{WallTime: 5}, nil,
+				true /* returnKeys */, hlc.Timestamp{WallTime: 2},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resumeSpan != nil {
+				t.Fatalf("unexpected resume span %s", resumeSpan)
+			}
+			if num != 2 || len(deleted) != 2 ||
+				!deleted[0].Equal(testKey2) || !deleted[1].Equal(testKey3) {
+				t.Fatalf("unexpected deleted keys %v (num = %d)", deleted, num)
+			}
+
+			kvs, _, _, err := MVCCScan(ctx, engine, keyMin, keyMax, math.MaxInt64,
+				hlc.Timestamp{WallTime: 5}, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(kvs) != 1 || !kvs[0].Key.Equal(testKey1) {
+				t.Fatalf("expected only %s to remain, found %v", testKey1, kvs)
+			}
+			// The older versions of the deleted keys are still visible at earlier
+			// timestamps.
+			kvs, _, _, err = MVCCScan(ctx, engine, keyMin, keyMax, math.MaxInt64,
+				hlc.Timestamp{WallTime: 3}, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(kvs) != 2 || !kvs[1].Key.Equal(testKey2) ||
+				!bytes.Equal(kvs[1].Value.RawBytes, value3.RawBytes) {
+				t.Fatalf("unexpected values at the earlier timestamp: %v", kvs)
+			}
+		})
+	}
+}
+
 func TestMVCCDeleteRangeFailed(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -39,6 +39,17 @@ func NewTempEngine(
 	panic(fmt.Sprintf("unknown engine type: %d", engine))
 }
 
+// NewTempFS returns the filesystem in which DistSQL processors can store files
+// in tempStorage.Path when the working set is larger than can be stored in
+// memory. Like the temp engine, it keeps the files in memory if tempStorage is
+// in-memory.
+func NewTempFS(tempStorage base.TempStorageConfig) vfs.FS {
+	if tempStorage.InMemory {
+		return vfs.NewMem()
+	}
+	return vfs.Default
+}
+
 type rocksDBTempEngine struct {
 	db *RocksDB
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -105,6 +106,9 @@ type Config struct {
 	AmbientCtx           log.AmbientContext
 	TestingKnobs         storagebase.IntentResolverTestingKnobs
 	RangeDescriptorCache kvbase.RangeDescriptorCache
+	// ContentionListener, if set, is notified about the time requests spend
+	// waiting on conflicting transactions.
+	ContentionListener storagebase.ContentionListener
 
 	TaskLimit                    int
 	MaxGCBatchWait               time.Duration
@@ -123,6 +127,7 @@ type IntentResolver struct {
 	stopper      *stop.Stopper
 	testingKnobs storagebase.IntentResolverTestingKnobs
 	ambientCtx   log.AmbientContext
+	contentionL  storagebase.ContentionListener
 	sem          chan struct{}    // Semaphore to limit async goroutines.
 	contentionQ  *contentionQueue // manages contention on individual keys
 
@@ -191,6 +196,7 @@ func New(c Config) *IntentResolver {
 		Metrics:      makeMetrics(),
 		rdc:          c.RangeDescriptorCache,
 		testingKnobs: c.TestingKnobs,
+		contentionL:  c.ContentionListener,
 	}
 	ir.mu.inFlightPushes = map[uuid.UUID]int{}
 	ir.mu.inFlightTxnCleanups = map[uuid.UUID]struct{}{}
@@ -247,6 +253,13 @@ func (ir *IntentResolver) ProcessWriteIntentError(
 		log.Infof(ctx, "resolving write intent %s", wiErr)
 	}
 
+	if ir.contentionL != nil {
+		// Note that the contention queue may have us wait on a different
+		// transaction than the intent holders, but the intent holders are the
+		// ones responsible for the contention.
+		defer ir.notifyContention(ctx, h.Txn, wiErr.Intents, timeutil.Now())
+	}
+
 	// Possibly queue this processing if the write intent error is for a
 	// single intent affecting a unitary key.
 	var cleanup func(*roachpb.WriteIntentError, *enginepb.TxnMeta)
@@ -287,6 +300,27 @@ func (ir *IntentResolver) ProcessWriteIntentError(
 	return cleanup, nil
 }
 
+// notifyContention reports the time spent waiting since start on the
+// transactions owning the given intents to the ContentionListener.
+func (ir *IntentResolver) notifyContention(
+	ctx context.Context, pusher *roachpb.Transaction, intents []roachpb.Intent, start time.Time,
+) {
+	waited := timeutil.Since(start)
+	var pusherMeta *enginepb.TxnMeta
+	if pusher != nil {
+		pusherMeta = &pusher.TxnMeta
+	}
+	for i := range intents {
+		pushee := intents[i].Txn
+		if i > 0 && pushee.ID == intents[i-1].Txn.ID {
+			// Intents are usually grouped by transaction, so this avoids
+			// reporting the same wait for a transaction multiple times.
+			continue
+		}
+		ir.contentionL.OnContention(ctx, pusherMeta, pushee, waited)
+	}
+}
+
 func getPusherTxn(h roachpb.Header) roachpb.Transaction {
 	// If the txn is nil, we communicate a priority by sending an empty
 	// txn with only the priority set. This is official usage of PushTxn.
@@ -13,11 +13,13 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
@@ -185,6 +187,72 @@ func (s *Store) logChange(
 	})
 }
 
+// rangeLogAsyncTimeout bounds the time spent writing a range log event which
+// isn't part of the transaction that caused it, so that the tasks don't pile up
+// when the range log can't be written to (for example, during an availability
+// incident).
+const rangeLogAsyncTimeout = 10 * time.Second
+
+// logTransferLease logs the acquisition of the lease of a range by a replica on
+// this store from a replica on another store. The event is written
+// asynchronously on a best-effort basis because leases are applied below Raft,
+// where we can't block on a transaction.
+func (s *Store) logTransferLease(
+	ctx context.Context, desc roachpb.RangeDescriptor, prevLease, newLease roachpb.Lease,
+) {
+	if !s.cfg.LogRangeEvents {
+		return
+	}
+	s.logRangeEventAsync(ctx, storagepb.RangeLogEvent{
+		RangeID:   desc.RangeID,
+		EventType: storagepb.RangeLogEventType_transfer_lease,
+		StoreID:   s.StoreID(),
+		Info: &storagepb.RangeLogEvent_Info{
+			UpdatedDesc: &desc,
+			Details:     fmt.Sprintf("new lease %s following %s", newLease, prevLease),
+		},
+	})
+}
+
+// logApplySnapshot logs the application of a snapshot by a replica on this
+// store. Like logTransferLease, the event is written asynchronously on a
+// best-effort basis.
+func (s *Store) logApplySnapshot(
+	ctx context.Context, desc roachpb.RangeDescriptor, snapType SnapshotRequest_Type, index uint64,
+) {
+	if !s.cfg.LogRangeEvents {
+		return
+	}
+	s.logRangeEventAsync(ctx, storagepb.RangeLogEvent{
+		RangeID:   desc.RangeID,
+		EventType: storagepb.RangeLogEventType_apply_snapshot,
+		StoreID:   s.StoreID(),
+		Info: &storagepb.RangeLogEvent_Info{
+			UpdatedDesc: &desc,
+			Details:     fmt.Sprintf("%s snapshot at applied index %d", snapType, index),
+		},
+	})
+}
+
+// logRangeEventAsync writes the range log event in its own transaction in an
+// async task. Failures are logged and otherwise ignored.
+func (s *Store) logRangeEventAsync(ctx context.Context, event storagepb.RangeLogEvent) {
+	taskCtx := s.AnnotateCtx(context.Background())
+	if err := s.stopper.RunAsyncTask(taskCtx, "log-range-event", func(ctx context.Context) {
+		if err := contextutil.RunWithTimeout(ctx, "log-range-event", rangeLogAsyncTimeout,
+			func(ctx context.Context) error {
+				return s.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+					event.Timestamp = selectEventTimestamp(s, txn.ReadTimestamp())
+					return s.insertRangeLogEvent(ctx, txn, event)
+				})
+			}); err != nil {
+			log.Warningf(ctx, "unable to log %s event for r%d: %+v", event.EventType, event.RangeID, err)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to log %s event for r%d: %+v", event.EventType, event.RangeID, err)
+	}
+}
+
 // selectEventTimestamp selects a timestamp for this log message. If the
 // transaction this event is being written in has a non-zero timestamp, then that
 // timestamp should be used; otherwise, the store's physical clock is used.
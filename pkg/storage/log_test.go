@@ -25,11 +25,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	_ "github.com/lib/pq"
+	"github.com/pkg/errors"
 )
 
 func TestLogSplits(t *testing.T) {
@@ -393,3 +396,39 @@ func TestLogRebalances(t *testing.T) {
 		t.Errorf("expected %d RemoveReplica events logged, found %d", e, a)
 	}
 }
+
+func TestLogLeaseTransfersAndSnapshots(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	// Adding a replica sends it a snapshot, and the lease is then moved to the
+	// new replica.
+	key := tc.ScratchRange(t)
+	desc := tc.AddReplicasOrFatal(t, key, tc.Target(1))
+	if err := tc.TransferRangeLease(desc, tc.Target(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The events are logged asynchronously.
+	for _, eventType := range []storagepb.RangeLogEventType{
+		storagepb.RangeLogEventType_apply_snapshot,
+		storagepb.RangeLogEventType_transfer_lease,
+	} {
+		testutils.SucceedsSoon(t, func() error {
+			var count int
+			db.QueryRow(t,
+				`SELECT count(*) FROM system.rangelog WHERE "rangeID" = $1 AND "storeID" = $2 AND "eventType" = $3`,
+				desc.RangeID, tc.Target(1).StoreID, eventType.String(),
+			).Scan(&count)
+			if count == 0 {
+				return errors.Errorf("no %s event logged for r%d", eventType, desc.RangeID)
+			}
+			return nil
+		})
+	}
+}
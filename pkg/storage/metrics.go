@@ -288,6 +288,42 @@ var (
 		Measurement: "Read Ops",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaFollowerReadsRedirectCount = metric.Metadata{
+		Name:        "follower_reads.redirect_count",
+		Help:        "Number of reads that a replica without the lease could not serve as follower reads and redirected to the leaseholder",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaFollowerReadsClosedTSRedirects = metric.Metadata{
+		Name:        "follower_reads.redirect.closed_timestamp_count",
+		Help:        "Number of reads redirected to the leaseholder because they were above the closed timestamp of the follower",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaFollowerReadsLeaseTypeRedirects = metric.Metadata{
+		Name:        "follower_reads.redirect.lease_type_count",
+		Help:        "Number of reads redirected to the leaseholder because the range had an expiration-based lease",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaLatchFreeReadsCount = metric.Metadata{
+		Name:        "follower_reads.latch_free_count",
+		Help:        "Number of reads below the closed timestamp served without latches or timestamp cache updates",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaSystemReadCacheHits = metric.Metadata{
+		Name:        "system_read_cache.hits",
+		Help:        "Number of point reads of system tables served from the leaseholder read cache",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaSystemReadCacheMisses = metric.Metadata{
+		Name:        "system_read_cache.misses",
+		Help:        "Number of point reads of system tables that missed the leaseholder read cache",
+		Measurement: "Read Ops",
+		Unit:        metric.Unit_COUNT,
+	}
 
 	// RocksDB metrics.
 	metaRdbBlockCacheHits = metric.Metadata{
@@ -418,6 +454,18 @@ var (
 		Measurement: "Snapshots",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRangeSnapshotApplyLatency = metric.Metadata{
+		Name:        "range.snapshots.apply-latency",
+		Help:        "Latency histogram for applying snapshots, including the ingestion of their SSTs",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRangeSnapshotIngestLatency = metric.Metadata{
+		Name:        "range.snapshots.ingest-latency",
+		Help:        "Latency histogram for ingesting the SSTs of applied snapshots into the engine",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 	metaRangeRaftLeaderTransfers = metric.Metadata{
 		Name:        "range.raftleadertransfers",
 		Help:        "Number of raft leader transfers",
@@ -474,6 +522,62 @@ var (
 		Measurement: "Latency",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRaftSchedulerQueueLength = metric.Metadata{
+		Name:        "raft.scheduler.queue_length",
+		Help:        "Number of ranges queued in the Raft scheduler",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRaftSchedulerLatency = metric.Metadata{
+		Name:        "raft.scheduler.latency",
+		Help:        "Latency histogram for ranges waiting in the Raft scheduler queue",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRaftSchedulerPriorityQueueLength = metric.Metadata{
+		Name:        "raft.scheduler.priority.queue_length",
+		Help:        "Number of system ranges queued in the priority shard of the Raft scheduler",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRaftSchedulerPriorityLatency = metric.Metadata{
+		Name:        "raft.scheduler.priority.latency",
+		Help:        "Latency histogram for system ranges waiting in the priority queue of the Raft scheduler",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+
+	// Raft command compression metrics.
+	metaRaftCommandsCompressed = metric.Metadata{
+		Name:        "raft.commands.compressed",
+		Help:        "Number of Raft commands proposed with a compressed payload",
+		Measurement: "Commands",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRaftCommandCompressionInputBytes = metric.Metadata{
+		Name:        "raft.commands.compression.inputbytes",
+		Help:        "Number of bytes of the Raft command payloads considered for compression",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRaftCommandCompressionOutputBytes = metric.Metadata{
+		Name:        "raft.commands.compression.outputbytes",
+		Help:        "Number of bytes of the Raft command payloads considered for compression, as proposed",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRaftCommandCompressionNanos = metric.Metadata{
+		Name:        "raft.commands.compression.nanos",
+		Help:        "Nanoseconds spent compressing Raft command payloads",
+		Measurement: "Processing Time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRaftCommandDecompressionNanos = metric.Metadata{
+		Name:        "raft.commands.decompression.nanos",
+		Help:        "Nanoseconds spent decoding compressed Raft commands for application",
+		Measurement: "Processing Time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 
 	// Raft message metrics.
 	metaRaftRcvdProp = metric.Metadata{
@@ -708,6 +812,12 @@ var (
 		Measurement: "Processing Time",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaConsistencyQueueStatsRepairs = metric.Metadata{
+		Name:        "queue.consistency.statsrepairs",
+		Help:        "Number of ranges whose MVCC stats disagreeing with a recomputation were repaired by the consistency checker queue",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaReplicaGCQueueSuccesses = metric.Metadata{
 		Name:        "queue.replicagc.process.success",
 		Help:        "Number of replicas successfully processed by the replica GC queue",
@@ -963,6 +1073,40 @@ var (
 		Unit:        metric.Unit_NANOSECONDS,
 	}
 
+	// ClearRange/RevertRange pacing metrics.
+	metaClearRangeBytesQueued = metric.Metadata{
+		Name:        "clearrange.bytes.queued",
+		Help:        "Number of bytes removed by ClearRange and RevertRange requests that are waiting for the clear rate limiter",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaClearRangeBytesCleared = metric.Metadata{
+		Name:        "clearrange.bytes.cleared",
+		Help:        "Number of bytes removed by ClearRange and RevertRange requests that were admitted by the clear rate limiter",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+
+	// Bulk read pacing metrics.
+	metaBulkReadBytes = metric.Metadata{
+		Name:        "bulkread.bytes",
+		Help:        "Number of bytes read by export requests and rangefeed catch-up scans that were admitted by the bulk read rate limiter",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaBulkReadRate = metric.Metadata{
+		Name:        "bulkread.rate",
+		Help:        "Current rate (bytes/sec) of the bulk read rate limiter, or 0 if bulk reads are not limited",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaBulkReadSchedulingLatency = metric.Metadata{
+		Name:        "bulkread.scheduling_latency",
+		Help:        "Goroutine scheduling latency last measured to pace bulk reads",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+
 	// Encryption-at-rest metrics.
 	// TODO(mberhault): metrics for key age, per-key file/bytes counts.
 	metaEncryptionAlgorithm = metric.Metadata{
@@ -1037,7 +1181,16 @@ type StoreMetrics struct {
 	AverageWritesPerSecond  *metric.GaugeFloat64
 
 	// Follower read metrics.
-	FollowerReadsCount *metric.Counter
+	FollowerReadsCount  *metric.Counter
+	LatchFreeReadsCount *metric.Counter
+
+	FollowerReadsRedirectCount      *metric.Counter
+	FollowerReadsClosedTSRedirects  *metric.Counter
+	FollowerReadsLeaseTypeRedirects *metric.Counter
+
+	// System read cache metrics.
+	SystemReadCacheHits   *metric.Counter
+	SystemReadCacheMisses *metric.Counter
 
 	// RocksDB metrics.
 	RdbBlockCacheHits           *metric.Gauge
@@ -1068,6 +1221,8 @@ type StoreMetrics struct {
 	RangeSnapshotsNormalApplied     *metric.Counter
 	RangeSnapshotsLearnerApplied    *metric.Counter
 	RangeSnapshotsPreemptiveApplied *metric.Counter
+	RangeSnapshotApplyLatency       *metric.Histogram
+	RangeSnapshotIngestLatency      *metric.Histogram
 	RangeRaftLeaderTransfers        *metric.Counter
 
 	// Raft processing metrics.
@@ -1080,6 +1235,21 @@ type StoreMetrics struct {
 	RaftHandleReadyLatency    *metric.Histogram
 	RaftApplyCommittedLatency *metric.Histogram
 
+	// Raft scheduler metrics.
+	RaftSchedulerQueueLength         *metric.Gauge
+	RaftSchedulerLatency             *metric.Histogram
+	RaftSchedulerPriorityQueueLength *metric.Gauge
+	RaftSchedulerPriorityLatency     *metric.Histogram
+
+	// Raft command compression metrics. The ratio of the output to the input
+	// bytes is the compression ratio of the commands that were large enough to
+	// be considered for compression.
+	RaftCommandsCompressed            *metric.Counter
+	RaftCommandCompressionInputBytes  *metric.Counter
+	RaftCommandCompressionOutputBytes *metric.Counter
+	RaftCommandCompressionNanos       *metric.Counter
+	RaftCommandDecompressionNanos     *metric.Counter
+
 	// Raft message metrics.
 	RaftRcvdMsgProp           *metric.Counter
 	RaftRcvdMsgApp            *metric.Counter
@@ -1130,6 +1300,7 @@ type StoreMetrics struct {
 	ConsistencyQueueFailures                  *metric.Counter
 	ConsistencyQueuePending                   *metric.Gauge
 	ConsistencyQueueProcessingNanos           *metric.Counter
+	ConsistencyQueueStatsRepairs              *metric.Counter
 	ReplicaGCQueueSuccesses                   *metric.Counter
 	ReplicaGCQueueFailures                    *metric.Counter
 	ReplicaGCQueuePending                     *metric.Gauge
@@ -1181,6 +1352,17 @@ type StoreMetrics struct {
 	AddSSTableProposalTotalDelay  *metric.Counter
 	AddSSTableProposalEngineDelay *metric.Counter
 
+	// ClearRange/RevertRange pacing: how many of the bytes to be removed are
+	// waiting for the clear rate limiter and how many were admitted by it?
+	ClearRangeBytesQueued  *metric.Gauge
+	ClearRangeBytesCleared *metric.Counter
+
+	// Bulk read pacing: how many bytes were admitted by the bulk read rate
+	// limiter, its current rate and the scheduling latency it adapts to.
+	BulkReadBytes             *metric.Counter
+	BulkReadRate              *metric.Gauge
+	BulkReadSchedulingLatency *metric.Gauge
+
 	// Encryption-at-rest stats.
 	// EncryptionAlgorithm is an enum representing the cipher in use, so we use a gauge.
 	EncryptionAlgorithm *metric.Gauge
@@ -1249,7 +1431,16 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		AverageWritesPerSecond:  metric.NewGaugeFloat64(metaAverageWritesPerSecond),
 
 		// Follower reads metrics.
-		FollowerReadsCount: metric.NewCounter(metaFollowerReadsCount),
+		FollowerReadsCount:  metric.NewCounter(metaFollowerReadsCount),
+		LatchFreeReadsCount: metric.NewCounter(metaLatchFreeReadsCount),
+
+		FollowerReadsRedirectCount:      metric.NewCounter(metaFollowerReadsRedirectCount),
+		FollowerReadsClosedTSRedirects:  metric.NewCounter(metaFollowerReadsClosedTSRedirects),
+		FollowerReadsLeaseTypeRedirects: metric.NewCounter(metaFollowerReadsLeaseTypeRedirects),
+
+		// System read cache metrics.
+		SystemReadCacheHits:   metric.NewCounter(metaSystemReadCacheHits),
+		SystemReadCacheMisses: metric.NewCounter(metaSystemReadCacheMisses),
 
 		// RocksDB metrics.
 		RdbBlockCacheHits:           metric.NewGauge(metaRdbBlockCacheHits),
@@ -1275,6 +1466,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RangeSnapshotsNormalApplied:     metric.NewCounter(metaRangeSnapshotsNormalApplied),
 		RangeSnapshotsLearnerApplied:    metric.NewCounter(metaRangeSnapshotsLearnerApplied),
 		RangeSnapshotsPreemptiveApplied: metric.NewCounter(metaRangeSnapshotsPreemptiveApplied),
+		RangeSnapshotApplyLatency:       metric.NewLatency(metaRangeSnapshotApplyLatency, histogramWindow),
+		RangeSnapshotIngestLatency:      metric.NewLatency(metaRangeSnapshotIngestLatency, histogramWindow),
 		RangeRaftLeaderTransfers:        metric.NewCounter(metaRangeRaftLeaderTransfers),
 
 		// Raft processing metrics.
@@ -1282,11 +1475,24 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RaftWorkingDurationNanos:  metric.NewCounter(metaRaftWorkingDurationNanos),
 		RaftTickingDurationNanos:  metric.NewCounter(metaRaftTickingDurationNanos),
 		RaftCommandsApplied:       metric.NewCounter(metaRaftCommandsApplied),
-		RaftLogCommitLatency:      metric.NewLatency(metaRaftLogCommitLatency, histogramWindow),
-		RaftCommandCommitLatency:  metric.NewLatency(metaRaftCommandCommitLatency, histogramWindow),
+		RaftLogCommitLatency:      metric.NewHighResLatency(metaRaftLogCommitLatency, histogramWindow),
+		RaftCommandCommitLatency:  metric.NewHighResLatency(metaRaftCommandCommitLatency, histogramWindow),
 		RaftHandleReadyLatency:    metric.NewLatency(metaRaftHandleReadyLatency, histogramWindow),
 		RaftApplyCommittedLatency: metric.NewLatency(metaRaftApplyCommittedLatency, histogramWindow),
 
+		// Raft scheduler metrics.
+		RaftSchedulerQueueLength:         metric.NewGauge(metaRaftSchedulerQueueLength),
+		RaftSchedulerLatency:             metric.NewHighResLatency(metaRaftSchedulerLatency, histogramWindow),
+		RaftSchedulerPriorityQueueLength: metric.NewGauge(metaRaftSchedulerPriorityQueueLength),
+		RaftSchedulerPriorityLatency:     metric.NewHighResLatency(metaRaftSchedulerPriorityLatency, histogramWindow),
+
+		// Raft command compression metrics.
+		RaftCommandsCompressed:            metric.NewCounter(metaRaftCommandsCompressed),
+		RaftCommandCompressionInputBytes:  metric.NewCounter(metaRaftCommandCompressionInputBytes),
+		RaftCommandCompressionOutputBytes: metric.NewCounter(metaRaftCommandCompressionOutputBytes),
+		RaftCommandCompressionNanos:       metric.NewCounter(metaRaftCommandCompressionNanos),
+		RaftCommandDecompressionNanos:     metric.NewCounter(metaRaftCommandDecompressionNanos),
+
 		// Raft message metrics.
 		RaftRcvdMsgProp:           metric.NewCounter(metaRaftRcvdProp),
 		RaftRcvdMsgApp:            metric.NewCounter(metaRaftRcvdApp),
@@ -1334,6 +1540,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		ConsistencyQueueFailures:                  metric.NewCounter(metaConsistencyQueueFailures),
 		ConsistencyQueuePending:                   metric.NewGauge(metaConsistencyQueuePending),
 		ConsistencyQueueProcessingNanos:           metric.NewCounter(metaConsistencyQueueProcessingNanos),
+		ConsistencyQueueStatsRepairs:              metric.NewCounter(metaConsistencyQueueStatsRepairs),
 		ReplicaGCQueueSuccesses:                   metric.NewCounter(metaReplicaGCQueueSuccesses),
 		ReplicaGCQueueFailures:                    metric.NewCounter(metaReplicaGCQueueFailures),
 		ReplicaGCQueuePending:                     metric.NewGauge(metaReplicaGCQueuePending),
@@ -1384,6 +1591,15 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		AddSSTableProposalTotalDelay:  metric.NewCounter(metaAddSSTableEvalTotalDelay),
 		AddSSTableProposalEngineDelay: metric.NewCounter(metaAddSSTableEvalEngineDelay),
 
+		// ClearRange/RevertRange pacing.
+		ClearRangeBytesQueued:  metric.NewGauge(metaClearRangeBytesQueued),
+		ClearRangeBytesCleared: metric.NewCounter(metaClearRangeBytesCleared),
+
+		// Bulk read pacing.
+		BulkReadBytes:             metric.NewCounter(metaBulkReadBytes),
+		BulkReadRate:              metric.NewGauge(metaBulkReadRate),
+		BulkReadSchedulingLatency: metric.NewGauge(metaBulkReadSchedulingLatency),
+
 		// Encryption-at-rest.
 		EncryptionAlgorithm: metric.NewGauge(metaEncryptionAlgorithm),
 
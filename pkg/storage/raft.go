@@ -216,7 +216,7 @@ func raftEntryFormatter(data []byte) string {
 	if len(data) == 0 {
 		return "[empty]"
 	}
-	commandID, _ := DecodeRaftCommand(data)
+	commandID := decodeRaftCommandID(data)
 	return fmt.Sprintf("[%x] [%d]", commandID, len(data))
 }
 
@@ -271,7 +271,7 @@ func (r *Replica) traceMessageSends(msgs []raftpb.Message, event string) {
 func extractIDs(ids []storagebase.CmdIDKey, ents []raftpb.Entry) []storagebase.CmdIDKey {
 	for _, e := range ents {
 		if e.Type == raftpb.EntryNormal && len(e.Data) > 0 {
-			id, _ := DecodeRaftCommand(e.Data)
+			id := decodeRaftCommandID(e.Data)
 			ids = append(ids, id)
 		}
 	}
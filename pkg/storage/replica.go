@@ -230,6 +230,13 @@ type Replica struct {
 	// the rest (e.g. RangeDescriptor, transaction record, Lease, ...).
 	latchMgr spanlatch.Manager
 
+	// historyLatchMgr holds non-MVCC write latches over the global spans of the
+	// requests that rewrite the MVCC history of their keys, even below the
+	// closed timestamp (ClearRange, AddSSTable and GC). The reads served without
+	// latching (see canServeLatchFreeRead) acquire read latches from it instead
+	// of from latchMgr.
+	historyLatchMgr spanlatch.Manager
+
 	mu struct {
 		// Protects all fields in the mu struct.
 		syncutil.RWMutex
@@ -353,6 +360,9 @@ type Replica struct {
 		// initialMaxClosed is the initial maxClosed timestamp for the replica as known
 		// from its left-hand-side upon creation.
 		initialMaxClosed hlc.Timestamp
+		// followerReadRejection is the reason for which the replica last failed
+		// to serve a read as a follower read. It is reported in the range status.
+		followerReadRejection string
 
 		// The most recently updated time for each follower of this range. This is updated
 		// every time a Raft message is received from a peer.
@@ -481,6 +491,11 @@ type Replica struct {
 		opFilter *rangefeed.Filter
 	}
 
+	// readCache caches point reads of frequently read system tables. It is
+	// invalidated by the logical operations that feed the rangefeed. See
+	// replicaReadCache.
+	readCache replicaReadCache
+
 	// Throttle how often we offer this Replica to the split and merge queues.
 	// We have triggers downstream of Raft that do so based on limited
 	// information and without explicit throttling some replicas will offer once
@@ -902,6 +917,7 @@ func (r *Replica) State() storagepb.RangeInfo {
 		}
 	}
 	ri.RangeMaxBytes = *r.mu.zone.RangeMaxBytes
+	ri.FollowerReadRejection = r.mu.followerReadRejection
 	if desc := ri.ReplicaState.Desc; desc != nil {
 		// Learner replicas don't serve follower reads, but they still receive
 		// closed timestamp updates, so include them here.
@@ -1152,6 +1168,9 @@ func (r *Replica) isNewerThanSplitRLocked(split *roachpb.SplitTrigger) bool {
 type endCmds struct {
 	repl *Replica
 	lg   *spanlatch.Guard
+	// skipTimestampCache is set for the reads that don't need to update the
+	// timestamp cache.
+	skipTimestampCache bool
 }
 
 // move moves the endCmds into the return value, clearing and making
@@ -1179,7 +1198,7 @@ func (ec *endCmds) done(
 	// Update the timestamp cache if the request is not being re-evaluated. Each
 	// request is considered in turn; only those marked as affecting the cache are
 	// processed. Inconsistent reads are excluded.
-	if ba.ReadConsistency == roachpb.CONSISTENT {
+	if ba.ReadConsistency == roachpb.CONSISTENT && !ec.skipTimestampCache {
 		ec.repl.updateTimestampCache(ctx, ba, br, pErr)
 	}
 
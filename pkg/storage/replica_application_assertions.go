@@ -0,0 +1,154 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+)
+
+// applyAssertionsEnabled controls whether the replica state machine validates
+// the invariants of the replicated state as commands are applied to it. A
+// violation means that the replicas of a range may have diverged, so it is
+// reported as a nonDeterministicFailure, which crashes the node instead of
+// letting the divergence go unnoticed until the next consistency check.
+//
+// The assertions are too expensive for production, so they are enabled by
+// default only in race builds, which are only used for testing.
+var applyAssertionsEnabled = envutil.EnvOrDefaultBool(
+	"COCKROACH_ENABLE_APPLY_ASSERTIONS", util.RaceEnabled)
+
+// assertStaged checks the invariants of the batch's view of the replica state
+// after cmd has been staged in it.
+func (b *replicaAppBatch) assertStaged(cmd *replicatedCmd) error {
+	if err := checkMVCCStats(b.state.Stats); err != nil {
+		return err
+	}
+	if res := cmd.replicatedResult(); res.State != nil && res.State.Lease != nil {
+		// The lease in the batch's view of the state is only updated once the
+		// command is applied, so the new lease can be compared against it.
+		if err := checkLeaseTransition(b.state.Lease, res.State.Lease); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stateForAssertions returns a copy of the replica's in-memory state that
+// stays unchanged while further commands are applied.
+func (sm *replicaStateMachine) stateForAssertions() storagepb.ReplicaState {
+	sm.r.mu.RLock()
+	defer sm.r.mu.RUnlock()
+	state := sm.r.mu.state
+	// All pointer fields other than Stats are replaced rather than updated in
+	// place when the state changes.
+	stats := *state.Stats
+	state.Stats = &stats
+	return state
+}
+
+// checkMVCCStats returns an error if the stats can't be the result of applying
+// accurate deltas to accurate stats. Stats that contain estimates aren't
+// checked.
+func checkMVCCStats(ms *enginepb.MVCCStats) error {
+	if ms == nil || ms.ContainsEstimates != 0 {
+		return nil
+	}
+	for _, f := range []struct {
+		name string
+		val  int64
+	}{
+		{"LiveBytes", ms.LiveBytes},
+		{"LiveCount", ms.LiveCount},
+		{"KeyBytes", ms.KeyBytes},
+		{"KeyCount", ms.KeyCount},
+		{"ValBytes", ms.ValBytes},
+		{"ValCount", ms.ValCount},
+		{"IntentBytes", ms.IntentBytes},
+		{"IntentCount", ms.IntentCount},
+		{"SysBytes", ms.SysBytes},
+		{"SysCount", ms.SysCount},
+	} {
+		if f.val < 0 {
+			return makeNonDeterministicFailure("MVCC stats field %s became negative: %d", f.name, f.val)
+		}
+	}
+	if ms.LiveCount > ms.KeyCount {
+		return makeNonDeterministicFailure(
+			"MVCC stats contain more live keys than keys: %d > %d", ms.LiveCount, ms.KeyCount)
+	}
+	return nil
+}
+
+// checkLeaseTransition returns an error if next can't replace prev as the
+// lease of a range. Lease sequence numbers never regress, and a lease that
+// keeps the sequence number of its predecessor must be an extension of it.
+func checkLeaseTransition(prev, next *roachpb.Lease) error {
+	if prev == nil || next == nil || prev.Sequence == 0 {
+		// The lease sequence isn't known yet.
+		return nil
+	}
+	switch {
+	case next.Sequence < prev.Sequence:
+		return makeNonDeterministicFailure(
+			"lease sequence inversion from %d to %d", prev.Sequence, next.Sequence)
+	case next.Sequence == prev.Sequence && !prev.Equivalent(*next):
+		return makeNonDeterministicFailure(
+			"lease sequence %d reused for a different lease", next.Sequence)
+	}
+	return nil
+}
+
+// checkReplicaStateTransition returns an error if the replicated state of a
+// replica can't have moved from prev to next by applying commands. It is
+// checked around the application of the side effects of non-trivial commands,
+// which are the ones that change more than the applied indexes and the stats.
+func checkReplicaStateTransition(prev, next *storagepb.ReplicaState) error {
+	if next.RaftAppliedIndex < prev.RaftAppliedIndex {
+		return makeNonDeterministicFailure("raft applied index regressed from %d to %d",
+			prev.RaftAppliedIndex, next.RaftAppliedIndex)
+	}
+	if next.LeaseAppliedIndex < prev.LeaseAppliedIndex {
+		return makeNonDeterministicFailure("lease applied index regressed from %d to %d",
+			prev.LeaseAppliedIndex, next.LeaseAppliedIndex)
+	}
+	if prev.Desc != nil && next.Desc != nil {
+		if next.Desc.RangeID != prev.Desc.RangeID {
+			return makeNonDeterministicFailure("range descriptor changed range ID from r%d to r%d",
+				prev.Desc.RangeID, next.Desc.RangeID)
+		}
+		if next.Desc.GetGeneration() < prev.Desc.GetGeneration() {
+			return makeNonDeterministicFailure("range descriptor generation regressed from %d to %d",
+				prev.Desc.GetGeneration(), next.Desc.GetGeneration())
+		}
+	}
+	if err := checkLeaseTransition(prev.Lease, next.Lease); err != nil {
+		return err
+	}
+	if prev.GCThreshold != nil && next.GCThreshold != nil && next.GCThreshold.Less(*prev.GCThreshold) {
+		return makeNonDeterministicFailure("GC threshold regressed from %s to %s",
+			prev.GCThreshold, next.GCThreshold)
+	}
+	if prev.TruncatedState != nil && next.TruncatedState != nil {
+		if next.TruncatedState.Index < prev.TruncatedState.Index {
+			return makeNonDeterministicFailure("truncated state regressed from index %d to %d",
+				prev.TruncatedState.Index, next.TruncatedState.Index)
+		}
+		if next.TruncatedState.Index > next.RaftAppliedIndex {
+			return makeNonDeterministicFailure("log truncated to index %d beyond applied index %d",
+				next.TruncatedState.Index, next.RaftAppliedIndex)
+		}
+	}
+	return checkMVCCStats(next.Stats)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMVCCStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.NoError(t, checkMVCCStats(nil))
+	require.NoError(t, checkMVCCStats(&enginepb.MVCCStats{
+		LiveCount: 1, KeyCount: 2, ValCount: 3, LiveBytes: 10, KeyBytes: 20, ValBytes: 30,
+	}))
+	require.Error(t, checkMVCCStats(&enginepb.MVCCStats{KeyBytes: -1}))
+	require.Error(t, checkMVCCStats(&enginepb.MVCCStats{LiveCount: 2, KeyCount: 1, ValCount: 1}))
+	// Estimates aren't checked.
+	require.NoError(t, checkMVCCStats(&enginepb.MVCCStats{ContainsEstimates: 2, KeyBytes: -1}))
+}
+
+func TestCheckLeaseTransition(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	repl1 := roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 1}
+	repl2 := roachpb.ReplicaDescriptor{NodeID: 2, StoreID: 2, ReplicaID: 2}
+	prev := &roachpb.Lease{Replica: repl1, Start: hlc.Timestamp{WallTime: 1}, Epoch: 1, Sequence: 2}
+
+	// An extension keeps the sequence number.
+	require.NoError(t, checkLeaseTransition(prev, prev))
+	// A new lease gets a higher sequence number.
+	next := &roachpb.Lease{Replica: repl2, Start: hlc.Timestamp{WallTime: 2}, Epoch: 1, Sequence: 3}
+	require.NoError(t, checkLeaseTransition(prev, next))
+	// A new lease can't reuse the sequence number of its predecessor.
+	next.Sequence = 2
+	require.Error(t, checkLeaseTransition(prev, next))
+	// Sequence numbers never regress.
+	next.Sequence = 1
+	require.Error(t, checkLeaseTransition(prev, next))
+	// Leases without sequence numbers aren't checked.
+	require.NoError(t, checkLeaseTransition(&roachpb.Lease{Replica: repl1}, next))
+	require.NoError(t, checkLeaseTransition(nil, next))
+}
+
+func TestCheckReplicaStateTransition(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	makeState := func() *storagepb.ReplicaState {
+		return &storagepb.ReplicaState{
+			RaftAppliedIndex:  10,
+			LeaseAppliedIndex: 5,
+			Desc:              &roachpb.RangeDescriptor{RangeID: 1, Generation: proto.Int64(2)},
+			Lease:             &roachpb.Lease{Epoch: 1, Sequence: 3},
+			TruncatedState:    &roachpb.RaftTruncatedState{Index: 4},
+			GCThreshold:       &hlc.Timestamp{WallTime: 100},
+			Stats:             &enginepb.MVCCStats{},
+		}
+	}
+	prev := makeState()
+	require.NoError(t, checkReplicaStateTransition(prev, makeState()))
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(s *storagepb.ReplicaState)
+		ok     bool
+	}{
+		{"applied index moves forward", func(s *storagepb.ReplicaState) { s.RaftAppliedIndex = 11 }, true},
+		{"applied index regresses", func(s *storagepb.ReplicaState) { s.RaftAppliedIndex = 9 }, false},
+		{"lease applied index regresses", func(s *storagepb.ReplicaState) { s.LeaseAppliedIndex = 4 }, false},
+		{"generation moves forward", func(s *storagepb.ReplicaState) { s.Desc.Generation = proto.Int64(3) }, true},
+		{"generation regresses", func(s *storagepb.ReplicaState) { s.Desc.Generation = proto.Int64(1) }, false},
+		{"range ID changes", func(s *storagepb.ReplicaState) { s.Desc.RangeID = 2 }, false},
+		{"lease sequence regresses", func(s *storagepb.ReplicaState) { s.Lease.Sequence = 2 }, false},
+		{"GC threshold regresses", func(s *storagepb.ReplicaState) { s.GCThreshold.WallTime = 99 }, false},
+		{"log truncated", func(s *storagepb.ReplicaState) { s.TruncatedState.Index = 8 }, true},
+		{"truncation regresses", func(s *storagepb.ReplicaState) { s.TruncatedState.Index = 3 }, false},
+		{"truncation beyond applied index", func(s *storagepb.ReplicaState) { s.TruncatedState.Index = 11 }, false},
+		{"stats become negative", func(s *storagepb.ReplicaState) { s.Stats.SysBytes = -1 }, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			next := makeState()
+			tc.mutate(next)
+			err := checkReplicaStateTransition(prev, next)
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -183,7 +183,11 @@ func (d *decodedRaftEntry) decode(ctx context.Context, e *raftpb.Entry) error {
 
 func (d *decodedRaftEntry) decodeNormalEntry(e *raftpb.Entry) error {
 	var encodedCommand []byte
-	d.idKey, encodedCommand = DecodeRaftCommand(e.Data)
+	var err error
+	d.idKey, encodedCommand, err = DecodeRaftCommand(e.Data)
+	if err != nil {
+		return wrapWithNonDeterministicFailure(err, "while decoding entry")
+	}
 	// An empty command is used to unquiesce a range and wake the
 	// leader. Clear commandID so it's ignored for processing.
 	if len(encodedCommand) == 0 {
@@ -12,10 +12,12 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/storage/apply"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/opentracing/opentracing-go"
 	"go.etcd.io/etcd/raft/raftpb"
@@ -65,9 +67,17 @@ func (d *replicaDecoder) DecodeAndBind(ctx context.Context, ents []raftpb.Entry)
 func (d *replicaDecoder) decode(ctx context.Context, ents []raftpb.Entry) error {
 	for i := range ents {
 		ent := &ents[i]
+		compressed := ent.Type == raftpb.EntryNormal && sniffCompressedRaftCommand(ent.Data)
+		var start time.Time
+		if compressed {
+			start = timeutil.Now()
+		}
 		if err := d.cmdBuf.allocate().decode(ctx, ent); err != nil {
 			return err
 		}
+		if compressed {
+			d.r.store.metrics.RaftCommandDecompressionNanos.Inc(timeutil.Since(start).Nanoseconds())
+		}
 	}
 	return nil
 }
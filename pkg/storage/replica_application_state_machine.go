@@ -483,6 +483,11 @@ func (b *replicaAppBatch) Stage(cmdI apply.Command) (apply.CheckedCommand, error
 	// non-trivial ReplicatedState updates until later (without ever staging
 	// them in the batch) is sufficient.
 	b.stageTrivialReplicatedEvalResult(ctx, cmd)
+	if applyAssertionsEnabled {
+		if err := b.assertStaged(cmd); err != nil {
+			return nil, err
+		}
+	}
 	b.entries++
 	if len(cmd.ent.Data) == 0 {
 		b.emptyEntries++
@@ -561,6 +566,9 @@ func (b *replicaAppBatch) runPreApplyTriggersBeforeStagingWriteBatch(
 	if ops := cmd.raftCmd.LogicalOpLog; ops != nil {
 		b.r.populatePrevValsInLogicalOpLogRaftMuLocked(ctx, ops, b.batch)
 	}
+	// Remove the read cache entries that the command is about to make stale
+	// before its writes become visible in the engine.
+	b.r.readCache.invalidate(&cmd.raftCmd)
 	return nil
 }
 
@@ -827,6 +835,7 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	}
 	b.batch.Close()
 	b.batch = nil
+	r.readCache.finishApply()
 
 	// Update the replica's applied indexes and mvcc stats.
 	r.mu.Lock()
@@ -998,11 +1007,23 @@ func (sm *replicaStateMachine) ApplySideEffects(
 	// before notifying a potentially waiting client.
 	clearTrivialReplicatedEvalResultFields(cmd.replicatedResult())
 	if !cmd.IsTrivial() {
+		var prevState storagepb.ReplicaState
+		if applyAssertionsEnabled {
+			prevState = sm.stateForAssertions()
+		}
 		shouldAssert, isRemoved := sm.handleNonTrivialReplicatedEvalResult(ctx, *cmd.replicatedResult())
 
 		if isRemoved {
 			return nil, apply.ErrRemoved
 		}
+		if applyAssertionsEnabled {
+			// Validate the transition of the in-memory state caused by the side
+			// effects of the command.
+			nextState := sm.stateForAssertions()
+			if err := checkReplicaStateTransition(&prevState, &nextState); err != nil {
+				return nil, err
+			}
+		}
 		// NB: Perform state assertion before acknowledging the client.
 		// Some tests (TestRangeStatsInit) assumes that once the store has started
 		// and the first range has a lease that there will not be a later hard-state.
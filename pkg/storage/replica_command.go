@@ -1024,6 +1024,19 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	// Non-voters are added and removed on their own, before any voters. They
+	// don't participate in the quorum, so there is no reason to change them
+	// atomically with the voters.
+	if len(chgs.NonVoterAdditions()) > 0 || len(chgs.NonVoterRemovals()) > 0 {
+		desc, err = r.changeNonVoters(ctx, desc, priority, reason, details, chgs)
+		if err != nil {
+			return nil, err
+		}
+		if len(chgs.Additions()) == 0 && len(chgs.Removals()) == 0 {
+			return desc, nil
+		}
+	}
+
 	if adds := chgs.Additions(); len(adds) > 0 {
 		// Lock learner snapshots even before we run the ConfChange txn to add them
 		// to prevent a race with the raft snapshot queue trying to send it first.
@@ -1161,7 +1174,23 @@ func validateReplicationChanges(
 	for _, rDesc := range desc.Replicas().All() {
 		chg, ok := byNodeID[rDesc.NodeID]
 		delete(byNodeID, rDesc.NodeID)
-		if !ok || chg.ChangeType != roachpb.ADD_REPLICA {
+		if !ok {
+			continue
+		}
+		switch chg.ChangeType {
+		case roachpb.ADD_REPLICA, roachpb.ADD_NON_VOTER:
+			// Checked below.
+		case roachpb.REMOVE_REPLICA:
+			if rDesc.GetType() == roachpb.NON_VOTER {
+				return errors.Errorf("unable to remove non-voter %v as a voter in %s", chg.Target, desc)
+			}
+			continue
+		case roachpb.REMOVE_NON_VOTER:
+			if rDesc.GetType() != roachpb.NON_VOTER {
+				return errors.Errorf("unable to remove %v which is not a non-voter in %s", chg.Target, desc)
+			}
+			continue
+		default:
 			continue
 		}
 		// We're adding a replica that's already there. This isn't allowed, even
@@ -1179,6 +1208,10 @@ func validateReplicationChanges(
 			return errors.Errorf(
 				"unable to add replica %v which is already present as a learner in %s", chg.Target, desc)
 		}
+		if rDesc.GetType() == roachpb.NON_VOTER {
+			return errors.Errorf(
+				"unable to add replica %v which is already present as a non-voter in %s", chg.Target, desc)
+		}
 
 		// Otherwise, we already had a full voter replica. Can't add another to
 		// this store.
@@ -1187,7 +1220,7 @@ func validateReplicationChanges(
 
 	// Any removals left in the map now refer to nonexisting replicas, and we refuse them.
 	for _, chg := range byNodeID {
-		if chg.ChangeType != roachpb.REMOVE_REPLICA {
+		if chg.ChangeType != roachpb.REMOVE_REPLICA && chg.ChangeType != roachpb.REMOVE_NON_VOTER {
 			continue
 		}
 		return errors.Errorf("removing %v which is not in %s", chg.Target, desc)
@@ -1223,6 +1256,63 @@ func addLearnerReplicas(
 	return desc, nil
 }
 
+// changeNonVoters carries out the additions and removals of non-voters in the
+// given changes, ignoring any changes to voters. Non-voters are removed one at
+// a time, similar to learners in maybeLeaveAtomicChangeReplicasAndRemoveLearners.
+// They are also added one at a time, directly as NON_VOTER replicas (which raft
+// treats as learners), and then caught up via a snapshot. If sending that
+// snapshot fails, the non-voter is left in place and will be caught up by the
+// raft snapshot queue.
+func (r *Replica) changeNonVoters(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	priority SnapshotRequest_Priority,
+	reason storagepb.RangeLogEventReason,
+	details string,
+	chgs roachpb.ReplicationChanges,
+) (*roachpb.RangeDescriptor, error) {
+	for _, target := range chgs.NonVoterRemovals() {
+		var err error
+		desc, err = execChangeReplicasTxn(
+			ctx, r.store, desc, reason, details,
+			[]internalReplicationChange{{target: target, typ: internalChangeTypeRemove}},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	adds := chgs.NonVoterAdditions()
+	if len(adds) == 0 {
+		return desc, nil
+	}
+	// Like with learners, prevent the raft snapshot queue from racing with the
+	// snapshots sent below.
+	releaseSnapshotLockFn := r.lockLearnerSnapshot(ctx, adds)
+	defer releaseSnapshotLockFn()
+	for _, target := range adds {
+		var err error
+		desc, err = execChangeReplicasTxn(
+			ctx, r.store, desc, reason, details,
+			[]internalReplicationChange{{target: target, typ: internalChangeTypeAddNonVoter}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if fn := r.store.cfg.TestingKnobs.ReplicaSkipLearnerSnapshot; fn != nil && fn() {
+			continue
+		}
+		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
+		if !ok {
+			return nil, errors.Errorf("programming error: replica %v not found in %v", target, desc)
+		}
+		if err := r.sendSnapshot(ctx, rDesc, SnapshotRequest_LEARNER, priority); err != nil {
+			return nil, err
+		}
+	}
+	return desc, nil
+}
+
 // lockLearnerSnapshot stops the raft snapshot queue from sending snapshots to
 // the soon-to-be added learner replicas to prevent duplicate snapshots from
 // being sent. This lock is best effort because it times out and it is a node
@@ -1479,6 +1569,9 @@ const (
 	// voter with them), see:
 	// https://github.com/cockroachdb/cockroach/pull/40268
 	internalChangeTypeRemove
+	// internalChangeTypeAddNonVoter adds a non-voter. Non-voters are never
+	// promoted, so unlike voters they aren't added as learners first.
+	internalChangeTypeAddNonVoter
 )
 
 // internalReplicationChange is a replication target together with an internal
@@ -1539,6 +1632,9 @@ func prepareChangeReplicasTrigger(
 			case internalChangeTypeAddLearner:
 				added = append(added,
 					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.LEARNER))
+			case internalChangeTypeAddNonVoter:
+				added = append(added,
+					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.NON_VOTER))
 			case internalChangeTypePromoteLearner:
 				typ := roachpb.VOTER_FULL
 				if useJoint {
@@ -1555,12 +1651,12 @@ func prepareChangeReplicasTrigger(
 					return nil, errors.Errorf("target %s not found", chg.target)
 				}
 				prevTyp := rDesc.GetType()
-				if !useJoint || prevTyp == roachpb.LEARNER {
+				if !useJoint || prevTyp == roachpb.LEARNER || prevTyp == roachpb.NON_VOTER {
 					rDesc, _ = updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
 				} else if prevTyp != roachpb.VOTER_FULL {
 					// NB: prevTyp is already known to be VOTER_FULL because of
-					// !InAtomicReplicationChange() and the learner handling
-					// above. We check it anyway.
+					// !InAtomicReplicationChange() and the learner and non-voter
+					// handling above. We check it anyway.
 					return nil, errors.Errorf("cannot transition from %s to VOTER_OUTGOING", prevTyp)
 				} else {
 					rDesc, _, _ = updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_OUTGOING)
@@ -248,6 +248,12 @@ func (r *Replica) CheckConsistency(
 			}
 		}
 
+		if !consistencyCheckRepairStatsEnabled.Get(&r.store.ClusterSettings().SV) {
+			log.Warningf(ctx, "not resolving stats delta of %+v since stats repair is disabled",
+				results[0].Response.Delta)
+			return resp, nil
+		}
+
 		// We've found that there's something to correct; send an RecomputeStatsRequest. Note that this
 		// code runs only on the lease holder (at the time of initiating the computation), so this work
 		// isn't duplicated except in rare leaseholder change scenarios (and concurrent invocation of
@@ -262,8 +268,11 @@ func (r *Replica) CheckConsistency(
 		var b client.Batch
 		b.AddRawRequest(&req)
 
-		err := r.store.db.Run(ctx, &b)
-		return resp, roachpb.NewError(err)
+		if err := r.store.db.Run(ctx, &b); err != nil {
+			return resp, roachpb.NewError(err)
+		}
+		r.store.metrics.ConsistencyQueueStatsRepairs.Inc(1)
+		return resp, nil
 	}
 
 	if args.WithDiff {
@@ -12,11 +12,15 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	ctstorage "github.com/cockroachdb/cockroach/pkg/storage/closedts/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/spanlatch"
+	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -31,58 +35,141 @@ var FollowerReadsEnabled = settings.RegisterPublicBoolSetting(
 	true,
 )
 
+// LatchFreeReadsEnabled controls whether the reads below the closed timestamp
+// are served without waiting for latches or updating the timestamp cache.
+var LatchFreeReadsEnabled = settings.RegisterBoolSetting(
+	"kv.closed_timestamp.latch_free_reads.enabled",
+	"allow the consistent reads below the closed timestamp to skip latching and timestamp cache updates",
+	false,
+)
+
+// canServeLatchFreeRead returns whether the read-only batch can be evaluated
+// without waiting for latches and without updating the timestamp cache. This
+// is the case for the consistent, non-transactional or read-only transactional
+// Get and Scan requests whose timestamp (and uncertainty interval) is below
+// the closed timestamp of the range: writes can't be proposed at or below the
+// closed timestamp anymore, so the reads neither need to wait for the writes
+// in flight nor to prevent the future writes from rewriting their history.
+// Such reads, typically AS OF SYSTEM TIME queries, then don't interfere with
+// the foreground writes to the same keys.
+//
+// ClearRange, AddSSTable and GC requests still rewrite the history below the
+// closed timestamp, so the reads fall back to latching while one of them
+// overlaps them (see tryAcquireLatchFreeRead).
+func (r *Replica) canServeLatchFreeRead(ctx context.Context, ba *roachpb.BatchRequest) bool {
+	if ba.ReadConsistency != roachpb.CONSISTENT ||
+		!LatchFreeReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+		return false
+	}
+	if ba.Txn != nil && ba.Txn.IsWriting() {
+		// The transaction must read its own intents, which might be written
+		// concurrently by its other requests.
+		return false
+	}
+	for _, union := range ba.Requests {
+		switch union.GetInner().(type) {
+		case *roachpb.GetRequest, *roachpb.ScanRequest, *roachpb.ReverseScanRequest:
+		default:
+			// The other read-only requests, like RefreshRequests, rely on the
+			// timestamp cache or on the latches.
+			return false
+		}
+	}
+	ts := ba.Timestamp
+	if ba.Txn != nil {
+		ts.Forward(ba.Txn.MaxTimestamp)
+	}
+	return ts.LessEq(r.maxClosed(ctx))
+}
+
+// tryAcquireLatchFreeRead acquires the history latches (see
+// Replica.historyLatchMgr) of a read which can be served without latching
+// (see canServeLatchFreeRead), unless a request rewriting the MVCC history
+// overlaps it. These latches make the requests rewriting the history that come
+// after the read wait for it. It returns false if the read must acquire the
+// regular latches like any other request.
+func (r *Replica) tryAcquireLatchFreeRead(
+	ctx context.Context, spans *spanset.SpanSet,
+) (*spanlatch.Guard, bool) {
+	lg, ok := r.historyLatchMgr.TryAcquire(historySpans(spans, spanset.SpanReadOnly))
+	if !ok {
+		log.Event(ctx, "falling back to latching for read overlapping a request rewriting the MVCC history")
+	}
+	return lg, ok
+}
+
+// rewritesHistory returns whether the batch contains a request which rewrites
+// the MVCC history of its keys, even below the closed timestamp. Such batches
+// acquire history latches over their spans (see Replica.historyLatchMgr).
+func rewritesHistory(ba *roachpb.BatchRequest) bool {
+	for _, union := range ba.Requests {
+		switch union.GetInner().(type) {
+		case *roachpb.ClearRangeRequest, *roachpb.AddSSTableRequest, *roachpb.GCRequest:
+			return true
+		}
+	}
+	return false
+}
+
+// historySpans returns the global spans of the given access as non-MVCC spans,
+// which conflict at all timestamps, for the history latches.
+func historySpans(spans *spanset.SpanSet, access spanset.SpanAccess) *spanset.SpanSet {
+	var hs spanset.SpanSet
+	for _, span := range spans.GetSpans(access, spanset.SpanGlobal) {
+		hs.AddNonMVCC(access, span.Span)
+	}
+	return &hs
+}
+
+// followerReadRejection is the reason for which a replica could not serve a
+// read as a follower read.
+type followerReadRejection int
+
+const (
+	followerReadNotRejected followerReadRejection = iota
+	followerReadRejectedReplicaType
+	followerReadRejectedDisabled
+	followerReadRejectedNoLeaseholder
+	followerReadRejectedLeaseType
+	followerReadRejectedBatch
+	followerReadRejectedClosedTimestamp
+)
+
 // canServeFollowerRead tests, when a range lease could not be
 // acquired, whether the read only batch can be served as a follower
 // read despite the error.
 func (r *Replica) canServeFollowerRead(
 	ctx context.Context, ba *roachpb.BatchRequest, pErr *roachpb.Error,
 ) *roachpb.Error {
-	// There's no known reason that a non-VOTER_FULL replica couldn't serve follower
-	// reads (or RangeFeed), but as of the time of writing, these are expected
-	// to be short-lived, so it's not worth working out the edge-cases. Revisit if
-	// we add long-lived learners or feel that incoming/outgoing voters also need
-	// to be able to serve follower reads.
-	repDesc, err := r.GetReplicaDescriptor()
+	lErr, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError)
+	if !ok {
+		// We couldn't do anything with the error, propagate it.
+		return pErr
+	}
+	rejection, reason, err := r.checkFollowerRead(ctx, ba, lErr)
 	if err != nil {
 		return roachpb.NewError(err)
 	}
-	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL {
-		log.Eventf(ctx, "%s replicas cannot serve follower reads", typ)
-		return pErr
-	}
-
-	canServeFollowerRead := false
-	if lErr, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); ok &&
-		lErr.LeaseHolder != nil && lErr.Lease.Type() == roachpb.LeaseEpoch &&
-		ba.IsAllTransactional() && // followerreadsccl.batchCanBeEvaluatedOnFollower
-		(ba.Txn == nil || !ba.Txn.IsWriting()) && // followerreadsccl.txnCanPerformFollowerRead
-		FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
-
-		ts := ba.Timestamp
-		if ba.Txn != nil {
-			ts.Forward(ba.Txn.MaxTimestamp)
-		}
+	if rejection == followerReadRejectedClosedTimestamp {
+		// We can't actually serve the read based on the closed timestamp.
+		// Signal the clients that we want an update so that future requests can succeed.
+		r.store.cfg.ClosedTimestamp.Clients.Request(lErr.LeaseHolder.NodeID, r.RangeID)
 
-		canServeFollowerRead = ts.LessEq(r.maxClosed(ctx))
-		if !canServeFollowerRead {
-			// We can't actually serve the read based on the closed timestamp.
-			// Signal the clients that we want an update so that future requests can succeed.
-			r.store.cfg.ClosedTimestamp.Clients.Request(lErr.LeaseHolder.NodeID, r.RangeID)
-
-			if false {
-				// NB: this can't go behind V(x) because the log message created by the
-				// storage might be gigantic in real clusters, and we don't want to trip it
-				// using logspy.
-				log.Warningf(ctx, "can't serve follower read for %s at epo %d, storage is %s",
-					ba.Timestamp, lErr.Lease.Epoch,
-					r.store.cfg.ClosedTimestamp.Storage.(*ctstorage.MultiStorage).StringForNodes(lErr.LeaseHolder.NodeID),
-				)
-			}
+		if false {
+			// NB: this can't go behind V(x) because the log message created by the
+			// storage might be gigantic in real clusters, and we don't want to trip it
+			// using logspy.
+			log.Warningf(ctx, "can't serve follower read for %s at epo %d, storage is %s",
+				ba.Timestamp, lErr.Lease.Epoch,
+				r.store.cfg.ClosedTimestamp.Storage.(*ctstorage.MultiStorage).StringForNodes(lErr.LeaseHolder.NodeID),
+			)
 		}
 	}
 
-	if !canServeFollowerRead {
-		// We couldn't do anything with the error, propagate it.
+	if rejection != followerReadNotRejected {
+		// The read is redirected to the leaseholder.
+		log.Eventf(ctx, "can't serve follower read: %s", reason)
+		r.recordFollowerReadRejection(rejection, reason)
 		return pErr
 	}
 
@@ -94,6 +181,71 @@ func (r *Replica) canServeFollowerRead(
 	return nil
 }
 
+// checkFollowerRead returns whether the read only batch, which was refused by
+// the lease check with the given error, can be served as a follower read and,
+// if it can't, the reason why.
+func (r *Replica) checkFollowerRead(
+	ctx context.Context, ba *roachpb.BatchRequest, lErr *roachpb.NotLeaseHolderError,
+) (followerReadRejection, string, error) {
+	// There's no known reason that a non-VOTER_FULL replica couldn't serve follower
+	// reads (or RangeFeed), but as of the time of writing, these are expected
+	// to be short-lived, so it's not worth working out the edge-cases. The
+	// exception are non-voters, which are long-lived and exist precisely to serve
+	// follower reads. Revisit if we feel that learners or incoming/outgoing
+	// voters also need to be able to serve follower reads.
+	repDesc, err := r.GetReplicaDescriptor()
+	if err != nil {
+		return followerReadNotRejected, "", err
+	}
+	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
+		return followerReadRejectedReplicaType,
+			fmt.Sprintf("%s replicas cannot serve follower reads", typ), nil
+	}
+	if !FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+		return followerReadRejectedDisabled, "follower reads are disabled", nil
+	}
+	if lErr.LeaseHolder == nil {
+		return followerReadRejectedNoLeaseholder, "the leaseholder is unknown", nil
+	}
+	if lErr.Lease.Type() != roachpb.LeaseEpoch {
+		return followerReadRejectedLeaseType,
+			"expiration-based leases don't support follower reads", nil
+	}
+	if !ba.IsAllTransactional() { // followerreadsccl.batchCanBeEvaluatedOnFollower
+		return followerReadRejectedBatch, "the batch contains non-transactional requests", nil
+	}
+	if ba.Txn != nil && ba.Txn.IsWriting() { // followerreadsccl.txnCanPerformFollowerRead
+		return followerReadRejectedBatch, "the transaction has performed writes", nil
+	}
+
+	ts := ba.Timestamp
+	if ba.Txn != nil {
+		ts.Forward(ba.Txn.MaxTimestamp)
+	}
+	if maxClosed := r.maxClosed(ctx); !ts.LessEq(maxClosed) {
+		return followerReadRejectedClosedTimestamp, fmt.Sprintf(
+			"the read timestamp %s is above the closed timestamp %s (lagging by %s)",
+			ts, maxClosed, time.Duration(ts.WallTime-maxClosed.WallTime)), nil
+	}
+	return followerReadNotRejected, "", nil
+}
+
+// recordFollowerReadRejection updates the metrics and the range status of the
+// replica with the reason for which a read was redirected to the leaseholder.
+func (r *Replica) recordFollowerReadRejection(rejection followerReadRejection, reason string) {
+	m := r.store.metrics
+	m.FollowerReadsRedirectCount.Inc(1)
+	switch rejection {
+	case followerReadRejectedClosedTimestamp:
+		m.FollowerReadsClosedTSRedirects.Inc(1)
+	case followerReadRejectedLeaseType:
+		m.FollowerReadsLeaseTypeRedirects.Inc(1)
+	}
+	r.mu.Lock()
+	r.mu.followerReadRejection = reason
+	r.mu.Unlock()
+}
+
 // maxClosed returns the maximum closed timestamp for this range.
 // It is computed as the most recent of the known closed timestamp for the
 // current lease holder for this range as tracked by the closed timestamp
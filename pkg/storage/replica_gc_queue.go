@@ -146,6 +146,8 @@ func (rgcq *replicaGCQueue) shouldQueue(
 
 	// It is critical to think of the replica as suspect if it is a learner as
 	// it both shouldn't be a learner for long but will never become a candidate.
+	// Non-voters are long-lived, but will never become candidates either, so
+	// they are suspect as well.
 	// It is less critical to consider joint configuration members as suspect
 	// but in cases where a replica is removed but only ever hears about the
 	// command which sets it to VOTER_OUTGOING we would conservatively wait
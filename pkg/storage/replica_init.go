@@ -61,6 +61,7 @@ func newReplica(rangeID roachpb.RangeID, store *Store) *Replica {
 	// Pass nil for the localityOracle because we intentionally don't track the
 	// origin locality of write load.
 	r.writeStats = newReplicaStats(store.Clock(), nil)
+	r.readCache.init()
 
 	// Init rangeStr with the range ID.
 	r.rangeStr.store(0, &roachpb.RangeDescriptor{RangeID: rangeID})
@@ -96,6 +97,7 @@ func (r *Replica) initRaftMuLockedReplicaMuLocked(
 	}
 
 	r.latchMgr = spanlatch.Make(r.store.stopper, r.store.metrics.SlowLatchRequests)
+	r.historyLatchMgr = spanlatch.Make(r.store.stopper, r.store.metrics.SlowLatchRequests)
 	r.mu.proposals = map[storagebase.CmdIDKey]*ProposalData{}
 	r.mu.checksums = map[uuid.UUID]ReplicaChecksum{}
 	// Clear the internal raft group in case we're being reset. Since we're
@@ -299,5 +301,10 @@ func (r *Replica) setDesc(ctx context.Context, desc *roachpb.RangeDescriptor) {
 
 	r.rangeStr.store(r.mu.replicaID, desc)
 	r.connectionClass.set(rpc.ConnectionClassForKey(desc.StartKey))
+	if desc.IsInitialized() && r.connectionClass.get() == rpc.SystemClass {
+		// Process the Raft work of the system ranges on the priority shard of
+		// the scheduler so that it isn't delayed by the user ranges.
+		r.store.scheduler.SetPriorityID(desc.RangeID)
+	}
 	r.mu.state.Desc = desc
 }
@@ -749,7 +749,8 @@ func TestLearnerAndJointConfigFollowerRead(t *testing.T) {
 			Key: scratchDesc.StartKey.AsRawKey(), EndKey: scratchDesc.EndKey.AsRawKey(),
 		}})
 
-		_, repl := getFirstStoreReplica(t, tc.Server(1), scratchStartKey)
+		store, repl := getFirstStoreReplica(t, tc.Server(1), scratchStartKey)
+		redirects := store.Metrics().FollowerReadsRedirectCount.Count()
 		testutils.SucceedsSoon(t, func() error {
 			// Trace the Send call so we can verify that it hit the exact `learner
 			// replicas cannot serve follower reads` branch that we're trying to test.
@@ -767,6 +768,9 @@ func TestLearnerAndJointConfigFollowerRead(t *testing.T) {
 			}
 			return nil
 		})
+		// The redirect is reflected in the metrics and in the range status.
+		require.True(t, store.Metrics().FollowerReadsRedirectCount.Count() > redirects)
+		require.Contains(t, repl.State().FollowerReadRejection, `cannot serve follower reads`)
 	}
 
 	// Can't serve follower read from the LEARNER.
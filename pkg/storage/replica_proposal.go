@@ -358,6 +358,12 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+
+		// Record the lease changing stores in the range log. Extensions and
+		// re-acquisitions by the same store aren't interesting enough.
+		if prevLease.Replica.StoreID != 0 && prevLease.Replica.StoreID != newLease.Replica.StoreID {
+			r.store.logTransferLease(ctx, *r.Desc(), prevLease, newLease)
+		}
 	}
 
 	// Sanity check to make sure that the lease sequence is moving in the right
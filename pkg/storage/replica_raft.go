@@ -299,6 +299,11 @@ func (r *Replica) propose(ctx context.Context, p *ProposalData) (index int64, pE
 	if _, err := protoutil.MarshalToWithoutFuzzing(p.command, data[preLen:]); err != nil {
 		return 0, roachpb.NewError(err)
 	}
+	// Compress the body of large commands. The footer is appended to the
+	// compressed encoding, so it stays uncompressed.
+	if prefix && r.shouldCompressRaftCommand(p.ctx, cmdLen) {
+		data = r.compressProposalData(data, cmdLen)
+	}
 
 	// Too verbose even for verbose logging, so manually enable if you want to
 	// debug proposal sizes.
@@ -333,6 +338,23 @@ func (r *Replica) propose(ctx context.Context, p *ProposalData) (index int64, pE
 	return int64(maxLeaseIndex), nil
 }
 
+// compressProposalData compresses the body of the encoded command data if that
+// makes it smaller and records the compression metrics.
+func (r *Replica) compressProposalData(data []byte, cmdLen int) []byte {
+	m := r.store.metrics
+	start := timeutil.Now()
+	compressed, ok := compressRaftCommand(data, storagepb.MaxRaftCommandFooterSize())
+	m.RaftCommandCompressionNanos.Inc(timeutil.Since(start).Nanoseconds())
+	m.RaftCommandCompressionInputBytes.Inc(int64(cmdLen))
+	if !ok {
+		m.RaftCommandCompressionOutputBytes.Inc(int64(cmdLen))
+		return data
+	}
+	m.RaftCommandsCompressed.Inc(1)
+	m.RaftCommandCompressionOutputBytes.Inc(int64(len(compressed) - raftCommandPrefixLen))
+	return compressed
+}
+
 func (r *Replica) numPendingProposalsRLocked() int {
 	return len(r.mu.proposals) + r.mu.proposalBuf.Len()
 }
@@ -1015,7 +1037,7 @@ func (r *Replica) maybeCoalesceHeartbeat(
 		Term:          msg.Term,
 		Commit:        msg.Commit,
 		Quiesce:       quiesce,
-		ToIsLearner:   toReplica.GetType() == roachpb.LEARNER,
+		ToIsLearner:   toReplica.GetType() == roachpb.LEARNER || toReplica.GetType() == roachpb.NON_VOTER,
 	}
 	if log.V(4) {
 		log.Infof(ctx, "coalescing beat: %+v", beat)
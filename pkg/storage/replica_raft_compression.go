@@ -0,0 +1,114 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// raftCommandCompressionEnabled controls whether the payloads of large Raft
+// commands are compressed when they are proposed.
+var raftCommandCompressionEnabled = settings.RegisterBoolSetting(
+	"kv.raft.command.compression.enabled",
+	"if set, the payloads of Raft commands of at least kv.raft.command.compression.min_size are compressed",
+	false,
+)
+
+// raftCommandCompressionMinSize is the size of the payload of a Raft command
+// starting from which it is compressed. Small commands don't compress well and
+// are cheap to replicate anyway.
+var raftCommandCompressionMinSize = settings.RegisterByteSizeSetting(
+	"kv.raft.command.compression.min_size",
+	"minimum size of the payload of a Raft command for it to be compressed",
+	64<<10,
+)
+
+// The length of the compressed part of a compressed payload is encoded as a
+// fixed-width integer so that the payload can be compressed in place right
+// after it.
+const raftCommandCompressedLenLen = 4
+
+// shouldCompressRaftCommand returns whether the payload of a Raft command of
+// the given size should be compressed. Compression requires all of the nodes
+// to understand the compressed encoding, so it's gated on the cluster version.
+func (r *Replica) shouldCompressRaftCommand(ctx context.Context, payloadLen int) bool {
+	st := r.store.ClusterSettings()
+	return raftCommandCompressionEnabled.Get(&st.SV) &&
+		int64(payloadLen) >= raftCommandCompressionMinSize.Get(&st.SV) &&
+		cluster.Version.IsActive(ctx, st, cluster.VersionRaftCommandCompression)
+}
+
+// compressRaftCommand compresses the payload of the encoded Raft command data,
+// which must have the command prefix. The returned encoding has the compressed
+// bit set in its prefix and has room for extraCap more bytes, which are used
+// by the caller to append the command footer. A compressed payload has the
+// following format:
+//
+//   - the length n of the compressed bytes as a 4-byte big-endian integer,
+//   - n bytes of the payload compressed with snappy,
+//   - the uncompressed trailing bytes, which are the appended footer.
+//
+// The trailing bytes are appended to the decompressed bytes on decoding, which
+// results in the same payload as the one of an uncompressed command with the
+// same footer appended.
+//
+// ok is false and data is returned unchanged if the compression doesn't make
+// the command smaller.
+func compressRaftCommand(data []byte, extraCap int) (_ []byte, ok bool) {
+	payload := data[raftCommandPrefixLen:]
+	preLen := raftCommandPrefixLen + raftCommandCompressedLenLen
+	maxLen := preLen + snappy.MaxEncodedLen(len(payload))
+	b := make([]byte, maxLen, maxLen+extraCap)
+	compressed := snappy.Encode(b[preLen:], payload)
+	if preLen+len(compressed) >= len(data) {
+		return data, false
+	}
+	copy(b, data[:raftCommandPrefixLen])
+	b[0] |= raftCommandCompressedBit
+	binary.BigEndian.PutUint32(b[raftCommandPrefixLen:preLen], uint32(len(compressed)))
+	return b[:preLen+len(compressed)], true
+}
+
+// decompressRaftCommandPayload decompresses the payload of a command encoded by
+// compressRaftCommand (without the command prefix).
+func decompressRaftCommandPayload(payload []byte) ([]byte, error) {
+	if len(payload) < raftCommandCompressedLenLen {
+		return nil, errors.Errorf("compressed payload too short: %d bytes", len(payload))
+	}
+	n := binary.BigEndian.Uint32(payload)
+	payload = payload[raftCommandCompressedLenLen:]
+	if uint64(n) > uint64(len(payload)) {
+		return nil, errors.Errorf(
+			"compressed payload has %d bytes, expected at least %d", len(payload), n)
+	}
+	compressed, trailer := payload[:n], payload[n:]
+	decodedLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, decodedLen, decodedLen+len(trailer))
+	if _, err := snappy.Decode(res, compressed); err != nil {
+		return nil, err
+	}
+	return append(res, trailer...), nil
+}
+
+// sniffCompressedRaftCommand returns whether the payload of the encoded Raft
+// command data is compressed.
+func sniffCompressedRaftCommand(data []byte) bool {
+	return len(data) > 0 && data[0]&raftCommandCompressedBit != 0
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/kr/pretty"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// encodeRaftCommandWithFooter encodes the command like a proposal does,
+// compressing its body if compress is set, and appends the footer with the
+// given maximum lease index.
+func encodeRaftCommandWithFooter(
+	t *testing.T,
+	version raftCommandEncodingVersion,
+	cmdID storagebase.CmdIDKey,
+	cmd *storagepb.RaftCommand,
+	maxLeaseIndex uint64,
+	compress bool,
+) []byte {
+	t.Helper()
+	cmdLen := cmd.Size()
+	data := make([]byte, raftCommandPrefixLen+cmdLen, raftCommandPrefixLen+cmdLen+storagepb.MaxRaftCommandFooterSize())
+	encodeRaftCommandPrefix(data[:raftCommandPrefixLen], version, cmdID)
+	if _, err := protoutil.MarshalToWithoutFuzzing(cmd, data[raftCommandPrefixLen:]); err != nil {
+		t.Fatal(err)
+	}
+	if compress {
+		var ok bool
+		if data, ok = compressRaftCommand(data, storagepb.MaxRaftCommandFooterSize()); !ok {
+			t.Fatal("expected the command to be compressed")
+		}
+	}
+	f := &storagepb.RaftCommandFooter{MaxLeaseIndex: maxLeaseIndex}
+	footer, err := protoutil.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(data, footer...)
+}
+
+func TestRaftCommandCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cmdID := storagebase.CmdIDKey(strings.Repeat("x", raftCommandIDLen))
+	var cmd storagepb.RaftCommand
+	cmd.WriteBatch = &storagepb.WriteBatch{Data: bytes.Repeat([]byte("compressible"), 1<<16)}
+
+	for _, version := range []raftCommandEncodingVersion{raftVersionStandard, raftVersionSideloaded} {
+		uncompressed := encodeRaftCommandWithFooter(t, version, cmdID, &cmd, 7, false /* compress */)
+		compressed := encodeRaftCommandWithFooter(t, version, cmdID, &cmd, 7, true /* compress */)
+		if len(compressed) >= len(uncompressed) {
+			t.Fatalf("expected the compressed encoding to be smaller, got %d >= %d bytes",
+				len(compressed), len(uncompressed))
+		}
+		if sniffCompressedRaftCommand(uncompressed) || !sniffCompressedRaftCommand(compressed) {
+			t.Fatal("the compressed bit is set incorrectly")
+		}
+		if sniffSideloadedRaftCommand(compressed) != (version == raftVersionSideloaded) {
+			t.Fatalf("expected sniffSideloadedRaftCommand to return %t",
+				version == raftVersionSideloaded)
+		}
+		if id := decodeRaftCommandID(compressed); id != cmdID {
+			t.Fatalf("expected command ID %x, got %x", cmdID, id)
+		}
+
+		// Both encodings decode to the same command, including the footer.
+		var cmds [2]storagepb.RaftCommand
+		for i, data := range [][]byte{uncompressed, compressed} {
+			id, payload, err := DecodeRaftCommand(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != cmdID {
+				t.Fatalf("expected command ID %x, got %x", cmdID, id)
+			}
+			if err := protoutil.Unmarshal(payload, &cmds[i]); err != nil {
+				t.Fatal(err)
+			}
+			if cmds[i].MaxLeaseIndex != 7 {
+				t.Fatalf("expected max lease index 7, got %d", cmds[i].MaxLeaseIndex)
+			}
+		}
+		if !reflect.DeepEqual(cmds[0], cmds[1]) {
+			t.Fatalf("decoded commands differ: %s", pretty.Diff(cmds[0], cmds[1]))
+		}
+
+		// A truncated payload results in an error rather than a panic.
+		for _, l := range []int{raftCommandPrefixLen + 2, len(compressed) / 2} {
+			if _, _, err := DecodeRaftCommand(compressed[:l]); err == nil {
+				t.Fatalf("expected an error decoding %d bytes of the compressed command", l)
+			}
+		}
+	}
+
+	// Commands that don't compress are left alone.
+	var small storagepb.RaftCommand
+	small.WriteBatch = &storagepb.WriteBatch{Data: []byte("a")}
+	data := encodeRaftCommandWithFooter(t, raftVersionStandard, cmdID, &small, 0, false /* compress */)
+	if res, ok := compressRaftCommand(data, 0); ok || !bytes.Equal(res, data) {
+		t.Fatalf("expected the command to be left uncompressed, got %x", res)
+	}
+}
+
+// TestRaftSSTableSideloadingCompressed verifies that a compressed sideloaded
+// command is stripped into an uncompressed one.
+func TestRaftSSTableSideloadingCompressed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	cmdID := storagebase.CmdIDKey(strings.Repeat("x", raftCommandIDLen))
+	addSST := storagepb.ReplicatedEvalResult_AddSSTable{
+		Data: bytes.Repeat([]byte("sst"), 1<<16), CRC32: 0, // not checked
+	}
+	var cmd storagepb.RaftCommand
+	cmd.ReplicatedEvalResult.AddSSTable = &addSST
+	var ent raftpb.Entry
+	ent.Index, ent.Term = 13, 99
+	ent.Data = encodeRaftCommandWithFooter(t, raftVersionSideloaded, cmdID, &cmd, 0, true /* compress */)
+
+	sideloaded := mustNewInMemSideloadStorage(roachpb.RangeID(3), roachpb.ReplicaID(17), ".")
+	postEnts, size, err := maybeSideloadEntriesImpl(ctx, []raftpb.Entry{ent}, sideloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(addSST.Data)) {
+		t.Fatalf("expected %d sideloadedSize, but found %d", len(addSST.Data), size)
+	}
+	addSSTStripped := addSST
+	addSSTStripped.Data = nil
+	if exp := mkEnt(raftVersionSideloaded, 13, 99, &addSSTStripped); !reflect.DeepEqual(postEnts[0], exp) {
+		t.Fatalf("result differs from expected: %s", pretty.Diff(postEnts[0], exp))
+	}
+	payload, err := sideloaded.Get(ctx, 13, 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload, addSST.Data) {
+		t.Fatal("the sideloaded payload differs from the SSTable")
+	}
+}
@@ -752,6 +752,9 @@ func (r *Replica) applySnapshot(
 			case SnapshotRequest_PREEMPTIVE:
 				r.store.metrics.RangeSnapshotsPreemptiveApplied.Inc(1)
 			}
+			if !raft.IsEmptySnap(snap) {
+				r.store.logApplySnapshot(ctx, *s.Desc, snapType, snap.Metadata.Index)
+			}
 		}
 	}()
 
@@ -776,6 +779,11 @@ func (r *Replica) applySnapshot(
 		log.Fatalf(ctx, "found empty HardState for non-empty Snapshot %+v", snap)
 	}
 
+	// The snapshot replaces the replica's data without logical operations, so
+	// none of the read cache's entries can be trusted.
+	r.readCache.clear()
+	defer r.readCache.finishApply()
+
 	var stats struct {
 		// Time to process subsumed replicas.
 		subsumedReplicas time.Time
@@ -806,6 +814,11 @@ func (r *Replica) applySnapshot(
 		log.Infof(ctx, "applied %s snapshot [%s%s%sid=%s index=%d]",
 			snapType, totalLog, subsumedReplicasLog, ingestionLog,
 			inSnap.SnapUUID.Short(), snap.Metadata.Index)
+		if err == nil {
+			r.store.metrics.RangeSnapshotApplyLatency.RecordValue(now.Sub(start).Nanoseconds())
+			r.store.metrics.RangeSnapshotIngestLatency.RecordValue(
+				stats.ingestion.Sub(stats.subsumedReplicas).Nanoseconds())
+		}
 	}(timeutil.Now())
 
 	unreplicatedSSTFile := &engine.MemFile{}
@@ -1163,7 +1176,9 @@ type raftCommandEncodingVersion byte
 
 // Raft commands are encoded with a 1-byte version (currently 0 or 1), an 8-byte
 // ID, followed by the payload. This inflexible encoding is used so we can
-// efficiently parse the command id while processing the logs.
+// efficiently parse the command id while processing the logs. The version byte
+// also carries a flag indicating that the payload is compressed (see
+// compressRaftCommand).
 //
 // TODO(bdarnell): is this commandID still appropriate for our needs?
 const (
@@ -1185,6 +1200,10 @@ const (
 	// TODO(tschottdorf): predates v1.0 by a significant margin. Remove.
 	raftCommandNoSplitBit  = 1 << 7
 	raftCommandNoSplitMask = raftCommandNoSplitBit - 1
+	// The compressed bit is set in the first byte of the commands whose
+	// payload is compressed. It is only used once the cluster version
+	// VersionRaftCommandCompression is active.
+	raftCommandCompressedBit = 1 << 6
 )
 
 func encodeRaftCommand(
@@ -1210,14 +1229,30 @@ func encodeRaftCommandPrefix(
 }
 
 // DecodeRaftCommand splits a raftpb.Entry.Data into its commandID and
-// command portions. The caller is responsible for checking that the data
-// is not empty (which indicates a dummy entry generated by raft rather
-// than a real command). Usage is mostly internal to the storage package
-// but is exported for use by debugging tools.
-func DecodeRaftCommand(data []byte) (storagebase.CmdIDKey, []byte) {
-	v := raftCommandEncodingVersion(data[0] & raftCommandNoSplitMask)
+// command portions, decompressing the latter if necessary. The caller is
+// responsible for checking that the data is not empty (which indicates a dummy
+// entry generated by raft rather than a real command). Usage is mostly
+// internal to the storage package but is exported for use by debugging tools.
+func DecodeRaftCommand(data []byte) (storagebase.CmdIDKey, []byte, error) {
+	b := data[0] & raftCommandNoSplitMask
+	v := raftCommandEncodingVersion(b &^ raftCommandCompressedBit)
 	if v != raftVersionStandard && v != raftVersionSideloaded {
 		panic(fmt.Sprintf("unknown command encoding version %v", data[0]))
 	}
-	return storagebase.CmdIDKey(data[1 : 1+raftCommandIDLen]), data[1+raftCommandIDLen:]
+	cmdID := decodeRaftCommandID(data)
+	payload := data[raftCommandPrefixLen:]
+	if b&raftCommandCompressedBit == 0 {
+		return cmdID, payload, nil
+	}
+	payload, err := decompressRaftCommandPayload(payload)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "while decompressing command %x", cmdID)
+	}
+	return cmdID, payload, nil
+}
+
+// decodeRaftCommandID returns the commandID of the encoded Raft command. It is
+// cheaper than DecodeRaftCommand because the payload isn't decompressed.
+func decodeRaftCommandID(data []byte) storagebase.CmdIDKey {
+	return storagebase.CmdIDKey(data[1 : 1+raftCommandIDLen])
 }
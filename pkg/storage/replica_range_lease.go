@@ -852,128 +852,11 @@ func (r *Replica) redirectOnOrAcquireLease(
 
 	// Loop until the lease is held or the replica ascertains the actual
 	// lease holder. Returns also on context.Done() (timeout or cancellation).
-	var status storagepb.LeaseStatus
 	for attempt := 1; ; attempt++ {
 		timestamp := r.store.Clock().Now()
-		llHandle, pErr := func() (*leaseRequestHandle, *roachpb.Error) {
-			r.mu.Lock()
-			defer r.mu.Unlock()
-
-			status = r.leaseStatus(*r.mu.state.Lease, timestamp, r.mu.minLeaseProposedTS)
-			switch status.State {
-			case storagepb.LeaseState_ERROR:
-				// Lease state couldn't be determined.
-				log.VEventf(ctx, 2, "lease state couldn't be determined")
-				return nil, roachpb.NewError(
-					newNotLeaseHolderError(nil, r.store.StoreID(), r.mu.state.Desc))
-
-			case storagepb.LeaseState_VALID, storagepb.LeaseState_STASIS:
-				if !status.Lease.OwnedBy(r.store.StoreID()) {
-					_, stillMember := r.mu.state.Desc.GetReplicaDescriptor(status.Lease.Replica.StoreID)
-					if !stillMember {
-						// This would be the situation in which the lease holder gets removed when
-						// holding the lease, or in which a lease request erroneously gets accepted
-						// for a replica that is not in the replica set. Neither of the two can
-						// happen in normal usage since appropriate mechanisms have been added:
-						//
-						// 1. Only the lease holder (at the time) schedules removal of a replica,
-						// but the lease can change hands and so the situation in which a follower
-						// coordinates a replica removal of the (new) lease holder is possible (if
-						// unlikely) in practice. In this situation, the new lease holder would at
-						// some point be asked to propose the replica change's EndTxn to Raft. A
-						// check has been added that prevents proposals that amount to the removal
-						// of the proposer's (and hence lease holder's) Replica, preventing this
-						// scenario.
-						//
-						// 2. A lease is accepted for a Replica that has been removed. Without
-						// precautions, this could happen because lease requests are special in
-						// that they are the only command that is proposed on a follower (other
-						// commands may be proposed from followers, but not successfully so). For
-						// all proposals, processRaftCommand checks that their ProposalLease is
-						// compatible with the active lease for the log position. For commands
-						// proposed on the lease holder, the spanlatch manager then serializes
-						// everything. But lease requests get created on followers based on their
-						// local state and thus without being sequenced through latching. Thus
-						// a recently removed follower (unaware of its own removal) could submit
-						// a proposal for the lease (correctly using as a ProposerLease the last
-						// active lease), and would receive it given the up-to-date ProposerLease.
-						// Hence, an extra check is in order: processRaftCommand makes sure that
-						// lease requests for a replica not in the descriptor are bounced.
-						//
-						// However, this is possible if the `cockroach debug
-						// unsafe-remove-dead-replicas` command has been used, so
-						// this is just a logged error instead of a fatal
-						// assertion.
-						log.Errorf(ctx, "lease %s owned by replica %+v that no longer exists",
-							status.Lease, status.Lease.Replica)
-					}
-					// Otherwise, if the lease is currently held by another replica, redirect
-					// to the holder.
-					return nil, roachpb.NewError(
-						newNotLeaseHolderError(&status.Lease, r.store.StoreID(), r.mu.state.Desc))
-				}
-				// Check that we're not in the process of transferring the lease away.
-				// If we are transferring the lease away, we can't serve reads or
-				// propose Raft commands - see comments on TransferLease.
-				// TODO(andrei): If the lease is being transferred, consider returning a
-				// new error type so the client backs off until the transfer is
-				// completed.
-				repDesc, err := r.getReplicaDescriptorRLocked()
-				if err != nil {
-					return nil, roachpb.NewError(err)
-				}
-				if transferLease, ok := r.mu.pendingLeaseRequest.TransferInProgress(
-					repDesc.ReplicaID); ok {
-					return nil, roachpb.NewError(
-						newNotLeaseHolderError(&transferLease, r.store.StoreID(), r.mu.state.Desc))
-				}
-
-				// If the lease is in stasis, we can't serve requests until we've
-				// renewed the lease, so we return the handle to block on renewal.
-				// Otherwise, we don't need to wait for the extension and simply
-				// ignore the returned handle (whose channel is buffered) and continue.
-				if status.State == storagepb.LeaseState_STASIS {
-					return r.requestLeaseLocked(ctx, status), nil
-				}
-
-				// Extend the lease if this range uses expiration-based
-				// leases, the lease is in need of renewal, and there's not
-				// already an extension pending.
-				_, requestPending := r.mu.pendingLeaseRequest.RequestPending()
-				if !requestPending && r.requiresExpiringLeaseRLocked() {
-					renewal := status.Lease.Expiration.Add(-r.store.cfg.RangeLeaseRenewalDuration().Nanoseconds(), 0)
-					if renewal.LessEq(timestamp) {
-						if log.V(2) {
-							log.Infof(ctx, "extending lease %s at %s", status.Lease, timestamp)
-						}
-						// We had an active lease to begin with, but we want to trigger
-						// a lease extension. We explicitly ignore the returned handle
-						// as we won't block on it.
-						_ = r.requestLeaseLocked(ctx, status)
-					}
-				}
-
-			case storagepb.LeaseState_EXPIRED:
-				// No active lease: Request renewal if a renewal is not already pending.
-				log.VEventf(ctx, 2, "request range lease (attempt #%d)", attempt)
-				return r.requestLeaseLocked(ctx, status), nil
-
-			case storagepb.LeaseState_PROSCRIBED:
-				// Lease proposed timestamp is earlier than the min proposed
-				// timestamp limit this replica must observe. If this store
-				// owns the lease, re-request. Otherwise, redirect.
-				if status.Lease.OwnedBy(r.store.StoreID()) {
-					log.VEventf(ctx, 2, "request range lease (attempt #%d)", attempt)
-					return r.requestLeaseLocked(ctx, status), nil
-				}
-				// If lease is currently held by another, redirect to holder.
-				return nil, roachpb.NewError(
-					newNotLeaseHolderError(&status.Lease, r.store.StoreID(), r.mu.state.Desc))
-			}
-
-			// Return a nil handle to signal that we have a valid lease.
-			return nil, nil
-		}()
+		r.mu.Lock()
+		status, llHandle, pErr := r.leaseStatusOrRequestLocked(ctx, timestamp, attempt)
+		r.mu.Unlock()
 		if pErr != nil {
 			return storagepb.LeaseStatus{}, pErr
 		}
@@ -983,71 +866,290 @@ func (r *Replica) redirectOnOrAcquireLease(
 		}
 
 		// Wait for the range lease to finish, or the context to expire.
-		pErr = func() (pErr *roachpb.Error) {
-			slowTimer := timeutil.NewTimer()
-			defer slowTimer.Stop()
-			slowTimer.Reset(base.SlowRequestThreshold)
-			tBegin := timeutil.Now()
-			for {
-				select {
-				case pErr = <-llHandle.C():
-					if pErr != nil {
-						switch tErr := pErr.GetDetail().(type) {
-						case *roachpb.AmbiguousResultError:
-							// This can happen if the RequestLease command we sent has been
-							// applied locally through a snapshot: the RequestLeaseRequest
-							// cannot be reproposed so we get this ambiguity.
-							// We'll just loop around.
-							return nil
-						case *roachpb.LeaseRejectedError:
-							if tErr.Existing.OwnedBy(r.store.StoreID()) {
-								// The RequestLease command we sent was rejected because another
-								// lease was applied in the meantime, but we own that other
-								// lease. So, loop until the current node becomes aware that
-								// it's the leaseholder.
-								return nil
-							}
-
-							// Getting a LeaseRejectedError back means someone else got there
-							// first, or the lease request was somehow invalid due to a concurrent
-							// change. That concurrent change could have been that this replica was
-							// removed (see processRaftCommand), so check for that case before
-							// falling back to a NotLeaseHolderError.
-							var err error
-							if _, descErr := r.GetReplicaDescriptor(); descErr != nil {
-								err = descErr
-							} else if lease, _ := r.GetLease(); !r.IsLeaseValid(lease, r.store.Clock().Now()) {
-								err = newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc())
-							} else {
-								err = newNotLeaseHolderError(&lease, r.store.StoreID(), r.Desc())
-							}
-							pErr = roachpb.NewError(err)
-						}
-						return pErr
-					}
-					log.Eventf(ctx, "lease acquisition succeeded: %+v", status.Lease)
+		if pErr := r.waitForLeaseRequest(ctx, llHandle, status, attempt); pErr != nil {
+			return storagepb.LeaseStatus{}, pErr
+		}
+	}
+}
+
+// leaseStatusOrRequestLocked determines the status of the lease at the given
+// timestamp. If another replica holds the lease or the status couldn't be
+// determined, it returns a NotLeaseHolderError. If this replica has to acquire
+// or renew the lease before it can serve requests, it requests the lease and
+// returns the handle of the request to wait on. Otherwise, this replica owns a
+// valid lease and the returned handle is nil.
+//
+// Requires repl.mu is exclusively locked.
+func (r *Replica) leaseStatusOrRequestLocked(
+	ctx context.Context, timestamp hlc.Timestamp, attempt int,
+) (storagepb.LeaseStatus, *leaseRequestHandle, *roachpb.Error) {
+	status := r.leaseStatus(*r.mu.state.Lease, timestamp, r.mu.minLeaseProposedTS)
+	switch status.State {
+	case storagepb.LeaseState_ERROR:
+		// Lease state couldn't be determined.
+		log.VEventf(ctx, 2, "lease state couldn't be determined")
+		return storagepb.LeaseStatus{}, nil, roachpb.NewError(
+			newNotLeaseHolderError(nil, r.store.StoreID(), r.mu.state.Desc))
+
+	case storagepb.LeaseState_VALID, storagepb.LeaseState_STASIS:
+		if !status.Lease.OwnedBy(r.store.StoreID()) {
+			_, stillMember := r.mu.state.Desc.GetReplicaDescriptor(status.Lease.Replica.StoreID)
+			if !stillMember {
+				// This would be the situation in which the lease holder gets removed when
+				// holding the lease, or in which a lease request erroneously gets accepted
+				// for a replica that is not in the replica set. Neither of the two can
+				// happen in normal usage since appropriate mechanisms have been added:
+				//
+				// 1. Only the lease holder (at the time) schedules removal of a replica,
+				// but the lease can change hands and so the situation in which a follower
+				// coordinates a replica removal of the (new) lease holder is possible (if
+				// unlikely) in practice. In this situation, the new lease holder would at
+				// some point be asked to propose the replica change's EndTxn to Raft. A
+				// check has been added that prevents proposals that amount to the removal
+				// of the proposer's (and hence lease holder's) Replica, preventing this
+				// scenario.
+				//
+				// 2. A lease is accepted for a Replica that has been removed. Without
+				// precautions, this could happen because lease requests are special in
+				// that they are the only command that is proposed on a follower (other
+				// commands may be proposed from followers, but not successfully so). For
+				// all proposals, processRaftCommand checks that their ProposalLease is
+				// compatible with the active lease for the log position. For commands
+				// proposed on the lease holder, the spanlatch manager then serializes
+				// everything. But lease requests get created on followers based on their
+				// local state and thus without being sequenced through latching. Thus
+				// a recently removed follower (unaware of its own removal) could submit
+				// a proposal for the lease (correctly using as a ProposerLease the last
+				// active lease), and would receive it given the up-to-date ProposerLease.
+				// Hence, an extra check is in order: processRaftCommand makes sure that
+				// lease requests for a replica not in the descriptor are bounced.
+				//
+				// However, this is possible if the `cockroach debug
+				// unsafe-remove-dead-replicas` command has been used, so
+				// this is just a logged error instead of a fatal
+				// assertion.
+				log.Errorf(ctx, "lease %s owned by replica %+v that no longer exists",
+					status.Lease, status.Lease.Replica)
+			}
+			// Otherwise, if the lease is currently held by another replica, redirect
+			// to the holder.
+			return storagepb.LeaseStatus{}, nil, roachpb.NewError(
+				newNotLeaseHolderError(&status.Lease, r.store.StoreID(), r.mu.state.Desc))
+		}
+		// Check that we're not in the process of transferring the lease away.
+		// If we are transferring the lease away, we can't serve reads or
+		// propose Raft commands - see comments on TransferLease.
+		// TODO(andrei): If the lease is being transferred, consider returning a
+		// new error type so the client backs off until the transfer is
+		// completed.
+		repDesc, err := r.getReplicaDescriptorRLocked()
+		if err != nil {
+			return storagepb.LeaseStatus{}, nil, roachpb.NewError(err)
+		}
+		if transferLease, ok := r.mu.pendingLeaseRequest.TransferInProgress(
+			repDesc.ReplicaID); ok {
+			return storagepb.LeaseStatus{}, nil, roachpb.NewError(
+				newNotLeaseHolderError(&transferLease, r.store.StoreID(), r.mu.state.Desc))
+		}
+
+		// If the lease is in stasis, we can't serve requests until we've
+		// renewed the lease, so we return the handle to block on renewal.
+		// Otherwise, we don't need to wait for the extension and simply
+		// ignore the returned handle (whose channel is buffered) and continue.
+		if status.State == storagepb.LeaseState_STASIS {
+			return status, r.requestLeaseLocked(ctx, status), nil
+		}
+
+		// Extend the lease if this range uses expiration-based
+		// leases, the lease is in need of renewal, and there's not
+		// already an extension pending.
+		_, requestPending := r.mu.pendingLeaseRequest.RequestPending()
+		if !requestPending && r.requiresExpiringLeaseRLocked() {
+			renewal := status.Lease.Expiration.Add(-r.store.cfg.RangeLeaseRenewalDuration().Nanoseconds(), 0)
+			if renewal.LessEq(timestamp) {
+				if log.V(2) {
+					log.Infof(ctx, "extending lease %s at %s", status.Lease, timestamp)
+				}
+				// We had an active lease to begin with, but we want to trigger
+				// a lease extension. We explicitly ignore the returned handle
+				// as we won't block on it.
+				_ = r.requestLeaseLocked(ctx, status)
+			}
+		}
+
+	case storagepb.LeaseState_EXPIRED:
+		// No active lease: Request renewal if a renewal is not already pending.
+		log.VEventf(ctx, 2, "request range lease (attempt #%d)", attempt)
+		return status, r.requestLeaseLocked(ctx, status), nil
+
+	case storagepb.LeaseState_PROSCRIBED:
+		// Lease proposed timestamp is earlier than the min proposed
+		// timestamp limit this replica must observe. If this store
+		// owns the lease, re-request. Otherwise, redirect.
+		if status.Lease.OwnedBy(r.store.StoreID()) {
+			log.VEventf(ctx, 2, "request range lease (attempt #%d)", attempt)
+			return status, r.requestLeaseLocked(ctx, status), nil
+		}
+		// If lease is currently held by another, redirect to holder.
+		return storagepb.LeaseStatus{}, nil, roachpb.NewError(
+			newNotLeaseHolderError(&status.Lease, r.store.StoreID(), r.mu.state.Desc))
+	}
+
+	// Return a nil handle to signal that we have a valid lease.
+	return status, nil, nil
+}
+
+// waitForLeaseRequest waits for the lease request of the given handle to
+// finish, or for the context to expire. A nil error means that the caller
+// should check the lease again, because it was either acquired or found to be
+// owned by this replica.
+func (r *Replica) waitForLeaseRequest(
+	ctx context.Context, llHandle *leaseRequestHandle, status storagepb.LeaseStatus, attempt int,
+) (pErr *roachpb.Error) {
+	slowTimer := timeutil.NewTimer()
+	defer slowTimer.Stop()
+	slowTimer.Reset(base.SlowRequestThreshold)
+	tBegin := timeutil.Now()
+	for {
+		select {
+		case pErr = <-llHandle.C():
+			if pErr != nil {
+				switch tErr := pErr.GetDetail().(type) {
+				case *roachpb.AmbiguousResultError:
+					// This can happen if the RequestLease command we sent has been
+					// applied locally through a snapshot: the RequestLeaseRequest
+					// cannot be reproposed so we get this ambiguity.
+					// We'll just loop around.
 					return nil
-				case <-slowTimer.C:
-					slowTimer.Read = true
-					log.Warningf(ctx, "have been waiting %s attempting to acquire lease",
-						base.SlowRequestThreshold)
-					r.store.metrics.SlowLeaseRequests.Inc(1)
-					defer func() {
-						r.store.metrics.SlowLeaseRequests.Dec(1)
-						log.Infof(ctx, "slow lease acquisition finished after %s with error %v after %d attempts", timeutil.Since(tBegin), pErr, attempt)
-					}()
-				case <-ctx.Done():
-					llHandle.Cancel()
-					log.VErrEventf(ctx, 2, "lease acquisition failed: %s", ctx.Err())
-					return roachpb.NewError(newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc()))
-				case <-r.store.Stopper().ShouldStop():
-					llHandle.Cancel()
-					return roachpb.NewError(newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc()))
+				case *roachpb.LeaseRejectedError:
+					if tErr.Existing.OwnedBy(r.store.StoreID()) {
+						// The RequestLease command we sent was rejected because another
+						// lease was applied in the meantime, but we own that other
+						// lease. So, loop until the current node becomes aware that
+						// it's the leaseholder.
+						return nil
+					}
+
+					// Getting a LeaseRejectedError back means someone else got there
+					// first, or the lease request was somehow invalid due to a concurrent
+					// change. That concurrent change could have been that this replica was
+					// removed (see processRaftCommand), so check for that case before
+					// falling back to a NotLeaseHolderError.
+					var err error
+					if _, descErr := r.GetReplicaDescriptor(); descErr != nil {
+						err = descErr
+					} else if lease, _ := r.GetLease(); !r.IsLeaseValid(lease, r.store.Clock().Now()) {
+						err = newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc())
+					} else {
+						err = newNotLeaseHolderError(&lease, r.store.StoreID(), r.Desc())
+					}
+					pErr = roachpb.NewError(err)
 				}
+				return pErr
 			}
-		}()
-		if pErr != nil {
+			log.Eventf(ctx, "lease acquisition succeeded: %+v", status.Lease)
+			return nil
+		case <-slowTimer.C:
+			slowTimer.Read = true
+			log.Warningf(ctx, "have been waiting %s attempting to acquire lease",
+				base.SlowRequestThreshold)
+			r.store.metrics.SlowLeaseRequests.Inc(1)
+			defer func() {
+				r.store.metrics.SlowLeaseRequests.Dec(1)
+				log.Infof(ctx, "slow lease acquisition finished after %s with error %v after %d attempts", timeutil.Since(tBegin), pErr, attempt)
+			}()
+		case <-ctx.Done():
+			llHandle.Cancel()
+			log.VErrEventf(ctx, 2, "lease acquisition failed: %s", ctx.Err())
+			return roachpb.NewError(newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc()))
+		case <-r.store.Stopper().ShouldStop():
+			llHandle.Cancel()
+			return roachpb.NewError(newNotLeaseHolderError(nil, r.store.StoreID(), r.Desc()))
+		}
+	}
+}
+
+// writeLeaseCheck is the lease check of a write batch. It is started before the
+// batch acquires latches, so that it overlaps with the wait for the latches of
+// conflicting requests, and is completed once the latches are held.
+type writeLeaseCheck struct {
+	status storagepb.LeaseStatus
+	// llHandle is the handle of the lease request the check has to wait on, if
+	// this replica had to acquire or renew the lease.
+	llHandle *leaseRequestHandle
+}
+
+// beginWriteLeaseCheck starts the lease check of a write batch without
+// blocking. If another replica holds the lease, it returns a
+// NotLeaseHolderError right away, so that the batch is redirected without
+// waiting for latches. If this replica has to acquire or renew the lease, the
+// lease is requested right away, so that the lease acquisition overlaps with the
+// latch acquisition.
+//
+// The returned check must be completed with finishWriteLeaseCheck or released
+// with release.
+func (r *Replica) beginWriteLeaseCheck(ctx context.Context) (writeLeaseCheck, *roachpb.Error) {
+	if status, ok := r.leaseGoodToGo(ctx); ok {
+		return writeLeaseCheck{status: status}, nil
+	}
+	timestamp := r.store.Clock().Now()
+	r.mu.Lock()
+	status, llHandle, pErr := r.leaseStatusOrRequestLocked(ctx, timestamp, 1 /* attempt */)
+	r.mu.Unlock()
+	if pErr != nil {
+		return writeLeaseCheck{}, pErr
+	}
+	return writeLeaseCheck{status: status, llHandle: llHandle}, nil
+}
+
+// finishWriteLeaseCheck completes a lease check started by beginWriteLeaseCheck
+// once the batch holds its latches, and returns the status of the lease under
+// which the batch is evaluated.
+//
+// If this replica held a valid epoch-based lease when the check was started, it
+// is only verified that the lease hasn't changed and isn't being transferred
+// away since, which is cheaper than checking the lease again. This is enough
+// for writes, because their proposals are rejected below Raft if they weren't
+// proposed under the lease that is applied.
+func (r *Replica) finishWriteLeaseCheck(
+	ctx context.Context, c *writeLeaseCheck,
+) (storagepb.LeaseStatus, *roachpb.Error) {
+	if c.llHandle != nil {
+		llHandle := c.llHandle
+		c.llHandle = nil
+		if pErr := r.waitForLeaseRequest(ctx, llHandle, c.status, 1 /* attempt */); pErr != nil {
 			return storagepb.LeaseStatus{}, pErr
 		}
+		return r.redirectOnOrAcquireLease(ctx)
+	}
+	if c.status.Lease.Type() == roachpb.LeaseEpoch && r.leaseUnchanged(c.status.Lease) {
+		return c.status, nil
+	}
+	return r.redirectOnOrAcquireLease(ctx)
+}
+
+// leaseUnchanged returns whether the given lease, which is owned by this
+// replica, is still the current lease of the range and isn't being transferred
+// away.
+func (r *Replica) leaseUnchanged(lease roachpb.Lease) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.mu.state.Lease.Sequence != lease.Sequence {
+		return false
+	}
+	repDesc, err := r.getReplicaDescriptorRLocked()
+	if err != nil {
+		return false
+	}
+	_, transferring := r.mu.pendingLeaseRequest.TransferInProgress(repDesc.ReplicaID)
+	return !transferring
+}
+
+// release releases the lease request handle of a lease check that isn't
+// completed, e.g. because the batch failed to acquire its latches.
+func (c *writeLeaseCheck) release() {
+	if c.llHandle != nil {
+		c.llHandle.Cancel()
+		c.llHandle = nil
 	}
 }
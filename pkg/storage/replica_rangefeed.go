@@ -212,9 +212,13 @@ func (r *Replica) RangeFeed(
 			// workable. See #35122 for details.
 			// MinTimestampHint: args.Timestamp,
 		})
-		catchUpIter = iteratorWithCloser{
-			SimpleIterator: innerIter,
-			close:          iterSemRelease,
+		catchUpIter = &pacedIterator{
+			SimpleIterator: iteratorWithCloser{
+				SimpleIterator: innerIter,
+				close:          iterSemRelease,
+			},
+			ctx:     stream.Context(),
+			limiter: r.store.limiters.BulkReadRate,
 		}
 		// Responsibility for releasing the semaphore now passes to the iterator.
 		iterSemRelease = nil
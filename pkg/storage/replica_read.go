@@ -29,11 +29,18 @@ import (
 // iterator to evaluate the batch and then updates the timestamp cache to
 // reflect the key spans that it read.
 func (r *Replica) executeReadOnlyBatch(
-	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet, lg *spanlatch.Guard,
+	ctx context.Context,
+	ba *roachpb.BatchRequest,
+	spans *spanset.SpanSet,
+	lg *spanlatch.Guard,
+	_ writeLeaseCheck,
+	latchFree bool,
 ) (br *roachpb.BatchResponse, pErr *roachpb.Error) {
 	// Guarantee we release the provided latches. This is wrapped to delay pErr
-	// evaluation to its value when returning.
-	ec := endCmds{repl: r, lg: lg}
+	// evaluation to its value when returning. The latch-free reads are below
+	// the closed timestamp (see canServeLatchFreeRead), so they don't update
+	// the timestamp cache.
+	ec := endCmds{repl: r, lg: lg, skipTimestampCache: latchFree}
 	defer func() {
 		ec.done(ctx, ba, br, pErr)
 	}()
@@ -41,12 +48,14 @@ func (r *Replica) executeReadOnlyBatch(
 	// If the read is not inconsistent, the read requires the range lease or
 	// permission to serve via follower reads.
 	var status storagepb.LeaseStatus
+	var followerRead bool
 	if ba.ReadConsistency.RequiresReadLease() {
 		if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
 			if nErr := r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
 				return nil, nErr
 			}
 			r.store.metrics.FollowerReadsCount.Inc(1)
+			followerRead = true
 		}
 	}
 	r.limitTxnMaxTimestamp(ctx, ba, status)
@@ -72,6 +81,14 @@ func (r *Replica) executeReadOnlyBatch(
 		rw = spanset.NewReadWriterAt(rw, spans, ba.Timestamp)
 	}
 	defer rw.Close()
+	// Only the leaseholder serves reads from the read cache since a follower
+	// may not have applied all the writes that the cache needs to observe.
+	if !followerRead && r.canServeFromReadCache(ba) {
+		if br = r.serveFromReadCache(ctx, rw, ba); br != nil {
+			log.Event(ctx, "read completed from read cache")
+			return br, nil
+		}
+	}
 	br, result, pErr = evaluateBatch(ctx, storagebase.CmdIDKey(""), rw, rec, nil, ba, true /* readOnly */)
 	if err := r.handleReadOnlyLocalEvalResult(ctx, ba, result.Local); err != nil {
 		pErr = roachpb.NewError(err)
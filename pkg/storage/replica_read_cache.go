@@ -0,0 +1,269 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// systemReadCacheEnabled controls whether leaseholders serve point reads of
+// the system tables in systemReadCacheSpans from a per-replica cache.
+var systemReadCacheEnabled = settings.RegisterBoolSetting(
+	"kv.system_read_cache.enabled",
+	"if set, leaseholders cache the results of point reads of the descriptor and zones "+
+		"system tables; has no effect unless kv.rangefeed.enabled is also set",
+	false,
+)
+
+// systemReadCacheMaxEntries is the maximum number of keys that the read cache
+// of a single replica holds.
+const systemReadCacheMaxEntries = 1024
+
+// systemReadCacheSpans are the spans whose point reads are served from the
+// read cache. They are read repeatedly by every node (descriptor leases, zone
+// config lookups) but written rarely.
+var systemReadCacheSpans = []roachpb.Span{
+	makeTableSpan(keys.DescriptorTableID),
+	makeTableSpan(keys.ZonesTableID),
+}
+
+func makeTableSpan(tableID uint32) roachpb.Span {
+	prefix := roachpb.Key(keys.MakeTablePrefix(tableID))
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
+
+// replicaReadCache caches the newest committed version of keys that are read
+// often from a Replica. It is kept coherent with the replicated state using
+// the logical operations that are provided to the Replica's rangefeed: every
+// applied command removes the entries of the keys that it writes, and a
+// command whose effects can't be determined from its logical operations
+// removes all entries.
+//
+// An entry is only valid as long as there are no intents on its key, so adding
+// an intent removes all entries since the key of an intent isn't part of its
+// logical operation.
+type replicaReadCache struct {
+	mu struct {
+		syncutil.Mutex
+		entries *cache.UnorderedCache
+		// generation is incremented every time a batch of commands that was
+		// staged while pendingApply was set has been applied. Entries read from
+		// the engine are only added if the generation didn't change in the
+		// meantime, which guarantees that they don't reflect a stale state.
+		generation int64
+		// pendingApply is set while commands that invalidated entries are being
+		// applied to the engine. No entries are added in that window.
+		pendingApply bool
+	}
+}
+
+// readCacheEntry is the value of an entry of a replicaReadCache.
+type readCacheEntry struct {
+	// value is the newest version of the key, which is a value without
+	// RawBytes if the key is deleted, or nil if the key has never been
+	// written.
+	value *roachpb.Value
+}
+
+func (c *replicaReadCache) init() {
+	c.mu.entries = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > systemReadCacheMaxEntries
+		},
+	})
+}
+
+// generation returns the current generation of the cache, which has to be
+// passed to maybeAdd.
+func (c *replicaReadCache) generation() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.generation
+}
+
+// get returns the value of key at the given timestamp and whether it was found
+// in the cache. A cached key isn't found if its newest version is above the
+// timestamp, since older versions aren't cached.
+func (c *replicaReadCache) get(key roachpb.Key, ts hlc.Timestamp) (*roachpb.Value, bool) {
+	c.mu.Lock()
+	v, ok := c.mu.entries.Get(string(key))
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return readCacheEntryValueAt(v.(readCacheEntry), ts)
+}
+
+func readCacheEntryValueAt(e readCacheEntry, ts hlc.Timestamp) (*roachpb.Value, bool) {
+	if e.value == nil {
+		return nil, true
+	}
+	if ts.Less(e.value.Timestamp) {
+		return nil, false
+	}
+	if !e.value.IsPresent() {
+		// The key is deleted.
+		return nil, true
+	}
+	valCopy := *e.value
+	return &valCopy, true
+}
+
+// maybeAdd adds an entry for the key unless the cache has been invalidated
+// since the given generation.
+func (c *replicaReadCache) maybeAdd(generation int64, key roachpb.Key, e readCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.pendingApply || c.mu.generation != generation {
+		return
+	}
+	c.mu.entries.Add(string(key), e)
+}
+
+// invalidate removes the entries that may be affected by the command, which is
+// about to be applied. finishApply must be called once the command has been
+// applied.
+func (c *replicaReadCache) invalidate(raftCmd *storagepb.RaftCommand) {
+	res := &raftCmd.ReplicatedEvalResult
+	if raftCmd.WriteBatch == nil && res.AddSSTable == nil && res.Split == nil && res.Merge == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.pendingApply = true
+	if raftCmd.LogicalOpLog == nil || res.AddSSTable != nil || res.Split != nil || res.Merge != nil {
+		c.mu.entries.Clear()
+		return
+	}
+	for _, op := range raftCmd.LogicalOpLog.Ops {
+		switch t := op.GetValue().(type) {
+		case *enginepb.MVCCWriteValueOp:
+			c.mu.entries.Del(string(t.Key))
+		case *enginepb.MVCCCommitIntentOp:
+			c.mu.entries.Del(string(t.Key))
+		case *enginepb.MVCCWriteIntentOp, *enginepb.MVCCUpdateIntentOp:
+			c.mu.entries.Clear()
+			return
+		case *enginepb.MVCCAbortIntentOp, *enginepb.MVCCAbortTxnOp:
+			// Removing an intent doesn't change the committed versions.
+		}
+	}
+}
+
+// clear removes all entries. finishApply must be called once the change that
+// made the entries stale has been applied.
+func (c *replicaReadCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.pendingApply = true
+	c.mu.entries.Clear()
+}
+
+// finishApply is called after the commands passed to invalidate have been
+// applied to the engine.
+func (c *replicaReadCache) finishApply() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.pendingApply {
+		c.mu.pendingApply = false
+		c.mu.generation++
+	}
+}
+
+// canServeFromReadCache returns whether the batch, which is being evaluated
+// by the leaseholder, consists only of consistent point reads of the spans
+// whose reads are cached.
+func (r *Replica) canServeFromReadCache(ba *roachpb.BatchRequest) bool {
+	if !systemReadCacheEnabled.Get(&r.store.cfg.Settings.SV) ||
+		!RangefeedEnabled.Get(&r.store.cfg.Settings.SV) {
+		return false
+	}
+	if ba.ReadConsistency != roachpb.CONSISTENT {
+		return false
+	}
+	// Transactions that have written need to check the AbortSpan, see
+	// evaluateBatch.
+	if ba.Txn != nil && ba.Txn.IsWriting() {
+		return false
+	}
+	for _, union := range ba.Requests {
+		get, ok := union.GetInner().(*roachpb.GetRequest)
+		if !ok {
+			return false
+		}
+		var inSpans bool
+		for _, sp := range systemReadCacheSpans {
+			if sp.ContainsKey(get.Key) {
+				inSpans = true
+				break
+			}
+		}
+		if !inSpans {
+			return false
+		}
+	}
+	return true
+}
+
+// serveFromReadCache evaluates the batch, which must satisfy
+// canServeFromReadCache, using the read cache. Keys that aren't cached are
+// read from the reader and added to the cache. It returns nil if the batch
+// needs to be evaluated normally, for example because one of its keys has an
+// intent or a version that is too new to be served from the cache.
+func (r *Replica) serveFromReadCache(
+	ctx context.Context, reader engine.Reader, ba *roachpb.BatchRequest,
+) *roachpb.BatchResponse {
+	c := &r.readCache
+	generation := c.generation()
+	br := ba.CreateReply()
+	for i, union := range ba.Requests {
+		key := union.GetInner().(*roachpb.GetRequest).Key
+		val, ok := c.get(key, ba.Timestamp)
+		if ok {
+			r.store.metrics.SystemReadCacheHits.Inc(1)
+		} else {
+			r.store.metrics.SystemReadCacheMisses.Inc(1)
+			// Read the newest version of the key, regardless of the batch's
+			// timestamp, so that the entry can serve later reads as well.
+			newest, intent, err := engine.MVCCGet(ctx, reader, key, hlc.MaxTimestamp, engine.MVCCGetOptions{
+				Inconsistent: true,
+				Tombstones:   true,
+			})
+			if err != nil || intent != nil {
+				return nil
+			}
+			e := readCacheEntry{value: newest}
+			c.maybeAdd(generation, key, e)
+			if val, ok = readCacheEntryValueAt(e, ba.Timestamp); !ok {
+				return nil
+			}
+		}
+		br.Responses[i].GetInner().(*roachpb.GetResponse).Value = val
+	}
+	if ba.Txn != nil {
+		br.Txn = ba.Txn.Clone()
+		br.Timestamp.Forward(br.Txn.ReadTimestamp)
+	} else {
+		br.Timestamp.Forward(ba.Timestamp)
+	}
+	return br
+}
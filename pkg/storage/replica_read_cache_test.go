@@ -0,0 +1,123 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaReadCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	keyA := roachpb.Key(keys.MakeTablePrefix(keys.DescriptorTableID)).Next()
+	keyB := keyA.Next()
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	value := func(s string, wallTime int64) *roachpb.Value {
+		v := roachpb.MakeValueFromString(s)
+		v.Timestamp = ts(wallTime)
+		return &v
+	}
+	writeCmd := func(ops ...enginepb.MVCCLogicalOp) *storagepb.RaftCommand {
+		return &storagepb.RaftCommand{
+			WriteBatch:   &storagepb.WriteBatch{},
+			LogicalOpLog: &storagepb.LogicalOpLog{Ops: ops},
+		}
+	}
+	writeValueOp := func(key roachpb.Key) enginepb.MVCCLogicalOp {
+		var op enginepb.MVCCLogicalOp
+		op.MustSetValue(&enginepb.MVCCWriteValueOp{Key: key})
+		return op
+	}
+	requireCached := func(c *replicaReadCache, key roachpb.Key, at hlc.Timestamp, expected *roachpb.Value) {
+		t.Helper()
+		val, ok := c.get(key, at)
+		require.True(t, ok)
+		require.Equal(t, expected, val)
+	}
+	requireNotCached := func(c *replicaReadCache, key roachpb.Key, at hlc.Timestamp) {
+		t.Helper()
+		_, ok := c.get(key, at)
+		require.False(t, ok)
+	}
+
+	t.Run("values", func(t *testing.T) {
+		var c replicaReadCache
+		c.init()
+		gen := c.generation()
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a", 10)})
+		requireCached(&c, keyA, ts(10), value("a", 10))
+		requireCached(&c, keyA, ts(20), value("a", 10))
+		// Older versions aren't cached.
+		requireNotCached(&c, keyA, ts(5))
+
+		// A key that has never been written is absent at any timestamp.
+		c.maybeAdd(gen, keyB, readCacheEntry{})
+		requireCached(&c, keyB, ts(1), nil)
+
+		// A deleted key is absent at or above the timestamp of the deletion.
+		tombstone := &roachpb.Value{Timestamp: ts(10)}
+		c.maybeAdd(gen, keyB, readCacheEntry{value: tombstone})
+		requireCached(&c, keyB, ts(10), nil)
+		requireNotCached(&c, keyB, ts(9))
+	})
+
+	t.Run("invalidation", func(t *testing.T) {
+		var c replicaReadCache
+		c.init()
+		gen := c.generation()
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a", 10)})
+		c.maybeAdd(gen, keyB, readCacheEntry{value: value("b", 10)})
+
+		// Writing a value only invalidates its key.
+		c.invalidate(writeCmd(writeValueOp(keyA)))
+		requireNotCached(&c, keyA, ts(20))
+		requireCached(&c, keyB, ts(20), value("b", 10))
+		// No entries are added while the command is being applied, nor
+		// afterwards by readers that started before it was applied.
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a", 10)})
+		requireNotCached(&c, keyA, ts(20))
+		c.finishApply()
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a", 10)})
+		requireNotCached(&c, keyA, ts(20))
+		gen = c.generation()
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a2", 15)})
+		requireCached(&c, keyA, ts(20), value("a2", 15))
+
+		// Commands without writes don't invalidate anything.
+		c.invalidate(&storagepb.RaftCommand{})
+		c.finishApply()
+		require.Equal(t, gen, c.generation())
+		requireCached(&c, keyA, ts(20), value("a2", 15))
+
+		// An intent invalidates all the entries since its key is unknown.
+		var intentOp enginepb.MVCCLogicalOp
+		intentOp.MustSetValue(&enginepb.MVCCWriteIntentOp{Timestamp: ts(30)})
+		c.invalidate(writeCmd(intentOp))
+		c.finishApply()
+		requireNotCached(&c, keyA, ts(20))
+		requireNotCached(&c, keyB, ts(20))
+
+		// So does a write without logical operations.
+		gen = c.generation()
+		c.maybeAdd(gen, keyA, readCacheEntry{value: value("a", 10)})
+		c.invalidate(&storagepb.RaftCommand{WriteBatch: &storagepb.WriteBatch{}})
+		c.finishApply()
+		requireNotCached(&c, keyA, ts(20))
+	})
+}
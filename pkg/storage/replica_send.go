@@ -13,6 +13,7 @@ package storage
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -120,10 +122,13 @@ func (r *Replica) sendWithRangeID(
 
 // batchExecutionFn is a method on Replica that is able to execute a
 // BatchRequest. It is called with the batch, along with the span bounds that
-// the batch will operate over and a guard for the latches protecting the span
-// bounds. The function must ensure that the latch guard is eventually released.
+// the batch will operate over, a guard for the latches protecting the span
+// bounds, for writes, the lease check that was started before the latches
+// were acquired and, for reads, whether the read is served below the closed
+// timestamp without latching (see canServeLatchFreeRead). The function must
+// ensure that the latch guard is eventually released.
 type batchExecutionFn func(
-	*Replica, context.Context, *roachpb.BatchRequest, *spanset.SpanSet, *spanlatch.Guard,
+	*Replica, context.Context, *roachpb.BatchRequest, *spanset.SpanSet, *spanlatch.Guard, writeLeaseCheck, bool,
 ) (*roachpb.BatchResponse, *roachpb.Error)
 
 var _ batchExecutionFn = (*Replica).executeWriteBatch
@@ -138,7 +143,9 @@ var _ batchExecutionFn = (*Replica).executeReadOnlyBatch
 // conflicting requests. This permits the execution function to run without
 // concern of coordinating with logically conflicting operations, although it
 // still needs to worry about coordinating with non-conflicting operations when
-// accessing shared data structures.
+// accessing shared data structures. The lease check of writes is started before
+// the latches are acquired, so that it overlaps with the wait for latches, and
+// the key spans of the request are only declared once for all the retries.
 //
 // If the execution function hits a concurrency error like a WriteIntentError or
 // a TransactionPushError it will propagate the error back to this method, which
@@ -152,6 +159,21 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		return nil, roachpb.NewError(err)
 	}
 
+	// The reads below the closed timestamp don't conflict with any MVCC writes
+	// that can still be proposed, so they don't need to wait for latches.
+	canBeLatchFree := ba.IsReadOnly() && r.canServeLatchFreeRead(ctx, ba)
+
+	// The requests rewriting the MVCC history below the closed timestamp make
+	// the overlapping reads fall back to latching, and wait for the latch-free
+	// reads in flight.
+	if rewritesHistory(ba) {
+		hg, err := r.historyLatchMgr.Acquire(ctx, historySpans(spans, spanset.SpanReadWrite))
+		if err != nil {
+			return nil, roachpb.NewError(err)
+		}
+		defer r.historyLatchMgr.Release(hg)
+	}
+
 	// Handle load-based splitting.
 	r.recordBatchForLoadBasedSplitting(ctx, ba, spans)
 
@@ -171,6 +193,10 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		}
 	}()
 
+	// contentionEvents accumulates the conflicting transactions that the batch
+	// waited on across retries. They are returned to the client on success.
+	var contentionEvents []roachpb.ContentionEvent
+
 	// Try to execute command; exit retry loop on success.
 	for {
 		// Exit loop if context has been canceled or timed out.
@@ -187,24 +213,53 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 			return br, pErr
 		}
 
+		// Start the lease check of writes before acquiring latches, so that
+		// redirects don't wait for the latches of conflicting requests and lease
+		// acquisitions overlap with that wait.
+		var lc writeLeaseCheck
+		if !ba.IsReadOnly() && !ba.IsSingleSkipLeaseCheckRequest() {
+			if lc, pErr = r.beginWriteLeaseCheck(ctx); pErr != nil {
+				return nil, pErr
+			}
+		}
+
 		// Acquire latches to prevent overlapping commands from executing until
 		// this command completes.
 		// TODO(nvanbenschoten): Replace this with a call into the upcoming
 		// concurrency package when it is introduced.
-		lg, err := r.beginCmds(ctx, ba, spans)
-		if err != nil {
-			return nil, roachpb.NewError(err)
+		var lg, hg *spanlatch.Guard
+		var latchFree bool
+		if canBeLatchFree {
+			if hg, latchFree = r.tryAcquireLatchFreeRead(ctx, spans); latchFree {
+				log.Event(ctx, "serving latch-free read below the closed timestamp")
+				r.store.metrics.LatchFreeReadsCount.Inc(1)
+			}
+		}
+		if !latchFree {
+			if lg, err = r.beginCmds(ctx, ba, spans); err != nil {
+				lc.release()
+				return nil, roachpb.NewError(err)
+			}
 		}
 
-		br, pErr = fn(r, ctx, ba, spans, lg)
+		br, pErr = fn(r, ctx, ba, spans, lg, lc, latchFree)
+		if hg != nil {
+			r.historyLatchMgr.Release(hg)
+		}
 		switch t := pErr.GetDetail().(type) {
 		case nil:
 			// Success.
+			br.ContentionEvents = append(br.ContentionEvents, contentionEvents...)
 			return br, nil
 		case *roachpb.WriteIntentError:
+			// Note that the write intent error may be mutated while it is
+			// handled, so capture the intent holders beforehand.
+			intents := append([]roachpb.Intent(nil), t.Intents...)
+			start := timeutil.Now()
 			if cleanup, pErr = r.handleWriteIntentError(ctx, ba, pErr, t, cleanup); pErr != nil {
 				return nil, pErr
 			}
+			contentionEvents = appendContentionEvents(contentionEvents, intents, timeutil.Since(start))
 			// Retry...
 		case *roachpb.TransactionPushError:
 			if pErr = r.handleTransactionPushError(ctx, ba, pErr, t); pErr != nil {
@@ -228,6 +283,27 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 	}
 }
 
+// appendContentionEvents appends a ContentionEvent for each of the
+// transactions owning the given intents, which a request waited on for the
+// given duration.
+func appendContentionEvents(
+	events []roachpb.ContentionEvent, intents []roachpb.Intent, waited time.Duration,
+) []roachpb.ContentionEvent {
+	for i := range intents {
+		if i > 0 && intents[i].Txn.ID == intents[i-1].Txn.ID {
+			// Intents are usually grouped by transaction, so this avoids
+			// reporting the same wait for a transaction multiple times.
+			continue
+		}
+		events = append(events, roachpb.ContentionEvent{
+			Key:      intents[i].Span.Key,
+			TxnMeta:  intents[i].Txn,
+			Duration: waited,
+		})
+	}
+	return events
+}
+
 func (r *Replica) handleWriteIntentError(
 	ctx context.Context,
 	ba *roachpb.BatchRequest,
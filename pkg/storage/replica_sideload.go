@@ -89,7 +89,10 @@ func maybeSideloadEntriesImpl(
 			}
 
 			ent := &entriesToAppend[i]
-			cmdID, data := DecodeRaftCommand(ent.Data) // cheap
+			cmdID, data, err := DecodeRaftCommand(ent.Data) // cheap unless compressed
+			if err != nil {
+				return nil, 0, err
+			}
 
 			// Unmarshal the command into an object that we can mutate.
 			var strippedCmd storagepb.RaftCommand
@@ -130,8 +133,12 @@ func maybeSideloadEntriesImpl(
 	return entriesToAppend, sideloadedEntriesSize, nil
 }
 
+// sniffSideloadedRaftCommand returns whether the encoded Raft command data has
+// the sideloaded encoding version. The payload of such a command may be
+// compressed when it arrives on the wire, but the stripped commands stored in
+// the Raft log never are.
 func sniffSideloadedRaftCommand(data []byte) (sideloaded bool) {
-	return len(data) > 0 && data[0] == byte(raftVersionSideloaded)
+	return len(data) > 0 && data[0]&^raftCommandCompressedBit == byte(raftVersionSideloaded)
 }
 
 // maybeInlineSideloadedRaftCommand takes an entry and inspects it. If its
@@ -170,7 +177,10 @@ func maybeInlineSideloadedRaftCommand(
 
 	log.Event(ctx, "inlined entry not cached")
 	// Out of luck, for whatever reason the inlined proposal isn't in the cache.
-	cmdID, data := DecodeRaftCommand(ent.Data)
+	cmdID, data, err := DecodeRaftCommand(ent.Data)
+	if err != nil {
+		return nil, err
+	}
 
 	var command storagepb.RaftCommand
 	if err := protoutil.Unmarshal(data, &command); err != nil {
@@ -212,7 +222,10 @@ func assertSideloadedRaftCommandInlined(ctx context.Context, ent *raftpb.Entry)
 	}
 
 	var command storagepb.RaftCommand
-	_, data := DecodeRaftCommand(ent.Data)
+	_, data, err := DecodeRaftCommand(ent.Data)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
 	if err := protoutil.Unmarshal(data, &command); err != nil {
 		log.Fatal(ctx, err)
 	}
@@ -51,8 +51,14 @@ func entryEq(l, r raftpb.Entry) error {
 	if reflect.DeepEqual(l, r) {
 		return nil
 	}
-	_, lData := DecodeRaftCommand(l.Data)
-	_, rData := DecodeRaftCommand(r.Data)
+	_, lData, err := DecodeRaftCommand(l.Data)
+	if err != nil {
+		return errors.Wrap(err, "decoding LHS")
+	}
+	_, rData, err := DecodeRaftCommand(r.Data)
+	if err != nil {
+		return errors.Wrap(err, "decoding RHS")
+	}
 	var lc, rc storagepb.RaftCommand
 	if err := protoutil.Unmarshal(lData, &lc); err != nil {
 		return errors.Wrap(err, "unmarshalling LHS")
@@ -840,7 +846,10 @@ func TestRaftSSTableSideloadingSnapshot(t *testing.T) {
 				t.Fatal(err)
 			}
 			if sniffSideloadedRaftCommand(ent.Data) {
-				_, cmdBytes := DecodeRaftCommand(ent.Data)
+				_, cmdBytes, err := DecodeRaftCommand(ent.Data)
+				if err != nil {
+					t.Fatal(err)
+				}
 				if err := protoutil.Unmarshal(cmdBytes, &cmd); err != nil {
 					t.Fatal(err)
 				}
@@ -1076,6 +1076,95 @@ func TestReplicaNotLeaseHolderError(t *testing.T) {
 	}
 }
 
+// TestReplicaWriteRedirectedBeforeLatching verifies that a write on a replica
+// that doesn't hold the lease is redirected without waiting for the latches of
+// conflicting requests.
+func TestReplicaWriteRedirectedBeforeLatching(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	tc := testContext{manualClock: hlc.NewManualClock(123)}
+	cfg := TestStoreConfig(hlc.NewClock(tc.manualClock.UnixNano, time.Nanosecond))
+	cfg.TestingKnobs.DisableAutomaticLeaseRenewal = true
+	tc.StartWithStoreConfig(t, stopper, cfg)
+	ctx := context.Background()
+
+	secondReplica, err := tc.addBogusReplicaToRangeDesc(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.manualClock.Set(leaseExpiry(tc.repl))
+	now := tc.Clock().Now()
+	if err := sendLeaseRequest(tc.repl, &roachpb.Lease{
+		Start:      now,
+		Expiration: now.Add(10, 0).Clone(),
+		Replica:    secondReplica,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold a non-MVCC write latch on the key, which conflicts with the write.
+	key := roachpb.Key("a")
+	var spans spanset.SpanSet
+	spans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key})
+	lg, err := tc.repl.latchMgr.Acquire(ctx, &spans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tc.repl.latchMgr.Release(lg)
+
+	putDone := make(chan *roachpb.Error, 1)
+	go func() {
+		args := putArgs(key, []byte("value"))
+		_, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: now}, &args)
+		putDone <- pErr
+	}()
+	select {
+	case pErr := <-putDone:
+		if _, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); !ok {
+			t.Fatalf("expected not lease holder error, found %v", pErr)
+		}
+	case <-time.After(testutils.DefaultSucceedsSoonDuration):
+		t.Fatal("write waited for the latch before being redirected")
+	}
+}
+
+// BenchmarkReplicaWriteBatch measures the latency of single-key writes
+// through the replica's write path, on distinct keys and on a single key
+// contended by all the writers.
+func BenchmarkReplicaWriteBatch(b *testing.B) {
+	for _, contended := range []bool{false, true} {
+		b.Run(fmt.Sprintf("contended=%t", contended), func(b *testing.B) {
+			stopper := stop.NewStopper()
+			defer stopper.Stop(context.TODO())
+			tc := testContext{}
+			tc.Start(b, stopper)
+			ctx := context.Background()
+
+			var keyID int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				key := roachpb.Key("a")
+				for pb.Next() {
+					if !contended {
+						key = roachpb.Key(fmt.Sprintf("k%d", atomic.AddInt64(&keyID, 1)))
+					}
+					var ba roachpb.BatchRequest
+					ba.RangeID = 1
+					ba.Timestamp = tc.Clock().Now()
+					put := putArgs(key, []byte("value"))
+					ba.Add(&put)
+					if _, pErr := tc.repl.Send(ctx, ba); pErr != nil {
+						b.Fatal(pErr)
+					}
+				}
+			})
+		})
+	}
+}
+
 // TestReplicaLeaseCounters verifies leaseRequest metrics counters are updated
 // correctly after a lease request.
 func TestReplicaLeaseCounters(t *testing.T) {
@@ -2494,6 +2583,142 @@ func TestReplicaLatchingInconsistent(t *testing.T) {
 	}
 }
 
+// TestReplicaLatchFreeReadBelowClosedTimestamp verifies that the consistent
+// reads below the closed timestamp don't wait for the latches and don't update
+// the timestamp cache, while the reads above it do.
+func TestReplicaLatchFreeReadBelowClosedTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+	ctx := context.Background()
+	LatchFreeReadsEnabled.Override(&tc.store.cfg.Settings.SV, true)
+
+	key := roachpb.Key("a")
+	tc.manualClock.Set((3 * time.Second).Nanoseconds())
+	closedTS := hlc.Timestamp{WallTime: (2 * time.Second).Nanoseconds()}
+	tc.repl.mu.Lock()
+	tc.repl.mu.initialMaxClosed = closedTS
+	tc.repl.mu.Unlock()
+
+	// Hold the write latch of a write in flight above the closed timestamp.
+	var spans spanset.SpanSet
+	spans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key},
+		hlc.Timestamp{WallTime: (2500 * time.Millisecond).Nanoseconds()})
+	lg, err := tc.repl.latchMgr.Acquire(ctx, &spans)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readTS := hlc.Timestamp{WallTime: (1 * time.Second).Nanoseconds()}
+	args := getArgs(key)
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: readTS}, &args); pErr != nil {
+		t.Fatal(pErr)
+	}
+	if rTS, _ := tc.repl.store.tsCache.GetMax(key, nil /* end */); !rTS.Less(readTS) {
+		t.Errorf("expected the latch-free read not to update the timestamp cache, found %s", rTS)
+	}
+	if n := tc.store.metrics.LatchFreeReadsCount.Count(); n != 1 {
+		t.Errorf("expected 1 latch-free read, found %d", n)
+	}
+
+	// A read above the closed timestamp waits for the latch.
+	readDone := make(chan *roachpb.Error)
+	go func() {
+		args := getArgs(key)
+		_, pErr := tc.SendWrapped(&args)
+		readDone <- pErr
+	}()
+	select {
+	case pErr := <-readDone:
+		t.Fatalf("read above the closed timestamp should have been blocked, got %v", pErr)
+	case <-time.After(10 * time.Millisecond):
+	}
+	tc.repl.latchMgr.Release(lg)
+	if pErr := <-readDone; pErr != nil {
+		t.Fatal(pErr)
+	}
+	if rTS, _ := tc.repl.store.tsCache.GetMax(key, nil /* end */); rTS.Less(closedTS) {
+		t.Errorf("expected the read to update the timestamp cache, found %s", rTS)
+	}
+	if n := tc.store.metrics.LatchFreeReadsCount.Count(); n != 1 {
+		t.Errorf("expected 1 latch-free read, found %d", n)
+	}
+}
+
+// TestReplicaLatchFreeReadConcurrentClearRange verifies that the reads below
+// the closed timestamp fall back to latching while a ClearRange overlapping
+// them is in flight, since it rewrites the history below the closed timestamp.
+func TestReplicaLatchFreeReadConcurrentClearRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	blockingStart := make(chan struct{}, 1)
+	blockingDone := make(chan struct{})
+
+	tc := testContext{}
+	tsc := TestStoreConfig(nil)
+	tsc.TestingKnobs.EvalKnobs.TestingEvalFilter =
+		func(filterArgs storagebase.FilterArgs) *roachpb.Error {
+			if _, ok := filterArgs.Req.(*roachpb.ClearRangeRequest); ok {
+				select {
+				case blockingStart <- struct{}{}:
+				default:
+				}
+				<-blockingDone
+			}
+			return nil
+		}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.StartWithStoreConfig(t, stopper, tsc)
+	LatchFreeReadsEnabled.Override(&tc.store.cfg.Settings.SV, true)
+
+	tc.manualClock.Set((3 * time.Second).Nanoseconds())
+	closedTS := hlc.Timestamp{WallTime: (2 * time.Second).Nanoseconds()}
+	tc.repl.mu.Lock()
+	tc.repl.mu.initialMaxClosed = closedTS
+	tc.repl.mu.Unlock()
+
+	clearDone := make(chan *roachpb.Error)
+	go func() {
+		_, pErr := tc.SendWrapped(&roachpb.ClearRangeRequest{
+			RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("a"), EndKey: roachpb.Key("c")},
+		})
+		clearDone <- pErr
+	}()
+	// Wait for the ClearRange to acquire its latches.
+	<-blockingStart
+
+	// A read below the closed timestamp overlapping the ClearRange acquires
+	// latches and updates the timestamp cache.
+	key := roachpb.Key("b")
+	readTS := hlc.Timestamp{WallTime: (1 * time.Second).Nanoseconds()}
+	args := getArgs(key)
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: readTS}, &args); pErr != nil {
+		t.Fatal(pErr)
+	}
+	if n := tc.store.metrics.LatchFreeReadsCount.Count(); n != 0 {
+		t.Errorf("expected no latch-free read, found %d", n)
+	}
+	if rTS, _ := tc.repl.store.tsCache.GetMax(key, nil /* end */); rTS.Less(readTS) {
+		t.Errorf("expected the read to update the timestamp cache, found %s", rTS)
+	}
+
+	// A read which doesn't overlap the ClearRange is still latch-free.
+	args = getArgs(roachpb.Key("d"))
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: readTS}, &args); pErr != nil {
+		t.Fatal(pErr)
+	}
+	if n := tc.store.metrics.LatchFreeReadsCount.Count(); n != 1 {
+		t.Errorf("expected 1 latch-free read, found %d", n)
+	}
+
+	close(blockingDone)
+	if pErr := <-clearDone; pErr != nil {
+		t.Fatal(pErr)
+	}
+}
+
 // TestReplicaLatchingSelfOverlap verifies that self-overlapping batches are
 // allowed, and in particular do not deadlock by introducing latch dependencies
 // between the parts of the batch.
@@ -12225,6 +12450,18 @@ func TestPrepareChangeReplicasTrigger(t *testing.T) {
 			typOp{roachpb.VOTER_FULL, noop},
 			typOp{roachpb.LEARNER, internalChangeTypeRemove},
 		),
+		// Simple addition of non-voter.
+		mk(
+			"SIMPLE(l2) ADD_REPLICA[(n200,s200):2NON_VOTER]: after=[(n100,s100):1 (n200,s200):2NON_VOTER] next=3",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{none, internalChangeTypeAddNonVoter},
+		),
+		// Simple removal of non-voter.
+		mk(
+			"SIMPLE(r2) REMOVE_REPLICA[(n200,s200):2NON_VOTER]: after=[(n100,s100):1] next=3",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{roachpb.NON_VOTER, internalChangeTypeRemove},
+		),
 
 		// All other cases below need to go through joint quorums (though some
 		// of them only due to limitations in etcd/raft).
@@ -12253,6 +12490,14 @@ func TestPrepareChangeReplicasTrigger(t *testing.T) {
 			typOp{roachpb.VOTER_FULL, internalChangeTypeRemove},
 		),
 
+		// Removal of a voter and a non-voter. The non-voter is removed outright.
+		mk(
+			"ENTER_JOINT(r2 r3) REMOVE_REPLICA[(n200,s200):2VOTER_OUTGOING (n300,s300):3NON_VOTER]: after=[(n100,s100):1 (n200,s200):2VOTER_OUTGOING] next=4",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{roachpb.VOTER_FULL, internalChangeTypeRemove},
+			typOp{roachpb.NON_VOTER, internalChangeTypeRemove},
+		),
+
 		// Demoting two voters.
 		mk(
 			"ENTER_JOINT(r2 l2 r3 l3) REMOVE_REPLICA[(n200,s200):2VOTER_DEMOTING (n300,s300):3VOTER_DEMOTING]: after=[(n100,s100):1 (n200,s200):2VOTER_DEMOTING (n300,s300):3VOTER_DEMOTING] next=4",
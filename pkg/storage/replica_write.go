@@ -40,6 +40,9 @@ import (
 //
 // Concretely,
 //
+// - The range lease is checked, and requested if necessary. This is started
+//   by executeBatchWithConcurrencyRetries before the latches are acquired,
+//   and completed here once they are held.
 // - Latches for the keys affected by the command are acquired (i.e.
 //   tracked as in-flight mutations).
 // - In doing so, we wait until no overlapping mutations are in flight.
@@ -62,7 +65,12 @@ import (
 // as this method makes the assumption that it operates on a shallow copy (see
 // call to applyTimestampCache).
 func (r *Replica) executeWriteBatch(
-	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet, lg *spanlatch.Guard,
+	ctx context.Context,
+	ba *roachpb.BatchRequest,
+	spans *spanset.SpanSet,
+	lg *spanlatch.Guard,
+	lc writeLeaseCheck,
+	_ bool,
 ) (br *roachpb.BatchResponse, pErr *roachpb.Error) {
 	startTime := timeutil.Now()
 
@@ -84,7 +92,7 @@ func (r *Replica) executeWriteBatch(
 	} else {
 		// Other write commands require that this replica has the range
 		// lease.
-		if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
+		if status, pErr = r.finishWriteLeaseCheck(ctx, &lc); pErr != nil {
 			return nil, pErr
 		}
 		lease = status.Lease
@@ -253,6 +261,10 @@ func (r *Replica) evaluateWriteBatch(
 	ctx context.Context, idKey storagebase.CmdIDKey, ba *roachpb.BatchRequest, spans *spanset.SpanSet,
 ) (engine.Batch, enginepb.MVCCStats, *roachpb.BatchResponse, result.Result, *roachpb.Error) {
 	ms := enginepb.MVCCStats{}
+	// The evaluation context is shared by the 1PC attempt, the regular execution
+	// and all their server-side refreshes, since they all operate over the same
+	// declared spans.
+	rec := NewReplicaEvalContext(r, spans)
 
 	// If the transaction has been pushed but it can commit at the higher
 	// timestamp, let's evaluate the batch at the bumped timestamp. This will
@@ -278,7 +290,6 @@ func (r *Replica) evaluateWriteBatch(
 		strippedBa.DeferWriteTooOldError = false
 		strippedBa.Requests = ba.Requests[:len(ba.Requests)-1] // strip end txn req
 
-		rec := NewReplicaEvalContext(r, spans)
 		batch, br, res, pErr := r.evaluateWriteBatchWithServersideRefreshes(
 			ctx, idKey, rec, &ms, &strippedBa, spans,
 		)
@@ -384,7 +395,6 @@ func (r *Replica) evaluateWriteBatch(
 		}
 	}
 
-	rec := NewReplicaEvalContext(r, spans)
 	batch, br, res, pErr := r.evaluateWriteBatchWithServersideRefreshes(
 		ctx, idKey, rec, &ms, ba, spans)
 	return batch, ms, br, res, pErr
@@ -402,6 +412,9 @@ func (r *Replica) evaluateWriteBatchWithServersideRefreshes(
 	spans *spanset.SpanSet,
 ) (batch engine.Batch, br *roachpb.BatchResponse, res result.Result, pErr *roachpb.Error) {
 	goldenMS := *ms
+	// Whether to log the logical operations is determined once for all the
+	// retries, so that they all produce the same kind of batch.
+	rangefeedEnabled := RangefeedEnabled.Get(&r.store.cfg.Settings.SV)
 	for retries := 0; ; retries++ {
 		if retries > 0 {
 			log.VEventf(ctx, 2, "server-side retry of batch")
@@ -412,7 +425,7 @@ func (r *Replica) evaluateWriteBatchWithServersideRefreshes(
 		}
 		batch = r.store.Engine().NewBatch()
 		var opLogger *engine.OpLoggerBatch
-		if RangefeedEnabled.Get(&r.store.cfg.Settings.SV) {
+		if rangefeedEnabled {
 			// TODO(nvanbenschoten): once we get rid of the RangefeedEnabled
 			// cluster setting we'll need a way to turn this on when any
 			// replica (not just the leaseholder) wants it and off when no
@@ -337,6 +337,9 @@ func (rq *replicateQueue) processOneChange(
 
 	action, _ := rq.allocator.ComputeAction(ctx, zone, desc)
 	log.VEventf(ctx, 1, "next replica action: %s", action)
+	if decision := allocatorDecisionFromContext(ctx); decision != nil {
+		decision.Action = action
+	}
 
 	// For simplicity, the first thing the allocator does is remove learners, so
 	// it can do all of its reasoning about only voters. We do the same here so
@@ -959,6 +962,9 @@ func (rq *replicateQueue) findTargetAndTransferLease(
 
 	if opts.dryRun {
 		log.VEventf(ctx, 1, "transferring lease to s%d", target.StoreID)
+		if decision := allocatorDecisionFromContext(ctx); decision != nil {
+			decision.LeaseTarget = target.StoreID
+		}
 		return false, nil
 	}
 
@@ -995,6 +1001,11 @@ func (rq *replicateQueue) changeReplicas(
 	dryRun bool,
 ) error {
 	if dryRun {
+		if decision := allocatorDecisionFromContext(ctx); decision != nil {
+			decision.Changes = append(decision.Changes, chgs...)
+			decision.Reason = reason
+			decision.Details = details
+		}
 		return nil
 	}
 	if _, err := repl.ChangeReplicas(ctx, desc, priority, reason, details, chgs); err != nil {
@@ -1052,3 +1063,34 @@ func rangeRaftProgress(raftStatus *raft.Status, replicas []roachpb.ReplicaDescri
 	buf.WriteString("]")
 	return buf.String()
 }
+
+// AllocatorDecision describes what the replicate queue would do with a range,
+// as determined by an allocator dry run (see Store.AllocatorDryRun).
+type AllocatorDecision struct {
+	// Action is the action computed by the allocator for the range.
+	Action AllocatorAction
+	// Changes are the replication changes that the replicate queue would
+	// carry out, if any.
+	Changes roachpb.ReplicationChanges
+	// Reason is the reason for the changes.
+	Reason storagepb.RangeLogEventReason
+	// Details is the JSON description of the allocator's choice of the stores
+	// targeted by the changes, including the scores of the candidate stores.
+	Details string
+	// LeaseTarget is the store the lease would be transferred to, or 0 if it
+	// would stay where it is.
+	LeaseTarget roachpb.StoreID
+}
+
+type allocatorDecisionKey struct{}
+
+// withAllocatorDecision returns a context that makes a dry run of the
+// replicate queue record its decision in the given AllocatorDecision.
+func withAllocatorDecision(ctx context.Context, decision *AllocatorDecision) context.Context {
+	return context.WithValue(ctx, allocatorDecisionKey{}, decision)
+}
+
+func allocatorDecisionFromContext(ctx context.Context) *AllocatorDecision {
+	decision, _ := ctx.Value(allocatorDecisionKey{}).(*AllocatorDecision)
+	return decision
+}
@@ -15,10 +15,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 const rangeIDChunkSize = 1000
@@ -124,47 +127,120 @@ const (
 	stateRaftTick
 )
 
-type raftScheduler struct {
-	processor  raftProcessor
+// raftScheduleInfo is the scheduling state of a range ID in a shard.
+type raftScheduleInfo struct {
+	state raftScheduleState
+	// queuedAt is the time (in nanoseconds) at which the range ID was queued.
+	queuedAt int64
+}
+
+// raftSchedulerShard is a queue of range IDs processed by its own set of
+// workers. The workers of a shard only contend on the mutex of their shard.
+type raftSchedulerShard struct {
 	numWorkers int
+	// queueLength and latency are the metrics of the shard. They are shared by
+	// all the shards of the same priority and are nil if the scheduler has no
+	// metrics.
+	queueLength *metric.Gauge
+	latency     *metric.Histogram
 
 	mu struct {
 		syncutil.Mutex
 		cond    *sync.Cond
 		queue   rangeIDQueue
-		state   map[roachpb.RangeID]raftScheduleState
+		state   map[roachpb.RangeID]raftScheduleInfo
 		stopped bool
 	}
+}
+
+func newRaftSchedulerShard(
+	numWorkers int, queueLength *metric.Gauge, latency *metric.Histogram,
+) *raftSchedulerShard {
+	shard := &raftSchedulerShard{
+		numWorkers:  numWorkers,
+		queueLength: queueLength,
+		latency:     latency,
+	}
+	shard.mu.cond = sync.NewCond(&shard.mu.Mutex)
+	shard.mu.state = make(map[roachpb.RangeID]raftScheduleInfo)
+	return shard
+}
+
+// priorityID is the value stored for the range IDs in
+// raftScheduler.priorityIDs.
+var priorityID = unsafe.Pointer(&struct{}{})
+
+// raftScheduler processes the Raft work of the ranges of a store on a fixed
+// number of workers. The workers are split into shards, each of which has its
+// own queue of range IDs, and the ranges are assigned to the shards by range
+// ID. The system ranges (see SetPriorityID) are assigned to a dedicated
+// priority shard instead, so that the processing of the node liveness and meta
+// ranges is not delayed by a backlog of user ranges.
+type raftScheduler struct {
+	processor raftProcessor
+	// shards is the set of shards processing the ranges that don't have a
+	// priority.
+	shards        []*raftSchedulerShard
+	priorityShard *raftSchedulerShard
+	// priorityIDs is the set of range IDs which are processed by the priority
+	// shard.
+	priorityIDs syncutil.IntMap // map[roachpb.RangeID]priorityID
 
 	done sync.WaitGroup
 }
 
+// newRaftScheduler creates a scheduler with numWorkers workers split into
+// shards of at most shardSize workers, plus priorityWorkers workers for the
+// system ranges. metrics may be nil.
 func newRaftScheduler(
-	metrics *StoreMetrics, processor raftProcessor, numWorkers int,
+	metrics *StoreMetrics,
+	processor raftProcessor,
+	numWorkers int,
+	shardSize int,
+	priorityWorkers int,
 ) *raftScheduler {
 	s := &raftScheduler{
-		processor:  processor,
-		numWorkers: numWorkers,
+		processor: processor,
 	}
-	s.mu.cond = sync.NewCond(&s.mu.Mutex)
-	s.mu.state = make(map[roachpb.RangeID]raftScheduleState)
+	var queueLength, priorityQueueLength *metric.Gauge
+	var latency, priorityLatency *metric.Histogram
+	if metrics != nil {
+		queueLength, latency = metrics.RaftSchedulerQueueLength, metrics.RaftSchedulerLatency
+		priorityQueueLength = metrics.RaftSchedulerPriorityQueueLength
+		priorityLatency = metrics.RaftSchedulerPriorityLatency
+	}
+	for numWorkers > 0 {
+		shardWorkers := shardSize
+		if shardWorkers > numWorkers {
+			shardWorkers = numWorkers
+		}
+		s.shards = append(s.shards, newRaftSchedulerShard(shardWorkers, queueLength, latency))
+		numWorkers -= shardWorkers
+	}
+	s.priorityShard = newRaftSchedulerShard(priorityWorkers, priorityQueueLength, priorityLatency)
 	return s
 }
 
 func (s *raftScheduler) Start(ctx context.Context, stopper *stop.Stopper) {
+	shards := append([]*raftSchedulerShard{s.priorityShard}, s.shards...)
 	stopper.RunWorker(ctx, func(ctx context.Context) {
 		<-stopper.ShouldStop()
-		s.mu.Lock()
-		s.mu.stopped = true
-		s.mu.Unlock()
-		s.mu.cond.Broadcast()
+		for _, shard := range shards {
+			shard.mu.Lock()
+			shard.mu.stopped = true
+			shard.mu.Unlock()
+			shard.mu.cond.Broadcast()
+		}
 	})
 
-	s.done.Add(s.numWorkers)
-	for i := 0; i < s.numWorkers; i++ {
-		stopper.RunWorker(ctx, func(ctx context.Context) {
-			s.worker(ctx)
-		})
+	for _, shard := range shards {
+		s.done.Add(shard.numWorkers)
+		shard := shard // copy for goroutine
+		for i := 0; i < shard.numWorkers; i++ {
+			stopper.RunWorker(ctx, func(ctx context.Context) {
+				s.worker(ctx, shard)
+			})
+		}
 	}
 }
 
@@ -172,7 +248,30 @@ func (s *raftScheduler) Wait(context.Context) {
 	s.done.Wait()
 }
 
-func (s *raftScheduler) worker(ctx context.Context) {
+// SetPriorityID marks the range as a system range, whose Raft processing is
+// done by the workers of the priority shard. A range that is queued while it
+// is being moved to the priority shard may be processed concurrently by the
+// workers of both shards, which is fine since the processing of a range is
+// serialized by its raftMu.
+func (s *raftScheduler) SetPriorityID(id roachpb.RangeID) {
+	s.priorityIDs.Store(int64(id), priorityID)
+}
+
+// RemovePriorityID undoes SetPriorityID, which must be done when the range is
+// removed from the store.
+func (s *raftScheduler) RemovePriorityID(id roachpb.RangeID) {
+	s.priorityIDs.Delete(int64(id))
+}
+
+// shardFor returns the shard processing the given range ID.
+func (s *raftScheduler) shardFor(id roachpb.RangeID) *raftSchedulerShard {
+	if _, ok := s.priorityIDs.Load(int64(id)); ok {
+		return s.priorityShard
+	}
+	return s.shards[int(id)%len(s.shards)]
+}
+
+func (s *raftScheduler) worker(ctx context.Context, shard *raftSchedulerShard) {
 	defer s.done.Done()
 
 	// We use a sync.Cond for worker notification instead of a buffered
@@ -182,32 +281,38 @@ func (s *raftScheduler) worker(ctx context.Context) {
 	// signaling a sync.Cond is significantly faster than selecting and sending
 	// on a buffered channel.
 
-	s.mu.Lock()
+	shard.mu.Lock()
 	for {
 		var id roachpb.RangeID
 		for {
-			if s.mu.stopped {
-				s.mu.Unlock()
+			if shard.mu.stopped {
+				shard.mu.Unlock()
 				return
 			}
 			var ok bool
-			if id, ok = s.mu.queue.PopFront(); ok {
+			if id, ok = shard.mu.queue.PopFront(); ok {
 				break
 			}
-			s.mu.cond.Wait()
+			shard.mu.cond.Wait()
 		}
 
 		// Grab and clear the existing state for the range ID. Note that we leave
 		// the range ID marked as "queued" so that a concurrent Enqueue* will not
 		// queue the range ID again.
-		state := s.mu.state[id]
-		s.mu.state[id] = stateQueued
-		s.mu.Unlock()
+		info := shard.mu.state[id]
+		shard.mu.state[id] = raftScheduleInfo{state: stateQueued}
+		shard.mu.Unlock()
+
+		if shard.queueLength != nil {
+			shard.queueLength.Dec(1)
+			shard.latency.RecordValue(timeutil.Now().UnixNano() - info.queuedAt)
+		}
 
 		// Process requests first. This avoids a scenario where a tick and a
 		// "quiesce" message are processed in the same iteration and intervening
 		// raft ready processing unquiesces the replica because the tick triggers
 		// an election.
+		state := info.state
 		if state&stateRaftRequest != 0 {
 			// processRequestQueue returns true if the range should perform ready
 			// processing. Do not reorder this below the call to processReady.
@@ -226,79 +331,114 @@ func (s *raftScheduler) worker(ctx context.Context) {
 			s.processor.processReady(ctx, id)
 		}
 
-		s.mu.Lock()
-		state = s.mu.state[id]
-		if state == stateQueued {
+		shard.mu.Lock()
+		info = shard.mu.state[id]
+		if info.state == stateQueued {
 			// No further processing required by the range ID, clear it from the
 			// state map.
-			delete(s.mu.state, id)
+			delete(shard.mu.state, id)
 		} else {
 			// There was a concurrent call to one of the Enqueue* methods. Queue the
 			// range ID for further processing.
-			s.mu.queue.PushBack(id)
-			s.mu.cond.Signal()
+			info.queuedAt = timeutil.Now().UnixNano()
+			shard.mu.state[id] = info
+			shard.pushLocked(id)
+			shard.mu.cond.Signal()
 		}
 	}
 }
 
-func (s *raftScheduler) enqueue1Locked(addState raftScheduleState, id roachpb.RangeID) int {
-	prevState := s.mu.state[id]
-	if prevState&addState == addState {
+func (shard *raftSchedulerShard) pushLocked(id roachpb.RangeID) {
+	shard.mu.queue.PushBack(id)
+	if shard.queueLength != nil {
+		shard.queueLength.Inc(1)
+	}
+}
+
+func (shard *raftSchedulerShard) enqueue1Locked(
+	addState raftScheduleState, id roachpb.RangeID, now int64,
+) int {
+	prev := shard.mu.state[id]
+	if prev.state&addState == addState {
 		return 0
 	}
 	var queued int
-	newState := prevState | addState
-	if newState&stateQueued == 0 {
-		newState |= stateQueued
+	next := raftScheduleInfo{state: prev.state | addState, queuedAt: prev.queuedAt}
+	if next.state&stateQueued == 0 {
+		next.state |= stateQueued
+		next.queuedAt = now
 		queued++
-		s.mu.queue.PushBack(id)
+		shard.pushLocked(id)
 	}
-	s.mu.state[id] = newState
+	shard.mu.state[id] = next
 	return queued
 }
 
-func (s *raftScheduler) enqueue1(addState raftScheduleState, id roachpb.RangeID) int {
-	s.mu.Lock()
-	count := s.enqueue1Locked(addState, id)
-	s.mu.Unlock()
-	return count
+func (shard *raftSchedulerShard) signal(count int) {
+	if count >= shard.numWorkers {
+		shard.mu.cond.Broadcast()
+	} else {
+		for i := 0; i < count; i++ {
+			shard.mu.cond.Signal()
+		}
+	}
+}
+
+func (s *raftScheduler) enqueue1(addState raftScheduleState, id roachpb.RangeID) {
+	now := timeutil.Now().UnixNano()
+	shard := s.shardFor(id)
+	shard.mu.Lock()
+	count := shard.enqueue1Locked(addState, id, now)
+	shard.mu.Unlock()
+	shard.signal(count)
 }
 
-func (s *raftScheduler) enqueueN(addState raftScheduleState, ids ...roachpb.RangeID) int {
-	// Enqueue the ids in chunks to avoid hold raftScheduler.mu for too long.
+func (s *raftScheduler) enqueueN(addState raftScheduleState, ids ...roachpb.RangeID) {
+	// Enqueue the ids in chunks to avoid holding the mutex of a shard for too
+	// long.
 	const enqueueChunkSize = 128
 
-	var count int
-	s.mu.Lock()
-	for i, id := range ids {
-		count += s.enqueue1Locked(addState, id)
-		if (i+1)%enqueueChunkSize == 0 {
-			s.mu.Unlock()
-			s.mu.Lock()
+	// Group the ids by shard so that the mutex of each shard is acquired once
+	// per chunk. The priority shard is at index len(s.shards).
+	now := timeutil.Now().UnixNano()
+	shardIDs := make([][]roachpb.RangeID, len(s.shards)+1)
+	for _, id := range ids {
+		idx := len(s.shards)
+		if _, ok := s.priorityIDs.Load(int64(id)); !ok {
+			idx = int(id) % len(s.shards)
 		}
+		shardIDs[idx] = append(shardIDs[idx], id)
 	}
-	s.mu.Unlock()
-	return count
-}
-
-func (s *raftScheduler) signal(count int) {
-	if count >= s.numWorkers {
-		s.mu.cond.Broadcast()
-	} else {
-		for i := 0; i < count; i++ {
-			s.mu.cond.Signal()
+	for idx, ids := range shardIDs {
+		if len(ids) == 0 {
+			continue
+		}
+		shard := s.priorityShard
+		if idx < len(s.shards) {
+			shard = s.shards[idx]
+		}
+		var count int
+		shard.mu.Lock()
+		for i, id := range ids {
+			count += shard.enqueue1Locked(addState, id, now)
+			if (i+1)%enqueueChunkSize == 0 {
+				shard.mu.Unlock()
+				shard.mu.Lock()
+			}
 		}
+		shard.mu.Unlock()
+		shard.signal(count)
 	}
 }
 
 func (s *raftScheduler) EnqueueRaftReady(id roachpb.RangeID) {
-	s.signal(s.enqueue1(stateRaftReady, id))
+	s.enqueue1(stateRaftReady, id)
 }
 
 func (s *raftScheduler) EnqueueRaftRequest(id roachpb.RangeID) {
-	s.signal(s.enqueue1(stateRaftRequest, id))
+	s.enqueue1(stateRaftRequest, id)
 }
 
 func (s *raftScheduler) EnqueueRaftTick(ids ...roachpb.RangeID) {
-	s.signal(s.enqueueN(stateRaftTick, ids...))
+	s.enqueueN(stateRaftTick, ids...)
 }
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -190,7 +191,7 @@ func TestSchedulerLoop(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
 	p := newTestProcessor()
-	s := newRaftScheduler(nil, p, 1)
+	s := newRaftScheduler(nil, p, 1 /* numWorkers */, 1 /* shardSize */, 1 /* priorityWorkers */)
 	stopper := stop.NewStopper()
 	ctx := context.TODO()
 	defer stopper.Stop(ctx)
@@ -212,7 +213,7 @@ func TestSchedulerBuffering(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
 	p := newTestProcessor()
-	s := newRaftScheduler(nil, p, 1)
+	s := newRaftScheduler(nil, p, 1 /* numWorkers */, 1 /* shardSize */, 1 /* priorityWorkers */)
 	stopper := stop.NewStopper()
 	ctx := context.TODO()
 	defer stopper.Stop(ctx)
@@ -229,7 +230,7 @@ func TestSchedulerBuffering(t *testing.T) {
 	}
 
 	for _, c := range testCases {
-		s.signal(s.enqueueN(c.state, 1, 1, 1, 1, 1))
+		s.enqueueN(c.state, 1, 1, 1, 1, 1)
 
 		testutils.SucceedsSoon(t, func() error {
 			if s := p.String(); c.expected != s {
@@ -239,3 +240,80 @@ func TestSchedulerBuffering(t *testing.T) {
 		})
 	}
 }
+
+// blockingProcessor is a testProcessor whose ticks of a range block until
+// unblock is closed.
+type blockingProcessor struct {
+	*testProcessor
+	blocked roachpb.RangeID
+	unblock chan struct{}
+}
+
+func (p *blockingProcessor) processTick(ctx context.Context, rangeID roachpb.RangeID) bool {
+	if rangeID == p.blocked {
+		<-p.unblock
+	}
+	return p.testProcessor.processTick(ctx, rangeID)
+}
+
+// Verify that the system ranges are processed by the priority shard while all
+// the other workers are busy, and that the queues of each priority are
+// reflected in their own metrics.
+func TestSchedulerPriority(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	p := &blockingProcessor{testProcessor: newTestProcessor(), blocked: 2, unblock: make(chan struct{})}
+	metrics := newStoreMetrics(time.Minute)
+	s := newRaftScheduler(metrics, p, 1 /* numWorkers */, 1 /* shardSize */, 1 /* priorityWorkers */)
+	stopper := stop.NewStopper()
+	ctx := context.TODO()
+	defer stopper.Stop(ctx)
+	s.Start(ctx, stopper)
+	s.SetPriorityID(1)
+
+	// The only regular worker is blocked processing range 2, so range 3 stays
+	// queued while range 1 is processed by the priority worker.
+	s.EnqueueRaftTick(2)
+	testutils.SucceedsSoon(t, func() error {
+		if l := metrics.RaftSchedulerQueueLength.Value(); l != 0 {
+			return errors.Errorf("expected range 2 to be dequeued, but the queue length is %d", l)
+		}
+		return nil
+	})
+	s.EnqueueRaftTick(1, 3)
+	testutils.SucceedsSoon(t, func() error {
+		const expected = "ready=[] request=[] tick=[1:1]"
+		if s := p.String(); expected != s {
+			return errors.Errorf("expected %s, but got %s", expected, s)
+		}
+		return nil
+	})
+	if l := metrics.RaftSchedulerQueueLength.Value(); l != 1 {
+		t.Fatalf("expected 1 queued range, but found %d", l)
+	}
+	if l := metrics.RaftSchedulerPriorityQueueLength.Value(); l != 0 {
+		t.Fatalf("expected no queued system ranges, but found %d", l)
+	}
+
+	close(p.unblock)
+	testutils.SucceedsSoon(t, func() error {
+		const expected = "ready=[] request=[] tick=[1:1,2:1,3:1]"
+		if s := p.String(); expected != s {
+			return errors.Errorf("expected %s, but got %s", expected, s)
+		}
+		return nil
+	})
+	if l := metrics.RaftSchedulerQueueLength.Value(); l != 0 {
+		t.Fatalf("expected no queued ranges, but found %d", l)
+	}
+	if n := metrics.RaftSchedulerPriorityLatency.TotalCount(); n != 1 {
+		t.Fatalf("expected 1 dequeued system range, but found %d", n)
+	}
+
+	// Once the range is no longer a system range, it is processed by the
+	// regular workers.
+	s.RemovePriorityID(1)
+	if shard := s.shardFor(1); shard == s.priorityShard {
+		t.Fatalf("expected range 1 to be processed by a regular shard")
+	}
+}
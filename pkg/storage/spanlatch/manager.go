@@ -204,6 +204,26 @@ func (m *Manager) Acquire(ctx context.Context, spans *spanset.SpanSet) (*Guard,
 	return lg, nil
 }
 
+// TryAcquire acquires latches from the Manager for each of the provided spans
+// like Acquire, but only if none of the latches that it would have to wait on
+// is held. Otherwise, it acquires no latches and returns false. The acquired
+// latches are still waited on by the later conflicting acquisitions.
+//
+// It returns a Guard which must be provided to Release if successful.
+func (m *Manager) TryAcquire(spans *spanset.SpanSet) (*Guard, bool) {
+	lg := newGuard(spans)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snap := m.snapshotLocked(spans)
+	defer snap.close()
+	if m.conflicts(lg, snap) {
+		return nil, false
+	}
+	m.insertLocked(lg)
+	return lg, true
+}
+
 // sequence locks the manager, captures an immutable snapshot, inserts latches
 // for each of the specified spans into the manager's interval trees, and
 // unlocks the manager. The role of the method is to sequence latch acquisition
@@ -354,6 +374,52 @@ func (m *Manager) wait(ctx context.Context, lg *Guard, snap snapshot) error {
 	return nil
 }
 
+// conflicts returns whether any latch in the provided snapshot would have to be
+// waited on by wait.
+func (m *Manager) conflicts(lg *Guard, snap snapshot) bool {
+	for s := spanset.SpanScope(0); s < spanset.NumSpanScope; s++ {
+		tr := &snap.trees[s]
+		for a := spanset.SpanAccess(0); a < spanset.NumSpanAccess; a++ {
+			latches := lg.latches(s, a)
+			for i := range latches {
+				latch := &latches[i]
+				switch a {
+				case spanset.SpanReadOnly:
+					it := tr[spanset.SpanReadWrite].MakeIter()
+					if iterConflicts(&it, latch, ignoreLater) {
+						return true
+					}
+				case spanset.SpanReadWrite:
+					it := tr[spanset.SpanReadWrite].MakeIter()
+					if iterConflicts(&it, latch, ignoreNothing) {
+						return true
+					}
+					it = tr[spanset.SpanReadOnly].MakeIter()
+					if iterConflicts(&it, latch, ignoreEarlier) {
+						return true
+					}
+				default:
+					panic("unknown access")
+				}
+			}
+		}
+	}
+	return false
+}
+
+// iterConflicts returns whether the provided iterator finds a held latch that
+// overlaps with the search latch and which should not be ignored given their
+// timestamps and the supplied ignoreFn.
+func iterConflicts(it *iterator, wait *latch, ignore ignoreFn) bool {
+	for it.FirstOverlap(wait); it.Valid(); it.NextOverlap() {
+		held := it.Cur()
+		if !held.done.signaled() && !ignore(wait.ts, held.ts) {
+			return true
+		}
+	}
+	return false
+}
+
 // iterAndWait uses the provided iterator to wait on all latches that overlap
 // with the search latch and which should not be ignored given their timestamp
 // and the supplied ignoreFn.
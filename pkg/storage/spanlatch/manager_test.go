@@ -536,6 +536,33 @@ func TestLatchManagerContextCancellation(t *testing.T) {
 	testLatchSucceeds(t, lg3C)
 }
 
+func TestLatchManagerTryAcquire(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	var m Manager
+	ts1, ts2 := hlc.Timestamp{WallTime: 1}, hlc.Timestamp{WallTime: 2}
+
+	// A write at a later timestamp doesn't prevent a read.
+	lg1 := m.MustAcquire(spans("a", "", write, ts2))
+	lg2, ok := m.TryAcquire(spans("a", "", read, ts1))
+	require.True(t, ok)
+
+	// The acquired read latch is waited on by a later non-MVCC write.
+	lg3C := m.MustAcquireCh(spans("a", "c", write, zeroTS))
+	testLatchBlocks(t, lg3C)
+	m.Release(lg1)
+	testLatchBlocks(t, lg3C)
+	m.Release(lg2)
+	lg3 := testLatchSucceeds(t, lg3C)
+
+	// A non-MVCC write prevents the read, which doesn't acquire any latch.
+	_, ok = m.TryAcquire(spans("b", "", read, ts1))
+	require.False(t, ok)
+	m.Release(lg3)
+	lg4, ok := m.TryAcquire(spans("b", "", read, ts1))
+	require.True(t, ok)
+	m.Release(lg4)
+}
+
 func BenchmarkLatchManagerReadOnlyMix(b *testing.B) {
 	for _, size := range []int{1, 4, 16, 64, 128, 256} {
 		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
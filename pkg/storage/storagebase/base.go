@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 )
 
@@ -39,6 +40,17 @@ var MergeQueueEnabled = settings.RegisterBoolSetting(
 // larger than the heartbeat interval used by the coordinator.
 const TxnCleanupThreshold = time.Hour
 
+// ContentionListener is notified whenever a request had to wait on a
+// conflicting transaction before it could proceed.
+type ContentionListener interface {
+	// OnContention is called once a request issued by the pusher transaction
+	// (nil for non-transactional requests) is done waiting on the pushee
+	// transaction. It must not block.
+	OnContention(
+		ctx context.Context, pusher *enginepb.TxnMeta, pushee enginepb.TxnMeta, waited time.Duration,
+	)
+}
+
 // CmdIDKey is a Raft command id.
 type CmdIDKey string
 
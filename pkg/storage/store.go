@@ -99,6 +99,16 @@ const (
 var storeSchedulerConcurrency = envutil.EnvOrDefaultInt(
 	"COCKROACH_SCHEDULER_CONCURRENCY", 8*runtime.NumCPU())
 
+// storeSchedulerShardSize is the maximum number of workers of a shard of the
+// Raft scheduler.
+var storeSchedulerShardSize = envutil.EnvOrDefaultInt(
+	"COCKROACH_SCHEDULER_SHARD_SIZE", 16)
+
+// storeSchedulerPriorityConcurrency is the number of workers of the Raft
+// scheduler dedicated to the system ranges.
+var storeSchedulerPriorityConcurrency = envutil.EnvOrDefaultInt(
+	"COCKROACH_SCHEDULER_PRIORITY_CONCURRENCY", 2)
+
 var logSSTInfoTicks = envutil.EnvOrDefaultInt(
 	"COCKROACH_LOG_SST_INFO_TICKS_INTERVAL", 60,
 )
@@ -110,6 +120,14 @@ var bulkIOWriteLimit = settings.RegisterPublicByteSizeSetting(
 	1<<40,
 )
 
+// clearRangeRateLimit limits the rate at which a store removes the data of
+// dropped or truncated tables (and of reverted spans).
+var clearRangeRateLimit = settings.RegisterPublicByteSizeSetting(
+	"kv.bulk_io_write.clear_range_max_rate",
+	"the rate limit (bytes/sec) to use for removing data on behalf of ClearRange and RevertRange requests (0 disables)",
+	0,
+)
+
 // importRequestsLimit limits concurrent import requests.
 var importRequestsLimit = settings.RegisterPositiveIntSetting(
 	"kv.bulk_io_write.concurrent_import_requests",
@@ -144,6 +162,25 @@ var ExportRequestsLimit = settings.RegisterPositiveIntSetting(
 	3,
 )
 
+// bulkIOReadLimit is the maximum rate at which a store reads data on behalf of
+// ExportRequests and rangefeed catch-up scans. The actual rate also yields to
+// foreground traffic, see bulkIOReadTargetSchedulingLatency.
+var bulkIOReadLimit = settings.RegisterPublicByteSizeSetting(
+	"kv.bulk_io_read.max_rate",
+	"the rate limit (bytes/sec) to use for reads on behalf of export requests and rangefeed catch-up scans (0 disables)",
+	1<<40,
+)
+
+// bulkIOReadTargetSchedulingLatency is the goroutine scheduling latency above
+// which a store considers its foreground traffic to be starved and slows down
+// bulk reads.
+var bulkIOReadTargetSchedulingLatency = settings.RegisterNonNegativeDurationSetting(
+	"kv.bulk_io_read.target_scheduling_latency",
+	"the goroutine scheduling latency above which bulk reads are slowed down in favor of "+
+		"foreground traffic (0 disables)",
+	5*time.Millisecond,
+)
+
 // TestStoreConfig has some fields initialized with values relevant in tests.
 func TestStoreConfig(clock *hlc.Clock) StoreConfig {
 	if clock == nil {
@@ -508,8 +545,9 @@ type Store struct {
 	//   the "Locked" suffix.
 	//
 	// * Store.scheduler.mu: Protects the Raft scheduler internal
-	//   state. Callbacks from the scheduler are performed while not holding this
-	//   mutex in order to observe the above ordering constraints.
+	//   state (there is one such mutex per shard of the scheduler). Callbacks
+	//   from the scheduler are performed while not holding this mutex in order
+	//   to observe the above ordering constraints.
 	//
 	// Splits and merges deserve special consideration: they operate on two
 	// ranges. For splits, this might seem fine because the right-hand range is
@@ -670,6 +708,10 @@ type StoreConfig struct {
 	// which is non-zero.
 	IntentResolverTaskLimit int
 
+	// ContentionListener, if set, is notified by the intent resolver about the
+	// time requests spend waiting on conflicting transactions.
+	ContentionListener storagebase.ContentionListener
+
 	TestingKnobs StoreTestingKnobs
 
 	// concurrentSnapshotApplyLimit specifies the maximum number of empty
@@ -791,7 +833,10 @@ func NewStore(
 	s.replRankings = newReplicaRankings()
 
 	s.draining.Store(false)
-	s.scheduler = newRaftScheduler(s.metrics, s, storeSchedulerConcurrency)
+	s.scheduler = newRaftScheduler(
+		s.metrics, s, storeSchedulerConcurrency, storeSchedulerShardSize,
+		storeSchedulerPriorityConcurrency,
+	)
 
 	s.raftEntryCache = raftentry.NewCache(cfg.RaftEntryCacheSize)
 	s.metrics.registry.AddMetricStruct(s.raftEntryCache.Metrics())
@@ -880,6 +925,20 @@ func NewStore(
 	addSSTableRequestLimit.SetOnChange(&cfg.Settings.SV, func() {
 		s.limiters.ConcurrentAddSSTableRequests.SetLimit(int(addSSTableRequestLimit.Get(&cfg.Settings.SV)))
 	})
+	s.limiters.ClearRate = batcheval.NewClearRateLimiter(
+		clearRangeRateLimit.Get(&cfg.Settings.SV),
+		s.metrics.ClearRangeBytesQueued, s.metrics.ClearRangeBytesCleared,
+	)
+	clearRangeRateLimit.SetOnChange(&cfg.Settings.SV, func() {
+		s.limiters.ClearRate.SetLimit(clearRangeRateLimit.Get(&cfg.Settings.SV))
+	})
+	s.limiters.BulkReadRate = batcheval.NewBulkReadLimiter(
+		bulkIOReadLimit.Get(&cfg.Settings.SV),
+		s.metrics.BulkReadBytes, s.metrics.BulkReadRate,
+	)
+	bulkIOReadLimit.SetOnChange(&cfg.Settings.SV, func() {
+		s.limiters.BulkReadRate.SetMaxRate(bulkIOReadLimit.Get(&cfg.Settings.SV))
+	})
 	s.limiters.ConcurrentRangefeedIters = limit.MakeConcurrentRequestLimiter(
 		"rangefeedIterLimiter", int(concurrentRangefeedItersLimit.Get(&cfg.Settings.SV)),
 	)
@@ -1306,6 +1365,7 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		AmbientCtx:           s.cfg.AmbientCtx,
 		TestingKnobs:         s.cfg.TestingKnobs.IntentResolverKnobs,
 		RangeDescriptorCache: s.cfg.RangeDescriptorCache,
+		ContentionListener:   s.cfg.ContentionListener,
 	})
 	s.metrics.registry.AddMetricStruct(s.intentResolver.Metrics)
 
@@ -1430,6 +1490,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// Connect rangefeeds to closed timestamp updates.
 	s.startClosedTimestampRangefeedSubscriber(ctx)
 
+	// Pace bulk reads according to the load of the node.
+	s.startBulkReadPacer(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
@@ -2430,18 +2493,23 @@ func (s *Store) ComputeStatsForKeySpan(startKey, endKey roachpb.RKey) (StoreKeyS
 }
 
 // AllocatorDryRun runs the given replica through the allocator without actually
-// carrying out any changes, returning all trace messages collected along the way.
-// Intended to help power a debug endpoint.
-func (s *Store) AllocatorDryRun(ctx context.Context, repl *Replica) (tracing.Recording, error) {
+// carrying out any changes, returning the decision of the allocator as well as
+// all trace messages collected along the way. Intended to help power a debug
+// endpoint.
+func (s *Store) AllocatorDryRun(
+	ctx context.Context, repl *Replica,
+) (AllocatorDecision, tracing.Recording, error) {
 	ctx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "allocator dry run")
 	defer cancel()
+	var decision AllocatorDecision
+	ctx = withAllocatorDecision(ctx, &decision)
 	canTransferLease := func() bool { return true }
 	_, err := s.replicateQueue.processOneChange(
 		ctx, repl, canTransferLease, true /* dryRun */)
 	if err != nil {
 		log.Eventf(ctx, "error simulating allocator on replica %s: %s", repl, err)
 	}
-	return collect(), nil
+	return decision, collect(), nil
 }
 
 // ManuallyEnqueue runs the given replica through the requested queue,
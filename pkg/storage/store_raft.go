@@ -189,7 +189,7 @@ func (s *Store) withReplicaForRequest(
 		req.RangeID,
 		req.ToReplica.ReplicaID,
 		&req.FromReplica,
-		req.ToReplica.GetType() == roachpb.LEARNER,
+		req.ToReplica.GetType() == roachpb.LEARNER || req.ToReplica.GetType() == roachpb.NON_VOTER,
 	)
 	if err != nil {
 		return roachpb.NewError(err)
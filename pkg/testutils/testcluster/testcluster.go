@@ -155,6 +155,9 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 	if len(args.ServerArgs.Locality.Tiers) > 0 {
 		noLocalities = false
 	}
+	if args.RegionLatencies != nil && noLocalities {
+		t.Fatal("simulating latency between regions requires the localities of the nodes")
+	}
 
 	// Pre-bind a listener for node zero so the kernel can go ahead and
 	// assign its address for use in the other nodes' join flags.
@@ -164,6 +167,20 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 		t.Fatal(err)
 	}
 
+	// If latency between regions is simulated, every node needs to know the
+	// addresses of all the others before it starts, so we pre-bind listeners
+	// for all of them.
+	listeners := []net.Listener{firstListener}
+	if args.RegionLatencies != nil {
+		for i := 1; i < nodes; i++ {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			listeners = append(listeners, ln)
+		}
+	}
+
 	var errCh chan error
 	if args.ParallelStart {
 		errCh = make(chan error, nodes)
@@ -171,12 +188,7 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 
 	disableLBS := false
 	for i := 0; i < nodes; i++ {
-		var serverArgs base.TestServerArgs
-		if perNodeServerArgs, ok := args.ServerArgsPerNode[i]; ok {
-			serverArgs = perNodeServerArgs
-		} else {
-			serverArgs = args.ServerArgs
-		}
+		serverArgs := serverArgsForNode(args, i)
 
 		// If no localities are specified in the args, we'll generate some
 		// automatically.
@@ -188,7 +200,7 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 			serverArgs.Locality = roachpb.Locality{Tiers: tiers}
 		}
 
-		if i == 0 {
+		if i < len(listeners) {
 			if serverArgs.Knobs.Server == nil {
 				serverArgs.Knobs.Server = &server.TestingKnobs{}
 			} else {
@@ -197,9 +209,14 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 				knobs := *serverArgs.Knobs.Server.(*server.TestingKnobs)
 				serverArgs.Knobs.Server = &knobs
 			}
-			serverArgs.Knobs.Server.(*server.TestingKnobs).RPCListener = firstListener
-			serverArgs.Addr = firstListener.Addr().String()
-		} else {
+			knobs := serverArgs.Knobs.Server.(*server.TestingKnobs)
+			knobs.RPCListener = listeners[i]
+			serverArgs.Addr = listeners[i].Addr().String()
+			if args.RegionLatencies != nil {
+				knobs.ContextTestingKnobs.ArtificialLatencyMap = latencyMap(args, listeners, i)
+			}
+		}
+		if i > 0 {
 			//serverArgs.JoinAddr = tc.Servers[0].ServingRPCAddr()
 			serverArgs.JoinAddr = firstListener.Addr().String()
 		}
@@ -273,6 +290,34 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 	return tc
 }
 
+// serverArgsForNode returns the TestServerArgs specified for the node with the
+// given index, before they are adjusted for the cluster.
+func serverArgsForNode(args base.TestClusterArgs, idx int) base.TestServerArgs {
+	if perNodeServerArgs, ok := args.ServerArgsPerNode[idx]; ok {
+		return perNodeServerArgs
+	}
+	return args.ServerArgs
+}
+
+// latencyMap returns the artificial latency map (see
+// rpc.ContextTestingKnobs.ArtificialLatencyMap) of the node with the given
+// index, which contains the latency between the node's region and the regions
+// of the other nodes, keyed by their addresses.
+func latencyMap(args base.TestClusterArgs, listeners []net.Listener, idx int) map[string]int {
+	m := make(map[string]int)
+	src, _ := serverArgsForNode(args, idx).Locality.Find("region")
+	for i, ln := range listeners {
+		if i == idx {
+			continue
+		}
+		dst, _ := serverArgsForNode(args, i).Locality.Find("region")
+		if latency := args.RegionLatencies.Latency(src, dst); latency > 0 {
+			m[ln.Addr().String()] = int(latency / time.Millisecond)
+		}
+	}
+	return m
+}
+
 type checkType bool
 
 const (
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
 )
 
 func TestManualReplication(t *testing.T) {
@@ -257,3 +259,54 @@ func TestStopServer(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestClusterRegionLatencies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const latency = 20 * time.Millisecond
+	locality := func(region string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: region}}}
+	}
+	tc := StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgsPerNode: map[int]base.TestServerArgs{
+			0: {Locality: locality("us-east1")},
+			1: {Locality: locality("us-east1")},
+			2: {Locality: locality("europe-west1")},
+		},
+		RegionLatencies: base.RegionLatencies{
+			{RegionA: "us-east1", RegionB: "europe-west1"}: latency,
+		},
+	})
+	defer tc.Stopper().Stop(context.TODO())
+
+	// Only the connections between nodes in different regions have latency
+	// injected, in both directions.
+	for src, dsts := range map[int]map[int]int{
+		0: {2: 20},
+		1: {2: 20},
+		2: {0: 20, 1: 20},
+	} {
+		knobs := tc.Servers[src].Cfg.TestingKnobs.Server.(*server.TestingKnobs)
+		latencyMap := knobs.ContextTestingKnobs.ArtificialLatencyMap
+		if len(latencyMap) != len(dsts) {
+			t.Fatalf("%d: unexpected latency map %v", src, latencyMap)
+		}
+		for dst, ms := range dsts {
+			if l := latencyMap[tc.Servers[dst].ServingRPCAddr()]; l != ms {
+				t.Fatalf("%d: expected latency %dms to node %d, found %dms", src, ms, dst, l)
+			}
+		}
+	}
+
+	// The injected latency is visible in the round trips measured by the
+	// heartbeats between the nodes.
+	testutils.SucceedsSoon(t, func() error {
+		addr := tc.Servers[2].ServingRPCAddr()
+		measured, ok := tc.Servers[0].RPCContext().RemoteClocks.Latency(addr)
+		if !ok || measured < latency {
+			return errors.Errorf("measured latency %s to node 3, expected at least %s", measured, latency)
+		}
+		return nil
+	})
+}
@@ -392,6 +392,13 @@ var charts = []sectionDescription{
 					"range.snapshots.learner-applied",
 				},
 			},
+			{
+				Title: "Snapshot Application Latency",
+				Metrics: []string{
+					"range.snapshots.apply-latency",
+					"range.snapshots.ingest-latency",
+				},
+			},
 		},
 	},
 	{
@@ -493,6 +500,33 @@ var charts = []sectionDescription{
 				Title:   "Count",
 				Metrics: []string{"follower_reads.success_count"},
 			},
+			{
+				Title:   "Latch-Free Reads",
+				Metrics: []string{"follower_reads.latch_free_count"},
+			},
+			{
+				Title:   "Redirects",
+				Metrics: []string{"follower_reads.redirect_count"},
+			},
+			{
+				Title: "Redirect Reasons",
+				Metrics: []string{
+					"follower_reads.redirect.closed_timestamp_count",
+					"follower_reads.redirect.lease_type_count",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{KVTransactionLayer, "System Read Cache"}},
+		Charts: []chartDescription{
+			{
+				Title: "Hits and Misses",
+				Metrics: []string{
+					"system_read_cache.hits",
+					"system_read_cache.misses",
+				},
+			},
 		},
 	},
 	{
@@ -923,6 +957,10 @@ var charts = []sectionDescription{
 				Title:   "Time Spent",
 				Metrics: []string{"queue.consistency.processingnanos"},
 			},
+			{
+				Title:   "Stats Repairs",
+				Metrics: []string{"queue.consistency.statsrepairs"},
+			},
 		},
 	},
 	{
@@ -1006,6 +1044,29 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{ReplicationLayer, "Raft", "Command Compression"}},
+		Charts: []chartDescription{
+			{
+				Title:   "Compressed Commands",
+				Metrics: []string{"raft.commands.compressed"},
+			},
+			{
+				Title: "Bytes",
+				Metrics: []string{
+					"raft.commands.compression.inputbytes",
+					"raft.commands.compression.outputbytes",
+				},
+			},
+			{
+				Title: "Processing Time",
+				Metrics: []string{
+					"raft.commands.compression.nanos",
+					"raft.commands.decompression.nanos",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{ReplicationLayer, "Raft", "Entry Cache"}},
 		Charts: []chartDescription{
@@ -1069,6 +1130,26 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{ReplicationLayer, "Raft", "Scheduler"}},
+		Charts: []chartDescription{
+			{
+				Title: "Queue Length",
+				Metrics: []string{
+					"raft.scheduler.queue_length",
+					"raft.scheduler.priority.queue_length",
+				},
+			},
+			{
+				Title:   "Latency",
+				Metrics: []string{"raft.scheduler.latency"},
+			},
+			{
+				Title:   "Priority Latency",
+				Metrics: []string{"raft.scheduler.priority.latency"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{ReplicationLayer, "Raft", "Log"}},
 		Charts: []chartDescription{
@@ -1327,6 +1408,14 @@ var charts = []sectionDescription{
 				Title:   "Queued",
 				Metrics: []string{"sql.distsql.flows.queued"},
 			},
+			{
+				Title:   "Queue Timeouts",
+				Metrics: []string{"sql.distsql.flows.queue_timeout"},
+			},
+			{
+				Title:   "Replanned",
+				Metrics: []string{"sql.distsql.flows.replanned"},
+			},
 			{
 				Title:   "Total",
 				Metrics: []string{"sql.distsql.flows.total"},
@@ -1344,6 +1433,14 @@ var charts = []sectionDescription{
 				Title:   "Current Memory Usage",
 				Metrics: []string{"sql.mem.distsql.current"},
 			},
+			{
+				Title: "Disk Spilling",
+				Metrics: []string{
+					"sql.disk.distsql.spilled.bytes.read",
+					"sql.disk.distsql.spilled.bytes.written",
+				},
+				AxisLabel: "Disk",
+			},
 			{
 				Title: "DML Mix",
 				Metrics: []string{
@@ -1707,6 +1804,34 @@ var charts = []sectionDescription{
 					"sql.txn.rollback.started.count.internal",
 				},
 			},
+			{
+				Title: "Deadlines",
+				Metrics: []string{
+					"sql.txn.deadline.extended.count",
+					"sql.txn.deadline.extended.count.internal",
+					"sql.txn.deadline.exceeded.count",
+					"sql.txn.deadline.exceeded.count.internal",
+				},
+				AxisLabel: "SQL Transactions",
+			},
+			{
+				Title: "Prepared Statements",
+				Metrics: []string{
+					"sql.prepared_statements.count",
+					"sql.prepared_statements.count.internal",
+					"sql.prepared_statements.evicted.count",
+					"sql.prepared_statements.evicted.count.internal",
+				},
+				AxisLabel: "SQL Statements",
+			},
+			{
+				Title: "Prepared Statement Memory",
+				Metrics: []string{
+					"sql.prepared_statements.bytes",
+					"sql.prepared_statements.bytes.internal",
+				},
+				AxisLabel: "Memory",
+			},
 			{
 				Title: "Savepoints",
 				Metrics: []string{
@@ -1845,6 +1970,26 @@ var charts = []sectionDescription{
 					"compactor.suggestionbytes.queued",
 				},
 			},
+			{
+				Title: "Paced Range Clears",
+				Metrics: []string{
+					"clearrange.bytes.queued",
+					"clearrange.bytes.cleared",
+				},
+			},
+			{
+				Title: "Paced Bulk Reads",
+				Metrics: []string{
+					"bulkread.bytes",
+					"bulkread.rate",
+				},
+			},
+			{
+				Title: "Bulk Read Scheduling Latency",
+				Metrics: []string{
+					"bulkread.scheduling_latency",
+				},
+			},
 			{
 				Title: "Success",
 				Metrics: []string{
@@ -77,6 +77,8 @@ export function getEventDescription(e: Event$Properties): string {
       return `Zone Config Removed: User ${info.User} removed the zone config for ${info.Target}`;
     case eventTypes.CREATE_STATISTICS:
       return `Table statistics refreshed for ${info.TableName}`;
+    case eventTypes.PLAN_REGRESSION:
+      return `Plan Regression: The plan of statement ${info.Fingerprint} changed from ${info.OldPlan} to ${info.NewPlan}, and its mean latency went from ${info.OldLatency}s to ${info.NewLatency}s`;
     default:
       return `Unknown Event Type: ${e.event_type}, content: ${JSON.stringify(info, null, 2)}`;
   }
@@ -97,6 +99,11 @@ export interface EventInfo {
   Target?: string;
   Config?: string;
   Statement?: string;
+  Fingerprint?: string;
+  OldPlan?: string;
+  NewPlan?: string;
+  OldLatency?: number;
+  NewLatency?: number;
   // The following are three names for the same key (it was renamed twice).
   // All ar included for backwards compatibility.
   DroppedTables?: string[];
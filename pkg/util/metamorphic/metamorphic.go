@@ -0,0 +1,87 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package metamorphic allows the internal constants which don't affect the
+// results of the computations (batch sizes, buffer sizes, thresholds at which
+// the algorithms fall back to different strategies, etc) to be randomized in
+// test processes. The tests of such processes then continuously exercise the
+// code with configurations other than the production one.
+//
+// A package registers its constants from an init function, and a test package
+// opts into the randomization by calling Randomize from its TestMain.
+package metamorphic
+
+import (
+	"log" // Don't bring cockroach/util/log into this low-level package.
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+// disabled prevents Randomize from changing the constants, which is useful to
+// check whether a failure depends on the randomized configuration.
+var disabled = envutil.EnvOrDefaultBool("COCKROACH_DISABLE_METAMORPHIC_TESTING", false)
+
+type constant struct {
+	name      string
+	randomize func(rng *rand.Rand) interface{}
+}
+
+// constants are the registered constants. They are only modified by the init
+// functions, so no synchronization is needed.
+var constants []constant
+
+// Register registers a metamorphic constant with the given name. randomize is
+// called by Randomize and must set the constant to a random value generated by
+// rng, which it returns so that the value can be logged. Register must only be
+// called from init functions.
+func Register(name string, randomize func(rng *rand.Rand) interface{}) {
+	for _, c := range constants {
+		if c.name == name {
+			panic("metamorphic constant " + name + " is already registered")
+		}
+	}
+	constants = append(constants, constant{name: name, randomize: randomize})
+}
+
+// Randomize sets all of the registered constants to random values and logs
+// them. It must be called from TestMain before any of the tests are run. The
+// random number generator is seeded from the COCKROACH_RANDOM_SEED environment
+// variable, so a configuration can be reproduced by setting it to the logged
+// seed.
+func Randomize() {
+	if disabled {
+		log.Printf("Metamorphic testing disabled")
+		return
+	}
+	rng, seed := randutil.NewPseudoRand()
+	log.Printf("Metamorphic seed: %d", seed)
+	sorted := append([]constant(nil), constants...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, c := range sorted {
+		log.Printf("Metamorphic constant %s: %v", c.name, c.randomize(rng))
+	}
+}
+
+// IntInRange returns a random value in [min, max] which is skewed towards the
+// bounds, since the configurations at the edges of the allowed range are the
+// most likely to expose bugs.
+func IntInRange(rng *rand.Rand, min, max int) int {
+	switch rng.Intn(4) {
+	case 0:
+		return min
+	case 1:
+		return max
+	default:
+		return min + rng.Intn(max-min+1)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metamorphic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestIntInRange(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		v := IntInRange(rng, 3, 7)
+		if v < 3 || v > 7 {
+			t.Fatalf("%d is out of [3, 7]", v)
+		}
+		seen[v] = true
+	}
+	if !seen[3] || !seen[7] {
+		t.Fatalf("expected the bounds to be generated, got %v", seen)
+	}
+	if v := IntInRange(rng, 5, 5); v != 5 {
+		t.Fatalf("expected 5, got %d", v)
+	}
+}
+
+func TestRandomize(t *testing.T) {
+	defer func(old []constant) { constants = old }(constants)
+	constants = nil
+
+	a, b := 1, 2
+	Register("a", func(rng *rand.Rand) interface{} {
+		a = IntInRange(rng, 10, 20)
+		return a
+	})
+	Register("b", func(rng *rand.Rand) interface{} {
+		b = IntInRange(rng, 30, 40)
+		return b
+	})
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected a panic registering a duplicate constant")
+			}
+		}()
+		Register("a", func(*rand.Rand) interface{} { return nil })
+	}()
+
+	Randomize()
+	if disabled {
+		if a != 1 || b != 2 {
+			t.Fatalf("expected the constants to be unchanged, got %d and %d", a, b)
+		}
+		return
+	}
+	if a < 10 || a > 20 || b < 30 || b > 40 {
+		t.Fatalf("the constants are out of range: %d and %d", a, b)
+	}
+}
@@ -11,6 +11,7 @@
 package metric
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -18,6 +19,8 @@ import (
 	"time"
 
 	"github.com/VividCortex/ewma"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/codahale/hdrhistogram"
@@ -39,6 +42,45 @@ const (
 	histWrapNum = 2
 )
 
+// highResLatencySigFigs is the number of significant decimal digits tracked
+// by high-resolution latency histograms (see NewHighResLatency). Each
+// additional digit multiplies the memory used by every such histogram by ten,
+// so the value is clamped to the range [1, 3].
+var highResLatencySigFigs = func() int {
+	const envVar = "COCKROACH_HIGH_RES_LATENCY_SIG_FIGS"
+	sigFigs := envutil.EnvOrDefaultInt(envVar, 2)
+	clamped := sigFigs
+	if clamped < 1 {
+		clamped = 1
+	}
+	if clamped > 3 {
+		clamped = 3
+	}
+	if clamped != sigFigs {
+		log.Warningf(context.Background(), "%s=%d is out of range [1, 3], using %d instead",
+			envVar, sigFigs, clamped)
+	}
+	return clamped
+}()
+
+// PrometheusLatencyBuckets are the upper bounds (in nanoseconds) of the
+// buckets used when exporting high-resolution latency histograms to
+// Prometheus. The raw HDR distribution has far too many (and too variable)
+// buckets to be useful to Prometheus, which requires bucket boundaries to be
+// stable across scrapes in order to aggregate and compute quantiles, so the
+// samples are folded into a fixed 1-2-5 series between 1µs and MaxLatency.
+var PrometheusLatencyBuckets = func() []int64 {
+	var buckets []int64
+	for decade := time.Microsecond.Nanoseconds(); decade < MaxLatency.Nanoseconds(); decade *= 10 {
+		for _, m := range []int64{1, 2, 5} {
+			if b := m * decade; b < MaxLatency.Nanoseconds() {
+				buckets = append(buckets, b)
+			}
+		}
+	}
+	return append(buckets, MaxLatency.Nanoseconds())
+}()
+
 // Iterable provides a method for synchronized access to interior objects.
 type Iterable interface {
 	// GetName returns the fully-qualified name of the metric.
@@ -174,7 +216,11 @@ func maybeTick(m periodic) {
 type Histogram struct {
 	Metadata
 	maxVal int64
-	mu     struct {
+	// exportBuckets, if set, are the upper bounds of the buckets into which the
+	// samples are folded when exporting to Prometheus. Otherwise, every
+	// non-empty bucket of the underlying HDR histogram is exported.
+	exportBuckets []int64
+	mu            struct {
 		syncutil.Mutex
 		cumulative *hdrhistogram.Histogram
 		sliding    *slidingHistogram
@@ -209,6 +255,30 @@ func NewLatency(metadata Metadata, histogramWindow time.Duration) *Histogram {
 	)
 }
 
+// NewHighResLatency returns a histogram suitable for latency tracking of
+// critical paths (SQL statements, KV requests, Raft commits) where the tail of
+// the distribution matters. Unlike NewLatency, the samples are recorded with
+// two significant digits by default (configurable through the
+// COCKROACH_HIGH_RES_LATENCY_SIG_FIGS environment variable), which keeps the
+// p99.9 and p99.99 quantiles meaningful. When exported to Prometheus, the
+// samples are folded into PrometheusLatencyBuckets.
+func NewHighResLatency(metadata Metadata, histogramWindow time.Duration) *Histogram {
+	h := NewHistogram(
+		metadata, histogramWindow, MaxLatency.Nanoseconds(), highResLatencySigFigs,
+	)
+	h.exportBuckets = PrometheusLatencyBuckets
+	return h
+}
+
+// ValueAtQuantileWindowed returns the value at the given quantile (expressed
+// as a percentage, i.e. 99.9 for p99.9) of the windowed histogram.
+func (h *Histogram) ValueAtQuantileWindowed(q float64) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	maybeTick(h.mu.sliding)
+	return h.mu.sliding.Current().ValueAtQuantile(q)
+}
+
 // Windowed returns a copy of the current windowed histogram data and its
 // rotation interval.
 func (h *Histogram) Windowed() (*hdrhistogram.Histogram, time.Duration) {
@@ -274,6 +344,13 @@ func (h *Histogram) ToPrometheusMetric() *prometheusgo.Metric {
 	h.mu.Lock()
 	maybeTick(h.mu.sliding)
 	bars := h.mu.cumulative.Distribution()
+	h.mu.Unlock()
+
+	if h.exportBuckets != nil {
+		return &prometheusgo.Metric{
+			Histogram: foldDistribution(bars, h.exportBuckets),
+		}
+	}
 	hist.Bucket = make([]*prometheusgo.Bucket, 0, len(bars))
 
 	var cumCount uint64
@@ -296,13 +373,56 @@ func (h *Histogram) ToPrometheusMetric() *prometheusgo.Metric {
 	}
 	hist.SampleCount = &cumCount
 	hist.SampleSum = &sum // can do better here; we approximate in the loop
-	h.mu.Unlock()
 
 	return &prometheusgo.Metric{
 		Histogram: hist,
 	}
 }
 
+// foldDistribution converts the given HDR histogram distribution into a
+// Prometheus histogram with the given (sorted) bucket upper bounds. All of the
+// buckets are always exported, even if empty, so that the bucket boundaries
+// remain stable across scrapes. Each HDR bar is attributed to the first bucket
+// containing its lower end, which is exact for samples recorded at a bucket
+// boundary (e.g. values clamped to MaxLatency). Samples above the last bound
+// are only reflected in the sample count (i.e. in the implicit +Inf bucket).
+func foldDistribution(bars []hdrhistogram.Bar, bounds []int64) *prometheusgo.Histogram {
+	counts := make([]uint64, len(bounds))
+	var totalCount uint64
+	var sum float64
+	b := 0
+	for _, bar := range bars {
+		if bar.Count == 0 {
+			continue
+		}
+		for b < len(bounds) && bounds[b] < bar.From {
+			b++
+		}
+		if b < len(bounds) {
+			counts[b] += uint64(bar.Count)
+		}
+		totalCount += uint64(bar.Count)
+		sum += float64(bar.To) * float64(bar.Count)
+	}
+
+	hist := &prometheusgo.Histogram{
+		Bucket: make([]*prometheusgo.Bucket, len(bounds)),
+	}
+	var cumCount uint64
+	for i := range bounds {
+		cumCount += counts[i]
+		curCumCount := cumCount // need a new alloc thanks to bad proto code
+		upperBound := float64(bounds[i])
+		hist.Bucket[i] = &prometheusgo.Bucket{
+			CumulativeCount: &curCumCount,
+			UpperBound:      &upperBound,
+		}
+	}
+	hist.SampleCount = &totalCount
+	hist.SampleSum = &sum // approximated using the upper bound of each HDR bar
+	return hist
+}
+
 // GetMetadata returns the metric's metadata including the Prometheus
 // MetricType.
 func (h *Histogram) GetMetadata() Metadata {
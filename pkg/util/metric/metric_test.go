@@ -132,6 +132,44 @@ func TestHistogramPrometheus(t *testing.T) {
 	}
 }
 
+func TestHighResLatencyPrometheus(t *testing.T) {
+	h := NewHighResLatency(Metadata{}, time.Hour)
+	h.RecordValue(time.Microsecond.Nanoseconds())
+	h.RecordValue(3 * time.Millisecond.Nanoseconds())
+	h.RecordValue(3 * time.Millisecond.Nanoseconds())
+	h.RecordValue(40 * time.Millisecond.Nanoseconds())
+	act := *h.ToPrometheusMetric().Histogram
+
+	if len(act.Bucket) != len(PrometheusLatencyBuckets) {
+		t.Fatalf("expected %d buckets, got %d", len(PrometheusLatencyBuckets), len(act.Bucket))
+	}
+	if c := act.GetSampleCount(); c != 4 {
+		t.Fatalf("expected sample count 4, got %d", c)
+	}
+	expCounts := map[time.Duration]uint64{
+		time.Microsecond:      1,
+		2 * time.Millisecond:  1,
+		5 * time.Millisecond:  3,
+		20 * time.Millisecond: 3,
+		50 * time.Millisecond: 4,
+		MaxLatency:            4,
+	}
+	for _, b := range act.Bucket {
+		if exp, ok := expCounts[time.Duration(b.GetUpperBound())]; ok {
+			if b.GetCumulativeCount() != exp {
+				t.Errorf("bucket %s: expected cumulative count %d, got %d",
+					time.Duration(b.GetUpperBound()), exp, b.GetCumulativeCount())
+			}
+		}
+	}
+
+	// The tail should be tracked with two significant digits.
+	p9999 := time.Duration(h.ValueAtQuantileWindowed(99.99))
+	if p9999 < 39*time.Millisecond || p9999 > 41*time.Millisecond {
+		t.Fatalf("expected p99.99 of ~40ms, got %s", p9999)
+	}
+}
+
 func TestHistogramRotate(t *testing.T) {
 	defer TestingSetNow(nil)()
 	setNow(0)